@@ -23,11 +23,15 @@ import (
 )
 
 type Config struct {
-	ServerMetricsPort   int
-	LogLevel            log.Level
-	Client              continuoustest.ClientConfig
-	Manager             continuoustest.ManagerConfig
-	WriteReadSeriesTest continuoustest.WriteReadSeriesTestConfig
+	ServerMetricsPort           int
+	LogLevel                    log.Level
+	Client                      continuoustest.ClientConfig
+	Manager                     continuoustest.ManagerConfig
+	WriteReadSeriesTest         continuoustest.WriteReadSeriesTestConfig
+	IngestQueryFreshnessTest    continuoustest.IngestQueryFreshnessTestConfig
+	WriteReadLabelsMetadataTest continuoustest.WriteReadLabelsMetadataTestConfig
+	WriteReadExemplarsTest      continuoustest.WriteReadExemplarsTestConfig
+	RulerAlertmanagerTest       continuoustest.RulerAlertmanagerTestConfig
 }
 
 func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
@@ -36,6 +40,10 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	cfg.Client.RegisterFlags(f)
 	cfg.Manager.RegisterFlags(f)
 	cfg.WriteReadSeriesTest.RegisterFlags(f)
+	cfg.IngestQueryFreshnessTest.RegisterFlags(f)
+	cfg.WriteReadLabelsMetadataTest.RegisterFlags(f)
+	cfg.WriteReadExemplarsTest.RegisterFlags(f)
+	cfg.RulerAlertmanagerTest.RegisterFlags(f)
 }
 
 func main() {
@@ -79,9 +87,28 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Exemplars are not translated to OTLP by the client.
+	if cfg.WriteReadExemplarsTest.Enabled && cfg.Client.WriteProtocol != "prometheus" {
+		level.Error(logger).Log("msg", "The exemplars test requires the prometheus write protocol")
+		util_log.Flush()
+		os.Exit(1)
+	}
+
 	// Run continuous testing.
 	m := continuoustest.NewManager(cfg.Manager, logger)
 	m.AddTest(continuoustest.NewWriteReadSeriesTest(cfg.WriteReadSeriesTest, client, logger, registry))
+	if cfg.IngestQueryFreshnessTest.Enabled {
+		m.AddTest(continuoustest.NewIngestQueryFreshnessTest(cfg.IngestQueryFreshnessTest, client, logger, registry))
+	}
+	if cfg.WriteReadLabelsMetadataTest.Enabled {
+		m.AddTest(continuoustest.NewWriteReadLabelsMetadataTest(cfg.WriteReadLabelsMetadataTest, client, logger, registry))
+	}
+	if cfg.WriteReadExemplarsTest.Enabled {
+		m.AddTest(continuoustest.NewWriteReadExemplarsTest(cfg.WriteReadExemplarsTest, client, logger, registry))
+	}
+	if cfg.RulerAlertmanagerTest.Enabled {
+		m.AddTest(continuoustest.NewRulerAlertmanagerTest(cfg.RulerAlertmanagerTest, client, logger, registry))
+	}
 	if err := m.Run(context.Background()); err != nil {
 		level.Error(logger).Log("msg", "Failed to run continuous test", "err", err.Error())
 		util_log.Flush()
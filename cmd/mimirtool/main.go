@@ -24,6 +24,7 @@ var (
 	analyzeCommand        commands.AnalyzeCommand
 	bucketValidateCommand commands.BucketValidationCommand
 	configCommand         commands.ConfigCommand
+	deadLetterCommand     commands.DeadLetterCommand
 	loadgenCommand        commands.LoadgenCommand
 	logConfig             commands.LoggerConfig
 	pushGateway           commands.PushGatewayConfig
@@ -43,6 +44,7 @@ func main() {
 	backfillCommand.Register(app, envVars)
 	bucketValidateCommand.Register(app, envVars)
 	configCommand.Register(app, envVars)
+	deadLetterCommand.Register(app, envVars)
 	loadgenCommand.Register(app, envVars, prometheus.DefaultRegisterer)
 	logConfig.Register(app, envVars)
 	pushGateway.Register(app, envVars)
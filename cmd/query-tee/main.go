@@ -63,6 +63,18 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Replay the captured requests instead of running the proxy, if requested.
+	if cfg.ProxyConfig.ReplayRequestLogFile != "" {
+		if err := replayRequestLog(proxy, cfg.ProxyConfig.ReplayRequestLogFile); err != nil {
+			level.Error(util_log.Logger).Log("msg", "Unable to replay the request log", "err", err.Error())
+			util_log.Flush()
+			os.Exit(1)
+		}
+
+		util_log.Flush()
+		return
+	}
+
 	if err := proxy.Start(); err != nil {
 		level.Error(util_log.Logger).Log("msg", "Unable to start the proxy", "err", err.Error())
 		util_log.Flush()
@@ -72,6 +84,29 @@ func main() {
 	proxy.Await()
 }
 
+func replayRequestLog(proxy *querytee.Proxy, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	summary, err := proxy.Replay(file)
+	if err != nil {
+		return err
+	}
+
+	level.Info(util_log.Logger).Log(
+		"msg", "Replayed the request log",
+		"requests", summary.Requests,
+		"unmatched", summary.Unmatched,
+		"success", summary.Results[querytee.ComparisonSuccess],
+		"fail", summary.Results[querytee.ComparisonFailed],
+		"skip", summary.Results[querytee.ComparisonSkipped],
+	)
+	return nil
+}
+
 func mimirReadRoutes(cfg Config) []querytee.Route {
 	prefix := cfg.PathPrefix
 
@@ -85,13 +120,20 @@ func mimirReadRoutes(cfg Config) []querytee.Route {
 		UseRelativeError:  cfg.ProxyConfig.UseRelativeError,
 		SkipRecentSamples: cfg.ProxyConfig.SkipRecentSamples,
 	})
+	labelsComparator := querytee.NewLabelsComparator()
+	seriesComparator := querytee.NewSeriesComparator()
+	cardinalityComparator := querytee.NewCardinalityComparator()
+
 	return []querytee.Route{
 		{Path: prefix + "/api/v1/query", RouteName: "api_v1_query", Methods: []string{"GET", "POST"}, ResponseComparator: samplesComparator},
 		{Path: prefix + "/api/v1/query_range", RouteName: "api_v1_query_range", Methods: []string{"GET", "POST"}, ResponseComparator: samplesComparator},
 		{Path: prefix + "/api/v1/query_exemplars", RouteName: "api_v1_query_exemplars", Methods: []string{"GET", "POST"}, ResponseComparator: nil},
-		{Path: prefix + "/api/v1/labels", RouteName: "api_v1_labels", Methods: []string{"GET", "POST"}, ResponseComparator: nil},
-		{Path: prefix + "/api/v1/label/{name}/values", RouteName: "api_v1_label_name_values", Methods: []string{"GET", "POST"}, ResponseComparator: nil},
-		{Path: prefix + "/api/v1/series", RouteName: "api_v1_series", Methods: []string{"GET", "POST"}, ResponseComparator: nil},
+		{Path: prefix + "/api/v1/labels", RouteName: "api_v1_labels", Methods: []string{"GET", "POST"}, ResponseComparator: labelsComparator},
+		{Path: prefix + "/api/v1/label/{name}/values", RouteName: "api_v1_label_name_values", Methods: []string{"GET", "POST"}, ResponseComparator: labelsComparator},
+		{Path: prefix + "/api/v1/series", RouteName: "api_v1_series", Methods: []string{"GET", "POST"}, ResponseComparator: seriesComparator},
+		{Path: prefix + "/api/v1/cardinality/label_names", RouteName: "api_v1_cardinality_label_names", Methods: []string{"GET", "POST"}, ResponseComparator: cardinalityComparator},
+		{Path: prefix + "/api/v1/cardinality/label_values", RouteName: "api_v1_cardinality_label_values", Methods: []string{"GET", "POST"}, ResponseComparator: cardinalityComparator},
+		{Path: prefix + "/api/v1/cardinality/active_series", RouteName: "api_v1_cardinality_active_series", Methods: []string{"GET", "POST"}, ResponseComparator: cardinalityComparator},
 		{Path: prefix + "/api/v1/metadata", RouteName: "api_v1_metadata", Methods: []string{"GET", "POST"}, ResponseComparator: nil},
 		{Path: prefix + "/prometheus/config/v1/rules", RouteName: "prometheus_config_v1_rules", Methods: []string{"GET", "POST"}, ResponseComparator: nil},
 		{Path: prefix + "/api/v1/alerts", RouteName: "api_v1_alerts", Methods: []string{"GET", "POST"}, ResponseComparator: nil},
@@ -14,6 +14,8 @@ import (
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/promql/parser"
 	"golang.org/x/exp/slices"
+
+	"github.com/grafana/mimir/pkg/util"
 )
 
 type CountMethod string
@@ -21,6 +23,7 @@ type CountMethod string
 const (
 	InMemoryMethod CountMethod = "inmemory"
 	ActiveMethod   CountMethod = "active"
+	BlocksMethod   CountMethod = "blocks"
 )
 
 const (
@@ -37,6 +40,10 @@ type LabelNamesRequest struct {
 	Matchers    []*labels.Matcher
 	CountMethod CountMethod
 	Limit       int
+
+	// Start and End (milliseconds since epoch) are only set when CountMethod is BlocksMethod.
+	Start int64
+	End   int64
 }
 
 // Strings returns a full representation of the request. The returned string can be
@@ -60,6 +67,11 @@ func (r *LabelNamesRequest) String() string {
 	b.WriteRune(stringParamSeparator)
 	b.WriteString(strconv.Itoa(r.Limit))
 
+	// Add time range (only used by the blocks count method).
+	if r.CountMethod == BlocksMethod {
+		writeTimeRange(&b, r.Start, r.End)
+	}
+
 	return b.String()
 }
 
@@ -95,6 +107,13 @@ func DecodeLabelNamesRequestFromValues(values url.Values) (*LabelNamesRequest, e
 		return nil, err
 	}
 
+	if parsed.CountMethod == BlocksMethod {
+		parsed.Start, parsed.End, err = extractTimeRange(values)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return parsed, nil
 }
 
@@ -103,6 +122,10 @@ type LabelValuesRequest struct {
 	Matchers    []*labels.Matcher
 	CountMethod CountMethod
 	Limit       int
+
+	// Start and End (milliseconds since epoch) are only set when CountMethod is BlocksMethod.
+	Start int64
+	End   int64
 }
 
 // Strings returns a full representation of the request. The returned string can be
@@ -135,6 +158,11 @@ func (r *LabelValuesRequest) String() string {
 	b.WriteRune(stringParamSeparator)
 	b.WriteString(strconv.Itoa(r.Limit))
 
+	// Add time range (only used by the blocks count method).
+	if r.CountMethod == BlocksMethod {
+		writeTimeRange(&b, r.Start, r.End)
+	}
+
 	return b.String()
 }
 
@@ -175,6 +203,13 @@ func DecodeLabelValuesRequestFromValues(values url.Values) (*LabelValuesRequest,
 		return nil, err
 	}
 
+	if parsed.CountMethod == BlocksMethod {
+		parsed.Start, parsed.End, err = extractTimeRange(values)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return parsed, nil
 }
 
@@ -270,9 +305,49 @@ func extractCountMethod(values url.Values) (countMethod CountMethod, err error)
 		return ActiveMethod, nil
 	case InMemoryMethod:
 		return InMemoryMethod, nil
+	case BlocksMethod:
+		return BlocksMethod, nil
 	default:
-		return "", fmt.Errorf("invalid 'count_method' param '%v'. valid options are: [%s]", countMethodParams[0], strings.Join([]string{string(ActiveMethod), string(InMemoryMethod)}, ","))
+		return "", fmt.Errorf("invalid 'count_method' param '%v'. valid options are: [%s]", countMethodParams[0], strings.Join([]string{string(ActiveMethod), string(InMemoryMethod), string(BlocksMethod)}, ","))
+	}
+}
+
+// extractTimeRange parses and validates the required request params `start` and `end`.
+func extractTimeRange(values url.Values) (start, end int64, err error) {
+	start, err = extractTime(values, "start")
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err = extractTime(values, "end")
+	if err != nil {
+		return 0, 0, err
+	}
+	if end < start {
+		return 0, 0, fmt.Errorf("'end' param must be equal or greater than 'start' param")
+	}
+	return start, end, nil
+}
+
+func extractTime(values url.Values, paramName string) (int64, error) {
+	params := values[paramName]
+	if len(params) == 0 {
+		return 0, fmt.Errorf("'%s' param is required when 'count_method' is '%s'", paramName, BlocksMethod)
 	}
+	if len(params) > 1 {
+		return 0, fmt.Errorf("multiple '%s' params are not allowed", paramName)
+	}
+	t, err := util.ParseTime(params[0])
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid '%s' param", paramName)
+	}
+	return t, nil
+}
+
+func writeTimeRange(b *strings.Builder, start, end int64) {
+	b.WriteRune(stringParamSeparator)
+	b.WriteString(strconv.FormatInt(start, 10))
+	b.WriteRune(stringParamSeparator)
+	b.WriteString(strconv.FormatInt(end, 10))
 }
 
 type ActiveSeriesRequest struct {
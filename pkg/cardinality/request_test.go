@@ -74,6 +74,52 @@ func TestLabelNamesRequest_String(t *testing.T) {
 	assert.Equal(t, "first=\"1\"\x01second!=\"2\"\x00active\x00100", req.String())
 }
 
+func TestDecodeLabelNamesRequest_BlocksCountMethod(t *testing.T) {
+	t.Run("should parse the time range", func(t *testing.T) {
+		actual, err := DecodeLabelNamesRequestFromValues(url.Values{
+			"count_method": []string{"blocks"},
+			"start":        []string{"1"},
+			"end":          []string{"2023-01-01T00:00:00Z"},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, &LabelNamesRequest{
+			CountMethod: BlocksMethod,
+			Limit:       defaultLimit,
+			Start:       1000,
+			End:         1672531200000,
+		}, actual)
+	})
+
+	t.Run("should fail if the time range is missing", func(t *testing.T) {
+		_, err := DecodeLabelNamesRequestFromValues(url.Values{
+			"count_method": []string{"blocks"},
+			"end":          []string{"2"},
+		})
+		require.EqualError(t, err, "'start' param is required when 'count_method' is 'blocks'")
+	})
+
+	t.Run("should fail if end is before start", func(t *testing.T) {
+		_, err := DecodeLabelNamesRequestFromValues(url.Values{
+			"count_method": []string{"blocks"},
+			"start":        []string{"2"},
+			"end":          []string{"1"},
+		})
+		require.EqualError(t, err, "'end' param must be equal or greater than 'start' param")
+	})
+
+	t.Run("should ignore the time range for other count methods", func(t *testing.T) {
+		actual, err := DecodeLabelNamesRequestFromValues(url.Values{
+			"count_method": []string{"inmemory"},
+			"start":        []string{"1"},
+			"end":          []string{"2"},
+		})
+		require.NoError(t, err)
+		assert.Zero(t, actual.Start)
+		assert.Zero(t, actual.End)
+	})
+}
+
 func TestDecodeLabelValuesRequest(t *testing.T) {
 	var (
 		params = url.Values{
@@ -137,6 +183,18 @@ func TestLabelValuesRequest_String(t *testing.T) {
 	assert.Equal(t, "foo\x01bar\x00first=\"1\"\x01second!=\"2\"\x00active\x00100", req.String())
 }
 
+func TestLabelValuesRequest_String_BlocksCountMethod(t *testing.T) {
+	req := &LabelValuesRequest{
+		LabelNames:  []model.LabelName{"foo"},
+		CountMethod: BlocksMethod,
+		Limit:       100,
+		Start:       1000,
+		End:         2000,
+	}
+
+	assert.Equal(t, "foo\x00\x00blocks\x00100\x001000\x002000", req.String())
+}
+
 func TestActiveSeriesRequest_String(t *testing.T) {
 	req := &ActiveSeriesRequest{
 		Matchers: []*labels.Matcher{
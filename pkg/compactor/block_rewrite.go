@@ -0,0 +1,238 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package compactor
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/oklog/ulid"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/prometheus/prometheus/tsdb/tombstones"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
+)
+
+// blockChecksBudget limits the number of blocks checked by a tenant cleanup to find out whether they need to be
+// rewritten, since checking a block downloads its index. The blocks left unchecked are checked by the following
+// cleanups, so that a tenant with many blocks to rewrite doesn't delay the update of its bucket index.
+type blockChecksBudget struct {
+	limited   bool
+	remaining int
+}
+
+// newBlockChecksBudget returns a budget of maxBlocks checks. 0 means no limit.
+func newBlockChecksBudget(maxBlocks int) *blockChecksBudget {
+	return &blockChecksBudget{limited: maxBlocks > 0, remaining: maxBlocks}
+}
+
+// take returns whether a block can be checked, consuming one check from the budget.
+func (b *blockChecksBudget) take() bool {
+	if !b.limited {
+		return true
+	}
+	if b.remaining <= 0 {
+		return false
+	}
+	b.remaining--
+	return true
+}
+
+// containsAll returns whether the sorted list of what has been applied to a block, like the retention rules or the
+// series deletion requests, contains all the needed ones.
+func containsAll(applied, needed []string) bool {
+	for _, n := range needed {
+		if _, found := slices.BinarySearch(applied, n); !found {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeApplied returns the sorted union of what has been applied to a block and what has been added.
+func mergeApplied(applied, added []string) []string {
+	merged := append(slices.Clone(applied), added...)
+	slices.Sort(merged)
+	return slices.Compact(merged)
+}
+
+// blockRewrite describes a rewrite of a block by the cleaner.
+type blockRewrite struct {
+	// description of the rewrite, used in the logs and in the markers, e.g. "retention rules".
+	description string
+
+	// The block is excluded from the compaction with noCompactReason while it's rewritten.
+	noCompactReason    block.NoCompactReason
+	markedForNoCompact prometheus.Counter
+
+	// retentionRules and seriesDeletionRequests are the matchers of the retention rules and the IDs of the series
+	// deletion requests applied to the new block, recorded in its meta.
+	retentionRules         []string
+	seriesDeletionRequests []string
+
+	// filterExemplars returns the exemplars of the block kept in the new block.
+	filterExemplars func([]exemplar.QueryResult) []exemplar.QueryResult
+}
+
+// rewriteBlockWithTombstones downloads the block into dir, rewrites it into a new block without the samples deleted by
+// the stones, uploads the new block and marks the original block for deletion. The original block is excluded from
+// the compaction while it's rewritten.
+// Returns the number of bytes removed from the block.
+func (c *BlocksCleaner) rewriteBlockWithTombstones(ctx context.Context, blockLogger log.Logger, userBucket objstore.Bucket, dir string, id ulid.ULID, stones tombstones.Reader, rewrite blockRewrite) (_ int64, returnErr error) {
+	bdir := filepath.Join(dir, id.String())
+
+	// Exclude the block from the compaction while it's rewritten, otherwise it could be compacted with the new block
+	// before being marked for deletion, bringing back the deleted samples. A compaction job already running when the
+	// block is marked outputs a new block, which is checked again in the next cleanup.
+	if err := block.MarkForNoCompact(ctx, blockLogger, userBucket, id, rewrite.noCompactReason, "block being rewritten by "+rewrite.description, rewrite.markedForNoCompact); err != nil {
+		return 0, errors.Wrap(err, "mark block for no compaction")
+	}
+	uploaded := false
+	defer func() {
+		// The block is compacted as usual if it can't be rewritten. Otherwise, the marker is deleted with the block.
+		// The marker is kept if the new block has been uploaded, so that both blocks aren't compacted together.
+		if returnErr == nil || uploaded {
+			return
+		}
+		if err := block.DeleteNoCompactMarker(ctx, blockLogger, userBucket, id); err != nil {
+			level.Warn(blockLogger).Log("msg", "failed to delete the no-compaction marker", "err", err)
+		}
+	}()
+
+	if err := block.Download(ctx, blockLogger, userBucket, id, bdir); err != nil {
+		return 0, errors.Wrap(err, "download block")
+	}
+	meta, err := block.ReadMetaFromDir(bdir)
+	if err != nil {
+		return 0, errors.Wrap(err, "read meta")
+	}
+	sizeBefore, err := dirSize(bdir)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := tombstones.WriteFile(blockLogger, bdir, stones); err != nil {
+		return 0, errors.Wrap(err, "write tombstones")
+	}
+
+	newID, err := rewriteBlock(ctx, blockLogger, dir, bdir, meta, rewrite)
+	if err != nil {
+		return 0, err
+	}
+
+	sizeAfter := int64(0)
+	if newID != (ulid.ULID{}) {
+		newDir := filepath.Join(dir, newID.String())
+		if err := rewriteBlockExemplars(blockLogger, bdir, newDir, rewrite.filterExemplars); err != nil {
+			return 0, err
+		}
+		if err := copyBlockMetricMetadata(blockLogger, bdir, newDir); err != nil {
+			return 0, err
+		}
+		if sizeAfter, err = dirSize(newDir); err != nil {
+			return 0, err
+		}
+		if err := block.Upload(ctx, blockLogger, userBucket, newDir, nil); err != nil {
+			return 0, errors.Wrapf(err, "upload of %s failed", newID)
+		}
+		uploaded = true
+		level.Info(blockLogger).Log("msg", "uploaded block rewritten by the "+rewrite.description, "result_block", newID)
+	} else {
+		level.Info(blockLogger).Log("msg", "block rewritten by the "+rewrite.description+" would have no samples")
+	}
+
+	details := fmt.Sprintf("block rewritten by %s into block %s", rewrite.description, newID)
+	if newID == (ulid.ULID{}) {
+		details = "all samples of the block deleted by " + rewrite.description
+	}
+	if err := block.MarkForDeletion(ctx, blockLogger, userBucket, id, details, c.blocksMarkedForDeletion); err != nil {
+		return 0, errors.Wrap(err, "mark block for deletion")
+	}
+
+	return max(sizeBefore-sizeAfter, 0), nil
+}
+
+// rewriteBlock writes the block stored in bdir, without the samples deleted by its tombstones, into a new block in dir.
+// The new block keeps the compaction lineage, the external labels and the resolution of the original block, and
+// records the applied retention rules and series deletion requests.
+// Returns an empty ID if the new block would have no samples.
+func rewriteBlock(ctx context.Context, logger log.Logger, dir, bdir string, meta *block.Meta, rewrite blockRewrite) (ulid.ULID, error) {
+	comp, err := tsdb.NewLeveledCompactor(ctx, nil, logger, []int64{meta.MaxTime - meta.MinTime}, nil, nil)
+	if err != nil {
+		return ulid.ULID{}, errors.Wrap(err, "create compactor")
+	}
+
+	b, err := tsdb.OpenBlock(logger, bdir, nil)
+	if err != nil {
+		return ulid.ULID{}, errors.Wrap(err, "open block")
+	}
+	newID, err := comp.Write(dir, b, meta.MinTime, meta.MaxTime, &meta.BlockMeta)
+	if closeErr := b.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return ulid.ULID{}, errors.Wrap(err, "rewrite block")
+	}
+	if newID == (ulid.ULID{}) {
+		return newID, nil
+	}
+
+	newDir := filepath.Join(dir, newID.String())
+	newMeta, err := block.InjectThanosMeta(logger, newDir, block.ThanosMeta{
+		Labels:                 meta.Thanos.Labels,
+		Downsample:             meta.Thanos.Downsample,
+		Source:                 block.CompactorSource,
+		SegmentFiles:           block.GetSegmentFiles(newDir),
+		RetentionRules:         rewrite.retentionRules,
+		SeriesDeletionRequests: rewrite.seriesDeletionRequests,
+	}, &meta.BlockMeta)
+	if err != nil {
+		return ulid.ULID{}, errors.Wrapf(err, "failed to finalize the block %s", newDir)
+	}
+
+	if err = os.Remove(filepath.Join(newDir, "tombstones")); err != nil {
+		return ulid.ULID{}, errors.Wrap(err, "remove tombstones")
+	}
+
+	if err := block.VerifyBlock(ctx, logger, newDir, newMeta.MinTime, newMeta.MaxTime, false); err != nil {
+		return ulid.ULID{}, errors.Wrapf(err, "invalid result block %s", newDir)
+	}
+	return newID, nil
+}
+
+// rewriteBlockExemplars writes the exemplars of the block stored in bdir, filtered with filter, to the block stored
+// in newDir.
+func rewriteBlockExemplars(logger log.Logger, bdir, newDir string, filter func([]exemplar.QueryResult) []exemplar.QueryResult) error {
+	exemplars, err := block.ReadExemplarsFromDir(bdir)
+	if err != nil {
+		return errors.Wrap(err, "read exemplars")
+	}
+	return errors.Wrap(block.WriteExemplarsFile(logger, newDir, filter(exemplars)), "write exemplars")
+}
+
+// dirSize returns the total size of the files in the directory.
+func dirSize(dir string) (int64, error) {
+	size := int64(0)
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, errors.Wrapf(err, "compute size of %s", dir)
+}
@@ -44,6 +44,9 @@ type BlocksCleanerConfig struct {
 	DeleteBlocksConcurrency    int
 	NoBlocksFileCleanupEnabled bool
 	CompactionBlockRanges      mimir_tsdb.DurationList // Used for estimating compaction jobs.
+	DataDir                    string                  // Used for rewriting the blocks to apply the retention rules and the series deletion requests.
+	SeriesDeletionDelay        time.Duration           // Time after which the deleted samples are expected to have been uploaded by the ingesters.
+	MaxCheckedBlocksPerTenant  int                     // Max number of blocks checked by each tenant cleanup to find out whether they need to be rewritten.
 }
 
 type BlocksCleaner struct {
@@ -61,20 +64,29 @@ type BlocksCleaner struct {
 	lastOwnedUsers []string
 
 	// Metrics.
-	runsStarted                         prometheus.Counter
-	runsCompleted                       prometheus.Counter
-	runsFailed                          prometheus.Counter
-	runsLastSuccess                     prometheus.Gauge
-	blocksCleanedTotal                  prometheus.Counter
-	blocksFailedTotal                   prometheus.Counter
-	blocksMarkedForDeletion             prometheus.Counter
-	partialBlocksMarkedForDeletion      prometheus.Counter
-	tenantBlocks                        *prometheus.GaugeVec
-	tenantMarkedBlocks                  *prometheus.GaugeVec
-	tenantPartialBlocks                 *prometheus.GaugeVec
-	tenantBucketIndexLastUpdate         *prometheus.GaugeVec
-	bucketIndexCompactionJobs           *prometheus.GaugeVec
-	bucketIndexCompactionPlanningErrors prometheus.Counter
+	runsStarted                            prometheus.Counter
+	runsCompleted                          prometheus.Counter
+	runsFailed                             prometheus.Counter
+	runsLastSuccess                        prometheus.Gauge
+	blocksCleanedTotal                     prometheus.Counter
+	blocksFailedTotal                      prometheus.Counter
+	blocksMarkedForDeletion                prometheus.Counter
+	partialBlocksMarkedForDeletion         prometheus.Counter
+	tenantBlocks                           *prometheus.GaugeVec
+	tenantMarkedBlocks                     *prometheus.GaugeVec
+	tenantPartialBlocks                    *prometheus.GaugeVec
+	tenantBucketIndexLastUpdate            *prometheus.GaugeVec
+	bucketIndexCompactionJobs              *prometheus.GaugeVec
+	bucketIndexCompactionPlanningErrors    prometheus.Counter
+	retentionRulesBlocksRewritten          prometheus.Counter
+	retentionRulesSeriesRemoved            prometheus.Counter
+	retentionRulesBytesRemoved             prometheus.Counter
+	retentionRulesFailures                 prometheus.Counter
+	retentionRulesBlocksMarkedForNoCompact prometheus.Counter
+	seriesDeletionBlocksRewritten          prometheus.Counter
+	seriesDeletionFailures                 prometheus.Counter
+	seriesDeletionBlocksMarkedForNoCompact prometheus.Counter
+	seriesDeletionRequestsProcessed        prometheus.Counter
 }
 
 func NewBlocksCleaner(cfg BlocksCleanerConfig, bucketClient objstore.Bucket, ownUser func(userID string) (bool, error), cfgProvider ConfigProvider, logger log.Logger, reg prometheus.Registerer) *BlocksCleaner {
@@ -86,6 +98,7 @@ func NewBlocksCleaner(cfg BlocksCleanerConfig, bucketClient objstore.Bucket, own
 		cfgProvider:  cfgProvider,
 		singleFlight: concurrency.NewLimitedConcurrencySingleFlight(cfg.CleanupConcurrency),
 		logger:       log.With(logger, "component", "cleaner"),
+
 		runsStarted: promauto.With(reg).NewCounter(prometheus.CounterOpts{
 			Name: "cortex_compactor_block_cleanup_started_total",
 			Help: "Total number of blocks cleanup runs started.",
@@ -149,6 +162,42 @@ func NewBlocksCleaner(cfg BlocksCleanerConfig, bucketClient objstore.Bucket, own
 			Name: "cortex_bucket_index_estimated_compaction_jobs_errors_total",
 			Help: "Total number of failed executions of compaction job estimation based on latest version of bucket index.",
 		}),
+		retentionRulesBlocksRewritten: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "cortex_compactor_retention_rules_blocks_rewritten_total",
+			Help: "Total number of blocks rewritten to drop the series matching the retention rules.",
+		}),
+		retentionRulesSeriesRemoved: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "cortex_compactor_retention_rules_series_removed_total",
+			Help: "Total number of series removed from the blocks by the retention rules.",
+		}),
+		retentionRulesBytesRemoved: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "cortex_compactor_retention_rules_bytes_removed_total",
+			Help: "Total number of bytes removed from the blocks by the retention rules.",
+		}),
+		retentionRulesFailures: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "cortex_compactor_retention_rules_failures_total",
+			Help: "Total number of blocks which failed to be rewritten by the retention rules.",
+		}),
+		retentionRulesBlocksMarkedForNoCompact: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "cortex_compactor_retention_rules_blocks_marked_for_no_compaction_total",
+			Help: "Total number of blocks marked for no compaction while being rewritten by the retention rules.",
+		}),
+		seriesDeletionBlocksRewritten: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "cortex_compactor_series_deletion_blocks_rewritten_total",
+			Help: "Total number of blocks rewritten to purge the samples deleted by the series deletion requests.",
+		}),
+		seriesDeletionFailures: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "cortex_compactor_series_deletion_failures_total",
+			Help: "Total number of blocks which failed to be rewritten by the series deletion requests.",
+		}),
+		seriesDeletionBlocksMarkedForNoCompact: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "cortex_compactor_series_deletion_blocks_marked_for_no_compaction_total",
+			Help: "Total number of blocks marked for no compaction while being rewritten by the series deletion requests.",
+		}),
+		seriesDeletionRequestsProcessed: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "cortex_compactor_series_deletion_requests_processed_total",
+			Help: "Total number of series deletion requests whose samples have been purged from all the blocks.",
+		}),
 	}
 
 	c.Service = services.NewTimerService(cfg.CleanupInterval, c.starting, c.ticker, c.stopping)
@@ -429,6 +478,9 @@ func (c *BlocksCleaner) cleanUser(ctx context.Context, userID string, userLogger
 		// error occurs here. Errors are logged in the function.
 		retention := c.cfgProvider.CompactorBlocksRetentionPeriod(userID)
 		c.applyUserRetentionPeriod(ctx, idx, retention, userBucket, userLogger)
+		checks := newBlockChecksBudget(c.cfg.MaxCheckedBlocksPerTenant)
+		c.applyUserRetentionRules(ctx, userID, idx, c.cfgProvider.CompactorBlocksRetentionRules(userID), retention, checks, userBucket, userLogger)
+		c.applyUserSeriesDeletionRequests(ctx, userID, idx, checks, userBucket, userLogger)
 	}
 
 	// Generate an updated in-memory version of the bucket index.
@@ -35,6 +35,7 @@ import (
 	mimir_testutil "github.com/grafana/mimir/pkg/storage/tsdb/testutil"
 	"github.com/grafana/mimir/pkg/util"
 	"github.com/grafana/mimir/pkg/util/test"
+	"github.com/grafana/mimir/pkg/util/validation"
 )
 
 type testBlocksCleanerOptions struct {
@@ -1096,6 +1097,9 @@ func (m *mockBucketFailure) Delete(ctx context.Context, name string) error {
 
 type mockConfigProvider struct {
 	userRetentionPeriods         map[string]time.Duration
+	userRetentionRules           map[string][]*validation.RetentionRule
+	downsampling5mAfter          map[string]time.Duration
+	downsampling1hAfter          map[string]time.Duration
 	splitAndMergeShards          map[string]int
 	instancesShardSize           map[string]int
 	splitGroups                  map[string]int
@@ -1110,6 +1114,9 @@ type mockConfigProvider struct {
 func newMockConfigProvider() *mockConfigProvider {
 	return &mockConfigProvider{
 		userRetentionPeriods:         make(map[string]time.Duration),
+		userRetentionRules:           make(map[string][]*validation.RetentionRule),
+		downsampling5mAfter:          make(map[string]time.Duration),
+		downsampling1hAfter:          make(map[string]time.Duration),
 		splitAndMergeShards:          make(map[string]int),
 		splitGroups:                  make(map[string]int),
 		blockUploadEnabled:           make(map[string]bool),
@@ -1128,6 +1135,18 @@ func (m *mockConfigProvider) CompactorBlocksRetentionPeriod(user string) time.Du
 	return 0
 }
 
+func (m *mockConfigProvider) CompactorBlocksRetentionRules(user string) []*validation.RetentionRule {
+	return m.userRetentionRules[user]
+}
+
+func (m *mockConfigProvider) CompactorDownsampling5mAfter(user string) time.Duration {
+	return m.downsampling5mAfter[user]
+}
+
+func (m *mockConfigProvider) CompactorDownsampling1hAfter(user string) time.Duration {
+	return m.downsampling1hAfter[user]
+}
+
 func (m *mockConfigProvider) CompactorSplitAndMergeShards(user string) int {
 	if result, ok := m.splitAndMergeShards[user]; ok {
 		return result
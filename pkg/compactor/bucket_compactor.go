@@ -291,8 +291,15 @@ func (c *BucketCompactor) runCompactionJob(ctx context.Context, job *Job) (shoul
 
 	level.Info(jobLogger).Log("msg", "compaction available and planned; downloading blocks", "block_count", len(toCompact), "blocks", toCompactStr)
 
+	// Samples of the series matching the series deletion requests are purged while compacting.
+	seriesDeletionRequests, err := mimir_tsdb.ListSeriesDeletionRequests(ctx, c.bkt)
+	if err != nil {
+		return false, nil, err
+	}
+
 	// Once we have a plan we need to download the actual data.
 	downloadBegin := time.Now()
+	blocksWithTombstones := make([]bool, len(toCompact))
 
 	err = concurrency.ForEachJob(ctx, len(toCompact), c.blockSyncConcurrency, func(ctx context.Context, idx int) error {
 		meta := toCompact[idx]
@@ -328,6 +335,11 @@ func (c *BucketCompactor) runCompactionJob(ctx context.Context, job *Job) (shoul
 		if err := stats.OutOfOrderLabelsErr(); err != nil {
 			return errors.Wrapf(err, "block id %s", meta.ULID)
 		}
+
+		blocksWithTombstones[idx], err = writeSeriesDeletionTombstones(ctx, jobLogger, bdir, meta, seriesDeletionRequests)
+		if err != nil {
+			return errors.Wrapf(err, "apply series deletion requests to block %s", meta.ULID)
+		}
 		return nil
 	})
 	if err != nil {
@@ -358,8 +370,9 @@ func (c *BucketCompactor) runCompactionJob(ctx context.Context, job *Job) (shoul
 	if !hasNonZeroULIDs(compIDs) {
 		// Prometheus compactor found that the compacted block would have no samples.
 		level.Info(jobLogger).Log("msg", "compacted block would have no samples, deleting source blocks", "blocks", toCompactStr)
-		for _, meta := range toCompact {
-			if meta.Stats.NumSamples == 0 {
+		for idx, meta := range toCompact {
+			// Blocks whose samples have all been deleted by series deletion requests are deleted too.
+			if meta.Stats.NumSamples == 0 || blocksWithTombstones[idx] {
 				if err := deleteBlock(c.bkt, meta.ULID, filepath.Join(subDir, meta.ULID.String()), jobLogger, c.metrics.blocksMarkedForDeletion); err != nil {
 					level.Warn(jobLogger).Log("msg", "failed to mark for deletion an empty block found during compaction", "block", meta.ULID, "err", err)
 				}
@@ -372,6 +385,22 @@ func (c *BucketCompactor) runCompactionJob(ctx context.Context, job *Job) (shoul
 	elapsed = time.Since(compactionBegin)
 	level.Info(jobLogger).Log("msg", "compacted blocks", "new", fmt.Sprintf("%v", compIDs), "blocks", toCompactStr, "duration", elapsed, "duration_ms", elapsed.Milliseconds())
 
+	// The exemplars of the source blocks are merged into the compacted blocks.
+	exemplars, err := compactionExemplars(blocksToCompactDirs, seriesDeletionRequests)
+	if err != nil {
+		return false, nil, err
+	}
+	exemplarsShards := uint64(1)
+	if job.UseSplitting() {
+		exemplarsShards = uint64(job.SplittingShards())
+	}
+
+	// The metric metadata of the source blocks is merged into the compacted blocks.
+	metricMetadata, err := compactionMetricMetadata(blocksToCompactDirs)
+	if err != nil {
+		return false, nil, err
+	}
+
 	uploadBegin := time.Now()
 	uploadedBlocks := atomic.NewInt64(0)
 
@@ -408,6 +437,14 @@ func (c *BucketCompactor) runCompactionJob(ctx context.Context, job *Job) (shoul
 			return errors.Wrap(err, "remove tombstones")
 		}
 
+		if err := writeCompactedBlockExemplars(jobLogger, bdir, newMeta, exemplars, uint64(blockToUpload.shardIndex), exemplarsShards); err != nil {
+			return errors.Wrapf(err, "write exemplars of block %s", bdir)
+		}
+
+		if err := block.WriteMetricMetadataFile(jobLogger, bdir, metricMetadata); err != nil {
+			return errors.Wrapf(err, "write metric metadata of block %s", bdir)
+		}
+
 		// Ensure the compacted block is valid.
 		if err := block.VerifyBlock(ctx, jobLogger, bdir, newMeta.MinTime, newMeta.MaxTime, false); err != nil {
 			return errors.Wrapf(err, "invalid result block %s", bdir)
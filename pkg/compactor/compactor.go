@@ -35,6 +35,7 @@ import (
 	"github.com/grafana/mimir/pkg/storage/tsdb/block"
 	"github.com/grafana/mimir/pkg/util"
 	util_log "github.com/grafana/mimir/pkg/util/log"
+	"github.com/grafana/mimir/pkg/util/validation"
 )
 
 const (
@@ -91,6 +92,7 @@ type Config struct {
 	CompactionWaitPeriod       time.Duration           `yaml:"first_level_compaction_wait_period"`
 	CleanupInterval            time.Duration           `yaml:"cleanup_interval" category:"advanced"`
 	CleanupConcurrency         int                     `yaml:"cleanup_concurrency" category:"advanced"`
+	CleanupMaxCheckedBlocks    int                     `yaml:"cleanup_max_checked_blocks_per_tenant" category:"experimental"`
 	DeletionDelay              time.Duration           `yaml:"deletion_delay" category:"advanced"`
 	TenantCleanupDelay         time.Duration           `yaml:"tenant_cleanup_delay" category:"advanced"`
 	MaxCompactionTime          time.Duration           `yaml:"max_compaction_time" category:"advanced"`
@@ -140,6 +142,7 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet, logger log.Logger) {
 	f.DurationVar(&cfg.CompactionWaitPeriod, "compactor.first-level-compaction-wait-period", 25*time.Minute, "How long the compactor waits before compacting first-level blocks that are uploaded by the ingesters. This configuration option allows for the reduction of cases where the compactor begins to compact blocks before all ingesters have uploaded their blocks to the storage.")
 	f.DurationVar(&cfg.CleanupInterval, "compactor.cleanup-interval", 15*time.Minute, "How frequently the compactor should run blocks cleanup and maintenance, as well as update the bucket index.")
 	f.IntVar(&cfg.CleanupConcurrency, "compactor.cleanup-concurrency", 20, "Max number of tenants for which blocks cleanup and maintenance should run concurrently.")
+	f.IntVar(&cfg.CleanupMaxCheckedBlocks, "compactor.cleanup-max-checked-blocks-per-tenant", 10, "Max number of blocks per tenant checked by each blocks cleanup to find out whether the series deletion requests and the retention rules require rewriting them. Checking a block downloads its index. The remaining blocks are checked by the following cleanups. 0 = no limit.")
 	f.StringVar(&cfg.CompactionJobsOrder, "compactor.compaction-jobs-order", CompactionOrderOldestFirst, fmt.Sprintf("The sorting to use when deciding which compaction jobs should run first for a given tenant. Supported values are: %s.", strings.Join(CompactionOrders, ", ")))
 	f.DurationVar(&cfg.DeletionDelay, "compactor.deletion-delay", 12*time.Hour, "Time before a block marked for deletion is deleted from bucket. "+
 		"If not 0, blocks will be marked for deletion and the compactor component will permanently delete blocks marked for deletion from the bucket. "+
@@ -190,6 +193,15 @@ type ConfigProvider interface {
 	// CompactorBlocksRetentionPeriod returns the retention period for a given user.
 	CompactorBlocksRetentionPeriod(user string) time.Duration
 
+	// CompactorBlocksRetentionRules returns the retention rules for a given user.
+	CompactorBlocksRetentionRules(user string) []*validation.RetentionRule
+
+	// CompactorDownsampling5mAfter returns the age after which the blocks of a given user are downsampled to a 5m resolution.
+	CompactorDownsampling5mAfter(userID string) time.Duration
+
+	// CompactorDownsampling1hAfter returns the age after which the blocks of a given user are downsampled to a 1h resolution.
+	CompactorDownsampling1hAfter(userID string) time.Duration
+
 	// CompactorSplitAndMergeShards returns the number of shards to use when splitting blocks.
 	CompactorSplitAndMergeShards(userID string) int
 
@@ -266,6 +278,8 @@ type MultitenantCompactor struct {
 	compactionRunFailedTenants     prometheus.Gauge
 	compactionRunInterval          prometheus.Gauge
 	blocksMarkedForDeletion        prometheus.Counter
+	blocksDownsampled              *prometheus.CounterVec
+	downsamplingFailures           prometheus.Counter
 
 	// Metrics shared across all BucketCompactor instances.
 	bucketCompactorMetrics *BucketCompactorMetrics
@@ -371,6 +385,14 @@ func newMultitenantCompactor(
 			Help:        blocksMarkedForDeletionHelp,
 			ConstLabels: prometheus.Labels{"reason": "compaction"},
 		}),
+		blocksDownsampled: promauto.With(registerer).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_compactor_blocks_downsampled_total",
+			Help: "Total number of blocks downsampled by the compactor.",
+		}, []string{"resolution"}),
+		downsamplingFailures: promauto.With(registerer).NewCounter(prometheus.CounterOpts{
+			Name: "cortex_compactor_downsampling_failures_total",
+			Help: "Total number of blocks the compactor failed to downsample.",
+		}),
 		blockUploadBlocks: promauto.With(registerer).NewGaugeVec(prometheus.GaugeOpts{
 			Name: "cortex_block_upload_api_blocks_total",
 			Help: "Total number of blocks successfully uploaded and validated using the block upload API.",
@@ -494,6 +516,9 @@ func (c *MultitenantCompactor) starting(ctx context.Context) error {
 		DeleteBlocksConcurrency:    defaultDeleteBlocksConcurrency,
 		NoBlocksFileCleanupEnabled: c.compactorCfg.NoBlocksFileCleanupEnabled,
 		CompactionBlockRanges:      c.compactorCfg.BlockRanges,
+		DataDir:                    c.compactorCfg.DataDir,
+		SeriesDeletionDelay:        c.storageCfg.TSDB.Retention,
+		MaxCheckedBlocksPerTenant:  c.compactorCfg.CleanupMaxCheckedBlocks,
 	}, c.bucketClient, c.shardingStrategy.blocksCleanerOwnsUser, c.cfgProvider, c.parentLogger, c.registerer)
 
 	// Start blocks cleaner asynchronously, don't wait until initial cleanup is finished.
@@ -784,6 +809,14 @@ func (c *MultitenantCompactor) compactUser(ctx context.Context, userID string) e
 		return errors.Wrap(err, "compaction")
 	}
 
+	if c.cfgProvider.CompactorDownsampling5mAfter(userID) > 0 {
+		// Sync the metas again to downsample the blocks resulting from the compaction, instead of their sources.
+		if err := syncer.SyncMetas(ctx); err != nil {
+			return errors.Wrap(err, "sync metas before downsampling")
+		}
+		c.downsampleUser(ctx, userID, userBucket, syncer.Metas(), userLogger)
+	}
+
 	return nil
 }
 
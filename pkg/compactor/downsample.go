@@ -0,0 +1,143 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package compactor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/oklog/ulid"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
+	"github.com/grafana/mimir/pkg/storage/tsdb/downsample"
+)
+
+// downsamplingDirName is the name of the directory, in the compactor data directory, where the blocks are downsampled.
+const downsamplingDirName = "downsample"
+
+// downsampleUser downsamples the blocks whose samples are all older than the downsampling ages configured for the user.
+// The raw blocks are downsampled to a 5m resolution, and the 5m resolution blocks to a 1h resolution. A block isn't
+// downsampled again if its sources have already been downsampled to the target resolution.
+func (c *MultitenantCompactor) downsampleUser(ctx context.Context, userID string, userBucket objstore.Bucket, metas map[ulid.ULID]*block.Meta, userLogger log.Logger) {
+	after := map[int64]time.Duration{
+		downsample.ResLevel1: c.cfgProvider.CompactorDownsampling5mAfter(userID),
+		downsample.ResLevel2: c.cfgProvider.CompactorDownsampling1hAfter(userID),
+	}
+
+	downsampledSources := map[int64]map[ulid.ULID]struct{}{
+		downsample.ResLevel1: {},
+		downsample.ResLevel2: {},
+	}
+	for _, meta := range metas {
+		if sources, ok := downsampledSources[meta.Thanos.Downsample.Resolution]; ok {
+			for _, id := range meta.Compaction.Sources {
+				sources[id] = struct{}{}
+			}
+		}
+	}
+
+	now := time.Now()
+	for _, meta := range metas {
+		if ctx.Err() != nil {
+			return
+		}
+
+		var resolution int64
+		switch meta.Thanos.Downsample.Resolution {
+		case downsample.ResLevel0:
+			resolution = downsample.ResLevel1
+		case downsample.ResLevel1:
+			resolution = downsample.ResLevel2
+		default:
+			continue
+		}
+		if after[resolution] <= 0 || time.UnixMilli(meta.MaxTime).After(now.Add(-after[resolution])) {
+			continue
+		}
+		if sourcesIncluded(meta.Compaction.Sources, downsampledSources[resolution]) {
+			continue
+		}
+
+		if err := c.downsampleBlock(ctx, userID, meta, resolution, userBucket, userLogger); err != nil {
+			// The block is downsampled again in the next compaction cycle.
+			c.downsamplingFailures.Inc()
+			level.Warn(userLogger).Log("msg", "failed to downsample block", "block", meta.ULID, "resolution", downsample.ResolutionString(resolution), "err", err)
+			continue
+		}
+		for _, id := range meta.Compaction.Sources {
+			downsampledSources[resolution][id] = struct{}{}
+		}
+	}
+}
+
+// downsampleBlock downloads the block, downsamples it to the given resolution and uploads the downsampled block.
+func (c *MultitenantCompactor) downsampleBlock(ctx context.Context, userID string, meta *block.Meta, resolution int64, userBucket objstore.Bucket, userLogger log.Logger) error {
+	blockLogger := log.With(userLogger, "block", meta.ULID, "resolution", downsample.ResolutionString(resolution))
+	dir := filepath.Join(c.compactorCfg.DataDir, downsamplingDirName, userID)
+	bdir := filepath.Join(dir, meta.ULID.String())
+
+	if err := os.RemoveAll(dir); err != nil {
+		return errors.Wrap(err, "clean up the downsampling directory")
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			level.Warn(blockLogger).Log("msg", "failed to remove the downsampling directory", "dir", dir, "err", err)
+		}
+	}()
+
+	level.Info(blockLogger).Log("msg", "downsampling block")
+	if err := block.Download(ctx, blockLogger, userBucket, meta.ULID, bdir); err != nil {
+		return errors.Wrap(err, "download block")
+	}
+
+	b, err := tsdb.OpenBlock(blockLogger, bdir, nil)
+	if err != nil {
+		return errors.Wrap(err, "open block")
+	}
+	newID, err := downsample.Block(ctx, blockLogger, meta, b, dir, resolution)
+	if closeErr := b.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.Wrap(err, "downsample block")
+	}
+	if newID == (ulid.ULID{}) {
+		level.Info(blockLogger).Log("msg", "downsampled block would have no samples")
+		return nil
+	}
+
+	newDir := filepath.Join(dir, newID.String())
+	if err := copyBlockMetricMetadata(blockLogger, bdir, newDir); err != nil {
+		return err
+	}
+	if err := block.VerifyBlock(ctx, blockLogger, newDir, meta.MinTime, meta.MaxTime, false); err != nil {
+		return errors.Wrapf(err, "invalid downsampled block %s", newID)
+	}
+	if err := block.Upload(ctx, blockLogger, userBucket, newDir, nil); err != nil {
+		return errors.Wrapf(err, "upload of %s failed", newID)
+	}
+
+	c.blocksDownsampled.WithLabelValues(downsample.ResolutionString(resolution)).Inc()
+	level.Info(blockLogger).Log("msg", "uploaded downsampled block", "result_block", newID)
+	return nil
+}
+
+// sourcesIncluded returns whether all the sources are included in the set.
+func sourcesIncluded(sources []ulid.ULID, set map[ulid.ULID]struct{}) bool {
+	if len(sources) == 0 {
+		return false
+	}
+	for _, id := range sources {
+		if _, ok := set[id]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package compactor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/oklog/ulid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/mimir/pkg/storage/bucket"
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
+	"github.com/grafana/mimir/pkg/storage/tsdb/downsample"
+)
+
+func TestMultitenantCompactor_DownsampleUser(t *testing.T) {
+	const userID = "user-1"
+
+	ctx := context.Background()
+	logger := log.NewNopLogger()
+	bucketClient := objstore.NewInMemBucket()
+	userBucket := bucket.NewUserBucketClient(userID, bucketClient, nil)
+
+	now := time.Now()
+	ts := func(hours int) int64 {
+		return now.Add(time.Duration(hours) * time.Hour).UnixMilli()
+	}
+	extLabels := map[string]string{"__compactor_shard_id__": "1_of_2"}
+
+	oldBlock := createTSDBBlock(t, bucketClient, userID, ts(-50), ts(-48), 10, extLabels)
+	recentBlock := createTSDBBlock(t, bucketClient, userID, ts(-4), ts(-2), 10, extLabels)
+
+	cfgProvider := newMockConfigProvider()
+	cfgProvider.downsampling5mAfter[userID] = 24 * time.Hour
+	cfgProvider.downsampling1hAfter[userID] = 7 * 24 * time.Hour
+
+	c := &MultitenantCompactor{
+		compactorCfg: Config{DataDir: t.TempDir()},
+		cfgProvider:  cfgProvider,
+		blocksDownsampled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_compactor_blocks_downsampled_total",
+		}, []string{"resolution"}),
+		downsamplingFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "cortex_compactor_downsampling_failures_total",
+		}),
+	}
+
+	metas := readDownsamplingTestMetas(t, userBucket)
+	require.Len(t, metas, 2)
+	c.downsampleUser(ctx, userID, userBucket, metas, logger)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(c.blocksDownsampled.WithLabelValues("5m")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(c.blocksDownsampled.WithLabelValues("1h")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(c.downsamplingFailures))
+
+	// Only the old block has been downsampled, and the raw blocks are kept.
+	metas = readDownsamplingTestMetas(t, userBucket)
+	require.Len(t, metas, 3)
+	require.Contains(t, metas, oldBlock)
+	require.Contains(t, metas, recentBlock)
+
+	var downsampled *block.Meta
+	for id, meta := range metas {
+		if id != oldBlock && id != recentBlock {
+			downsampled = meta
+		}
+	}
+	require.NotNil(t, downsampled)
+	assert.Equal(t, downsample.ResLevel1, downsampled.Thanos.Downsample.Resolution)
+	assert.Equal(t, metas[oldBlock].MinTime, downsampled.MinTime)
+	assert.Equal(t, metas[oldBlock].MaxTime, downsampled.MaxTime)
+	assert.Equal(t, extLabels, downsampled.Thanos.Labels)
+	assert.Equal(t, []ulid.ULID{oldBlock}, downsampled.Compaction.Sources)
+
+	// The block isn't downsampled again, and the 5m block is too recent to be downsampled to 1h.
+	c.downsampleUser(ctx, userID, userBucket, metas, logger)
+	assert.Equal(t, 1.0, testutil.ToFloat64(c.blocksDownsampled.WithLabelValues("5m")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(c.blocksDownsampled.WithLabelValues("1h")))
+	assert.Len(t, readDownsamplingTestMetas(t, userBucket), 3)
+
+	// The 5m block is downsampled to 1h once it's older than the configured age.
+	cfgProvider.downsampling1hAfter[userID] = 24 * time.Hour
+	c.downsampleUser(ctx, userID, userBucket, metas, logger)
+	assert.Equal(t, 1.0, testutil.ToFloat64(c.blocksDownsampled.WithLabelValues("1h")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(c.downsamplingFailures))
+	assert.Len(t, readDownsamplingTestMetas(t, userBucket), 4)
+}
+
+func readDownsamplingTestMetas(t *testing.T, userBucket objstore.Bucket) map[ulid.ULID]*block.Meta {
+	metas := map[ulid.ULID]*block.Meta{}
+	require.NoError(t, userBucket.Iter(context.Background(), "", func(name string) error {
+		id, ok := block.IsBlockDir(name)
+		if !ok {
+			return nil
+		}
+		meta, err := block.DownloadMeta(context.Background(), log.NewNopLogger(), userBucket, id)
+		if err != nil {
+			return err
+		}
+		metas[id] = &meta
+		return nil
+	}))
+	return metas
+}
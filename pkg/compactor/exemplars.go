@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package compactor
+
+import (
+	"path/filepath"
+
+	"github.com/go-kit/log"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/labels"
+
+	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
+)
+
+// compactionExemplars returns the exemplars of the blocks to compact, merged by series, without the exemplars deleted
+// by the series deletion requests.
+func compactionExemplars(dirs []string, requests []*mimir_tsdb.SeriesDeletionRequest) ([]exemplar.QueryResult, error) {
+	sets := make([][]exemplar.QueryResult, 0, len(dirs))
+	for _, dir := range dirs {
+		series, err := block.ReadExemplarsFromDir(dir)
+		if err != nil {
+			return nil, errors.Wrapf(err, "read exemplars of block %s", filepath.Base(dir))
+		}
+		sets = append(sets, series)
+	}
+
+	exemplars := block.MergeExemplars(sets...)
+	for _, req := range requests {
+		matchersSets, err := req.Matchers()
+		if err != nil {
+			return nil, err
+		}
+		exemplars = deleteExemplars(exemplars, matchersSets, req.StartTime, req.EndTime)
+	}
+	return exemplars, nil
+}
+
+// writeCompactedBlockExemplars writes the exemplars of the series of the shard of the compacted block, within the
+// time range of the block, to the exemplars file of the block.
+func writeCompactedBlockExemplars(logger log.Logger, bdir string, meta *block.Meta, exemplars []exemplar.QueryResult, shardIndex, shardCount uint64) error {
+	var blockExemplars []exemplar.QueryResult
+	for _, s := range exemplars {
+		// The series are split between the shards the same way the TSDB compactor does.
+		if shardCount > 1 && labels.StableHash(s.SeriesLabels)%shardCount != shardIndex {
+			continue
+		}
+
+		var kept []exemplar.Exemplar
+		for _, e := range s.Exemplars {
+			if e.Ts >= meta.MinTime && e.Ts < meta.MaxTime {
+				kept = append(kept, e)
+			}
+		}
+		if len(kept) > 0 {
+			blockExemplars = append(blockExemplars, exemplar.QueryResult{SeriesLabels: s.SeriesLabels, Exemplars: kept})
+		}
+	}
+	return block.WriteExemplarsFile(logger, bdir, blockExemplars)
+}
+
+// deleteExemplars removes the exemplars of the series matching any of the sets of matchers, within the time range
+// (inclusive). The series left without exemplars are removed.
+func deleteExemplars(series []exemplar.QueryResult, matchersSets [][]*labels.Matcher, minT, maxT int64) []exemplar.QueryResult {
+	result := series[:0]
+	for _, s := range series {
+		if block.MatchesAnyMatchersSet(s.SeriesLabels, matchersSets) {
+			kept := s.Exemplars[:0]
+			for _, e := range s.Exemplars {
+				if e.Ts < minT || e.Ts > maxT {
+					kept = append(kept, e)
+				}
+			}
+			s.Exemplars = kept
+		}
+		if len(s.Exemplars) > 0 {
+			result = append(result, s)
+		}
+	}
+	return result
+}
@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package compactor
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
+)
+
+func TestDeleteExemplars(t *testing.T) {
+	seriesA := labels.FromStrings("__name__", "metric", "job", "a")
+	seriesB := labels.FromStrings("__name__", "metric", "job", "b")
+	e := func(ts int64) exemplar.Exemplar {
+		return exemplar.Exemplar{Labels: labels.FromStrings("trace_id", "1"), Value: 1, Ts: ts}
+	}
+	series := func() []exemplar.QueryResult {
+		return []exemplar.QueryResult{
+			{SeriesLabels: seriesA, Exemplars: []exemplar.Exemplar{e(10), e(20), e(30)}},
+			{SeriesLabels: seriesB, Exemplars: []exemplar.Exemplar{e(10), e(20)}},
+		}
+	}
+
+	tests := map[string]struct {
+		matchersSets [][]*labels.Matcher
+		minT, maxT   int64
+		expected     []exemplar.QueryResult
+	}{
+		"no matching series": {
+			matchersSets: [][]*labels.Matcher{{labels.MustNewMatcher(labels.MatchEqual, "job", "c")}},
+			minT:         math.MinInt64,
+			maxT:         math.MaxInt64,
+			expected:     series(),
+		},
+		"within the time range": {
+			matchersSets: [][]*labels.Matcher{{labels.MustNewMatcher(labels.MatchEqual, "job", "a")}},
+			minT:         10,
+			maxT:         20,
+			expected: []exemplar.QueryResult{
+				{SeriesLabels: seriesA, Exemplars: []exemplar.Exemplar{e(30)}},
+				{SeriesLabels: seriesB, Exemplars: []exemplar.Exemplar{e(10), e(20)}},
+			},
+		},
+		"series left without exemplars are removed": {
+			matchersSets: [][]*labels.Matcher{
+				{labels.MustNewMatcher(labels.MatchEqual, "job", "c")},
+				{labels.MustNewMatcher(labels.MatchEqual, "job", "b")},
+			},
+			minT: math.MinInt64,
+			maxT: math.MaxInt64,
+			expected: []exemplar.QueryResult{
+				{SeriesLabels: seriesA, Exemplars: []exemplar.Exemplar{e(10), e(20), e(30)}},
+			},
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			assert.Equal(t, testData.expected, deleteExemplars(series(), testData.matchersSets, testData.minT, testData.maxT))
+		})
+	}
+}
+
+func TestWriteCompactedBlockExemplars(t *testing.T) {
+	var series []exemplar.QueryResult
+	for _, job := range []string{"a", "b", "c", "d", "e", "f"} {
+		series = append(series, exemplar.QueryResult{
+			SeriesLabels: labels.FromStrings("__name__", "metric", "job", job),
+			Exemplars: []exemplar.Exemplar{
+				{Labels: labels.FromStrings("trace_id", "1"), Value: 1, Ts: 10},
+				{Labels: labels.FromStrings("trace_id", "2"), Value: 2, Ts: 20},
+			},
+		})
+	}
+	meta := &block.Meta{BlockMeta: tsdb.BlockMeta{MinTime: 0, MaxTime: 20}}
+
+	// The series are split between the shards, and only the exemplars within the block time range are kept.
+	var total int
+	for shardIndex := uint64(0); shardIndex < 2; shardIndex++ {
+		dir := t.TempDir()
+		require.NoError(t, writeCompactedBlockExemplars(log.NewNopLogger(), dir, meta, series, shardIndex, 2))
+
+		actual, err := block.ReadExemplarsFromDir(dir)
+		require.NoError(t, err)
+		for _, s := range actual {
+			assert.Equal(t, shardIndex, labels.StableHash(s.SeriesLabels)%2)
+			assert.Equal(t, []exemplar.Exemplar{{Labels: labels.FromStrings("trace_id", "1"), Value: 1, Ts: 10}}, s.Exemplars)
+		}
+		total += len(actual)
+	}
+	assert.Equal(t, len(series), total)
+}
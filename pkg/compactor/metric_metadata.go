@@ -0,0 +1,37 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package compactor
+
+import (
+	"path/filepath"
+
+	"github.com/go-kit/log"
+	"github.com/pkg/errors"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
+)
+
+// compactionMetricMetadata returns the metric metadata of the blocks to compact, merged and deduplicated.
+// The metric metadata isn't split between the shards of the compacted blocks, because it's small compared
+// to the series, and each shard may contain series of the same metric family.
+func compactionMetricMetadata(dirs []string) ([]mimirpb.MetricMetadata, error) {
+	sets := make([][]mimirpb.MetricMetadata, 0, len(dirs))
+	for _, dir := range dirs {
+		md, err := block.ReadMetricMetadataFromDir(dir)
+		if err != nil {
+			return nil, errors.Wrapf(err, "read metric metadata of block %s", filepath.Base(dir))
+		}
+		sets = append(sets, md)
+	}
+	return block.MergeMetricMetadata(sets...), nil
+}
+
+// copyBlockMetricMetadata copies the metric metadata of the block stored in bdir to the block stored in newDir.
+func copyBlockMetricMetadata(logger log.Logger, bdir, newDir string) error {
+	md, err := block.ReadMetricMetadataFromDir(bdir)
+	if err != nil {
+		return errors.Wrap(err, "read metric metadata")
+	}
+	return errors.Wrap(block.WriteMetricMetadataFile(logger, newDir, md), "write metric metadata")
+}
@@ -0,0 +1,43 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package compactor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
+)
+
+func TestCompactionMetricMetadata(t *testing.T) {
+	logger := log.NewNopLogger()
+	up := mimirpb.MetricMetadata{Type: mimirpb.GAUGE, MetricFamilyName: "up", Help: "Up."}
+	requests := mimirpb.MetricMetadata{Type: mimirpb.COUNTER, MetricFamilyName: "requests", Help: "Requests."}
+
+	dirs := []string{t.TempDir(), t.TempDir(), t.TempDir()}
+	require.NoError(t, block.WriteMetricMetadataFile(logger, dirs[0], []mimirpb.MetricMetadata{up, requests}))
+	require.NoError(t, block.WriteMetricMetadataFile(logger, dirs[1], []mimirpb.MetricMetadata{up}))
+	// The last block has no metric metadata.
+
+	md, err := compactionMetricMetadata(dirs)
+	require.NoError(t, err)
+	assert.Equal(t, []mimirpb.MetricMetadata{requests, up}, md)
+
+	newDir := t.TempDir()
+	require.NoError(t, copyBlockMetricMetadata(logger, dirs[0], newDir))
+	md, err = block.ReadMetricMetadataFromDir(newDir)
+	require.NoError(t, err)
+	assert.Equal(t, []mimirpb.MetricMetadata{requests, up}, md)
+
+	// Nothing is written if the block has no metric metadata.
+	newDir = t.TempDir()
+	require.NoError(t, copyBlockMetricMetadata(logger, dirs[2], newDir))
+	_, err = os.Stat(filepath.Join(newDir, block.MetricMetadataFilename))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,190 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package compactor
+
+import (
+	"context"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/oklog/ulid"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/prometheus/prometheus/tsdb/index"
+	"github.com/prometheus/prometheus/tsdb/tombstones"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
+	"github.com/grafana/mimir/pkg/storage/tsdb/bucketindex"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+// retentionRulesDirName is the name of the directory, in the compactor data directory, where the blocks
+// rewritten by the retention rules are downloaded.
+const retentionRulesDirName = "retention-rules"
+
+// applyUserRetentionRules rewrites the blocks whose time range is entirely older than the period of some retention rules,
+// dropping the series matching these rules. The blocks older than the tenant retention period are skipped, because
+// they're deleted anyway.
+func (c *BlocksCleaner) applyUserRetentionRules(ctx context.Context, userID string, idx *bucketindex.Index, rules []*validation.RetentionRule, retention time.Duration, checks *blockChecksBudget, userBucket objstore.Bucket, userLogger log.Logger) {
+	if len(rules) == 0 {
+		return
+	}
+
+	marked := make(map[ulid.ULID]struct{}, len(idx.BlockDeletionMarks))
+	for _, d := range idx.BlockDeletionMarks {
+		marked[d.ID] = struct{}{}
+	}
+
+	now := time.Now()
+	for _, b := range idx.Blocks {
+		if ctx.Err() != nil {
+			return
+		}
+		if _, isMarked := marked[b.ID]; isMarked {
+			continue
+		}
+		if retention > 0 && time.UnixMilli(b.MaxTime).Before(now.Add(-retention)) {
+			continue
+		}
+
+		var (
+			blockRules []*validation.RetentionRule
+			matches    []string
+		)
+		for _, rule := range rules {
+			if !time.UnixMilli(b.MaxTime).After(now.Add(-time.Duration(rule.Period))) {
+				blockRules = append(blockRules, rule)
+				matches = append(matches, rule.Match)
+			}
+		}
+		if len(blockRules) == 0 {
+			continue
+		}
+
+		// The rules applying to a block only change when a rule is added or when the block ages out of another rule.
+		// The rules applied to a block are recorded in its bucket index entry, and in its meta when it's rewritten.
+		sort.Strings(matches)
+		if containsAll(b.RetentionRules, matches) {
+			continue
+		}
+		if !checks.take() {
+			// The rules are applied in the next cycles.
+			continue
+		}
+
+		if err := c.applyRetentionRulesToBlock(ctx, userID, b, blockRules, matches, userBucket, userLogger); err != nil {
+			// The rules are applied again in the next cycle.
+			c.retentionRulesFailures.Inc()
+			level.Warn(userLogger).Log("msg", "failed to apply retention rules to block", "block", b.ID, "err", err)
+			continue
+		}
+
+		b.RetentionRules = mergeApplied(b.RetentionRules, matches)
+	}
+}
+
+// applyRetentionRulesToBlock rewrites the block without the series matching the retention rules, uploads the new block
+// and marks the original block for deletion. The block is left untouched if it doesn't contain any matching series.
+// The matchers of the rules are stored in the meta of the new block.
+func (c *BlocksCleaner) applyRetentionRulesToBlock(ctx context.Context, userID string, b *bucketindex.Block, rules []*validation.RetentionRule, matches []string, userBucket objstore.Bucket, userLogger log.Logger) error {
+	blockLogger := log.With(userLogger, "block", b.ID)
+	dir := filepath.Join(c.cfg.DataDir, retentionRulesDirName, userID)
+	bdir := filepath.Join(dir, b.ID.String())
+
+	if err := os.RemoveAll(dir); err != nil {
+		return errors.Wrap(err, "clean up the retention rules directory")
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			level.Warn(blockLogger).Log("msg", "failed to remove the retention rules directory", "dir", dir, "err", err)
+		}
+	}()
+
+	// The index is enough to find whether the block contains series matching the rules.
+	if err := os.MkdirAll(bdir, 0o750); err != nil {
+		return errors.Wrap(err, "create block directory")
+	}
+	if err := objstore.DownloadFile(ctx, blockLogger, userBucket, path.Join(b.ID.String(), block.IndexFilename), filepath.Join(bdir, block.IndexFilename)); err != nil {
+		return errors.Wrap(err, "download index")
+	}
+	series, err := retentionRulesMatchingSeries(ctx, filepath.Join(bdir, block.IndexFilename), rules)
+	if err != nil {
+		return err
+	}
+	if len(series) == 0 {
+		level.Debug(blockLogger).Log("msg", "block contains no series matching the retention rules")
+		return nil
+	}
+
+	level.Info(blockLogger).Log("msg", "rewriting block to drop the series matching the retention rules", "series", len(series))
+
+	// The series are dropped by deleting all their samples, which is what the TSDB compactor does when
+	// writing a block with tombstones.
+	stones := tombstones.NewMemTombstones()
+	for _, ref := range series {
+		stones.AddInterval(ref, tombstones.Interval{Mint: b.MinTime, Maxt: b.MaxTime})
+	}
+
+	matchersSets := make([][]*labels.Matcher, 0, len(rules))
+	for _, rule := range rules {
+		matchersSets = append(matchersSets, rule.Matchers())
+	}
+
+	bytesRemoved, err := c.rewriteBlockWithTombstones(ctx, blockLogger, userBucket, dir, b.ID, stones, blockRewrite{
+		description:        "retention rules",
+		noCompactReason:    block.RetentionRulesNoCompactReason,
+		markedForNoCompact: c.retentionRulesBlocksMarkedForNoCompact,
+		retentionRules:     mergeApplied(b.RetentionRules, matches),
+		// The series deletion requests already applied to the block still apply to the new block.
+		seriesDeletionRequests: b.SeriesDeletionRequests,
+		filterExemplars: func(exemplars []exemplar.QueryResult) []exemplar.QueryResult {
+			return deleteExemplars(exemplars, matchersSets, math.MinInt64, math.MaxInt64)
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	c.retentionRulesBlocksRewritten.Inc()
+	c.retentionRulesSeriesRemoved.Add(float64(len(series)))
+	c.retentionRulesBytesRemoved.Add(float64(bytesRemoved))
+	return nil
+}
+
+// retentionRulesMatchingSeries returns the series of the index matching any of the retention rules.
+func retentionRulesMatchingSeries(ctx context.Context, indexPath string, rules []*validation.RetentionRule) ([]storage.SeriesRef, error) {
+	ir, err := index.NewFileReader(indexPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "open index")
+	}
+	defer ir.Close()
+
+	seen := map[storage.SeriesRef]struct{}{}
+	var series []storage.SeriesRef
+	for _, rule := range rules {
+		postings, err := tsdb.PostingsForMatchers(ctx, ir, rule.Matchers()...)
+		if err != nil {
+			return nil, errors.Wrap(err, "select series")
+		}
+		for postings.Next() {
+			if _, ok := seen[postings.At()]; !ok {
+				seen[postings.At()] = struct{}{}
+				series = append(series, postings.At())
+			}
+		}
+		if err := postings.Err(); err != nil {
+			return nil, errors.Wrap(err, "select series")
+		}
+	}
+	return series, nil
+}
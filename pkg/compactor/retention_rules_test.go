@@ -0,0 +1,172 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package compactor
+
+import (
+	"context"
+	"path"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/oklog/ulid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/mimir/pkg/storage/bucket"
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
+	"github.com/grafana/mimir/pkg/storage/tsdb/bucketindex"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+func TestBlocksCleaner_ApplyUserRetentionRules(t *testing.T) {
+	const userID = "user-1"
+
+	ctx := context.Background()
+	logger := log.NewNopLogger()
+	bucketClient := block.BucketWithGlobalMarkers(objstore.NewInMemBucket())
+	userBucket := bucket.NewUserBucketClient(userID, bucketClient, nil)
+	cfgProvider := newMockConfigProvider()
+
+	now := time.Now()
+	ts := func(hours int) int64 {
+		return now.Add(time.Duration(hours) * time.Hour).UnixMilli()
+	}
+	extLabels := labels.FromStrings("__compactor_shard_id__", "1_of_2")
+
+	// The debug series of this block are older than the period of their retention rule.
+	mixedBlock := uploadCleanerTestBlock(t, userBucket, []labels.Labels{
+		labels.FromStrings("__name__", "debug_metric", "job", "debug", "instance", "1"),
+		labels.FromStrings("__name__", "debug_metric", "job", "debug", "instance", "2"),
+		labels.FromStrings("__name__", "slo_metric", "job", "slo", "instance", "1"),
+		labels.FromStrings("__name__", "slo_metric", "job", "slo", "instance", "2"),
+	}, ts(-72), ts(-48), extLabels)
+	// All the series of this block are older than the period of their retention rule.
+	debugBlock := uploadCleanerTestBlock(t, userBucket, []labels.Labels{
+		labels.FromStrings("__name__", "debug_metric", "job", "debug", "instance", "1"),
+		labels.FromStrings("__name__", "debug_metric", "job", "debug", "instance", "2"),
+		labels.FromStrings("__name__", "debug_metric", "job", "debug", "instance", "3"),
+	}, ts(-48), ts(-36), extLabels)
+	// This block has no series matching the retention rules older than their period.
+	sloBlock := uploadCleanerTestBlock(t, userBucket, []labels.Labels{
+		labels.FromStrings("__name__", "slo_metric", "job", "slo", "instance", "1"),
+		labels.FromStrings("__name__", "slo_metric", "job", "slo", "instance", "2"),
+		labels.FromStrings("__name__", "slo_metric", "job", "slo", "instance", "3"),
+	}, ts(-36), ts(-30), extLabels)
+	// This block is more recent than the period of the retention rules.
+	recentBlock := uploadCleanerTestBlock(t, userBucket, []labels.Labels{
+		labels.FromStrings("__name__", "debug_metric", "job", "debug", "instance", "1"),
+		labels.FromStrings("__name__", "debug_metric", "job", "debug", "instance", "2"),
+		labels.FromStrings("__name__", "slo_metric", "job", "slo", "instance", "1"),
+	}, ts(-12), ts(-10), extLabels)
+
+	rules := []*validation.RetentionRule{
+		{Match: `{job="debug"}`, Period: model.Duration(24 * time.Hour)},
+		{Match: `{job="slo"}`, Period: model.Duration(30 * 24 * time.Hour)},
+	}
+	for _, rule := range rules {
+		require.NoError(t, rule.Validate())
+	}
+
+	reg := prometheus.NewPedanticRegistry()
+	cleaner := NewBlocksCleaner(BlocksCleanerConfig{DataDir: t.TempDir(), CleanupConcurrency: 1}, bucketClient, func(string) (bool, error) { return true, nil }, cfgProvider, logger, reg)
+
+	idx, _, err := bucketindex.NewUpdater(bucketClient, userID, nil, logger).UpdateIndex(ctx, nil)
+	require.NoError(t, err)
+	cleaner.applyUserRetentionRules(ctx, userID, idx, rules, 0, newBlockChecksBudget(0), userBucket, logger)
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(cleaner.retentionRulesBlocksRewritten))
+	assert.Equal(t, 5.0, testutil.ToFloat64(cleaner.retentionRulesSeriesRemoved))
+	assert.Greater(t, testutil.ToFloat64(cleaner.retentionRulesBytesRemoved), 0.0)
+	assert.Equal(t, 0.0, testutil.ToFloat64(cleaner.retentionRulesFailures))
+
+	// The rewritten blocks are marked for deletion, and excluded from the compaction.
+	for blockID, expectedMarked := range map[ulid.ULID]bool{mixedBlock: true, debugBlock: true, sloBlock: false, recentBlock: false} {
+		for _, marker := range []string{block.DeletionMarkFilename, block.NoCompactMarkFilename} {
+			marked, err := userBucket.Exists(ctx, path.Join(blockID.String(), marker))
+			require.NoError(t, err)
+			assert.Equal(t, expectedMarked, marked, blockID.String(), marker)
+		}
+	}
+
+	// The mixed block has been rewritten without the debug series, keeping its time range, compaction lineage and
+	// external labels.
+	mixedMeta, err := block.DownloadMeta(ctx, logger, userBucket, mixedBlock)
+	require.NoError(t, err)
+
+	idx, _, err = bucketindex.NewUpdater(bucketClient, userID, nil, logger).UpdateIndex(ctx, idx)
+	require.NoError(t, err)
+	require.Len(t, idx.Blocks, 5)
+
+	var newBlock *bucketindex.Block
+	for _, b := range idx.Blocks {
+		switch b.ID {
+		case mixedBlock, debugBlock, recentBlock:
+		case sloBlock:
+			// The retention rules applied to the blocks left untouched are recorded in the bucket index.
+			assert.Equal(t, []string{`{job="debug"}`}, b.RetentionRules)
+		default:
+			newBlock = b
+		}
+	}
+	require.NotNil(t, newBlock)
+
+	dir := t.TempDir()
+	require.NoError(t, block.Download(ctx, logger, userBucket, newBlock.ID, filepath.Join(dir, newBlock.ID.String())))
+	meta, err := block.ReadMetaFromDir(filepath.Join(dir, newBlock.ID.String()))
+	require.NoError(t, err)
+	assert.Equal(t, ts(-72), meta.MinTime)
+	assert.Equal(t, ts(-48), meta.MaxTime)
+	assert.Equal(t, mixedMeta.Compaction.Level, meta.Compaction.Level)
+	assert.Equal(t, mixedMeta.Compaction.Sources, meta.Compaction.Sources)
+	assert.Equal(t, extLabels.Map(), meta.Thanos.Labels)
+	assert.Equal(t, []string{`{job="debug"}`}, meta.Thanos.RetentionRules)
+	assert.Equal(t, []string{`{job="debug"}`}, newBlock.RetentionRules)
+	assert.Equal(t, uint64(2), meta.Stats.NumSeries)
+	assert.Equal(t, []labels.Labels{
+		labels.FromStrings("__name__", "slo_metric", "job", "slo", "instance", "1"),
+		labels.FromStrings("__name__", "slo_metric", "job", "slo", "instance", "2"),
+	}, readRetentionRulesTestBlockSeries(t, filepath.Join(dir, newBlock.ID.String())))
+
+	// The retention rules aren't applied again to the same blocks.
+	cleaner.applyUserRetentionRules(ctx, userID, idx, rules, 0, newBlockChecksBudget(0), userBucket, logger)
+	assert.Equal(t, 2.0, testutil.ToFloat64(cleaner.retentionRulesBlocksRewritten))
+
+	// The retention rules aren't applied again to the rewritten block after a restart, because they're recorded
+	// in its meta, nor to the blocks left untouched, because they're recorded in the bucket index.
+	reg = prometheus.NewPedanticRegistry()
+	cleaner = NewBlocksCleaner(BlocksCleanerConfig{DataDir: t.TempDir(), CleanupConcurrency: 1}, bucketClient, func(string) (bool, error) { return true, nil }, cfgProvider, logger, reg)
+	checks := newBlockChecksBudget(1)
+	cleaner.applyUserRetentionRules(ctx, userID, idx, rules, 0, checks, userBucket, logger)
+	assert.Equal(t, 0.0, testutil.ToFloat64(cleaner.retentionRulesBlocksRewritten))
+	assert.Equal(t, 0.0, testutil.ToFloat64(cleaner.retentionRulesFailures))
+	assert.Equal(t, 1, checks.remaining)
+	marked, err := userBucket.Exists(ctx, path.Join(newBlock.ID.String(), block.NoCompactMarkFilename))
+	require.NoError(t, err)
+	assert.False(t, marked)
+}
+
+func readRetentionRulesTestBlockSeries(t *testing.T, bdir string) []labels.Labels {
+	b, err := tsdb.OpenBlock(log.NewNopLogger(), bdir, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, b.Close()) })
+
+	q, err := tsdb.NewBlockQuerier(b, b.MinTime(), b.MaxTime())
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, q.Close()) })
+
+	var series []labels.Labels
+	set := q.Select(context.Background(), true, nil, labels.MustNewMatcher(labels.MatchRegexp, "__name__", ".+"))
+	for set.Next() {
+		series = append(series, set.At().Labels())
+	}
+	require.NoError(t, set.Err())
+	return series
+}
@@ -0,0 +1,292 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package compactor
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/oklog/ulid"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/prometheus/prometheus/tsdb/chunks"
+	"github.com/prometheus/prometheus/tsdb/index"
+	"github.com/prometheus/prometheus/tsdb/tombstones"
+	"github.com/thanos-io/objstore"
+
+	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
+	"github.com/grafana/mimir/pkg/storage/tsdb/bucketindex"
+	"github.com/grafana/mimir/pkg/util"
+	"github.com/grafana/mimir/pkg/util/math"
+)
+
+// seriesDeletionDirName is the name of the directory, in the compactor data directory, where the blocks
+// rewritten by the series deletion requests are downloaded.
+const seriesDeletionDirName = "series-deletion"
+
+// errSeriesDeletionBlockMarkedForDeletion is returned when the block to rewrite has been marked for deletion since the
+// bucket index has been updated.
+var errSeriesDeletionBlockMarkedForDeletion = errors.New("block marked for deletion")
+
+// writeSeriesDeletionTombstones writes the tombstones of the series matching the series deletion requests into the
+// block stored in the input directory, so that the deleted samples are purged when the block is compacted.
+// Returns whether any tombstone has been written.
+func writeSeriesDeletionTombstones(ctx context.Context, logger log.Logger, bdir string, meta *block.Meta, requests []*mimir_tsdb.SeriesDeletionRequest) (bool, error) {
+	stones, err := seriesDeletionTombstones(ctx, filepath.Join(bdir, block.IndexFilename), meta.MinTime, meta.MaxTime, requests)
+	if err != nil {
+		return false, err
+	}
+	if stones.Total() == 0 {
+		return false, nil
+	}
+
+	if _, err := tombstones.WriteFile(logger, bdir, stones); err != nil {
+		return false, errors.Wrap(err, "write tombstones")
+	}
+	return true, nil
+}
+
+// seriesDeletionTombstones returns the tombstones of the series of the index matching the series deletion requests,
+// for the block with the input time range (max time exclusive). Only the series with chunks overlapping the deleted
+// time range get tombstones, so that a block whose deleted samples have already been purged gets none.
+func seriesDeletionTombstones(ctx context.Context, indexPath string, blockMinT, blockMaxT int64, requests []*mimir_tsdb.SeriesDeletionRequest) (*tombstones.MemTombstones, error) {
+	stones := tombstones.NewMemTombstones()
+
+	// The block max time is exclusive, while the series deletion requests time range is inclusive.
+	minT, maxT := blockMinT, blockMaxT-1
+
+	var overlapping []*mimir_tsdb.SeriesDeletionRequest
+	for _, req := range requests {
+		if req.Overlaps(minT, maxT) {
+			overlapping = append(overlapping, req)
+		}
+	}
+	if len(overlapping) == 0 {
+		return stones, nil
+	}
+
+	ir, err := index.NewFileReader(indexPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "open index")
+	}
+	defer ir.Close()
+
+	var (
+		builder labels.ScratchBuilder
+		chks    []chunks.Meta
+	)
+	for _, req := range overlapping {
+		matchersSets, err := req.Matchers()
+		if err != nil {
+			return nil, err
+		}
+
+		interval := tombstones.Interval{Mint: math.Max(req.StartTime, minT), Maxt: math.Min(req.EndTime, maxT)}
+		for _, matchers := range matchersSets {
+			postings, err := tsdb.PostingsForMatchers(ctx, ir, matchers...)
+			if err != nil {
+				return nil, errors.Wrap(err, "select series")
+			}
+			for postings.Next() {
+				if err := ir.Series(postings.At(), &builder, &chks); err != nil {
+					return nil, errors.Wrap(err, "read series")
+				}
+				for _, chk := range chks {
+					if chk.OverlapsClosedInterval(interval.Mint, interval.Maxt) {
+						stones.AddInterval(postings.At(), interval)
+						break
+					}
+				}
+			}
+			if err := postings.Err(); err != nil {
+				return nil, errors.Wrap(err, "select series")
+			}
+		}
+	}
+
+	return stones, nil
+}
+
+// applyUserSeriesDeletionRequests rewrites the blocks overlapping the pending series deletion requests, purging the
+// deleted samples, and marks the requests as processed once the deleted samples have been purged from all the blocks.
+// Because the ingesters keep uploading the samples of the time range of a request for a while, a request is processed
+// only once its time range, bounded by its creation time, is older than the series deletion delay.
+func (c *BlocksCleaner) applyUserSeriesDeletionRequests(ctx context.Context, userID string, idx *bucketindex.Index, checks *blockChecksBudget, userBucket objstore.Bucket, userLogger log.Logger) {
+	var pending []*mimir_tsdb.SeriesDeletionRequest
+	for _, req := range idx.SeriesDeletionRequests {
+		if !req.Processed() {
+			pending = append(pending, req)
+		}
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	marked := make(map[ulid.ULID]struct{}, len(idx.BlockDeletionMarks))
+	for _, d := range idx.BlockDeletionMarks {
+		marked[d.ID] = struct{}{}
+	}
+
+	// The requests which failed to be applied to some blocks aren't processed.
+	failed := map[string]struct{}{}
+
+	for _, b := range idx.Blocks {
+		if ctx.Err() != nil {
+			return
+		}
+		if _, isMarked := marked[b.ID]; isMarked {
+			continue
+		}
+
+		var (
+			blockRequests []*mimir_tsdb.SeriesDeletionRequest
+			requestIDs    []string
+		)
+		for _, req := range pending {
+			if req.Overlaps(b.MinTime, b.MaxTime-1) {
+				blockRequests = append(blockRequests, req)
+				requestIDs = append(requestIDs, req.RequestID)
+			}
+		}
+		if len(blockRequests) == 0 {
+			continue
+		}
+
+		// The requests applying to a block only change when a request is added. The requests applied to a block are
+		// recorded in its bucket index entry, and in its meta when it's rewritten.
+		sort.Strings(requestIDs)
+		if containsAll(b.SeriesDeletionRequests, requestIDs) {
+			continue
+		}
+		if !checks.take() {
+			// The requests are applied in the next cycles.
+			for _, id := range requestIDs {
+				failed[id] = struct{}{}
+			}
+			continue
+		}
+
+		if err := c.applySeriesDeletionRequestsToBlock(ctx, userID, b, blockRequests, requestIDs, userBucket, userLogger); err != nil {
+			// The requests are applied again in the next cycle, to this block or to the block replacing it.
+			if !errors.Is(err, errSeriesDeletionBlockMarkedForDeletion) {
+				c.seriesDeletionFailures.Inc()
+				level.Warn(userLogger).Log("msg", "failed to apply series deletion requests to block", "block", b.ID, "err", err)
+			}
+			for _, id := range requestIDs {
+				failed[id] = struct{}{}
+			}
+			continue
+		}
+
+		b.SeriesDeletionRequests = mergeApplied(b.SeriesDeletionRequests, requestIDs)
+	}
+
+	now := time.Now()
+	for _, req := range pending {
+		if _, isFailed := failed[req.RequestID]; isFailed {
+			continue
+		}
+		if min(req.EndTime, time.Unix(int64(req.CreatedAt), 0).UnixMilli()) >= now.Add(-c.cfg.SeriesDeletionDelay).UnixMilli() {
+			continue
+		}
+
+		if err := c.markSeriesDeletionRequestProcessed(ctx, req, now, userBucket); err != nil {
+			level.Warn(userLogger).Log("msg", "failed to mark series deletion request as processed", "request_id", req.RequestID, "err", err)
+			continue
+		}
+
+		c.seriesDeletionRequestsProcessed.Inc()
+		level.Info(userLogger).Log("msg", "series deletion request processed", "request_id", req.RequestID)
+	}
+}
+
+// markSeriesDeletionRequestProcessed uploads the request with the processed time, unless the request has been
+// cancelled in the meanwhile.
+func (c *BlocksCleaner) markSeriesDeletionRequestProcessed(ctx context.Context, req *mimir_tsdb.SeriesDeletionRequest, now time.Time, userBucket objstore.Bucket) error {
+	exists, err := userBucket.Exists(ctx, mimir_tsdb.SeriesDeletionRequestFilepath(req.RequestID))
+	if err != nil || !exists {
+		return err
+	}
+
+	processed := *req
+	processed.ProcessedAt = util.UnixSecondsFromTime(now)
+	return mimir_tsdb.WriteSeriesDeletionRequest(ctx, userBucket, &processed)
+}
+
+// applySeriesDeletionRequestsToBlock rewrites the block without the samples deleted by the series deletion requests,
+// uploads the new block and marks the original block for deletion. The block is left untouched if it doesn't contain
+// any deleted sample. The IDs of the requests are stored in the meta of the new block.
+func (c *BlocksCleaner) applySeriesDeletionRequestsToBlock(ctx context.Context, userID string, b *bucketindex.Block, requests []*mimir_tsdb.SeriesDeletionRequest, requestIDs []string, userBucket objstore.Bucket, userLogger log.Logger) error {
+	blockLogger := log.With(userLogger, "block", b.ID)
+	dir := filepath.Join(c.cfg.DataDir, seriesDeletionDirName, userID)
+	bdir := filepath.Join(dir, b.ID.String())
+
+	if err := os.RemoveAll(dir); err != nil {
+		return errors.Wrap(err, "clean up the series deletion directory")
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			level.Warn(blockLogger).Log("msg", "failed to remove the series deletion directory", "dir", dir, "err", err)
+		}
+	}()
+
+	// The block may have just been rewritten by the retention rules, in which case the new block is checked in the
+	// next cleanup.
+	marked, err := userBucket.Exists(ctx, path.Join(b.ID.String(), block.DeletionMarkFilename))
+	if err != nil {
+		return errors.Wrap(err, "check deletion mark")
+	}
+	if marked {
+		return errSeriesDeletionBlockMarkedForDeletion
+	}
+
+	// The index is enough to find whether the block contains deleted samples.
+	if err := os.MkdirAll(bdir, 0o750); err != nil {
+		return errors.Wrap(err, "create block directory")
+	}
+	if err := objstore.DownloadFile(ctx, blockLogger, userBucket, path.Join(b.ID.String(), block.IndexFilename), filepath.Join(bdir, block.IndexFilename)); err != nil {
+		return errors.Wrap(err, "download index")
+	}
+	stones, err := seriesDeletionTombstones(ctx, filepath.Join(bdir, block.IndexFilename), b.MinTime, b.MaxTime, requests)
+	if err != nil {
+		return err
+	}
+	if stones.Total() == 0 {
+		level.Debug(blockLogger).Log("msg", "block contains no samples deleted by the series deletion requests")
+		return nil
+	}
+
+	level.Info(blockLogger).Log("msg", "rewriting block to purge the samples deleted by the series deletion requests", "tombstones", stones.Total())
+
+	_, err = c.rewriteBlockWithTombstones(ctx, blockLogger, userBucket, dir, b.ID, stones, blockRewrite{
+		description:        "series deletion requests",
+		noCompactReason:    block.SeriesDeletionNoCompactReason,
+		markedForNoCompact: c.seriesDeletionBlocksMarkedForNoCompact,
+		// The retention rules already applied to the block still apply to the new block.
+		retentionRules:         b.RetentionRules,
+		seriesDeletionRequests: mergeApplied(b.SeriesDeletionRequests, requestIDs),
+		filterExemplars: func(exemplars []exemplar.QueryResult) []exemplar.QueryResult {
+			for _, req := range requests {
+				// The matchers have already been parsed successfully to find the deleted samples.
+				matchersSets, _ := req.Matchers()
+				exemplars = deleteExemplars(exemplars, matchersSets, req.StartTime, req.EndTime)
+			}
+			return exemplars
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	c.seriesDeletionBlocksRewritten.Inc()
+	return nil
+}
@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package compactor
+
+import (
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/tenant"
+
+	"github.com/grafana/mimir/pkg/storage/bucket"
+	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
+	"github.com/grafana/mimir/pkg/util"
+)
+
+type SeriesDeletionRequestsResponse struct {
+	Requests []*mimir_tsdb.SeriesDeletionRequest `json:"requests"`
+}
+
+// DeleteSeries creates a series deletion request for the tenant. The series matching any of the match[]
+// selectors are hidden from queries within the start and end time range, and their samples are purged
+// from the blocks rewritten by the compactor.
+func (c *MultitenantCompactor) DeleteSeries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := tenant.TenantID(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	startTime, err := util.ParseTimeParam(r, "start", math.MinInt64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	endTime, err := util.ParseTimeParam(r, "end", now.UnixMilli())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	req, err := mimir_tsdb.NewSeriesDeletionRequest(r.Form["match[]"], startTime, endTime, now)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	userBucket := bucket.NewUserBucketClient(userID, c.bucketClient, c.cfgProvider)
+	if err := mimir_tsdb.WriteSeriesDeletionRequest(ctx, userBucket, req); err != nil {
+		level.Error(c.logger).Log("msg", "failed to write series deletion request", "user", userID, "err", err)
+
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	level.Info(c.logger).Log("msg", "series deletion request created", "user", userID, "request_id", req.RequestID, "selectors", req.Selectors, "start", req.StartTime, "end", req.EndTime)
+
+	util.WriteJSONResponse(w, req)
+}
+
+// ListSeriesDeletionRequests returns the series deletion requests of the tenant.
+func (c *MultitenantCompactor) ListSeriesDeletionRequests(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := tenant.TenantID(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	userBucket := bucket.NewUserBucketClient(userID, c.bucketClient, c.cfgProvider)
+	requests, err := mimir_tsdb.ListSeriesDeletionRequests(ctx, userBucket)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if requests == nil {
+		requests = []*mimir_tsdb.SeriesDeletionRequest{}
+	}
+	util.WriteJSONResponse(w, SeriesDeletionRequestsResponse{Requests: requests})
+}
+
+// CancelSeriesDeletionRequest removes a series deletion request of the tenant. The samples already
+// purged by the compactor are not restored.
+func (c *MultitenantCompactor) CancelSeriesDeletionRequest(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := tenant.TenantID(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	requestID := r.FormValue("request_id")
+	if requestID == "" {
+		http.Error(w, "missing request_id parameter", http.StatusBadRequest)
+		return
+	}
+
+	userBucket := bucket.NewUserBucketClient(userID, c.bucketClient, c.cfgProvider)
+	found, err := mimir_tsdb.DeleteSeriesDeletionRequest(ctx, userBucket, requestID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		http.Error(w, "series deletion request not found", http.StatusNotFound)
+		return
+	}
+
+	level.Info(c.logger).Log("msg", "series deletion request cancelled", "user", userID, "request_id", requestID)
+
+	w.WriteHeader(http.StatusOK)
+}
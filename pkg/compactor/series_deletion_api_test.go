@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package compactor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/mimir/pkg/storage/tsdb"
+)
+
+func TestSeriesDeletionAPI(t *testing.T) {
+	bkt := objstore.NewInMemBucket()
+	cfg := prepareConfig(t)
+	c, _, _, _, _ := prepare(t, cfg, bkt)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), c))
+	t.Cleanup(stopServiceFn(t, c))
+
+	ctx := user.InjectOrgID(context.Background(), "fake")
+
+	deleteSeries := func(ctx context.Context, form url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/compactor/delete_series", strings.NewReader(form.Encode())).WithContext(ctx)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		resp := httptest.NewRecorder()
+		c.DeleteSeries(resp, req)
+		return resp
+	}
+
+	listRequests := func() []*tsdb.SeriesDeletionRequest {
+		resp := httptest.NewRecorder()
+		c.ListSeriesDeletionRequests(resp, httptest.NewRequest(http.MethodGet, "/compactor/delete_series_requests", nil).WithContext(ctx))
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		res := SeriesDeletionRequestsResponse{}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &res))
+		return res.Requests
+	}
+
+	cancelRequest := func(requestID string) int {
+		resp := httptest.NewRecorder()
+		c.CancelSeriesDeletionRequest(resp, httptest.NewRequest(http.MethodDelete, "/compactor/delete_series_requests?request_id="+requestID, nil).WithContext(ctx))
+		return resp.Code
+	}
+
+	t.Run("should fail without tenant", func(t *testing.T) {
+		resp := deleteSeries(context.Background(), url.Values{"match[]": {"up"}})
+		require.Equal(t, http.StatusUnauthorized, resp.Code)
+	})
+
+	t.Run("should fail on invalid requests", func(t *testing.T) {
+		for _, form := range []url.Values{
+			{},
+			{"match[]": {"{job="}},
+			{"match[]": {"up"}, "start": {"invalid"}},
+			{"match[]": {"up"}, "start": {"20"}, "end": {"10"}},
+		} {
+			resp := deleteSeries(ctx, form)
+			assert.Equal(t, http.StatusBadRequest, resp.Code, form.Encode())
+		}
+
+		assert.Empty(t, listRequests())
+	})
+
+	t.Run("should create, list and cancel series deletion requests", func(t *testing.T) {
+		resp := deleteSeries(ctx, url.Values{"match[]": {`{job="test"}`, "up"}, "start": {"10"}, "end": {"20"}})
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		created := &tsdb.SeriesDeletionRequest{}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), created))
+		assert.NotEmpty(t, created.RequestID)
+		assert.Equal(t, []string{`{job="test"}`, "up"}, created.Selectors)
+		assert.Equal(t, int64(10000), created.StartTime)
+		assert.Equal(t, int64(20000), created.EndTime)
+		assert.NotNil(t, bkt.Objects()["fake/"+tsdb.SeriesDeletionRequestFilepath(created.RequestID)])
+
+		assert.Equal(t, []*tsdb.SeriesDeletionRequest{created}, listRequests())
+
+		assert.Equal(t, http.StatusOK, cancelRequest(created.RequestID))
+		assert.Equal(t, http.StatusNotFound, cancelRequest(created.RequestID))
+		assert.Empty(t, listRequests())
+	})
+}
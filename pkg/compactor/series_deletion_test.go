@@ -0,0 +1,316 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package compactor
+
+import (
+	"context"
+	"path"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/oklog/ulid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/prometheus/prometheus/tsdb/chunkenc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/mimir/pkg/storage/bucket"
+	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
+	"github.com/grafana/mimir/pkg/storage/tsdb/bucketindex"
+)
+
+func TestWriteSeriesDeletionTombstones(t *testing.T) {
+	ctx := context.Background()
+	logger := log.NewNopLogger()
+	dir := t.TempDir()
+
+	series := []labels.Labels{
+		labels.FromStrings("series_id", "1", "job", "deleted"),
+		labels.FromStrings("series_id", "2", "job", "partially-deleted"),
+		labels.FromStrings("series_id", "3", "job", "kept"),
+	}
+	blockID, err := block.CreateBlock(ctx, dir, series, 100, 0, 1000, labels.EmptyLabels())
+	require.NoError(t, err)
+
+	bdir := filepath.Join(dir, blockID.String())
+	meta, err := block.ReadMetaFromDir(bdir)
+	require.NoError(t, err)
+
+	t.Run("should not write tombstones if no request overlaps with the block", func(t *testing.T) {
+		req, err := mimir_tsdb.NewSeriesDeletionRequest([]string{`{job="deleted"}`}, 2000, 3000, time.Now())
+		require.NoError(t, err)
+
+		written, err := writeSeriesDeletionTombstones(ctx, logger, bdir, meta, []*mimir_tsdb.SeriesDeletionRequest{req})
+		require.NoError(t, err)
+		assert.False(t, written)
+	})
+
+	t.Run("should not write tombstones if no series matches the requests", func(t *testing.T) {
+		req, err := mimir_tsdb.NewSeriesDeletionRequest([]string{`{job="unknown"}`}, 0, 1000, time.Now())
+		require.NoError(t, err)
+
+		written, err := writeSeriesDeletionTombstones(ctx, logger, bdir, meta, []*mimir_tsdb.SeriesDeletionRequest{req})
+		require.NoError(t, err)
+		assert.False(t, written)
+	})
+
+	t.Run("should purge the samples of the matching series once the block is compacted", func(t *testing.T) {
+		fullReq, err := mimir_tsdb.NewSeriesDeletionRequest([]string{`{job="deleted"}`}, 0, 1000, time.Now())
+		require.NoError(t, err)
+		partialReq, err := mimir_tsdb.NewSeriesDeletionRequest([]string{`{job="partially-deleted"}`}, 500, 2000, time.Now())
+		require.NoError(t, err)
+
+		written, err := writeSeriesDeletionTombstones(ctx, logger, bdir, meta, []*mimir_tsdb.SeriesDeletionRequest{fullReq, partialReq})
+		require.NoError(t, err)
+		assert.True(t, written)
+
+		comp, err := tsdb.NewLeveledCompactor(ctx, nil, logger, []int64{1000}, nil, nil)
+		require.NoError(t, err)
+
+		compactedID, err := comp.Compact(dir, []string{bdir}, nil)
+		require.NoError(t, err)
+
+		compacted, err := tsdb.OpenBlock(logger, filepath.Join(dir, compactedID.String()), nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { require.NoError(t, compacted.Close()) })
+
+		q, err := tsdb.NewBlockQuerier(compacted, 0, 1000)
+		require.NoError(t, err)
+		t.Cleanup(func() { require.NoError(t, q.Close()) })
+
+		actual := map[string][2]int64{}
+		set := q.Select(ctx, true, nil, labels.MustNewMatcher(labels.MatchRegexp, "job", ".+"))
+		var it chunkenc.Iterator
+		for set.Next() {
+			it = set.At().Iterator(it)
+
+			minT, maxT := int64(-1), int64(-1)
+			for it.Next() != chunkenc.ValNone {
+				if minT < 0 {
+					minT = it.AtT()
+				}
+				maxT = it.AtT()
+			}
+			require.NoError(t, it.Err())
+			actual[set.At().Labels().Get("job")] = [2]int64{minT, maxT}
+		}
+		require.NoError(t, set.Err())
+
+		require.Len(t, actual, 2)
+		assert.NotContains(t, actual, "deleted")
+		assert.Less(t, actual["partially-deleted"][1], int64(500))
+		assert.GreaterOrEqual(t, actual["kept"][1], int64(500))
+	})
+}
+
+func TestBlocksCleaner_ApplyUserSeriesDeletionRequests(t *testing.T) {
+	const userID = "user-1"
+
+	ctx := context.Background()
+	logger := log.NewNopLogger()
+	bucketClient := block.BucketWithGlobalMarkers(objstore.NewInMemBucket())
+	userBucket := bucket.NewUserBucketClient(userID, bucketClient, nil)
+	cfgProvider := newMockConfigProvider()
+
+	now := time.Now()
+	ts := func(hours int) int64 {
+		return now.Add(time.Duration(hours) * time.Hour).UnixMilli()
+	}
+	series := []labels.Labels{
+		labels.FromStrings("__name__", "metric", "job", "pii", "instance", "1"),
+		labels.FromStrings("__name__", "metric", "job", "pii", "instance", "2"),
+		labels.FromStrings("__name__", "metric", "job", "kept", "instance", "1"),
+	}
+
+	// This block is fully compacted, so it's never compacted again.
+	oldBlock := uploadCleanerTestBlock(t, userBucket, series, ts(-48), ts(-24), labels.EmptyLabels())
+	recentBlock := uploadCleanerTestBlock(t, userBucket, series, ts(-4), ts(-2), labels.EmptyLabels())
+	untouchedBlock := uploadCleanerTestBlock(t, userBucket, series, ts(-20), ts(-10), labels.EmptyLabels())
+	// This block has no series matching the requests.
+	keptBlock := uploadCleanerTestBlock(t, userBucket, []labels.Labels{
+		labels.FromStrings("__name__", "metric", "job", "kept", "instance", "2"),
+		labels.FromStrings("__name__", "metric", "job", "kept", "instance", "3"),
+		labels.FromStrings("__name__", "metric", "job", "kept", "instance", "4"),
+	}, ts(-3), ts(-2), labels.EmptyLabels())
+
+	// The samples of this request are expected to be in the blocks.
+	oldReq, err := mimir_tsdb.NewSeriesDeletionRequest([]string{`{job="pii"}`}, ts(-40), ts(-30), now)
+	require.NoError(t, err)
+	// The samples of this request may still be uploaded by the ingesters.
+	recentReq, err := mimir_tsdb.NewSeriesDeletionRequest([]string{`{job="pii"}`}, ts(-3), ts(1), now)
+	require.NoError(t, err)
+	for _, req := range []*mimir_tsdb.SeriesDeletionRequest{oldReq, recentReq} {
+		require.NoError(t, mimir_tsdb.WriteSeriesDeletionRequest(ctx, userBucket, req))
+	}
+
+	reg := prometheus.NewPedanticRegistry()
+	cfg := BlocksCleanerConfig{DataDir: t.TempDir(), CleanupConcurrency: 1, SeriesDeletionDelay: 13 * time.Hour}
+	cleaner := NewBlocksCleaner(cfg, bucketClient, func(string) (bool, error) { return true, nil }, cfgProvider, logger, reg)
+
+	idx, _, err := bucketindex.NewUpdater(bucketClient, userID, nil, logger).UpdateIndex(ctx, nil)
+	require.NoError(t, err)
+	cleaner.applyUserSeriesDeletionRequests(ctx, userID, idx, newBlockChecksBudget(0), userBucket, logger)
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(cleaner.seriesDeletionBlocksRewritten))
+	assert.Equal(t, 0.0, testutil.ToFloat64(cleaner.seriesDeletionFailures))
+	assert.Equal(t, 1.0, testutil.ToFloat64(cleaner.seriesDeletionRequestsProcessed))
+
+	// The rewritten blocks are marked for deletion, and excluded from the compaction.
+	for blockID, expectedMarked := range map[ulid.ULID]bool{oldBlock: true, recentBlock: true, untouchedBlock: false, keptBlock: false} {
+		for _, marker := range []string{block.DeletionMarkFilename, block.NoCompactMarkFilename} {
+			marked, err := userBucket.Exists(ctx, path.Join(blockID.String(), marker))
+			require.NoError(t, err)
+			assert.Equal(t, expectedMarked, marked, blockID.String(), marker)
+		}
+	}
+
+	// Only the request whose samples are expected to be in the blocks is processed.
+	requests, err := mimir_tsdb.ListSeriesDeletionRequests(ctx, userBucket)
+	require.NoError(t, err)
+	require.Len(t, requests, 2)
+	for _, req := range requests {
+		assert.Equal(t, req.RequestID == oldReq.RequestID, req.Processed(), req.RequestID)
+	}
+
+	// The deleted samples have been purged from the new blocks.
+	idx, _, err = bucketindex.NewUpdater(bucketClient, userID, nil, logger).UpdateIndex(ctx, idx)
+	require.NoError(t, err)
+	require.Len(t, idx.Blocks, 6)
+
+	dir := t.TempDir()
+	for _, b := range idx.Blocks {
+		if b.ID == keptBlock {
+			// The requests applied to the blocks left untouched are recorded in the bucket index.
+			assert.Equal(t, []string{recentReq.RequestID}, b.SeriesDeletionRequests)
+		}
+		if b.ID == oldBlock || b.ID == recentBlock || b.ID == untouchedBlock || b.ID == keptBlock {
+			continue
+		}
+
+		bdir := filepath.Join(dir, b.ID.String())
+		require.NoError(t, block.Download(ctx, logger, userBucket, b.ID, bdir))
+		deletedMinT, deletedMaxT := ts(-40), ts(-30)
+		if b.MinTime == ts(-4) {
+			deletedMinT, deletedMaxT = ts(-3), ts(1)
+		}
+
+		meta, err := block.ReadMetaFromDir(bdir)
+		require.NoError(t, err)
+		assert.Equal(t, b.SeriesDeletionRequests, meta.Thanos.SeriesDeletionRequests)
+		assert.Len(t, meta.Thanos.SeriesDeletionRequests, 1)
+
+		samples := readSeriesDeletionTestBlockSamples(t, bdir)
+		require.Len(t, samples, len(series))
+		for _, lbls := range series {
+			timestamps := samples[lbls.String()]
+			require.NotEmpty(t, timestamps)
+
+			deleted := 0
+			for _, ts := range timestamps {
+				if ts >= deletedMinT && ts <= deletedMaxT {
+					deleted++
+				}
+			}
+			if lbls.Get("job") == "pii" {
+				assert.Zero(t, deleted, lbls.String())
+			} else {
+				assert.NotZero(t, deleted, lbls.String())
+			}
+		}
+	}
+
+	// The requests aren't applied again to the same blocks.
+	cleaner.applyUserSeriesDeletionRequests(ctx, userID, idx, newBlockChecksBudget(0), userBucket, logger)
+	assert.Equal(t, 2.0, testutil.ToFloat64(cleaner.seriesDeletionBlocksRewritten))
+
+	// The pending request isn't applied again after a restart, neither to the rewritten block, because it's
+	// recorded in its meta, nor to the block left untouched, because it's recorded in the bucket index.
+	reg = prometheus.NewPedanticRegistry()
+	cleaner = NewBlocksCleaner(cfg, bucketClient, func(string) (bool, error) { return true, nil }, cfgProvider, logger, reg)
+	checks := newBlockChecksBudget(1)
+	cleaner.applyUserSeriesDeletionRequests(ctx, userID, idx, checks, userBucket, logger)
+	assert.Equal(t, 1, checks.remaining)
+	assert.Equal(t, 0.0, testutil.ToFloat64(cleaner.seriesDeletionBlocksRewritten))
+	assert.Equal(t, 0.0, testutil.ToFloat64(cleaner.seriesDeletionFailures))
+	assert.Equal(t, 0.0, testutil.ToFloat64(cleaner.seriesDeletionRequestsProcessed))
+}
+
+func TestBlocksCleaner_ApplyUserSeriesDeletionRequests_MaxCheckedBlocks(t *testing.T) {
+	const userID = "user-1"
+
+	ctx := context.Background()
+	logger := log.NewNopLogger()
+	bucketClient := block.BucketWithGlobalMarkers(objstore.NewInMemBucket())
+	userBucket := bucket.NewUserBucketClient(userID, bucketClient, nil)
+
+	now := time.Now()
+	ts := func(hours int) int64 {
+		return now.Add(time.Duration(hours) * time.Hour).UnixMilli()
+	}
+	series := []labels.Labels{
+		labels.FromStrings("__name__", "metric", "job", "pii", "instance", "1"),
+		labels.FromStrings("__name__", "metric", "job", "pii", "instance", "2"),
+		labels.FromStrings("__name__", "metric", "job", "kept", "instance", "1"),
+	}
+	uploadCleanerTestBlock(t, userBucket, series, ts(-48), ts(-36), labels.EmptyLabels())
+	uploadCleanerTestBlock(t, userBucket, series, ts(-36), ts(-24), labels.EmptyLabels())
+
+	req, err := mimir_tsdb.NewSeriesDeletionRequest([]string{`{job="pii"}`}, ts(-48), ts(-24), now)
+	require.NoError(t, err)
+	require.NoError(t, mimir_tsdb.WriteSeriesDeletionRequest(ctx, userBucket, req))
+
+	cfg := BlocksCleanerConfig{DataDir: t.TempDir(), CleanupConcurrency: 1, SeriesDeletionDelay: 13 * time.Hour}
+	cleaner := NewBlocksCleaner(cfg, bucketClient, func(string) (bool, error) { return true, nil }, newMockConfigProvider(), logger, prometheus.NewPedanticRegistry())
+
+	// Each cleanup checks a single block, and the request is processed once it has been applied to both blocks.
+	var idx *bucketindex.Index
+	for i := 1; i <= 2; i++ {
+		idx, _, err = bucketindex.NewUpdater(bucketClient, userID, nil, logger).UpdateIndex(ctx, idx)
+		require.NoError(t, err)
+		cleaner.applyUserSeriesDeletionRequests(ctx, userID, idx, newBlockChecksBudget(1), userBucket, logger)
+		assert.Equal(t, float64(i), testutil.ToFloat64(cleaner.seriesDeletionBlocksRewritten))
+		assert.Equal(t, float64(i-1), testutil.ToFloat64(cleaner.seriesDeletionRequestsProcessed))
+	}
+}
+
+// uploadCleanerTestBlock creates a block with the series and uploads it to the bucket.
+func uploadCleanerTestBlock(t *testing.T, bkt objstore.Bucket, series []labels.Labels, minT, maxT int64, extLabels labels.Labels) ulid.ULID {
+	dir := t.TempDir()
+	blockID, err := block.CreateBlock(context.Background(), dir, series, 10, minT, maxT, extLabels)
+	require.NoError(t, err)
+	require.NoError(t, block.Upload(context.Background(), log.NewNopLogger(), bkt, filepath.Join(dir, blockID.String()), nil))
+	return blockID
+}
+
+// readSeriesDeletionTestBlockSamples returns the timestamps of the samples of the block, by series.
+func readSeriesDeletionTestBlockSamples(t *testing.T, bdir string) map[string][]int64 {
+	b, err := tsdb.OpenBlock(log.NewNopLogger(), bdir, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, b.Close()) })
+
+	q, err := tsdb.NewBlockQuerier(b, b.MinTime(), b.MaxTime())
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, q.Close()) })
+
+	samples := map[string][]int64{}
+	set := q.Select(context.Background(), true, nil, labels.MustNewMatcher(labels.MatchRegexp, "__name__", ".+"))
+	var it chunkenc.Iterator
+	for set.Next() {
+		it = set.At().Iterator(it)
+		var timestamps []int64
+		for it.Next() != chunkenc.ValNone {
+			timestamps = append(timestamps, it.AtT())
+		}
+		require.NoError(t, it.Err())
+		samples[set.At().Labels().String()] = timestamps
+	}
+	require.NoError(t, set.Err())
+	return samples
+}
@@ -3,10 +3,13 @@
 package continuoustest
 
 import (
+	"bytes"
 	"context"
 	"flag"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/go-kit/log"
@@ -15,7 +18,9 @@ import (
 	"github.com/prometheus/client_golang/api"
 	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/rulefmt"
 	"github.com/prometheus/prometheus/prompb"
+	"gopkg.in/yaml.v3"
 
 	"github.com/grafana/mimir/pkg/util/instrumentation"
 	util_math "github.com/grafana/mimir/pkg/util/math"
@@ -31,11 +36,35 @@ type MimirClient interface {
 	// an error. The error is always returned if request was not successful (eg. received a 4xx or 5xx error).
 	WriteSeries(ctx context.Context, series []prompb.TimeSeries) (statusCode int, err error)
 
+	// WriteSeriesWithMetadata is like WriteSeries, but also writes the input metric metadata.
+	WriteSeriesWithMetadata(ctx context.Context, series []prompb.TimeSeries, metadata []prompb.MetricMetadata) (statusCode int, err error)
+
 	// QueryRange performs a range query.
 	QueryRange(ctx context.Context, query string, start, end time.Time, step time.Duration, options ...RequestOption) (model.Matrix, error)
 
 	// Query performs an instant query.
 	Query(ctx context.Context, query string, ts time.Time, options ...RequestOption) (model.Vector, error)
+
+	// QueryExemplars queries the exemplars of the series selected by the input query in the given time range.
+	QueryExemplars(ctx context.Context, query string, start, end time.Time, options ...RequestOption) ([]v1.ExemplarQueryResult, error)
+
+	// LabelNames returns the label names of the series matching the input selectors in the given time range.
+	LabelNames(ctx context.Context, matches []string, start, end time.Time, options ...RequestOption) ([]string, error)
+
+	// LabelValues returns the values of the input label of the series matching the input selectors in the given time range.
+	LabelValues(ctx context.Context, label string, matches []string, start, end time.Time, options ...RequestOption) (model.LabelValues, error)
+
+	// Series returns the series matching the input selectors in the given time range.
+	Series(ctx context.Context, matches []string, start, end time.Time, options ...RequestOption) ([]model.LabelSet, error)
+
+	// Metadata returns the metadata of the input metric, or of all metrics if the input metric is empty.
+	Metadata(ctx context.Context, metric string, options ...RequestOption) (map[string][]v1.Metadata, error)
+
+	// SetRuleGroup creates or replaces the input rule group in the given namespace.
+	SetRuleGroup(ctx context.Context, namespace string, group rulefmt.RuleGroup) error
+
+	// SetAlertmanagerConfig replaces the Alertmanager configuration and templates.
+	SetAlertmanagerConfig(ctx context.Context, config string, templates map[string]string) error
 }
 
 type ClientConfig struct {
@@ -51,6 +80,9 @@ type ClientConfig struct {
 
 	ReadBaseEndpoint flagext.URLValue
 	ReadTimeout      time.Duration
+
+	RulerBaseEndpoint        flagext.URLValue
+	AlertmanagerBaseEndpoint flagext.URLValue
 }
 
 func (cfg *ClientConfig) RegisterFlags(f *flag.FlagSet) {
@@ -67,11 +99,15 @@ func (cfg *ClientConfig) RegisterFlags(f *flag.FlagSet) {
 	f.Var(&cfg.ReadBaseEndpoint, "tests.read-endpoint", "The base endpoint on the read path. The URL should have no trailing slash. The specific API path is appended by the tool to the URL, for example /api/v1/query_range for range query API, so the configured URL must not include it.")
 	f.DurationVar(&cfg.ReadTimeout, "tests.read-timeout", 60*time.Second, "The timeout for a single read request.")
 
+	f.Var(&cfg.RulerBaseEndpoint, "tests.ruler-endpoint", "The base endpoint of the ruler configuration API. The URL should have no trailing slash. The specific API path is appended by the tool to the URL, for example /prometheus/config/v1/rules, so the configured URL must not include it. Required only by tests configuring rules.")
+	f.Var(&cfg.AlertmanagerBaseEndpoint, "tests.alertmanager-endpoint", "The base endpoint of the Alertmanager configuration API. The URL should have no trailing slash. The specific API path is appended by the tool to the URL, for example /api/v1/alerts, so the configured URL must not include it. Required only by tests configuring the Alertmanager.")
+
 }
 
 type Client struct {
 	writeClient clientWriter
 	readClient  v1.API
+	httpClient  *http.Client
 	cfg         ClientConfig
 	logger      log.Logger
 }
@@ -142,6 +178,7 @@ func NewClient(cfg ClientConfig, logger log.Logger) (*Client, error) {
 	return &Client{
 		writeClient: writeClient,
 		readClient:  v1.NewAPI(readClient),
+		httpClient:  &http.Client{Transport: rt},
 		cfg:         cfg,
 		logger:      logger,
 	}, nil
@@ -197,18 +234,137 @@ func (c *Client) Query(ctx context.Context, query string, ts time.Time, options
 	return vector, nil
 }
 
+// QueryExemplars implements MimirClient.
+func (c *Client) QueryExemplars(ctx context.Context, query string, start, end time.Time, options ...RequestOption) ([]v1.ExemplarQueryResult, error) {
+	ctx = contextWithRequestOptions(ctx, options...)
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.ReadTimeout)
+	defer cancel()
+
+	return c.readClient.QueryExemplars(ctx, query, start, end)
+}
+
+// LabelNames implements MimirClient.
+func (c *Client) LabelNames(ctx context.Context, matches []string, start, end time.Time, options ...RequestOption) ([]string, error) {
+	ctx = contextWithRequestOptions(ctx, options...)
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.ReadTimeout)
+	defer cancel()
+
+	names, _, err := c.readClient.LabelNames(ctx, matches, start, end)
+	return names, err
+}
+
+// LabelValues implements MimirClient.
+func (c *Client) LabelValues(ctx context.Context, label string, matches []string, start, end time.Time, options ...RequestOption) (model.LabelValues, error) {
+	ctx = contextWithRequestOptions(ctx, options...)
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.ReadTimeout)
+	defer cancel()
+
+	values, _, err := c.readClient.LabelValues(ctx, label, matches, start, end)
+	return values, err
+}
+
+// Series implements MimirClient.
+func (c *Client) Series(ctx context.Context, matches []string, start, end time.Time, options ...RequestOption) ([]model.LabelSet, error) {
+	ctx = contextWithRequestOptions(ctx, options...)
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.ReadTimeout)
+	defer cancel()
+
+	series, _, err := c.readClient.Series(ctx, matches, start, end)
+	return series, err
+}
+
+// Metadata implements MimirClient.
+func (c *Client) Metadata(ctx context.Context, metric string, options ...RequestOption) (map[string][]v1.Metadata, error) {
+	ctx = contextWithRequestOptions(ctx, options...)
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.ReadTimeout)
+	defer cancel()
+
+	return c.readClient.Metadata(ctx, metric, "")
+}
+
+// SetRuleGroup implements MimirClient.
+func (c *Client) SetRuleGroup(ctx context.Context, namespace string, group rulefmt.RuleGroup) error {
+	if c.cfg.RulerBaseEndpoint.URL == nil {
+		return errors.New("the ruler endpoint has not been set")
+	}
+
+	data, err := yaml.Marshal(group)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal rule group")
+	}
+
+	return c.sendConfigRequest(ctx, c.cfg.RulerBaseEndpoint.String()+"/prometheus/config/v1/rules/"+url.PathEscape(namespace), data, http.StatusAccepted)
+}
+
+// SetAlertmanagerConfig implements MimirClient.
+func (c *Client) SetAlertmanagerConfig(ctx context.Context, config string, templates map[string]string) error {
+	if c.cfg.AlertmanagerBaseEndpoint.URL == nil {
+		return errors.New("the alertmanager endpoint has not been set")
+	}
+
+	data, err := yaml.Marshal(struct {
+		AlertmanagerConfig string            `yaml:"alertmanager_config"`
+		TemplateFiles      map[string]string `yaml:"template_files"`
+	}{
+		AlertmanagerConfig: config,
+		TemplateFiles:      templates,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal alertmanager config")
+	}
+
+	return c.sendConfigRequest(ctx, c.cfg.AlertmanagerBaseEndpoint.String()+"/api/v1/alerts", data, http.StatusCreated)
+}
+
+// sendConfigRequest POSTs the input YAML config to the endpoint, and returns an error unless
+// the response has the expected status code.
+func (c *Client) sendConfigRequest(ctx context.Context, endpoint string, data []byte, expectedStatusCode int) error {
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.WriteTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/yaml")
+	req.Header.Set("User-Agent", "mimir-continuous-test")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expectedStatusCode {
+		truncatedBody, err := io.ReadAll(io.LimitReader(resp.Body, maxErrMsgLen))
+		if err != nil {
+			return errors.Wrapf(err, "server returned HTTP status %s and client failed to read response body", resp.Status)
+		}
+
+		return fmt.Errorf("server returned HTTP status %s and body %q (truncated to %d bytes)", resp.Status, string(truncatedBody), maxErrMsgLen)
+	}
+
+	return nil
+}
+
 // WriteSeries implements MimirClient.
 func (c *Client) WriteSeries(ctx context.Context, series []prompb.TimeSeries) (int, error) {
+	return c.WriteSeriesWithMetadata(ctx, series, nil)
+}
+
+// WriteSeriesWithMetadata implements MimirClient.
+func (c *Client) WriteSeriesWithMetadata(ctx context.Context, series []prompb.TimeSeries, metadata []prompb.MetricMetadata) (int, error) {
 	lastStatusCode := 0
 
-	// Honor the batch size.
+	// Honor the batch size. The metadata is written with each batch, so that it's always
+	// written along with the series it refers to.
 	for len(series) > 0 {
 		end := util_math.Min(len(series), c.cfg.WriteBatchSize)
 		batch := series[0:end]
 		series = series[end:]
 
 		var err error
-		lastStatusCode, err = c.writeClient.sendWriteRequest(ctx, &prompb.WriteRequest{Timeseries: batch})
+		lastStatusCode, err = c.writeClient.sendWriteRequest(ctx, &prompb.WriteRequest{Timeseries: batch, Metadata: metadata})
 		if err != nil {
 			return lastStatusCode, err
 		}
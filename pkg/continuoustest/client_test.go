@@ -15,7 +15,9 @@ import (
 	"github.com/gogo/protobuf/proto"
 	"github.com/golang/snappy"
 	"github.com/grafana/dskit/flagext"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/rulefmt"
 	"github.com/prometheus/prometheus/prompb"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -89,6 +91,23 @@ func TestOTLPHttpClient_WriteSeries(t *testing.T) {
 		assert.Equal(t, 2, receivedRequests[2].Metrics().MetricCount())
 	})
 
+	t.Run("write series with metadata", func(t *testing.T) {
+		receivedRequests = nil
+		nextStatusCode = http.StatusOK
+
+		series := generateSineWaveSeries("test", now, 1)
+		metadata := []prompb.MetricMetadata{{Type: prompb.MetricMetadata_GAUGE, MetricFamilyName: "test", Help: "Test metric.", Unit: "seconds"}}
+		statusCode, err := c.WriteSeriesWithMetadata(ctx, series, metadata)
+		require.NoError(t, err)
+		assert.Equal(t, 200, statusCode)
+
+		require.Len(t, receivedRequests, 1)
+		metric := receivedRequests[0].Metrics().ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0)
+		assert.Equal(t, "test", metric.Name())
+		assert.Equal(t, "Test metric.", metric.Description())
+		assert.Equal(t, "seconds", metric.Unit())
+	})
+
 	t.Run("request failed with 4xx error", func(t *testing.T) {
 		receivedRequests = nil
 		nextStatusCode = http.StatusBadRequest
@@ -174,6 +193,21 @@ func TestPromWriterClient_WriteSeries(t *testing.T) {
 		assert.Equal(t, series[20:22], receivedRequests[2].Timeseries)
 	})
 
+	t.Run("write series with metadata", func(t *testing.T) {
+		receivedRequests = nil
+		nextStatusCode = http.StatusOK
+
+		series := generateSineWaveSeries("test", now, 1)
+		metadata := []prompb.MetricMetadata{{Type: prompb.MetricMetadata_GAUGE, MetricFamilyName: "test", Help: "Test metric.", Unit: "seconds"}}
+		statusCode, err := c.WriteSeriesWithMetadata(ctx, series, metadata)
+		require.NoError(t, err)
+		assert.Equal(t, 200, statusCode)
+
+		require.Len(t, receivedRequests, 1)
+		assert.Equal(t, series, receivedRequests[0].Timeseries)
+		assert.Equal(t, metadata, receivedRequests[0].Metadata)
+	})
+
 	t.Run("request failed with 4xx error", func(t *testing.T) {
 		receivedRequests = nil
 		nextStatusCode = http.StatusBadRequest
@@ -285,6 +319,141 @@ func TestClient_Query(t *testing.T) {
 	})
 }
 
+func TestClient_LabelsMetadataAndExemplarsAPIs(t *testing.T) {
+	var receivedRequests []*http.Request
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		receivedRequests = append(receivedRequests, request)
+
+		var body string
+		switch request.URL.Path {
+		case "/api/v1/labels":
+			body = `{"status":"success","data":["__name__","series_id"]}`
+		case "/api/v1/label/series_id/values":
+			body = `{"status":"success","data":["0","1"]}`
+		case "/api/v1/series":
+			body = `{"status":"success","data":[{"__name__":"test","series_id":"0"}]}`
+		case "/api/v1/metadata":
+			body = `{"status":"success","data":{"test":[{"type":"gauge","help":"Test metric.","unit":"seconds"}]}}`
+		case "/api/v1/query_exemplars":
+			body = `{"status":"success","data":[{"seriesLabels":{"__name__":"test"},"exemplars":[{"labels":{"trace_id":"abc"},"value":"1","timestamp":1}]}]}`
+		default:
+			writer.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		writer.WriteHeader(http.StatusOK)
+		_, err := writer.Write([]byte(body))
+		require.NoError(t, err)
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := ClientConfig{}
+	flagext.DefaultValues(&cfg)
+	require.NoError(t, cfg.WriteBaseEndpoint.Set(server.URL))
+	require.NoError(t, cfg.ReadBaseEndpoint.Set(server.URL))
+
+	c, err := NewClient(cfg, log.NewNopLogger())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	start, end := time.Unix(0, 0), time.Unix(1000, 0)
+	matches := []string{"test"}
+
+	names, err := c.LabelNames(ctx, matches, start, end)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"__name__", "series_id"}, names)
+
+	values, err := c.LabelValues(ctx, "series_id", matches, start, end)
+	require.NoError(t, err)
+	assert.Equal(t, model.LabelValues{"0", "1"}, values)
+
+	series, err := c.Series(ctx, matches, start, end)
+	require.NoError(t, err)
+	assert.Equal(t, []model.LabelSet{{"__name__": "test", "series_id": "0"}}, series)
+
+	metadata, err := c.Metadata(ctx, "test")
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]v1.Metadata{"test": {{Type: v1.MetricTypeGauge, Help: "Test metric.", Unit: "seconds"}}}, metadata)
+
+	exemplars, err := c.QueryExemplars(ctx, "test", start, end, WithResultsCacheEnabled(false))
+	require.NoError(t, err)
+	require.Len(t, exemplars, 1)
+	assert.Equal(t, model.LabelSet{"__name__": "test"}, exemplars[0].SeriesLabels)
+	assert.Equal(t, []v1.Exemplar{{Labels: model.LabelSet{"trace_id": "abc"}, Value: 1, Timestamp: 1000}}, exemplars[0].Exemplars)
+
+	require.Len(t, receivedRequests, 5)
+	assert.Equal(t, "no-store", receivedRequests[4].Header.Get("Cache-Control"))
+}
+
+func TestClient_SetRuleGroupAndAlertmanagerConfig(t *testing.T) {
+	var (
+		nextStatusCode   int
+		receivedRequests []*http.Request
+		receivedBodies   []string
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, err := io.ReadAll(request.Body)
+		require.NoError(t, err)
+
+		receivedRequests = append(receivedRequests, request)
+		receivedBodies = append(receivedBodies, string(body))
+		writer.WriteHeader(nextStatusCode)
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := ClientConfig{}
+	flagext.DefaultValues(&cfg)
+	cfg.TenantID = "test"
+	require.NoError(t, cfg.WriteBaseEndpoint.Set(server.URL))
+	require.NoError(t, cfg.ReadBaseEndpoint.Set(server.URL))
+
+	ctx := context.Background()
+	group := rulefmt.RuleGroup{Name: "group"}
+
+	t.Run("should fail if the endpoints have not been set", func(t *testing.T) {
+		c, err := NewClient(cfg, log.NewNopLogger())
+		require.NoError(t, err)
+
+		require.Error(t, c.SetRuleGroup(ctx, "namespace", group))
+		require.Error(t, c.SetAlertmanagerConfig(ctx, "config", nil))
+	})
+
+	cfg.RulerBaseEndpoint = cfg.ReadBaseEndpoint
+	cfg.AlertmanagerBaseEndpoint = cfg.ReadBaseEndpoint
+	c, err := NewClient(cfg, log.NewNopLogger())
+	require.NoError(t, err)
+
+	t.Run("should set the rule group", func(t *testing.T) {
+		receivedRequests, receivedBodies = nil, nil
+		nextStatusCode = http.StatusAccepted
+
+		require.NoError(t, c.SetRuleGroup(ctx, "name space", group))
+		require.Len(t, receivedRequests, 1)
+		assert.Equal(t, "/prometheus/config/v1/rules/name%20space", receivedRequests[0].URL.EscapedPath())
+		assert.Equal(t, "test", receivedRequests[0].Header.Get("X-Scope-OrgID"))
+		assert.Contains(t, receivedBodies[0], "name: group")
+	})
+
+	t.Run("should set the alertmanager config", func(t *testing.T) {
+		receivedRequests, receivedBodies = nil, nil
+		nextStatusCode = http.StatusCreated
+
+		require.NoError(t, c.SetAlertmanagerConfig(ctx, "route: {}", map[string]string{"tmpl": "content"}))
+		require.Len(t, receivedRequests, 1)
+		assert.Equal(t, "/api/v1/alerts", receivedRequests[0].URL.Path)
+		assert.Equal(t, "alertmanager_config: 'route: {}'\ntemplate_files:\n    tmpl: content\n", receivedBodies[0])
+	})
+
+	t.Run("should fail on unexpected status code", func(t *testing.T) {
+		nextStatusCode = http.StatusBadRequest
+
+		require.Error(t, c.SetRuleGroup(ctx, "namespace", group))
+		require.Error(t, c.SetAlertmanagerConfig(ctx, "config", nil))
+	})
+}
+
 // ClientMock mocks MimirClient.
 type ClientMock struct {
 	mock.Mock
@@ -295,6 +464,11 @@ func (m *ClientMock) WriteSeries(ctx context.Context, series []prompb.TimeSeries
 	return args.Int(0), args.Error(1)
 }
 
+func (m *ClientMock) WriteSeriesWithMetadata(ctx context.Context, series []prompb.TimeSeries, metadata []prompb.MetricMetadata) (int, error) {
+	args := m.Called(ctx, series, metadata)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *ClientMock) QueryRange(ctx context.Context, query string, start, end time.Time, step time.Duration, options ...RequestOption) (model.Matrix, error) {
 	args := m.Called(ctx, query, start, end, step, options)
 	return args.Get(0).(model.Matrix), args.Error(1)
@@ -304,3 +478,38 @@ func (m *ClientMock) Query(ctx context.Context, query string, ts time.Time, opti
 	args := m.Called(ctx, query, ts, options)
 	return args.Get(0).(model.Vector), args.Error(1)
 }
+
+func (m *ClientMock) QueryExemplars(ctx context.Context, query string, start, end time.Time, options ...RequestOption) ([]v1.ExemplarQueryResult, error) {
+	args := m.Called(ctx, query, start, end, options)
+	return args.Get(0).([]v1.ExemplarQueryResult), args.Error(1)
+}
+
+func (m *ClientMock) LabelNames(ctx context.Context, matches []string, start, end time.Time, options ...RequestOption) ([]string, error) {
+	args := m.Called(ctx, matches, start, end, options)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *ClientMock) LabelValues(ctx context.Context, label string, matches []string, start, end time.Time, options ...RequestOption) (model.LabelValues, error) {
+	args := m.Called(ctx, label, matches, start, end, options)
+	return args.Get(0).(model.LabelValues), args.Error(1)
+}
+
+func (m *ClientMock) Series(ctx context.Context, matches []string, start, end time.Time, options ...RequestOption) ([]model.LabelSet, error) {
+	args := m.Called(ctx, matches, start, end, options)
+	return args.Get(0).([]model.LabelSet), args.Error(1)
+}
+
+func (m *ClientMock) Metadata(ctx context.Context, metric string, options ...RequestOption) (map[string][]v1.Metadata, error) {
+	args := m.Called(ctx, metric, options)
+	return args.Get(0).(map[string][]v1.Metadata), args.Error(1)
+}
+
+func (m *ClientMock) SetRuleGroup(ctx context.Context, namespace string, group rulefmt.RuleGroup) error {
+	args := m.Called(ctx, namespace, group)
+	return args.Error(0)
+}
+
+func (m *ClientMock) SetAlertmanagerConfig(ctx context.Context, config string, templates map[string]string) error {
+	args := m.Called(ctx, config, templates)
+	return args.Error(0)
+}
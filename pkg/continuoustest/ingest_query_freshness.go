@@ -0,0 +1,173 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package continuoustest
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/prompb"
+
+	"github.com/grafana/mimir/pkg/util/spanlogger"
+)
+
+const (
+	freshnessMarkerMetricName = "mimir_continuous_test_freshness_marker"
+	freshnessMarkerTypeLabel  = "float"
+)
+
+type IngestQueryFreshnessTestConfig struct {
+	Enabled      bool
+	Timeout      time.Duration
+	PollInterval time.Duration
+}
+
+func (cfg *IngestQueryFreshnessTestConfig) RegisterFlags(f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, "tests.ingest-query-freshness-test.enabled", false, "Set to true to measure how long it takes for a written sample to become queryable.")
+	f.DurationVar(&cfg.Timeout, "tests.ingest-query-freshness-test.timeout", time.Minute, "How long to wait for a written sample to become queryable before considering the test failed.")
+	f.DurationVar(&cfg.PollInterval, "tests.ingest-query-freshness-test.poll-interval", 250*time.Millisecond, "How frequently to query the written sample while waiting for it to become queryable.")
+}
+
+// IngestQueryFreshnessTest writes a marker sample with a unique timestamp and value, and measures
+// how long it takes until the marker sample is returned by an instant query.
+type IngestQueryFreshnessTest struct {
+	name    string
+	cfg     IngestQueryFreshnessTestConfig
+	client  MimirClient
+	logger  log.Logger
+	metrics *TestMetrics
+
+	freshnessSeconds prometheus.Histogram
+}
+
+func NewIngestQueryFreshnessTest(cfg IngestQueryFreshnessTestConfig, client MimirClient, logger log.Logger, reg prometheus.Registerer) *IngestQueryFreshnessTest {
+	const name = "ingest-query-freshness"
+
+	return &IngestQueryFreshnessTest{
+		name:    name,
+		cfg:     cfg,
+		client:  client,
+		logger:  log.With(logger, "test", name),
+		metrics: NewTestMetrics(name, reg),
+		freshnessSeconds: promauto.With(reg).NewHistogram(prometheus.HistogramOpts{
+			Name:        "mimir_continuous_test_ingest_query_freshness_seconds",
+			Help:        "Time between the write of a sample and the first query returning it.",
+			Buckets:     prometheus.ExponentialBuckets(0.1, 2, 12),
+			ConstLabels: map[string]string{"test": name},
+		}),
+	}
+}
+
+// Name implements Test.
+func (t *IngestQueryFreshnessTest) Name() string {
+	return t.name
+}
+
+// Init implements Test.
+func (t *IngestQueryFreshnessTest) Init(_ context.Context, _ time.Time) error {
+	if t.cfg.PollInterval <= 0 {
+		return errors.New("the poll interval must be greater than 0")
+	}
+	if t.cfg.Timeout < t.cfg.PollInterval {
+		return errors.New("the timeout must be greater than or equal to the poll interval")
+	}
+	return nil
+}
+
+// Run implements Test.
+func (t *IngestQueryFreshnessTest) Run(ctx context.Context, now time.Time) error {
+	sp, ctx := spanlogger.NewWithLogger(ctx, t.logger, "IngestQueryFreshnessTest.Run")
+	defer sp.Finish()
+
+	// The marker timestamp is truncated to the millisecond, because that's the precision of samples. We use the
+	// timestamp as sample value too, so that the marker written in this run can't be confused with a previous one.
+	timestamp := time.UnixMilli(now.UnixMilli())
+	expectedValue := model.SampleValue(timestamp.UnixMilli())
+	logger := log.With(sp, "timestamp", timestamp.UnixMilli())
+
+	writeStart := time.Now()
+	if err := t.writeMarker(ctx, logger, timestamp); err != nil {
+		return err
+	}
+
+	// Poll the marker until it's queryable or the timeout expires.
+	t.metrics.queryResultChecksTotal.WithLabelValues(freshnessMarkerTypeLabel).Inc()
+	query := fmt.Sprintf("max_over_time(%s[1s])", freshnessMarkerMetricName)
+	ticker := time.NewTicker(t.cfg.PollInterval)
+	defer ticker.Stop()
+	deadline := time.NewTimer(t.cfg.Timeout)
+	defer deadline.Stop()
+
+	for {
+		found, err := t.queryMarker(ctx, logger, query, timestamp, expectedValue)
+		if err == nil && found {
+			elapsed := time.Since(writeStart)
+			t.freshnessSeconds.Observe(elapsed.Seconds())
+			level.Debug(logger).Log("msg", "Marker sample is queryable", "elapsed", elapsed)
+			return nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-deadline.C:
+			t.metrics.queryResultChecksFailedTotal.WithLabelValues(freshnessMarkerTypeLabel).Inc()
+			level.Warn(logger).Log("msg", "Marker sample has not become queryable before the timeout", "timeout", t.cfg.Timeout)
+			return fmt.Errorf("marker sample has not become queryable within %s", t.cfg.Timeout)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (t *IngestQueryFreshnessTest) writeMarker(ctx context.Context, logger log.Logger, timestamp time.Time) error {
+	series := []prompb.TimeSeries{{
+		Labels: []prompb.Label{{Name: "__name__", Value: freshnessMarkerMetricName}},
+		Samples: []prompb.Sample{{
+			Timestamp: timestamp.UnixMilli(),
+			Value:     float64(timestamp.UnixMilli()),
+		}},
+	}}
+
+	statusCode, err := t.client.WriteSeries(ctx, series)
+
+	t.metrics.writesTotal.WithLabelValues(freshnessMarkerTypeLabel).Inc()
+	if statusCode/100 != 2 {
+		t.metrics.writesFailedTotal.WithLabelValues(strconv.Itoa(statusCode), freshnessMarkerTypeLabel).Inc()
+		level.Warn(logger).Log("msg", "Failed to remote write marker sample", "status_code", statusCode, "err", err)
+
+		if err != nil {
+			return errors.Wrap(err, "failed to remote write marker sample")
+		}
+		return fmt.Errorf("remote write marker sample failed with status code %d", statusCode)
+	}
+
+	level.Debug(logger).Log("msg", "Remote write marker sample succeeded")
+	return nil
+}
+
+// queryMarker returns whether the marker sample with the expected value is returned by the query at the input timestamp.
+func (t *IngestQueryFreshnessTest) queryMarker(ctx context.Context, logger log.Logger, query string, timestamp time.Time, expectedValue model.SampleValue) (bool, error) {
+	t.metrics.queriesTotal.WithLabelValues(freshnessMarkerTypeLabel).Inc()
+	vector, err := t.client.Query(ctx, query, timestamp, WithResultsCacheEnabled(false))
+	if err != nil {
+		t.metrics.queriesFailedTotal.WithLabelValues(freshnessMarkerTypeLabel).Inc()
+		level.Warn(logger).Log("msg", "Failed to execute instant query", "query", query, "err", err)
+		return false, err
+	}
+
+	for _, sample := range vector {
+		if sample.Value == expectedValue {
+			return true, nil
+		}
+	}
+	return false, nil
+}
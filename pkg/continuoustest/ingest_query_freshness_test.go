@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package continuoustest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIngestQueryFreshnessTest_Run(t *testing.T) {
+	logger := log.NewNopLogger()
+	cfg := IngestQueryFreshnessTestConfig{
+		Enabled:      true,
+		Timeout:      time.Second,
+		PollInterval: 10 * time.Millisecond,
+	}
+
+	now := time.UnixMilli(1000123)
+	expectedSeries := []prompb.TimeSeries{{
+		Labels:  []prompb.Label{{Name: "__name__", Value: freshnessMarkerMetricName}},
+		Samples: []prompb.Sample{{Timestamp: 1000123, Value: 1000123}},
+	}}
+
+	t.Run("should observe the freshness once the marker sample is queryable", func(t *testing.T) {
+		client := &ClientMock{}
+		client.On("WriteSeries", mock.Anything, mock.Anything).Return(200, nil)
+
+		// The marker sample becomes queryable at the 3rd query. The previous queries return
+		// the marker sample written by a previous run.
+		client.On("Query", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(model.Vector{{Value: 999000}}, nil).Twice()
+		client.On("Query", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(model.Vector{{Value: 1000123}}, nil)
+
+		reg := prometheus.NewPedanticRegistry()
+		test := NewIngestQueryFreshnessTest(cfg, client, logger, reg)
+		require.NoError(t, test.Init(context.Background(), now))
+		require.NoError(t, test.Run(context.Background(), now))
+
+		client.AssertNumberOfCalls(t, "WriteSeries", 1)
+		client.AssertCalled(t, "WriteSeries", mock.Anything, expectedSeries)
+		client.AssertNumberOfCalls(t, "Query", 3)
+		client.AssertCalled(t, "Query", mock.Anything, "max_over_time(mimir_continuous_test_freshness_marker[1s])", now, mock.Anything)
+
+		assert.Equal(t, 1, testutil.CollectAndCount(reg, "mimir_continuous_test_ingest_query_freshness_seconds"))
+		assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+			# HELP mimir_continuous_test_queries_total Total number of attempted query requests.
+			# TYPE mimir_continuous_test_queries_total counter
+			mimir_continuous_test_queries_total{test="ingest-query-freshness",type="float"} 3
+
+			# HELP mimir_continuous_test_query_result_checks_total Total number of query results checked for correctness.
+			# TYPE mimir_continuous_test_query_result_checks_total counter
+			mimir_continuous_test_query_result_checks_total{test="ingest-query-freshness",type="float"} 1
+		`), "mimir_continuous_test_queries_total", "mimir_continuous_test_query_result_checks_total", "mimir_continuous_test_query_result_checks_failed_total"))
+	})
+
+	t.Run("should fail if the marker sample is not queryable before the timeout", func(t *testing.T) {
+		client := &ClientMock{}
+		client.On("WriteSeries", mock.Anything, mock.Anything).Return(200, nil)
+		client.On("Query", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(model.Vector{}, nil)
+
+		reg := prometheus.NewPedanticRegistry()
+		test := NewIngestQueryFreshnessTest(IngestQueryFreshnessTestConfig{Timeout: 100 * time.Millisecond, PollInterval: 10 * time.Millisecond}, client, logger, reg)
+		require.Error(t, test.Run(context.Background(), now))
+
+		assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+			# HELP mimir_continuous_test_query_result_checks_failed_total Total number of query results failed when checking for correctness.
+			# TYPE mimir_continuous_test_query_result_checks_failed_total counter
+			mimir_continuous_test_query_result_checks_failed_total{test="ingest-query-freshness",type="float"} 1
+		`), "mimir_continuous_test_query_result_checks_failed_total"))
+	})
+
+	t.Run("should fail without querying if the marker sample write fails", func(t *testing.T) {
+		client := &ClientMock{}
+		client.On("WriteSeries", mock.Anything, mock.Anything).Return(500, errors.New("500 error"))
+
+		reg := prometheus.NewPedanticRegistry()
+		test := NewIngestQueryFreshnessTest(cfg, client, logger, reg)
+		require.Error(t, test.Run(context.Background(), now))
+
+		client.AssertNumberOfCalls(t, "Query", 0)
+		assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+			# HELP mimir_continuous_test_writes_failed_total Total number of failed write requests.
+			# TYPE mimir_continuous_test_writes_failed_total counter
+			mimir_continuous_test_writes_failed_total{status_code="500",test="ingest-query-freshness",type="float"} 1
+		`), "mimir_continuous_test_writes_failed_total"))
+	})
+}
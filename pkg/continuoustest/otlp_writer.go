@@ -13,9 +13,11 @@ import (
 
 	"github.com/grafana/dskit/flagext"
 	"github.com/pkg/errors"
+	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/prompb"
 
 	"github.com/grafana/mimir/pkg/distributor"
+	"github.com/grafana/mimir/pkg/mimirpb"
 )
 
 type otlpHTTPWriter struct {
@@ -26,7 +28,7 @@ type otlpHTTPWriter struct {
 }
 
 func (pw *otlpHTTPWriter) sendWriteRequest(ctx context.Context, req *prompb.WriteRequest) (int, error) {
-	metricRequest := distributor.TimeseriesToOTLPRequest(req.Timeseries, nil)
+	metricRequest := distributor.TimeseriesToOTLPRequest(req.Timeseries, otlpMetadata(req))
 	rawBytes, err := metricRequest.MarshalProto()
 	if err != nil {
 		return 0, err
@@ -69,6 +71,39 @@ func (pw *otlpHTTPWriter) sendWriteRequest(ctx context.Context, req *prompb.Writ
 	return httpResp.StatusCode, nil
 }
 
+// otlpMetadata returns the metadata of each series in the input request, in the same order
+// of the series, or nil if the request has no metadata.
+func otlpMetadata(req *prompb.WriteRequest) []mimirpb.MetricMetadata {
+	if len(req.Metadata) == 0 {
+		return nil
+	}
+
+	byMetricName := make(map[string]prompb.MetricMetadata, len(req.Metadata))
+	for _, m := range req.Metadata {
+		byMetricName[m.MetricFamilyName] = m
+	}
+
+	metadata := make([]mimirpb.MetricMetadata, len(req.Timeseries))
+	for i, ts := range req.Timeseries {
+		for _, l := range ts.Labels {
+			if l.Name != model.MetricNameLabel {
+				continue
+			}
+			if m, ok := byMetricName[l.Value]; ok {
+				metadata[i] = mimirpb.MetricMetadata{
+					Type:             mimirpb.MetricMetadata_MetricType(m.Type),
+					MetricFamilyName: m.MetricFamilyName,
+					Help:             m.Help,
+					Unit:             m.Unit,
+				}
+			}
+			break
+		}
+	}
+
+	return metadata
+}
+
 func compress(input []byte) ([]byte, error) {
 	var b bytes.Buffer
 	gz := gzip.NewWriter(&b)
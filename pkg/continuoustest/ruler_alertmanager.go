@@ -0,0 +1,278 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package continuoustest
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/multierror"
+	"github.com/pkg/errors"
+	"github.com/prometheus/alertmanager/notify/webhook"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"github.com/prometheus/prometheus/prompb"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/mimir/pkg/util/spanlogger"
+)
+
+const (
+	rulesInputMetricName = "mimir_continuous_test_rules_input"
+	rulesRecordName      = "mimir_continuous_test:rules_input:max"
+	rulesAlertName       = "MimirContinuousTestAlert"
+	rulesRunLabel        = "run"
+	rulesNamespace       = "mimir-continuous-test"
+	rulesGroupName       = "ruler-alertmanager"
+	rulesGroupInterval   = 20 * time.Second
+	rulesPollInterval    = time.Second
+	rulesWebhookPath     = "/webhook"
+
+	recordingRuleTypeLabel = "recording_rule"
+	alertTypeLabel         = "alert"
+)
+
+type RulerAlertmanagerTestConfig struct {
+	Enabled              bool
+	WebhookListenAddress string
+	WebhookURL           string
+	Timeout              time.Duration
+}
+
+func (cfg *RulerAlertmanagerTestConfig) RegisterFlags(f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, "tests.ruler-alertmanager-test.enabled", false, "Set to true to upload a recording rule and an alerting rule, and check that the recorded series is queryable and the alert is notified to a webhook receiver run by the tool. The rule group and the Alertmanager configuration of the tenant are overwritten by the test.")
+	f.StringVar(&cfg.WebhookListenAddress, "tests.ruler-alertmanager-test.webhook-listen-address", ":9901", "The address on which the webhook receiver listens for alert notifications.")
+	f.StringVar(&cfg.WebhookURL, "tests.ruler-alertmanager-test.webhook-url", "", "The URL at which the Alertmanager can reach the webhook receiver, for example http://mimir-continuous-test:9901"+rulesWebhookPath+".")
+	f.DurationVar(&cfg.Timeout, "tests.ruler-alertmanager-test.timeout", 3*time.Minute, "How long to wait for the recorded series to be queryable and for the alert to be notified, before considering the test failed.")
+}
+
+// RulerAlertmanagerTest writes a series with a unique label and value at each run, and checks that
+// the series is processed by a recording rule and an alerting rule, whose alert is notified by the
+// Alertmanager to a webhook receiver run by the test.
+type RulerAlertmanagerTest struct {
+	name    string
+	cfg     RulerAlertmanagerTestConfig
+	client  MimirClient
+	logger  log.Logger
+	metrics *TestMetrics
+
+	pollInterval time.Duration
+	listener     net.Listener
+	server       *http.Server
+
+	// notifiedRunsMx protects notifiedRuns, which holds the run IDs of the notified alerts.
+	notifiedRunsMx sync.Mutex
+	notifiedRuns   map[string]struct{}
+}
+
+func NewRulerAlertmanagerTest(cfg RulerAlertmanagerTestConfig, client MimirClient, logger log.Logger, reg prometheus.Registerer) *RulerAlertmanagerTest {
+	const name = "ruler-alertmanager"
+
+	return &RulerAlertmanagerTest{
+		name:         name,
+		cfg:          cfg,
+		client:       client,
+		logger:       log.With(logger, "test", name),
+		metrics:      NewTestMetrics(name, reg),
+		pollInterval: rulesPollInterval,
+		notifiedRuns: map[string]struct{}{},
+	}
+}
+
+// Name implements Test.
+func (t *RulerAlertmanagerTest) Name() string {
+	return t.name
+}
+
+// Init implements Test.
+func (t *RulerAlertmanagerTest) Init(ctx context.Context, _ time.Time) error {
+	if t.cfg.WebhookURL == "" {
+		return errors.New("the webhook URL has not been set")
+	}
+
+	// Start the webhook receiver before configuring the Alertmanager, so that no notification is lost.
+	listener, err := net.Listen("tcp", t.cfg.WebhookListenAddress)
+	if err != nil {
+		return errors.Wrap(err, "failed to start the webhook receiver")
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(rulesWebhookPath, t.handleWebhook)
+	t.listener = listener
+	t.server = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := t.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			level.Error(t.logger).Log("msg", "Webhook receiver failed", "err", err)
+		}
+	}()
+
+	if err := t.client.SetAlertmanagerConfig(ctx, t.alertmanagerConfig(), nil); err != nil {
+		return errors.Wrap(err, "failed to set the alertmanager config")
+	}
+	if err := t.client.SetRuleGroup(ctx, rulesNamespace, t.ruleGroup()); err != nil {
+		return errors.Wrap(err, "failed to set the rule group")
+	}
+
+	level.Info(t.logger).Log("msg", "Configured the rule group and the alertmanager", "webhook_url", t.cfg.WebhookURL)
+	return nil
+}
+
+func (t *RulerAlertmanagerTest) ruleGroup() rulefmt.RuleGroup {
+	var record, alert, recordExpr, alertExpr yaml.Node
+	record.SetString(rulesRecordName)
+	recordExpr.SetString(fmt.Sprintf("max(%s)", rulesInputMetricName))
+	alert.SetString(rulesAlertName)
+	alertExpr.SetString(fmt.Sprintf("%s > 0", rulesInputMetricName))
+
+	return rulefmt.RuleGroup{
+		Name:     rulesGroupName,
+		Interval: model.Duration(rulesGroupInterval),
+		Rules: []rulefmt.RuleNode{
+			{Record: record, Expr: recordExpr},
+			{Alert: alert, Expr: alertExpr},
+		},
+	}
+}
+
+func (t *RulerAlertmanagerTest) alertmanagerConfig() string {
+	// Alerts are grouped by run, so that the alert of each run is notified right away.
+	return fmt.Sprintf(`route:
+  receiver: mimir-continuous-test
+  group_by: [alertname, %s]
+  group_wait: 0s
+  group_interval: 10s
+  repeat_interval: 1h
+receivers:
+  - name: mimir-continuous-test
+    webhook_configs:
+      - url: %q
+        send_resolved: false
+`, rulesRunLabel, t.cfg.WebhookURL)
+}
+
+func (t *RulerAlertmanagerTest) handleWebhook(w http.ResponseWriter, r *http.Request) {
+	var msg webhook.Message
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+		level.Warn(t.logger).Log("msg", "Failed to decode webhook notification", "err", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	t.notifiedRunsMx.Lock()
+	defer t.notifiedRunsMx.Unlock()
+
+	if msg.Data == nil {
+		return
+	}
+	for _, alert := range msg.Alerts.Firing() {
+		if alert.Labels[model.AlertNameLabel] == rulesAlertName {
+			t.notifiedRuns[alert.Labels[rulesRunLabel]] = struct{}{}
+		}
+	}
+}
+
+// isRunNotified returns whether the alert of the input run has been notified, and forgets it if so.
+func (t *RulerAlertmanagerTest) isRunNotified(runID string) bool {
+	t.notifiedRunsMx.Lock()
+	defer t.notifiedRunsMx.Unlock()
+
+	_, ok := t.notifiedRuns[runID]
+	delete(t.notifiedRuns, runID)
+	return ok
+}
+
+// Run implements Test.
+func (t *RulerAlertmanagerTest) Run(ctx context.Context, now time.Time) error {
+	sp, ctx := spanlogger.NewWithLogger(ctx, t.logger, "RulerAlertmanagerTest.Run")
+	defer sp.Finish()
+
+	// The run ID is used as value of the written sample too. Since it grows at every run,
+	// the recorded max() is the value written in this run.
+	runID := strconv.FormatInt(now.UnixMilli(), 10)
+	expectedValue := model.SampleValue(now.UnixMilli())
+	logger := log.With(sp, "run", runID)
+
+	series := []prompb.TimeSeries{{
+		Labels: []prompb.Label{
+			{Name: model.MetricNameLabel, Value: rulesInputMetricName},
+			{Name: rulesRunLabel, Value: runID},
+		},
+		Samples: []prompb.Sample{{Timestamp: now.UnixMilli(), Value: float64(expectedValue)}},
+	}}
+
+	statusCode, err := t.client.WriteSeries(ctx, series)
+	t.metrics.writesTotal.WithLabelValues(floatTypeLabel).Inc()
+	if statusCode/100 != 2 {
+		t.metrics.writesFailedTotal.WithLabelValues(strconv.Itoa(statusCode), floatTypeLabel).Inc()
+		level.Warn(logger).Log("msg", "Failed to remote write rules input series", "status_code", statusCode, "err", err)
+
+		if err != nil {
+			return errors.Wrap(err, "failed to remote write rules input series")
+		}
+		return fmt.Errorf("remote write rules input series failed with status code %d", statusCode)
+	}
+
+	t.metrics.queryResultChecksTotal.WithLabelValues(recordingRuleTypeLabel).Inc()
+	t.metrics.queryResultChecksTotal.WithLabelValues(alertTypeLabel).Inc()
+
+	ticker := time.NewTicker(t.pollInterval)
+	defer ticker.Stop()
+	deadline := time.NewTimer(t.cfg.Timeout)
+	defer deadline.Stop()
+
+	recorded, notified := false, false
+	for {
+		if !recorded {
+			recorded = t.isRecorded(ctx, logger, expectedValue)
+		}
+		if !notified {
+			notified = t.isRunNotified(runID)
+		}
+		if recorded && notified {
+			level.Debug(logger).Log("msg", "Recorded series is queryable and alert has been notified")
+			return nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-deadline.C:
+			errs := multierror.New()
+			if !recorded {
+				t.metrics.queryResultChecksFailedTotal.WithLabelValues(recordingRuleTypeLabel).Inc()
+				level.Warn(logger).Log("msg", "Recorded series has not become queryable before the timeout", "timeout", t.cfg.Timeout)
+				errs.Add(fmt.Errorf("recorded series has not become queryable within %s", t.cfg.Timeout))
+			}
+			if !notified {
+				t.metrics.queryResultChecksFailedTotal.WithLabelValues(alertTypeLabel).Inc()
+				level.Warn(logger).Log("msg", "Alert has not been notified before the timeout", "timeout", t.cfg.Timeout)
+				errs.Add(fmt.Errorf("alert has not been notified within %s", t.cfg.Timeout))
+			}
+			return errs.Err()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// isRecorded returns whether the latest sample of the recorded series has the expected value.
+func (t *RulerAlertmanagerTest) isRecorded(ctx context.Context, logger log.Logger, expectedValue model.SampleValue) bool {
+	t.metrics.queriesTotal.WithLabelValues(recordingRuleTypeLabel).Inc()
+	vector, err := t.client.Query(ctx, rulesRecordName, time.Now(), WithResultsCacheEnabled(false))
+	if err != nil {
+		t.metrics.queriesFailedTotal.WithLabelValues(recordingRuleTypeLabel).Inc()
+		level.Warn(logger).Log("msg", "Failed to execute instant query", "query", rulesRecordName, "err", err)
+		return false
+	}
+
+	return len(vector) == 1 && vector[0].Value == expectedValue
+}
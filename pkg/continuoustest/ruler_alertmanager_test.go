@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package continuoustest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/alertmanager/notify/webhook"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRulerAlertmanagerTest(t *testing.T) {
+	logger := log.NewNopLogger()
+	cfg := RulerAlertmanagerTestConfig{
+		Enabled:              true,
+		WebhookListenAddress: "localhost:0",
+		WebhookURL:           "http://mimir-continuous-test:9901/webhook",
+		Timeout:              500 * time.Millisecond,
+	}
+	now := time.UnixMilli(1000123)
+
+	// notify simulates the Alertmanager, notifying the firing alert of the input run to the webhook receiver.
+	notify := func(t *testing.T, test *RulerAlertmanagerTest, runID string) {
+		msg := webhook.Message{Data: &template.Data{Alerts: template.Alerts{{
+			Status: "firing",
+			Labels: template.KV{"alertname": rulesAlertName, "run": runID},
+		}}}}
+		data, err := json.Marshal(msg)
+		require.NoError(t, err)
+
+		resp, err := http.Post("http://"+test.listener.Addr().String()+rulesWebhookPath, "application/json", bytes.NewReader(data))
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+
+	setup := func(t *testing.T, recordedValue model.SampleValue) (*RulerAlertmanagerTest, *ClientMock, *prometheus.Registry) {
+		client := &ClientMock{}
+		client.On("SetAlertmanagerConfig", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		client.On("SetRuleGroup", mock.Anything, rulesNamespace, mock.Anything).Return(nil)
+		client.On("WriteSeries", mock.Anything, mock.Anything).Return(200, nil)
+		client.On("Query", mock.Anything, rulesRecordName, mock.Anything, mock.Anything).Return(model.Vector{{Value: recordedValue}}, nil)
+
+		reg := prometheus.NewPedanticRegistry()
+		test := NewRulerAlertmanagerTest(cfg, client, logger, reg)
+		test.pollInterval = 10 * time.Millisecond
+		require.NoError(t, test.Init(context.Background(), now))
+		t.Cleanup(func() { require.NoError(t, test.server.Close()) })
+
+		return test, client, reg
+	}
+
+	t.Run("should configure the rule group and the alertmanager at initialization", func(t *testing.T) {
+		_, client, _ := setup(t, 0)
+
+		client.AssertCalled(t, "SetAlertmanagerConfig", mock.Anything, mock.MatchedBy(func(config string) bool {
+			return strings.Contains(config, `url: "http://mimir-continuous-test:9901/webhook"`)
+		}), mock.Anything)
+
+		client.AssertCalled(t, "SetRuleGroup", mock.Anything, rulesNamespace, mock.MatchedBy(func(group rulefmt.RuleGroup) bool {
+			return group.Name == rulesGroupName && len(group.Rules) == 2 &&
+				group.Rules[0].Record.Value == rulesRecordName && group.Rules[0].Expr.Value == "max(mimir_continuous_test_rules_input)" &&
+				group.Rules[1].Alert.Value == rulesAlertName && group.Rules[1].Expr.Value == "mimir_continuous_test_rules_input > 0"
+		}))
+	})
+
+	t.Run("should pass if the series is recorded and the alert is notified", func(t *testing.T) {
+		test, client, reg := setup(t, 1000123)
+		notify(t, test, "1000123")
+
+		require.NoError(t, test.Run(context.Background(), now))
+
+		client.AssertCalled(t, "WriteSeries", mock.Anything, []prompb.TimeSeries{{
+			Labels:  []prompb.Label{{Name: "__name__", Value: rulesInputMetricName}, {Name: "run", Value: "1000123"}},
+			Samples: []prompb.Sample{{Timestamp: 1000123, Value: 1000123}},
+		}})
+		assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+			# HELP mimir_continuous_test_query_result_checks_total Total number of query results checked for correctness.
+			# TYPE mimir_continuous_test_query_result_checks_total counter
+			mimir_continuous_test_query_result_checks_total{test="ruler-alertmanager",type="alert"} 1
+			mimir_continuous_test_query_result_checks_total{test="ruler-alertmanager",type="recording_rule"} 1
+		`), "mimir_continuous_test_query_result_checks_total", "mimir_continuous_test_query_result_checks_failed_total"))
+	})
+
+	t.Run("should fail if the alert of the run is not notified", func(t *testing.T) {
+		test, _, reg := setup(t, 1000123)
+		notify(t, test, "999000")
+
+		require.Error(t, test.Run(context.Background(), now))
+
+		assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+			# HELP mimir_continuous_test_query_result_checks_failed_total Total number of query results failed when checking for correctness.
+			# TYPE mimir_continuous_test_query_result_checks_failed_total counter
+			mimir_continuous_test_query_result_checks_failed_total{test="ruler-alertmanager",type="alert"} 1
+		`), "mimir_continuous_test_query_result_checks_failed_total"))
+	})
+
+	t.Run("should fail if the recorded series has an unexpected value", func(t *testing.T) {
+		test, _, reg := setup(t, 999000)
+		notify(t, test, "1000123")
+
+		require.Error(t, test.Run(context.Background(), now))
+
+		assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+			# HELP mimir_continuous_test_query_result_checks_failed_total Total number of query results failed when checking for correctness.
+			# TYPE mimir_continuous_test_query_result_checks_failed_total counter
+			mimir_continuous_test_query_result_checks_failed_total{test="ruler-alertmanager",type="recording_rule"} 1
+		`), "mimir_continuous_test_query_result_checks_failed_total"))
+	})
+}
@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package continuoustest
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/pkg/errors"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/prompb"
+
+	"github.com/grafana/mimir/pkg/util/spanlogger"
+)
+
+const (
+	exemplarsMetricName   = "mimir_continuous_test_exemplars"
+	exemplarsTraceIDLabel = "trace_id"
+	exemplarsTypeLabel    = "exemplars"
+)
+
+type WriteReadExemplarsTestConfig struct {
+	Enabled bool
+	Timeout time.Duration
+}
+
+func (cfg *WriteReadExemplarsTestConfig) RegisterFlags(f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, "tests.write-read-exemplars-test.enabled", false, "Set to true to write exemplars and check them through the exemplars query API. Requires the prometheus write protocol, and exemplars to be enabled for the tenant.")
+	f.DurationVar(&cfg.Timeout, "tests.write-read-exemplars-test.timeout", time.Minute, "How long to retry the query while waiting for the written exemplar to be returned before considering the test failed.")
+}
+
+// WriteReadExemplarsTest writes a sample with an exemplar having a unique trace ID,
+// and checks that the exemplar is returned by the exemplars query API.
+type WriteReadExemplarsTest struct {
+	name    string
+	cfg     WriteReadExemplarsTestConfig
+	client  MimirClient
+	logger  log.Logger
+	metrics *TestMetrics
+}
+
+func NewWriteReadExemplarsTest(cfg WriteReadExemplarsTestConfig, client MimirClient, logger log.Logger, reg prometheus.Registerer) *WriteReadExemplarsTest {
+	const name = "write-read-exemplars"
+
+	return &WriteReadExemplarsTest{
+		name:    name,
+		cfg:     cfg,
+		client:  client,
+		logger:  log.With(logger, "test", name),
+		metrics: NewTestMetrics(name, reg),
+	}
+}
+
+// Name implements Test.
+func (t *WriteReadExemplarsTest) Name() string {
+	return t.name
+}
+
+// Init implements Test.
+func (t *WriteReadExemplarsTest) Init(_ context.Context, _ time.Time) error {
+	if t.cfg.Timeout <= 0 {
+		return errors.New("the timeout must be greater than 0")
+	}
+	return nil
+}
+
+// Run implements Test.
+func (t *WriteReadExemplarsTest) Run(ctx context.Context, now time.Time) error {
+	sp, ctx := spanlogger.NewWithLogger(ctx, t.logger, "WriteReadExemplarsTest.Run")
+	defer sp.Finish()
+
+	timestamp := time.UnixMilli(now.UnixMilli())
+	traceID := fmt.Sprintf("%016x", timestamp.UnixMilli())
+	logger := log.With(sp, "timestamp", timestamp.UnixMilli(), "trace_id", traceID)
+
+	series := []prompb.TimeSeries{{
+		Labels:  []prompb.Label{{Name: model.MetricNameLabel, Value: exemplarsMetricName}},
+		Samples: []prompb.Sample{{Timestamp: timestamp.UnixMilli(), Value: 1}},
+		Exemplars: []prompb.Exemplar{{
+			Labels:    []prompb.Label{{Name: exemplarsTraceIDLabel, Value: traceID}},
+			Value:     1,
+			Timestamp: timestamp.UnixMilli(),
+		}},
+	}}
+
+	statusCode, err := t.client.WriteSeries(ctx, series)
+	t.metrics.writesTotal.WithLabelValues(exemplarsTypeLabel).Inc()
+	if statusCode/100 != 2 {
+		t.metrics.writesFailedTotal.WithLabelValues(strconv.Itoa(statusCode), exemplarsTypeLabel).Inc()
+		level.Warn(logger).Log("msg", "Failed to remote write exemplar", "status_code", statusCode, "err", err)
+
+		if err != nil {
+			return errors.Wrap(err, "failed to remote write exemplar")
+		}
+		return fmt.Errorf("remote write exemplar failed with status code %d", statusCode)
+	}
+
+	expected := v1.Exemplar{
+		Labels:    model.LabelSet{exemplarsTraceIDLabel: model.LabelValue(traceID)},
+		Value:     1,
+		Timestamp: model.TimeFromUnixNano(timestamp.UnixNano()),
+	}
+
+	return runQueryAndVerifyResult(ctx, t.metrics, logger, t.cfg.Timeout, exemplarsTypeLabel,
+		func(ctx context.Context) ([]v1.ExemplarQueryResult, error) {
+			return t.client.QueryExemplars(ctx, exemplarsMetricName, timestamp, timestamp, WithResultsCacheEnabled(false))
+		},
+		func(results []v1.ExemplarQueryResult) error {
+			for _, result := range results {
+				if slices.ContainsFunc(result.Exemplars, func(e v1.Exemplar) bool {
+					return e.Labels.Equal(expected.Labels) && e.Value == expected.Value && e.Timestamp == expected.Timestamp
+				}) {
+					return nil
+				}
+			}
+			return fmt.Errorf("expected exemplar with trace ID %s has not been returned", traceID)
+		})
+}
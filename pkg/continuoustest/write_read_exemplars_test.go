@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package continuoustest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteReadExemplarsTest_Run(t *testing.T) {
+	logger := log.NewNopLogger()
+	now := time.UnixMilli(1000123)
+
+	expectedSeries := []prompb.TimeSeries{{
+		Labels:    []prompb.Label{{Name: "__name__", Value: exemplarsMetricName}},
+		Samples:   []prompb.Sample{{Timestamp: 1000123, Value: 1}},
+		Exemplars: []prompb.Exemplar{{Labels: []prompb.Label{{Name: "trace_id", Value: "00000000000f42bb"}}, Value: 1, Timestamp: 1000123}},
+	}}
+
+	tests := map[string]struct {
+		exemplars   []v1.Exemplar
+		expectedErr bool
+	}{
+		"should pass if the written exemplar is returned": {
+			exemplars: []v1.Exemplar{
+				{Labels: model.LabelSet{"trace_id": "00000000000f3e58"}, Value: 1, Timestamp: 999000},
+				{Labels: model.LabelSet{"trace_id": "00000000000f42bb"}, Value: 1, Timestamp: 1000123},
+			},
+		},
+		"should fail if the written exemplar is not returned": {
+			exemplars: []v1.Exemplar{
+				{Labels: model.LabelSet{"trace_id": "00000000000f3e58"}, Value: 1, Timestamp: 999000},
+			},
+			expectedErr: true,
+		},
+		"should fail if the returned exemplar has a different timestamp": {
+			exemplars: []v1.Exemplar{
+				{Labels: model.LabelSet{"trace_id": "00000000000f42bb"}, Value: 1, Timestamp: 1000000},
+			},
+			expectedErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := &ClientMock{}
+			client.On("WriteSeries", mock.Anything, mock.Anything).Return(200, nil)
+			client.On("QueryExemplars", mock.Anything, exemplarsMetricName, now, now, mock.Anything).Return([]v1.ExemplarQueryResult{{
+				SeriesLabels: model.LabelSet{"__name__": exemplarsMetricName},
+				Exemplars:    tc.exemplars,
+			}}, nil)
+
+			test := NewWriteReadExemplarsTest(WriteReadExemplarsTestConfig{Enabled: true, Timeout: 100 * time.Millisecond}, client, logger, prometheus.NewPedanticRegistry())
+			require.NoError(t, test.Init(context.Background(), now))
+
+			err := test.Run(context.Background(), now)
+			if tc.expectedErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			client.AssertCalled(t, "WriteSeries", mock.Anything, expectedSeries)
+		})
+	}
+}
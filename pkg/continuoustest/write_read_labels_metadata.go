@@ -0,0 +1,236 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package continuoustest
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/backoff"
+	"github.com/grafana/dskit/multierror"
+	"github.com/pkg/errors"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/prompb"
+
+	"github.com/grafana/mimir/pkg/util/spanlogger"
+)
+
+const (
+	labelsMetadataMetricName    = "mimir_continuous_test_labels_metadata"
+	labelsMetadataMetricHelp    = "Series written by mimir-continuous-test to check the labels and metadata APIs."
+	labelsMetadataSeriesIDLabel = "series_id"
+
+	labelNamesTypeLabel  = "label_names"
+	labelValuesTypeLabel = "label_values"
+	seriesTypeLabel      = "series"
+	metadataTypeLabel    = "metadata"
+)
+
+type WriteReadLabelsMetadataTestConfig struct {
+	Enabled   bool
+	NumSeries int
+	Timeout   time.Duration
+}
+
+func (cfg *WriteReadLabelsMetadataTestConfig) RegisterFlags(f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, "tests.write-read-labels-metadata-test.enabled", false, "Set to true to write series with metric metadata and check them through the labels, series and metadata APIs.")
+	f.IntVar(&cfg.NumSeries, "tests.write-read-labels-metadata-test.num-series", 10, "Number of series used for the test.")
+	f.DurationVar(&cfg.Timeout, "tests.write-read-labels-metadata-test.timeout", time.Minute, "How long to retry each query while waiting for the written series and metadata to be returned before considering the test failed.")
+}
+
+// WriteReadLabelsMetadataTest writes series along with their metric metadata, and checks
+// that the label names, label values, series and metadata APIs return them.
+type WriteReadLabelsMetadataTest struct {
+	name    string
+	cfg     WriteReadLabelsMetadataTestConfig
+	client  MimirClient
+	logger  log.Logger
+	metrics *TestMetrics
+}
+
+func NewWriteReadLabelsMetadataTest(cfg WriteReadLabelsMetadataTestConfig, client MimirClient, logger log.Logger, reg prometheus.Registerer) *WriteReadLabelsMetadataTest {
+	const name = "write-read-labels-metadata"
+
+	return &WriteReadLabelsMetadataTest{
+		name:    name,
+		cfg:     cfg,
+		client:  client,
+		logger:  log.With(logger, "test", name),
+		metrics: NewTestMetrics(name, reg),
+	}
+}
+
+// Name implements Test.
+func (t *WriteReadLabelsMetadataTest) Name() string {
+	return t.name
+}
+
+// Init implements Test.
+func (t *WriteReadLabelsMetadataTest) Init(_ context.Context, _ time.Time) error {
+	if t.cfg.NumSeries <= 0 {
+		return errors.New("the number of series must be greater than 0")
+	}
+	if t.cfg.Timeout <= 0 {
+		return errors.New("the timeout must be greater than 0")
+	}
+	return nil
+}
+
+// Run implements Test.
+func (t *WriteReadLabelsMetadataTest) Run(ctx context.Context, now time.Time) error {
+	sp, ctx := spanlogger.NewWithLogger(ctx, t.logger, "WriteReadLabelsMetadataTest.Run")
+	defer sp.Finish()
+	logger := log.With(sp, "timestamp", now.UnixMilli(), "num_series", t.cfg.NumSeries)
+
+	series := make([]prompb.TimeSeries, 0, t.cfg.NumSeries)
+	expectedSeries := make([]model.LabelSet, 0, t.cfg.NumSeries)
+	expectedValues := make(model.LabelValues, 0, t.cfg.NumSeries)
+	for i := 0; i < t.cfg.NumSeries; i++ {
+		seriesID := strconv.Itoa(i)
+		series = append(series, prompb.TimeSeries{
+			Labels: []prompb.Label{
+				{Name: model.MetricNameLabel, Value: labelsMetadataMetricName},
+				{Name: labelsMetadataSeriesIDLabel, Value: seriesID},
+			},
+			Samples: []prompb.Sample{{Timestamp: now.UnixMilli(), Value: 1}},
+		})
+		expectedSeries = append(expectedSeries, model.LabelSet{model.MetricNameLabel: labelsMetadataMetricName, labelsMetadataSeriesIDLabel: model.LabelValue(seriesID)})
+		expectedValues = append(expectedValues, model.LabelValue(seriesID))
+	}
+	slices.Sort(expectedValues)
+
+	metadata := []prompb.MetricMetadata{{
+		Type:             prompb.MetricMetadata_GAUGE,
+		MetricFamilyName: labelsMetadataMetricName,
+		Help:             labelsMetadataMetricHelp,
+	}}
+
+	statusCode, err := t.client.WriteSeriesWithMetadata(ctx, series, metadata)
+	t.metrics.writesTotal.WithLabelValues(floatTypeLabel).Inc()
+	if statusCode/100 != 2 {
+		t.metrics.writesFailedTotal.WithLabelValues(strconv.Itoa(statusCode), floatTypeLabel).Inc()
+		level.Warn(logger).Log("msg", "Failed to remote write series with metadata", "status_code", statusCode, "err", err)
+
+		if err != nil {
+			return errors.Wrap(err, "failed to remote write series with metadata")
+		}
+		return fmt.Errorf("remote write series with metadata failed with status code %d", statusCode)
+	}
+
+	// Query a short time range ending at the written samples, so that series written by previous runs
+	// with a different number of configured series don't influence the results.
+	start, end := now.Add(-time.Minute), now
+	matches := []string{labelsMetadataMetricName}
+
+	errs := new(multierror.MultiError)
+	errs.Add(runQueryAndVerifyResult(ctx, t.metrics, logger, t.cfg.Timeout, labelNamesTypeLabel,
+		func(ctx context.Context) ([]string, error) {
+			return t.client.LabelNames(ctx, matches, start, end, WithResultsCacheEnabled(false))
+		},
+		func(names []string) error {
+			expected := []string{model.MetricNameLabel, labelsMetadataSeriesIDLabel}
+			if !slices.Equal(names, expected) {
+				return fmt.Errorf("expected label names %v but got %v", expected, names)
+			}
+			return nil
+		}))
+
+	errs.Add(runQueryAndVerifyResult(ctx, t.metrics, logger, t.cfg.Timeout, labelValuesTypeLabel,
+		func(ctx context.Context) (model.LabelValues, error) {
+			return t.client.LabelValues(ctx, labelsMetadataSeriesIDLabel, matches, start, end, WithResultsCacheEnabled(false))
+		},
+		func(values model.LabelValues) error {
+			slices.Sort(values)
+			if !slices.Equal(values, expectedValues) {
+				return fmt.Errorf("expected %d label values but got %d: %v", len(expectedValues), len(values), values)
+			}
+			return nil
+		}))
+
+	errs.Add(runQueryAndVerifyResult(ctx, t.metrics, logger, t.cfg.Timeout, seriesTypeLabel,
+		func(ctx context.Context) ([]model.LabelSet, error) {
+			return t.client.Series(ctx, matches, start, end, WithResultsCacheEnabled(false))
+		},
+		func(actual []model.LabelSet) error {
+			if len(actual) != len(expectedSeries) {
+				return fmt.Errorf("expected %d series but got %d", len(expectedSeries), len(actual))
+			}
+			for _, expected := range expectedSeries {
+				if !slices.ContainsFunc(actual, expected.Equal) {
+					return fmt.Errorf("expected series %s has not been returned", expected)
+				}
+			}
+			return nil
+		}))
+
+	errs.Add(runQueryAndVerifyResult(ctx, t.metrics, logger, t.cfg.Timeout, metadataTypeLabel,
+		func(ctx context.Context) (map[string][]v1.Metadata, error) {
+			return t.client.Metadata(ctx, labelsMetadataMetricName, WithResultsCacheEnabled(false))
+		},
+		func(actual map[string][]v1.Metadata) error {
+			expected := v1.Metadata{Type: v1.MetricTypeGauge, Help: labelsMetadataMetricHelp}
+			if !slices.Contains(actual[labelsMetadataMetricName], expected) {
+				return fmt.Errorf("expected metadata %+v but got %+v", expected, actual[labelsMetadataMetricName])
+			}
+			return nil
+		}))
+
+	return errs.Err()
+}
+
+// queryRetryBackoff is the backoff between the attempts of runQueryAndVerifyResult.
+var queryRetryBackoff = backoff.Config{
+	MinBackoff: 250 * time.Millisecond,
+	MaxBackoff: 5 * time.Second,
+}
+
+// runQueryAndVerifyResult runs the input query, and verifies its result, tracking the outcome in the test metrics.
+// Since the written data may not be queryable right away, the query is retried with backoff until its result
+// is verified or the timeout expires.
+func runQueryAndVerifyResult[T any](ctx context.Context, metrics *TestMetrics, logger log.Logger, timeout time.Duration, typeLabel string, query func(context.Context) (T, error), verify func(T) error) error {
+	logger = log.With(logger, "type", typeLabel)
+
+	retryCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	metrics.queryResultChecksTotal.WithLabelValues(typeLabel).Inc()
+	var lastErr error
+	for boff := backoff.New(retryCtx, queryRetryBackoff); boff.Ongoing(); boff.Wait() {
+		metrics.queriesTotal.WithLabelValues(typeLabel).Inc()
+		result, err := query(retryCtx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			metrics.queriesFailedTotal.WithLabelValues(typeLabel).Inc()
+			level.Debug(logger).Log("msg", "Failed to execute query, retrying", "err", err)
+			lastErr = errors.Wrapf(err, "failed to execute %s query", typeLabel)
+			continue
+		}
+
+		if err := verify(result); err != nil {
+			level.Debug(logger).Log("msg", "Query result check failed, retrying", "err", err)
+			lastErr = errors.Wrapf(err, "%s query result check failed", typeLabel)
+			continue
+		}
+		return nil
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("%s query has not been executed within %s", typeLabel, timeout)
+	}
+	metrics.queryResultChecksFailedTotal.WithLabelValues(typeLabel).Inc()
+	level.Warn(logger).Log("msg", "Query result check failed before the timeout", "timeout", timeout, "err", lastErr)
+	return lastErr
+}
@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package continuoustest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteReadLabelsMetadataTest_Run(t *testing.T) {
+	logger := log.NewNopLogger()
+	cfg := WriteReadLabelsMetadataTestConfig{Enabled: true, NumSeries: 2, Timeout: time.Minute}
+	now := time.Unix(1000, 0)
+
+	expectedSeries := []prompb.TimeSeries{
+		{
+			Labels:  []prompb.Label{{Name: "__name__", Value: labelsMetadataMetricName}, {Name: "series_id", Value: "0"}},
+			Samples: []prompb.Sample{{Timestamp: 1000000, Value: 1}},
+		}, {
+			Labels:  []prompb.Label{{Name: "__name__", Value: labelsMetadataMetricName}, {Name: "series_id", Value: "1"}},
+			Samples: []prompb.Sample{{Timestamp: 1000000, Value: 1}},
+		},
+	}
+	expectedMetadata := []prompb.MetricMetadata{{Type: prompb.MetricMetadata_GAUGE, MetricFamilyName: labelsMetadataMetricName, Help: labelsMetadataMetricHelp}}
+	matches := []string{labelsMetadataMetricName}
+
+	t.Run("should pass if all APIs return the written series and metadata", func(t *testing.T) {
+		client := &ClientMock{}
+		client.On("WriteSeriesWithMetadata", mock.Anything, mock.Anything, mock.Anything).Return(200, nil)
+		client.On("LabelNames", mock.Anything, matches, now.Add(-time.Minute), now, mock.Anything).Return([]string{"__name__", "series_id"}, nil)
+		client.On("LabelValues", mock.Anything, "series_id", matches, now.Add(-time.Minute), now, mock.Anything).Return(model.LabelValues{"1", "0"}, nil)
+		client.On("Series", mock.Anything, matches, now.Add(-time.Minute), now, mock.Anything).Return([]model.LabelSet{
+			{"__name__": labelsMetadataMetricName, "series_id": "1"},
+			{"__name__": labelsMetadataMetricName, "series_id": "0"},
+		}, nil)
+		client.On("Metadata", mock.Anything, labelsMetadataMetricName, mock.Anything).Return(map[string][]v1.Metadata{
+			labelsMetadataMetricName: {{Type: v1.MetricTypeGauge, Help: labelsMetadataMetricHelp}},
+		}, nil)
+
+		reg := prometheus.NewPedanticRegistry()
+		test := NewWriteReadLabelsMetadataTest(cfg, client, logger, reg)
+		require.NoError(t, test.Init(context.Background(), now))
+		require.NoError(t, test.Run(context.Background(), now))
+
+		client.AssertCalled(t, "WriteSeriesWithMetadata", mock.Anything, expectedSeries, expectedMetadata)
+		assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+			# HELP mimir_continuous_test_query_result_checks_total Total number of query results checked for correctness.
+			# TYPE mimir_continuous_test_query_result_checks_total counter
+			mimir_continuous_test_query_result_checks_total{test="write-read-labels-metadata",type="label_names"} 1
+			mimir_continuous_test_query_result_checks_total{test="write-read-labels-metadata",type="label_values"} 1
+			mimir_continuous_test_query_result_checks_total{test="write-read-labels-metadata",type="metadata"} 1
+			mimir_continuous_test_query_result_checks_total{test="write-read-labels-metadata",type="series"} 1
+		`), "mimir_continuous_test_query_result_checks_total", "mimir_continuous_test_query_result_checks_failed_total"))
+	})
+
+	t.Run("should retry the queries until the APIs return the written series and metadata", func(t *testing.T) {
+		client := &ClientMock{}
+		client.On("WriteSeriesWithMetadata", mock.Anything, mock.Anything, mock.Anything).Return(200, nil)
+		client.On("LabelNames", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]string{"__name__"}, nil).Once()
+		client.On("LabelNames", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]string{"__name__", "series_id"}, nil)
+		client.On("LabelValues", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(model.LabelValues{"0", "1"}, nil)
+		client.On("Series", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]model.LabelSet(nil), errors.New("unavailable")).Once()
+		client.On("Series", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]model.LabelSet{
+			{"__name__": labelsMetadataMetricName, "series_id": "1"},
+			{"__name__": labelsMetadataMetricName, "series_id": "0"},
+		}, nil)
+		client.On("Metadata", mock.Anything, mock.Anything, mock.Anything).Return(map[string][]v1.Metadata{
+			labelsMetadataMetricName: {{Type: v1.MetricTypeGauge, Help: labelsMetadataMetricHelp}},
+		}, nil)
+
+		reg := prometheus.NewPedanticRegistry()
+		test := NewWriteReadLabelsMetadataTest(cfg, client, logger, reg)
+		require.NoError(t, test.Run(context.Background(), now))
+
+		client.AssertNumberOfCalls(t, "LabelNames", 2)
+		client.AssertNumberOfCalls(t, "Series", 2)
+		assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+			# HELP mimir_continuous_test_queries_failed_total Total number of failed query requests.
+			# TYPE mimir_continuous_test_queries_failed_total counter
+			mimir_continuous_test_queries_failed_total{test="write-read-labels-metadata",type="series"} 1
+		`), "mimir_continuous_test_queries_failed_total", "mimir_continuous_test_query_result_checks_failed_total"))
+	})
+
+	t.Run("should fail if the APIs don't return the written series and metadata before the timeout", func(t *testing.T) {
+		cfg := cfg
+		cfg.Timeout = 100 * time.Millisecond
+
+		client := &ClientMock{}
+		client.On("WriteSeriesWithMetadata", mock.Anything, mock.Anything, mock.Anything).Return(200, nil)
+		client.On("LabelNames", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]string{"__name__"}, nil)
+		client.On("LabelValues", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(model.LabelValues{"0", "1"}, nil)
+		client.On("Series", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]model.LabelSet{
+			{"__name__": labelsMetadataMetricName, "series_id": "0"},
+		}, nil)
+		client.On("Metadata", mock.Anything, mock.Anything, mock.Anything).Return(map[string][]v1.Metadata{
+			labelsMetadataMetricName: {{Type: v1.MetricTypeCounter, Help: labelsMetadataMetricHelp}},
+		}, nil)
+
+		reg := prometheus.NewPedanticRegistry()
+		test := NewWriteReadLabelsMetadataTest(cfg, client, logger, reg)
+		require.Error(t, test.Run(context.Background(), now))
+
+		assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+			# HELP mimir_continuous_test_query_result_checks_failed_total Total number of query results failed when checking for correctness.
+			# TYPE mimir_continuous_test_query_result_checks_failed_total counter
+			mimir_continuous_test_query_result_checks_failed_total{test="write-read-labels-metadata",type="label_names"} 1
+			mimir_continuous_test_query_result_checks_failed_total{test="write-read-labels-metadata",type="metadata"} 1
+			mimir_continuous_test_query_result_checks_failed_total{test="write-read-labels-metadata",type="series"} 1
+		`), "mimir_continuous_test_query_result_checks_failed_total"))
+	})
+
+	t.Run("should fail without querying if the write fails", func(t *testing.T) {
+		client := &ClientMock{}
+		client.On("WriteSeriesWithMetadata", mock.Anything, mock.Anything, mock.Anything).Return(400, nil)
+
+		reg := prometheus.NewPedanticRegistry()
+		test := NewWriteReadLabelsMetadataTest(cfg, client, logger, reg)
+		require.Error(t, test.Run(context.Background(), now))
+
+		client.AssertNumberOfCalls(t, "LabelNames", 0)
+		client.AssertNumberOfCalls(t, "Metadata", 0)
+	})
+}
@@ -103,6 +103,10 @@ type Distributor struct {
 	distributorsRing       *ring.Ring
 	healthyInstancesCount  *atomic.Uint32
 
+	// Pool of clients to the other distributors in the ring, to forward the requests which must be
+	// handled by all the distributors. Nil if the distributor doesn't join the distributors ring.
+	distributorPool *ring_client.Pool
+
 	// For handling HA replicas.
 	HATracker *haTracker
 
@@ -155,6 +159,11 @@ type Distributor struct {
 
 	PushWithMiddlewares PushFunc
 
+	// Captures of the series pushed by tenants, for debugging purposes.
+	pushCaptures pushCaptures
+
+	streamAggregator *streamAggregator
+
 	// Pool of []byte used when marshalling write requests.
 	writeRequestBytePool sync.Pool
 
@@ -187,6 +196,9 @@ type Config struct {
 	// for testing and for extending the ingester by adding calls to the client
 	IngesterClientFactory ring_client.PoolFactory `yaml:"-"`
 
+	// for testing, the factory of the clients to the other distributors
+	DistributorClientFactory ring_client.PoolFactory `yaml:"-"`
+
 	// when true the distributor does not validate the label name, Mimir doesn't directly use
 	// this (and should never use it) but this feature is used by other projects built on top of it
 	SkipLabelNameValidation bool `yaml:"-"`
@@ -449,7 +461,14 @@ func New(cfg Config, clientConfig ingester_client.Config, limits *validation.Ove
 			return nil, err
 		}
 
-		subservices = append(subservices, distributorsLifecycler, distributorsRing)
+		if cfg.DistributorClientFactory == nil {
+			cfg.DistributorClientFactory = ring_client.PoolInstFunc(func(inst ring.InstanceDesc) (ring_client.PoolClient, error) {
+				return dialDistributorClient(clientConfig.GRPCClientConfig, inst)
+			})
+		}
+		d.distributorPool = newDistributorPool(cfg.PoolConfig, distributorsRing, cfg.DistributorClientFactory, reg, log)
+
+		subservices = append(subservices, distributorsLifecycler, distributorsRing, d.distributorPool)
 		requestRateStrategy = newGlobalRateStrategy(newRequestRateStrategy(limits), d)
 		ingestionRateStrategy = newGlobalRateStrategyWithBurstFactor(limits, d)
 	}
@@ -463,9 +482,10 @@ func New(cfg Config, clientConfig ingester_client.Config, limits *validation.Ove
 	d.activeUsers = util.NewActiveUsersCleanupWithDefaultValues(d.cleanupInactiveUser)
 	d.activeGroups = activeGroupsCleanupService
 
+	d.streamAggregator = newStreamAggregator(limits, cfg.DistributorRing.Common.InstanceID, reg, log)
 	d.PushWithMiddlewares = d.wrapPushWithMiddlewares(d.push)
 
-	subservices = append(subservices, d.ingesterPool, d.activeUsers)
+	subservices = append(subservices, d.ingesterPool, d.activeUsers, d.streamAggregator)
 
 	if cfg.ReusableIngesterPushWorkers > 0 {
 		wp := concurrency.NewReusableGoroutinesPool(cfg.ReusableIngesterPushWorkers)
@@ -588,6 +608,8 @@ func (d *Distributor) cleanupInactiveUser(userID string) {
 	d.sampleValidationMetrics.deleteUserMetrics(userID)
 	d.exemplarValidationMetrics.deleteUserMetrics(userID)
 	d.metadataValidationMetrics.deleteUserMetrics(userID)
+
+	d.streamAggregator.cleanupUserMetrics(userID)
 }
 
 func (d *Distributor) RemoveGroupMetricsForUser(userID, group string) {
@@ -696,6 +718,7 @@ func (d *Distributor) wrapPushWithMiddlewares(next PushFunc) PushFunc {
 	middlewares = append(middlewares, d.prePushRelabelMiddleware)
 	middlewares = append(middlewares, d.prePushSortAndFilterMiddleware)
 	middlewares = append(middlewares, d.prePushValidationMiddleware)
+	middlewares = append(middlewares, d.prePushStreamAggregationMiddleware)
 	middlewares = append(middlewares, d.cfg.PushWrappers...)
 
 	for ix := len(middlewares) - 1; ix >= 0; ix-- {
@@ -948,6 +971,11 @@ func (d *Distributor) prePushValidationMiddleware(next PushFunc) PushFunc {
 		// Enforce the creation grace period on exemplars too.
 		maxExemplarTS := now.Add(d.limits.CreationGracePeriod(userID)).UnixMilli()
 
+		capture := d.pushCaptures.get(userID)
+		if capture != nil && capture.expired(now) {
+			capture = nil
+		}
+
 		var firstPartialErr error
 		var removeIndexes []int
 		for tsIdx, ts := range req.Timeseries {
@@ -958,10 +986,17 @@ func (d *Distributor) prePushValidationMiddleware(next PushFunc) PushFunc {
 
 			d.labelsHistogram.Observe(float64(len(ts.Labels)))
 
+			// The received data is counted before validateSeries drops some of it.
+			samples, histograms, exemplars := len(ts.Samples), len(ts.Histograms), len(ts.Exemplars)
+
 			skipLabelNameValidation := d.cfg.SkipLabelNameValidation || req.GetSkipLabelNameValidation()
 			// Note that validateSeries may drop some data in ts.
 			validationErr := d.validateSeries(now, &req.Timeseries[tsIdx], userID, group, skipLabelNameValidation, minExemplarTS, maxExemplarTS)
 
+			if capture != nil {
+				capture.record(now, ts.Labels, samples, histograms, exemplars, validationErr)
+			}
+
 			// Errors in validation are considered non-fatal, as one series in a request may contain
 			// invalid data but all the remaining series could be perfectly valid.
 			if validationErr != nil {
@@ -1079,7 +1114,12 @@ func (d *Distributor) metricsMiddleware(next PushFunc) PushFunc {
 
 type ctxKey int
 
-const requestStateKey ctxKey = 1
+const (
+	requestStateKey ctxKey = 1
+
+	// remoteWrite2StatsKey holds the *remoteWrite2Stats of a remote write 2.0 request.
+	remoteWrite2StatsKey ctxKey = 2
+)
 
 // requestState represents state of checks for given request. If this object is stored in context,
 // it means that request has been checked against inflight requests limit, and FinishPushRequest,
@@ -2384,6 +2424,110 @@ func (d *Distributor) UserStats(ctx context.Context, countMethod cardinality.Cou
 	return totalStats, nil
 }
 
+// CostAttribution returns the active series of the tenant, grouped by the values of the cost attribution label of
+// the tenant. The active series of each value are de-duplicated for the replication factor like the ones returned
+// by UserStats. Each ingester admits the values within the bound on a first-come basis, so the series with the same
+// value can be attributed to the value by some ingesters and to the overflow value by others.
+func (d *Distributor) CostAttribution(ctx context.Context) (*CostAttribution, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	replicationSets, err := d.getIngesterReplicationSetsForQuery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	type zonedCostAttributionResponse struct {
+		zone string
+		resp *ingester_client.CostAttributionResponse
+	}
+
+	// When ingest storage is disabled, if ingesters are running in a single zone we can't tolerate any errors.
+	// In this case we expect exactly 1 replication set.
+	if !d.cfg.IngestStorageConfig.Enabled && len(replicationSets) == 1 && replicationSets[0].ZoneCount() == 1 {
+		replicationSets[0].MaxErrors = 0
+	}
+
+	var (
+		req                       = &ingester_client.CostAttributionRequest{}
+		quorumConfig              = d.queryQuorumConfigForReplicationSets(ctx, replicationSets)
+		responsesByReplicationSet = make([][]zonedCostAttributionResponse, len(replicationSets))
+	)
+
+	err = concurrency.ForEachJob(ctx, len(replicationSets), 0, func(ctx context.Context, replicationSetIdx int) error {
+		replicationSet := replicationSets[replicationSetIdx]
+
+		resps, err := ring.DoUntilQuorum[zonedCostAttributionResponse](ctx, replicationSet, quorumConfig, func(ctx context.Context, desc *ring.InstanceDesc) (zonedCostAttributionResponse, error) {
+			poolClient, err := d.ingesterPool.GetClientForInstance(*desc)
+			if err != nil {
+				return zonedCostAttributionResponse{}, err
+			}
+
+			client := poolClient.(ingester_client.IngesterClient)
+			resp, err := client.CostAttribution(ctx, req)
+			if err != nil {
+				return zonedCostAttributionResponse{}, err
+			}
+
+			return zonedCostAttributionResponse{zone: desc.Zone, resp: resp}, nil
+		}, func(zonedCostAttributionResponse) {})
+
+		if err != nil {
+			return err
+		}
+
+		// Each goroutine accesses a different index, so there's no need to lock.
+		responsesByReplicationSet[replicationSetIdx] = resps
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	activeByValue := map[string]uint64{}
+	for replicationSetIdx, resps := range responsesByReplicationSet {
+		// Collect responses by value and zone.
+		zoneActiveByValue := map[string]map[string]uint64{}
+		for _, r := range resps {
+			for _, v := range r.resp.Values {
+				if zoneActiveByValue[v.Value] == nil {
+					zoneActiveByValue[v.Value] = map[string]uint64{}
+				}
+				zoneActiveByValue[v.Value][r.zone] += v.ActiveSeries
+			}
+		}
+
+		// When the ingest storage is enabled a partition is owned by only 1 ingester per zone,
+		// so regardless the number of zones we have it's behaving like multi-zone is always enabled.
+		isMultiZone := d.cfg.IngestStorageConfig.Enabled || replicationSets[replicationSetIdx].ZoneCount() > 1
+
+		for value, zoneActive := range zoneActiveByValue {
+			activeByValue[value] += approximateFromZones(isMultiZone, d.ingestersRing.ReplicationFactor(), zoneActive)
+		}
+	}
+
+	result := &CostAttribution{Label: d.limits.CostAttributionLabel(tenantID), Values: []CostAttributionValue{}}
+	for value, active := range activeByValue {
+		if active > 0 {
+			result.Values = append(result.Values, CostAttributionValue{Value: value, ActiveSeries: active})
+		}
+	}
+	slices.SortFunc(result.Values, func(a, b CostAttributionValue) int {
+		if a.ActiveSeries != b.ActiveSeries {
+			if a.ActiveSeries > b.ActiveSeries {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Value, b.Value)
+	})
+	return result, nil
+}
+
 // UserIDStats models ingestion statistics for one user, including the user ID
 type UserIDStats struct {
 	UserID string `json:"userID"`
@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/grpcclient"
+	"github.com/grafana/dskit/httpgrpc"
+	"github.com/grafana/dskit/ring"
+	ring_client "github.com/grafana/dskit/ring/client"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// distributorClient is the client used to forward to the other distributors the HTTP requests which must be
+// handled by all of them, over HTTP over gRPC.
+type distributorClient struct {
+	httpgrpc.HTTPClient
+	grpc_health_v1.HealthClient
+	conn *grpc.ClientConn
+}
+
+func (c *distributorClient) Close() error {
+	return c.conn.Close()
+}
+
+func (c *distributorClient) String() string {
+	return c.conn.Target()
+}
+
+func dialDistributorClient(clientCfg grpcclient.Config, inst ring.InstanceDesc) (*distributorClient, error) {
+	opts, err := clientCfg.DialOption(nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := grpc.Dial(inst.Addr, opts...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to dial distributor %s %s", inst.Id, inst.Addr)
+	}
+
+	return &distributorClient{
+		HTTPClient:   httpgrpc.NewHTTPClient(conn),
+		HealthClient: grpc_health_v1.NewHealthClient(conn),
+		conn:         conn,
+	}, nil
+}
+
+func newDistributorPool(cfg PoolConfig, distributorsRing ring.ReadRing, factory ring_client.PoolFactory, reg prometheus.Registerer, logger log.Logger) *ring_client.Pool {
+	poolCfg := ring_client.PoolConfig{
+		CheckInterval:      cfg.ClientCleanupPeriod,
+		HealthCheckEnabled: true,
+		HealthCheckTimeout: cfg.RemoteTimeout,
+	}
+
+	clientsCount := promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+		Name: "cortex_distributor_distributor_clients",
+		Help: "The current number of distributor clients.",
+	})
+
+	return ring_client.NewPool("distributor", poolCfg, ring_client.NewRingServiceDiscovery(distributorsRing), factory, clientsCount, logger)
+}
@@ -13,6 +13,7 @@ import (
 	"math"
 	"math/rand"
 	"net/http"
+	"net/http/httptest"
 	"sort"
 	"strconv"
 	"strings"
@@ -2394,6 +2395,73 @@ func TestDistributor_ActiveSeries(t *testing.T) {
 
 }
 
+func TestDistributor_CostAttribution(t *testing.T) {
+	pushedSeries := []labels.Labels{
+		labels.FromStrings(labels.MetricName, "test_1", "team", "a"),
+		labels.FromStrings(labels.MetricName, "test_2", "team", "a"),
+		labels.FromStrings(labels.MetricName, "test_1", "team", "b"),
+		labels.FromStrings(labels.MetricName, "test_1"),
+	}
+
+	tests := map[string]struct {
+		ingesterStateByZone map[string]ingesterZoneState
+	}{
+		"single zone": {
+			ingesterStateByZone: map[string]ingesterZoneState{
+				"single-zone": {numIngesters: 3, happyIngesters: 3},
+			},
+		},
+		"multi zone": {
+			ingesterStateByZone: map[string]ingesterZoneState{
+				"zone-a": {numIngesters: 1, happyIngesters: 1},
+				"zone-b": {numIngesters: 1, happyIngesters: 1},
+				"zone-c": {numIngesters: 1, happyIngesters: 1},
+			},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			limits := prepareDefaultLimits()
+			limits.CostAttributionLabel = "team"
+			distributors, ingesters, _, _ := prepare(t, prepConfig{
+				ingesterStateByZone: test.ingesterStateByZone,
+				numDistributors:     1,
+				replicationFactor:   3,
+				limits:              limits,
+			})
+			d := distributors[0]
+
+			ctx := user.InjectOrgID(context.Background(), "test")
+			for _, series := range pushedSeries {
+				_, err := d.Push(ctx, mockWriteRequest(series, 1, 100000))
+				require.NoError(t, err)
+			}
+
+			// The series are replicated to all the ingesters, and are counted only once.
+			ca, err := d.CostAttribution(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, &CostAttribution{Label: "team", Values: []CostAttributionValue{
+				{Value: "a", ActiveSeries: 2},
+				{Value: "", ActiveSeries: 1},
+				{Value: "b", ActiveSeries: 1},
+			}}, ca)
+			// Check that the correct number of ingesters have been queried.
+			assert.Contains(t, []int{3, 2}, countMockIngestersCalled(ingesters, "CostAttribution"))
+
+			rec := httptest.NewRecorder()
+			d.CostAttributionHandler(rec, httptest.NewRequest(http.MethodGet, "/distributor/cost_attribution", nil).WithContext(ctx))
+			require.Equal(t, http.StatusOK, rec.Code)
+			assert.JSONEq(t, `{"label":"team","values":[{"value":"a","active_series":2},{"value":"","active_series":1},{"value":"b","active_series":1}]}`, rec.Body.String())
+
+			// The requests without tenant are rejected.
+			rec = httptest.NewRecorder()
+			d.CostAttributionHandler(rec, httptest.NewRequest(http.MethodGet, "/distributor/cost_attribution", nil))
+			assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		})
+	}
+}
+
 func BenchmarkDistributor_ActiveSeries(b *testing.B) {
 	const numIngesters = 3
 	const numSeries = 10e3
@@ -4249,7 +4317,7 @@ func prepareIngesterZone(t testing.TB, zone string, state ingesterZoneState, cfg
 			kafkaCfg.LastProducedOffsetPollInterval = 100 * time.Millisecond
 			kafkaCfg.LastProducedOffsetRetryTimeout = 100 * time.Millisecond
 
-			ingester.partitionReader, err = ingest.NewPartitionReaderForPusher(kafkaCfg, ingester.partitionID(), ingester.instanceID(), newMockIngesterPusherAdapter(ingester), log.NewNopLogger(), nil)
+			ingester.partitionReader, err = ingest.NewPartitionReaderForPusher(kafkaCfg, ingest.DeadLetterConfig{}, ingester.partitionID(), ingester.instanceID(), newMockIngesterPusherAdapter(ingester), log.NewNopLogger(), nil)
 			require.NoError(t, err)
 
 			// We start it async, and then we wait until running in a defer so that multiple partition
@@ -5596,6 +5664,33 @@ func (i *mockIngester) AllUserStats(context.Context, *client.UserStatsRequest, .
 	return &i.stats, nil
 }
 
+func (i *mockIngester) CostAttribution(ctx context.Context, _ *client.CostAttributionRequest, _ ...grpc.CallOption) (*client.CostAttributionResponse, error) {
+	if err := i.enforceReadConsistency(ctx); err != nil {
+		return nil, err
+	}
+
+	i.Lock()
+	defer i.Unlock()
+
+	i.trackCall("CostAttribution")
+
+	if !i.happy {
+		return nil, errFail
+	}
+
+	// The series are attributed to the values of the "team" label, without bound.
+	activeByValue := map[string]uint64{}
+	for _, ts := range i.timeseries {
+		activeByValue[mimirpb.FromLabelAdaptersToLabels(ts.Labels).Get("team")]++
+	}
+
+	resp := &client.CostAttributionResponse{Label: "team"}
+	for value, active := range activeByValue {
+		resp.Values = append(resp.Values, client.CostAttributionValue{Value: value, ActiveSeries: active})
+	}
+	return resp, nil
+}
+
 func (i *mockIngester) UserStats(ctx context.Context, _ *client.UserStatsRequest, _ ...grpc.CallOption) (*client.UserStatsResponse, error) {
 	if err := i.enforceReadConsistency(ctx); err != nil {
 		return nil, err
@@ -11,6 +11,7 @@ import (
 	"flag"
 	"fmt"
 	"math/rand"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -33,6 +34,7 @@ var (
 	errNegativeUpdateTimeoutJitterMax = errors.New("HA tracker max update timeout jitter shouldn't be negative")
 	errInvalidFailoverTimeout         = "HA Tracker failover timeout (%v) must be at least 1s greater than update timeout - max jitter (%v)"
 	errMemberlistUnsupported          = errors.New("memberlist is not supported by the HA tracker since gossip propagation is too slow for HA purposes")
+	errUnknownHACluster               = errors.New("unknown HA cluster")
 )
 
 type haTrackerLimits interface {
@@ -272,6 +274,14 @@ const (
 	// If we have received last sample for given cluster before this timeout, we will mark selected replica for deletion.
 	// If selected replica is marked for deletion for this time, it is deleted completely.
 	deletionTimeout = 30 * time.Minute
+
+	// Maximum number of elections kept in the KV store entry of each cluster.
+	haTrackerMaxElectionHistory = 20
+
+	// Reasons of the elections of a replica.
+	haElectionReasonInitial  = "initial"  // First elected replica known by the distributor.
+	haElectionReasonFailover = "failover" // The elected replica changed after the failover timeout expired.
+	haElectionReasonForced   = "forced"   // The elected replica has been forced through the failover endpoint.
 )
 
 func (h *haTracker) updateKVLoop(ctx context.Context) {
@@ -467,28 +477,73 @@ func (h *haTracker) updateCache(userID, cluster string, desc *ReplicaDesc) {
 	h.electedReplicaTimestamp.WithLabelValues(userID, cluster).Set(float64(desc.ReceivedAt / 1000))
 }
 
+// newReplicaDesc returns the KV store entry electing the replica at now, replacing the previous entry, if any.
+// The elections of the previous entry are kept, and the election of the replica is added if it wasn't elected
+// already, so that the history of the cluster is shared by all the distributors and survives their restarts.
+func newReplicaDesc(prev *ReplicaDesc, replica string, now time.Time, reason string) *ReplicaDesc {
+	desc := &ReplicaDesc{
+		Replica:    replica,
+		ReceivedAt: timestamp.FromTime(now),
+		DeletedAt:  0,
+	}
+	if prev == nil {
+		desc.Elections = []ReplicaElection{{Replica: replica, ElectedAt: desc.ReceivedAt, Reason: haElectionReasonInitial}}
+		return desc
+	}
+
+	desc.Elections = prev.Elections
+	if prev.Replica != replica {
+		desc.Elections = append(slices.Clone(prev.Elections), ReplicaElection{
+			Replica:         replica,
+			PreviousReplica: prev.Replica,
+			ElectedAt:       desc.ReceivedAt,
+			Reason:          reason,
+		})
+		if len(desc.Elections) > haTrackerMaxElectionHistory {
+			desc.Elections = desc.Elections[len(desc.Elections)-haTrackerMaxElectionHistory:]
+		}
+	}
+	return desc
+}
+
+// forceFailover elects the replica for the cluster in the KV store, without waiting for the failover
+// timeout to expire. It returns errUnknownHACluster if the cluster isn't known by the HA tracker.
+func (h *haTracker) forceFailover(ctx context.Context, userID, cluster, replica string, now time.Time) error {
+	h.electedLock.RLock()
+	entry := h.clusters[userID][cluster]
+	h.electedLock.RUnlock()
+	if entry == nil {
+		return errUnknownHACluster
+	}
+
+	key := fmt.Sprintf("%s/%s", userID, cluster)
+	err := h.client.CAS(ctx, key, func(in interface{}) (out interface{}, retry bool, err error) {
+		prev, _ := in.(*ReplicaDesc)
+		return newReplicaDesc(prev, replica, now, haElectionReasonForced), true, nil
+	})
+	h.kvCASCalls.WithLabelValues(userID, cluster).Inc()
+	return err
+}
+
 // If we do set the value then err will be nil and desc will contain the value we set.
 // If there is already a valid value in the store, return nil, nil.
 func (h *haTracker) updateKVStore(ctx context.Context, userID, cluster, replica string, now time.Time) error {
 	key := fmt.Sprintf("%s/%s", userID, cluster)
 	var desc *ReplicaDesc
 	err := h.client.CAS(ctx, key, func(in interface{}) (out interface{}, retry bool, err error) {
-		var ok bool
-		if desc, ok = in.(*ReplicaDesc); ok && desc.DeletedAt == 0 {
+		prev, ok := in.(*ReplicaDesc)
+		if ok && prev.DeletedAt == 0 {
 			// If the entry in KVStore is up-to-date, just stop the loop.
-			if h.withinUpdateTimeout(now, desc.ReceivedAt) ||
+			if h.withinUpdateTimeout(now, prev.ReceivedAt) ||
 				// If our replica is different, wait until the failover time.
-				desc.Replica != replica && now.Sub(timestamp.Time(desc.ReceivedAt)) < h.cfg.FailoverTimeout {
+				prev.Replica != replica && now.Sub(timestamp.Time(prev.ReceivedAt)) < h.cfg.FailoverTimeout {
+				desc = prev
 				return nil, false, nil
 			}
 		}
 
 		// Attempt to update KVStore to our timestamp and replica.
-		desc = &ReplicaDesc{
-			Replica:    replica,
-			ReceivedAt: timestamp.FromTime(now),
-			DeletedAt:  0,
-		}
+		desc = newReplicaDesc(prev, replica, now, haElectionReasonFailover)
 		return desc, true, nil
 	})
 	h.kvCASCalls.WithLabelValues(userID, cluster).Inc()
@@ -34,6 +34,8 @@ type ReplicaDesc struct {
 	// already remove entry from memory. Actual deletion from KV store does *not* trigger
 	// "watch" notification with a key for all KV stores.
 	DeletedAt int64 `protobuf:"varint,3,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	// Latest elections of the cluster, oldest first.
+	Elections []ReplicaElection `protobuf:"bytes,4,rep,name=elections,proto3" json:"elections"`
 }
 
 func (m *ReplicaDesc) Reset()      { *m = ReplicaDesc{} }
@@ -89,28 +91,111 @@ func (m *ReplicaDesc) GetDeletedAt() int64 {
 	return 0
 }
 
+func (m *ReplicaDesc) GetElections() []ReplicaElection {
+	if m != nil {
+		return m.Elections
+	}
+	return nil
+}
+
+type ReplicaElection struct {
+	Replica         string `protobuf:"bytes,1,opt,name=replica,proto3" json:"replica,omitempty"`
+	PreviousReplica string `protobuf:"bytes,2,opt,name=previous_replica,json=previousReplica,proto3" json:"previous_replica,omitempty"`
+	// Unix timestamp in milliseconds when the replica has been elected.
+	ElectedAt int64 `protobuf:"varint,3,opt,name=elected_at,json=electedAt,proto3" json:"elected_at,omitempty"`
+	// Reason of the election: initial, failover or forced.
+	Reason string `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+}
+
+func (m *ReplicaElection) Reset()      { *m = ReplicaElection{} }
+func (*ReplicaElection) ProtoMessage() {}
+func (*ReplicaElection) Descriptor() ([]byte, []int) {
+	return fileDescriptor_86f0e7bcf71d860b, []int{1}
+}
+func (m *ReplicaElection) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ReplicaElection) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ReplicaElection.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ReplicaElection) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReplicaElection.Merge(m, src)
+}
+func (m *ReplicaElection) XXX_Size() int {
+	return m.Size()
+}
+func (m *ReplicaElection) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReplicaElection.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReplicaElection proto.InternalMessageInfo
+
+func (m *ReplicaElection) GetReplica() string {
+	if m != nil {
+		return m.Replica
+	}
+	return ""
+}
+
+func (m *ReplicaElection) GetPreviousReplica() string {
+	if m != nil {
+		return m.PreviousReplica
+	}
+	return ""
+}
+
+func (m *ReplicaElection) GetElectedAt() int64 {
+	if m != nil {
+		return m.ElectedAt
+	}
+	return 0
+}
+
+func (m *ReplicaElection) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*ReplicaDesc)(nil), "distributor.ReplicaDesc")
+	proto.RegisterType((*ReplicaElection)(nil), "distributor.ReplicaElection")
 }
 
 func init() { proto.RegisterFile("ha_tracker.proto", fileDescriptor_86f0e7bcf71d860b) }
 
 var fileDescriptor_86f0e7bcf71d860b = []byte{
-	// 224 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0xc8, 0x48, 0x8c, 0x2f,
-	0x29, 0x4a, 0x4c, 0xce, 0x4e, 0x2d, 0xd2, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0xe2, 0x4e, 0xc9,
-	0x2c, 0x2e, 0x29, 0xca, 0x4c, 0x2a, 0x2d, 0xc9, 0x2f, 0x92, 0xd2, 0x4d, 0xcf, 0x2c, 0xc9, 0x28,
-	0x4d, 0xd2, 0x4b, 0xce, 0xcf, 0xd5, 0x4f, 0xcf, 0x4f, 0xcf, 0xd7, 0x07, 0xab, 0x49, 0x2a, 0x4d,
-	0x03, 0xf3, 0xc0, 0x1c, 0x30, 0x0b, 0xa2, 0x57, 0x29, 0x9d, 0x8b, 0x3b, 0x28, 0xb5, 0x20, 0x27,
-	0x33, 0x39, 0xd1, 0x25, 0xb5, 0x38, 0x59, 0x48, 0x82, 0x8b, 0xbd, 0x08, 0xc2, 0x95, 0x60, 0x54,
-	0x60, 0xd4, 0xe0, 0x0c, 0x82, 0x71, 0x85, 0xe4, 0xb9, 0xb8, 0x8b, 0x52, 0x93, 0x53, 0x33, 0xcb,
-	0x52, 0x53, 0xe2, 0x13, 0x4b, 0x24, 0x98, 0x14, 0x18, 0x35, 0x98, 0x83, 0xb8, 0x60, 0x42, 0x8e,
-	0x25, 0x42, 0xb2, 0x5c, 0x5c, 0x29, 0xa9, 0x39, 0xa9, 0x25, 0x10, 0x79, 0x66, 0xb0, 0x3c, 0x27,
-	0x54, 0xc4, 0xb1, 0xc4, 0xc9, 0xe4, 0xc2, 0x43, 0x39, 0x86, 0x1b, 0x0f, 0xe5, 0x18, 0x3e, 0x3c,
-	0x94, 0x63, 0x6c, 0x78, 0x24, 0xc7, 0xb8, 0xe2, 0x91, 0x1c, 0xe3, 0x89, 0x47, 0x72, 0x8c, 0x17,
-	0x1e, 0xc9, 0x31, 0x3e, 0x78, 0x24, 0xc7, 0xf8, 0xe2, 0x91, 0x1c, 0xc3, 0x87, 0x47, 0x72, 0x8c,
-	0x13, 0x1e, 0xcb, 0x31, 0x5c, 0x78, 0x2c, 0xc7, 0x70, 0xe3, 0xb1, 0x1c, 0x43, 0x12, 0x1b, 0xd8,
-	0x95, 0xc6, 0x80, 0x00, 0x00, 0x00, 0xff, 0xff, 0xb3, 0xd1, 0xdd, 0x8d, 0xf5, 0x00, 0x00, 0x00,
+	// 312 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x90, 0x4f, 0x4e, 0x3a, 0x31,
+	0x14, 0xc7, 0xfb, 0x80, 0xf0, 0x0b, 0x9d, 0x05, 0x64, 0x16, 0xbf, 0x4c, 0x8c, 0x3e, 0x08, 0x2b,
+	0x5c, 0x38, 0x24, 0xea, 0x01, 0x84, 0xe8, 0x05, 0xe6, 0x02, 0x64, 0xa6, 0x3c, 0xa1, 0x11, 0x29,
+	0xe9, 0x74, 0x58, 0x7b, 0x01, 0x13, 0x8f, 0xa1, 0x37, 0x61, 0xc9, 0x92, 0x95, 0x91, 0xb2, 0x71,
+	0xc9, 0x11, 0x8c, 0xa5, 0x13, 0xff, 0x2c, 0xdc, 0xf5, 0xfb, 0x7d, 0x9f, 0xd7, 0x7e, 0x52, 0xde,
+	0x9a, 0xa6, 0x23, 0xa3, 0x53, 0x71, 0x47, 0x3a, 0x5e, 0x68, 0x65, 0x54, 0x18, 0x8c, 0x65, 0x6e,
+	0xb4, 0xcc, 0x0a, 0xa3, 0xf4, 0xd1, 0xd9, 0x44, 0x9a, 0x69, 0x91, 0xc5, 0x42, 0xdd, 0xf7, 0x27,
+	0x6a, 0xa2, 0xfa, 0x8e, 0xc9, 0x8a, 0x5b, 0x97, 0x5c, 0x70, 0xa7, 0xc3, 0x6e, 0xf7, 0x05, 0x78,
+	0x90, 0xd0, 0x62, 0x26, 0x45, 0x7a, 0x4d, 0xb9, 0x08, 0x23, 0xfe, 0x4f, 0x1f, 0x62, 0x04, 0x1d,
+	0xe8, 0x35, 0x92, 0x32, 0x86, 0x6d, 0x1e, 0x68, 0x12, 0x24, 0x97, 0x34, 0x1e, 0xa5, 0x26, 0xaa,
+	0x74, 0xa0, 0x57, 0x4d, 0x78, 0x59, 0x0d, 0x4c, 0x78, 0xc2, 0xf9, 0x98, 0x66, 0x64, 0x0e, 0xf3,
+	0xaa, 0x9b, 0x37, 0x7c, 0x33, 0x30, 0xe1, 0x15, 0x6f, 0xd0, 0x8c, 0x84, 0x91, 0x6a, 0x9e, 0x47,
+	0xb5, 0x4e, 0xb5, 0x17, 0x9c, 0x1f, 0xc7, 0xdf, 0xcc, 0x63, 0xaf, 0x71, 0xe3, 0xa1, 0x61, 0x6d,
+	0xf5, 0xda, 0x66, 0xc9, 0xd7, 0x52, 0xf7, 0x11, 0x78, 0xf3, 0x17, 0xf4, 0x87, 0xef, 0x29, 0x6f,
+	0x2d, 0x34, 0x2d, 0xa5, 0x2a, 0xf2, 0x51, 0x89, 0x54, 0x1c, 0xd2, 0x2c, 0x7b, 0x7f, 0xd9, 0xa7,
+	0xb9, 0x7b, 0xe5, 0x87, 0xb9, 0x6f, 0x06, 0x26, 0xfc, 0xcf, 0xeb, 0x9a, 0xd2, 0x5c, 0xcd, 0xa3,
+	0x9a, 0xdb, 0xf7, 0x69, 0x78, 0xb9, 0xde, 0x22, 0xdb, 0x6c, 0x91, 0xed, 0xb7, 0x08, 0x0f, 0x16,
+	0xe1, 0xd9, 0x22, 0xac, 0x2c, 0xc2, 0xda, 0x22, 0xbc, 0x59, 0x84, 0x77, 0x8b, 0x6c, 0x6f, 0x11,
+	0x9e, 0x76, 0xc8, 0xd6, 0x3b, 0x64, 0x9b, 0x1d, 0xb2, 0xac, 0xee, 0x3e, 0xfe, 0xe2, 0x63, 0x00,
+	0x42, 0x14, 0xde, 0x3d, 0xc8, 0x01, 0x00, 0x00,
 }
 
 func (this *ReplicaDesc) Equal(that interface{}) bool {
@@ -141,17 +226,78 @@ func (this *ReplicaDesc) Equal(that interface{}) bool {
 	if this.DeletedAt != that1.DeletedAt {
 		return false
 	}
+	if len(this.Elections) != len(that1.Elections) {
+		return false
+	}
+	for i := range this.Elections {
+		if !this.Elections[i].Equal(&that1.Elections[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *ReplicaElection) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*ReplicaElection)
+	if !ok {
+		that2, ok := that.(ReplicaElection)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Replica != that1.Replica {
+		return false
+	}
+	if this.PreviousReplica != that1.PreviousReplica {
+		return false
+	}
+	if this.ElectedAt != that1.ElectedAt {
+		return false
+	}
+	if this.Reason != that1.Reason {
+		return false
+	}
 	return true
 }
 func (this *ReplicaDesc) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 7)
+	s := make([]string, 0, 8)
 	s = append(s, "&distributor.ReplicaDesc{")
 	s = append(s, "Replica: "+fmt.Sprintf("%#v", this.Replica)+",\n")
 	s = append(s, "ReceivedAt: "+fmt.Sprintf("%#v", this.ReceivedAt)+",\n")
 	s = append(s, "DeletedAt: "+fmt.Sprintf("%#v", this.DeletedAt)+",\n")
+	if this.Elections != nil {
+		vs := make([]ReplicaElection, len(this.Elections))
+		for i := range vs {
+			vs[i] = this.Elections[i]
+		}
+		s = append(s, "Elections: "+fmt.Sprintf("%#v", vs)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *ReplicaElection) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 8)
+	s = append(s, "&distributor.ReplicaElection{")
+	s = append(s, "Replica: "+fmt.Sprintf("%#v", this.Replica)+",\n")
+	s = append(s, "PreviousReplica: "+fmt.Sprintf("%#v", this.PreviousReplica)+",\n")
+	s = append(s, "ElectedAt: "+fmt.Sprintf("%#v", this.ElectedAt)+",\n")
+	s = append(s, "Reason: "+fmt.Sprintf("%#v", this.Reason)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -183,6 +329,20 @@ func (m *ReplicaDesc) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Elections) > 0 {
+		for iNdEx := len(m.Elections) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Elections[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintHaTracker(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x22
+		}
+	}
 	if m.DeletedAt != 0 {
 		i = encodeVarintHaTracker(dAtA, i, uint64(m.DeletedAt))
 		i--
@@ -203,6 +363,55 @@ func (m *ReplicaDesc) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *ReplicaElection) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ReplicaElection) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ReplicaElection) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Reason) > 0 {
+		i -= len(m.Reason)
+		copy(dAtA[i:], m.Reason)
+		i = encodeVarintHaTracker(dAtA, i, uint64(len(m.Reason)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.ElectedAt != 0 {
+		i = encodeVarintHaTracker(dAtA, i, uint64(m.ElectedAt))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.PreviousReplica) > 0 {
+		i -= len(m.PreviousReplica)
+		copy(dAtA[i:], m.PreviousReplica)
+		i = encodeVarintHaTracker(dAtA, i, uint64(len(m.PreviousReplica)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Replica) > 0 {
+		i -= len(m.Replica)
+		copy(dAtA[i:], m.Replica)
+		i = encodeVarintHaTracker(dAtA, i, uint64(len(m.Replica)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintHaTracker(dAtA []byte, offset int, v uint64) int {
 	offset -= sovHaTracker(v)
 	base := offset
@@ -230,6 +439,36 @@ func (m *ReplicaDesc) Size() (n int) {
 	if m.DeletedAt != 0 {
 		n += 1 + sovHaTracker(uint64(m.DeletedAt))
 	}
+	if len(m.Elections) > 0 {
+		for _, e := range m.Elections {
+			l = e.Size()
+			n += 1 + l + sovHaTracker(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *ReplicaElection) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Replica)
+	if l > 0 {
+		n += 1 + l + sovHaTracker(uint64(l))
+	}
+	l = len(m.PreviousReplica)
+	if l > 0 {
+		n += 1 + l + sovHaTracker(uint64(l))
+	}
+	if m.ElectedAt != 0 {
+		n += 1 + sovHaTracker(uint64(m.ElectedAt))
+	}
+	l = len(m.Reason)
+	if l > 0 {
+		n += 1 + l + sovHaTracker(uint64(l))
+	}
 	return n
 }
 
@@ -243,10 +482,29 @@ func (this *ReplicaDesc) String() string {
 	if this == nil {
 		return "nil"
 	}
+	repeatedStringForElections := "[]ReplicaElection{"
+	for _, f := range this.Elections {
+		repeatedStringForElections += strings.Replace(strings.Replace(f.String(), "ReplicaElection", "ReplicaElection", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForElections += "}"
 	s := strings.Join([]string{`&ReplicaDesc{`,
 		`Replica:` + fmt.Sprintf("%v", this.Replica) + `,`,
 		`ReceivedAt:` + fmt.Sprintf("%v", this.ReceivedAt) + `,`,
 		`DeletedAt:` + fmt.Sprintf("%v", this.DeletedAt) + `,`,
+		`Elections:` + repeatedStringForElections + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ReplicaElection) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ReplicaElection{`,
+		`Replica:` + fmt.Sprintf("%v", this.Replica) + `,`,
+		`PreviousReplica:` + fmt.Sprintf("%v", this.PreviousReplica) + `,`,
+		`ElectedAt:` + fmt.Sprintf("%v", this.ElectedAt) + `,`,
+		`Reason:` + fmt.Sprintf("%v", this.Reason) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -358,6 +616,205 @@ func (m *ReplicaDesc) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Elections", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHaTracker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthHaTracker
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthHaTracker
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Elections = append(m.Elections, ReplicaElection{})
+			if err := m.Elections[len(m.Elections)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipHaTracker(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthHaTracker
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ReplicaElection) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowHaTracker
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ReplicaElection: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ReplicaElection: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Replica", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHaTracker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHaTracker
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthHaTracker
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Replica = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PreviousReplica", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHaTracker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHaTracker
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthHaTracker
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PreviousReplica = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ElectedAt", wireType)
+			}
+			m.ElectedAt = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHaTracker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ElectedAt |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Reason", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowHaTracker
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthHaTracker
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthHaTracker
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Reason = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipHaTracker(dAtA[iNdEx:])
@@ -7,11 +7,14 @@ package distributor
 
 import (
 	_ "embed" // Used to embed html template
+	"errors"
 	"html/template"
 	"net/http"
 	"sort"
 	"time"
 
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/tenant"
 	"github.com/prometheus/prometheus/model/timestamp"
 
 	"github.com/grafana/mimir/pkg/util"
@@ -26,6 +29,23 @@ type haTrackerStatusPageContents struct {
 	Now     time.Time          `json:"now"`
 }
 
+type haTrackerHistoryResponse struct {
+	Clusters []haTrackerClusterHistory `json:"clusters"`
+}
+
+type haTrackerClusterHistory struct {
+	Cluster   string              `json:"cluster"`
+	Replica   string              `json:"replica"`
+	Elections []haTrackerElection `json:"elections"`
+}
+
+type haTrackerElection struct {
+	Replica         string    `json:"replica"`
+	PreviousReplica string    `json:"previousReplica,omitempty"`
+	ElectedAt       time.Time `json:"electedAt"`
+	Reason          string    `json:"reason"`
+}
+
 type haTrackerReplica struct {
 	UserID       string        `json:"userID"`
 	Cluster      string        `json:"cluster"`
@@ -69,3 +89,85 @@ func (h *haTracker) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		Now:     time.Now(),
 	}, haTrackerStatusPageTemplate, req)
 }
+
+// ElectionHistoryHandler returns the history of the elected replicas of the HA clusters of the tenant,
+// as stored in the KV store. The clusters can be filtered with the cluster parameter.
+func (h *haTracker) ElectionHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := tenant.TenantID(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	clusterFilter := r.FormValue("cluster")
+
+	clusters := []haTrackerClusterHistory{}
+	h.electedLock.RLock()
+	for cluster, entry := range h.clusters[userID] {
+		if clusterFilter != "" && cluster != clusterFilter {
+			continue
+		}
+		elections := make([]haTrackerElection, 0, len(entry.elected.Elections))
+		for _, e := range entry.elected.Elections {
+			elections = append(elections, haTrackerElection{
+				Replica:         e.Replica,
+				PreviousReplica: e.PreviousReplica,
+				ElectedAt:       timestamp.Time(e.ElectedAt),
+				Reason:          e.Reason,
+			})
+		}
+		clusters = append(clusters, haTrackerClusterHistory{
+			Cluster:   cluster,
+			Replica:   entry.elected.Replica,
+			Elections: elections,
+		})
+	}
+	h.electedLock.RUnlock()
+
+	sort.Slice(clusters, func(i, j int) bool {
+		return clusters[i].Cluster < clusters[j].Cluster
+	})
+
+	util.WriteJSONResponse(w, haTrackerHistoryResponse{Clusters: clusters})
+}
+
+// ForceFailoverHandler elects the replica parameter for the cluster parameter of the tenant parameter in the KV store,
+// without waiting for the failover timeout of the currently elected replica to expire.
+func (h *haTracker) ForceFailoverHandler(w http.ResponseWriter, r *http.Request) {
+	if !h.cfg.EnableHATracker {
+		http.Error(w, "HA tracker is not enabled", http.StatusBadRequest)
+		return
+	}
+
+	userID := r.FormValue("tenant")
+	if userID == "" {
+		http.Error(w, "missing tenant parameter", http.StatusBadRequest)
+		return
+	}
+
+	cluster := r.FormValue("cluster")
+	if cluster == "" {
+		http.Error(w, "missing cluster parameter", http.StatusBadRequest)
+		return
+	}
+	replica := r.FormValue("replica")
+	if replica == "" {
+		http.Error(w, "missing replica parameter", http.StatusBadRequest)
+		return
+	}
+
+	err := h.forceFailover(r.Context(), userID, cluster, replica, time.Now())
+	if errors.Is(err, errUnknownHACluster) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		level.Error(h.logger).Log("msg", "failed to force HA tracker failover", "user", userID, "cluster", cluster, "replica", replica, "err", err)
+
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	level.Info(h.logger).Log("msg", "forced HA tracker failover", "user", userID, "cluster", cluster, "replica", replica)
+
+	w.WriteHeader(http.StatusOK)
+}
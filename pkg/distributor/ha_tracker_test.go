@@ -7,7 +7,11 @@ package distributor
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
@@ -870,3 +874,100 @@ func getSumOfHistogramSampleCount(families []*dto.MetricFamily, metricName strin
 
 	return sum
 }
+
+func TestHATracker_ElectionHistoryAndForcedFailover(t *testing.T) {
+	kvStore, closer := consul.NewInMemoryClient(GetReplicaDescCodec(), log.NewNopLogger(), nil)
+	t.Cleanup(func() { assert.NoError(t, closer.Close()) })
+
+	c, err := newHATracker(HATrackerConfig{
+		EnableHATracker:        true,
+		KVStore:                kv.Config{Mock: kv.PrefixClient(kvStore, "prefix")},
+		UpdateTimeout:          time.Second,
+		UpdateTimeoutJitterMax: 0,
+		FailoverTimeout:        10 * time.Second,
+	}, trackerLimits{maxClusters: 100}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), c))
+	defer services.StopAndAwaitTerminated(context.Background(), c) //nolint:errcheck
+
+	now := time.Now()
+	require.NoError(t, c.checkReplica(context.Background(), "user", "cluster", "r1", now))
+
+	// Force the failover to r2 before the failover timeout expires.
+	now = now.Add(2 * time.Second)
+	require.NoError(t, c.forceFailover(context.Background(), "user", "cluster", "r2", now))
+	checkReplicaTimestamp(t, time.Second, c, "user", "cluster", "r2", now)
+	require.NoError(t, c.checkReplica(context.Background(), "user", "cluster", "r2", now))
+	require.Error(t, c.checkReplica(context.Background(), "user", "cluster", "r1", now))
+
+	// Fail over to r1 once the failover timeout has expired.
+	now = now.Add(11 * time.Second)
+	require.Error(t, c.checkReplica(context.Background(), "user", "cluster", "r1", now))
+	c.updateKVStoreAll(context.Background(), now)
+	checkReplicaTimestamp(t, time.Second, c, "user", "cluster", "r1", now)
+
+	// Force the failover to r2 after the failover timeout has expired too.
+	now = now.Add(11 * time.Second)
+	require.NoError(t, c.forceFailover(context.Background(), "user", "cluster", "r2", now))
+	checkReplicaTimestamp(t, time.Second, c, "user", "cluster", "r2", now)
+
+	require.ErrorIs(t, c.forceFailover(context.Background(), "user", "unknown", "r1", now), errUnknownHACluster)
+
+	// The history is stored in the KV store, so it's also returned by a distributor which didn't observe the elections.
+	other, err := newHATracker(HATrackerConfig{
+		EnableHATracker:        true,
+		KVStore:                kv.Config{Mock: kv.PrefixClient(kvStore, "prefix")},
+		UpdateTimeout:          time.Second,
+		UpdateTimeoutJitterMax: 0,
+		FailoverTimeout:        10 * time.Second,
+	}, trackerLimits{maxClusters: 100}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+	require.NoError(t, other.checkReplica(context.Background(), "user", "cluster", "r2", now))
+
+	for name, tracker := range map[string]*haTracker{"election history": c, "election history from the KV store": other} {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/distributor/ha_tracker/history", nil)
+			req = req.WithContext(user.InjectOrgID(req.Context(), "user"))
+			rec := httptest.NewRecorder()
+			tracker.ElectionHistoryHandler(rec, req)
+			require.Equal(t, http.StatusOK, rec.Code)
+
+			var resp haTrackerHistoryResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			require.Len(t, resp.Clusters, 1)
+			assert.Equal(t, "cluster", resp.Clusters[0].Cluster)
+			assert.Equal(t, "r2", resp.Clusters[0].Replica)
+
+			var reasons, replicas []string
+			for _, e := range resp.Clusters[0].Elections {
+				reasons = append(reasons, e.Reason)
+				replicas = append(replicas, e.PreviousReplica+">"+e.Replica)
+			}
+			assert.Equal(t, []string{haElectionReasonInitial, haElectionReasonForced, haElectionReasonFailover, haElectionReasonForced}, reasons)
+			assert.Equal(t, []string{">r1", "r1>r2", "r2>r1", "r1>r2"}, replicas)
+			assert.Equal(t, now.UnixMilli(), resp.Clusters[0].Elections[3].ElectedAt.UnixMilli())
+		})
+	}
+
+	t.Run("forced failover", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			form         url.Values
+			expectedCode int
+		}{
+			"missing tenant":  {form: url.Values{"cluster": {"cluster"}, "replica": {"r1"}}, expectedCode: http.StatusBadRequest},
+			"missing cluster": {form: url.Values{"tenant": {"user"}, "replica": {"r1"}}, expectedCode: http.StatusBadRequest},
+			"missing replica": {form: url.Values{"tenant": {"user"}, "cluster": {"cluster"}}, expectedCode: http.StatusBadRequest},
+			"unknown tenant":  {form: url.Values{"tenant": {"unknown"}, "cluster": {"cluster"}, "replica": {"r1"}}, expectedCode: http.StatusNotFound},
+			"unknown cluster": {form: url.Values{"tenant": {"user"}, "cluster": {"unknown"}, "replica": {"r1"}}, expectedCode: http.StatusNotFound},
+			"known cluster":   {form: url.Values{"tenant": {"user"}, "cluster": {"cluster"}, "replica": {"r1"}}, expectedCode: http.StatusOK},
+		} {
+			t.Run(name, func(t *testing.T) {
+				req := httptest.NewRequest(http.MethodPost, "/distributor/ha_tracker/failover", strings.NewReader(tc.form.Encode()))
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				rec := httptest.NewRecorder()
+				c.ForceFailoverHandler(rec, req)
+				require.Equal(t, tc.expectedCode, rec.Code, rec.Body.String())
+			})
+		}
+	})
+}
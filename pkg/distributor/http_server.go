@@ -8,6 +8,8 @@ package distributor
 import (
 	"net/http"
 
+	"github.com/grafana/dskit/tenant"
+
 	"github.com/grafana/mimir/pkg/cardinality"
 	"github.com/grafana/mimir/pkg/util"
 )
@@ -20,6 +22,18 @@ type UserStats struct {
 	RuleIngestionRate float64 `json:"RuleIngestionRate"`
 }
 
+// CostAttribution models the active series of a tenant, grouped by the values of the cost attribution label.
+type CostAttribution struct {
+	Label  string                 `json:"label"`
+	Values []CostAttributionValue `json:"values"`
+}
+
+// CostAttributionValue models the active series attributed to a value of the cost attribution label.
+type CostAttributionValue struct {
+	Value        string `json:"value"`
+	ActiveSeries uint64 `json:"active_series"`
+}
+
 // UserStatsHandler handles user stats to the Distributor.
 func (d *Distributor) UserStatsHandler(w http.ResponseWriter, r *http.Request) {
 	stats, err := d.UserStats(r.Context(), cardinality.InMemoryMethod)
@@ -30,3 +44,26 @@ func (d *Distributor) UserStatsHandler(w http.ResponseWriter, r *http.Request) {
 
 	util.WriteJSONResponse(w, stats)
 }
+
+// CostAttributionHandler returns the active series of the tenant, grouped by the values of the cost attribution
+// label of the tenant.
+func (d *Distributor) CostAttributionHandler(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := tenant.TenantID(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if d.limits.CostAttributionLabel(tenantID) == "" {
+		http.Error(w, "cost attribution is disabled for tenant "+tenantID, http.StatusNotFound)
+		return
+	}
+
+	ca, err := d.CostAttribution(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSONResponse(w, ca)
+}
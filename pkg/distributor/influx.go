@@ -0,0 +1,413 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/httpgrpc"
+	"github.com/grafana/dskit/middleware"
+	"github.com/grafana/dskit/tenant"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/common/model"
+	prometheustranslator "github.com/prometheus/prometheus/storage/remote/otlptranslator/prometheus"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/util"
+	"github.com/grafana/mimir/pkg/util/spanlogger"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+const (
+	influxParseError           = "influx_parse_error"
+	influxUnsupportedFieldType = "influx_unsupported_field_type"
+
+	// influxValueField is the name of the field which is converted to a metric named after the measurement only.
+	influxValueField = "value"
+)
+
+// errInfluxStringField is returned when parsing a field with a string value, which can't be converted to a sample.
+var errInfluxStringField = errors.New("string field values are not supported")
+
+// InfluxHandler is an http.Handler accepting InfluxDB line protocol write requests.
+//
+// Each field of a line is converted to a series, named after the measurement and the field key,
+// and labelled with the line tags.
+func InfluxHandler(
+	maxRecvMsgSize int,
+	sourceIPs *middleware.SourceIPExtractor,
+	allowSkipLabelNameValidation bool,
+	limits *validation.Overrides,
+	retryCfg RetryConfig,
+	reg prometheus.Registerer,
+	push PushFunc,
+	logger log.Logger,
+) http.Handler {
+	discardedDueToInfluxParseError := validation.DiscardedSamplesCounter(reg, influxParseError)
+	discardedDueToInfluxUnsupportedFieldType := validation.DiscardedSamplesCounter(reg, influxUnsupportedFieldType)
+
+	influxRequestsCounter := promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+		Name: "cortex_distributor_influx_requests_total",
+		Help: "The total number of InfluxDB line protocol requests that have come in to the distributor.",
+	}, []string{"user"})
+
+	h := handler(maxRecvMsgSize, sourceIPs, allowSkipLabelNameValidation, limits, retryCfg, push, logger, func(ctx context.Context, r *http.Request, maxRecvMsgSize int, buffers *util.RequestBuffers, req *mimirpb.PreallocWriteRequest, logger log.Logger) error {
+		precision, err := influxPrecision(r.URL.Query().Get("precision"))
+		if err != nil {
+			return httpgrpc.Errorf(http.StatusBadRequest, err.Error())
+		}
+
+		if r.ContentLength > int64(maxRecvMsgSize) {
+			return httpgrpc.Errorf(http.StatusRequestEntityTooLarge, distributorMaxWriteMessageSizeErr{
+				actual: int(r.ContentLength),
+				limit:  maxRecvMsgSize,
+			}.Error())
+		}
+
+		spanLogger, ctx := spanlogger.NewWithLogger(ctx, logger, "Distributor.InfluxHandler.decodeAndConvert")
+		defer spanLogger.Span.Finish()
+
+		body, err := readInfluxBody(r, maxRecvMsgSize, buffers)
+		if err != nil {
+			return err
+		}
+
+		tenantID, err := tenant.TenantID(ctx)
+		if err != nil {
+			return err
+		}
+
+		influxRequestsCounter.WithLabelValues(tenantID).Inc()
+
+		result := influxLinesToTimeseries(body, precision, time.Now())
+		if result.parseErrors > 0 {
+			discardedDueToInfluxParseError.WithLabelValues(tenantID, "").Add(float64(result.parseErrors))
+		}
+		if result.unsupportedFields > 0 {
+			discardedDueToInfluxUnsupportedFieldType.WithLabelValues(tenantID, "").Add(float64(result.unsupportedFields))
+		}
+		if result.firstErr != nil {
+			if len(result.timeseries) == 0 {
+				return httpgrpc.Errorf(http.StatusBadRequest, result.firstErr.Error())
+			}
+			level.Warn(logger).Log("msg", "InfluxDB line protocol parse error", "err", result.firstErr, "parse_errors", result.parseErrors, "unsupported_fields", result.unsupportedFields)
+		}
+
+		level.Debug(spanLogger).Log("msg", "InfluxDB line protocol to Prometheus conversion complete", "series_count", len(result.timeseries))
+
+		req.Timeseries = result.timeseries
+		return nil
+	})
+
+	// InfluxDB clients expect "204 No Content" on a successful write.
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := &headerTrackingResponseWriter{ResponseWriter: w}
+		h.ServeHTTP(rw, r)
+		if !rw.wroteHeader {
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+}
+
+func readInfluxBody(r *http.Request, maxRecvMsgSize int, buffers *util.RequestBuffers) ([]byte, error) {
+	var reader io.Reader = r.Body
+	switch contentEncoding := r.Header.Get("Content-Encoding"); contentEncoding {
+	case "gzip":
+		gzReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, errors.Wrap(err, "create gzip reader")
+		}
+		defer gzReader.Close()
+		reader = gzReader
+	case "":
+	default:
+		return nil, httpgrpc.Errorf(http.StatusUnsupportedMediaType, "unsupported compression: %s. Only \"gzip\" or no compression supported", contentEncoding)
+	}
+
+	sz := int(r.ContentLength)
+	if sz > 0 {
+		// Extra space guarantees no reallocation
+		sz += bytes.MinRead
+	}
+	buf := buffers.Get(sz)
+	if _, err := buf.ReadFrom(http.MaxBytesReader(nil, io.NopCloser(reader), int64(maxRecvMsgSize))); err != nil {
+		if util.IsRequestBodyTooLarge(err) {
+			return nil, httpgrpc.Errorf(http.StatusRequestEntityTooLarge, distributorMaxWriteMessageSizeErr{
+				actual: -1,
+				limit:  maxRecvMsgSize,
+			}.Error())
+		}
+		return nil, errors.Wrap(err, "read write request")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// influxPrecision returns the duration of a timestamp unit for the input precision query parameter.
+func influxPrecision(precision string) (time.Duration, error) {
+	switch precision {
+	case "", "n", "ns":
+		return time.Nanosecond, nil
+	case "u", "us", "µ", "µs":
+		return time.Microsecond, nil
+	case "ms":
+		return time.Millisecond, nil
+	case "s":
+		return time.Second, nil
+	case "m":
+		return time.Minute, nil
+	case "h":
+		return time.Hour, nil
+	default:
+		return 0, fmt.Errorf("unsupported precision %q, supported: [ns, us, ms, s, m, h]", precision)
+	}
+}
+
+type influxConversionResult struct {
+	timeseries []mimirpb.PreallocTimeseries
+
+	// parseErrors is the number of lines and fields which couldn't be parsed.
+	parseErrors int
+	// unsupportedFields is the number of fields whose value can't be converted to a sample.
+	unsupportedFields int
+	// firstErr is the first error encountered while parsing, if any.
+	firstErr error
+}
+
+// influxLinesToTimeseries converts the input InfluxDB line protocol lines to time series. Lines which can't be parsed
+// and fields which can't be converted are skipped, and tracked in the result.
+func influxLinesToTimeseries(body []byte, precision time.Duration, now time.Time) influxConversionResult {
+	result := influxConversionResult{timeseries: mimirpb.PreallocTimeseriesSliceFromPool()}
+	seriesIdx := map[string]int{}
+
+	// The body is copied to a string, so that the labels don't reference the request buffers.
+	for lineNum, line := range strings.Split(string(body), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		point, err := parseInfluxLine(line, precision, now)
+		if err != nil {
+			result.parseErrors++
+			if result.firstErr == nil {
+				result.firstErr = fmt.Errorf("line %d: %w", lineNum+1, err)
+			}
+			continue
+		}
+
+		for _, field := range point.fields {
+			value, err := parseInfluxFieldValue(field.value)
+			if err != nil {
+				if errors.Is(err, errInfluxStringField) {
+					result.unsupportedFields++
+				} else {
+					result.parseErrors++
+				}
+				if result.firstErr == nil {
+					result.firstErr = fmt.Errorf("line %d: field %q: %w", lineNum+1, field.key, err)
+				}
+				continue
+			}
+
+			lbls := make([]mimirpb.LabelAdapter, 0, len(point.tags)+1)
+			lbls = append(lbls, mimirpb.LabelAdapter{Name: model.MetricNameLabel, Value: influxMetricName(point.measurement, field.key)})
+			for _, tag := range point.tags {
+				lbls = append(lbls, mimirpb.LabelAdapter{Name: prometheustranslator.NormalizeLabel(tag.key), Value: tag.value})
+			}
+			slices.SortFunc(lbls, func(a, b mimirpb.LabelAdapter) int { return strings.Compare(a.Name, b.Name) })
+
+			sample := mimirpb.Sample{TimestampMs: point.timestampMs, Value: value}
+			key := mimirpb.FromLabelAdaptersToString(lbls)
+			if idx, ok := seriesIdx[key]; ok {
+				result.timeseries[idx].Samples = append(result.timeseries[idx].Samples, sample)
+				continue
+			}
+
+			seriesIdx[key] = len(result.timeseries)
+			result.timeseries = append(result.timeseries, mimirpb.PreallocTimeseries{TimeSeries: &mimirpb.TimeSeries{
+				Labels:  lbls,
+				Samples: []mimirpb.Sample{sample},
+			}})
+		}
+	}
+
+	return result
+}
+
+// influxMetricName returns the name of the metric for the input measurement and field key.
+func influxMetricName(measurement, field string) string {
+	name := measurement
+	if field != influxValueField {
+		name = measurement + "_" + field
+	}
+
+	var b strings.Builder
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_', r == ':':
+			b.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				b.WriteRune('_')
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
+
+type influxKeyValue struct {
+	key, value string
+}
+
+type influxPoint struct {
+	measurement string
+	tags        []influxKeyValue
+	fields      []influxKeyValue
+	timestampMs int64
+}
+
+// parseInfluxLine parses a single InfluxDB line protocol line. Field values are returned unparsed.
+func parseInfluxLine(line string, precision time.Duration, now time.Time) (influxPoint, error) {
+	var point influxPoint
+
+	// Quotes are only meaningful in field values.
+	seriesKey, rest := splitInfluxSection(line, false)
+	fieldSet, timestamp := splitInfluxSection(strings.TrimLeft(rest, " "), true)
+	timestamp = strings.TrimSpace(timestamp)
+
+	// Parse measurement and tags.
+	parts := splitInfluxUnescaped(seriesKey, ',', false)
+	point.measurement = unescapeInfluxKey(parts[0])
+	if point.measurement == "" {
+		return point, errors.New("missing measurement")
+	}
+	for _, part := range parts[1:] {
+		kv := splitInfluxUnescaped(part, '=', false)
+		if len(kv) != 2 || kv[0] == "" {
+			return point, fmt.Errorf("invalid tag %q", part)
+		}
+		if kv[1] == "" {
+			continue
+		}
+		point.tags = append(point.tags, influxKeyValue{key: unescapeInfluxKey(kv[0]), value: unescapeInfluxKey(kv[1])})
+	}
+
+	// Parse fields.
+	if fieldSet == "" {
+		return point, errors.New("missing fields")
+	}
+	for _, part := range splitInfluxUnescaped(fieldSet, ',', true) {
+		idx := indexInfluxUnescaped(part, '=', true)
+		if idx <= 0 || idx == len(part)-1 {
+			return point, fmt.Errorf("invalid field %q", part)
+		}
+		point.fields = append(point.fields, influxKeyValue{key: unescapeInfluxKey(part[:idx]), value: part[idx+1:]})
+	}
+
+	// Parse timestamp.
+	if timestamp == "" {
+		point.timestampMs = now.UnixMilli()
+		return point, nil
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return point, fmt.Errorf("invalid timestamp %q", timestamp)
+	}
+	if precision < time.Millisecond {
+		point.timestampMs = ts / int64(time.Millisecond/precision)
+	} else {
+		point.timestampMs = ts * int64(precision/time.Millisecond)
+	}
+
+	return point, nil
+}
+
+// parseInfluxFieldValue returns the sample value of the input field value. Booleans are converted to 0 and 1,
+// while string values are not supported.
+func parseInfluxFieldValue(value string) (float64, error) {
+	switch value {
+	case "t", "T", "true", "True", "TRUE":
+		return 1, nil
+	case "f", "F", "false", "False", "FALSE":
+		return 0, nil
+	}
+
+	switch {
+	case strings.HasPrefix(value, `"`):
+		return 0, errInfluxStringField
+	case strings.HasSuffix(value, "i"):
+		v, err := strconv.ParseInt(value[:len(value)-1], 10, 64)
+		return float64(v), err
+	case strings.HasSuffix(value, "u"):
+		v, err := strconv.ParseUint(value[:len(value)-1], 10, 64)
+		return float64(v), err
+	default:
+		return strconv.ParseFloat(value, 64)
+	}
+}
+
+// splitInfluxSection splits the input at the first unescaped space, skipping quoted strings if quotes is true.
+func splitInfluxSection(s string, quotes bool) (string, string) {
+	if idx := indexInfluxUnescaped(s, ' ', quotes); idx >= 0 {
+		return s[:idx], s[idx+1:]
+	}
+	return s, ""
+}
+
+// splitInfluxUnescaped splits the input at each unescaped separator, skipping quoted strings if quotes is true.
+func splitInfluxUnescaped(s string, sep byte, quotes bool) []string {
+	var parts []string
+	for {
+		idx := indexInfluxUnescaped(s, sep, quotes)
+		if idx < 0 {
+			return append(parts, s)
+		}
+		parts = append(parts, s[:idx])
+		s = s[idx+1:]
+	}
+}
+
+// indexInfluxUnescaped returns the index of the first unescaped separator, or -1. If quotes is true,
+// separators within quoted strings are skipped.
+func indexInfluxUnescaped(s string, sep byte, quotes bool) int {
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\':
+			// Skip the escaped character.
+			i++
+		case s[i] == '"' && quotes:
+			quoted = !quoted
+		case s[i] == sep && !quoted:
+			return i
+		}
+	}
+	return -1
+}
+
+var influxKeyUnescaper = strings.NewReplacer(`\,`, ",", `\=`, "=", `\ `, " ")
+
+// unescapeInfluxKey unescapes a measurement, tag key, tag value or field key.
+func unescapeInfluxKey(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	return influxKeyUnescaper.Replace(s)
+}
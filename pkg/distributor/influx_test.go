@@ -0,0 +1,319 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+func TestParseInfluxLine(t *testing.T) {
+	now := time.UnixMilli(1700000000000)
+
+	tests := map[string]struct {
+		line      string
+		precision time.Duration
+		expected  influxPoint
+		expectErr string
+	}{
+		"measurement, tags, fields and timestamp": {
+			line:      `cpu,host=server01,region=us-west usage_idle=98.5,usage_user=1i 1700000000123456789`,
+			precision: time.Nanosecond,
+			expected: influxPoint{
+				measurement: "cpu",
+				tags:        []influxKeyValue{{"host", "server01"}, {"region", "us-west"}},
+				fields:      []influxKeyValue{{"usage_idle", "98.5"}, {"usage_user", "1i"}},
+				timestampMs: 1700000000123,
+			},
+		},
+		"no tags and no timestamp": {
+			line:      `cpu value=1`,
+			precision: time.Nanosecond,
+			expected: influxPoint{
+				measurement: "cpu",
+				fields:      []influxKeyValue{{"value", "1"}},
+				timestampMs: now.UnixMilli(),
+			},
+		},
+		"seconds precision": {
+			line:      `cpu value=1 1700000000`,
+			precision: time.Second,
+			expected: influxPoint{
+				measurement: "cpu",
+				fields:      []influxKeyValue{{"value", "1"}},
+				timestampMs: 1700000000000,
+			},
+		},
+		"escaped characters": {
+			line:      `my\ measurement,tag\,key=tag\ value\=x field\ key="string, with \"spaces\"",other=2 1700000000000`,
+			precision: time.Millisecond,
+			expected: influxPoint{
+				measurement: "my measurement",
+				tags:        []influxKeyValue{{"tag,key", "tag value=x"}},
+				fields:      []influxKeyValue{{"field key", `"string, with \"spaces\""`}, {"other", "2"}},
+				timestampMs: 1700000000000,
+			},
+		},
+		"quotes in tag values are literal": {
+			line:      `cpu,host="a b value=1`,
+			precision: time.Nanosecond,
+			expectErr: "invalid field",
+		},
+		"missing fields": {
+			line:      `cpu,host=a`,
+			precision: time.Nanosecond,
+			expectErr: "missing fields",
+		},
+		"invalid tag": {
+			line:      `cpu,host value=1`,
+			precision: time.Nanosecond,
+			expectErr: "invalid tag",
+		},
+		"invalid field": {
+			line:      `cpu value= 1`,
+			precision: time.Nanosecond,
+			expectErr: "invalid field",
+		},
+		"invalid timestamp": {
+			line:      `cpu value=1 abc`,
+			precision: time.Nanosecond,
+			expectErr: "invalid timestamp",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			point, err := parseInfluxLine(tc.line, tc.precision, now)
+			if tc.expectErr != "" {
+				require.ErrorContains(t, err, tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, point)
+		})
+	}
+}
+
+func TestParseInfluxFieldValue(t *testing.T) {
+	for value, expected := range map[string]float64{
+		"1.5":   1.5,
+		"-2e3":  -2000,
+		"10i":   10,
+		"-10i":  -10,
+		"10u":   10,
+		"t":     1,
+		"TRUE":  1,
+		"false": 0,
+		"F":     0,
+	} {
+		actual, err := parseInfluxFieldValue(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, expected, actual, value)
+	}
+
+	_, err := parseInfluxFieldValue(`"string"`)
+	assert.ErrorIs(t, err, errInfluxStringField)
+
+	_, err = parseInfluxFieldValue("1.5i")
+	assert.Error(t, err)
+}
+
+func TestInfluxLinesToTimeseries(t *testing.T) {
+	body := strings.Join([]string{
+		`# comment`,
+		`cpu,host=a,cpu-id=0 value=1,usage.idle=0.5 1000000000`,
+		``,
+		`cpu,cpu-id=0,host=a value=2 2000000000`,
+		`cpu,host=b message="hello",value=3 3000000000`,
+		`invalid line`,
+		`1mem,host=a free=4i 4000000000`,
+	}, "\n")
+
+	result := influxLinesToTimeseries([]byte(body), time.Nanosecond, time.Now())
+	assert.Equal(t, 1, result.parseErrors)
+	assert.Equal(t, 1, result.unsupportedFields)
+	assert.ErrorContains(t, result.firstErr, "line 5: field \"message\"")
+
+	assert.Equal(t, []mimirpb.PreallocTimeseries{
+		{TimeSeries: &mimirpb.TimeSeries{
+			Labels:  []mimirpb.LabelAdapter{{Name: "__name__", Value: "cpu"}, {Name: "cpu_id", Value: "0"}, {Name: "host", Value: "a"}},
+			Samples: []mimirpb.Sample{{TimestampMs: 1000, Value: 1}, {TimestampMs: 2000, Value: 2}},
+		}},
+		{TimeSeries: &mimirpb.TimeSeries{
+			Labels:  []mimirpb.LabelAdapter{{Name: "__name__", Value: "cpu_usage_idle"}, {Name: "cpu_id", Value: "0"}, {Name: "host", Value: "a"}},
+			Samples: []mimirpb.Sample{{TimestampMs: 1000, Value: 0.5}},
+		}},
+		{TimeSeries: &mimirpb.TimeSeries{
+			Labels:  []mimirpb.LabelAdapter{{Name: "__name__", Value: "cpu"}, {Name: "host", Value: "b"}},
+			Samples: []mimirpb.Sample{{TimestampMs: 3000, Value: 3}},
+		}},
+		{TimeSeries: &mimirpb.TimeSeries{
+			Labels:  []mimirpb.LabelAdapter{{Name: "__name__", Value: "_1mem_free"}, {Name: "host", Value: "a"}},
+			Samples: []mimirpb.Sample{{TimestampMs: 4000, Value: 4}},
+		}},
+	}, result.timeseries)
+}
+
+func TestInfluxHandler(t *testing.T) {
+	tests := map[string]struct {
+		body         string
+		precision    string
+		compression  bool
+		encoding     string
+		maxMsgSize   int
+		responseCode int
+		errMessage   string
+		expectPush   bool
+		expectedSize int
+	}{
+		"valid lines": {
+			body:         "cpu,host=a value=1 1000\ncpu,host=b value=2 1000",
+			precision:    "ms",
+			maxMsgSize:   100000,
+			responseCode: http.StatusNoContent,
+			expectPush:   true,
+			expectedSize: 2,
+		},
+		"valid lines with compression": {
+			body:         "cpu,host=a value=1 1000\ncpu,host=b value=2 1000",
+			precision:    "ms",
+			compression:  true,
+			maxMsgSize:   100000,
+			responseCode: http.StatusNoContent,
+			expectPush:   true,
+			expectedSize: 2,
+		},
+		"partially invalid lines": {
+			body:         "cpu,host=a value=1 1000\ninvalid",
+			maxMsgSize:   100000,
+			responseCode: http.StatusNoContent,
+			expectPush:   true,
+			expectedSize: 1,
+		},
+		"all lines invalid": {
+			body:         "invalid\ncpu message=\"hello\"",
+			maxMsgSize:   100000,
+			responseCode: http.StatusBadRequest,
+			errMessage:   "line 1: missing fields",
+		},
+		"unsupported precision": {
+			body:         "cpu value=1",
+			precision:    "d",
+			maxMsgSize:   100000,
+			responseCode: http.StatusBadRequest,
+			errMessage:   "unsupported precision",
+		},
+		"unsupported compression": {
+			body:         "cpu value=1",
+			encoding:     "snappy",
+			maxMsgSize:   100000,
+			responseCode: http.StatusUnsupportedMediaType,
+			errMessage:   "Only \"gzip\" or no compression supported",
+		},
+		"request too big": {
+			body:         "cpu,host=a value=1 1000\ncpu,host=b value=2 1000",
+			maxMsgSize:   10,
+			responseCode: http.StatusRequestEntityTooLarge,
+			errMessage:   "the incoming push request has been rejected because its message size",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			body := []byte(tc.body)
+			if tc.compression {
+				var b bytes.Buffer
+				gz := gzip.NewWriter(&b)
+				_, err := gz.Write(body)
+				require.NoError(t, err)
+				require.NoError(t, gz.Close())
+				body = b.Bytes()
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/push/influx/write?precision="+tc.precision, bytes.NewReader(body))
+			req = req.WithContext(user.InjectOrgID(context.Background(), "test"))
+			if tc.compression {
+				req.Header.Set("Content-Encoding", "gzip")
+			}
+			if tc.encoding != "" {
+				req.Header.Set("Content-Encoding", tc.encoding)
+			}
+
+			limits, err := validation.NewOverrides(validation.Limits{}, validation.NewMockTenantLimits(map[string]*validation.Limits{}))
+			require.NoError(t, err)
+
+			pushed := false
+			pusher := func(_ context.Context, pushReq *Request) error {
+				t.Cleanup(pushReq.CleanUp)
+				request, err := pushReq.WriteRequest()
+				if err != nil {
+					return err
+				}
+				pushed = true
+				assert.Len(t, request.Timeseries, tc.expectedSize)
+				return nil
+			}
+
+			reg := prometheus.NewPedanticRegistry()
+			handler := InfluxHandler(tc.maxMsgSize, nil, false, limits, RetryConfig{}, reg, pusher, log.NewNopLogger())
+
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.responseCode, resp.Code)
+			assert.Equal(t, tc.expectPush, pushed)
+			if tc.errMessage != "" {
+				respBody, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				assert.Contains(t, string(respBody), tc.errMessage)
+			}
+		})
+	}
+
+	t.Run("should track discarded samples", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/push/influx/write", strings.NewReader("cpu value=1,message=\"hello\"\ninvalid"))
+		req = req.WithContext(user.InjectOrgID(context.Background(), "test"))
+
+		limits, err := validation.NewOverrides(validation.Limits{}, validation.NewMockTenantLimits(map[string]*validation.Limits{}))
+		require.NoError(t, err)
+
+		reg := prometheus.NewPedanticRegistry()
+		handler := InfluxHandler(100000, nil, false, limits, RetryConfig{}, reg, func(_ context.Context, pushReq *Request) error {
+			t.Cleanup(pushReq.CleanUp)
+			_, err := pushReq.WriteRequest()
+			return err
+		}, log.NewNopLogger())
+
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusNoContent, resp.Code)
+
+		assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+			# HELP cortex_discarded_samples_total The total number of samples that were discarded.
+			# TYPE cortex_discarded_samples_total counter
+			cortex_discarded_samples_total{group="",reason="influx_parse_error",user="test"} 1
+			cortex_discarded_samples_total{group="",reason="influx_unsupported_field_type",user="test"} 1
+
+			# HELP cortex_distributor_influx_requests_total The total number of InfluxDB line protocol requests that have come in to the distributor.
+			# TYPE cortex_distributor_influx_requests_total counter
+			cortex_distributor_influx_requests_total{user="test"} 1
+		`), "cortex_discarded_samples_total", "cortex_distributor_influx_requests_total"))
+	})
+}
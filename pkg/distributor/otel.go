@@ -60,6 +60,8 @@ func OTLPHandler(
 		Help: "The total number of OTLP requests that have come in to the distributor.",
 	}, []string{"user"})
 
+	deltaToCumulative := newOTelDeltaToCumulative(limits, reg)
+
 	return handler(maxRecvMsgSize, sourceIPs, allowSkipLabelNameValidation, limits, retryCfg, push, logger, func(ctx context.Context, r *http.Request, maxRecvMsgSize int, buffers *util.RequestBuffers, req *mimirpb.PreallocWriteRequest, logger log.Logger) error {
 		contentType := r.Header.Get("Content-Type")
 		contentEncoding := r.Header.Get("Content-Encoding")
@@ -157,6 +159,13 @@ func OTLPHandler(
 
 		otlpRequestsCounter.WithLabelValues(tenantID).Inc()
 
+		if limits.OTelDeltaToCumulativeEnabled(tenantID) {
+			deltaToCumulative.convert(tenantID, otlpReq.Metrics(), time.Now())
+		}
+		if attrs := limits.PromoteOTelResourceAttributes(tenantID); len(attrs) > 0 {
+			promoteOTelResourceAttributes(otlpReq.Metrics(), attrs)
+		}
+
 		metrics, err := otelMetricsToTimeseries(tenantID, addSuffixes, discardedDueToOtelParseError, logger, otlpReq.Metrics())
 		if err != nil {
 			return err
@@ -289,6 +298,74 @@ func otelMetricsToTimeseries(tenantID string, addSuffixes bool, discardedDueToOt
 	return mimirTs, nil
 }
 
+// promoteOTelResourceAttributes copies the given resource attributes to the attributes of the data points
+// of each resource, so that they're converted to labels. Data point attributes take precedence over the
+// promoted resource attributes.
+func promoteOTelResourceAttributes(md pmetric.Metrics, names []string) {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+
+		promoted := pcommon.NewMap()
+		for _, name := range names {
+			if v, ok := rm.Resource().Attributes().Get(name); ok {
+				v.CopyTo(promoted.PutEmpty(name))
+			}
+		}
+		if promoted.Len() == 0 {
+			continue
+		}
+
+		promote := func(attrs pcommon.Map) {
+			promoted.Range(func(k string, v pcommon.Value) bool {
+				if _, ok := attrs.Get(k); !ok {
+					v.CopyTo(attrs.PutEmpty(k))
+				}
+				return true
+			})
+		}
+
+		sms := rm.ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			metrics := sms.At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				forEachOTelDataPointAttributes(metrics.At(k), promote)
+			}
+		}
+	}
+}
+
+// forEachOTelDataPointAttributes calls f with the attributes of each data point of m.
+func forEachOTelDataPointAttributes(m pmetric.Metric, f func(pcommon.Map)) {
+	switch m.Type() {
+	case pmetric.MetricTypeGauge:
+		dps := m.Gauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			f(dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeSum:
+		dps := m.Sum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			f(dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeHistogram:
+		dps := m.Histogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			f(dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		dps := m.ExponentialHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			f(dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeSummary:
+		dps := m.Summary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			f(dps.At(i).Attributes())
+		}
+	}
+}
+
 func promToMimirTimeseries(promTs *prompb.TimeSeries) mimirpb.PreallocTimeseries {
 	labels := make([]mimirpb.LabelAdapter, 0, len(promTs.Labels))
 	for _, label := range promTs.Labels {
@@ -0,0 +1,404 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+const (
+	otelDeltaOutOfOrder   = "otlp_delta_out_of_order"
+	otelDeltaStreamsLimit = "otlp_delta_streams_limit"
+
+	// otelDeltaSweepInterval is the minimum interval between two evictions of the stale streams.
+	otelDeltaSweepInterval = time.Minute
+)
+
+// otelDeltaToCumulative accumulates the data points of OTLP sums, histograms and exponential histograms
+// with delta temporality into cumulative data points. The state of each accumulated stream is kept in the
+// memory of the distributor, so all the data points of a stream must be received by the same distributor
+// to be correctly accumulated.
+type otelDeltaToCumulative struct {
+	limits *validation.Overrides
+
+	mtx       sync.Mutex
+	tenants   map[string]*otelDeltaTenant
+	lastSweep time.Time
+
+	streams               *prometheus.GaugeVec
+	discardedOutOfOrder   *prometheus.CounterVec
+	discardedStreamsLimit *prometheus.CounterVec
+}
+
+// otelDeltaTenant holds the accumulated streams of a tenant, keyed by stream identity.
+type otelDeltaTenant struct {
+	mtx     sync.Mutex
+	streams map[string]*otelDeltaStream
+}
+
+// otelDeltaStream is the cumulative state of a stream of delta data points.
+type otelDeltaStream struct {
+	start    pcommon.Timestamp
+	last     pcommon.Timestamp
+	lastSeen time.Time
+
+	sum     float64
+	hist    *otelDeltaHistogram
+	expHist *otelDeltaExpHistogram
+}
+
+type otelDeltaHistogram struct {
+	bounds  []float64
+	buckets []uint64
+	count   uint64
+	sum     float64
+}
+
+type otelDeltaExpHistogram struct {
+	scale              int32
+	zeroThreshold      float64
+	zeroCount          uint64
+	count              uint64
+	sum                float64
+	positive, negative otelExpBuckets
+}
+
+// otelExpBuckets are the buckets of an exponential histogram, starting at the bucket index offset.
+type otelExpBuckets struct {
+	offset int32
+	counts []uint64
+}
+
+func newOTelDeltaToCumulative(limits *validation.Overrides, reg prometheus.Registerer) *otelDeltaToCumulative {
+	return &otelDeltaToCumulative{
+		limits:  limits,
+		tenants: map[string]*otelDeltaTenant{},
+		streams: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+			Name: "cortex_distributor_otlp_delta_to_cumulative_streams",
+			Help: "The number of OTLP delta streams being accumulated into cumulative series by the distributor.",
+		}, []string{"user"}),
+		discardedOutOfOrder:   validation.DiscardedSamplesCounter(reg, otelDeltaOutOfOrder),
+		discardedStreamsLimit: validation.DiscardedSamplesCounter(reg, otelDeltaStreamsLimit),
+	}
+}
+
+// convert replaces the delta data points of md with the cumulative data points accumulated so far.
+// Data points which are older than the last accumulated data point of their stream, or which would
+// exceed the maximum number of streams of the tenant, are dropped.
+func (c *otelDeltaToCumulative) convert(tenantID string, md pmetric.Metrics, now time.Time) {
+	c.sweep(now)
+
+	t := c.tenant(tenantID)
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	a := otelDeltaAccumulation{
+		tenant:     t,
+		now:        now,
+		maxStreams: c.limits.OTelDeltaToCumulativeMaxStreams(tenantID),
+	}
+
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		resourceKey := appendOTelAttributes(nil, rm.Resource().Attributes())
+
+		sms := rm.ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			sm := sms.At(j)
+			scopeKey := append(resourceKey, sm.Scope().Name()...)
+			scopeKey = append(scopeKey, 0xff)
+			scopeKey = append(scopeKey, sm.Scope().Version()...)
+			scopeKey = append(scopeKey, 0xff)
+			scopeKey = appendOTelAttributes(scopeKey, sm.Scope().Attributes())
+
+			metrics := sm.Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				m := metrics.At(k)
+				metricKey := append(scopeKey, byte(m.Type()))
+				metricKey = append(metricKey, m.Name()...)
+				metricKey = append(metricKey, 0xff)
+				a.accumulate(metricKey, m)
+			}
+		}
+	}
+
+	c.streams.WithLabelValues(tenantID).Set(float64(len(t.streams)))
+	if a.outOfOrder > 0 {
+		c.discardedOutOfOrder.WithLabelValues(tenantID, "").Add(float64(a.outOfOrder))
+	}
+	if a.overLimit > 0 {
+		c.discardedStreamsLimit.WithLabelValues(tenantID, "").Add(float64(a.overLimit))
+	}
+}
+
+func (c *otelDeltaToCumulative) tenant(tenantID string) *otelDeltaTenant {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	t, ok := c.tenants[tenantID]
+	if !ok {
+		t = &otelDeltaTenant{streams: map[string]*otelDeltaStream{}}
+		c.tenants[tenantID] = t
+	}
+	return t
+}
+
+// sweep evicts the streams which haven't received any data point for longer than the maximum staleness
+// of their tenant. The streams are evicted at most once per otelDeltaSweepInterval.
+func (c *otelDeltaToCumulative) sweep(now time.Time) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if now.Sub(c.lastSweep) < otelDeltaSweepInterval {
+		return
+	}
+	c.lastSweep = now
+
+	for tenantID, t := range c.tenants {
+		maxStale := c.limits.OTelDeltaToCumulativeMaxStale(tenantID)
+
+		t.mtx.Lock()
+		for key, s := range t.streams {
+			if now.Sub(s.lastSeen) > maxStale {
+				delete(t.streams, key)
+			}
+		}
+		c.streams.WithLabelValues(tenantID).Set(float64(len(t.streams)))
+		t.mtx.Unlock()
+	}
+}
+
+// otelDeltaAccumulation accumulates the delta data points of a single request.
+type otelDeltaAccumulation struct {
+	tenant     *otelDeltaTenant
+	now        time.Time
+	maxStreams int
+
+	outOfOrder int
+	overLimit  int
+}
+
+func (a *otelDeltaAccumulation) accumulate(metricKey []byte, m pmetric.Metric) {
+	switch m.Type() {
+	case pmetric.MetricTypeSum:
+		if m.Sum().AggregationTemporality() != pmetric.AggregationTemporalityDelta {
+			return
+		}
+		m.Sum().DataPoints().RemoveIf(func(dp pmetric.NumberDataPoint) bool {
+			s := a.stream(appendOTelAttributes(metricKey, dp.Attributes()), dp.StartTimestamp(), dp.Timestamp())
+			if s == nil {
+				return true
+			}
+			if dp.ValueType() == pmetric.NumberDataPointValueTypeInt {
+				s.sum += float64(dp.IntValue())
+			} else {
+				s.sum += dp.DoubleValue()
+			}
+			dp.SetDoubleValue(s.sum)
+			dp.SetStartTimestamp(s.start)
+			return false
+		})
+		m.Sum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+
+	case pmetric.MetricTypeHistogram:
+		if m.Histogram().AggregationTemporality() != pmetric.AggregationTemporalityDelta {
+			return
+		}
+		m.Histogram().DataPoints().RemoveIf(func(dp pmetric.HistogramDataPoint) bool {
+			s := a.stream(appendOTelAttributes(metricKey, dp.Attributes()), dp.StartTimestamp(), dp.Timestamp())
+			if s == nil {
+				return true
+			}
+			s.addHistogram(dp)
+			return false
+		})
+		m.Histogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+
+	case pmetric.MetricTypeExponentialHistogram:
+		if m.ExponentialHistogram().AggregationTemporality() != pmetric.AggregationTemporalityDelta {
+			return
+		}
+		m.ExponentialHistogram().DataPoints().RemoveIf(func(dp pmetric.ExponentialHistogramDataPoint) bool {
+			s := a.stream(appendOTelAttributes(metricKey, dp.Attributes()), dp.StartTimestamp(), dp.Timestamp())
+			if s == nil {
+				return true
+			}
+			s.addExpHistogram(dp)
+			return false
+		})
+		m.ExponentialHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	}
+}
+
+// stream returns the stream with the given key, creating it if it doesn't exist. It returns nil if
+// the data point with the given timestamp must be dropped.
+func (a *otelDeltaAccumulation) stream(key []byte, start, ts pcommon.Timestamp) *otelDeltaStream {
+	s, ok := a.tenant.streams[string(key)]
+	if !ok {
+		if a.maxStreams > 0 && len(a.tenant.streams) >= a.maxStreams {
+			a.overLimit++
+			return nil
+		}
+		if start == 0 {
+			start = ts
+		}
+		s = &otelDeltaStream{start: start}
+		a.tenant.streams[string(key)] = s
+	} else if ts <= s.last {
+		a.outOfOrder++
+		return nil
+	}
+
+	s.last = ts
+	s.lastSeen = a.now
+	return s
+}
+
+func (s *otelDeltaStream) addHistogram(dp pmetric.HistogramDataPoint) {
+	if s.hist == nil || !otelHistogramBoundsEqual(s.hist.bounds, dp.ExplicitBounds()) || len(s.hist.buckets) != dp.BucketCounts().Len() {
+		// The buckets layout has changed, so the accumulation starts over.
+		s.hist = &otelDeltaHistogram{
+			bounds:  dp.ExplicitBounds().AsRaw(),
+			buckets: make([]uint64, dp.BucketCounts().Len()),
+		}
+		if dp.StartTimestamp() != 0 {
+			s.start = dp.StartTimestamp()
+		}
+	}
+
+	h := s.hist
+	for i := range h.buckets {
+		h.buckets[i] += dp.BucketCounts().At(i)
+	}
+	h.count += dp.Count()
+	h.sum += dp.Sum()
+
+	dp.BucketCounts().FromRaw(h.buckets)
+	dp.SetCount(h.count)
+	dp.SetSum(h.sum)
+	dp.RemoveMin()
+	dp.RemoveMax()
+	dp.SetStartTimestamp(s.start)
+}
+
+func (s *otelDeltaStream) addExpHistogram(dp pmetric.ExponentialHistogramDataPoint) {
+	if s.expHist == nil || s.expHist.zeroThreshold != dp.ZeroThreshold() {
+		// The zero bucket has changed, so the accumulation starts over.
+		s.expHist = &otelDeltaExpHistogram{scale: dp.Scale(), zeroThreshold: dp.ZeroThreshold()}
+		if dp.StartTimestamp() != 0 {
+			s.start = dp.StartTimestamp()
+		}
+	}
+
+	h := s.expHist
+	positive := otelExpBuckets{offset: dp.Positive().Offset(), counts: dp.Positive().BucketCounts().AsRaw()}
+	negative := otelExpBuckets{offset: dp.Negative().Offset(), counts: dp.Negative().BucketCounts().AsRaw()}
+
+	// Buckets with different scales are merged at the lowest one.
+	if dp.Scale() < h.scale {
+		h.positive.downscale(h.scale - dp.Scale())
+		h.negative.downscale(h.scale - dp.Scale())
+		h.scale = dp.Scale()
+	} else if dp.Scale() > h.scale {
+		positive.downscale(dp.Scale() - h.scale)
+		negative.downscale(dp.Scale() - h.scale)
+	}
+
+	h.positive.add(positive)
+	h.negative.add(negative)
+	h.zeroCount += dp.ZeroCount()
+	h.count += dp.Count()
+	h.sum += dp.Sum()
+
+	dp.SetScale(h.scale)
+	dp.Positive().SetOffset(h.positive.offset)
+	dp.Positive().BucketCounts().FromRaw(h.positive.counts)
+	dp.Negative().SetOffset(h.negative.offset)
+	dp.Negative().BucketCounts().FromRaw(h.negative.counts)
+	dp.SetZeroCount(h.zeroCount)
+	dp.SetCount(h.count)
+	dp.SetSum(h.sum)
+	dp.RemoveMin()
+	dp.RemoveMax()
+	dp.SetStartTimestamp(s.start)
+}
+
+// downscale reduces the scale of the buckets by the given amount, merging the buckets which
+// fall into the same bucket at the lower scale.
+func (b *otelExpBuckets) downscale(by int32) {
+	if by <= 0 || len(b.counts) == 0 {
+		return
+	}
+
+	offset := b.offset >> by
+	last := (b.offset + int32(len(b.counts)) - 1) >> by
+	counts := make([]uint64, last-offset+1)
+	for i, count := range b.counts {
+		counts[((b.offset+int32(i))>>by)-offset] += count
+	}
+	b.offset, b.counts = offset, counts
+}
+
+// add adds the counts of other, which must have the same scale, to the buckets.
+func (b *otelExpBuckets) add(other otelExpBuckets) {
+	if len(other.counts) == 0 {
+		return
+	}
+	if len(b.counts) == 0 {
+		b.offset, b.counts = other.offset, slices.Clone(other.counts)
+		return
+	}
+
+	offset := min(b.offset, other.offset)
+	end := max(b.offset+int32(len(b.counts)), other.offset+int32(len(other.counts)))
+	counts := make([]uint64, end-offset)
+	for i, count := range b.counts {
+		counts[b.offset-offset+int32(i)] += count
+	}
+	for i, count := range other.counts {
+		counts[other.offset-offset+int32(i)] += count
+	}
+	b.offset, b.counts = offset, counts
+}
+
+func otelHistogramBoundsEqual(bounds []float64, other pcommon.Float64Slice) bool {
+	if len(bounds) != other.Len() {
+		return false
+	}
+	for i, bound := range bounds {
+		if bound != other.At(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// appendOTelAttributes appends the attributes, sorted by key, to b.
+func appendOTelAttributes(b []byte, attrs pcommon.Map) []byte {
+	keys := make([]string, 0, attrs.Len())
+	attrs.Range(func(k string, _ pcommon.Value) bool {
+		keys = append(keys, k)
+		return true
+	})
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v, _ := attrs.Get(k)
+		b = append(b, k...)
+		b = append(b, 0xfe)
+		b = append(b, v.AsString()...)
+		b = append(b, 0xff)
+	}
+	return append(b, 0xff)
+}
@@ -0,0 +1,246 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/flagext"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+func TestOTelDeltaToCumulative_Sum(t *testing.T) {
+	c := newOTelDeltaToCumulative(prepareOTelDeltaLimits(t, 0), prometheus.NewPedanticRegistry())
+	now := time.Now()
+
+	md := newOTelDeltaSums(t, "requests", []otelDeltaPoint{{"api", 10, 20, 5}, {"web", 10, 20, 1}})
+	c.convert("user", md, now)
+	assertOTelCumulativeSums(t, md, []otelDeltaPoint{{"api", 10, 20, 5}, {"web", 10, 20, 1}})
+
+	md = newOTelDeltaSums(t, "requests", []otelDeltaPoint{{"api", 20, 30, 3}, {"api", 30, 40, 2}})
+	c.convert("user", md, now)
+	assertOTelCumulativeSums(t, md, []otelDeltaPoint{{"api", 10, 30, 8}, {"api", 10, 40, 10}})
+
+	// Data points which aren't newer than the last accumulated one are dropped.
+	md = newOTelDeltaSums(t, "requests", []otelDeltaPoint{{"api", 30, 40, 2}, {"web", 20, 30, 1}})
+	c.convert("user", md, now)
+	assertOTelCumulativeSums(t, md, []otelDeltaPoint{{"web", 10, 30, 2}})
+
+	// Cumulative sums are left untouched.
+	md = newOTelDeltaSums(t, "requests", []otelDeltaPoint{{"api", 0, 50, 100}})
+	md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	c.convert("user", md, now)
+	assertOTelCumulativeSums(t, md, []otelDeltaPoint{{"api", 0, 50, 100}})
+}
+
+func TestOTelDeltaToCumulative_Limits(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	c := newOTelDeltaToCumulative(prepareOTelDeltaLimits(t, 1), reg)
+	now := time.Now()
+
+	md := newOTelDeltaSums(t, "requests", []otelDeltaPoint{{"api", 10, 20, 5}, {"web", 10, 20, 1}})
+	c.convert("user", md, now)
+	assertOTelCumulativeSums(t, md, []otelDeltaPoint{{"api", 10, 20, 5}})
+
+	md = newOTelDeltaSums(t, "requests", []otelDeltaPoint{{"api", 10, 20, 5}})
+	c.convert("user", md, now)
+	assertOTelCumulativeSums(t, md, nil)
+
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+		# HELP cortex_discarded_samples_total The total number of samples that were discarded.
+		# TYPE cortex_discarded_samples_total counter
+		cortex_discarded_samples_total{group="",reason="otlp_delta_out_of_order",user="user"} 1
+		cortex_discarded_samples_total{group="",reason="otlp_delta_streams_limit",user="user"} 1
+		# HELP cortex_distributor_otlp_delta_to_cumulative_streams The number of OTLP delta streams being accumulated into cumulative series by the distributor.
+		# TYPE cortex_distributor_otlp_delta_to_cumulative_streams gauge
+		cortex_distributor_otlp_delta_to_cumulative_streams{user="user"} 1
+	`), "cortex_discarded_samples_total", "cortex_distributor_otlp_delta_to_cumulative_streams"))
+
+	// Once the stream is stale, it's evicted and a new stream can be accumulated.
+	now = now.Add(10 * time.Minute)
+	md = newOTelDeltaSums(t, "requests", []otelDeltaPoint{{"web", 30, 40, 2}})
+	c.convert("user", md, now)
+	assertOTelCumulativeSums(t, md, []otelDeltaPoint{{"web", 30, 40, 2}})
+}
+
+func TestOTelDeltaToCumulative_Histogram(t *testing.T) {
+	c := newOTelDeltaToCumulative(prepareOTelDeltaLimits(t, 0), prometheus.NewPedanticRegistry())
+	now := time.Now()
+
+	newHistogram := func(start, ts int64, bounds []float64, buckets []uint64) pmetric.Metrics {
+		md := pmetric.NewMetrics()
+		m := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+		m.SetName("latency")
+		m.SetEmptyHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+		dp := m.Histogram().DataPoints().AppendEmpty()
+		dp.SetStartTimestamp(pcommon.Timestamp(start))
+		dp.SetTimestamp(pcommon.Timestamp(ts))
+		dp.ExplicitBounds().FromRaw(bounds)
+		dp.BucketCounts().FromRaw(buckets)
+		var count uint64
+		for _, b := range buckets {
+			count += b
+		}
+		dp.SetCount(count)
+		dp.SetSum(float64(count))
+		dp.SetMin(1)
+		dp.SetMax(1)
+		return md
+	}
+	dataPoint := func(md pmetric.Metrics) pmetric.HistogramDataPoint {
+		h := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Histogram()
+		assert.Equal(t, pmetric.AggregationTemporalityCumulative, h.AggregationTemporality())
+		require.Equal(t, 1, h.DataPoints().Len())
+		return h.DataPoints().At(0)
+	}
+
+	md := newHistogram(10, 20, []float64{1, 10}, []uint64{1, 2, 3})
+	c.convert("user", md, now)
+	dp := dataPoint(md)
+	assert.Equal(t, []uint64{1, 2, 3}, dp.BucketCounts().AsRaw())
+
+	md = newHistogram(20, 30, []float64{1, 10}, []uint64{1, 0, 1})
+	c.convert("user", md, now)
+	dp = dataPoint(md)
+	assert.Equal(t, pcommon.Timestamp(10), dp.StartTimestamp())
+	assert.Equal(t, []uint64{2, 2, 4}, dp.BucketCounts().AsRaw())
+	assert.Equal(t, uint64(8), dp.Count())
+	assert.Equal(t, float64(8), dp.Sum())
+	assert.False(t, dp.HasMin())
+	assert.False(t, dp.HasMax())
+
+	// The accumulation starts over when the bounds change.
+	md = newHistogram(30, 40, []float64{5}, []uint64{1, 1})
+	c.convert("user", md, now)
+	dp = dataPoint(md)
+	assert.Equal(t, pcommon.Timestamp(30), dp.StartTimestamp())
+	assert.Equal(t, []uint64{1, 1}, dp.BucketCounts().AsRaw())
+	assert.Equal(t, uint64(2), dp.Count())
+}
+
+func TestOTelDeltaToCumulative_ExponentialHistogram(t *testing.T) {
+	c := newOTelDeltaToCumulative(prepareOTelDeltaLimits(t, 0), prometheus.NewPedanticRegistry())
+	now := time.Now()
+
+	newHistogram := func(start, ts int64, scale, offset int32, buckets []uint64, zeroCount uint64) pmetric.Metrics {
+		md := pmetric.NewMetrics()
+		m := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+		m.SetName("latency")
+		m.SetEmptyExponentialHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+		dp := m.ExponentialHistogram().DataPoints().AppendEmpty()
+		dp.SetStartTimestamp(pcommon.Timestamp(start))
+		dp.SetTimestamp(pcommon.Timestamp(ts))
+		dp.SetScale(scale)
+		dp.SetZeroCount(zeroCount)
+		dp.Positive().SetOffset(offset)
+		dp.Positive().BucketCounts().FromRaw(buckets)
+		count := zeroCount
+		for _, b := range buckets {
+			count += b
+		}
+		dp.SetCount(count)
+		return md
+	}
+
+	md := newHistogram(10, 20, 1, 0, []uint64{1, 2, 3}, 1)
+	c.convert("user", md, now)
+
+	// Buckets are merged at the lowest scale.
+	md = newHistogram(20, 30, 0, 2, []uint64{4}, 0)
+	c.convert("user", md, now)
+
+	h := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).ExponentialHistogram()
+	assert.Equal(t, pmetric.AggregationTemporalityCumulative, h.AggregationTemporality())
+	dp := h.DataPoints().At(0)
+	assert.Equal(t, pcommon.Timestamp(10), dp.StartTimestamp())
+	assert.Equal(t, int32(0), dp.Scale())
+	assert.Equal(t, int32(0), dp.Positive().Offset())
+	assert.Equal(t, []uint64{3, 3, 4}, dp.Positive().BucketCounts().AsRaw())
+	assert.Equal(t, uint64(1), dp.ZeroCount())
+	assert.Equal(t, uint64(11), dp.Count())
+}
+
+func TestOTelExpBuckets(t *testing.T) {
+	t.Run("downscale", func(t *testing.T) {
+		b := otelExpBuckets{offset: -3, counts: []uint64{1, 2, 3, 4, 5}}
+		b.downscale(1)
+		assert.Equal(t, otelExpBuckets{offset: -2, counts: []uint64{1, 5, 9}}, b)
+
+		b.downscale(2)
+		assert.Equal(t, otelExpBuckets{offset: -1, counts: []uint64{6, 9}}, b)
+	})
+
+	t.Run("add", func(t *testing.T) {
+		var b otelExpBuckets
+		b.add(otelExpBuckets{offset: 2, counts: []uint64{1, 1}})
+		assert.Equal(t, otelExpBuckets{offset: 2, counts: []uint64{1, 1}}, b)
+
+		b.add(otelExpBuckets{offset: 0, counts: []uint64{1, 0, 1}})
+		assert.Equal(t, otelExpBuckets{offset: 0, counts: []uint64{1, 0, 2, 1}}, b)
+
+		b.add(otelExpBuckets{offset: 5, counts: []uint64{1}})
+		assert.Equal(t, otelExpBuckets{offset: 0, counts: []uint64{1, 0, 2, 1, 0, 1}}, b)
+	})
+}
+
+type otelDeltaPoint struct {
+	job       string
+	start, ts int64
+	value     float64
+}
+
+func prepareOTelDeltaLimits(t *testing.T, maxStreams int) *validation.Overrides {
+	limits := validation.Limits{}
+	flagext.DefaultValues(&limits)
+	limits.OTelDeltaToCumulativeEnabled = true
+	limits.OTelDeltaToCumulativeMaxStreams = maxStreams
+
+	overrides, err := validation.NewOverrides(limits, nil)
+	require.NoError(t, err)
+	return overrides
+}
+
+func newOTelDeltaSums(t *testing.T, name string, points []otelDeltaPoint) pmetric.Metrics {
+	t.Helper()
+
+	md := pmetric.NewMetrics()
+	m := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName(name)
+	m.SetEmptySum().SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	for _, p := range points {
+		dp := m.Sum().DataPoints().AppendEmpty()
+		dp.Attributes().PutStr("job", p.job)
+		dp.SetStartTimestamp(pcommon.Timestamp(p.start))
+		dp.SetTimestamp(pcommon.Timestamp(p.ts))
+		dp.SetIntValue(int64(p.value))
+	}
+	return md
+}
+
+func assertOTelCumulativeSums(t *testing.T, md pmetric.Metrics, expected []otelDeltaPoint) {
+	t.Helper()
+
+	sum := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum()
+	assert.Equal(t, pmetric.AggregationTemporalityCumulative, sum.AggregationTemporality())
+
+	var actual []otelDeltaPoint
+	for i := 0; i < sum.DataPoints().Len(); i++ {
+		dp := sum.DataPoints().At(i)
+		job, _ := dp.Attributes().Get("job")
+		value := dp.DoubleValue()
+		if dp.ValueType() == pmetric.NumberDataPointValueTypeInt {
+			value = float64(dp.IntValue())
+		}
+		actual = append(actual, otelDeltaPoint{job.Str(), int64(dp.StartTimestamp()), int64(dp.Timestamp()), value})
+	}
+	assert.Equal(t, expected, actual)
+}
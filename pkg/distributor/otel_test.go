@@ -18,6 +18,7 @@ import (
 	"github.com/prometheus/prometheus/prompb"
 	"github.com/prometheus/prometheus/storage/remote"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
 
 	"github.com/grafana/mimir/pkg/mimirpb"
@@ -94,6 +95,26 @@ func BenchmarkOTLPHandler(b *testing.B) {
 	})
 }
 
+func TestPromoteOTelResourceAttributes(t *testing.T) {
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "api")
+	rm.Resource().Attributes().PutStr("k8s.namespace.name", "prod")
+	rm.Resource().Attributes().PutStr("k8s.pod.name", "api-1")
+	metrics := rm.ScopeMetrics().AppendEmpty().Metrics()
+
+	gauge := metrics.AppendEmpty()
+	gauge.SetEmptyGauge().DataPoints().AppendEmpty().Attributes().PutStr("method", "GET")
+	sum := metrics.AppendEmpty()
+	sum.SetEmptySum().DataPoints().AppendEmpty().Attributes().PutStr("k8s.namespace.name", "dev")
+
+	promoteOTelResourceAttributes(md, []string{"k8s.namespace.name", "cloud.region"})
+
+	require.Equal(t, map[string]any{"method": "GET", "k8s.namespace.name": "prod"}, gauge.Gauge().DataPoints().At(0).Attributes().AsRaw())
+	// Data point attributes take precedence over the promoted resource attributes.
+	require.Equal(t, map[string]any{"k8s.namespace.name": "dev"}, sum.Sum().DataPoints().At(0).Attributes().AsRaw())
+}
+
 func createOTLPProtoRequest(tb testing.TB, metricRequest pmetricotlp.ExportRequest, compress bool) *http.Request {
 	tb.Helper()
 
@@ -12,12 +12,14 @@ import (
 	"flag"
 	"fmt"
 	"math/rand"
+	"mime"
 	"net/http"
 	"strconv"
 	"sync"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
+	"github.com/gogo/protobuf/proto"
 	"github.com/grafana/dskit/httpgrpc"
 	"github.com/grafana/dskit/httpgrpc/server"
 	"github.com/grafana/dskit/middleware"
@@ -50,6 +52,13 @@ var (
 const (
 	SkipLabelNameValidationHeader = "X-Mimir-SkipLabelNameValidation"
 	statusClientClosedRequest     = 499
+
+	remoteWrite1ProtoMessage = "prometheus.WriteRequest"
+	remoteWrite2ProtoMessage = "io.prometheus.write.v2.Request"
+
+	remoteWrite2SamplesWrittenHeader    = "X-Prometheus-Remote-Write-Samples-Written"
+	remoteWrite2HistogramsWrittenHeader = "X-Prometheus-Remote-Write-Histograms-Written"
+	remoteWrite2ExemplarsWrittenHeader  = "X-Prometheus-Remote-Write-Exemplars-Written"
 )
 
 type RetryConfig struct {
@@ -85,13 +94,109 @@ func Handler(
 	push PushFunc,
 	logger log.Logger,
 ) http.Handler {
-	return handler(maxRecvMsgSize, sourceIPs, allowSkipLabelNameValidation, limits, retryCfg, push, logger, func(ctx context.Context, r *http.Request, maxRecvMsgSize int, buffers *util.RequestBuffers, req *mimirpb.PreallocWriteRequest, _ log.Logger) error {
-		err := util.ParseProtoReader(ctx, r.Body, int(r.ContentLength), maxRecvMsgSize, buffers, req, util.RawSnappy)
+	h := handler(maxRecvMsgSize, sourceIPs, allowSkipLabelNameValidation, limits, retryCfg, push, logger, func(ctx context.Context, r *http.Request, maxRecvMsgSize int, buffers *util.RequestBuffers, req *mimirpb.PreallocWriteRequest, _ log.Logger) error {
+		protoMessage, err := remoteWriteProtoMessage(r.Header.Get("Content-Type"))
+		if err != nil {
+			return httpgrpc.Errorf(http.StatusUnsupportedMediaType, err.Error())
+		}
+
+		var msg proto.Message = req
+		if protoMessage == remoteWrite2ProtoMessage {
+			tenantID, err := tenant.TenantID(ctx)
+			if err != nil {
+				return err
+			}
+			msg = &mimirpb.WriteRequestV2{Request: req, CreatedTimestampZeroIngestion: limits.CreatedTimestampZeroIngestionEnabled(tenantID)}
+		}
+
+		err = util.ParseProtoReader(ctx, r.Body, int(r.ContentLength), maxRecvMsgSize, buffers, msg, util.RawSnappy)
 		if errors.Is(err, util.MsgSizeTooLargeErr{}) {
 			err = distributorMaxWriteMessageSizeErr{actual: int(r.ContentLength), limit: maxRecvMsgSize}
 		}
-		return err
+		if err != nil {
+			return err
+		}
+
+		if stats, ok := ctx.Value(remoteWrite2StatsKey).(*remoteWrite2Stats); ok {
+			stats.count(&req.WriteRequest)
+		}
+		return nil
 	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if protoMessage, _ := remoteWriteProtoMessage(r.Header.Get("Content-Type")); protoMessage != remoteWrite2ProtoMessage {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		// Remote write 2.0 clients expect the number of written samples, histograms and exemplars
+		// in the response headers.
+		stats := &remoteWrite2Stats{}
+		rw := &headerTrackingResponseWriter{ResponseWriter: w}
+		h.ServeHTTP(rw, r.WithContext(context.WithValue(r.Context(), remoteWrite2StatsKey, stats)))
+		if !rw.wroteHeader {
+			stats.setHeaders(w.Header())
+		}
+	})
+}
+
+// remoteWriteProtoMessage returns the protobuf message of a remote write request with the given
+// Content-Type, as defined by the remote write 2.0 specification. Requests without the proto parameter
+// are remote write 1.0 requests.
+func remoteWriteProtoMessage(contentType string) (string, error) {
+	if contentType == "" {
+		return remoteWrite1ProtoMessage, nil
+	}
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != "application/x-protobuf" {
+		// Keep accepting the requests of clients which don't set the protobuf content type.
+		return remoteWrite1ProtoMessage, nil
+	}
+
+	switch protoMessage := params["proto"]; protoMessage {
+	case "", remoteWrite1ProtoMessage:
+		return remoteWrite1ProtoMessage, nil
+	case remoteWrite2ProtoMessage:
+		return remoteWrite2ProtoMessage, nil
+	default:
+		return "", fmt.Errorf("unsupported remote write protobuf message %q, supported messages are %q and %q", protoMessage, remoteWrite1ProtoMessage, remoteWrite2ProtoMessage)
+	}
+}
+
+// remoteWrite2Stats holds the number of samples, histograms and exemplars of a remote write 2.0 request.
+type remoteWrite2Stats struct {
+	samples, histograms, exemplars int
+}
+
+func (s *remoteWrite2Stats) count(req *mimirpb.WriteRequest) {
+	for _, ts := range req.Timeseries {
+		s.samples += len(ts.Samples)
+		s.histograms += len(ts.Histograms)
+		s.exemplars += len(ts.Exemplars)
+	}
+}
+
+func (s *remoteWrite2Stats) setHeaders(h http.Header) {
+	h.Set(remoteWrite2SamplesWrittenHeader, strconv.Itoa(s.samples))
+	h.Set(remoteWrite2HistogramsWrittenHeader, strconv.Itoa(s.histograms))
+	h.Set(remoteWrite2ExemplarsWrittenHeader, strconv.Itoa(s.exemplars))
+}
+
+// headerTrackingResponseWriter tracks whether the wrapped handler has written the response header.
+type headerTrackingResponseWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (w *headerTrackingResponseWriter) WriteHeader(code int) {
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *headerTrackingResponseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
 }
 
 type distributorMaxWriteMessageSizeErr struct {
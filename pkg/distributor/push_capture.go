@@ -0,0 +1,401 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/concurrency"
+	"github.com/grafana/dskit/httpgrpc"
+	"github.com/grafana/dskit/ring"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/util"
+)
+
+const (
+	defaultPushCaptureDuration  = time.Minute
+	maxPushCaptureDuration      = 10 * time.Minute
+	defaultPushCaptureMaxSeries = 1000
+	maxPushCaptureMaxSeries     = 10000
+
+	pushCaptureOutcomeAccepted = "accepted"
+
+	// pushCaptureForwardedParam is set on the push capture requests forwarded to the other distributors.
+	pushCaptureForwardedParam = "forwarded"
+)
+
+// pushCaptures holds the push captures of the tenants. Its zero value has no captures.
+type pushCaptures struct {
+	mtx      sync.RWMutex
+	captures map[string]*pushCapture
+}
+
+// pushCapture records the series pushed by a tenant, until its deadline or until it holds
+// the maximum number of series.
+type pushCapture struct {
+	mtx       sync.Mutex
+	matchers  [][]*labels.Matcher
+	selectors []string
+	startedAt time.Time
+	until     time.Time
+	maxSeries int
+	skipped   int
+	series    []capturedSeries
+}
+
+type capturedSeries struct {
+	ReceivedAt time.Time         `json:"receivedAt"`
+	Labels     map[string]string `json:"labels"`
+	Samples    int               `json:"samples"`
+	Histograms int               `json:"histograms"`
+	Exemplars  int               `json:"exemplars"`
+	Outcome    string            `json:"outcome"`
+}
+
+type pushCaptureResponse struct {
+	Selectors []string         `json:"selectors"`
+	StartedAt time.Time        `json:"startedAt"`
+	Until     time.Time        `json:"until"`
+	MaxSeries int              `json:"maxSeries"`
+	Skipped   int              `json:"skipped"`
+	Series    []capturedSeries `json:"series"`
+}
+
+func (c *pushCaptures) get(userID string) *pushCapture {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	return c.captures[userID]
+}
+
+func (c *pushCaptures) set(userID string, capture *pushCapture) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if capture == nil {
+		delete(c.captures, userID)
+		return
+	}
+	if c.captures == nil {
+		c.captures = map[string]*pushCapture{}
+	}
+	c.captures[userID] = capture
+}
+
+// expired returns whether the capture has stopped recording the series received at now.
+func (c *pushCapture) expired(now time.Time) bool {
+	return !now.Before(c.until)
+}
+
+// record adds the series to the capture, if the capture hasn't expired and the series matches any of
+// its selectors. The labels are copied, because they're backed by the request buffers.
+func (c *pushCapture) record(now time.Time, lbls []mimirpb.LabelAdapter, samples, histograms, exemplars int, validationErr error) {
+	if len(c.matchers) > 0 {
+		series := mimirpb.FromLabelAdaptersToLabels(lbls)
+		matched := false
+		for _, matchers := range c.matchers {
+			if seriesMatches(matchers, series) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return
+		}
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.expired(now) {
+		return
+	}
+	if len(c.series) >= c.maxSeries {
+		c.skipped++
+		return
+	}
+
+	series := capturedSeries{
+		ReceivedAt: now,
+		Labels:     make(map[string]string, len(lbls)),
+		Samples:    samples,
+		Histograms: histograms,
+		Exemplars:  exemplars,
+		Outcome:    pushCaptureOutcomeAccepted,
+	}
+	for _, l := range lbls {
+		series.Labels[strings.Clone(l.Name)] = strings.Clone(l.Value)
+	}
+	if validationErr != nil {
+		series.Outcome = validationErr.Error()
+	}
+	c.series = append(c.series, series)
+}
+
+// seriesMatches returns whether the series matches all the matchers.
+func seriesMatches(matchers []*labels.Matcher, lbls labels.Labels) bool {
+	for _, m := range matchers {
+		if !m.Matches(lbls.Get(m.Name)) {
+			return false
+		}
+	}
+	return true
+}
+
+// StartPushCaptureHandler starts capturing the series pushed by the tenant to all the distributors, replacing the
+// previous capture of the tenant. The capture records the series after relabeling, along with their number of
+// samples, histograms and exemplars, and the outcome of their validation.
+func (d *Distributor) StartPushCaptureHandler(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	userID := r.Form.Get("tenant")
+	if userID == "" {
+		http.Error(w, "missing tenant parameter", http.StatusBadRequest)
+		return
+	}
+
+	var err error
+	duration := defaultPushCaptureDuration
+	if v := r.Form.Get("duration"); v != "" {
+		duration, err = time.ParseDuration(v)
+		if err != nil || duration <= 0 || duration > maxPushCaptureDuration {
+			http.Error(w, "invalid duration parameter: must be a positive duration up to "+maxPushCaptureDuration.String(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	maxSeries := defaultPushCaptureMaxSeries
+	if v := r.Form.Get("max_series"); v != "" {
+		maxSeries, err = strconv.Atoi(v)
+		if err != nil || maxSeries <= 0 || maxSeries > maxPushCaptureMaxSeries {
+			http.Error(w, "invalid max_series parameter: must be a positive integer up to "+strconv.Itoa(maxPushCaptureMaxSeries), http.StatusBadRequest)
+			return
+		}
+	}
+
+	selectors := r.Form["match[]"]
+	matchers := make([][]*labels.Matcher, 0, len(selectors))
+	for _, selector := range selectors {
+		m, err := parser.ParseMetricSelector(selector)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		matchers = append(matchers, m)
+	}
+
+	now := time.Now()
+	capture := &pushCapture{
+		matchers:  matchers,
+		selectors: selectors,
+		startedAt: now,
+		until:     now.Add(duration),
+		maxSeries: maxSeries,
+	}
+	d.pushCaptures.set(userID, capture)
+
+	level.Info(d.log).Log("msg", "push capture started", "user", userID, "duration", duration, "max_series", maxSeries, "selectors", strings.Join(selectors, ","))
+
+	responses, err := d.forwardPushCaptureRequest(r)
+	if err != nil {
+		http.Error(w, "failed to start the push capture on all distributors: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	d.writePushCaptureResponse(w, capture, responses)
+}
+
+// PushCaptureHandler returns the series captured for the tenant by all the distributors.
+func (d *Distributor) PushCaptureHandler(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	userID := r.Form.Get("tenant")
+	if userID == "" {
+		http.Error(w, "missing tenant parameter", http.StatusBadRequest)
+		return
+	}
+
+	responses, err := d.forwardPushCaptureRequest(r)
+	if err != nil {
+		http.Error(w, "failed to get the push capture from all distributors: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	capture := d.pushCaptures.get(userID)
+	if capture == nil && len(responses) == 0 {
+		http.Error(w, "push capture not found", http.StatusNotFound)
+		return
+	}
+
+	d.writePushCaptureResponse(w, capture, responses)
+}
+
+// StopPushCaptureHandler stops the push capture of the tenant on all the distributors and discards the captured series.
+func (d *Distributor) StopPushCaptureHandler(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	userID := r.Form.Get("tenant")
+	if userID == "" {
+		http.Error(w, "missing tenant parameter", http.StatusBadRequest)
+		return
+	}
+
+	found := d.pushCaptures.get(userID) != nil
+	d.pushCaptures.set(userID, nil)
+
+	responses, err := d.forwardPushCaptureRequest(r)
+	if err != nil {
+		http.Error(w, "failed to stop the push capture on all distributors: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !found && len(responses) == 0 {
+		http.Error(w, "push capture not found", http.StatusNotFound)
+		return
+	}
+
+	level.Info(d.log).Log("msg", "push capture stopped", "user", userID)
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// forwardPushCaptureRequest forwards the push capture request to the other healthy distributors in the ring, which
+// handle it on their own, and returns the body of their successful responses. The distributors without a capture
+// of the tenant are skipped. The request isn't forwarded if the distributors ring isn't configured, or if the request
+// has been forwarded by another distributor. The form of the request must have been parsed.
+func (d *Distributor) forwardPushCaptureRequest(r *http.Request) ([][]byte, error) {
+	if d.distributorsRing == nil || r.Form.Get(pushCaptureForwardedParam) != "" {
+		return nil, nil
+	}
+
+	replicationSet, err := d.distributorsRing.GetAllHealthy(ring.Reporting)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	for name, values := range r.Form {
+		form[name] = values
+	}
+	form.Set(pushCaptureForwardedParam, "true")
+	req := &httpgrpc.HTTPRequest{
+		Method: r.Method,
+		Url:    r.URL.Path + "?" + form.Encode(),
+	}
+
+	var (
+		mtx       sync.Mutex
+		responses [][]byte
+	)
+	err = concurrency.ForEachJob(r.Context(), len(replicationSet.Instances), len(replicationSet.Instances), func(ctx context.Context, idx int) error {
+		inst := replicationSet.Instances[idx]
+		if inst.Id == d.distributorsLifecycler.GetInstanceID() {
+			return nil
+		}
+
+		c, err := d.distributorPool.GetClientForInstance(inst)
+		if err != nil {
+			return errors.Wrapf(err, "distributor %s", inst.Id)
+		}
+
+		resp, err := c.(httpgrpc.HTTPClient).Handle(ctx, req)
+		if err != nil {
+			var ok bool
+			if resp, ok = httpgrpc.HTTPResponseFromError(err); !ok {
+				return errors.Wrapf(err, "distributor %s", inst.Id)
+			}
+		}
+
+		switch resp.Code {
+		case http.StatusOK:
+			mtx.Lock()
+			responses = append(responses, resp.Body)
+			mtx.Unlock()
+			return nil
+		case http.StatusNotFound:
+			return nil
+		default:
+			return fmt.Errorf("distributor %s: %d %s", inst.Id, resp.Code, strings.TrimSpace(string(resp.Body)))
+		}
+	})
+	return responses, err
+}
+
+// writePushCaptureResponse writes the capture of this distributor, if any, merged with the captures returned by
+// the other distributors. The series are sorted by the time they have been received, and the series exceeding the
+// max number of series of the capture are counted as skipped.
+func (d *Distributor) writePushCaptureResponse(w http.ResponseWriter, capture *pushCapture, responses [][]byte) {
+	captures := make([]pushCaptureResponse, 0, len(responses)+1)
+	if capture != nil {
+		captures = append(captures, capture.response())
+	}
+	for _, body := range responses {
+		var resp pushCaptureResponse
+		if err := json.Unmarshal(body, &resp); err != nil {
+			http.Error(w, "failed to decode the push capture of another distributor: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		captures = append(captures, resp)
+	}
+
+	merged := captures[0]
+	for _, c := range captures[1:] {
+		if c.StartedAt.Before(merged.StartedAt) {
+			merged.StartedAt = c.StartedAt
+		}
+		if c.Until.After(merged.Until) {
+			merged.Until = c.Until
+		}
+		merged.Skipped += c.Skipped
+		merged.Series = append(merged.Series, c.Series...)
+	}
+
+	sort.SliceStable(merged.Series, func(i, j int) bool {
+		return merged.Series[i].ReceivedAt.Before(merged.Series[j].ReceivedAt)
+	})
+	if len(merged.Series) > merged.MaxSeries {
+		merged.Skipped += len(merged.Series) - merged.MaxSeries
+		merged.Series = merged.Series[:merged.MaxSeries]
+	}
+
+	util.WriteJSONResponse(w, merged)
+}
+
+func (c *pushCapture) response() pushCaptureResponse {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	resp := pushCaptureResponse{
+		Selectors: c.selectors,
+		StartedAt: c.startedAt,
+		Until:     c.until,
+		MaxSeries: c.maxSeries,
+		Skipped:   c.skipped,
+		Series:    append([]capturedSeries{}, c.series...),
+	}
+	if resp.Selectors == nil {
+		resp.Selectors = []string{}
+	}
+	return resp
+}
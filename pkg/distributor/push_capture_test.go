@@ -0,0 +1,182 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/grafana/dskit/httpgrpc"
+	httpgrpc_server "github.com/grafana/dskit/httpgrpc/server"
+	"github.com/grafana/dskit/ring"
+	ring_client "github.com/grafana/dskit/ring/client"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestDistributor_PushCapture(t *testing.T) {
+	ds, _, _, _ := prepare(t, prepConfig{
+		numIngesters:      3,
+		happyIngesters:    3,
+		numDistributors:   1,
+		replicationFactor: 3,
+	})
+	d := ds[0]
+
+	serve := func(handler http.HandlerFunc, method string, form url.Values) *httptest.ResponseRecorder {
+		if form == nil {
+			form = url.Values{"tenant": {"user"}}
+		}
+		req := httptest.NewRequest(method, "/distributor/push_capture?"+form.Encode(), nil)
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	// There's no capture before it's started.
+	require.Equal(t, http.StatusNotFound, serve(d.PushCaptureHandler, http.MethodGet, nil).Code)
+
+	for _, form := range []url.Values{
+		{"max_series": {"1"}},
+		{"tenant": {"user"}, "duration": {"1h"}},
+		{"tenant": {"user"}, "max_series": {"0"}},
+		{"tenant": {"user"}, "match[]": {"{__name__="}},
+	} {
+		require.Equal(t, http.StatusBadRequest, serve(d.StartPushCaptureHandler, http.MethodPost, form).Code, form)
+	}
+
+	rec := serve(d.StartPushCaptureHandler, http.MethodPost, url.Values{"tenant": {"user"}, "match[]": {`{__name__="foo"}`}, "max_series": {"2"}})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	ctx := user.InjectOrgID(context.Background(), "user")
+	now := time.Now().UnixMilli()
+	_, err := d.Push(ctx, mockWriteRequest(labels.FromStrings(labels.MetricName, "foo", "job", "api"), 1, now))
+	require.NoError(t, err)
+	_, err = d.Push(ctx, mockWriteRequest(labels.FromStrings(labels.MetricName, "bar", "job", "api"), 1, now))
+	require.NoError(t, err)
+	_, err = d.Push(ctx, mockWriteRequest(labels.FromStrings(labels.MetricName, "foo", "job", "web"), 1, now+time.Hour.Milliseconds()))
+	require.Error(t, err)
+	// The capture is full.
+	_, err = d.Push(ctx, mockWriteRequest(labels.FromStrings(labels.MetricName, "foo", "job", "db"), 1, now))
+	require.NoError(t, err)
+
+	rec = serve(d.PushCaptureHandler, http.MethodGet, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var resp pushCaptureResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, []string{`{__name__="foo"}`}, resp.Selectors)
+	assert.Equal(t, 2, resp.MaxSeries)
+	assert.Equal(t, 1, resp.Skipped)
+	require.Len(t, resp.Series, 2)
+	assert.Equal(t, map[string]string{"__name__": "foo", "job": "api"}, resp.Series[0].Labels)
+	assert.Equal(t, 1, resp.Series[0].Samples)
+	assert.Equal(t, pushCaptureOutcomeAccepted, resp.Series[0].Outcome)
+	assert.Equal(t, map[string]string{"__name__": "foo", "job": "web"}, resp.Series[1].Labels)
+	assert.Contains(t, resp.Series[1].Outcome, "too far in the future")
+
+	require.Equal(t, http.StatusOK, serve(d.StopPushCaptureHandler, http.MethodDelete, nil).Code)
+	require.Equal(t, http.StatusNotFound, serve(d.PushCaptureHandler, http.MethodGet, nil).Code)
+	require.Equal(t, http.StatusNotFound, serve(d.StopPushCaptureHandler, http.MethodDelete, nil).Code)
+}
+
+func TestDistributor_PushCaptureForwardedToAllDistributors(t *testing.T) {
+	var ds []*Distributor
+	ds, _, _, _ = prepare(t, prepConfig{
+		numIngesters:      3,
+		happyIngesters:    3,
+		numDistributors:   2,
+		replicationFactor: 3,
+		configure: func(cfg *Config) {
+			cfg.DistributorClientFactory = ring_client.PoolInstFunc(func(inst ring.InstanceDesc) (ring_client.PoolClient, error) {
+				idx, err := strconv.Atoi(inst.Id)
+				if err != nil {
+					return nil, err
+				}
+				return newMockDistributorClient(ds[idx]), nil
+			})
+		},
+	})
+
+	serve := func(d *Distributor, method string, form url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/distributor/push_capture?"+form.Encode(), nil)
+		rec := httptest.NewRecorder()
+		pushCaptureRouter(d).ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve(ds[0], http.MethodPost, url.Values{"tenant": {"user"}, "max_series": {"2"}})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.NotNil(t, ds[0].pushCaptures.get("user"))
+	require.NotNil(t, ds[1].pushCaptures.get("user"))
+
+	// The series pushed to each distributor are captured.
+	ctx := user.InjectOrgID(context.Background(), "user")
+	now := time.Now().UnixMilli()
+	for i, name := range []string{"first", "second", "third"} {
+		_, err := ds[(i+1)%2].Push(ctx, mockWriteRequest(labels.FromStrings(labels.MetricName, name), 1, now))
+		require.NoError(t, err)
+	}
+
+	rec = serve(ds[0], http.MethodGet, url.Values{"tenant": {"user"}})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var resp pushCaptureResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, 2, resp.MaxSeries)
+	assert.Equal(t, 1, resp.Skipped)
+	require.Len(t, resp.Series, 2)
+	assert.Equal(t, map[string]string{"__name__": "first"}, resp.Series[0].Labels)
+	assert.Equal(t, map[string]string{"__name__": "second"}, resp.Series[1].Labels)
+
+	// The capture is stopped on all the distributors.
+	require.Equal(t, http.StatusOK, serve(ds[1], http.MethodDelete, url.Values{"tenant": {"user"}}).Code)
+	require.Nil(t, ds[0].pushCaptures.get("user"))
+	require.Nil(t, ds[1].pushCaptures.get("user"))
+	require.Equal(t, http.StatusNotFound, serve(ds[0], http.MethodGet, url.Values{"tenant": {"user"}}).Code)
+}
+
+func pushCaptureRouter(d *Distributor) http.Handler {
+	router := mux.NewRouter()
+	router.Path("/distributor/push_capture").Methods(http.MethodPost).HandlerFunc(d.StartPushCaptureHandler)
+	router.Path("/distributor/push_capture").Methods(http.MethodGet).HandlerFunc(d.PushCaptureHandler)
+	router.Path("/distributor/push_capture").Methods(http.MethodDelete).HandlerFunc(d.StopPushCaptureHandler)
+	return router
+}
+
+// mockDistributorClient handles the HTTP over gRPC requests with the push capture handlers of a distributor.
+type mockDistributorClient struct {
+	server httpgrpc.HTTPServer
+}
+
+func newMockDistributorClient(d *Distributor) *mockDistributorClient {
+	return &mockDistributorClient{server: httpgrpc_server.NewServer(pushCaptureRouter(d))}
+}
+
+func (c *mockDistributorClient) Handle(ctx context.Context, req *httpgrpc.HTTPRequest, _ ...grpc.CallOption) (*httpgrpc.HTTPResponse, error) {
+	return c.server.Handle(ctx, req)
+}
+
+func (c *mockDistributorClient) Check(context.Context, *grpc_health_v1.HealthCheckRequest, ...grpc.CallOption) (*grpc_health_v1.HealthCheckResponse, error) {
+	return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+}
+
+func (c *mockDistributorClient) Watch(context.Context, *grpc_health_v1.HealthCheckRequest, ...grpc.CallOption) (grpc_health_v1.Health_WatchClient, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *mockDistributorClient) Close() error {
+	return nil
+}
@@ -11,6 +11,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
@@ -35,6 +36,7 @@ import (
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/encoding/protowire"
 
 	"github.com/grafana/mimir/pkg/ingester/client"
 	"github.com/grafana/mimir/pkg/mimirpb"
@@ -51,6 +53,82 @@ func TestHandler_remoteWrite(t *testing.T) {
 	assert.Equal(t, 200, resp.Code)
 }
 
+func TestHandler_remoteWrite2(t *testing.T) {
+	limits, err := validation.NewOverrides(validation.Limits{}, validation.NewMockTenantLimits(map[string]*validation.Limits{
+		"ct-enabled": {CreatedTimestampZeroIngestionEnabled: true},
+	}))
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		tenantID        string
+		contentType     string
+		expectedCode    int
+		expectedSamples []mimirpb.Sample
+		expectedHeaders map[string]string
+	}{
+		"remote write 2.0 request": {
+			tenantID:        "test",
+			contentType:     "application/x-protobuf;proto=io.prometheus.write.v2.Request",
+			expectedCode:    http.StatusOK,
+			expectedSamples: []mimirpb.Sample{{TimestampMs: 2000, Value: 1}},
+			expectedHeaders: map[string]string{
+				"X-Prometheus-Remote-Write-Samples-Written":    "1",
+				"X-Prometheus-Remote-Write-Histograms-Written": "0",
+				"X-Prometheus-Remote-Write-Exemplars-Written":  "0",
+			},
+		},
+		"remote write 2.0 request with created timestamp zero ingestion enabled": {
+			tenantID:        "ct-enabled",
+			contentType:     "application/x-protobuf;proto=io.prometheus.write.v2.Request",
+			expectedCode:    http.StatusOK,
+			expectedSamples: []mimirpb.Sample{{TimestampMs: 1000, Value: 0}, {TimestampMs: 2000, Value: 1}},
+			expectedHeaders: map[string]string{
+				"X-Prometheus-Remote-Write-Samples-Written":    "2",
+				"X-Prometheus-Remote-Write-Histograms-Written": "0",
+				"X-Prometheus-Remote-Write-Exemplars-Written":  "0",
+			},
+		},
+		"unsupported protobuf message": {
+			tenantID:     "test",
+			contentType:  "application/x-protobuf;proto=io.prometheus.write.v3.Request",
+			expectedCode: http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := createRequest(t, createPrometheusRemoteWrite2Protobuf())
+			req.Header.Set("Content-Type", tc.contentType)
+			req.Header.Set("X-Prometheus-Remote-Write-Version", "2.0.0")
+			req = req.WithContext(user.InjectOrgID(context.Background(), tc.tenantID))
+
+			pushed := false
+			handler := Handler(100000, nil, false, limits, RetryConfig{}, func(_ context.Context, pushReq *Request) error {
+				request, err := pushReq.WriteRequest()
+				if err != nil {
+					return err
+				}
+				t.Cleanup(pushReq.CleanUp)
+				pushed = true
+
+				require.Len(t, request.Timeseries, 1)
+				assert.Equal(t, []mimirpb.LabelAdapter{{Name: "__name__", Value: "foo_total"}, {Name: "job", Value: "test"}}, request.Timeseries[0].Labels)
+				assert.Equal(t, tc.expectedSamples, request.Timeseries[0].Samples)
+				assert.Equal(t, []*mimirpb.MetricMetadata{{Type: mimirpb.COUNTER, MetricFamilyName: "foo_total", Help: "Total number of foos."}}, request.Metadata)
+				return nil
+			}, log.NewNopLogger())
+
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+			assert.Equal(t, tc.expectedCode, resp.Code)
+			assert.Equal(t, tc.expectedCode == http.StatusOK, pushed)
+			for name, value := range tc.expectedHeaders {
+				assert.Equal(t, value, resp.Header().Get(name), name)
+			}
+		})
+	}
+}
+
 func TestOTelMetricsToMetadata(t *testing.T) {
 	otelMetrics := pmetric.NewMetrics()
 	rs := otelMetrics.ResourceMetrics().AppendEmpty()
@@ -656,6 +734,38 @@ func createPrometheusRemoteWriteProtobuf(t testing.TB) []byte {
 	return inputBytes
 }
 
+// createPrometheusRemoteWrite2Protobuf returns a remote write 2.0 request with a single counter series.
+func createPrometheusRemoteWrite2Protobuf() []byte {
+	var refs, sample, metadata, series, req []byte
+	for _, ref := range []uint64{1, 2, 3, 4} {
+		refs = protowire.AppendVarint(refs, ref)
+	}
+	sample = protowire.AppendTag(sample, 1, protowire.Fixed64Type)
+	sample = protowire.AppendFixed64(sample, math.Float64bits(1))
+	sample = protowire.AppendTag(sample, 2, protowire.VarintType)
+	sample = protowire.AppendVarint(sample, 2000)
+	metadata = protowire.AppendTag(metadata, 1, protowire.VarintType)
+	metadata = protowire.AppendVarint(metadata, uint64(mimirpb.COUNTER))
+	metadata = protowire.AppendTag(metadata, 3, protowire.VarintType)
+	metadata = protowire.AppendVarint(metadata, 5)
+
+	series = protowire.AppendTag(series, 1, protowire.BytesType)
+	series = protowire.AppendBytes(series, refs)
+	series = protowire.AppendTag(series, 2, protowire.BytesType)
+	series = protowire.AppendBytes(series, sample)
+	series = protowire.AppendTag(series, 5, protowire.BytesType)
+	series = protowire.AppendBytes(series, metadata)
+	series = protowire.AppendTag(series, 6, protowire.VarintType)
+	series = protowire.AppendVarint(series, 1000)
+
+	for _, symbol := range []string{"", "__name__", "foo_total", "job", "test", "Total number of foos."} {
+		req = protowire.AppendTag(req, 4, protowire.BytesType)
+		req = protowire.AppendString(req, symbol)
+	}
+	req = protowire.AppendTag(req, 5, protowire.BytesType)
+	return protowire.AppendBytes(req, series)
+}
+
 func createMimirWriteRequestProtobuf(t *testing.T, skipLabelNameValidation bool) []byte {
 	t.Helper()
 	h := remote.HistogramToHistogramProto(1337, test.GenerateTestHistogram(1))
@@ -0,0 +1,564 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"context"
+	"math"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/tenant"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/util"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+const (
+	// streamAggregationFlushInterval is the interval at which the aggregation windows are checked for completion.
+	streamAggregationFlushInterval = time.Second
+
+	// streamAggregatorLabel is the label added to the aggregated series, set to the distributor instance ID,
+	// because each distributor only aggregates the samples it receives.
+	streamAggregatorLabel = "aggregator"
+
+	// streamAggregationStaleWindows is the number of windows after which an input series that hasn't
+	// received any sample is forgotten.
+	streamAggregationStaleWindows = 2
+
+	// streamAggregationStripes is the number of stripes the outputs of an aggregation are sharded into, so that
+	// the samples of different outputs can be aggregated concurrently.
+	streamAggregationStripes = 64
+
+	streamAggregationInputSeriesLimit  = "stream_aggregation_input_series_limit"
+	streamAggregationOutputSeriesLimit = "stream_aggregation_output_series_limit"
+)
+
+// streamAggregator aggregates the samples of the series matching the stream aggregation rules of the
+// tenants, and pushes the aggregated series at the end of each aggregation window.
+type streamAggregator struct {
+	services.Service
+
+	limits     *validation.Overrides
+	instanceID string
+	logger     log.Logger
+
+	// push pushes the aggregated series through the push middlewares following the aggregation.
+	push PushFunc
+
+	// mtx protects aggregations, but not the state of each aggregation, which is sharded into stripes.
+	mtx          sync.RWMutex
+	aggregations map[string]map[string]*streamAggregation // First key = user, second key = rule key.
+	series       map[string]*streamAggregationSeries      // Key = user.
+
+	inputSamples               *prometheus.CounterVec
+	pushFailures               *prometheus.CounterVec
+	discardedInputSeriesLimit  *prometheus.CounterVec
+	discardedOutputSeriesLimit *prometheus.CounterVec
+}
+
+// streamAggregationSeries counts the input and output series aggregated for a tenant, across all its rules.
+type streamAggregationSeries struct {
+	inputs  atomic.Int64
+	outputs atomic.Int64
+}
+
+// reserveStreamAggregationSeries adds a series to the counter, unless the limit is reached. A limit of 0 disables
+// the limit.
+func reserveStreamAggregationSeries(counter *atomic.Int64, limit int) bool {
+	if counter.Add(1) > int64(limit) && limit > 0 {
+		counter.Add(-1)
+		return false
+	}
+	return true
+}
+
+// streamAggregation holds the state of the aggregated series of a rule.
+type streamAggregation struct {
+	// The rule may be reloaded while aggregating, with a different drop_input.
+	rule atomic.Pointer[validation.StreamAggregationRule]
+
+	// windowEnd is only accessed by the flush, once the aggregation has been created.
+	windowEnd time.Time
+
+	// series is shared by all the aggregations of the tenant.
+	series *streamAggregationSeries
+
+	stripes [streamAggregationStripes]streamAggregationStripe
+}
+
+type streamAggregationStripe struct {
+	mtx     sync.Mutex
+	outputs map[string]*streamAggregationOutput
+
+	// removed is set once the rule has been removed, so that its series aren't counted anymore.
+	removed bool
+}
+
+type streamAggregationOutput struct {
+	metric   string
+	labels   labels.Labels // Grouping labels, without the metric name.
+	inputs   map[string]*streamAggregationInput
+	samples  int
+	min, max float64
+	total    float64
+}
+
+type streamAggregationInput struct {
+	last     float64 // Last value received in the current window.
+	inWindow bool
+	counter  float64 // Last value received, used to compute the increase of counters.
+	lastSeen time.Time
+}
+
+func newStreamAggregator(limits *validation.Overrides, instanceID string, reg prometheus.Registerer, logger log.Logger) *streamAggregator {
+	a := &streamAggregator{
+		limits:       limits,
+		instanceID:   instanceID,
+		logger:       logger,
+		aggregations: map[string]map[string]*streamAggregation{},
+		series:       map[string]*streamAggregationSeries{},
+
+		inputSamples: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_distributor_stream_aggregation_input_samples_total",
+			Help: "The total number of samples aggregated by the stream aggregation rules.",
+		}, []string{"user"}),
+		pushFailures: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_distributor_stream_aggregation_push_failures_total",
+			Help: "The total number of failed pushes of aggregated series.",
+		}, []string{"user"}),
+		discardedInputSeriesLimit:  validation.DiscardedSamplesCounter(reg, streamAggregationInputSeriesLimit),
+		discardedOutputSeriesLimit: validation.DiscardedSamplesCounter(reg, streamAggregationOutputSeriesLimit),
+	}
+	a.Service = services.NewTimerService(streamAggregationFlushInterval, nil, a.iteration, nil).WithName("stream aggregator")
+	return a
+}
+
+// prePushStreamAggregationMiddleware aggregates the series matching the stream aggregation rules of the tenant,
+// and drops them from the request if the rules are configured so.
+func (d *Distributor) prePushStreamAggregationMiddleware(next PushFunc) PushFunc {
+	// The aggregation runs on the validated series, while the aggregated series are validated on their own.
+	d.streamAggregator.push = d.streamAggregationLimitsMiddleware(d.prePushValidationMiddleware(next))
+
+	return func(ctx context.Context, pushReq *Request) error {
+		cleanupInDefer := true
+		defer func() {
+			if cleanupInDefer {
+				pushReq.CleanUp()
+			}
+		}()
+
+		userID, err := tenant.TenantID(ctx)
+		if err != nil {
+			return err
+		}
+
+		rules := d.limits.StreamAggregationRules(userID)
+		if len(rules) == 0 {
+			cleanupInDefer = false
+			return next(ctx, pushReq)
+		}
+
+		req, err := pushReq.WriteRequest()
+		if err != nil {
+			return err
+		}
+
+		removeTsIndexes := d.streamAggregator.aggregate(userID, rules, req.Timeseries, time.Now())
+		if len(removeTsIndexes) > 0 {
+			for _, removeTsIndex := range removeTsIndexes {
+				mimirpb.ReusePreallocTimeseries(&req.Timeseries[removeTsIndex])
+			}
+			req.Timeseries = util.RemoveSliceIndexes(req.Timeseries, removeTsIndexes)
+		}
+
+		if len(req.Timeseries) == 0 && len(req.Metadata) == 0 {
+			return nil
+		}
+
+		cleanupInDefer = false
+		return next(ctx, pushReq)
+	}
+}
+
+// streamAggregationLimitsMiddleware enforces the instance limits on the pushes of the aggregated series, like the
+// limitsMiddleware does for the received requests. The per-tenant request rate limit isn't enforced, because the
+// aggregated series are pushed by the distributor itself, once per tenant and aggregation window, while the
+// per-tenant ingestion rate limit is enforced by the validation.
+func (d *Distributor) streamAggregationLimitsMiddleware(next PushFunc) PushFunc {
+	return func(ctx context.Context, pushReq *Request) error {
+		ctx, rs, err := d.startPushRequest(ctx, -1)
+		if err != nil {
+			pushReq.CleanUp()
+			return err
+		}
+
+		rs.pushHandlerPerformsCleanup = true
+		pushReq.AddCleanup(func() {
+			d.cleanupAfterPushFinished(rs)
+		})
+
+		req, err := pushReq.WriteRequest()
+		if err != nil {
+			pushReq.CleanUp()
+			return err
+		}
+		if err := d.checkWriteRequestSize(rs, int64(req.Size())); err != nil {
+			pushReq.CleanUp()
+			return err
+		}
+
+		return next(ctx, pushReq)
+	}
+}
+
+// aggregate adds the samples of the series matching the rules to their aggregations, and returns
+// the indexes of the series to drop. The series which can't be aggregated by a rule because of the series limits
+// aren't dropped, so that their samples aren't lost.
+func (a *streamAggregator) aggregate(userID string, rules []*validation.StreamAggregationRule, timeseries []mimirpb.PreallocTimeseries, now time.Time) []int {
+	aggregations := a.userAggregations(userID, rules, now)
+	maxInputs, maxOutputs := a.limits.StreamAggregationMaxInputSeries(userID), a.limits.StreamAggregationMaxOutputSeries(userID)
+
+	var (
+		removeIndexes                             []int
+		inputSamples                              int
+		inputLimitedSamples, outputLimitedSamples int
+	)
+	for tsIdx, ts := range timeseries {
+		series := mimirpb.FromLabelAdaptersToLabels(ts.Labels)
+
+		drop, keep := false, false
+		for i, rule := range rules {
+			// The matchers of a rule which hasn't been validated are empty.
+			if len(rule.Matchers()) == 0 || !seriesMatches(rule.Matchers(), series) {
+				continue
+			}
+
+			if len(ts.Samples) > 0 {
+				switch aggregations[i].add(rule, series, ts.Samples, now, maxInputs, maxOutputs) {
+				case streamAggregationInputSeriesLimit:
+					inputLimitedSamples += len(ts.Samples)
+					keep = true
+					continue
+				case streamAggregationOutputSeriesLimit:
+					outputLimitedSamples += len(ts.Samples)
+					keep = true
+					continue
+				}
+				inputSamples += len(ts.Samples)
+			}
+			drop = drop || rule.DropInput
+		}
+		if drop && !keep {
+			removeIndexes = append(removeIndexes, tsIdx)
+		}
+	}
+	if inputSamples > 0 {
+		a.inputSamples.WithLabelValues(userID).Add(float64(inputSamples))
+	}
+	if inputLimitedSamples > 0 {
+		a.discardedInputSeriesLimit.WithLabelValues(userID, "").Add(float64(inputLimitedSamples))
+	}
+	if outputLimitedSamples > 0 {
+		a.discardedOutputSeriesLimit.WithLabelValues(userID, "").Add(float64(outputLimitedSamples))
+	}
+	return removeIndexes
+}
+
+// userAggregations returns the aggregations of the rules of the tenant, creating the ones which don't exist.
+func (a *streamAggregator) userAggregations(userID string, rules []*validation.StreamAggregationRule, now time.Time) []*streamAggregation {
+	aggregations := make([]*streamAggregation, len(rules))
+
+	a.mtx.RLock()
+	missing := false
+	for i, rule := range rules {
+		if aggregations[i] = a.aggregations[userID][rule.Key()]; aggregations[i] == nil {
+			missing = true
+		}
+	}
+	a.mtx.RUnlock()
+
+	if missing {
+		a.mtx.Lock()
+		for i, rule := range rules {
+			if aggregations[i] == nil {
+				aggregations[i] = a.aggregation(userID, rule, now)
+			}
+		}
+		a.mtx.Unlock()
+	}
+
+	for i, rule := range rules {
+		aggregations[i].rule.Store(rule)
+	}
+	return aggregations
+}
+
+// aggregation returns the aggregation of the rule, creating it if it doesn't exist. Must be called with mtx held.
+func (a *streamAggregator) aggregation(userID string, rule *validation.StreamAggregationRule, now time.Time) *streamAggregation {
+	userAggregations := a.aggregations[userID]
+	if userAggregations == nil {
+		userAggregations = map[string]*streamAggregation{}
+		a.aggregations[userID] = userAggregations
+		a.series[userID] = &streamAggregationSeries{}
+	}
+
+	key := rule.Key()
+	agg := userAggregations[key]
+	if agg == nil {
+		interval := time.Duration(rule.Interval)
+		agg = &streamAggregation{
+			windowEnd: now.Truncate(interval).Add(interval),
+			series:    a.series[userID],
+		}
+		agg.rule.Store(rule)
+		userAggregations[key] = agg
+	}
+	return agg
+}
+
+// add aggregates the samples of the input series. Returns the discard reason if the input series can't be aggregated
+// because of the limits of the series aggregated for the tenant, or an empty string otherwise.
+func (g *streamAggregation) add(rule *validation.StreamAggregationRule, series labels.Labels, samples []mimirpb.Sample, now time.Time, maxInputs, maxOutputs int) string {
+	var grouping labels.Labels
+	if len(rule.By) > 0 {
+		grouping = series.MatchLabels(true, rule.By...)
+	} else {
+		grouping = series.MatchLabels(false, rule.Without...)
+	}
+	metric := series.Get(labels.MetricName)
+
+	outputKey := metric + "\xff" + grouping.String()
+	stripe := &g.stripes[mimirpb.HashAdd32a(mimirpb.HashNew32a(), outputKey)%streamAggregationStripes]
+
+	stripe.mtx.Lock()
+	defer stripe.mtx.Unlock()
+
+	if stripe.removed {
+		return ""
+	}
+
+	inputKey := series.String()
+	out := stripe.outputs[outputKey]
+	var in *streamAggregationInput
+	if out != nil {
+		in = out.inputs[inputKey]
+	}
+	if out == nil && !reserveStreamAggregationSeries(&g.series.outputs, maxOutputs) {
+		return streamAggregationOutputSeriesLimit
+	}
+	if in == nil && !reserveStreamAggregationSeries(&g.series.inputs, maxInputs) {
+		if out == nil {
+			g.series.outputs.Add(-1)
+		}
+		return streamAggregationInputSeriesLimit
+	}
+
+	if out == nil {
+		// The labels are copied, because they're backed by the request buffers.
+		b := labels.NewScratchBuilder(grouping.Len())
+		grouping.Range(func(l labels.Label) {
+			b.Add(strings.Clone(l.Name), strings.Clone(l.Value))
+		})
+		b.Sort()
+		out = &streamAggregationOutput{
+			metric: strings.Clone(metric),
+			labels: b.Labels(),
+			inputs: map[string]*streamAggregationInput{},
+		}
+		if stripe.outputs == nil {
+			stripe.outputs = map[string]*streamAggregationOutput{}
+		}
+		stripe.outputs[outputKey] = out
+	}
+
+	newInput := in == nil
+	if newInput {
+		in = &streamAggregationInput{}
+		out.inputs[inputKey] = in
+	}
+
+	for _, s := range samples {
+		if out.samples == 0 {
+			out.min, out.max = s.Value, s.Value
+		} else {
+			out.min, out.max = math.Min(out.min, s.Value), math.Max(out.max, s.Value)
+		}
+		out.samples++
+
+		// The first value of an input series is its baseline, so that only its increase is
+		// accounted for the total.
+		if !newInput {
+			if s.Value >= in.counter {
+				out.total += s.Value - in.counter
+			} else {
+				// Counter reset.
+				out.total += s.Value
+			}
+		}
+		newInput = false
+		in.counter = s.Value
+		in.last = s.Value
+	}
+	in.inWindow = true
+	in.lastSeen = now
+	return ""
+}
+
+func (a *streamAggregator) iteration(_ context.Context) error {
+	a.flush(time.Now())
+	return nil
+}
+
+// flush pushes the aggregated series of the windows that ended before now.
+func (a *streamAggregator) flush(now time.Time) {
+	ended := map[string][]*streamAggregation{}
+
+	a.mtx.Lock()
+	for userID, userAggregations := range a.aggregations {
+		rules := map[string]bool{}
+		for _, rule := range a.limits.StreamAggregationRules(userID) {
+			rules[rule.Key()] = true
+		}
+
+		for key, agg := range userAggregations {
+			if !rules[key] {
+				// The rule has been removed.
+				delete(userAggregations, key)
+				agg.remove()
+				continue
+			}
+			if now.Before(agg.windowEnd) {
+				continue
+			}
+			ended[userID] = append(ended[userID], agg)
+		}
+		if len(userAggregations) == 0 {
+			delete(a.aggregations, userID)
+			delete(a.series, userID)
+		}
+	}
+	a.mtx.Unlock()
+
+	for userID, aggregations := range ended {
+		var timeseries []mimirpb.PreallocTimeseries
+		for _, agg := range aggregations {
+			timeseries = append(timeseries, agg.flush(a.instanceID, now)...)
+		}
+		if len(timeseries) == 0 {
+			continue
+		}
+		req := &mimirpb.WriteRequest{Timeseries: timeseries, Source: mimirpb.API}
+		if err := a.push(user.InjectOrgID(context.Background(), userID), NewParsedRequest(req)); err != nil {
+			a.pushFailures.WithLabelValues(userID).Inc()
+			level.Warn(a.logger).Log("msg", "failed to push aggregated series", "user", userID, "err", err)
+		}
+	}
+}
+
+// remove releases the series of the aggregation of a removed rule from the series aggregated for the tenant.
+func (g *streamAggregation) remove() {
+	for i := range g.stripes {
+		s := &g.stripes[i]
+		s.mtx.Lock()
+		for _, out := range s.outputs {
+			g.series.inputs.Add(-int64(len(out.inputs)))
+		}
+		g.series.outputs.Add(-int64(len(s.outputs)))
+		s.outputs = nil
+		s.removed = true
+		s.mtx.Unlock()
+	}
+}
+
+// flush returns the aggregated series of the current window, and starts a new window.
+func (g *streamAggregation) flush(instanceID string, now time.Time) []mimirpb.PreallocTimeseries {
+	rule := g.rule.Load()
+	interval := time.Duration(rule.Interval)
+	ts := g.windowEnd.UnixMilli()
+
+	var timeseries []mimirpb.PreallocTimeseries
+	for i := range g.stripes {
+		timeseries = g.stripes[i].flush(rule, g.series, instanceID, ts, now, timeseries)
+	}
+
+	g.windowEnd = now.Truncate(interval).Add(interval)
+	return timeseries
+}
+
+// flush appends the aggregated series of the outputs of the stripe to timeseries, and resets the outputs
+// for the next window.
+func (s *streamAggregationStripe) flush(rule *validation.StreamAggregationRule, series *streamAggregationSeries, instanceID string, ts int64, now time.Time, timeseries []mimirpb.PreallocTimeseries) []mimirpb.PreallocTimeseries {
+	interval := time.Duration(rule.Interval)
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	for key, out := range s.outputs {
+		var sum float64
+		var count int
+		for inputKey, in := range out.inputs {
+			if in.inWindow {
+				sum += in.last
+				count++
+				in.inWindow = false
+			} else if now.Sub(in.lastSeen) > streamAggregationStaleWindows*interval {
+				delete(out.inputs, inputKey)
+				series.inputs.Add(-1)
+			}
+		}
+		if len(out.inputs) == 0 {
+			delete(s.outputs, key)
+			series.outputs.Add(-1)
+			continue
+		}
+		if count == 0 {
+			continue
+		}
+
+		for _, output := range rule.Outputs {
+			var value float64
+			switch output {
+			case validation.StreamAggregationOutputTotal:
+				value = out.total
+			case validation.StreamAggregationOutputSum:
+				value = sum
+			case validation.StreamAggregationOutputCount:
+				value = float64(count)
+			case validation.StreamAggregationOutputMin:
+				value = out.min
+			case validation.StreamAggregationOutputMax:
+				value = out.max
+			}
+
+			b := labels.NewBuilder(out.labels)
+			b.Set(labels.MetricName, rule.OutputMetricName(out.metric, output))
+			b.Set(streamAggregatorLabel, instanceID)
+			timeseries = append(timeseries, mimirpb.PreallocTimeseries{TimeSeries: &mimirpb.TimeSeries{
+				Labels:  mimirpb.FromLabelsToLabelAdapters(b.Labels()),
+				Samples: []mimirpb.Sample{{TimestampMs: ts, Value: value}},
+			}})
+		}
+		out.samples = 0
+	}
+	return timeseries
+}
+
+func (a *streamAggregator) cleanupUserMetrics(userID string) {
+	a.inputSamples.DeleteLabelValues(userID)
+	a.pushFailures.DeleteLabelValues(userID)
+
+	filter := prometheus.Labels{"user": userID}
+	a.discardedInputSeriesLimit.DeletePartialMatch(filter)
+	a.discardedOutputSeriesLimit.DeletePartialMatch(filter)
+}
@@ -0,0 +1,275 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package distributor
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/flagext"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+func TestStreamAggregator(t *testing.T) {
+	rule := &validation.StreamAggregationRule{
+		Match:    `{__name__="http_requests_total"}`,
+		Interval: model.Duration(time.Minute),
+		By:       []string{"job"},
+		Outputs: []string{
+			validation.StreamAggregationOutputTotal,
+			validation.StreamAggregationOutputSum,
+			validation.StreamAggregationOutputCount,
+			validation.StreamAggregationOutputMin,
+			validation.StreamAggregationOutputMax,
+		},
+	}
+	require.NoError(t, rule.Validate())
+	a := newStreamAggregatorForTest(t, rule)
+
+	var pushed []*mimirpb.WriteRequest
+	a.push = func(ctx context.Context, pushReq *Request) error {
+		userID, err := user.ExtractOrgID(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "user", userID)
+
+		req, err := pushReq.WriteRequest()
+		require.NoError(t, err)
+		pushed = append(pushed, req)
+		return nil
+	}
+
+	series := func(pod string, value float64) mimirpb.PreallocTimeseries {
+		return makeTimeseries([]string{labels.MetricName, "http_requests_total", "job", "api", "pod", pod}, makeSamples(0, value), nil)
+	}
+	other := makeTimeseries([]string{labels.MetricName, "other", "job", "api"}, makeSamples(0, 1), nil)
+
+	start := time.Date(2024, 1, 1, 10, 0, 10, 0, time.UTC)
+	rules := []*validation.StreamAggregationRule{rule}
+
+	// The first values of the input series are their baselines.
+	assert.Empty(t, a.aggregate("user", rules, []mimirpb.PreallocTimeseries{series("1", 10), series("2", 5), other}, start))
+	// The second input series has been reset.
+	assert.Empty(t, a.aggregate("user", rules, []mimirpb.PreallocTimeseries{series("1", 15), series("2", 2)}, start.Add(20*time.Second)))
+
+	// The window hasn't ended yet.
+	a.flush(start.Add(49 * time.Second))
+	require.Empty(t, pushed)
+
+	windowEnd := time.Date(2024, 1, 1, 10, 1, 0, 0, time.UTC)
+	a.flush(windowEnd)
+	require.Len(t, pushed, 1)
+
+	actual := map[string]float64{}
+	for _, ts := range pushed[0].Timeseries {
+		lbls := mimirpb.FromLabelAdaptersToLabels(ts.Labels)
+		assert.Equal(t, "api", lbls.Get("job"))
+		assert.Equal(t, "distributor-1", lbls.Get(streamAggregatorLabel))
+		assert.Empty(t, lbls.Get("pod"))
+		require.Len(t, ts.Samples, 1)
+		assert.Equal(t, windowEnd.UnixMilli(), ts.Samples[0].TimestampMs)
+		actual[lbls.Get(labels.MetricName)] = ts.Samples[0].Value
+	}
+	assert.Equal(t, map[string]float64{
+		"http_requests_total:1m_by_job_total": 7,
+		"http_requests_total:1m_by_job_sum":   17,
+		"http_requests_total:1m_by_job_count": 2,
+		"http_requests_total:1m_by_job_min":   2,
+		"http_requests_total:1m_by_job_max":   15,
+	}, actual)
+
+	// Nothing is pushed for a window without samples, and the input series are eventually forgotten.
+	a.flush(windowEnd.Add(time.Minute))
+	a.flush(windowEnd.Add(3 * time.Minute))
+	require.Len(t, pushed, 1)
+	for i := range a.aggregations["user"][rule.Key()].stripes {
+		assert.Empty(t, a.aggregations["user"][rule.Key()].stripes[i].outputs)
+	}
+}
+
+func TestStreamAggregator_Concurrency(t *testing.T) {
+	rule := &validation.StreamAggregationRule{
+		Match:    `{__name__="http_requests_total"}`,
+		Interval: model.Duration(time.Minute),
+		By:       []string{"job"},
+		Outputs:  []string{validation.StreamAggregationOutputCount},
+	}
+	require.NoError(t, rule.Validate())
+	a := newStreamAggregatorForTest(t, rule)
+
+	var (
+		mtx    sync.Mutex
+		pushed int
+	)
+	a.push = func(_ context.Context, pushReq *Request) error {
+		req, err := pushReq.WriteRequest()
+		require.NoError(t, err)
+
+		mtx.Lock()
+		defer mtx.Unlock()
+		pushed += len(req.Timeseries)
+		return nil
+	}
+
+	const jobs = 10
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	rules := []*validation.StreamAggregationRule{rule}
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < jobs; i++ {
+		wg.Add(1)
+		go func(job string) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				series := makeTimeseries([]string{labels.MetricName, "http_requests_total", "job", job, "pod", strconv.Itoa(j)}, makeSamples(0, 1), nil)
+				a.aggregate("user", rules, []mimirpb.PreallocTimeseries{series}, start)
+			}
+		}(strconv.Itoa(i))
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < 100; j++ {
+			a.flush(start)
+		}
+	}()
+	wg.Wait()
+
+	// There's one output series per job.
+	a.flush(start.Add(time.Minute))
+	assert.Equal(t, jobs, pushed)
+}
+
+func TestStreamAggregator_SeriesLimits(t *testing.T) {
+	rule := &validation.StreamAggregationRule{
+		Match:     `{__name__="http_requests_total"}`,
+		Interval:  model.Duration(time.Minute),
+		By:        []string{"job"},
+		Outputs:   []string{validation.StreamAggregationOutputCount},
+		DropInput: true,
+	}
+	require.NoError(t, rule.Validate())
+
+	limits := validation.Limits{}
+	flagext.DefaultValues(&limits)
+	limits.StreamAggregationRules = []*validation.StreamAggregationRule{rule}
+	limits.StreamAggregationMaxInputSeries = 3
+	limits.StreamAggregationMaxOutputSeries = 2
+	overrides, err := validation.NewOverrides(limits, nil)
+	require.NoError(t, err)
+
+	reg := prometheus.NewPedanticRegistry()
+	a := newStreamAggregator(overrides, "distributor-1", reg, log.NewNopLogger())
+	a.push = func(context.Context, *Request) error { return nil }
+
+	series := func(job, pod string) mimirpb.PreallocTimeseries {
+		return makeTimeseries([]string{labels.MetricName, "http_requests_total", "job", job, "pod", pod}, makeSamples(0, 1), nil)
+	}
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	rules := []*validation.StreamAggregationRule{rule}
+
+	assert.Equal(t, []int{0, 1, 2}, a.aggregate("user", rules, []mimirpb.PreallocTimeseries{series("a", "1"), series("a", "2"), series("b", "1")}, start))
+
+	// The series exceeding the limits aren't aggregated, and aren't dropped.
+	assert.Equal(t, []int{2}, a.aggregate("user", rules, []mimirpb.PreallocTimeseries{series("c", "1"), series("a", "3"), series("a", "1")}, start))
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+		# HELP cortex_discarded_samples_total The total number of samples that were discarded.
+		# TYPE cortex_discarded_samples_total counter
+		cortex_discarded_samples_total{group="",reason="stream_aggregation_input_series_limit",user="user"} 1
+		cortex_discarded_samples_total{group="",reason="stream_aggregation_output_series_limit",user="user"} 1
+	`), "cortex_discarded_samples_total"))
+
+	// Once the input series are forgotten, new series can be aggregated.
+	a.flush(start.Add(time.Minute))
+	a.flush(start.Add(4 * time.Minute))
+	assert.Equal(t, int64(0), a.series["user"].inputs.Load())
+	assert.Equal(t, int64(0), a.series["user"].outputs.Load())
+	assert.Equal(t, []int{0, 1, 2}, a.aggregate("user", rules, []mimirpb.PreallocTimeseries{series("c", "1"), series("a", "3"), series("a", "1")}, start.Add(4*time.Minute)))
+}
+
+func TestDistributor_StreamAggregationMiddleware(t *testing.T) {
+	rule := &validation.StreamAggregationRule{
+		Match:     `{__name__="http_requests_total"}`,
+		Interval:  model.Duration(time.Minute),
+		Without:   []string{"pod"},
+		Outputs:   []string{validation.StreamAggregationOutputSum},
+		DropInput: true,
+	}
+	require.NoError(t, rule.Validate())
+
+	limits := prepareDefaultLimits()
+	limits.StreamAggregationRules = []*validation.StreamAggregationRule{rule}
+
+	var received []string
+	receive := func(PushFunc) PushFunc {
+		return func(_ context.Context, pushReq *Request) error {
+			defer pushReq.CleanUp()
+
+			req, err := pushReq.WriteRequest()
+			require.NoError(t, err)
+			for _, ts := range req.Timeseries {
+				received = append(received, fmt.Sprintf("%s %v", mimirpb.FromLabelAdaptersToLabels(ts.Labels).String(), ts.Samples[0].Value))
+			}
+			return nil
+		}
+	}
+
+	ds, _, _, _ := prepare(t, prepConfig{
+		numIngesters:    3,
+		happyIngesters:  3,
+		numDistributors: 1,
+		limits:          limits,
+		configure: func(cfg *Config) {
+			cfg.PushWrappers = []PushWrapper{receive}
+		},
+	})
+	d := ds[0]
+
+	ctx := user.InjectOrgID(context.Background(), "user")
+	now := time.Now()
+	_, err := d.Push(ctx, &mimirpb.WriteRequest{Timeseries: []mimirpb.PreallocTimeseries{
+		makeTimeseries([]string{labels.MetricName, "http_requests_total", "pod", "1"}, makeSamples(now.UnixMilli(), 1), nil),
+		makeTimeseries([]string{labels.MetricName, "other", "pod", "1"}, makeSamples(now.UnixMilli(), 1), nil),
+		// The series failing the validation isn't aggregated.
+		makeTimeseries([]string{labels.MetricName, "http_requests_total", "pod", "2"}, makeSamples(now.Add(time.Hour).UnixMilli(), 100), nil),
+	}})
+	require.ErrorContains(t, err, "too far in the future")
+	assert.Equal(t, []string{`{__name__="other", pod="1"} 1`}, received)
+
+	// The request isn't forwarded if all its series are dropped.
+	received = nil
+	_, err = d.Push(ctx, &mimirpb.WriteRequest{Timeseries: []mimirpb.PreallocTimeseries{
+		makeTimeseries([]string{labels.MetricName, "http_requests_total", "pod", "3"}, makeSamples(now.UnixMilli(), 2), nil),
+	}})
+	require.NoError(t, err)
+	assert.Empty(t, received)
+
+	// The aggregated series are validated and pushed through the following middlewares.
+	d.streamAggregator.flush(now.Add(time.Minute))
+	assert.Equal(t, []string{`{__name__="http_requests_total:1m_without_pod_sum", aggregator="0"} 3`}, received)
+}
+
+func newStreamAggregatorForTest(t *testing.T, rules ...*validation.StreamAggregationRule) *streamAggregator {
+	limits := validation.Limits{}
+	flagext.DefaultValues(&limits)
+	limits.StreamAggregationRules = rules
+
+	overrides, err := validation.NewOverrides(limits, nil)
+	require.NoError(t, err)
+
+	return newStreamAggregator(overrides, "distributor-1", prometheus.NewPedanticRegistry(), log.NewNopLogger())
+}
@@ -271,6 +271,9 @@ type roundTripperHandler struct {
 }
 
 func (rth roundTripperHandler) Do(ctx context.Context, r Request) (Response, error) {
+	// Propagate the estimated cost of the query, so that the query-scheduler can use it to fairly dequeue requests.
+	ctx = ContextWithQueryCost(ctx, EstimateQueryCost(r))
+
 	request, err := rth.codec.EncodeRequest(ctx, r)
 	if err != nil {
 		return nil, err
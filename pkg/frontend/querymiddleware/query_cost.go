@@ -0,0 +1,39 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querymiddleware
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+var queryCostCtxKey = contextKey(1)
+
+// EstimateQueryCost returns a rough estimate of the cost of executing the input request, computed
+// as the number of series the query is estimated to touch multiplied by the number of hours it spans.
+// When the number of series is not known, the query is assumed to touch a single series.
+func EstimateQueryCost(r Request) float64 {
+	series := float64(r.GetHints().GetEstimatedSeriesCount())
+	if series < 1 {
+		series = 1
+	}
+
+	hours := math.Ceil(float64(r.GetEnd()-r.GetStart()) / float64(time.Hour.Milliseconds()))
+	if hours < 1 {
+		hours = 1
+	}
+
+	return series * hours
+}
+
+// ContextWithQueryCost returns a new context with the estimated query cost stored in it.
+func ContextWithQueryCost(ctx context.Context, cost float64) context.Context {
+	return context.WithValue(ctx, queryCostCtxKey, cost)
+}
+
+// QueryCostFromContext returns the estimated query cost stored in the context, or 0 if not set.
+func QueryCostFromContext(ctx context.Context) float64 {
+	cost, _ := ctx.Value(queryCostCtxKey).(float64)
+	return cost
+}
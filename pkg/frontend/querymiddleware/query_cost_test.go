@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querymiddleware
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateQueryCost(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+
+	tests := map[string]struct {
+		request      Request
+		expectedCost float64
+	}{
+		"instant query without estimated series count": {
+			request:      &PrometheusInstantQueryRequest{Time: start},
+			expectedCost: 1,
+		},
+		"range query without estimated series count": {
+			request:      &PrometheusRangeQueryRequest{Start: start, End: start + (90 * time.Minute).Milliseconds()},
+			expectedCost: 2,
+		},
+		"range query with estimated series count": {
+			request: (&PrometheusRangeQueryRequest{Start: start, End: start + (3 * time.Hour).Milliseconds()}).
+				WithEstimatedSeriesCountHint(100),
+			expectedCost: 300,
+		},
+		"instant query with estimated series count": {
+			request:      (&PrometheusInstantQueryRequest{Time: start}).WithEstimatedSeriesCountHint(50),
+			expectedCost: 50,
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			assert.Equal(t, testData.expectedCost, EstimateQueryCost(testData.request))
+		})
+	}
+}
+
+func TestQueryCostFromContext(t *testing.T) {
+	assert.Zero(t, QueryCostFromContext(context.Background()))
+	assert.Equal(t, 12.5, QueryCostFromContext(ContextWithQueryCost(context.Background(), 12.5)))
+}
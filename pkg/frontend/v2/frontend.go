@@ -56,6 +56,7 @@ type Config struct {
 	Port int    `category:"advanced"`
 
 	AdditionalQueryQueueDimensionsEnabled bool `yaml:"additional_query_queue_dimensions_enabled" category:"experimental"`
+	CostBasedQueueFairnessEnabled         bool `yaml:"cost_based_queue_fairness_enabled" category:"experimental"`
 
 	// These configuration options are injected internally.
 	QuerySchedulerDiscovery schedulerdiscovery.Config `yaml:"-"`
@@ -74,6 +75,7 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet, logger log.Logger) {
 	f.IntVar(&cfg.Port, "query-frontend.instance-port", 0, "Port to advertise to querier (via scheduler) (defaults to server.grpc-listen-port).")
 
 	f.BoolVar(&cfg.AdditionalQueryQueueDimensionsEnabled, "query-frontend.additional-query-queue-dimensions-enabled", false, "Enqueue query requests with additional queue dimensions to split tenant request queues into subqueues. This enables separate requests to proceed from a tenant's subqueues even when other subqueues are blocked on slow query requests. Must be set on both query-frontend and scheduler to take effect. (default false)")
+	f.BoolVar(&cfg.CostBasedQueueFairnessEnabled, "query-frontend.cost-based-queue-fairness-enabled", false, "Enqueue query requests with their estimated cost, so that the query-scheduler can dequeue requests from the tenant which consumed the lowest estimated query cost. Must be set on both query-frontend and scheduler to take effect.")
 
 	cfg.GRPCClientConfig.RegisterFlagsWithPrefix("query-frontend.grpc-client-config", f)
 }
@@ -35,6 +35,11 @@ func (a *frontendToSchedulerAdapter) frontendToSchedulerEnqueueRequest(
 		}
 	}
 
+	var estimatedCost float64
+	if a.cfg.CostBasedQueueFairnessEnabled {
+		estimatedCost = querymiddleware.QueryCostFromContext(req.ctx)
+	}
+
 	return &schedulerpb.FrontendToScheduler{
 		Type:                      schedulerpb.ENQUEUE,
 		QueryID:                   req.queryID,
@@ -43,6 +48,7 @@ func (a *frontendToSchedulerAdapter) frontendToSchedulerEnqueueRequest(
 		FrontendAddress:           frontendAddr,
 		StatsEnabled:              req.statsEnabled,
 		AdditionalQueueDimensions: addlQueueDims,
+		EstimatedCost:             estimatedCost,
 	}, nil
 }
 
@@ -13,6 +13,8 @@ import (
 	"github.com/grafana/dskit/httpgrpc"
 	"github.com/grafana/dskit/user"
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/frontend/querymiddleware"
 )
 
 const rangeURLFormat = "/api/v1/query_range?end=%d&query=&start=%d&step=%d"
@@ -244,3 +246,27 @@ func TestQueryDecoding(t *testing.T) {
 		require.Contains(t, errHTTPDecode.Error(), "net/http")
 	})
 }
+
+func TestFrontendToSchedulerEnqueueRequest_EstimatedCost(t *testing.T) {
+	ctx := querymiddleware.ContextWithQueryCost(user.InjectOrgID(context.Background(), "tenant-0"), 42)
+	req := &frontendRequest{
+		queryID: 1,
+		userID:  "tenant-0",
+		request: &httpgrpc.HTTPRequest{Method: "GET", Url: "/api/v1/query?query=up"},
+		ctx:     ctx,
+	}
+
+	t.Run("cost-based queue fairness disabled", func(t *testing.T) {
+		adapter := &frontendToSchedulerAdapter{cfg: Config{}}
+		msg, err := adapter.frontendToSchedulerEnqueueRequest(req, "frontend:9095")
+		require.NoError(t, err)
+		require.Zero(t, msg.EstimatedCost)
+	})
+
+	t.Run("cost-based queue fairness enabled", func(t *testing.T) {
+		adapter := &frontendToSchedulerAdapter{cfg: Config{CostBasedQueueFairnessEnabled: true}}
+		msg, err := adapter.frontendToSchedulerEnqueueRequest(req, "frontend:9095")
+		require.NoError(t, err)
+		require.Equal(t, 42.0, msg.EstimatedCost)
+	})
+}
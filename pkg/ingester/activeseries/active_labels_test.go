@@ -39,7 +39,7 @@ func TestIsLabelValueActive(t *testing.T) {
 		labels.FromStrings("a", "5"),
 	}
 	allStorageRefs := []storage.SeriesRef{1, 2, 3, 4, 5}
-	activeSeries := NewActiveSeries(&Matchers{}, time.Duration(ttl))
+	activeSeries := NewActiveSeries(&Matchers{}, nil, time.Duration(ttl))
 
 	memPostings := index.NewMemPostings()
 	for i, l := range series {
@@ -24,7 +24,7 @@ func TestPostings_Expand(t *testing.T) {
 	}
 	allStorageRefs := []storage.SeriesRef{1, 2, 3, 4, 5}
 	storagePostings := index.NewListPostings(allStorageRefs)
-	activeSeries := NewActiveSeries(&Matchers{}, time.Duration(ttl))
+	activeSeries := NewActiveSeries(&Matchers{}, nil, time.Duration(ttl))
 
 	// Update each series at a different time according to its index.
 	for i := range allStorageRefs {
@@ -56,7 +56,7 @@ func TestPostings_Seek(t *testing.T) {
 	}
 	allStorageRefs := []storage.SeriesRef{1, 2, 3, 4, 5}
 	storagePostings := index.NewListPostings(allStorageRefs)
-	activeSeries := NewActiveSeries(&Matchers{}, time.Duration(ttl))
+	activeSeries := NewActiveSeries(&Matchers{}, nil, time.Duration(ttl))
 
 	// Update each series at a different time according to its index.
 	for i := range allStorageRefs {
@@ -88,7 +88,7 @@ func TestPostings_SeekToEnd(t *testing.T) {
 	}
 	allStorageRefs := []storage.SeriesRef{1, 2, 3, 4, 5}
 	storagePostings := index.NewListPostings(allStorageRefs)
-	activeSeries := NewActiveSeries(&Matchers{}, time.Duration(ttl))
+	activeSeries := NewActiveSeries(&Matchers{}, nil, time.Duration(ttl))
 
 	// Update each series at a different time according to its index.
 	for i := range allStorageRefs {
@@ -42,9 +42,10 @@ type ActiveSeries struct {
 	stripes [numStripes]seriesStripe
 	deleted deletedSeries
 
-	// matchersMutex protects matchers and lastMatchersUpdate.
+	// matchersMutex protects matchers, costAttribution and lastMatchersUpdate.
 	matchersMutex      sync.RWMutex
 	matchers           *Matchers
+	costAttribution    *CostAttribution
 	lastMatchersUpdate time.Time
 
 	// The duration after which series become inactive.
@@ -54,7 +55,8 @@ type ActiveSeries struct {
 
 // seriesStripe holds a subset of the series timestamps for a single tenant.
 type seriesStripe struct {
-	matchers *Matchers
+	matchers        *Matchers
+	costAttribution *CostAttribution
 
 	deleted *deletedSeries
 
@@ -71,6 +73,9 @@ type seriesStripe struct {
 	activeMatchingNativeHistograms       []uint32 // Number of active entries (only native histograms) in this stripe matching each matcher of the configured Matchers.
 	activeNativeHistogramBuckets         uint32   // Number of buckets in active native histogram entries in this stripe. Only decreased during purge or clear.
 	activeMatchingNativeHistogramBuckets []uint32 // Number of buckets in active native histogram entries in this stripe matching each matcher of the configured Matchers.
+
+	// Number of active entries in this stripe by cost attribution value. Nil if the cost attribution is disabled.
+	activeAttributed map[string]uint32
 }
 
 // seriesEntry holds a timestamp for single series.
@@ -78,16 +83,19 @@ type seriesEntry struct {
 	nanos                     *atomic.Int64        // Unix timestamp in nanoseconds. Needs to be a pointer because we don't store pointers to entries in the stripe.
 	matches                   preAllocDynamicSlice //  Index of the matcher matching
 	numNativeHistogramBuckets int                  // Number of buckets in native histogram series, -1 if not a native histogram.
+	attribution               string               // Cost attribution value of the series, empty if the cost attribution is disabled.
 
 	deleted bool // This series was marked as deleted, so before purging we need to remove the refence to it from the deletedSeries.
 }
 
-func NewActiveSeries(asm *Matchers, timeout time.Duration) *ActiveSeries {
-	c := &ActiveSeries{matchers: asm, timeout: timeout}
+// NewActiveSeries returns an ActiveSeries tracking the series matching the matchers. The series are also grouped by
+// the cost attribution label values, unless the cost attribution is nil.
+func NewActiveSeries(asm *Matchers, ca *CostAttribution, timeout time.Duration) *ActiveSeries {
+	c := &ActiveSeries{matchers: asm, costAttribution: ca, timeout: timeout}
 
 	// Stripes are pre-allocated so that we only read on them and no lock is required.
 	for i := 0; i < numStripes; i++ {
-		c.stripes[i].reinitialize(asm, &c.deleted)
+		c.stripes[i].reinitialize(asm, ca, &c.deleted)
 	}
 
 	return c
@@ -104,12 +112,31 @@ func (c *ActiveSeries) ReloadMatchers(asm *Matchers, now time.Time) {
 	defer c.matchersMutex.Unlock()
 
 	for i := 0; i < numStripes; i++ {
-		c.stripes[i].reinitialize(asm, &c.deleted)
+		c.stripes[i].reinitialize(asm, c.costAttribution, &c.deleted)
 	}
 	c.matchers = asm
 	c.lastMatchersUpdate = now
 }
 
+// ReloadCostAttribution replaces the cost attribution, resetting the tracked series like ReloadMatchers does.
+func (c *ActiveSeries) ReloadCostAttribution(ca *CostAttribution, now time.Time) {
+	c.matchersMutex.Lock()
+	defer c.matchersMutex.Unlock()
+
+	for i := 0; i < numStripes; i++ {
+		c.stripes[i].reinitialize(c.matchers, ca, &c.deleted)
+	}
+	c.costAttribution = ca
+	c.lastMatchersUpdate = now
+}
+
+// CurrentCostAttribution returns the cost attribution, or nil if the cost attribution is disabled.
+func (c *ActiveSeries) CurrentCostAttribution() *CostAttribution {
+	c.matchersMutex.RLock()
+	defer c.matchersMutex.RUnlock()
+	return c.costAttribution
+}
+
 func (c *ActiveSeries) CurrentConfig() CustomTrackersConfig {
 	c.matchersMutex.RLock()
 	defer c.matchersMutex.RUnlock()
@@ -118,16 +145,18 @@ func (c *ActiveSeries) CurrentConfig() CustomTrackersConfig {
 
 // UpdateSeries updates series timestamp to 'now'. Function is called to make a copy of labels if entry doesn't exist yet.
 // Pass -1 in numNativeHistogramBuckets if the series is not a native histogram series.
-func (c *ActiveSeries) UpdateSeries(series labels.Labels, ref storage.SeriesRef, now time.Time, numNativeHistogramBuckets int) {
+// Returns the cost attribution value of the series, and false if the cost attribution is disabled.
+func (c *ActiveSeries) UpdateSeries(series labels.Labels, ref storage.SeriesRef, now time.Time, numNativeHistogramBuckets int) (string, bool) {
 	stripeID := ref % numStripes
 
-	created := c.stripes[stripeID].updateSeriesTimestamp(now, series, ref, numNativeHistogramBuckets)
+	attribution, attributed, created := c.stripes[stripeID].updateSeriesTimestamp(now, series, ref, numNativeHistogramBuckets)
 	if created {
 		if deleted, ok := c.deleted.find(series); ok {
 			deletedStripeID := deleted.ref % numStripes
 			c.stripes[deletedStripeID].remove(deleted.ref)
 		}
 	}
+	return attribution, attributed
 }
 
 // PostDeletion should be called when series are deleted from the head.
@@ -153,6 +182,13 @@ func (c *ActiveSeries) Purge(now time.Time) bool {
 	purgeTime := now.Add(-c.timeout)
 	c.purge(purgeTime)
 
+	// Forget the cost attribution values without active series, so that they don't count towards the bound.
+	// The series are attributed concurrently, so the values attributed while counting the active series are kept.
+	if c.costAttribution != nil {
+		c.costAttribution.startPrune()
+		c.costAttribution.prune(c.activeByAttribution())
+	}
+
 	return !c.lastMatchersUpdate.After(purgeTime)
 }
 
@@ -205,6 +241,27 @@ func (c *ActiveSeries) ActiveWithMatchers() (total int, totalMatching []int, tot
 	return
 }
 
+// ActiveByAttribution returns the number of active series by cost attribution value. Values without active
+// series aren't returned. Returns nil if the cost attribution is disabled. This method does not purge expired
+// entries, so Purge should be called periodically.
+func (c *ActiveSeries) ActiveByAttribution() map[string]int {
+	c.matchersMutex.RLock()
+	defer c.matchersMutex.RUnlock()
+
+	if c.costAttribution == nil {
+		return nil
+	}
+	return c.activeByAttribution()
+}
+
+func (c *ActiveSeries) activeByAttribution() map[string]int {
+	result := map[string]int{}
+	for s := 0; s < numStripes; s++ {
+		c.stripes[s].updateAttributed(result)
+	}
+	return result
+}
+
 func (s *seriesStripe) containsRef(ref storage.SeriesRef) bool {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -259,14 +316,24 @@ func (s *seriesStripe) getTotalAndUpdateMatching(matching []int, matchingNativeH
 	return s.active, s.activeNativeHistograms, s.activeNativeHistogramBuckets
 }
 
-func (s *seriesStripe) updateSeriesTimestamp(now time.Time, series labels.Labels, ref storage.SeriesRef, numNativeHistogramBuckets int) bool {
+// updateAttributed adds the active series in the stripe by cost attribution value to the map provided.
+func (s *seriesStripe) updateAttributed(attributed map[string]int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for value, a := range s.activeAttributed {
+		attributed[value] += int(a)
+	}
+}
+
+func (s *seriesStripe) updateSeriesTimestamp(now time.Time, series labels.Labels, ref storage.SeriesRef, numNativeHistogramBuckets int) (attribution string, attributed, created bool) {
 	nowNanos := now.UnixNano()
 
-	e, needsUpdating := s.findEntryForSeries(ref, numNativeHistogramBuckets)
-	created := false
+	e, attribution, needsUpdating := s.findEntryForSeries(ref, numNativeHistogramBuckets)
 	if e == nil || needsUpdating {
-		e, created = s.findAndUpdateOrCreateEntryForSeries(ref, series, nowNanos, numNativeHistogramBuckets)
+		e, attribution, created = s.findAndUpdateOrCreateEntryForSeries(ref, series, nowNanos, numNativeHistogramBuckets)
 	}
+	attributed = s.costAttribution != nil
 
 	entryTimeSet := created
 	if !entryTimeSet {
@@ -285,17 +352,17 @@ func (s *seriesStripe) updateSeriesTimestamp(now time.Time, series labels.Labels
 		}
 	}
 
-	return created
+	return attribution, attributed, created
 }
 
-func (s *seriesStripe) findEntryForSeries(ref storage.SeriesRef, numNativeHistogramBuckets int) (*atomic.Int64, bool) {
+func (s *seriesStripe) findEntryForSeries(ref storage.SeriesRef, numNativeHistogramBuckets int) (*atomic.Int64, string, bool) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 	entry := s.refs[ref]
-	return entry.nanos, entry.numNativeHistogramBuckets != numNativeHistogramBuckets
+	return entry.nanos, entry.attribution, entry.numNativeHistogramBuckets != numNativeHistogramBuckets
 }
 
-func (s *seriesStripe) findAndUpdateOrCreateEntryForSeries(ref storage.SeriesRef, series labels.Labels, nowNanos int64, numNativeHistogramBuckets int) (entryTime *atomic.Int64, created bool) {
+func (s *seriesStripe) findAndUpdateOrCreateEntryForSeries(ref storage.SeriesRef, series labels.Labels, nowNanos int64, numNativeHistogramBuckets int) (entryTime *atomic.Int64, attribution string, created bool) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -335,7 +402,7 @@ func (s *seriesStripe) findAndUpdateOrCreateEntryForSeries(ref storage.SeriesRef
 			entry.numNativeHistogramBuckets = numNativeHistogramBuckets
 			s.refs[ref] = entry
 		}
-		return entry.nanos, false
+		return entry.nanos, entry.attribution, false
 	}
 
 	matches := s.matchers.matches(series)
@@ -355,14 +422,20 @@ func (s *seriesStripe) findAndUpdateOrCreateEntryForSeries(ref storage.SeriesRef
 		}
 	}
 
+	if s.costAttribution != nil {
+		attribution = s.costAttribution.attribute(series)
+		s.activeAttributed[attribution]++
+	}
+
 	e := seriesEntry{
 		nanos:                     atomic.NewInt64(nowNanos),
 		matches:                   matches,
 		numNativeHistogramBuckets: numNativeHistogramBuckets,
+		attribution:               attribution,
 	}
 
 	s.refs[ref] = e
-	return e.nanos, true
+	return e.nanos, attribution, true
 }
 
 // nolint // Linter reports that this method is unused, but it is.
@@ -375,6 +448,7 @@ func (s *seriesStripe) clear() {
 	s.active = 0
 	s.activeNativeHistograms = 0
 	s.activeNativeHistogramBuckets = 0
+	clear(s.activeAttributed)
 	for i := range s.activeMatching {
 		s.activeMatching[i] = 0
 		s.activeMatchingNativeHistograms[i] = 0
@@ -382,8 +456,8 @@ func (s *seriesStripe) clear() {
 	}
 }
 
-// Reinitialize assigns new matchers and corresponding size activeMatching slices.
-func (s *seriesStripe) reinitialize(asm *Matchers, deleted *deletedSeries) {
+// Reinitialize assigns new matchers and corresponding size activeMatching slices, and the new cost attribution.
+func (s *seriesStripe) reinitialize(asm *Matchers, ca *CostAttribution, deleted *deletedSeries) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -397,6 +471,11 @@ func (s *seriesStripe) reinitialize(asm *Matchers, deleted *deletedSeries) {
 	s.activeMatching = resizeAndClear(len(asm.MatcherNames()), s.activeMatching)
 	s.activeMatchingNativeHistograms = resizeAndClear(len(asm.MatcherNames()), s.activeMatchingNativeHistograms)
 	s.activeMatchingNativeHistogramBuckets = resizeAndClear(len(asm.MatcherNames()), s.activeMatchingNativeHistogramBuckets)
+	s.costAttribution = ca
+	s.activeAttributed = nil
+	if ca != nil {
+		s.activeAttributed = map[string]uint32{}
+	}
 }
 
 func (s *seriesStripe) purge(keepUntil time.Time) {
@@ -415,6 +494,7 @@ func (s *seriesStripe) purge(keepUntil time.Time) {
 	s.activeMatching = resizeAndClear(len(s.activeMatching), s.activeMatching)
 	s.activeMatchingNativeHistograms = resizeAndClear(len(s.activeMatchingNativeHistograms), s.activeMatchingNativeHistograms)
 	s.activeMatchingNativeHistogramBuckets = resizeAndClear(len(s.activeMatchingNativeHistogramBuckets), s.activeMatchingNativeHistogramBuckets)
+	clear(s.activeAttributed)
 
 	oldest := int64(math.MaxInt64)
 	for ref, entry := range s.refs {
@@ -432,6 +512,9 @@ func (s *seriesStripe) purge(keepUntil time.Time) {
 			s.activeNativeHistograms++
 			s.activeNativeHistogramBuckets += uint32(entry.numNativeHistogramBuckets)
 		}
+		if s.activeAttributed != nil {
+			s.activeAttributed[entry.attribution]++
+		}
 		ml := entry.matches.len()
 		for i := 0; i < ml; i++ {
 			match := entry.matches.get(i)
@@ -474,6 +557,11 @@ func (s *seriesStripe) remove(ref storage.SeriesRef) {
 		s.activeNativeHistograms--
 		s.activeNativeHistogramBuckets -= uint32(entry.numNativeHistogramBuckets)
 	}
+	if s.activeAttributed != nil {
+		if s.activeAttributed[entry.attribution]--; s.activeAttributed[entry.attribution] == 0 {
+			delete(s.activeAttributed, entry.attribution)
+		}
+	}
 	ml := entry.matches.len()
 	for i := 0; i < ml; i++ {
 		match := entry.matches.get(i)
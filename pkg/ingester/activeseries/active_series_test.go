@@ -29,7 +29,7 @@ func TestActiveSeries_UpdateSeries_NoMatchers(t *testing.T) {
 	ref4, ls4 := storage.SeriesRef(4), labels.FromStrings("a", "4")
 	ref5 := storage.SeriesRef(5) // will be used for ls1 again.
 
-	c := NewActiveSeries(&Matchers{}, DefaultTimeout)
+	c := NewActiveSeries(&Matchers{}, nil, DefaultTimeout)
 	valid := c.Purge(time.Now())
 	assert.True(t, valid)
 	allActive, activeMatching, allActiveHistograms, activeMatchingHistograms, allActiveBuckets, activeMatchingBuckets := c.ActiveWithMatchers()
@@ -194,7 +194,7 @@ func TestActiveSeries_ContainsRef(t *testing.T) {
 	for ttl := 1; ttl <= len(series); ttl++ {
 		t.Run(fmt.Sprintf("ttl: %d", ttl), func(t *testing.T) {
 			mockedTime := time.Unix(int64(ttl), 0)
-			c := NewActiveSeries(&Matchers{}, DefaultTimeout)
+			c := NewActiveSeries(&Matchers{}, nil, DefaultTimeout)
 
 			// Update each series with a different timestamp according to each index
 			for i := 0; i < len(series); i++ {
@@ -229,7 +229,7 @@ func TestActiveSeries_UpdateSeries_WithMatchers(t *testing.T) {
 
 	asm := NewMatchers(mustNewCustomTrackersConfigFromMap(t, map[string]string{"foo": `{a=~"2|3|4"}`}))
 
-	c := NewActiveSeries(asm, DefaultTimeout)
+	c := NewActiveSeries(asm, nil, DefaultTimeout)
 	valid := c.Purge(time.Now())
 	assert.True(t, valid)
 	allActive, activeMatching, allActiveHistograms, activeMatchingHistograms, allActiveBuckets, activeMatchingBuckets := c.ActiveWithMatchers()
@@ -455,7 +455,7 @@ func TestActiveSeries_ShouldCorrectlyHandleHashCollisions(t *testing.T) {
 	ls1, ls2 := labelsWithHashCollision()
 	ref1, ref2 := storage.SeriesRef(1), storage.SeriesRef(2)
 
-	c := NewActiveSeries(&Matchers{}, DefaultTimeout)
+	c := NewActiveSeries(&Matchers{}, nil, DefaultTimeout)
 	c.UpdateSeries(ls1, ref1, time.Now(), -1)
 	c.UpdateSeries(ls2, ref2, time.Now(), -1)
 
@@ -483,7 +483,7 @@ func TestActiveSeries_Purge_NoMatchers(t *testing.T) {
 	for ttl := 1; ttl <= len(series); ttl++ {
 		t.Run(fmt.Sprintf("ttl: %d", ttl), func(t *testing.T) {
 			mockedTime := time.Unix(int64(ttl), 0)
-			c := NewActiveSeries(&Matchers{}, DefaultTimeout)
+			c := NewActiveSeries(&Matchers{}, nil, DefaultTimeout)
 
 			for i := 0; i < len(series); i++ {
 				c.UpdateSeries(series[i], refs[i], time.Unix(int64(i), 0), -1)
@@ -529,7 +529,7 @@ func TestActiveSeries_Purge_WithMatchers(t *testing.T) {
 		t.Run(fmt.Sprintf("ttl=%d", ttl), func(t *testing.T) {
 			mockedTime := time.Unix(int64(ttl), 0)
 
-			c := NewActiveSeries(asm, 5*time.Minute)
+			c := NewActiveSeries(asm, nil, 5*time.Minute)
 
 			exp := len(series) - ttl
 			expMatchingSeries := 0
@@ -561,7 +561,7 @@ func TestActiveSeries_PurgeOpt(t *testing.T) {
 	ref1, ref2 := storage.SeriesRef(1), storage.SeriesRef(2)
 
 	currentTime := time.Now()
-	c := NewActiveSeries(&Matchers{}, 59*time.Second)
+	c := NewActiveSeries(&Matchers{}, nil, 59*time.Second)
 
 	c.UpdateSeries(ls1, ref1, currentTime.Add(-2*time.Minute), -1)
 	c.UpdateSeries(ls2, ref2, currentTime, -1)
@@ -597,7 +597,7 @@ func TestActiveSeries_ReloadSeriesMatchers(t *testing.T) {
 	asm := NewMatchers(mustNewCustomTrackersConfigFromMap(t, map[string]string{"foo": `{a=~.*}`}))
 
 	currentTime := time.Now()
-	c := NewActiveSeries(asm, DefaultTimeout)
+	c := NewActiveSeries(asm, nil, DefaultTimeout)
 
 	valid := c.Purge(currentTime)
 	assert.True(t, valid)
@@ -654,6 +654,94 @@ func TestActiveSeries_ReloadSeriesMatchers(t *testing.T) {
 	assert.Equal(t, []int{0, 1}, activeMatching)
 }
 
+func TestActiveSeries_CostAttribution(t *testing.T) {
+	ref1, ls1 := storage.SeriesRef(1), labels.FromStrings("__name__", "m", "team", "a")
+	ref2, ls2 := storage.SeriesRef(2), labels.FromStrings("__name__", "m", "team", "b")
+	ref3, ls3 := storage.SeriesRef(3), labels.FromStrings("__name__", "m", "team", "c")
+	ref4, ls4 := storage.SeriesRef(4), labels.FromStrings("__name__", "m", "team", "a", "job", "x")
+	ref5, ls5 := storage.SeriesRef(5), labels.FromStrings("__name__", "m")
+
+	currentTime := time.Now()
+	c := NewActiveSeries(&Matchers{}, NewCostAttribution("team", 3), DefaultTimeout)
+	assert.Equal(t, "team", c.CurrentCostAttribution().Label())
+	assert.Equal(t, 3, c.CurrentCostAttribution().MaxValues())
+	assert.Empty(t, c.ActiveByAttribution())
+
+	for _, s := range []struct {
+		ref      storage.SeriesRef
+		lbls     labels.Labels
+		expected string
+	}{
+		{ref1, ls1, "a"},
+		{ref2, ls2, "b"},
+		{ref4, ls4, "a"},
+		// The series without the label are attributed to the empty value.
+		{ref5, ls5, ""},
+		// The max number of distinct values has been reached.
+		{ref3, ls3, CostAttributionOverflowValue},
+	} {
+		attribution, attributed := c.UpdateSeries(s.lbls, s.ref, currentTime, -1)
+		assert.True(t, attributed)
+		assert.Equal(t, s.expected, attribution)
+	}
+
+	// The attribution of an existing series doesn't change.
+	attribution, _ := c.UpdateSeries(ls3, ref3, currentTime, -1)
+	assert.Equal(t, CostAttributionOverflowValue, attribution)
+
+	assert.True(t, c.Purge(currentTime))
+	assert.Equal(t, map[string]int{"a": 2, "b": 1, "": 1, CostAttributionOverflowValue: 1}, c.ActiveByAttribution())
+
+	// The values without active series are forgotten on purge, making room for new values.
+	currentTime = currentTime.Add(DefaultTimeout)
+	c.UpdateSeries(ls1, ref1, currentTime, -1)
+	c.UpdateSeries(ls5, ref5, currentTime, -1)
+	assert.True(t, c.Purge(currentTime.Add(time.Second)))
+	assert.Equal(t, map[string]int{"a": 1, "": 1}, c.ActiveByAttribution())
+
+	attribution, _ = c.UpdateSeries(labels.FromStrings("__name__", "m", "team", "d"), storage.SeriesRef(6), currentTime, -1)
+	assert.Equal(t, "d", attribution)
+
+	// The series are removed from their value when deleted.
+	c.PostDeletion(map[chunks.HeadSeriesRef]labels.Labels{chunks.HeadSeriesRef(ref1): ls1})
+	c.UpdateSeries(ls1, storage.SeriesRef(7), currentTime, -1)
+	assert.Equal(t, map[string]int{"a": 1, "": 1, "d": 1}, c.ActiveByAttribution())
+}
+
+func TestActiveSeries_ReloadCostAttribution(t *testing.T) {
+	ref1, ls1 := storage.SeriesRef(1), labels.FromStrings("team", "a", "service", "x")
+
+	currentTime := time.Now()
+	c := NewActiveSeries(&Matchers{}, nil, DefaultTimeout)
+	assert.Nil(t, c.CurrentCostAttribution())
+
+	_, attributed := c.UpdateSeries(ls1, ref1, currentTime, -1)
+	assert.False(t, attributed)
+	assert.Nil(t, c.ActiveByAttribution())
+
+	c.ReloadCostAttribution(NewCostAttribution("team", 10), currentTime)
+	assert.False(t, c.Purge(currentTime))
+
+	attribution, attributed := c.UpdateSeries(ls1, ref1, currentTime, -1)
+	assert.True(t, attributed)
+	assert.Equal(t, "a", attribution)
+	assert.Equal(t, map[string]int{"a": 1}, c.ActiveByAttribution())
+
+	c.ReloadCostAttribution(NewCostAttribution("service", 10), currentTime)
+	attribution, _ = c.UpdateSeries(ls1, ref1, currentTime, -1)
+	assert.Equal(t, "x", attribution)
+
+	// Adding timeout time to make Purge results valid.
+	currentTime = currentTime.Add(DefaultTimeout)
+	c.UpdateSeries(ls1, ref1, currentTime, -1)
+	assert.True(t, c.Purge(currentTime))
+	assert.Equal(t, map[string]int{"x": 1}, c.ActiveByAttribution())
+
+	c.ReloadCostAttribution(NewCostAttribution("", 10), currentTime)
+	assert.Nil(t, c.CurrentCostAttribution())
+	assert.Nil(t, c.ActiveByAttribution())
+}
+
 func TestActiveSeries_ReloadSeriesMatchers_LessMatchers(t *testing.T) {
 	ref1, ls1 := storage.SeriesRef(1), labels.FromStrings("a", "1")
 
@@ -663,7 +751,7 @@ func TestActiveSeries_ReloadSeriesMatchers_LessMatchers(t *testing.T) {
 	}))
 
 	currentTime := time.Now()
-	c := NewActiveSeries(asm, DefaultTimeout)
+	c := NewActiveSeries(asm, nil, DefaultTimeout)
 	valid := c.Purge(currentTime)
 	assert.True(t, valid)
 	allActive, activeMatching, _, _, _, _ := c.ActiveWithMatchers()
@@ -702,7 +790,7 @@ func TestActiveSeries_ReloadSeriesMatchers_SameSizeNewLabels(t *testing.T) {
 
 	currentTime := time.Now()
 
-	c := NewActiveSeries(asm, DefaultTimeout)
+	c := NewActiveSeries(asm, nil, DefaultTimeout)
 	valid := c.Purge(currentTime)
 	assert.True(t, valid)
 	allActive, activeMatching, _, _, _, _ := c.ActiveWithMatchers()
@@ -755,7 +843,7 @@ func benchmarkActiveSeriesUpdateSeriesConcurrency(b *testing.B, numSeries, numGo
 	var (
 		// Run the active series tracker with an active timeout = 0 so that the Purge() will always
 		// purge the series.
-		c           = NewActiveSeries(&Matchers{}, 0)
+		c           = NewActiveSeries(&Matchers{}, nil, 0)
 		updateGroup = &sync.WaitGroup{}
 		purgeGroup  = &sync.WaitGroup{}
 		start       = make(chan struct{})
@@ -893,7 +981,7 @@ func BenchmarkActiveSeries_UpdateSeries(b *testing.B) {
 
 			b.ResetTimer()
 			for i := 0; i < b.N; i++ {
-				c := NewActiveSeries(asm, DefaultTimeout)
+				c := NewActiveSeries(asm, nil, DefaultTimeout)
 				for round := 0; round <= tt.nRounds; round++ {
 					for ix := 0; ix < tt.nSeries; ix++ {
 						c.UpdateSeries(series[ix], refs[ix], time.Unix(0, now), -1)
@@ -918,7 +1006,7 @@ func benchmarkPurge(b *testing.B, twice bool) {
 	const numExpiresSeries = numSeries / 25
 
 	currentTime := time.Now()
-	c := NewActiveSeries(&Matchers{}, DefaultTimeout)
+	c := NewActiveSeries(&Matchers{}, nil, DefaultTimeout)
 
 	series := [numSeries]labels.Labels{}
 	refs := [numSeries]storage.SeriesRef{}
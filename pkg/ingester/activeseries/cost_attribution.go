@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package activeseries
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"go.uber.org/atomic"
+)
+
+// CostAttributionOverflowValue is the value the series are attributed to once the max number of distinct
+// values of the cost attribution label has been reached.
+const CostAttributionOverflowValue = "__overflow__"
+
+// CostAttribution attributes the series to the values of a label, bounding the number of distinct values.
+// The series with a label value exceeding the bound are attributed to CostAttributionOverflowValue. The series
+// without the label are attributed to the empty value.
+type CostAttribution struct {
+	label     string
+	maxValues int
+
+	// mu protects values. Values are interned, so that the entries don't reference the labels of the series.
+	mu     sync.RWMutex
+	values map[string]*costAttributionValue
+}
+
+type costAttributionValue struct {
+	value string
+
+	// attributed is set when a series is attributed to the value, and reset when a prune starts, so that the
+	// values attributed to series while the active series are counted aren't pruned.
+	attributed atomic.Bool
+}
+
+// NewCostAttribution returns a CostAttribution grouping the series by the label, with at most maxValues distinct
+// values. Returns nil if the label is empty, which disables the cost attribution.
+func NewCostAttribution(label string, maxValues int) *CostAttribution {
+	if label == "" {
+		return nil
+	}
+	return &CostAttribution{
+		label:     label,
+		maxValues: maxValues,
+		values:    map[string]*costAttributionValue{},
+	}
+}
+
+// Label returns the cost attribution label, or an empty string if ca is nil.
+func (ca *CostAttribution) Label() string {
+	if ca == nil {
+		return ""
+	}
+	return ca.label
+}
+
+// MaxValues returns the max number of distinct values of the cost attribution label, or 0 if ca is nil.
+func (ca *CostAttribution) MaxValues() int {
+	if ca == nil {
+		return 0
+	}
+	return ca.maxValues
+}
+
+// attribute returns the value the series is attributed to, tracking it if it's a new value within the bound.
+func (ca *CostAttribution) attribute(series labels.Labels) string {
+	value := series.Get(ca.label)
+
+	ca.mu.RLock()
+	v, ok := ca.values[value]
+	ca.mu.RUnlock()
+	if ok {
+		v.attributed.Store(true)
+		return v.value
+	}
+
+	ca.mu.Lock()
+	defer ca.mu.Unlock()
+
+	// Check again under the write lock.
+	if v, ok := ca.values[value]; ok {
+		v.attributed.Store(true)
+		return v.value
+	}
+	if len(ca.values) >= ca.maxValues {
+		return CostAttributionOverflowValue
+	}
+
+	v = &costAttributionValue{value: strings.Clone(value)}
+	v.attributed.Store(true)
+	ca.values[v.value] = v
+	return v.value
+}
+
+// startPrune must be called before counting the active series passed to prune.
+func (ca *CostAttribution) startPrune() {
+	ca.mu.RLock()
+	defer ca.mu.RUnlock()
+
+	for _, v := range ca.values {
+		v.attributed.Store(false)
+	}
+}
+
+// prune forgets the values without active series, making room for new values. The values attributed to series
+// since startPrune has been called are kept, because the series may not have been counted in active.
+func (ca *CostAttribution) prune(active map[string]int) {
+	ca.mu.Lock()
+	defer ca.mu.Unlock()
+
+	for value, v := range ca.values {
+		if active[value] == 0 && !v.attributed.Load() {
+			delete(ca.values, value)
+		}
+	}
+}
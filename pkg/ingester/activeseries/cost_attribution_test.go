@@ -0,0 +1,35 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package activeseries
+
+import (
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCostAttribution_Prune(t *testing.T) {
+	a, b := labels.FromStrings("team", "a"), labels.FromStrings("team", "b")
+
+	ca := NewCostAttribution("team", 1)
+	assert.Equal(t, "a", ca.attribute(a))
+	assert.Equal(t, CostAttributionOverflowValue, ca.attribute(b))
+
+	// The value attributed to a series while the active series are counted isn't pruned, even if the series
+	// hasn't been counted.
+	ca.startPrune()
+	assert.Equal(t, "a", ca.attribute(a))
+	ca.prune(map[string]int{})
+	assert.Equal(t, CostAttributionOverflowValue, ca.attribute(b))
+
+	// The value is pruned once it isn't attributed anymore.
+	ca.startPrune()
+	ca.prune(map[string]int{})
+	assert.Equal(t, "b", ca.attribute(b))
+
+	// The values with active series aren't pruned.
+	ca.startPrune()
+	ca.prune(map[string]int{"b": 1})
+	assert.Equal(t, CostAttributionOverflowValue, ca.attribute(a))
+}
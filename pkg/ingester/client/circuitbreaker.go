@@ -39,6 +39,7 @@ var (
 		"/cortex.Ingester/MetricsMetadata":         {},
 		"/cortex.Ingester/LabelNamesAndValues":     {},
 		"/cortex.Ingester/LabelValuesCardinality":  {},
+		"/cortex.Ingester/CostAttribution":         {},
 	}
 )
 
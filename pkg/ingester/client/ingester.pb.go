@@ -1777,6 +1777,143 @@ func (m *ActiveSeriesResponse) GetMetric() []*mimirpb.Metric {
 	return nil
 }
 
+type CostAttributionRequest struct {
+}
+
+func (m *CostAttributionRequest) Reset()      { *m = CostAttributionRequest{} }
+func (*CostAttributionRequest) ProtoMessage() {}
+func (*CostAttributionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_60f6df4f3586b478, []int{32}
+}
+func (m *CostAttributionRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CostAttributionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CostAttributionRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CostAttributionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CostAttributionRequest.Merge(m, src)
+}
+func (m *CostAttributionRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *CostAttributionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CostAttributionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CostAttributionRequest proto.InternalMessageInfo
+
+type CostAttributionResponse struct {
+	Label  string                 `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	Values []CostAttributionValue `protobuf:"bytes,2,rep,name=values,proto3" json:"values"`
+}
+
+func (m *CostAttributionResponse) Reset()      { *m = CostAttributionResponse{} }
+func (*CostAttributionResponse) ProtoMessage() {}
+func (*CostAttributionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_60f6df4f3586b478, []int{33}
+}
+func (m *CostAttributionResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CostAttributionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CostAttributionResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CostAttributionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CostAttributionResponse.Merge(m, src)
+}
+func (m *CostAttributionResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *CostAttributionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CostAttributionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CostAttributionResponse proto.InternalMessageInfo
+
+func (m *CostAttributionResponse) GetLabel() string {
+	if m != nil {
+		return m.Label
+	}
+	return ""
+}
+
+func (m *CostAttributionResponse) GetValues() []CostAttributionValue {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+type CostAttributionValue struct {
+	Value        string `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	ActiveSeries uint64 `protobuf:"varint,2,opt,name=active_series,json=activeSeries,proto3" json:"active_series,omitempty"`
+}
+
+func (m *CostAttributionValue) Reset()      { *m = CostAttributionValue{} }
+func (*CostAttributionValue) ProtoMessage() {}
+func (*CostAttributionValue) Descriptor() ([]byte, []int) {
+	return fileDescriptor_60f6df4f3586b478, []int{34}
+}
+func (m *CostAttributionValue) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CostAttributionValue) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CostAttributionValue.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CostAttributionValue) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CostAttributionValue.Merge(m, src)
+}
+func (m *CostAttributionValue) XXX_Size() int {
+	return m.Size()
+}
+func (m *CostAttributionValue) XXX_DiscardUnknown() {
+	xxx_messageInfo_CostAttributionValue.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CostAttributionValue proto.InternalMessageInfo
+
+func (m *CostAttributionValue) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+func (m *CostAttributionValue) GetActiveSeries() uint64 {
+	if m != nil {
+		return m.ActiveSeries
+	}
+	return 0
+}
+
 type TimeSeriesChunk struct {
 	FromIngesterId string                                              `protobuf:"bytes,1,opt,name=from_ingester_id,json=fromIngesterId,proto3" json:"from_ingester_id,omitempty"`
 	UserId         string                                              `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
@@ -1787,7 +1924,7 @@ type TimeSeriesChunk struct {
 func (m *TimeSeriesChunk) Reset()      { *m = TimeSeriesChunk{} }
 func (*TimeSeriesChunk) ProtoMessage() {}
 func (*TimeSeriesChunk) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{32}
+	return fileDescriptor_60f6df4f3586b478, []int{35}
 }
 func (m *TimeSeriesChunk) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1847,7 +1984,7 @@ type Chunk struct {
 func (m *Chunk) Reset()      { *m = Chunk{} }
 func (*Chunk) ProtoMessage() {}
 func (*Chunk) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{33}
+	return fileDescriptor_60f6df4f3586b478, []int{36}
 }
 func (m *Chunk) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1904,7 +2041,7 @@ type LabelMatchers struct {
 func (m *LabelMatchers) Reset()      { *m = LabelMatchers{} }
 func (*LabelMatchers) ProtoMessage() {}
 func (*LabelMatchers) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{34}
+	return fileDescriptor_60f6df4f3586b478, []int{37}
 }
 func (m *LabelMatchers) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1949,7 +2086,7 @@ type LabelMatcher struct {
 func (m *LabelMatcher) Reset()      { *m = LabelMatcher{} }
 func (*LabelMatcher) ProtoMessage() {}
 func (*LabelMatcher) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{35}
+	return fileDescriptor_60f6df4f3586b478, []int{38}
 }
 func (m *LabelMatcher) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2009,7 +2146,7 @@ type TimeSeriesFile struct {
 func (m *TimeSeriesFile) Reset()      { *m = TimeSeriesFile{} }
 func (*TimeSeriesFile) ProtoMessage() {}
 func (*TimeSeriesFile) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{36}
+	return fileDescriptor_60f6df4f3586b478, []int{39}
 }
 func (m *TimeSeriesFile) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2104,6 +2241,9 @@ func init() {
 	proto.RegisterType((*MetricsMetadataRequest)(nil), "cortex.MetricsMetadataRequest")
 	proto.RegisterType((*MetricsMetadataResponse)(nil), "cortex.MetricsMetadataResponse")
 	proto.RegisterType((*ActiveSeriesResponse)(nil), "cortex.ActiveSeriesResponse")
+	proto.RegisterType((*CostAttributionRequest)(nil), "cortex.CostAttributionRequest")
+	proto.RegisterType((*CostAttributionResponse)(nil), "cortex.CostAttributionResponse")
+	proto.RegisterType((*CostAttributionValue)(nil), "cortex.CostAttributionValue")
 	proto.RegisterType((*TimeSeriesChunk)(nil), "cortex.TimeSeriesChunk")
 	proto.RegisterType((*Chunk)(nil), "cortex.Chunk")
 	proto.RegisterType((*LabelMatchers)(nil), "cortex.LabelMatchers")
@@ -2114,133 +2254,138 @@ func init() {
 func init() { proto.RegisterFile("ingester.proto", fileDescriptor_60f6df4f3586b478) }
 
 var fileDescriptor_60f6df4f3586b478 = []byte{
-	// 2004 bytes of a gzipped FileDescriptorProto
+	// 2086 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x59, 0xcd, 0x6f, 0x1b, 0xc7,
-	0x15, 0xe7, 0xf0, 0x43, 0x12, 0x1f, 0x29, 0x6a, 0x35, 0x94, 0x2c, 0x66, 0x1d, 0x53, 0xca, 0x16,
-	0x4e, 0xd5, 0x34, 0xa1, 0xfc, 0xd5, 0xc2, 0x09, 0x52, 0xa4, 0x94, 0x44, 0x5b, 0xb4, 0x4d, 0x51,
-	0x59, 0x52, 0x89, 0x5b, 0x20, 0x58, 0x2c, 0xc9, 0x91, 0xb4, 0x30, 0x77, 0xc9, 0xec, 0x0e, 0x03,
-	0x29, 0xa7, 0x02, 0x05, 0x7a, 0xee, 0xad, 0x97, 0xa2, 0x40, 0x6f, 0x45, 0x4f, 0x45, 0x2f, 0xbd,
-	0xf5, 0x9c, 0x4b, 0x00, 0x1f, 0x83, 0x02, 0x35, 0x6a, 0xb9, 0x87, 0xf6, 0x16, 0xa0, 0xff, 0x40,
-	0xb1, 0x33, 0xb3, 0x9f, 0x5c, 0x59, 0xb2, 0x61, 0xfb, 0x24, 0xce, 0x7b, 0x6f, 0xde, 0xfc, 0xde,
-	0xc7, 0xbc, 0xf7, 0x66, 0x05, 0x25, 0xc3, 0x3a, 0x24, 0x0e, 0x25, 0x76, 0x6d, 0x6c, 0x8f, 0xe8,
-	0x08, 0xcf, 0xf4, 0x47, 0x36, 0x25, 0xc7, 0xf2, 0x07, 0x87, 0x06, 0x3d, 0x9a, 0xf4, 0x6a, 0xfd,
-	0x91, 0xb9, 0x71, 0x38, 0x3a, 0x1c, 0x6d, 0x30, 0x76, 0x6f, 0x72, 0xc0, 0x56, 0x6c, 0xc1, 0x7e,
-	0xf1, 0x6d, 0xf2, 0xb5, 0xb0, 0xb8, 0xad, 0x1f, 0xe8, 0x96, 0xbe, 0x61, 0x1a, 0xa6, 0x61, 0x6f,
-	0x8c, 0x1f, 0x1d, 0xf2, 0x5f, 0xe3, 0x1e, 0xff, 0xcb, 0x77, 0x28, 0xbf, 0x41, 0x20, 0x3f, 0xd0,
-	0x7b, 0x64, 0xb8, 0xab, 0x9b, 0xc4, 0xa9, 0x5b, 0x83, 0xcf, 0xf4, 0xe1, 0x84, 0x38, 0x2a, 0xf9,
-	0x72, 0x42, 0x1c, 0x8a, 0xaf, 0xc1, 0x9c, 0xa9, 0xd3, 0xfe, 0x11, 0xb1, 0x9d, 0x0a, 0x5a, 0xcb,
-	0xac, 0x17, 0x6e, 0x2c, 0xd5, 0x38, 0xb4, 0x1a, 0xdb, 0xd5, 0xe2, 0x4c, 0xd5, 0x97, 0xc2, 0x3f,
-	0x85, 0x62, 0x7f, 0x34, 0xb1, 0xa8, 0x66, 0x12, 0x7a, 0x34, 0x1a, 0x54, 0xd2, 0x6b, 0x68, 0xbd,
-	0x74, 0xa3, 0xec, 0xed, 0xda, 0x72, 0x79, 0x2d, 0xc6, 0x52, 0x0b, 0xfd, 0x60, 0xa1, 0xec, 0xc0,
-	0xe5, 0x44, 0x1c, 0xce, 0x78, 0x64, 0x39, 0x04, 0xff, 0x08, 0x72, 0x06, 0x25, 0xa6, 0x87, 0xa2,
-	0x1c, 0x41, 0x21, 0x64, 0xb9, 0x84, 0xb2, 0x0d, 0x85, 0x10, 0x15, 0x5f, 0x01, 0x18, 0xba, 0x4b,
-	0xcd, 0xd2, 0x4d, 0x52, 0x41, 0x6b, 0x68, 0x3d, 0xaf, 0xe6, 0x87, 0xde, 0x51, 0xf8, 0x12, 0xcc,
-	0x7c, 0xc5, 0x04, 0x2b, 0xe9, 0xb5, 0xcc, 0x7a, 0x5e, 0x15, 0x2b, 0xe5, 0xcf, 0x08, 0xae, 0x84,
-	0xd4, 0x6c, 0xe9, 0xf6, 0xc0, 0xb0, 0xf4, 0xa1, 0x41, 0x4f, 0x3c, 0xdf, 0xac, 0x42, 0x21, 0x50,
-	0xcc, 0x81, 0xe5, 0x55, 0xf0, 0x35, 0x3b, 0x11, 0xe7, 0xa5, 0x5f, 0xca, 0x79, 0x99, 0x0b, 0x3a,
-	0x6f, 0x1f, 0xaa, 0x67, 0x61, 0x15, 0xfe, 0xbb, 0x19, 0xf5, 0xdf, 0x95, 0x69, 0xff, 0x75, 0x88,
-	0x6d, 0x10, 0x87, 0x1d, 0xe1, 0x79, 0xf2, 0x09, 0x82, 0xe5, 0x44, 0x81, 0xf3, 0x9c, 0xaa, 0x03,
-	0xe6, 0x6c, 0xe6, 0x4c, 0xcd, 0x61, 0x3b, 0x85, 0x0f, 0x6e, 0x3e, 0xf7, 0xe8, 0x29, 0x6a, 0xc3,
-	0xa2, 0xf6, 0x89, 0x2a, 0x0d, 0x63, 0x64, 0x79, 0x6b, 0x1a, 0x1a, 0x13, 0xc5, 0x12, 0x64, 0x1e,
-	0x91, 0x13, 0x81, 0xc9, 0xfd, 0x89, 0x97, 0x20, 0xc7, 0x70, 0xb0, 0x5c, 0xcc, 0xaa, 0x7c, 0xf1,
-	0x51, 0xfa, 0x36, 0x52, 0xbe, 0x45, 0x50, 0x50, 0x89, 0x3e, 0xf0, 0x42, 0x5a, 0x83, 0xd9, 0x2f,
-	0x27, 0x1c, 0x6c, 0x2c, 0xdb, 0x3f, 0x9d, 0x10, 0xdb, 0x8b, 0xbc, 0xea, 0x09, 0xe1, 0x87, 0xb0,
-	0xa2, 0xf7, 0xfb, 0x64, 0x4c, 0xc9, 0x40, 0xb3, 0x85, 0xab, 0x35, 0x7a, 0x32, 0x16, 0xc6, 0x96,
-	0x6e, 0xac, 0x79, 0xfb, 0x43, 0xa7, 0xd4, 0xbc, 0xa0, 0x74, 0x4f, 0xc6, 0x44, 0x5d, 0xf6, 0x14,
-	0x84, 0xa9, 0x8e, 0x72, 0x0b, 0x8a, 0x61, 0x02, 0x2e, 0xc0, 0x6c, 0xa7, 0xde, 0xda, 0x7b, 0xd0,
-	0xe8, 0x48, 0x29, 0xbc, 0x02, 0xe5, 0x4e, 0x57, 0x6d, 0xd4, 0x5b, 0x8d, 0x6d, 0xed, 0x61, 0x5b,
-	0xd5, 0xb6, 0x76, 0xf6, 0x77, 0xef, 0x77, 0x24, 0xa4, 0x7c, 0xe2, 0xee, 0xd2, 0x7d, 0x55, 0x78,
-	0x03, 0x66, 0x6d, 0xe2, 0x4c, 0x86, 0xd4, 0xb3, 0x67, 0x39, 0x66, 0x0f, 0x97, 0x53, 0x3d, 0x29,
-	0xe5, 0x04, 0x70, 0x87, 0xda, 0x44, 0x37, 0x23, 0x6a, 0x36, 0xa1, 0xd4, 0x3f, 0x9a, 0x58, 0x8f,
-	0xc8, 0xc0, 0x0b, 0x25, 0xd7, 0x76, 0xd9, 0xd3, 0xc6, 0xf7, 0x6c, 0x71, 0x19, 0x1e, 0x0c, 0x75,
-	0xbe, 0x1f, 0x5e, 0xba, 0xb7, 0xc5, 0xf5, 0xda, 0x89, 0x66, 0x58, 0x03, 0x72, 0xcc, 0x42, 0x91,
-	0x51, 0x81, 0x91, 0x9a, 0x2e, 0x45, 0xf9, 0x0b, 0x82, 0x72, 0x82, 0x1e, 0x7c, 0x00, 0x33, 0x2c,
-	0xf8, 0xf1, 0xab, 0x3f, 0xee, 0xf1, 0x5c, 0xd9, 0xd3, 0x0d, 0x7b, 0xf3, 0xc3, 0x6f, 0x9e, 0xac,
-	0xa6, 0xfe, 0xf1, 0x64, 0xf5, 0xfa, 0x45, 0x0a, 0x20, 0xdf, 0x57, 0x1f, 0xe8, 0x63, 0x4a, 0x6c,
-	0x55, 0x68, 0xc7, 0xd7, 0x61, 0x86, 0x21, 0xf6, 0xf2, 0xb4, 0x9c, 0x60, 0xdc, 0x66, 0xd6, 0x3d,
-	0x47, 0x15, 0x82, 0xca, 0xef, 0xd2, 0x50, 0x08, 0x71, 0x71, 0x15, 0x0a, 0xa6, 0x61, 0x69, 0xd4,
-	0x30, 0x89, 0xc6, 0xae, 0x9a, 0x6b, 0x63, 0xde, 0x34, 0xac, 0xae, 0x61, 0x92, 0x96, 0xc3, 0xf8,
-	0xfa, 0xb1, 0xcf, 0x4f, 0x0b, 0xbe, 0x7e, 0x2c, 0xf8, 0xd7, 0x20, 0xeb, 0x26, 0x8f, 0xb8, 0xf6,
-	0x6f, 0x27, 0x00, 0xa8, 0x35, 0xac, 0xfe, 0x68, 0x60, 0x58, 0x87, 0x2a, 0x93, 0xc4, 0x7b, 0x90,
-	0x1d, 0xe8, 0x54, 0xaf, 0x64, 0xd7, 0xd0, 0x7a, 0x71, 0xf3, 0x63, 0xe1, 0x85, 0x5b, 0x17, 0xf2,
-	0xc2, 0xbe, 0xe5, 0xe8, 0x07, 0x64, 0xf3, 0x84, 0x92, 0xce, 0xd0, 0xe8, 0x13, 0x95, 0x69, 0x52,
-	0xb6, 0x61, 0xce, 0x3b, 0xc3, 0x4d, 0xba, 0xfd, 0xdd, 0xfb, 0xbb, 0xed, 0xcf, 0x77, 0xa5, 0x14,
-	0x9e, 0x85, 0xcc, 0xc3, 0xb6, 0x2a, 0x21, 0x3c, 0x0f, 0xf9, 0x9d, 0x66, 0xa7, 0xdb, 0xbe, 0xab,
-	0xd6, 0x5b, 0x52, 0x1a, 0x97, 0x61, 0xe1, 0xce, 0x83, 0x76, 0xbd, 0xab, 0x05, 0xc4, 0x8c, 0xf2,
-	0x6f, 0x04, 0xc5, 0xf0, 0x95, 0xc1, 0xef, 0x03, 0x76, 0xa8, 0x6e, 0x53, 0x66, 0xbc, 0x43, 0x75,
-	0x73, 0x1c, 0x78, 0x48, 0x62, 0x9c, 0xae, 0xc7, 0x68, 0x39, 0x78, 0x1d, 0x24, 0x62, 0x0d, 0xa2,
-	0xb2, 0xdc, 0x5b, 0x25, 0x62, 0x0d, 0xc2, 0x92, 0xe1, 0x1a, 0x9b, 0xb9, 0x50, 0x8d, 0xfd, 0x19,
-	0x5c, 0x76, 0x98, 0x43, 0x0d, 0xeb, 0x50, 0xe3, 0x81, 0xd4, 0x7a, 0x2e, 0x53, 0x73, 0x8c, 0xaf,
-	0x49, 0x65, 0xc0, 0x6a, 0x44, 0xc5, 0x17, 0x61, 0x6e, 0x77, 0x36, 0x5d, 0x81, 0x8e, 0xf1, 0x35,
-	0xb9, 0x97, 0x9d, 0xcb, 0x4a, 0x39, 0x35, 0x77, 0x64, 0x58, 0xd4, 0x51, 0xfe, 0x88, 0x60, 0xa9,
-	0x71, 0x4c, 0xcc, 0xf1, 0x50, 0xb7, 0xdf, 0x88, 0xb9, 0xd7, 0xa7, 0xcc, 0x5d, 0x4e, 0x32, 0xd7,
-	0x09, 0xec, 0x55, 0xee, 0x42, 0xb9, 0xde, 0xa7, 0xc6, 0x57, 0xa2, 0x48, 0xbe, 0x74, 0x67, 0x57,
-	0xee, 0xc3, 0x7c, 0xa4, 0x6a, 0xe0, 0x8f, 0x00, 0x18, 0xe4, 0xa4, 0x82, 0x39, 0xee, 0xd5, 0x5c,
-	0xdc, 0xfc, 0x4c, 0x71, 0x6d, 0x42, 0xd2, 0xca, 0xff, 0xd2, 0x50, 0x66, 0xda, 0xbc, 0x72, 0x23,
-	0x74, 0x7e, 0x02, 0x05, 0x1e, 0x93, 0xb0, 0xd2, 0x15, 0x0f, 0x59, 0xa0, 0x32, 0x7c, 0x1d, 0xc3,
-	0x3b, 0x62, 0xa0, 0xd2, 0x2f, 0x02, 0x0a, 0xdf, 0x03, 0x29, 0x48, 0x0d, 0xa1, 0x81, 0x7b, 0xf9,
-	0xad, 0x48, 0xdd, 0xe4, 0x98, 0x23, 0x6a, 0x16, 0xfc, 0x8d, 0xa2, 0x6c, 0xdd, 0x82, 0x15, 0xc3,
-	0xd1, 0xdc, 0xb0, 0x8e, 0x0e, 0x84, 0x2e, 0x8d, 0xcb, 0xb0, 0xcb, 0x3a, 0xa7, 0x96, 0x0d, 0xa7,
-	0x61, 0x0d, 0xda, 0x07, 0x5c, 0x9e, 0xab, 0xc4, 0x5f, 0xc0, 0x4a, 0x1c, 0x81, 0xc8, 0xd1, 0x4a,
-	0x8e, 0x01, 0x59, 0x3d, 0x13, 0x88, 0x48, 0x54, 0x0e, 0x67, 0x39, 0x06, 0x87, 0x33, 0x95, 0xdf,
-	0x23, 0x58, 0x9c, 0xda, 0xf8, 0xc6, 0x2a, 0xec, 0xaa, 0x88, 0xad, 0xc6, 0x46, 0x17, 0xaf, 0x05,
-	0x30, 0x12, 0xeb, 0xfd, 0x8a, 0x01, 0x2b, 0x67, 0x98, 0x85, 0xdf, 0x81, 0xa2, 0x70, 0x07, 0xef,
-	0x1f, 0x88, 0x5d, 0xd3, 0x02, 0xa7, 0xb1, 0x06, 0x82, 0x7f, 0x1c, 0x2b, 0xe0, 0xf3, 0xfe, 0xd8,
-	0x94, 0x50, 0xba, 0x3b, 0xb0, 0x1c, 0xbb, 0xb8, 0xaf, 0x20, 0xa9, 0xff, 0x8e, 0x00, 0x87, 0x07,
-	0x52, 0x71, 0xd5, 0xce, 0x19, 0x96, 0x92, 0x6b, 0x45, 0xfa, 0x05, 0x6a, 0x45, 0xe6, 0xdc, 0x5a,
-	0xe1, 0xa6, 0xdc, 0x05, 0x6a, 0xc5, 0x6d, 0x28, 0x47, 0xf0, 0x0b, 0x9f, 0xbc, 0x03, 0xc5, 0xd0,
-	0x38, 0xe7, 0x8d, 0xba, 0x85, 0x60, 0x26, 0x73, 0x94, 0x3f, 0x20, 0x58, 0x0c, 0xe6, 0xf7, 0x37,
-	0x5b, 0x06, 0x2f, 0x64, 0xda, 0x4f, 0x44, 0x68, 0x04, 0x3e, 0x61, 0xd9, 0x79, 0x33, 0xbc, 0x72,
-	0x0f, 0xa4, 0x7d, 0x87, 0xd8, 0x1d, 0xaa, 0x53, 0xdf, 0xaa, 0xf8, 0x94, 0x8e, 0x2e, 0x38, 0xa5,
-	0xff, 0x0d, 0xc1, 0x62, 0x48, 0x99, 0x80, 0x70, 0xd5, 0x7b, 0xfc, 0x19, 0x23, 0x4b, 0xb3, 0x75,
-	0xca, 0x33, 0x04, 0xa9, 0xf3, 0x3e, 0x55, 0xd5, 0x29, 0x71, 0x93, 0xc8, 0x9a, 0x98, 0xc1, 0x28,
-	0xed, 0xa6, 0x7f, 0xde, 0x9a, 0x78, 0x77, 0xf8, 0x7d, 0xc0, 0xfa, 0xd8, 0xd0, 0x62, 0x9a, 0x32,
-	0x4c, 0x93, 0xa4, 0x8f, 0x8d, 0x66, 0x44, 0x59, 0x0d, 0xca, 0xf6, 0x64, 0x48, 0xe2, 0xe2, 0x59,
-	0x26, 0xbe, 0xe8, 0xb2, 0x22, 0xf2, 0xca, 0x17, 0x50, 0x76, 0x81, 0x37, 0xb7, 0xa3, 0xd0, 0x57,
-	0x60, 0x76, 0xe2, 0x10, 0x5b, 0x33, 0x06, 0x22, 0xab, 0x67, 0xdc, 0x65, 0x73, 0x80, 0x3f, 0x10,
-	0x63, 0x49, 0x9a, 0xc5, 0xc6, 0x2f, 0x9e, 0x53, 0xc6, 0x8b, 0x99, 0xe3, 0x2e, 0x60, 0x97, 0xe5,
-	0x44, 0xb5, 0x5f, 0x87, 0x9c, 0xe3, 0x12, 0xe2, 0xc3, 0x66, 0x02, 0x12, 0x95, 0x4b, 0x2a, 0x7f,
-	0x45, 0x50, 0x6d, 0x11, 0x6a, 0x1b, 0x7d, 0xe7, 0xce, 0xc8, 0x8e, 0xa6, 0xc2, 0x6b, 0x4e, 0xc9,
-	0xdb, 0x50, 0xf4, 0x72, 0x4d, 0x73, 0x08, 0x7d, 0x7e, 0x77, 0x2e, 0x78, 0xa2, 0x1d, 0x42, 0x95,
-	0xfb, 0xb0, 0x7a, 0x26, 0x66, 0xe1, 0x8a, 0x75, 0x98, 0x31, 0x99, 0x88, 0xf0, 0x85, 0x14, 0x14,
-	0x24, 0xbe, 0x55, 0x15, 0x7c, 0x65, 0x0c, 0x97, 0x84, 0xb2, 0x16, 0xa1, 0xba, 0xeb, 0x5d, 0xcf,
-	0xf0, 0x25, 0xc8, 0x0d, 0x0d, 0xd3, 0xa0, 0xcc, 0xd6, 0x45, 0x95, 0x2f, 0x5c, 0x03, 0xd9, 0x0f,
-	0x6d, 0x4c, 0x6c, 0x4d, 0x9c, 0x91, 0x66, 0x02, 0x25, 0x46, 0xdf, 0x23, 0x36, 0xd7, 0xe7, 0x3e,
-	0x94, 0x05, 0x3f, 0xc3, 0x63, 0x2d, 0x4e, 0x6c, 0xc3, 0xca, 0xd4, 0x89, 0x02, 0xf6, 0x2d, 0x98,
-	0x33, 0x05, 0x4d, 0x00, 0xaf, 0xc4, 0x81, 0xfb, 0x7b, 0x7c, 0x49, 0xe5, 0xe7, 0xb0, 0x14, 0x1d,
-	0x58, 0x5e, 0xd8, 0x09, 0xff, 0x45, 0xb0, 0x10, 0x1b, 0x15, 0x5c, 0x43, 0x0f, 0xec, 0x91, 0xa9,
-	0x79, 0x1f, 0x5a, 0x82, 0xa4, 0x2d, 0xb9, 0xf4, 0xa6, 0x20, 0x37, 0x07, 0xe1, 0xac, 0x4e, 0x47,
-	0xb2, 0x3a, 0xe8, 0x93, 0x99, 0xd7, 0xda, 0x27, 0x83, 0x46, 0x96, 0x3d, 0xbf, 0x91, 0x7d, 0x8b,
-	0x20, 0xc7, 0x2d, 0x7c, 0x5d, 0x99, 0x2d, 0xc3, 0x1c, 0x11, 0x2f, 0x02, 0x16, 0xfa, 0x9c, 0xea,
-	0xaf, 0x5f, 0xc3, 0xfb, 0xa3, 0x0e, 0xf3, 0x91, 0x3b, 0xf0, 0x12, 0x83, 0xaa, 0x06, 0xc5, 0x30,
-	0x07, 0x5f, 0x15, 0xcf, 0x2a, 0x5e, 0xa7, 0x17, 0xbd, 0xdd, 0x8c, 0xcd, 0xde, 0xe0, 0xfc, 0x2d,
-	0x85, 0x21, 0xcb, 0x1a, 0x34, 0x0f, 0x3a, 0xfb, 0x1d, 0x7c, 0x3a, 0xe0, 0x39, 0xcf, 0x17, 0xca,
-	0xaf, 0x11, 0x94, 0x82, 0xfc, 0xba, 0x63, 0x0c, 0xc9, 0xab, 0x48, 0x2f, 0x19, 0xe6, 0x0e, 0x8c,
-	0x21, 0x61, 0x18, 0xf8, 0x71, 0xfe, 0xda, 0xc5, 0x16, 0xf8, 0x99, 0x7b, 0xea, 0xbd, 0x75, 0x28,
-	0x84, 0x5a, 0x8d, 0xfb, 0x2c, 0x6b, 0xee, 0x6a, 0xad, 0x46, 0xab, 0xad, 0xfe, 0x42, 0x4a, 0x61,
-	0x80, 0x99, 0xfa, 0x56, 0xb7, 0xf9, 0x59, 0x43, 0x42, 0xef, 0xdd, 0x83, 0xbc, 0x6f, 0x2c, 0xce,
-	0x43, 0xae, 0xf1, 0xe9, 0x7e, 0xfd, 0x81, 0x94, 0x72, 0xb7, 0xec, 0xb6, 0xbb, 0x1a, 0x5f, 0x22,
-	0xbc, 0x00, 0x05, 0xb5, 0x71, 0xb7, 0xf1, 0x50, 0x6b, 0xd5, 0xbb, 0x5b, 0x3b, 0x52, 0x1a, 0x63,
-	0x28, 0x71, 0xc2, 0x6e, 0x5b, 0xd0, 0x32, 0x37, 0xfe, 0x39, 0x0b, 0x73, 0x9e, 0x35, 0xf8, 0x43,
-	0xc8, 0xee, 0x4d, 0x9c, 0x23, 0x7c, 0x29, 0xb8, 0x09, 0x9f, 0xdb, 0x06, 0x25, 0xa2, 0xe6, 0xc8,
-	0x2b, 0x53, 0x74, 0x7e, 0x97, 0x95, 0x14, 0xde, 0x86, 0x42, 0x68, 0xd6, 0xc3, 0x89, 0x1f, 0x5a,
-	0xe4, 0xcb, 0x09, 0xd3, 0x6e, 0xa0, 0xe3, 0x1a, 0xc2, 0x6d, 0x28, 0x31, 0x96, 0x37, 0xcb, 0x39,
-	0xd8, 0x7f, 0x35, 0x27, 0xbd, 0xcb, 0xe4, 0x2b, 0x67, 0x70, 0x7d, 0x58, 0x3b, 0xd1, 0x8f, 0x87,
-	0x72, 0xd2, 0x77, 0xc6, 0x38, 0xb8, 0x84, 0x91, 0x49, 0x49, 0xe1, 0x06, 0x40, 0x30, 0x70, 0xe0,
-	0xb7, 0x22, 0xc2, 0xe1, 0x21, 0x49, 0x96, 0x93, 0x58, 0xbe, 0x9a, 0x4d, 0xc8, 0xfb, 0x6d, 0x13,
-	0x57, 0x12, 0x3a, 0x29, 0x57, 0x72, 0x76, 0x8f, 0x55, 0x52, 0xf8, 0x0e, 0x14, 0xeb, 0xc3, 0xe1,
-	0x45, 0xd4, 0xc8, 0x61, 0x8e, 0x13, 0xd7, 0x33, 0xf4, 0x4b, 0x7d, 0xbc, 0x53, 0xe1, 0x77, 0xfd,
-	0x5b, 0xf5, 0xdc, 0xf6, 0x2b, 0xff, 0xf0, 0x5c, 0x39, 0xff, 0xb4, 0x2e, 0x2c, 0xc4, 0x1a, 0x0b,
-	0xae, 0xc6, 0x76, 0xc7, 0x7a, 0x9c, 0xbc, 0x7a, 0x26, 0xdf, 0xd7, 0xda, 0x13, 0x23, 0x6e, 0xf4,
-	0x3b, 0x33, 0x56, 0xa6, 0x83, 0x10, 0xff, 0x18, 0x2e, 0xff, 0xe0, 0xb9, 0x32, 0xa1, 0xac, 0x7c,
-	0x04, 0x97, 0x92, 0x3f, 0xc7, 0xe2, 0xab, 0x09, 0x39, 0x33, 0xfd, 0x69, 0x59, 0x7e, 0xf7, 0x3c,
-	0xb1, 0xd0, 0x61, 0x2d, 0x28, 0x86, 0xdb, 0x25, 0xf6, 0xd3, 0x32, 0xe1, 0xd5, 0x2f, 0xbf, 0x9d,
-	0xcc, 0x0c, 0xd4, 0x6d, 0x7e, 0xfc, 0xf8, 0x69, 0x35, 0xf5, 0xdd, 0xd3, 0x6a, 0xea, 0xfb, 0xa7,
-	0x55, 0xf4, 0xab, 0xd3, 0x2a, 0xfa, 0xd3, 0x69, 0x15, 0x7d, 0x73, 0x5a, 0x45, 0x8f, 0x4f, 0xab,
-	0xe8, 0x5f, 0xa7, 0x55, 0xf4, 0x9f, 0xd3, 0x6a, 0xea, 0xfb, 0xd3, 0x2a, 0xfa, 0xed, 0xb3, 0x6a,
-	0xea, 0xf1, 0xb3, 0x6a, 0xea, 0xbb, 0x67, 0xd5, 0xd4, 0x2f, 0x67, 0xfa, 0x43, 0x83, 0x58, 0xb4,
-	0x37, 0xc3, 0xfe, 0xab, 0x70, 0xf3, 0xff, 0x01, 0x00, 0x00, 0xff, 0xff, 0x7e, 0x0b, 0x2c, 0x55,
-	0xd0, 0x18, 0x00, 0x00,
+	0x15, 0xe7, 0xf0, 0x43, 0x16, 0x1f, 0x29, 0x6a, 0x35, 0x94, 0x44, 0x66, 0x1d, 0x53, 0xca, 0x06,
+	0x4e, 0xd5, 0x34, 0x91, 0xfc, 0xd5, 0xc2, 0x31, 0x52, 0xa4, 0x94, 0x44, 0x5b, 0xb2, 0x4d, 0x51,
+	0x5e, 0x52, 0x89, 0x5b, 0x20, 0x58, 0x2c, 0xc9, 0x91, 0xb4, 0x10, 0x77, 0xc9, 0xec, 0x0e, 0x03,
+	0x29, 0xa7, 0x02, 0x05, 0x7a, 0xee, 0xad, 0x97, 0xa2, 0x40, 0x6f, 0x45, 0x4f, 0x45, 0x7b, 0xe8,
+	0xad, 0xe7, 0x5c, 0x02, 0xf8, 0x18, 0xf4, 0x60, 0xd4, 0x72, 0x0f, 0xed, 0x2d, 0x40, 0xff, 0x81,
+	0x60, 0x67, 0x66, 0x3f, 0xb9, 0xb2, 0x64, 0x23, 0xf6, 0x49, 0x9c, 0xf7, 0x35, 0xbf, 0xf7, 0xe6,
+	0xbd, 0x37, 0x6f, 0x47, 0x50, 0x32, 0xac, 0x03, 0xe2, 0x50, 0x62, 0xaf, 0x8e, 0xec, 0x21, 0x1d,
+	0xe2, 0xa9, 0xde, 0xd0, 0xa6, 0xe4, 0x58, 0xfe, 0xf0, 0xc0, 0xa0, 0x87, 0xe3, 0xee, 0x6a, 0x6f,
+	0x68, 0xae, 0x1d, 0x0c, 0x0f, 0x86, 0x6b, 0x8c, 0xdd, 0x1d, 0xef, 0xb3, 0x15, 0x5b, 0xb0, 0x5f,
+	0x5c, 0x4d, 0xbe, 0x16, 0x16, 0xb7, 0xf5, 0x7d, 0xdd, 0xd2, 0xd7, 0x4c, 0xc3, 0x34, 0xec, 0xb5,
+	0xd1, 0xd1, 0x01, 0xff, 0x35, 0xea, 0xf2, 0xbf, 0x5c, 0x43, 0xf9, 0x2d, 0x02, 0xf9, 0xa1, 0xde,
+	0x25, 0x83, 0x1d, 0xdd, 0x24, 0x4e, 0xdd, 0xea, 0x7f, 0xaa, 0x0f, 0xc6, 0xc4, 0x51, 0xc9, 0x17,
+	0x63, 0xe2, 0x50, 0x7c, 0x0d, 0xa6, 0x4d, 0x9d, 0xf6, 0x0e, 0x89, 0xed, 0x54, 0xd1, 0x72, 0x66,
+	0xa5, 0x70, 0x63, 0x7e, 0x95, 0x43, 0x5b, 0x65, 0x5a, 0x4d, 0xce, 0x54, 0x7d, 0x29, 0xfc, 0x33,
+	0x28, 0xf6, 0x86, 0x63, 0x8b, 0x6a, 0x26, 0xa1, 0x87, 0xc3, 0x7e, 0x35, 0xbd, 0x8c, 0x56, 0x4a,
+	0x37, 0xca, 0x9e, 0xd6, 0x86, 0xcb, 0x6b, 0x32, 0x96, 0x5a, 0xe8, 0x05, 0x0b, 0x65, 0x0b, 0x2e,
+	0x27, 0xe2, 0x70, 0x46, 0x43, 0xcb, 0x21, 0xf8, 0xc7, 0x90, 0x33, 0x28, 0x31, 0x3d, 0x14, 0xe5,
+	0x08, 0x0a, 0x21, 0xcb, 0x25, 0x94, 0x4d, 0x28, 0x84, 0xa8, 0xf8, 0x0a, 0xc0, 0xc0, 0x5d, 0x6a,
+	0x96, 0x6e, 0x92, 0x2a, 0x5a, 0x46, 0x2b, 0x79, 0x35, 0x3f, 0xf0, 0xb6, 0xc2, 0x8b, 0x30, 0xf5,
+	0x25, 0x13, 0xac, 0xa6, 0x97, 0x33, 0x2b, 0x79, 0x55, 0xac, 0x94, 0xbf, 0x20, 0xb8, 0x12, 0x32,
+	0xb3, 0xa1, 0xdb, 0x7d, 0xc3, 0xd2, 0x07, 0x06, 0x3d, 0xf1, 0x62, 0xb3, 0x04, 0x85, 0xc0, 0x30,
+	0x07, 0x96, 0x57, 0xc1, 0xb7, 0xec, 0x44, 0x82, 0x97, 0x7e, 0xa5, 0xe0, 0x65, 0x2e, 0x18, 0xbc,
+	0x3d, 0xa8, 0x9d, 0x85, 0x55, 0xc4, 0xef, 0x66, 0x34, 0x7e, 0x57, 0x26, 0xe3, 0xd7, 0x26, 0xb6,
+	0x41, 0x1c, 0xb6, 0x85, 0x17, 0xc9, 0xa7, 0x08, 0x16, 0x12, 0x05, 0xce, 0x0b, 0xaa, 0x0e, 0x98,
+	0xb3, 0x59, 0x30, 0x35, 0x87, 0x69, 0x8a, 0x18, 0xdc, 0x7c, 0xe1, 0xd6, 0x13, 0xd4, 0x86, 0x45,
+	0xed, 0x13, 0x55, 0x1a, 0xc4, 0xc8, 0xf2, 0x06, 0x2c, 0x24, 0x8a, 0x62, 0x09, 0x32, 0x47, 0xe4,
+	0x44, 0x60, 0x72, 0x7f, 0xe2, 0x79, 0xc8, 0x31, 0x1c, 0x2c, 0x17, 0xb3, 0x2a, 0x5f, 0xdc, 0x49,
+	0xdf, 0x46, 0xca, 0x37, 0x08, 0x0a, 0x2a, 0xd1, 0xfb, 0xde, 0x91, 0xae, 0xc2, 0xa5, 0x2f, 0xc6,
+	0x1c, 0x6c, 0x2c, 0xdb, 0x1f, 0x8d, 0x89, 0xed, 0x9d, 0xbc, 0xea, 0x09, 0xe1, 0xc7, 0x50, 0xd1,
+	0x7b, 0x3d, 0x32, 0xa2, 0xa4, 0xaf, 0xd9, 0x22, 0xd4, 0x1a, 0x3d, 0x19, 0x09, 0x67, 0x4b, 0x37,
+	0x96, 0x3d, 0xfd, 0xd0, 0x2e, 0xab, 0xde, 0xa1, 0x74, 0x4e, 0x46, 0x44, 0x5d, 0xf0, 0x0c, 0x84,
+	0xa9, 0x8e, 0x72, 0x0b, 0x8a, 0x61, 0x02, 0x2e, 0xc0, 0xa5, 0x76, 0xbd, 0xb9, 0xfb, 0xb0, 0xd1,
+	0x96, 0x52, 0xb8, 0x02, 0xe5, 0x76, 0x47, 0x6d, 0xd4, 0x9b, 0x8d, 0x4d, 0xed, 0x71, 0x4b, 0xd5,
+	0x36, 0xb6, 0xf6, 0x76, 0x1e, 0xb4, 0x25, 0xa4, 0x7c, 0x02, 0x45, 0xbe, 0x91, 0x38, 0xf5, 0x35,
+	0xb8, 0x64, 0x13, 0x67, 0x3c, 0xa0, 0x9e, 0x3f, 0x0b, 0x31, 0x7f, 0xb8, 0x9c, 0xea, 0x49, 0x29,
+	0x27, 0x80, 0xdb, 0xd4, 0x26, 0xba, 0x19, 0x31, 0xb3, 0x0e, 0xa5, 0xde, 0xe1, 0xd8, 0x3a, 0x22,
+	0x7d, 0xef, 0x28, 0xb9, 0xb5, 0xcb, 0x9e, 0x35, 0xae, 0xb3, 0xc1, 0x65, 0xf8, 0x61, 0xa8, 0x33,
+	0xbd, 0xf0, 0xd2, 0xad, 0x16, 0x37, 0x6a, 0x27, 0x9a, 0x61, 0xf5, 0xc9, 0x31, 0x3b, 0x8a, 0x8c,
+	0x0a, 0x8c, 0xb4, 0xed, 0x52, 0x94, 0xbf, 0x22, 0x28, 0x27, 0xd8, 0xc1, 0xfb, 0x30, 0xc5, 0x0e,
+	0x3f, 0x5e, 0xfa, 0xa3, 0x2e, 0xcf, 0x95, 0x5d, 0xdd, 0xb0, 0xd7, 0x3f, 0xfa, 0xfa, 0xe9, 0x52,
+	0xea, 0x5f, 0x4f, 0x97, 0xae, 0x5f, 0xa4, 0x01, 0x72, 0xbd, 0x7a, 0x5f, 0x1f, 0x51, 0x62, 0xab,
+	0xc2, 0x3a, 0xbe, 0x0e, 0x53, 0x0c, 0xb1, 0x97, 0xa7, 0xe5, 0x04, 0xe7, 0xd6, 0xb3, 0xee, 0x3e,
+	0xaa, 0x10, 0x54, 0x7e, 0x9f, 0x86, 0x42, 0x88, 0x8b, 0x6b, 0x50, 0x30, 0x0d, 0x4b, 0xa3, 0x86,
+	0x49, 0x34, 0x56, 0x6a, 0xae, 0x8f, 0x79, 0xd3, 0xb0, 0x3a, 0x86, 0x49, 0x9a, 0x0e, 0xe3, 0xeb,
+	0xc7, 0x3e, 0x3f, 0x2d, 0xf8, 0xfa, 0xb1, 0xe0, 0x5f, 0x83, 0xac, 0x9b, 0x3c, 0xa2, 0xec, 0xdf,
+	0x4e, 0x00, 0xb0, 0xda, 0xb0, 0x7a, 0xc3, 0xbe, 0x61, 0x1d, 0xa8, 0x4c, 0x12, 0xef, 0x42, 0xb6,
+	0xaf, 0x53, 0xbd, 0x9a, 0x5d, 0x46, 0x2b, 0xc5, 0xf5, 0x8f, 0x45, 0x14, 0x6e, 0x5d, 0x28, 0x0a,
+	0x7b, 0x96, 0xa3, 0xef, 0x93, 0xf5, 0x13, 0x4a, 0xda, 0x03, 0xa3, 0x47, 0x54, 0x66, 0x49, 0xd9,
+	0x84, 0x69, 0x6f, 0x0f, 0x37, 0xe9, 0xf6, 0x76, 0x1e, 0xec, 0xb4, 0x3e, 0xdb, 0x91, 0x52, 0xf8,
+	0x12, 0x64, 0x1e, 0xb7, 0x54, 0x09, 0xe1, 0x19, 0xc8, 0x6f, 0x6d, 0xb7, 0x3b, 0xad, 0x7b, 0x6a,
+	0xbd, 0x29, 0xa5, 0x71, 0x19, 0x66, 0xef, 0x3e, 0x6c, 0xd5, 0x3b, 0x5a, 0x40, 0xcc, 0x28, 0xff,
+	0x41, 0x50, 0x0c, 0x97, 0x0c, 0xfe, 0x00, 0xb0, 0x43, 0x75, 0x9b, 0x32, 0xe7, 0x1d, 0xaa, 0x9b,
+	0xa3, 0x20, 0x42, 0x12, 0xe3, 0x74, 0x3c, 0x46, 0xd3, 0xc1, 0x2b, 0x20, 0x11, 0xab, 0x1f, 0x95,
+	0xe5, 0xd1, 0x2a, 0x11, 0xab, 0x1f, 0x96, 0x0c, 0xf7, 0xd8, 0xcc, 0x85, 0x7a, 0xec, 0xcf, 0xe1,
+	0xb2, 0xc3, 0x02, 0x6a, 0x58, 0x07, 0x1a, 0x3f, 0x48, 0xad, 0xeb, 0x32, 0x35, 0xc7, 0xf8, 0x8a,
+	0x54, 0xfb, 0xac, 0x47, 0x54, 0x7d, 0x11, 0x16, 0x76, 0x67, 0xdd, 0x15, 0x68, 0x1b, 0x5f, 0x91,
+	0xfb, 0xd9, 0xe9, 0xac, 0x94, 0x53, 0x73, 0x87, 0x86, 0x45, 0x1d, 0xe5, 0x4f, 0x08, 0xe6, 0x1b,
+	0xc7, 0xc4, 0x1c, 0x0d, 0x74, 0xfb, 0x8d, 0xb8, 0x7b, 0x7d, 0xc2, 0xdd, 0x85, 0x24, 0x77, 0x9d,
+	0xc0, 0x5f, 0xe5, 0x1e, 0x94, 0xeb, 0x3d, 0x6a, 0x7c, 0x29, 0x9a, 0xe4, 0x2b, 0xdf, 0xec, 0xca,
+	0x03, 0x98, 0x89, 0x74, 0x0d, 0x7c, 0x07, 0x80, 0x41, 0x4e, 0x6a, 0x98, 0xa3, 0xee, 0xaa, 0x8b,
+	0x9b, 0xef, 0x29, 0xca, 0x26, 0x24, 0xad, 0xfc, 0x3f, 0x0d, 0x65, 0x66, 0xcd, 0x6b, 0x37, 0xc2,
+	0xe6, 0x27, 0x50, 0xe0, 0x67, 0x12, 0x36, 0x5a, 0xf1, 0x90, 0x05, 0x26, 0xc3, 0xe5, 0x18, 0xd6,
+	0x88, 0x81, 0x4a, 0xbf, 0x0c, 0x28, 0x7c, 0x1f, 0xa4, 0x20, 0x35, 0x84, 0x05, 0x1e, 0xe5, 0xb7,
+	0x22, 0x7d, 0x93, 0x63, 0x8e, 0x98, 0x99, 0xf5, 0x15, 0x39, 0x19, 0xdf, 0x82, 0x8a, 0xe1, 0x68,
+	0xee, 0xb1, 0x0e, 0xf7, 0x85, 0x2d, 0x8d, 0xcb, 0xb0, 0x62, 0x9d, 0x56, 0xcb, 0x86, 0xd3, 0xb0,
+	0xfa, 0xad, 0x7d, 0x2e, 0xcf, 0x4d, 0xe2, 0xcf, 0xa1, 0x12, 0x47, 0x20, 0x72, 0xb4, 0x9a, 0x63,
+	0x40, 0x96, 0xce, 0x04, 0x22, 0x12, 0x95, 0xc3, 0x59, 0x88, 0xc1, 0xe1, 0x4c, 0xe5, 0x0f, 0x08,
+	0xe6, 0x26, 0x14, 0xdf, 0x58, 0x87, 0x5d, 0x12, 0x67, 0xab, 0xb1, 0xd1, 0xc5, 0xbb, 0x02, 0x18,
+	0x89, 0xdd, 0xfd, 0x8a, 0x01, 0x95, 0x33, 0xdc, 0xc2, 0xef, 0x40, 0x51, 0x84, 0x83, 0xdf, 0x1f,
+	0x88, 0x95, 0x69, 0x81, 0xd3, 0xd8, 0x05, 0x82, 0x7f, 0x12, 0x6b, 0xe0, 0x33, 0xfe, 0xd8, 0x94,
+	0xd0, 0xba, 0xdb, 0xb0, 0x10, 0x2b, 0xdc, 0x1f, 0x20, 0xa9, 0xff, 0x89, 0x00, 0x87, 0x07, 0x52,
+	0x51, 0x6a, 0xe7, 0x0c, 0x4b, 0xc9, 0xbd, 0x22, 0xfd, 0x12, 0xbd, 0x22, 0x73, 0x6e, 0xaf, 0x70,
+	0x53, 0xee, 0x02, 0xbd, 0xe2, 0x36, 0x94, 0x23, 0xf8, 0x45, 0x4c, 0xde, 0x81, 0x62, 0x68, 0x9c,
+	0xf3, 0x46, 0xdd, 0x42, 0x30, 0x93, 0x39, 0xca, 0x1f, 0x11, 0xcc, 0x05, 0xf3, 0xfb, 0x9b, 0x6d,
+	0x83, 0x17, 0x72, 0xed, 0xa7, 0x80, 0xc3, 0xf8, 0x84, 0x67, 0xe7, 0xcd, 0xf0, 0xca, 0x7d, 0x90,
+	0xf6, 0x1c, 0x62, 0xb7, 0xa9, 0x4e, 0x7d, 0xaf, 0xe2, 0x53, 0x3a, 0xba, 0xe0, 0x94, 0xfe, 0x0f,
+	0x04, 0x73, 0x21, 0x63, 0x02, 0xc2, 0x55, 0xef, 0xe3, 0xcf, 0x18, 0x5a, 0x9a, 0xad, 0x53, 0x9e,
+	0x21, 0x48, 0x9d, 0xf1, 0xa9, 0xaa, 0x4e, 0x89, 0x9b, 0x44, 0xd6, 0xd8, 0x0c, 0x46, 0x69, 0x37,
+	0xfd, 0xf3, 0xd6, 0xd8, 0xab, 0xe1, 0x0f, 0x00, 0xeb, 0x23, 0x43, 0x8b, 0x59, 0xca, 0x30, 0x4b,
+	0x92, 0x3e, 0x32, 0xb6, 0x23, 0xc6, 0x56, 0xa1, 0x6c, 0x8f, 0x07, 0x24, 0x2e, 0x9e, 0x65, 0xe2,
+	0x73, 0x2e, 0x2b, 0x22, 0xaf, 0x7c, 0x0e, 0x65, 0x17, 0xf8, 0xf6, 0x66, 0x14, 0x7a, 0x05, 0x2e,
+	0x8d, 0x1d, 0x62, 0x6b, 0x46, 0x5f, 0x64, 0xf5, 0x94, 0xbb, 0xdc, 0xee, 0xe3, 0x0f, 0xc5, 0x58,
+	0x92, 0x5e, 0x46, 0xe1, 0xe6, 0x39, 0xe1, 0xbc, 0x98, 0x39, 0xee, 0x01, 0x76, 0x59, 0x4e, 0xd4,
+	0xfa, 0x75, 0xc8, 0x39, 0x2e, 0x21, 0x3e, 0x6c, 0x26, 0x20, 0x51, 0xb9, 0xa4, 0xf2, 0x37, 0x04,
+	0xb5, 0x26, 0xa1, 0xb6, 0xd1, 0x73, 0xee, 0x0e, 0xed, 0x68, 0x2a, 0xbc, 0xe6, 0x94, 0xbc, 0x0d,
+	0x45, 0x2f, 0xd7, 0x34, 0x87, 0xd0, 0x17, 0xdf, 0xce, 0x05, 0x4f, 0xb4, 0x4d, 0xa8, 0xf2, 0x00,
+	0x96, 0xce, 0xc4, 0x2c, 0x42, 0xb1, 0x02, 0x53, 0x26, 0x13, 0x11, 0xb1, 0x90, 0x82, 0x86, 0xc4,
+	0x55, 0x55, 0xc1, 0x57, 0x46, 0xb0, 0x28, 0x8c, 0x35, 0x09, 0xd5, 0xdd, 0xe8, 0x7a, 0x8e, 0xcf,
+	0x43, 0x6e, 0x60, 0x98, 0x06, 0x65, 0xbe, 0xce, 0xa9, 0x7c, 0xe1, 0x3a, 0xc8, 0x7e, 0x68, 0x23,
+	0x62, 0x6b, 0x62, 0x8f, 0x34, 0x13, 0x28, 0x31, 0xfa, 0x2e, 0xb1, 0xb9, 0x3d, 0xf7, 0x43, 0x59,
+	0xf0, 0x33, 0xfc, 0xac, 0xc5, 0x8e, 0x2d, 0xa8, 0x4c, 0xec, 0x28, 0x60, 0xdf, 0x82, 0x69, 0x53,
+	0xd0, 0x04, 0xf0, 0x6a, 0x1c, 0xb8, 0xaf, 0xe3, 0x4b, 0x2a, 0xbf, 0x80, 0xf9, 0xe8, 0xc0, 0xf2,
+	0xd2, 0x41, 0xa8, 0xc2, 0xe2, 0xc6, 0xd0, 0xa1, 0x75, 0x4a, 0x6d, 0xa3, 0x3b, 0x66, 0x59, 0xcc,
+	0x83, 0xa0, 0x1c, 0x41, 0x65, 0x82, 0x23, 0xcc, 0xbb, 0xf1, 0x71, 0x83, 0x2f, 0x52, 0x99, 0x2f,
+	0xf0, 0x9d, 0xc8, 0xf3, 0x40, 0x21, 0x18, 0xca, 0x63, 0x66, 0x58, 0x1b, 0xf4, 0xee, 0x18, 0xae,
+	0xa1, 0x3c, 0x82, 0xf9, 0x24, 0xa9, 0xe0, 0x7b, 0x54, 0xec, 0xc4, 0x16, 0xf8, 0x5d, 0x98, 0xd1,
+	0x99, 0xdb, 0xd1, 0x1a, 0x2f, 0xea, 0xa1, 0x58, 0x28, 0xff, 0x43, 0x30, 0x1b, 0x1b, 0x82, 0xdc,
+	0x23, 0xdc, 0xb7, 0x87, 0xa6, 0xe6, 0x3d, 0x21, 0x05, 0xe5, 0x58, 0x72, 0xe9, 0xdb, 0x82, 0xbc,
+	0xdd, 0x0f, 0xd7, 0x6b, 0x3a, 0x52, 0xaf, 0xc1, 0x04, 0x90, 0x79, 0xad, 0x13, 0x40, 0x70, 0x45,
+	0x67, 0xcf, 0xbf, 0xa2, 0xbf, 0x41, 0x90, 0xe3, 0x1e, 0xbe, 0xae, 0x9a, 0x95, 0x61, 0x9a, 0x88,
+	0x6f, 0x1d, 0x96, 0xd4, 0x39, 0xd5, 0x5f, 0xbf, 0x86, 0x2f, 0xab, 0x3a, 0xcc, 0x44, 0xaa, 0xfb,
+	0x15, 0x46, 0x70, 0x0d, 0x8a, 0x61, 0x0e, 0xbe, 0x2a, 0x3e, 0x18, 0xf9, 0x0d, 0x34, 0xe7, 0x69,
+	0x33, 0x36, 0x7b, 0x5d, 0x60, 0x6c, 0x8c, 0x21, 0xcb, 0x46, 0x0f, 0x7e, 0xe8, 0xec, 0x77, 0x90,
+	0x84, 0x99, 0x50, 0x12, 0x2a, 0xbf, 0x41, 0x50, 0x0a, 0xf2, 0xeb, 0xae, 0x31, 0x20, 0x3f, 0x44,
+	0x7a, 0xc9, 0x30, 0xbd, 0x6f, 0x0c, 0x08, 0xc3, 0xc0, 0xb7, 0xf3, 0xd7, 0x2e, 0xb6, 0x20, 0xce,
+	0x3c, 0x52, 0xef, 0xaf, 0x40, 0x21, 0x74, 0x89, 0xba, 0x1f, 0x9c, 0xdb, 0x3b, 0x5a, 0xb3, 0xd1,
+	0x6c, 0xa9, 0xbf, 0x94, 0x52, 0x18, 0x60, 0xaa, 0xbe, 0xd1, 0xd9, 0xfe, 0xb4, 0x21, 0xa1, 0xf7,
+	0xef, 0x43, 0xde, 0x77, 0x16, 0xe7, 0x21, 0xd7, 0x78, 0xb4, 0x57, 0x7f, 0x28, 0xa5, 0x5c, 0x95,
+	0x9d, 0x56, 0x47, 0xe3, 0x4b, 0x84, 0x67, 0xa1, 0xa0, 0x36, 0xee, 0x35, 0x1e, 0x6b, 0xcd, 0x7a,
+	0x67, 0x63, 0x4b, 0x4a, 0x63, 0x0c, 0x25, 0x4e, 0xd8, 0x69, 0x09, 0x5a, 0xe6, 0xc6, 0xdf, 0xa7,
+	0x61, 0xda, 0xf3, 0x06, 0x7f, 0x04, 0xd9, 0xdd, 0xb1, 0x73, 0x88, 0x17, 0x83, 0x4a, 0xf8, 0xcc,
+	0x36, 0x28, 0x11, 0x8d, 0x44, 0xae, 0x4c, 0xd0, 0x79, 0x1b, 0x51, 0x52, 0x78, 0x13, 0x0a, 0xa1,
+	0x29, 0x16, 0x27, 0x3e, 0x21, 0xc9, 0x97, 0x13, 0xe6, 0xf8, 0xc0, 0xc6, 0x35, 0x84, 0x5b, 0x50,
+	0x62, 0x2c, 0x6f, 0x4a, 0x75, 0xb0, 0xdf, 0x7a, 0x92, 0xbe, 0x38, 0xe5, 0x2b, 0x67, 0x70, 0x7d,
+	0x58, 0x5b, 0xd1, 0x67, 0x51, 0x39, 0xe9, 0x05, 0x35, 0x0e, 0x2e, 0x61, 0x18, 0x54, 0x52, 0xb8,
+	0x01, 0x10, 0x8c, 0x52, 0xf8, 0xad, 0x88, 0x70, 0x78, 0xfc, 0x93, 0xe5, 0x24, 0x96, 0x6f, 0x66,
+	0x1d, 0xf2, 0xfe, 0x40, 0x80, 0xab, 0x09, 0x33, 0x02, 0x37, 0x72, 0xf6, 0xf4, 0xa0, 0xa4, 0xf0,
+	0x5d, 0x28, 0xd6, 0x07, 0x83, 0x8b, 0x98, 0x91, 0xc3, 0x1c, 0x27, 0x6e, 0x67, 0x00, 0x95, 0x33,
+	0xee, 0x60, 0xfc, 0x9e, 0x5f, 0x55, 0x2f, 0x1c, 0x2c, 0xe4, 0x1f, 0x9d, 0x2b, 0xe7, 0xef, 0xd6,
+	0x81, 0xd9, 0xd8, 0x95, 0x89, 0x6b, 0x31, 0xed, 0xd8, 0xed, 0x2d, 0x2f, 0x9d, 0xc9, 0xf7, 0xad,
+	0x76, 0xa1, 0x1c, 0xc4, 0xd9, 0x7f, 0x41, 0xc7, 0xca, 0xe4, 0x21, 0xc4, 0x9f, 0xf9, 0xe5, 0x77,
+	0x5f, 0x28, 0x13, 0xca, 0xca, 0x23, 0x58, 0x4c, 0x7e, 0x68, 0xc6, 0x57, 0x13, 0x72, 0x66, 0xf2,
+	0xd1, 0x5c, 0x7e, 0xef, 0x3c, 0xb1, 0xd0, 0x66, 0x4d, 0x28, 0x86, 0x07, 0x01, 0xec, 0xa7, 0x65,
+	0xc2, 0x7b, 0x86, 0xfc, 0x76, 0x32, 0x33, 0x64, 0xae, 0x03, 0xb3, 0xb1, 0xeb, 0x38, 0x88, 0x7a,
+	0xf2, 0xb8, 0x20, 0x2f, 0x9d, 0xc9, 0xf7, 0xec, 0xae, 0x7f, 0xfc, 0xe4, 0x59, 0x2d, 0xf5, 0xed,
+	0xb3, 0x5a, 0xea, 0xbb, 0x67, 0x35, 0xf4, 0xeb, 0xd3, 0x1a, 0xfa, 0xf3, 0x69, 0x0d, 0x7d, 0x7d,
+	0x5a, 0x43, 0x4f, 0x4e, 0x6b, 0xe8, 0xdf, 0xa7, 0x35, 0xf4, 0xdf, 0xd3, 0x5a, 0xea, 0xbb, 0xd3,
+	0x1a, 0xfa, 0xdd, 0xf3, 0x5a, 0xea, 0xc9, 0xf3, 0x5a, 0xea, 0xdb, 0xe7, 0xb5, 0xd4, 0xaf, 0xa6,
+	0x7a, 0x03, 0x83, 0x58, 0xb4, 0x3b, 0xc5, 0xfe, 0x0b, 0x73, 0xf3, 0xfb, 0x01, 0x00, 0x34, 0xd5,
+	0x43, 0xc7, 0x00, 0x1a, 0x00, 0x00,
 }
 
 func (x CountMethod) String() string {
@@ -3299,6 +3444,86 @@ func (this *ActiveSeriesResponse) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *CostAttributionRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*CostAttributionRequest)
+	if !ok {
+		that2, ok := that.(CostAttributionRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	return true
+}
+func (this *CostAttributionResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*CostAttributionResponse)
+	if !ok {
+		that2, ok := that.(CostAttributionResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Label != that1.Label {
+		return false
+	}
+	if len(this.Values) != len(that1.Values) {
+		return false
+	}
+	for i := range this.Values {
+		if !this.Values[i].Equal(&that1.Values[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *CostAttributionValue) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*CostAttributionValue)
+	if !ok {
+		that2, ok := that.(CostAttributionValue)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Value != that1.Value {
+		return false
+	}
+	if this.ActiveSeries != that1.ActiveSeries {
+		return false
+	}
+	return true
+}
 func (this *TimeSeriesChunk) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
@@ -3918,6 +4143,43 @@ func (this *ActiveSeriesResponse) GoString() string {
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
+func (this *CostAttributionRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 4)
+	s = append(s, "&client.CostAttributionRequest{")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *CostAttributionResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&client.CostAttributionResponse{")
+	s = append(s, "Label: "+fmt.Sprintf("%#v", this.Label)+",\n")
+	if this.Values != nil {
+		vs := make([]CostAttributionValue, len(this.Values))
+		for i := range vs {
+			vs[i] = this.Values[i]
+		}
+		s = append(s, "Values: "+fmt.Sprintf("%#v", vs)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *CostAttributionValue) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&client.CostAttributionValue{")
+	s = append(s, "Value: "+fmt.Sprintf("%#v", this.Value)+",\n")
+	s = append(s, "ActiveSeries: "+fmt.Sprintf("%#v", this.ActiveSeries)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
 func (this *TimeSeriesChunk) GoString() string {
 	if this == nil {
 		return "nil"
@@ -4025,6 +4287,7 @@ type IngesterClient interface {
 	// The listing order of the labels is not guaranteed.
 	LabelValuesCardinality(ctx context.Context, in *LabelValuesCardinalityRequest, opts ...grpc.CallOption) (Ingester_LabelValuesCardinalityClient, error)
 	ActiveSeries(ctx context.Context, in *ActiveSeriesRequest, opts ...grpc.CallOption) (Ingester_ActiveSeriesClient, error)
+	CostAttribution(ctx context.Context, in *CostAttributionRequest, opts ...grpc.CallOption) (*CostAttributionResponse, error)
 }
 
 type ingesterClient struct {
@@ -4235,6 +4498,15 @@ func (x *ingesterActiveSeriesClient) Recv() (*ActiveSeriesResponse, error) {
 	return m, nil
 }
 
+func (c *ingesterClient) CostAttribution(ctx context.Context, in *CostAttributionRequest, opts ...grpc.CallOption) (*CostAttributionResponse, error) {
+	out := new(CostAttributionResponse)
+	err := c.cc.Invoke(ctx, "/cortex.Ingester/CostAttribution", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // IngesterServer is the server API for Ingester service.
 type IngesterServer interface {
 	Push(context.Context, *mimirpb.WriteRequest) (*mimirpb.WriteResponse, error)
@@ -4254,6 +4526,7 @@ type IngesterServer interface {
 	// The listing order of the labels is not guaranteed.
 	LabelValuesCardinality(*LabelValuesCardinalityRequest, Ingester_LabelValuesCardinalityServer) error
 	ActiveSeries(*ActiveSeriesRequest, Ingester_ActiveSeriesServer) error
+	CostAttribution(context.Context, *CostAttributionRequest) (*CostAttributionResponse, error)
 }
 
 // UnimplementedIngesterServer can be embedded to have forward compatible implementations.
@@ -4296,6 +4569,9 @@ func (*UnimplementedIngesterServer) LabelValuesCardinality(req *LabelValuesCardi
 func (*UnimplementedIngesterServer) ActiveSeries(req *ActiveSeriesRequest, srv Ingester_ActiveSeriesServer) error {
 	return status.Errorf(codes.Unimplemented, "method ActiveSeries not implemented")
 }
+func (*UnimplementedIngesterServer) CostAttribution(ctx context.Context, req *CostAttributionRequest) (*CostAttributionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CostAttribution not implemented")
+}
 
 func RegisterIngesterServer(s *grpc.Server, srv IngesterServer) {
 	s.RegisterService(&_Ingester_serviceDesc, srv)
@@ -4529,6 +4805,24 @@ func (x *ingesterActiveSeriesServer) Send(m *ActiveSeriesResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Ingester_CostAttribution_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CostAttributionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IngesterServer).CostAttribution(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cortex.Ingester/CostAttribution",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IngesterServer).CostAttribution(ctx, req.(*CostAttributionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Ingester_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "cortex.Ingester",
 	HandlerType: (*IngesterServer)(nil),
@@ -4565,6 +4859,10 @@ var _Ingester_serviceDesc = grpc.ServiceDesc{
 			MethodName: "MetricsMetadata",
 			Handler:    _Ingester_MetricsMetadata_Handler,
 		},
+		{
+			MethodName: "CostAttribution",
+			Handler:    _Ingester_CostAttribution_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -5974,7 +6272,7 @@ func (m *ActiveSeriesResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *TimeSeriesChunk) Marshal() (dAtA []byte, err error) {
+func (m *CostAttributionRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -5984,62 +6282,20 @@ func (m *TimeSeriesChunk) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *TimeSeriesChunk) MarshalTo(dAtA []byte) (int, error) {
+func (m *CostAttributionRequest) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *TimeSeriesChunk) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *CostAttributionRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Chunks) > 0 {
-		for iNdEx := len(m.Chunks) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Chunks[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintIngester(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x22
-		}
-	}
-	if len(m.Labels) > 0 {
-		for iNdEx := len(m.Labels) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size := m.Labels[iNdEx].Size()
-				i -= size
-				if _, err := m.Labels[iNdEx].MarshalTo(dAtA[i:]); err != nil {
-					return 0, err
-				}
-				i = encodeVarintIngester(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x1a
-		}
-	}
-	if len(m.UserId) > 0 {
-		i -= len(m.UserId)
-		copy(dAtA[i:], m.UserId)
-		i = encodeVarintIngester(dAtA, i, uint64(len(m.UserId)))
-		i--
-		dAtA[i] = 0x12
-	}
-	if len(m.FromIngesterId) > 0 {
-		i -= len(m.FromIngesterId)
-		copy(dAtA[i:], m.FromIngesterId)
-		i = encodeVarintIngester(dAtA, i, uint64(len(m.FromIngesterId)))
-		i--
-		dAtA[i] = 0xa
-	}
 	return len(dAtA) - i, nil
 }
 
-func (m *Chunk) Marshal() (dAtA []byte, err error) {
+func (m *CostAttributionResponse) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -6049,7 +6305,151 @@ func (m *Chunk) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *Chunk) MarshalTo(dAtA []byte) (int, error) {
+func (m *CostAttributionResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CostAttributionResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Values) > 0 {
+		for iNdEx := len(m.Values) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Values[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintIngester(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Label) > 0 {
+		i -= len(m.Label)
+		copy(dAtA[i:], m.Label)
+		i = encodeVarintIngester(dAtA, i, uint64(len(m.Label)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CostAttributionValue) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CostAttributionValue) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CostAttributionValue) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.ActiveSeries != 0 {
+		i = encodeVarintIngester(dAtA, i, uint64(m.ActiveSeries))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Value) > 0 {
+		i -= len(m.Value)
+		copy(dAtA[i:], m.Value)
+		i = encodeVarintIngester(dAtA, i, uint64(len(m.Value)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TimeSeriesChunk) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TimeSeriesChunk) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TimeSeriesChunk) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Chunks) > 0 {
+		for iNdEx := len(m.Chunks) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Chunks[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintIngester(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if len(m.Labels) > 0 {
+		for iNdEx := len(m.Labels) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size := m.Labels[iNdEx].Size()
+				i -= size
+				if _, err := m.Labels[iNdEx].MarshalTo(dAtA[i:]); err != nil {
+					return 0, err
+				}
+				i = encodeVarintIngester(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.UserId) > 0 {
+		i -= len(m.UserId)
+		copy(dAtA[i:], m.UserId)
+		i = encodeVarintIngester(dAtA, i, uint64(len(m.UserId)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.FromIngesterId) > 0 {
+		i -= len(m.FromIngesterId)
+		copy(dAtA[i:], m.FromIngesterId)
+		i = encodeVarintIngester(dAtA, i, uint64(len(m.FromIngesterId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Chunk) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Chunk) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
@@ -6820,6 +7220,50 @@ func (m *ActiveSeriesResponse) Size() (n int) {
 	return n
 }
 
+func (m *CostAttributionRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *CostAttributionResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Label)
+	if l > 0 {
+		n += 1 + l + sovIngester(uint64(l))
+	}
+	if len(m.Values) > 0 {
+		for _, e := range m.Values {
+			l = e.Size()
+			n += 1 + l + sovIngester(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *CostAttributionValue) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovIngester(uint64(l))
+	}
+	if m.ActiveSeries != 0 {
+		n += 1 + sovIngester(uint64(m.ActiveSeries))
+	}
+	return n
+}
+
 func (m *TimeSeriesChunk) Size() (n int) {
 	if m == nil {
 		return 0
@@ -7415,6 +7859,42 @@ func (this *ActiveSeriesResponse) String() string {
 	}, "")
 	return s
 }
+func (this *CostAttributionRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&CostAttributionRequest{`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *CostAttributionResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForValues := "[]CostAttributionValue{"
+	for _, f := range this.Values {
+		repeatedStringForValues += strings.Replace(strings.Replace(f.String(), "CostAttributionValue", "CostAttributionValue", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForValues += "}"
+	s := strings.Join([]string{`&CostAttributionResponse{`,
+		`Label:` + fmt.Sprintf("%v", this.Label) + `,`,
+		`Values:` + repeatedStringForValues + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *CostAttributionValue) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&CostAttributionValue{`,
+		`Value:` + fmt.Sprintf("%v", this.Value) + `,`,
+		`ActiveSeries:` + fmt.Sprintf("%v", this.ActiveSeries) + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func (this *TimeSeriesChunk) String() string {
 	if this == nil {
 		return "nil"
@@ -11145,6 +11625,273 @@ func (m *ActiveSeriesResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *CostAttributionRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowIngester
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CostAttributionRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CostAttributionRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipIngester(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthIngester
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CostAttributionResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowIngester
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CostAttributionResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CostAttributionResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Label", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowIngester
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthIngester
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthIngester
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Label = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Values", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowIngester
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthIngester
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthIngester
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Values = append(m.Values, CostAttributionValue{})
+			if err := m.Values[len(m.Values)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipIngester(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthIngester
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CostAttributionValue) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowIngester
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CostAttributionValue: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CostAttributionValue: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowIngester
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthIngester
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthIngester
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ActiveSeries", wireType)
+			}
+			m.ActiveSeries = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowIngester
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ActiveSeries |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipIngester(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthIngester
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *TimeSeriesChunk) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
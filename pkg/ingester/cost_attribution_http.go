@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package ingester
+
+import (
+	"context"
+	"net/http"
+	"sort"
+
+	"github.com/grafana/dskit/tenant"
+
+	"github.com/grafana/mimir/pkg/ingester/client"
+	"github.com/grafana/mimir/pkg/util"
+)
+
+type costAttributionResponse struct {
+	Label  string                         `json:"label"`
+	Values []costAttributionResponseValue `json:"values"`
+}
+
+type costAttributionResponseValue struct {
+	Value        string `json:"value"`
+	ActiveSeries int    `json:"active_series"`
+}
+
+// CostAttribution implements the CostAttribution RPC. It returns the active series of the tenant in this ingester,
+// grouped by the values of the cost attribution label of the tenant. The response has no label if the cost
+// attribution is disabled.
+func (i *Ingester) CostAttribution(ctx context.Context, _ *client.CostAttributionRequest) (_ *client.CostAttributionResponse, err error) {
+	defer func() { err = i.mapReadErrorToErrorWithStatus(err) }()
+	if err := i.checkAvailable(); err != nil {
+		return nil, err
+	}
+	if err := i.checkReadOverloaded(); err != nil {
+		return nil, err
+	}
+
+	userID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !i.cfg.ActiveSeriesMetrics.Enabled {
+		return &client.CostAttributionResponse{}, nil
+	}
+
+	db := i.getTSDB(userID)
+	if db == nil {
+		return &client.CostAttributionResponse{}, nil
+	}
+
+	ca := db.activeSeries.CurrentCostAttribution()
+	if ca == nil {
+		return &client.CostAttributionResponse{}, nil
+	}
+
+	resp := &client.CostAttributionResponse{Label: ca.Label()}
+	for value, active := range db.activeSeries.ActiveByAttribution() {
+		resp.Values = append(resp.Values, client.CostAttributionValue{Value: value, ActiveSeries: uint64(active)})
+	}
+	return resp, nil
+}
+
+// CostAttributionHandler returns the active series of the tenant in this ingester, grouped by the values of the
+// cost attribution label of the tenant.
+func (i *Ingester) CostAttributionHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := tenant.TenantID(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if !i.cfg.ActiveSeriesMetrics.Enabled {
+		http.Error(w, "active series tracking is disabled", http.StatusNotFound)
+		return
+	}
+
+	db := i.getTSDB(userID)
+	if db == nil {
+		http.Error(w, "TSDB not found for tenant "+userID, http.StatusNotFound)
+		return
+	}
+
+	ca := db.activeSeries.CurrentCostAttribution()
+	if ca == nil {
+		http.Error(w, "cost attribution is disabled for tenant "+userID, http.StatusNotFound)
+		return
+	}
+
+	resp := costAttributionResponse{Label: ca.Label(), Values: []costAttributionResponseValue{}}
+	for value, active := range db.activeSeries.ActiveByAttribution() {
+		resp.Values = append(resp.Values, costAttributionResponseValue{Value: value, ActiveSeries: active})
+	}
+	sort.Slice(resp.Values, func(i, j int) bool {
+		if resp.Values[i].ActiveSeries != resp.Values[j].ActiveSeries {
+			return resp.Values[i].ActiveSeries > resp.Values[j].ActiveSeries
+		}
+		return resp.Values[i].Value < resp.Values[j].Value
+	})
+
+	util.WriteJSONResponse(w, resp)
+}
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package ingester
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/test"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/ingester/client"
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+func TestIngester_CostAttributionHandler(t *testing.T) {
+	ctx := context.Background()
+	cfg := defaultIngesterTestConfig(t)
+	cfg.ActiveSeriesMetrics.Enabled = true
+
+	limits := defaultLimitsTestConfig()
+	limits.CostAttributionLabel = "team"
+	overrides, err := validation.NewOverrides(limits, nil)
+	require.NoError(t, err)
+
+	i, err := prepareIngesterWithBlockStorageAndOverrides(t, cfg, overrides, "", "", nil)
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(ctx, i))
+	defer services.StopAndAwaitTerminated(ctx, i) //nolint:errcheck
+
+	// Wait until it's healthy
+	test.Poll(t, 1*time.Second, 1, func() interface{} {
+		return i.lifecycler.HealthyInstancesCount()
+	})
+
+	request := func(tenantID string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/ingester/cost_attribution", nil)
+		require.NoError(t, err)
+
+		i.CostAttributionHandler(rec, req.WithContext(user.InjectOrgID(req.Context(), tenantID)))
+		return rec
+	}
+
+	costAttribution := func(tenantID string) *client.CostAttributionResponse {
+		resp, err := i.CostAttribution(user.InjectOrgID(ctx, tenantID), &client.CostAttributionRequest{})
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("tenant without TSDB", func(t *testing.T) {
+		require.Equal(t, http.StatusNotFound, request(userID).Code)
+		require.Equal(t, &client.CostAttributionResponse{}, costAttribution(userID))
+	})
+
+	pushWithUser(t, i, [][]mimirpb.LabelAdapter{
+		{{Name: labels.MetricName, Value: "test_metric"}, {Name: "team", Value: "b"}},
+		{{Name: labels.MetricName, Value: "test_metric"}, {Name: "team", Value: "a"}},
+		{{Name: labels.MetricName, Value: "other_metric"}, {Name: "team", Value: "a"}},
+	}, userID, func(lbls []mimirpb.LabelAdapter, t time.Time) *mimirpb.WriteRequest {
+		return mimirpb.ToWriteRequest([][]mimirpb.LabelAdapter{lbls}, []mimirpb.Sample{{Value: 1, TimestampMs: t.UnixMilli()}}, nil, nil, mimirpb.API)
+	})
+
+	t.Run("tenant with cost attribution", func(t *testing.T) {
+		rec := request(userID)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.JSONEq(t, `{"label":"team","values":[{"value":"a","active_series":2},{"value":"b","active_series":1}]}`, rec.Body.String())
+
+		resp := costAttribution(userID)
+		require.Equal(t, "team", resp.Label)
+		require.ElementsMatch(t, []client.CostAttributionValue{{Value: "a", ActiveSeries: 2}, {Value: "b", ActiveSeries: 1}}, resp.Values)
+	})
+
+	t.Run("tenant with cost attribution disabled", func(t *testing.T) {
+		limits := defaultLimitsTestConfig()
+		i.limits, err = validation.NewOverrides(limits, nil)
+		require.NoError(t, err)
+		i.updateActiveSeries(time.Now())
+
+		require.Equal(t, http.StatusNotFound, request(userID).Code)
+		require.Equal(t, &client.CostAttributionResponse{}, costAttribution(userID))
+	})
+}
@@ -174,7 +174,8 @@ type Config struct {
 	IngesterPartitionRing PartitionRingConfig `yaml:"partition_ring" category:"experimental" doc:"hidden"`
 
 	// Config for metadata purging.
-	MetadataRetainPeriod time.Duration `yaml:"metadata_retain_period" category:"advanced"`
+	MetadataRetainPeriod       time.Duration `yaml:"metadata_retain_period" category:"advanced"`
+	MetadataPersistenceEnabled bool          `yaml:"metadata_persistence_enabled" category:"experimental"`
 
 	RateUpdatePeriod time.Duration `yaml:"rate_update_period" category:"advanced"`
 
@@ -218,6 +219,7 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet, logger log.Logger) {
 	cfg.ActiveSeriesMetrics.RegisterFlags(f)
 
 	f.DurationVar(&cfg.MetadataRetainPeriod, "ingester.metadata-retain-period", 10*time.Minute, "Period at which metadata we have not seen will remain in memory before being deleted.")
+	f.BoolVar(&cfg.MetadataPersistenceEnabled, "ingester.metadata-persistence-enabled", false, "If enabled, the metric metadata of each tenant is periodically persisted to the TSDB directory of the tenant and restored on startup, so that it survives the ingester restarts.")
 	f.DurationVar(&cfg.RateUpdatePeriod, "ingester.rate-update-period", 15*time.Second, "Period with which to update the per-tenant ingestion rates.")
 	f.BoolVar(&cfg.StreamChunksWhenUsingBlocks, "ingester.stream-chunks-when-using-blocks", true, "Stream chunks from ingesters to queriers.")
 	f.DurationVar(&cfg.TSDBConfigUpdatePeriod, "ingester.tsdb-config-update-period", 15*time.Second, "Period with which to update the per-tenant TSDB configuration.")
@@ -437,7 +439,7 @@ func New(cfg Config, limits *validation.Overrides, ingestersRing ring.ReadRing,
 		// We use the ingester instance ID as consumer group. This means that we have N consumer groups
 		// where N is the total number of ingesters. Each ingester is part of their own consumer group
 		// so that they all replay the owned partition with no gaps.
-		i.ingestReader, err = ingest.NewPartitionReaderForPusher(kafkaCfg, i.ingestPartitionID, cfg.IngesterRing.InstanceID, i, log.With(logger, "component", "ingest_reader"), registerer)
+		i.ingestReader, err = ingest.NewPartitionReaderForPusher(kafkaCfg, ingestCfg.DeadLetterConfig, i.ingestPartitionID, cfg.IngesterRing.InstanceID, i, log.With(logger, "component", "ingest_reader"), registerer)
 		if err != nil {
 			return nil, errors.Wrap(err, "creating ingest storage reader")
 		}
@@ -617,6 +619,10 @@ func (i *Ingester) stopping(_ error) error {
 		level.Warn(i.logger).Log("msg", "failed to remove shutdown marker", "path", shutdownMarkerPath, "err", err)
 	}
 
+	if i.cfg.MetadataPersistenceEnabled {
+		i.persistUserMetricsMetadata()
+	}
+
 	if !i.cfg.BlocksStorageConfig.TSDB.KeepUserTSDBOpenOnShutdown {
 		i.closeAllTSDB()
 	}
@@ -671,6 +677,9 @@ func (i *Ingester) updateLoop(ctx context.Context) error {
 		select {
 		case <-metadataPurgeTicker.C:
 			i.purgeUserMetricsMetadata()
+			if i.cfg.MetadataPersistenceEnabled {
+				i.persistUserMetricsMetadata()
+			}
 		case <-ingestionRateTicker.C:
 			i.ingestionRate.Tick()
 		case <-rateUpdateTicker.C:
@@ -701,6 +710,12 @@ func (i *Ingester) replaceMatchers(asm *activeseries.Matchers, userDB *userTSDB,
 	userDB.activeSeries.ReloadMatchers(asm, now)
 }
 
+func (i *Ingester) replaceCostAttribution(ca *activeseries.CostAttribution, userDB *userTSDB, now time.Time) {
+	i.metrics.deletePerUserCostAttributionMetrics(userDB.userID)
+	userDB.attributedValues = nil
+	userDB.activeSeries.ReloadCostAttribution(ca, now)
+}
+
 func (i *Ingester) updateActiveSeries(now time.Time) {
 	for _, userID := range i.getTSDBUsers() {
 		userDB := i.getTSDB(userID)
@@ -712,6 +727,11 @@ func (i *Ingester) updateActiveSeries(now time.Time) {
 		if newMatchersConfig.String() != userDB.activeSeries.CurrentConfig().String() {
 			i.replaceMatchers(activeseries.NewMatchers(newMatchersConfig), userDB, now)
 		}
+		currentCostAttribution := userDB.activeSeries.CurrentCostAttribution()
+		newCostAttributionLabel, newMaxCostAttribution := i.limits.CostAttributionLabel(userID), i.limits.MaxCostAttributionPerUser(userID)
+		if newCostAttributionLabel != currentCostAttribution.Label() || (newCostAttributionLabel != "" && newMaxCostAttribution != currentCostAttribution.MaxValues()) {
+			i.replaceCostAttribution(activeseries.NewCostAttribution(newCostAttributionLabel, newMaxCostAttribution), userDB, now)
+		}
 		valid := userDB.activeSeries.Purge(now)
 		if !valid {
 			// Active series config has been reloaded, exposing loading metric until MetricsIdleTimeout passes.
@@ -753,8 +773,32 @@ func (i *Ingester) updateActiveSeries(now time.Time) {
 					i.metrics.activeNativeHistogramBucketsCustomTrackersPerUser.DeleteLabelValues(userID, name)
 				}
 			}
+
+			i.updateAttributedActiveSeries(userDB)
+		}
+	}
+}
+
+// updateAttributedActiveSeries updates the attributed active series metric of the tenant, removing the values
+// without active series anymore from the attributed metrics. The samples are only attributed to the values of
+// active series, so the values without active series don't receive samples anymore.
+func (i *Ingester) updateAttributedActiveSeries(userDB *userTSDB) {
+	activeByAttribution := userDB.activeSeries.ActiveByAttribution()
+
+	for value := range userDB.attributedValues {
+		if _, ok := activeByAttribution[value]; !ok {
+			i.metrics.attributedActiveSeries.DeleteLabelValues(userDB.userID, value)
+			i.metrics.attributedReceivedSamplesTotal.DeleteLabelValues(userDB.userID, value)
+			delete(userDB.attributedValues, value)
 		}
 	}
+	for value, active := range activeByAttribution {
+		if userDB.attributedValues == nil {
+			userDB.attributedValues = map[string]struct{}{}
+		}
+		userDB.attributedValues[value] = struct{}{}
+		i.metrics.attributedActiveSeries.WithLabelValues(userDB.userID, value).Set(float64(active))
+	}
 }
 
 // updateUsageStats updated some anonymous usage statistics tracked by the ingester.
@@ -885,6 +929,9 @@ type pushStats struct {
 	newValueForTimestampCount int
 	perUserSeriesLimitCount   int
 	perMetricSeriesLimitCount int
+
+	// Number of succeeded samples by cost attribution value. Nil if the cost attribution is disabled.
+	attributedSamples map[string]int
 }
 
 // StartPushRequest checks if ingester can start push request, and increments relevant counters.
@@ -1108,6 +1155,9 @@ func (i *Ingester) updateMetricsFromPushStats(userID string, group string, stats
 	if stats.perMetricSeriesLimitCount > 0 {
 		discarded.perMetricSeriesLimit.WithLabelValues(userID, group).Add(float64(stats.perMetricSeriesLimitCount))
 	}
+	for attribution, count := range stats.attributedSamples {
+		i.metrics.attributedReceivedSamplesTotal.WithLabelValues(userID, attribution).Add(float64(count))
+	}
 	if stats.succeededSamplesCount > 0 {
 		i.ingestionRate.Add(int64(stats.succeededSamplesCount))
 
@@ -1347,7 +1397,12 @@ func (i *Ingester) pushSamplesToAppender(userID string, timeseries []mimirpb.Pre
 		}
 
 		if activeSeries != nil && stats.succeededSamplesCount > oldSucceededSamplesCount {
-			activeSeries.UpdateSeries(nonCopiedLabels, ref, startAppend, numNativeHistogramBuckets)
+			if attribution, ok := activeSeries.UpdateSeries(nonCopiedLabels, ref, startAppend, numNativeHistogramBuckets); ok {
+				if stats.attributedSamples == nil {
+					stats.attributedSamples = map[string]int{}
+				}
+				stats.attributedSamples[attribution] += stats.succeededSamplesCount - oldSucceededSamplesCount
+			}
 		}
 
 		if len(ts.Exemplars) > 0 && i.limits.MaxGlobalExemplarsPerUser(userID) > 0 {
@@ -2396,10 +2451,11 @@ func (i *Ingester) createTSDB(userID string, walReplayConcurrency int) (*userTSD
 
 	blockRanges := i.cfg.BlocksStorageConfig.TSDB.BlockRanges.ToMilliseconds()
 	matchersConfig := i.limits.ActiveSeriesCustomTrackersConfig(userID)
+	costAttribution := activeseries.NewCostAttribution(i.limits.CostAttributionLabel(userID), i.limits.MaxCostAttributionPerUser(userID))
 
 	userDB := &userTSDB{
 		userID:                  userID,
-		activeSeries:            activeseries.NewActiveSeries(activeseries.NewMatchers(matchersConfig), i.cfg.ActiveSeriesMetrics.IdleTimeout),
+		activeSeries:            activeseries.NewActiveSeries(activeseries.NewMatchers(matchersConfig), costAttribution, i.cfg.ActiveSeriesMetrics.IdleTimeout),
 		seriesInMetric:          newMetricCounter(i.limiter, i.cfg.getIgnoreSeriesLimitForMetricNamesMap()),
 		ingestedAPISamples:      util_math.NewEWMARate(0.2, i.cfg.RateUpdatePeriod),
 		ingestedRuleSamples:     util_math.NewEWMARate(0.2, i.cfg.RateUpdatePeriod),
@@ -2486,8 +2542,30 @@ func (i *Ingester) createTSDB(userID string, walReplayConcurrency int) (*userTSD
 	}
 	userDB.setLastUpdate(lastUpdateTime)
 
+	// Restore the metric metadata persisted before the last shutdown.
+	if i.cfg.MetadataPersistenceEnabled {
+		if err := i.getOrCreateUserMetadata(userID).readFromDir(udir); err != nil {
+			level.Warn(userLogger).Log("msg", "failed to restore the persisted metric metadata", "err", err)
+		}
+	}
+
 	// Create a new shipper for this database
 	if i.cfg.BlocksStorageConfig.TSDB.IsBlocksShippingEnabled() {
+		var exemplars storage.ExemplarQueryable
+		if i.cfg.BlocksStorageConfig.TSDB.ShipExemplarsEnabled {
+			exemplars = db
+		}
+
+		var metricMetadata func() []mimirpb.MetricMetadata
+		if i.cfg.BlocksStorageConfig.TSDB.ShipMetricMetadataEnabled {
+			metricMetadata = func() []mimirpb.MetricMetadata {
+				if userMetadata := i.getUserMetadata(userID); userMetadata != nil {
+					return userMetadata.allMetadata()
+				}
+				return nil
+			}
+		}
+
 		userDB.shipper = newShipper(
 			userLogger,
 			i.limits,
@@ -2496,6 +2574,8 @@ func (i *Ingester) createTSDB(userID string, walReplayConcurrency int) (*userTSD
 			udir,
 			bucket.NewUserBucketClient(userID, i.bucket, i.limits),
 			block.ReceiveSource,
+			exemplars,
+			metricMetadata,
 		)
 
 		// Initialise the shipper blocks cache.
@@ -3603,6 +3683,21 @@ func (i *Ingester) purgeUserMetricsMetadata() {
 	}
 }
 
+// persistUserMetricsMetadata persists the metric metadata of each tenant with an open TSDB to the TSDB directory
+// of the tenant.
+func (i *Ingester) persistUserMetricsMetadata() {
+	for _, userID := range i.getUsersWithMetadata() {
+		metadata := i.getUserMetadata(userID)
+		if metadata == nil || i.getTSDB(userID) == nil {
+			continue
+		}
+
+		if err := metadata.writeToDir(i.cfg.BlocksStorageConfig.TSDB.BlocksDir(userID)); err != nil {
+			level.Warn(i.logger).Log("msg", "failed to persist the metric metadata", "user", userID, "err", err)
+		}
+	}
+}
+
 // MetricsMetadata returns all the metrics metadata of a user.
 func (i *Ingester) MetricsMetadata(ctx context.Context, req *client.MetricsMetadataRequest) (resp *client.MetricsMetadataResponse, err error) {
 	defer func() { err = i.mapReadErrorToErrorWithStatus(err) }()
@@ -3630,6 +3725,11 @@ func (i *Ingester) CheckReady(ctx context.Context) error {
 	if err := i.checkAvailable(); err != nil {
 		return fmt.Errorf("ingester not ready: %v", err)
 	}
+	if i.ingestReader != nil {
+		if err := i.ingestReader.CheckReady(ctx); err != nil {
+			return fmt.Errorf("ingester not ready: %v", err)
+		}
+	}
 	return i.lifecycler.CheckReady(ctx)
 }
 
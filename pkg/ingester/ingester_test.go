@@ -7428,6 +7428,51 @@ func TestIngesterUserLimitExceeded(t *testing.T) {
 
 }
 
+func TestIngester_MetadataPersistence(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		t.Run(fmt.Sprintf("persistence enabled: %t", enabled), func(t *testing.T) {
+			// create a data dir that survives an ingester restart
+			dataDir := t.TempDir()
+
+			newIngester := func() *Ingester {
+				cfg := defaultIngesterTestConfig(t)
+				cfg.MetadataPersistenceEnabled = enabled
+				ing, err := prepareIngesterWithBlocksStorageAndLimits(t, cfg, defaultLimitsTestConfig(), dataDir, nil)
+				require.NoError(t, err)
+				require.NoError(t, services.StartAndAwaitRunning(context.Background(), ing))
+
+				// Wait until it's healthy
+				test.Poll(t, time.Second, 1, func() interface{} {
+					return ing.lifecycler.HealthyInstancesCount()
+				})
+
+				return ing
+			}
+
+			ing := newIngester()
+
+			ctx := user.InjectOrgID(context.Background(), "1")
+			series := []mimirpb.LabelAdapter{{Name: labels.MetricName, Value: "testmetric"}}
+			metadata := &mimirpb.MetricMetadata{MetricFamilyName: "testmetric", Help: "a help for testmetric", Type: mimirpb.COUNTER}
+			_, err := ing.Push(ctx, mimirpb.ToWriteRequest([][]mimirpb.LabelAdapter{series}, []mimirpb.Sample{{TimestampMs: 1, Value: 1}}, nil, []*mimirpb.MetricMetadata{metadata}, mimirpb.API))
+			require.NoError(t, err)
+
+			require.NoError(t, services.StopAndAwaitTerminated(context.Background(), ing))
+			ing = newIngester()
+			defer services.StopAndAwaitTerminated(context.Background(), ing) //nolint:errcheck
+
+			// The metadata survives the restart only if the persistence is enabled.
+			res, err := ing.MetricsMetadata(ctx, client.DefaultMetricsMetadataRequest())
+			require.NoError(t, err)
+			if enabled {
+				assert.Equal(t, []*mimirpb.MetricMetadata{metadata}, res.Metadata)
+			} else {
+				assert.Empty(t, res.Metadata)
+			}
+		})
+	}
+}
+
 func TestIngesterMetricLimitExceeded(t *testing.T) {
 	limits := defaultLimitsTestConfig()
 	limits.MaxGlobalSeriesPerMetric = 1
@@ -8478,6 +8523,105 @@ func pushWithUser(t *testing.T, ingester *Ingester, labelsToPush [][]mimirpb.Lab
 	}
 }
 
+func TestIngesterActiveSeriesCostAttribution(t *testing.T) {
+	const userID = "test_user"
+
+	metricNames := []string{
+		"cortex_ingester_attributed_active_series",
+		"cortex_ingester_attributed_received_samples_total",
+	}
+
+	registry := prometheus.NewRegistry()
+	cfg := defaultIngesterTestConfig(t)
+	cfg.ActiveSeriesMetrics.Enabled = true
+
+	limits := defaultLimitsTestConfig()
+	limits.CostAttributionLabel = "team"
+	limits.MaxCostAttributionPerUser = 2
+	overrides, err := validation.NewOverrides(limits, nil)
+	require.NoError(t, err)
+
+	ing, err := prepareIngesterWithBlockStorageAndOverrides(t, cfg, overrides, "", "", registry)
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), ing))
+	defer services.StopAndAwaitTerminated(context.Background(), ing) //nolint:errcheck
+
+	// Wait until the ingester is healthy
+	test.Poll(t, 100*time.Millisecond, 1, func() interface{} {
+		return ing.lifecycler.HealthyInstancesCount()
+	})
+
+	req := func(lbls []mimirpb.LabelAdapter, t time.Time) *mimirpb.WriteRequest {
+		return mimirpb.ToWriteRequest(
+			[][]mimirpb.LabelAdapter{lbls, lbls},
+			[]mimirpb.Sample{{Value: 1, TimestampMs: t.UnixMilli()}, {Value: 2, TimestampMs: t.UnixMilli() + 1}},
+			nil,
+			nil,
+			mimirpb.API,
+		)
+	}
+	pushWithUser(t, ing, [][]mimirpb.LabelAdapter{
+		{{Name: labels.MetricName, Value: "test_metric"}, {Name: "team", Value: "a"}, {Name: "service", Value: "x"}},
+		{{Name: labels.MetricName, Value: "test_metric"}, {Name: "team", Value: "b"}, {Name: "service", Value: "x"}},
+		{{Name: labels.MetricName, Value: "test_metric"}, {Name: "team", Value: "a"}, {Name: "service", Value: "y"}},
+		{{Name: labels.MetricName, Value: "test_metric"}, {Name: "team", Value: "c"}, {Name: "service", Value: "y"}},
+	}, userID, req)
+
+	ing.updateActiveSeries(time.Now())
+
+	// The series of the team "c" exceed the max number of values.
+	require.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(`
+		# HELP cortex_ingester_attributed_active_series Number of currently active series per user and value of the cost attribution label.
+		# TYPE cortex_ingester_attributed_active_series gauge
+		cortex_ingester_attributed_active_series{attribution="__overflow__",user="test_user"} 1
+		cortex_ingester_attributed_active_series{attribution="a",user="test_user"} 2
+		cortex_ingester_attributed_active_series{attribution="b",user="test_user"} 1
+		# HELP cortex_ingester_attributed_received_samples_total The total number of samples received and successfully appended per user and value of the cost attribution label.
+		# TYPE cortex_ingester_attributed_received_samples_total counter
+		cortex_ingester_attributed_received_samples_total{attribution="__overflow__",user="test_user"} 2
+		cortex_ingester_attributed_received_samples_total{attribution="a",user="test_user"} 4
+		cortex_ingester_attributed_received_samples_total{attribution="b",user="test_user"} 2
+	`), metricNames...))
+
+	// Changing the cost attribution label resets the attributed metrics.
+	limits.CostAttributionLabel = "service"
+	ing.limits, err = validation.NewOverrides(limits, nil)
+	require.NoError(t, err)
+	// Reload the config as if it happened an idle timeout ago, so that the next update is valid.
+	ing.updateActiveSeries(time.Now().Add(-cfg.ActiveSeriesMetrics.IdleTimeout))
+	require.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(""), metricNames...))
+
+	pushWithUser(t, ing, [][]mimirpb.LabelAdapter{
+		{{Name: labels.MetricName, Value: "test_metric"}, {Name: "team", Value: "a"}, {Name: "service", Value: "x"}},
+	}, userID, req)
+
+	ing.updateActiveSeries(time.Now())
+	require.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(`
+		# HELP cortex_ingester_attributed_active_series Number of currently active series per user and value of the cost attribution label.
+		# TYPE cortex_ingester_attributed_active_series gauge
+		cortex_ingester_attributed_active_series{attribution="x",user="test_user"} 1
+		# HELP cortex_ingester_attributed_received_samples_total The total number of samples received and successfully appended per user and value of the cost attribution label.
+		# TYPE cortex_ingester_attributed_received_samples_total counter
+		cortex_ingester_attributed_received_samples_total{attribution="x",user="test_user"} 2
+	`), metricNames...))
+
+	// The values without active series anymore are removed from the attributed metrics.
+	lastActiveX := time.Now()
+	pushWithUser(t, ing, [][]mimirpb.LabelAdapter{
+		{{Name: labels.MetricName, Value: "test_metric"}, {Name: "team", Value: "a"}, {Name: "service", Value: "y"}},
+	}, userID, req)
+
+	ing.updateActiveSeries(lastActiveX.Add(cfg.ActiveSeriesMetrics.IdleTimeout))
+	require.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(`
+		# HELP cortex_ingester_attributed_active_series Number of currently active series per user and value of the cost attribution label.
+		# TYPE cortex_ingester_attributed_active_series gauge
+		cortex_ingester_attributed_active_series{attribution="y",user="test_user"} 1
+		# HELP cortex_ingester_attributed_received_samples_total The total number of samples received and successfully appended per user and value of the cost attribution label.
+		# TYPE cortex_ingester_attributed_received_samples_total counter
+		cortex_ingester_attributed_received_samples_total{attribution="y",user="test_user"} 2
+	`), metricNames...))
+}
+
 func TestGetIgnoreSeriesLimitForMetricNamesMap(t *testing.T) {
 	cfg := Config{}
 
@@ -44,6 +44,10 @@ type ingesterMetrics struct {
 	activeNativeHistogramBucketsPerUser               *prometheus.GaugeVec
 	activeNativeHistogramBucketsCustomTrackersPerUser *prometheus.GaugeVec
 
+	// Cost attribution
+	attributedActiveSeries         *prometheus.GaugeVec
+	attributedReceivedSamplesTotal *prometheus.CounterVec
+
 	// Owned series
 	ownedSeriesPerUser *prometheus.GaugeVec
 
@@ -335,6 +339,18 @@ func newIngesterMetrics(
 			Help: "Number of currently active native histogram buckets matching a pre-configured label matchers per user.",
 		}, []string{"user", "name"}),
 
+		// Not registered automatically, but only if activeSeriesEnabled is true.
+		attributedActiveSeries: promauto.With(activeSeriesReg).NewGaugeVec(prometheus.GaugeOpts{
+			Name: "cortex_ingester_attributed_active_series",
+			Help: "Number of currently active series per user and value of the cost attribution label.",
+		}, []string{"user", "attribution"}),
+
+		// Not registered automatically, but only if activeSeriesEnabled is true.
+		attributedReceivedSamplesTotal: promauto.With(activeSeriesReg).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_ingester_attributed_received_samples_total",
+			Help: "The total number of samples received and successfully appended per user and value of the cost attribution label.",
+		}, []string{"user", "attribution"}),
+
 		compactionsTriggered: promauto.With(r).NewCounter(prometheus.CounterOpts{
 			Name: "cortex_ingester_tsdb_compactions_triggered_total",
 			Help: "Total number of triggered compactions.",
@@ -401,6 +417,8 @@ func (m *ingesterMetrics) deletePerUserMetrics(userID string) {
 
 	m.maxLocalSeriesPerUser.DeleteLabelValues(userID)
 	m.ownedSeriesPerUser.DeleteLabelValues(userID)
+
+	m.attributedReceivedSamplesTotal.DeletePartialMatch(filter)
 }
 
 func (m *ingesterMetrics) deletePerGroupMetricsForUser(userID, group string) {
@@ -417,6 +435,13 @@ func (m *ingesterMetrics) deletePerUserCustomTrackerMetrics(userID string, custo
 		m.activeSeriesCustomTrackersPerUserNativeHistograms.DeleteLabelValues(userID, name)
 		m.activeNativeHistogramBucketsCustomTrackersPerUser.DeleteLabelValues(userID, name)
 	}
+	m.attributedActiveSeries.DeletePartialMatch(prometheus.Labels{"user": userID})
+}
+
+func (m *ingesterMetrics) deletePerUserCostAttributionMetrics(userID string) {
+	filter := prometheus.Labels{"user": userID}
+	m.attributedActiveSeries.DeletePartialMatch(filter)
+	m.attributedReceivedSamplesTotal.DeletePartialMatch(filter)
 }
 
 type discardedMetrics struct {
@@ -12,6 +12,7 @@ import (
 	"path"
 	"path/filepath"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/go-kit/log"
@@ -22,9 +23,13 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
 	"github.com/prometheus/prometheus/tsdb/fileutil"
+	"github.com/prometheus/prometheus/tsdb/index"
 	"github.com/thanos-io/objstore"
 
+	"github.com/grafana/mimir/pkg/mimirpb"
 	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
 	"github.com/grafana/mimir/pkg/storage/tsdb/block"
 )
@@ -69,11 +74,19 @@ type shipper struct {
 	metrics     *shipperMetrics
 	bucket      objstore.Bucket
 	source      block.SourceType
+
+	// exemplars is optional, and when set the exemplars within the time range of each block are shipped along with the block.
+	exemplars storage.ExemplarQueryable
+
+	// metricMetadata is optional, and when set the metric metadata of the metrics within each block is shipped along with the block.
+	metricMetadata func() []mimirpb.MetricMetadata
 }
 
 // newShipper creates a new uploader that detects new TSDB blocks in dir and uploads them to
 // remote if necessary. It attaches the Thanos metadata section in each meta JSON file.
 // If uploadCompacted is enabled, it also uploads compacted blocks which are already in filesystem.
+// If exemplars is not nil, the exemplars within the time range of each block are shipped along with the block.
+// If metricMetadata is not nil, the metric metadata of the metrics within each block is shipped along with the block.
 func newShipper(
 	logger log.Logger,
 	cfgProvider ShipperConfigProvider,
@@ -82,19 +95,23 @@ func newShipper(
 	dir string,
 	bucket objstore.Bucket,
 	source block.SourceType,
+	exemplars storage.ExemplarQueryable,
+	metricMetadata func() []mimirpb.MetricMetadata,
 ) *shipper {
 	if logger == nil {
 		logger = log.NewNopLogger()
 	}
 
 	return &shipper{
-		logger:      logger,
-		cfgProvider: cfgProvider,
-		userID:      userID,
-		dir:         dir,
-		bucket:      bucket,
-		metrics:     metrics,
-		source:      source,
+		logger:         logger,
+		cfgProvider:    cfgProvider,
+		userID:         userID,
+		dir:            dir,
+		bucket:         bucket,
+		metrics:        metrics,
+		source:         source,
+		exemplars:      exemplars,
+		metricMetadata: metricMetadata,
 	}
 }
 
@@ -197,10 +214,80 @@ func (s *shipper) upload(ctx context.Context, meta *block.Meta) error {
 		meta.Thanos.Labels[mimir_tsdb.OutOfOrderExternalLabel] = mimir_tsdb.OutOfOrderExternalLabelValue
 	}
 
+	// The exemplars of the out-of-order blocks are shipped along with the in-order blocks overlapping them.
+	if s.exemplars != nil && !meta.Compaction.FromOutOfOrder() {
+		if err := s.writeExemplars(ctx, blockDir, meta); err != nil {
+			// The block is shipped anyway, because its samples are more valuable than its exemplars.
+			level.Warn(s.logger).Log("msg", "failed to write the exemplars of the block, the block is shipped without exemplars", "block", meta.ULID, "err", err)
+		}
+	}
+
+	if s.metricMetadata != nil {
+		if err := s.writeMetricMetadata(ctx, blockDir); err != nil {
+			level.Warn(s.logger).Log("msg", "failed to write the metric metadata of the block, the block is shipped without metric metadata", "block", meta.ULID, "err", err)
+		}
+	}
+
 	// Upload block with custom metadata.
 	return block.Upload(ctx, s.logger, s.bucket, blockDir, meta)
 }
 
+// writeExemplars writes the exemplars within the time range of the block to the exemplars file of the block.
+func (s *shipper) writeExemplars(ctx context.Context, blockDir string, meta *block.Meta) error {
+	q, err := s.exemplars.ExemplarQuerier(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The block max time is exclusive, while the exemplars time range is inclusive.
+	series, err := q.Select(meta.MinTime, meta.MaxTime-1, []*labels.Matcher{labels.MustNewMatcher(labels.MatchRegexp, labels.MetricName, ".+")})
+	if err != nil {
+		return errors.Wrap(err, "select exemplars")
+	}
+	return block.WriteExemplarsFile(s.logger, blockDir, series)
+}
+
+// writeMetricMetadata writes the metric metadata of the metrics within the block to the metric metadata file of the block.
+func (s *shipper) writeMetricMetadata(ctx context.Context, blockDir string) error {
+	r, err := index.NewFileReader(filepath.Join(blockDir, block.IndexFilename))
+	if err != nil {
+		return errors.Wrap(err, "open index")
+	}
+	defer runutil.CloseWithLogOnErr(s.logger, r, "close index reader")
+
+	names, err := r.LabelValues(ctx, labels.MetricName)
+	if err != nil {
+		return errors.Wrap(err, "read metric names")
+	}
+	return block.WriteMetricMetadataFile(s.logger, blockDir, filterMetricMetadataByMetricNames(s.metricMetadata(), names))
+}
+
+// metricMetadataSuffixes are the suffixes of the series names of the metric families whose series aren't named
+// after the metric family.
+var metricMetadataSuffixes = []string{"_bucket", "_sum", "_count", "_total", "_created", "_info"}
+
+// filterMetricMetadataByMetricNames returns the metric metadata of the metric families with at least one series
+// named after one of the metric names.
+func filterMetricMetadataByMetricNames(md []mimirpb.MetricMetadata, names []string) []mimirpb.MetricMetadata {
+	families := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		families[name] = struct{}{}
+		for _, suffix := range metricMetadataSuffixes {
+			if family, ok := strings.CutSuffix(name, suffix); ok {
+				families[family] = struct{}{}
+			}
+		}
+	}
+
+	var result []mimirpb.MetricMetadata
+	for _, m := range md {
+		if _, ok := families[m.MetricFamilyName]; ok {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
 // blockMetasFromOldest returns the block meta of each block found in dir
 // sorted by minTime asc.
 func (s *shipper) blockMetasFromOldest() (metas []*block.Meta, _ error) {
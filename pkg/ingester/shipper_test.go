@@ -13,6 +13,7 @@ import (
 	"path"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"testing"
 	"time"
 
@@ -20,10 +21,13 @@ import (
 	"github.com/grafana/dskit/concurrency"
 	"github.com/oklog/ulid"
 	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/tsdb"
 	"github.com/stretchr/testify/require"
 	"github.com/thanos-io/objstore"
 
+	"github.com/grafana/mimir/pkg/mimirpb"
 	"github.com/grafana/mimir/pkg/storage/bucket/filesystem"
 	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
 	"github.com/grafana/mimir/pkg/storage/tsdb/block"
@@ -51,7 +55,7 @@ func TestShipper(t *testing.T) {
 	logger := log.NewLogfmtLogger(logs)
 	overrides, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
 	require.NoError(t, err)
-	s := newShipper(logger, overrides, "", newShipperMetrics(nil), blocksDir, bkt, block.TestSource)
+	s := newShipper(logger, overrides, "", newShipperMetrics(nil), blocksDir, bkt, block.TestSource, nil, nil)
 
 	t.Run("no shipper file yet", func(t *testing.T) {
 		// No shipper file = nothing is reported as shipped.
@@ -193,7 +197,7 @@ func TestShipper_DeceivingUploadErrors(t *testing.T) {
 	logger := log.NewLogfmtLogger(os.Stderr)
 	overrides, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
 	require.NoError(t, err)
-	s := newShipper(logger, overrides, "", newShipperMetrics(nil), blocksDir, bkt, block.TestSource)
+	s := newShipper(logger, overrides, "", newShipperMetrics(nil), blocksDir, bkt, block.TestSource, nil, nil)
 
 	// Create and upload a block
 	id1 := ulid.MustNew(1, nil)
@@ -259,7 +263,7 @@ func TestIterBlockMetas(t *testing.T) {
 	}.WriteToDir(log.NewNopLogger(), path.Join(dir, id3.String())))
 	overrides, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
 	require.NoError(t, err)
-	shipper := newShipper(nil, overrides, "", newShipperMetrics(nil), dir, nil, block.TestSource)
+	shipper := newShipper(nil, overrides, "", newShipperMetrics(nil), dir, nil, block.TestSource, nil, nil)
 	metas, err := shipper.blockMetasFromOldest()
 	require.NoError(t, err)
 	require.Equal(t, sort.SliceIsSorted(metas, func(i, j int) bool {
@@ -273,7 +277,7 @@ func TestShipperAddsSegmentFiles(t *testing.T) {
 	inmemory := objstore.NewInMemBucket()
 	overrides, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
 	require.NoError(t, err)
-	s := newShipper(nil, overrides, "", newShipperMetrics(nil), dir, inmemory, block.TestSource)
+	s := newShipper(nil, overrides, "", newShipperMetrics(nil), dir, inmemory, block.TestSource, nil, nil)
 
 	id := ulid.MustNew(1, nil)
 	blockDir := path.Join(dir, id.String())
@@ -306,6 +310,120 @@ func TestShipperAddsSegmentFiles(t *testing.T) {
 	require.Equal(t, []string{segmentFile}, meta.Thanos.SegmentFiles)
 }
 
+func TestShipper_Exemplars(t *testing.T) {
+	dir := t.TempDir()
+
+	inmemory := objstore.NewInMemBucket()
+	overrides, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
+	require.NoError(t, err)
+
+	exemplars, err := tsdb.NewCircularExemplarStorage(10, tsdb.NewExemplarMetrics(nil))
+	require.NoError(t, err)
+	series := labels.FromStrings("__name__", "metric", "job", "a")
+	for _, ts := range []int64{500, 1000, 1999, 2000} {
+		require.NoError(t, exemplars.AddExemplar(series, exemplar.Exemplar{Labels: labels.FromStrings("trace_id", strconv.FormatInt(ts, 10)), Value: float64(ts), Ts: ts}))
+	}
+
+	s := newShipper(nil, overrides, "", newShipperMetrics(nil), dir, inmemory, block.TestSource, exemplars, nil)
+
+	inOrderID := ulid.MustNew(1, nil)
+	createBlock(t, dir, inOrderID, block.Meta{
+		BlockMeta: tsdb.BlockMeta{
+			ULID:    inOrderID,
+			MinTime: 1000,
+			MaxTime: 2000,
+			Version: 1,
+			Stats:   tsdb.BlockStats{NumSamples: 100},
+		},
+	})
+	oooID := ulid.MustNew(2, nil)
+	createBlock(t, dir, oooID, metaWithOOOHint(block.Meta{
+		BlockMeta: tsdb.BlockMeta{
+			ULID:    oooID,
+			MinTime: 0,
+			MaxTime: 2000,
+			Version: 1,
+			Stats:   tsdb.BlockStats{NumSamples: 100},
+		},
+		Thanos: block.ThanosMeta{Labels: map[string]string{}},
+	}))
+
+	uploaded, err := s.Sync(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, uploaded)
+
+	// Only the exemplars within the time range of the in-order block are shipped along with it.
+	meta, err := block.DownloadMeta(context.Background(), log.NewNopLogger(), inmemory, inOrderID)
+	require.NoError(t, err)
+	require.True(t, block.HasExemplars(&meta))
+
+	actual, err := block.DownloadExemplars(context.Background(), log.NewNopLogger(), inmemory, inOrderID)
+	require.NoError(t, err)
+	require.Equal(t, []exemplar.QueryResult{{
+		SeriesLabels: series,
+		Exemplars: []exemplar.Exemplar{
+			{Labels: labels.FromStrings("trace_id", "1000"), Value: 1000, Ts: 1000},
+			{Labels: labels.FromStrings("trace_id", "1999"), Value: 1999, Ts: 1999},
+		},
+	}}, actual)
+
+	// The exemplars aren't shipped along with the out-of-order blocks.
+	meta, err = block.DownloadMeta(context.Background(), log.NewNopLogger(), inmemory, oooID)
+	require.NoError(t, err)
+	require.False(t, block.HasExemplars(&meta))
+}
+
+func TestShipper_MetricMetadata(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	inmemory := objstore.NewInMemBucket()
+	overrides, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
+	require.NoError(t, err)
+
+	requests := mimirpb.MetricMetadata{Type: mimirpb.COUNTER, MetricFamilyName: "requests", Help: "Total requests."}
+	duration := mimirpb.MetricMetadata{Type: mimirpb.HISTOGRAM, MetricFamilyName: "duration_seconds", Help: "Duration."}
+	other := mimirpb.MetricMetadata{Type: mimirpb.GAUGE, MetricFamilyName: "other", Help: "Not in the block."}
+
+	s := newShipper(nil, overrides, "", newShipperMetrics(nil), dir, inmemory, block.TestSource, nil, func() []mimirpb.MetricMetadata {
+		return []mimirpb.MetricMetadata{requests, duration, other}
+	})
+
+	id, err := block.CreateBlock(ctx, dir, []labels.Labels{
+		labels.FromStrings("__name__", "requests_total"),
+		labels.FromStrings("__name__", "duration_seconds_bucket", "le", "1"),
+		labels.FromStrings("__name__", "duration_seconds_count"),
+	}, 10, 0, 1000, labels.EmptyLabels())
+	require.NoError(t, err)
+
+	uploaded, err := s.Sync(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, uploaded)
+
+	// Only the metric metadata of the metrics within the block is shipped along with it.
+	meta, err := block.DownloadMeta(ctx, log.NewNopLogger(), inmemory, id)
+	require.NoError(t, err)
+	require.True(t, block.HasMetricMetadata(&meta))
+
+	actual, err := block.DownloadMetricMetadata(ctx, log.NewNopLogger(), inmemory, id)
+	require.NoError(t, err)
+	require.Equal(t, []mimirpb.MetricMetadata{duration, requests}, actual)
+}
+
+func TestFilterMetricMetadataByMetricNames(t *testing.T) {
+	md := []mimirpb.MetricMetadata{
+		{Type: mimirpb.GAUGE, MetricFamilyName: "up"},
+		{Type: mimirpb.COUNTER, MetricFamilyName: "requests"},
+		{Type: mimirpb.SUMMARY, MetricFamilyName: "latency"},
+		{Type: mimirpb.INFO, MetricFamilyName: "build"},
+		{Type: mimirpb.GAUGE, MetricFamilyName: "missing"},
+	}
+
+	actual := filterMetricMetadataByMetricNames(md, []string{"up", "requests_total", "latency_sum", "build_info", "missing_seconds"})
+	require.Equal(t, md[:4], actual)
+	require.Empty(t, filterMetricMetadataByMetricNames(md, nil))
+}
+
 func TestShipper_AddOOOLabel(t *testing.T) {
 	for _, tc := range []struct {
 		name                      string
@@ -417,7 +535,7 @@ func TestShipper_AddOOOLabel(t *testing.T) {
 			}
 			overrides, err := validation.NewOverrides(defaultLimitsTestConfig(), validation.NewMockTenantLimits(tenantLimits))
 			require.NoError(t, err)
-			s := newShipper(logger, overrides, "", newShipperMetrics(nil), blocksDir, bkt, block.TestSource)
+			s := newShipper(logger, overrides, "", newShipperMetrics(nil), blocksDir, bkt, block.TestSource, nil, nil)
 
 			createBlock(t, blocksDir, tc.meta.ULID, tc.meta)
 
@@ -6,13 +6,41 @@
 package ingester
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
 	"sync"
 	"time"
 
 	"github.com/grafana/mimir/pkg/ingester/client"
 	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/util/atomicfs"
 )
 
+const (
+	// userMetricsMetadataFilename is the file, in the TSDB directory of the tenant, persisting the metric metadata
+	// of the tenant across the ingester restarts.
+	userMetricsMetadataFilename = "metric_metadata.json"
+
+	// userMetricsMetadataFormatV1 is the only supported format of the metric metadata file.
+	userMetricsMetadataFormatV1 = 1
+)
+
+type userMetricsMetadataFile struct {
+	Version  int                       `json:"version"`
+	Metadata []persistedMetricMetadata `json:"metadata"`
+}
+
+type persistedMetricMetadata struct {
+	mimirpb.MetricMetadata
+
+	// LastUpdate is the last time, in milliseconds, the metadata has been received. It isn't restored, because
+	// the restored metadata is considered received at the restart.
+	LastUpdate int64 `json:"last_update"`
+}
+
 // userMetricsMetadata allows metric metadata of a tenant to be held by the ingester.
 // Metadata is kept as a set as it can come from multiple targets that Prometheus scrapes
 // with the same metric name.
@@ -126,6 +154,83 @@ func (mm *userMetricsMetadata) toClientMetadata(req *client.MetricsMetadataReque
 	return r
 }
 
+// allMetadata returns all the metric metadata of the tenant.
+func (mm *userMetricsMetadata) allMetadata() []mimirpb.MetricMetadata {
+	mm.mtx.RLock()
+	defer mm.mtx.RUnlock()
+
+	var r []mimirpb.MetricMetadata
+	for _, set := range mm.metricToMetadata {
+		for m := range set {
+			r = append(r, m)
+		}
+	}
+	return r
+}
+
+// writeToDir persists the metric metadata of the tenant to the metric metadata file in dir.
+func (mm *userMetricsMetadata) writeToDir(dir string) error {
+	file := userMetricsMetadataFile{Version: userMetricsMetadataFormatV1}
+
+	mm.mtx.RLock()
+	for _, set := range mm.metricToMetadata {
+		for m, lastUpdate := range set {
+			file.Metadata = append(file.Metadata, persistedMetricMetadata{MetricMetadata: m, LastUpdate: lastUpdate.UnixMilli()})
+		}
+	}
+	mm.mtx.RUnlock()
+
+	data, err := json.Marshal(file)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, userMetricsMetadataFilename)
+	return atomicfs.CreateFileAndMove(path+".tmp", path, bytes.NewReader(data))
+}
+
+// readFromDir restores the metric metadata persisted to the metric metadata file in dir, if any. The limits aren't
+// enforced, because the metadata has been accepted before being persisted. The restored metadata is considered
+// received now, otherwise it would be purged right after a restart lasting longer than the retain period, while
+// the ingester couldn't receive it again.
+func (mm *userMetricsMetadata) readFromDir(dir string) error {
+	data, err := os.ReadFile(filepath.Join(dir, userMetricsMetadataFilename))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var file userMetricsMetadataFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return err
+	}
+	if file.Version != userMetricsMetadataFormatV1 {
+		return fmt.Errorf("unexpected metric metadata format version %d", file.Version)
+	}
+
+	mm.mtx.Lock()
+	defer mm.mtx.Unlock()
+
+	now := time.Now()
+	for _, m := range file.Metadata {
+		set, ok := mm.metricToMetadata[m.MetricFamilyName]
+		if !ok {
+			set = metricMetadataSet{}
+			mm.metricToMetadata[m.MetricFamilyName] = set
+		}
+
+		if _, ok := set[m.MetricMetadata]; ok {
+			continue
+		}
+		mm.metrics.memMetadata.Inc()
+		mm.metrics.memMetadataCreatedTotal.WithLabelValues(mm.userID).Inc()
+		set[m.MetricMetadata] = now
+	}
+	return nil
+}
+
 type metricMetadataSet map[mimirpb.MetricMetadata]time.Time
 
 // If deadline is zero time, all metrics are purged.
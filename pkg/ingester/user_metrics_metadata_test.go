@@ -3,10 +3,13 @@
 package ingester
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -259,3 +262,57 @@ func TestUserMetricsMetadataRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestUserMetricsMetadataPersistence(t *testing.T) {
+	// Mock the ring
+	ring := &ringCountMock{}
+	ring.On("InstancesCount").Return(1)
+	ring.On("ZonesCount").Return(1)
+
+	limits, err := validation.NewOverrides(validation.Limits{MaxGlobalMetricsWithMetadataPerUser: 1}, nil)
+	require.NoError(t, err)
+	limiter := NewLimiter(limits, ring, 1, false)
+
+	newMetrics := func() *ingesterMetrics {
+		return newIngesterMetrics(prometheus.NewPedanticRegistry(), true, func() *InstanceLimits { return nil }, nil, nil, nil)
+	}
+
+	dir := t.TempDir()
+	mm := newMetadataMap(limiter, newMetrics(), newIngesterErrSamplers(0), "test")
+
+	// Restoring from a directory without the metric metadata file is a no-op.
+	require.NoError(t, mm.readFromDir(dir))
+	assert.Empty(t, mm.allMetadata())
+
+	inputMetadata := []mimirpb.MetricMetadata{
+		{Type: mimirpb.COUNTER, MetricFamilyName: "test_metric_1", Help: "foo"},
+		{Type: mimirpb.COUNTER, MetricFamilyName: "test_metric_1", Help: "bar", Unit: "seconds"},
+	}
+	for _, m := range inputMetadata {
+		require.NoError(t, mm.add(m.MetricFamilyName, &m))
+		// The metadata has been received long before the restart.
+		mm.metricToMetadata[m.MetricFamilyName][m] = time.Now().Add(-48 * time.Hour)
+	}
+	require.NoError(t, mm.writeToDir(dir))
+
+	// The limits aren't enforced on the restored metadata.
+	restored := newMetadataMap(limiter, newMetrics(), newIngesterErrSamplers(0), "test")
+	other := mimirpb.MetricMetadata{Type: mimirpb.GAUGE, MetricFamilyName: "test_metric_2", Help: "baz"}
+	require.NoError(t, restored.add(other.MetricFamilyName, &other))
+	require.NoError(t, restored.readFromDir(dir))
+	assert.ElementsMatch(t, append(inputMetadata, other), restored.allMetadata())
+	assert.Equal(t, 3.0, testutil.ToFloat64(restored.metrics.memMetadata))
+
+	// The restored metadata is considered received at the restart, so that it isn't purged before it can be
+	// received again.
+	restored.purge(time.Now().Add(-time.Hour))
+	assert.Len(t, restored.allMetadata(), 3)
+	restored.purge(time.Now().Add(time.Hour))
+	assert.Empty(t, restored.allMetadata())
+
+	t.Run("unexpected format version", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, userMetricsMetadataFilename), []byte(`{"version":2}`), 0o600))
+		require.EqualError(t, restored.readFromDir(dir), "unexpected metric metadata format version 2")
+	})
+}
@@ -92,6 +92,10 @@ type userTSDB struct {
 	seriesInMetric *metricCounter
 	limiter        *Limiter
 
+	// Cost attribution values exported in the attributed active series and received samples metrics. Only accessed
+	// by the ingester loop updating the active series metrics.
+	attributedValues map[string]struct{}
+
 	instanceSeriesCount *atomic.Int64 // Shared across all userTSDB instances created by ingester.
 	instanceLimitsFn    func() *InstanceLimits
 	instanceErrors      *prometheus.CounterVec
@@ -80,7 +80,7 @@ func (g *grpcInflightMethodLimiter) RPCCallStarting(ctx context.Context, methodN
 		httpMethod := getSingleMetadata(md, httpgrpc.MetadataMethod)
 		httpURL := getSingleMetadata(md, httpgrpc.MetadataURL)
 
-		if httpMethod == http.MethodPost && (strings.HasSuffix(httpURL, api.PrometheusPushEndpoint) || strings.HasSuffix(httpURL, api.OTLPPushEndpoint)) {
+		if httpMethod == http.MethodPost && (strings.HasSuffix(httpURL, api.PrometheusPushEndpoint) || strings.HasSuffix(httpURL, api.OTLPPushEndpoint) || strings.HasSuffix(httpURL, api.InfluxPushEndpoint)) {
 			dist := g.getDistributor()
 			if dist == nil {
 				return ctx, errNoDistributor
@@ -190,6 +190,20 @@ func TestGrpcInflightMethodLimiter(t *testing.T) {
 		require.Equal(t, int64(0), m.lastRequestSize)
 	})
 
+	t.Run("distributor push via httpgrpc, InfluxDB line protocol", func(t *testing.T) {
+		m := &mockDistributorReceiver{}
+		l := newGrpcInflightMethodLimiter(nil, func() distributorPushReceiver { return m })
+
+		_, err := l.RPCCallStarting(context.Background(), httpgrpcHandleMethod, metadata.New(map[string]string{
+			httpgrpc.MetadataMethod:      "POST",
+			httpgrpc.MetadataURL:         "prefix" + api.InfluxPushEndpoint,
+			grpcutil.MetadataMessageSize: "123456",
+		}))
+		require.NoError(t, err)
+		require.Equal(t, 1, m.startCalls)
+		require.Equal(t, int64(123456), m.lastRequestSize)
+	})
+
 	t.Run("distributor push via httpgrpc, /hello", func(t *testing.T) {
 		m := &mockDistributorReceiver{}
 		l := newGrpcInflightMethodLimiter(nil, func() distributorPushReceiver { return m })
@@ -511,8 +511,8 @@ func (t *Mimir) initQueryable() (serv services.Service, err error) {
 		t.Cfg.Querier, t.Overrides, t.Distributor, t.StoreQueryable, registerer, util_log.Logger, t.ActivityTracker,
 	)
 
-	// Use the distributor to return metric metadata by default
-	t.MetadataSupplier = t.Distributor
+	// Use the distributor to return metric metadata by default, and the store-gateways if enabled
+	t.MetadataSupplier = querier.NewMetadataSupplier(t.Cfg.Querier, t.Distributor, t.StoreQueryable, util_log.Logger)
 
 	// Register the default endpoints that are always enabled for the querier module
 	t.API.RegisterQueryable(t.Distributor)
@@ -598,6 +598,7 @@ func (t *Mimir) initQuerier() (serv services.Service, err error) {
 	internalQuerierRouter := api.NewQuerierHandler(
 		t.Cfg.API,
 		t.QuerierQueryable,
+		t.StoreQueryable,
 		t.ExemplarQueryable,
 		t.MetadataSupplier,
 		t.QuerierEngine,
@@ -883,8 +884,16 @@ func (t *Mimir) initRuler() (serv services.Service, err error) {
 		),
 	)
 
+	var evaluationHistory *ruler.EvaluationHistory
+	if t.Cfg.Ruler.EvaluationHistory.Enabled {
+		evaluationHistory, err = ruler.NewEvaluationHistory(context.Background(), t.Cfg.Ruler.EvaluationHistory, t.Cfg.RulerStorage, t.Overrides, util_log.Logger, t.Registerer)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	dnsResolver := dns.NewProvider(util_log.Logger, dnsProviderReg, dns.GolangResolverType)
-	manager, err := ruler.NewDefaultMultiTenantManager(t.Cfg.Ruler, managerFactory, t.Registerer, util_log.Logger, dnsResolver)
+	manager, err := ruler.NewDefaultMultiTenantManager(t.Cfg.Ruler, managerFactory, evaluationHistory, t.Registerer, util_log.Logger, dnsResolver)
 	if err != nil {
 		return nil, err
 	}
@@ -906,7 +915,10 @@ func (t *Mimir) initRuler() (serv services.Service, err error) {
 	t.API.RegisterRuler(t.Ruler)
 
 	// Expose HTTP configuration and prometheus-compatible Ruler APIs
-	t.API.RegisterRulerAPI(ruler.NewAPI(t.Ruler, t.RulerDirectStorage, util_log.Logger), t.Cfg.Ruler.EnableAPI, t.BuildInfoHandler)
+	t.API.RegisterRulerAPI(ruler.NewAPI(t.Ruler, t.RulerDirectStorage, queryFunc, util_log.Logger), t.Cfg.Ruler.EnableAPI, t.BuildInfoHandler)
+	if evaluationHistory != nil {
+		t.API.RegisterRulerEvaluationHistory(evaluationHistory)
+	}
 
 	return t.Ruler, nil
 }
@@ -0,0 +1,401 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package mimirpb
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Field numbers of the Prometheus remote write 2.0 messages, as defined by
+// https://github.com/prometheus/prometheus/blob/main/prompb/io/prometheus/write/v2/types.proto.
+// The Sample and Histogram messages are wire compatible with the ones of this package, so they're
+// not listed here.
+const (
+	rw2RequestSymbolsField    = 4
+	rw2RequestTimeseriesField = 5
+
+	rw2TimeSeriesLabelsRefsField       = 1
+	rw2TimeSeriesSamplesField          = 2
+	rw2TimeSeriesHistogramsField       = 3
+	rw2TimeSeriesExemplarsField        = 4
+	rw2TimeSeriesMetadataField         = 5
+	rw2TimeSeriesCreatedTimestampField = 6
+
+	rw2ExemplarLabelsRefsField = 1
+	rw2ExemplarValueField      = 2
+	rw2ExemplarTimestampField  = 3
+
+	rw2MetadataTypeField    = 1
+	rw2MetadataHelpRefField = 3
+	rw2MetadataUnitRefField = 4
+)
+
+// WriteRequestV2 is a proto.Message which decodes a Prometheus remote write 2.0 request
+// (io.prometheus.write.v2.Request) into a WriteRequest. The labels and metadata of the remote
+// write 2.0 request reference its symbols table, and are resolved while decoding. Like for
+// PreallocWriteRequest, the strings of the decoded WriteRequest reference the unmarshalled buffer.
+type WriteRequestV2 struct {
+	// Request is the WriteRequest which the decoded series and metadata are written to.
+	Request *PreallocWriteRequest
+
+	// CreatedTimestampZeroIngestion enables the ingestion of a zero sample at the created timestamp
+	// of each series, if the created timestamp precedes the first sample of the series in the request.
+	CreatedTimestampZeroIngestion bool
+}
+
+// Reset implements proto.Message.
+func (m *WriteRequestV2) Reset() { m.Request.Reset() }
+
+// String implements proto.Message.
+func (m *WriteRequestV2) String() string { return m.Request.String() }
+
+// ProtoMessage implements proto.Message.
+func (m *WriteRequestV2) ProtoMessage() {}
+
+// Unmarshal decodes the remote write 2.0 request in dAtA into m.Request.
+func (m *WriteRequestV2) Unmarshal(dAtA []byte) error {
+	// The symbols table can be encoded after the series referencing it, so it's read first.
+	var symbols []string
+	err := rw2ForEachField(dAtA, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		if num != rw2RequestSymbolsField {
+			return nil
+		}
+		symbol, err := rw2Bytes(typ, value)
+		if err != nil {
+			return errors.Wrap(err, "symbols")
+		}
+		symbols = append(symbols, yoloString(symbol))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	d := rw2Decoder{
+		req:                           &m.Request.WriteRequest,
+		symbols:                       symbols,
+		metadata:                      map[string]struct{}{},
+		createdTimestampZeroIngestion: m.CreatedTimestampZeroIngestion,
+	}
+	m.Request.Timeseries = PreallocTimeseriesSliceFromPool()
+
+	return rw2ForEachField(dAtA, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		if num != rw2RequestTimeseriesField {
+			return nil
+		}
+		buf, err := rw2Bytes(typ, value)
+		if err != nil {
+			return errors.Wrap(err, "timeseries")
+		}
+		return errors.Wrapf(d.decodeTimeSeries(buf), "timeseries %d", len(d.req.Timeseries)-1)
+	})
+}
+
+// rw2Decoder holds the state used to decode the series of a remote write 2.0 request.
+type rw2Decoder struct {
+	req     *WriteRequest
+	symbols []string
+
+	// metadata holds the names of the metric families whose metadata has already been added to req.
+	metadata map[string]struct{}
+
+	createdTimestampZeroIngestion bool
+
+	// refs is reused across series to decode label references.
+	refs []uint32
+}
+
+func (d *rw2Decoder) decodeTimeSeries(buf []byte) error {
+	ts := TimeseriesFromPool()
+	d.req.Timeseries = append(d.req.Timeseries, PreallocTimeseries{TimeSeries: ts})
+
+	var (
+		meta             *MetricMetadata
+		createdTimestamp int64
+	)
+	d.refs = d.refs[:0]
+
+	err := rw2ForEachField(buf, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		switch num {
+		case rw2TimeSeriesLabelsRefsField:
+			refs, err := rw2AppendUint32s(d.refs, typ, value)
+			if err != nil {
+				return errors.Wrap(err, "labels")
+			}
+			d.refs = refs
+
+		case rw2TimeSeriesSamplesField:
+			b, err := rw2Bytes(typ, value)
+			if err != nil {
+				return errors.Wrap(err, "samples")
+			}
+			var s Sample
+			if err := s.Unmarshal(b); err != nil {
+				return errors.Wrap(err, "samples")
+			}
+			ts.Samples = append(ts.Samples, s)
+
+		case rw2TimeSeriesHistogramsField:
+			b, err := rw2Bytes(typ, value)
+			if err != nil {
+				return errors.Wrap(err, "histograms")
+			}
+			var h Histogram
+			if err := h.Unmarshal(b); err != nil {
+				return errors.Wrap(err, "histograms")
+			}
+			ts.Histograms = append(ts.Histograms, h)
+
+		case rw2TimeSeriesExemplarsField:
+			b, err := rw2Bytes(typ, value)
+			if err != nil {
+				return errors.Wrap(err, "exemplars")
+			}
+			e, err := d.decodeExemplar(b)
+			if err != nil {
+				return errors.Wrap(err, "exemplars")
+			}
+			ts.Exemplars = append(ts.Exemplars, e)
+
+		case rw2TimeSeriesMetadataField:
+			b, err := rw2Bytes(typ, value)
+			if err != nil {
+				return errors.Wrap(err, "metadata")
+			}
+			if meta, err = d.decodeMetadata(b); err != nil {
+				return errors.Wrap(err, "metadata")
+			}
+
+		case rw2TimeSeriesCreatedTimestampField:
+			v, err := rw2Varint(typ, value)
+			if err != nil {
+				return errors.Wrap(err, "created timestamp")
+			}
+			createdTimestamp = int64(v)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if ts.Labels, err = d.appendLabels(ts.Labels, d.refs); err != nil {
+		return errors.Wrap(err, "labels")
+	}
+
+	if meta != nil {
+		d.addMetadata(ts.Labels, meta)
+	}
+
+	if d.createdTimestampZeroIngestion && createdTimestamp != 0 {
+		addCreatedTimestampZeroSample(ts, createdTimestamp)
+	}
+
+	return nil
+}
+
+func (d *rw2Decoder) decodeExemplar(buf []byte) (Exemplar, error) {
+	var (
+		e    Exemplar
+		refs []uint32
+	)
+	err := rw2ForEachField(buf, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		var err error
+		switch num {
+		case rw2ExemplarLabelsRefsField:
+			refs, err = rw2AppendUint32s(refs, typ, value)
+		case rw2ExemplarValueField:
+			var v uint64
+			v, err = rw2Fixed64(typ, value)
+			e.Value = math.Float64frombits(v)
+		case rw2ExemplarTimestampField:
+			var v uint64
+			v, err = rw2Varint(typ, value)
+			e.TimestampMs = int64(v)
+		}
+		return err
+	})
+	if err != nil {
+		return e, err
+	}
+
+	e.Labels, err = d.appendLabels(nil, refs)
+	return e, err
+}
+
+func (d *rw2Decoder) decodeMetadata(buf []byte) (*MetricMetadata, error) {
+	meta := &MetricMetadata{}
+	err := rw2ForEachField(buf, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		if num != rw2MetadataTypeField && num != rw2MetadataHelpRefField && num != rw2MetadataUnitRefField {
+			return nil
+		}
+		v, err := rw2Varint(typ, value)
+		if err != nil {
+			return err
+		}
+
+		switch num {
+		case rw2MetadataTypeField:
+			// The metric types of remote write 2.0 have the same values as the ones of this package.
+			meta.Type = MetricMetadata_MetricType(v)
+		case rw2MetadataHelpRefField:
+			meta.Help, err = d.symbol(v)
+		case rw2MetadataUnitRefField:
+			meta.Unit, err = d.symbol(v)
+		}
+		return err
+	})
+	return meta, err
+}
+
+// addMetadata adds the metadata of a series to the request, unless it's empty or the metadata
+// of the same metric family has already been added.
+func (d *rw2Decoder) addMetadata(lbls []LabelAdapter, meta *MetricMetadata) {
+	if meta.Type == UNKNOWN && meta.Help == "" && meta.Unit == "" {
+		return
+	}
+
+	for _, l := range lbls {
+		if l.Name != "__name__" {
+			continue
+		}
+		if _, ok := d.metadata[l.Value]; ok {
+			return
+		}
+		d.metadata[l.Value] = struct{}{}
+
+		meta.MetricFamilyName = l.Value
+		d.req.Metadata = append(d.req.Metadata, meta)
+		return
+	}
+}
+
+// appendLabels resolves the name and value references of refs, and appends the resulting labels to dst.
+func (d *rw2Decoder) appendLabels(dst []LabelAdapter, refs []uint32) ([]LabelAdapter, error) {
+	if len(refs)%2 != 0 {
+		return dst, fmt.Errorf("odd number of label references: %d", len(refs))
+	}
+
+	for i := 0; i < len(refs); i += 2 {
+		name, err := d.symbol(uint64(refs[i]))
+		if err != nil {
+			return dst, err
+		}
+		value, err := d.symbol(uint64(refs[i+1]))
+		if err != nil {
+			return dst, err
+		}
+		dst = append(dst, LabelAdapter{Name: name, Value: value})
+	}
+	return dst, nil
+}
+
+func (d *rw2Decoder) symbol(ref uint64) (string, error) {
+	if ref >= uint64(len(d.symbols)) {
+		return "", fmt.Errorf("symbol reference %d is out of range, the symbols table has %d entries", ref, len(d.symbols))
+	}
+	return d.symbols[ref], nil
+}
+
+// addCreatedTimestampZeroSample adds a zero sample at the created timestamp before the first sample
+// of the series, if the created timestamp precedes it.
+func addCreatedTimestampZeroSample(ts *TimeSeries, createdTimestamp int64) {
+	if len(ts.Samples) > 0 && createdTimestamp < ts.Samples[0].TimestampMs {
+		ts.Samples = append(ts.Samples, Sample{})
+		copy(ts.Samples[1:], ts.Samples)
+		ts.Samples[0] = Sample{TimestampMs: createdTimestamp}
+		return
+	}
+
+	if len(ts.Histograms) > 0 && createdTimestamp < ts.Histograms[0].Timestamp {
+		first := ts.Histograms[0]
+		zero := Histogram{
+			Schema:        first.Schema,
+			ZeroThreshold: first.ZeroThreshold,
+			Timestamp:     createdTimestamp,
+		}
+		if first.IsFloatHistogram() {
+			zero.Count = &Histogram_CountFloat{}
+			zero.ZeroCount = &Histogram_ZeroCountFloat{}
+		} else {
+			zero.Count = &Histogram_CountInt{}
+			zero.ZeroCount = &Histogram_ZeroCountInt{}
+		}
+
+		ts.Histograms = append(ts.Histograms, Histogram{})
+		copy(ts.Histograms[1:], ts.Histograms)
+		ts.Histograms[0] = zero
+	}
+}
+
+// rw2ForEachField calls fn with the number, type and encoded value of each field of the
+// protobuf message in buf.
+func rw2ForEachField(buf []byte, fn func(num protowire.Number, typ protowire.Type, value []byte) error) error {
+	for len(buf) > 0 {
+		num, typ, n := protowire.ConsumeTag(buf)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		buf = buf[n:]
+
+		n = protowire.ConsumeFieldValue(num, typ, buf)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		if err := fn(num, typ, buf[:n]); err != nil {
+			return err
+		}
+		buf = buf[n:]
+	}
+	return nil
+}
+
+func rw2Bytes(typ protowire.Type, value []byte) ([]byte, error) {
+	if typ != protowire.BytesType {
+		return nil, fmt.Errorf("unexpected wire type %d", typ)
+	}
+	b, _ := protowire.ConsumeBytes(value)
+	return b, nil
+}
+
+func rw2Varint(typ protowire.Type, value []byte) (uint64, error) {
+	if typ != protowire.VarintType {
+		return 0, fmt.Errorf("unexpected wire type %d", typ)
+	}
+	v, _ := protowire.ConsumeVarint(value)
+	return v, nil
+}
+
+func rw2Fixed64(typ protowire.Type, value []byte) (uint64, error) {
+	if typ != protowire.Fixed64Type {
+		return 0, fmt.Errorf("unexpected wire type %d", typ)
+	}
+	v, _ := protowire.ConsumeFixed64(value)
+	return v, nil
+}
+
+// rw2AppendUint32s decodes a repeated uint32 field, either packed or not, and appends its values to dst.
+func rw2AppendUint32s(dst []uint32, typ protowire.Type, value []byte) ([]uint32, error) {
+	if typ == protowire.VarintType {
+		v, _ := protowire.ConsumeVarint(value)
+		return append(dst, uint32(v)), nil
+	}
+
+	packed, err := rw2Bytes(typ, value)
+	if err != nil {
+		return dst, err
+	}
+	for len(packed) > 0 {
+		v, n := protowire.ConsumeVarint(packed)
+		if n < 0 {
+			return dst, protowire.ParseError(n)
+		}
+		dst = append(dst, uint32(v))
+		packed = packed[n:]
+	}
+	return dst, nil
+}
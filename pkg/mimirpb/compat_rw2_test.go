@@ -0,0 +1,190 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package mimirpb
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/grafana/mimir/pkg/util/test"
+)
+
+func TestWriteRequestV2_Unmarshal(t *testing.T) {
+	series := []rw2TestSeries{
+		{
+			labels:           []string{"__name__", "http_requests_total", "job", "api"},
+			samples:          []Sample{{TimestampMs: 2000, Value: 10}, {TimestampMs: 3000, Value: 12}},
+			exemplars:        []rw2TestExemplar{{labels: []string{"trace_id", "abc"}, value: 11, timestamp: 2500}},
+			metadata:         &MetricMetadata{Type: COUNTER, Help: "Total number of HTTP requests.", Unit: "requests"},
+			createdTimestamp: 1000,
+		},
+		{
+			labels:           []string{"__name__", "http_requests_total", "job", "web"},
+			samples:          []Sample{{TimestampMs: 2000, Value: 5}},
+			metadata:         &MetricMetadata{Type: COUNTER, Help: "Total number of HTTP requests.", Unit: "requests"},
+			createdTimestamp: 2000,
+		},
+		{
+			labels:           []string{"__name__", "request_duration_seconds", "job", "api"},
+			histograms:       []Histogram{FromHistogramToHistogramProto(2000, test.GenerateTestHistogram(0))},
+			metadata:         &MetricMetadata{Type: HISTOGRAM},
+			createdTimestamp: 1000,
+		},
+		{
+			labels:  []string{"__name__", "up", "job", "api"},
+			samples: []Sample{{TimestampMs: 2000, Value: 1}},
+		},
+	}
+	buf := marshalRW2(series)
+
+	t.Run("created timestamp zero ingestion disabled", func(t *testing.T) {
+		req := &PreallocWriteRequest{}
+		require.NoError(t, (&WriteRequestV2{Request: req}).Unmarshal(buf))
+
+		require.Len(t, req.Timeseries, 4)
+		assert.Equal(t, []LabelAdapter{{Name: "__name__", Value: "http_requests_total"}, {Name: "job", Value: "api"}}, req.Timeseries[0].Labels)
+		assert.Equal(t, series[0].samples, req.Timeseries[0].Samples)
+		assert.Equal(t, []Exemplar{{Labels: []LabelAdapter{{Name: "trace_id", Value: "abc"}}, Value: 11, TimestampMs: 2500}}, req.Timeseries[0].Exemplars)
+		assert.Equal(t, []LabelAdapter{{Name: "__name__", Value: "http_requests_total"}, {Name: "job", Value: "web"}}, req.Timeseries[1].Labels)
+		assert.Equal(t, series[1].samples, req.Timeseries[1].Samples)
+		assert.Equal(t, series[2].histograms, req.Timeseries[2].Histograms)
+		assert.Equal(t, []LabelAdapter{{Name: "__name__", Value: "up"}, {Name: "job", Value: "api"}}, req.Timeseries[3].Labels)
+
+		// Metadata is deduplicated by metric family.
+		assert.Equal(t, []*MetricMetadata{
+			{Type: COUNTER, MetricFamilyName: "http_requests_total", Help: "Total number of HTTP requests.", Unit: "requests"},
+			{Type: HISTOGRAM, MetricFamilyName: "request_duration_seconds"},
+		}, req.Metadata)
+	})
+
+	t.Run("created timestamp zero ingestion enabled", func(t *testing.T) {
+		req := &PreallocWriteRequest{}
+		require.NoError(t, (&WriteRequestV2{Request: req, CreatedTimestampZeroIngestion: true}).Unmarshal(buf))
+
+		require.Len(t, req.Timeseries, 4)
+		assert.Equal(t, []Sample{{TimestampMs: 1000, Value: 0}, {TimestampMs: 2000, Value: 10}, {TimestampMs: 3000, Value: 12}}, req.Timeseries[0].Samples)
+		// The created timestamp doesn't precede the first sample.
+		assert.Equal(t, series[1].samples, req.Timeseries[1].Samples)
+
+		histograms := req.Timeseries[2].Histograms
+		require.Len(t, histograms, 2)
+		assert.Equal(t, int64(1000), histograms[0].Timestamp)
+		assert.Equal(t, uint64(0), histograms[0].GetCountInt())
+		assert.Equal(t, series[2].histograms[0].Schema, histograms[0].Schema)
+		assert.Equal(t, series[2].histograms[0], histograms[1])
+	})
+
+	t.Run("should fail on symbol reference out of range", func(t *testing.T) {
+		var ts []byte
+		ts = protowire.AppendTag(ts, rw2TimeSeriesLabelsRefsField, protowire.BytesType)
+		ts = protowire.AppendBytes(ts, protowire.AppendVarint(protowire.AppendVarint(nil, 1), 5))
+		var invalid []byte
+		invalid = protowire.AppendTag(invalid, rw2RequestSymbolsField, protowire.BytesType)
+		invalid = protowire.AppendString(invalid, "")
+		invalid = protowire.AppendTag(invalid, rw2RequestSymbolsField, protowire.BytesType)
+		invalid = protowire.AppendString(invalid, "__name__")
+		invalid = protowire.AppendTag(invalid, rw2RequestTimeseriesField, protowire.BytesType)
+		invalid = protowire.AppendBytes(invalid, ts)
+
+		err := (&WriteRequestV2{Request: &PreallocWriteRequest{}}).Unmarshal(invalid)
+		require.ErrorContains(t, err, "symbol reference 5 is out of range")
+	})
+
+	t.Run("should fail on truncated input", func(t *testing.T) {
+		err := (&WriteRequestV2{Request: &PreallocWriteRequest{}}).Unmarshal(buf[:len(buf)-1])
+		require.Error(t, err)
+	})
+}
+
+type rw2TestExemplar struct {
+	labels    []string
+	value     float64
+	timestamp int64
+}
+
+type rw2TestSeries struct {
+	labels           []string
+	samples          []Sample
+	histograms       []Histogram
+	exemplars        []rw2TestExemplar
+	metadata         *MetricMetadata
+	createdTimestamp int64
+}
+
+// marshalRW2 encodes the series into a remote write 2.0 request. The symbols table is
+// encoded after the series, to check that it's decoded regardless of its position.
+func marshalRW2(series []rw2TestSeries) []byte {
+	symbols := []string{""}
+	symbolRefs := map[string]uint64{"": 0}
+	ref := func(s string) uint64 {
+		if r, ok := symbolRefs[s]; ok {
+			return r
+		}
+		symbolRefs[s] = uint64(len(symbols))
+		symbols = append(symbols, s)
+		return symbolRefs[s]
+	}
+	refs := func(lbls []string) []byte {
+		var b []byte
+		for _, l := range lbls {
+			b = protowire.AppendVarint(b, ref(l))
+		}
+		return b
+	}
+
+	var buf []byte
+	for _, s := range series {
+		var ts []byte
+		ts = protowire.AppendTag(ts, rw2TimeSeriesLabelsRefsField, protowire.BytesType)
+		ts = protowire.AppendBytes(ts, refs(s.labels))
+		for _, sample := range s.samples {
+			b, _ := sample.Marshal()
+			ts = protowire.AppendTag(ts, rw2TimeSeriesSamplesField, protowire.BytesType)
+			ts = protowire.AppendBytes(ts, b)
+		}
+		for _, h := range s.histograms {
+			b, _ := h.Marshal()
+			ts = protowire.AppendTag(ts, rw2TimeSeriesHistogramsField, protowire.BytesType)
+			ts = protowire.AppendBytes(ts, b)
+		}
+		for _, e := range s.exemplars {
+			var b []byte
+			b = protowire.AppendTag(b, rw2ExemplarLabelsRefsField, protowire.BytesType)
+			b = protowire.AppendBytes(b, refs(e.labels))
+			b = protowire.AppendTag(b, rw2ExemplarValueField, protowire.Fixed64Type)
+			b = protowire.AppendFixed64(b, math.Float64bits(e.value))
+			b = protowire.AppendTag(b, rw2ExemplarTimestampField, protowire.VarintType)
+			b = protowire.AppendVarint(b, uint64(e.timestamp))
+			ts = protowire.AppendTag(ts, rw2TimeSeriesExemplarsField, protowire.BytesType)
+			ts = protowire.AppendBytes(ts, b)
+		}
+		if s.metadata != nil {
+			var b []byte
+			b = protowire.AppendTag(b, rw2MetadataTypeField, protowire.VarintType)
+			b = protowire.AppendVarint(b, uint64(s.metadata.Type))
+			b = protowire.AppendTag(b, rw2MetadataHelpRefField, protowire.VarintType)
+			b = protowire.AppendVarint(b, ref(s.metadata.Help))
+			b = protowire.AppendTag(b, rw2MetadataUnitRefField, protowire.VarintType)
+			b = protowire.AppendVarint(b, ref(s.metadata.Unit))
+			ts = protowire.AppendTag(ts, rw2TimeSeriesMetadataField, protowire.BytesType)
+			ts = protowire.AppendBytes(ts, b)
+		}
+		if s.createdTimestamp != 0 {
+			ts = protowire.AppendTag(ts, rw2TimeSeriesCreatedTimestampField, protowire.VarintType)
+			ts = protowire.AppendVarint(ts, uint64(s.createdTimestamp))
+		}
+
+		buf = protowire.AppendTag(buf, rw2RequestTimeseriesField, protowire.BytesType)
+		buf = protowire.AppendBytes(buf, ts)
+	}
+
+	for _, s := range symbols {
+		buf = protowire.AppendTag(buf, rw2RequestSymbolsField, protowire.BytesType)
+		buf = protowire.AppendString(buf, s)
+	}
+	return buf
+}
@@ -8,11 +8,15 @@ package client
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/pkg/errors"
+	"github.com/prometheus/common/model"
 	log "github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v3"
 
@@ -87,6 +91,49 @@ func (r *MimirClient) GetRuleGroup(ctx context.Context, namespace, groupName str
 	return &rg, nil
 }
 
+// BackfillRuleGroup evaluates the recording rules of a rule group over a past time range, and returns the recorded series.
+// The request is retried after sleepTime while the ruler has too many backfills in progress.
+func (r *MimirClient) BackfillRuleGroup(ctx context.Context, namespace, groupName string, start, end time.Time, sleepTime time.Duration) (model.Matrix, error) {
+	escapedNamespace := url.PathEscape(namespace)
+	escapedGroupName := url.PathEscape(groupName)
+	params := url.Values{
+		"start": {start.UTC().Format(time.RFC3339Nano)},
+		"end":   {end.UTC().Format(time.RFC3339Nano)},
+	}
+	path := r.apiPath + "/" + escapedNamespace + "/" + escapedGroupName + "/backfill?" + params.Encode()
+
+	var res *http.Response
+	for {
+		var err error
+		res, err = r.doRequest(ctx, path, "POST", nil, -1)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, errTooManyRequests) {
+			return nil, err
+		}
+		log.WithField("error", err).Warning("will sleep and try again")
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(sleepTime):
+		}
+	}
+
+	defer res.Body.Close()
+
+	var resp struct {
+		Data struct {
+			Result model.Matrix `json:"result"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&resp); err != nil {
+		return nil, errors.Wrap(err, "unable to unmarshal response")
+	}
+
+	return resp.Data.Result, nil
+}
+
 // ListRules retrieves a rule group
 func (r *MimirClient) ListRules(ctx context.Context, namespace string) (map[string][]rwrulefmt.RuleGroup, error) {
 	path := r.apiPath
@@ -0,0 +1,205 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/golang/snappy"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/mimirtool/client"
+	"github.com/grafana/mimir/pkg/storage/ingest"
+)
+
+// DeadLetterCommand inspects and re-injects the records which the ingesters couldn't apply
+// when consuming from the ingest storage.
+type DeadLetterCommand struct {
+	spoolDir     string
+	kafkaAddress string
+	kafkaTopic   string
+	tenantID     string
+
+	address      string
+	pushPath     string
+	apiKey       string
+	writeTimeout time.Duration
+	delete       bool
+
+	output io.Writer
+}
+
+func (c *DeadLetterCommand) Register(app *kingpin.Application, envVars EnvVarNames) {
+	deadLetterCmd := app.Command("dead-letter", "Inspect and re-inject the records which Grafana Mimir ingesters couldn't apply when consuming from the ingest storage.")
+	listCmd := deadLetterCmd.Command("list", "List the records in the dead-letter spool directory or topic.").Action(c.list)
+	reinjectCmd := deadLetterCmd.Command("reinject", "Re-inject the records in the dead-letter spool directory or topic by pushing them to Grafana Mimir.").Action(c.reinject)
+
+	for _, cmd := range []*kingpin.CmdClause{listCmd, reinjectCmd} {
+		cmd.Flag("spool-dir", "Dead-letter spool directory, as configured via -ingest-storage.dead-letter.spool-dir.").
+			StringVar(&c.spoolDir)
+		cmd.Flag("kafka.address", "Address of the Kafka backend with the dead-letter topic.").
+			StringVar(&c.kafkaAddress)
+		cmd.Flag("kafka.topic", "Dead-letter topic, as configured via -ingest-storage.dead-letter.topic.").
+			StringVar(&c.kafkaTopic)
+		cmd.Flag("tenant", "Only include the records of this tenant.").
+			StringVar(&c.tenantID)
+	}
+
+	reinjectCmd.Flag("address", "Address of the Grafana Mimir cluster; alternatively, set "+envVars.Address+".").
+		Envar(envVars.Address).
+		Required().
+		StringVar(&c.address)
+	reinjectCmd.Flag("push-path", "Path of the push endpoint.").
+		Default("/api/v1/push").
+		StringVar(&c.pushPath)
+	reinjectCmd.Flag("key", "Basic auth password to use when contacting Grafana Mimir, the username is the tenant ID of each record; alternatively, set "+envVars.APIKey+".").
+		Envar(envVars.APIKey).
+		Default("").
+		StringVar(&c.apiKey)
+	reinjectCmd.Flag("write-timeout", "Timeout for write requests.").
+		Default("30s").
+		DurationVar(&c.writeTimeout)
+	reinjectCmd.Flag("delete", "Delete the records successfully re-injected from the spool directory.").
+		BoolVar(&c.delete)
+}
+
+// forEachRecord calls fn for each record in the configured spool directory or topic. The path of the record
+// is empty when reading from the topic.
+func (c *DeadLetterCommand) forEachRecord(ctx context.Context, fn func(path string, rec ingest.DeadLetterRecord) error) error {
+	filter := func(path string, rec ingest.DeadLetterRecord) error {
+		if c.tenantID != "" && rec.TenantID != c.tenantID {
+			return nil
+		}
+		return fn(path, rec)
+	}
+
+	switch {
+	case c.spoolDir != "" && (c.kafkaAddress != "" || c.kafkaTopic != ""):
+		return errors.New("either --spool-dir or --kafka.address and --kafka.topic must be set, but not both")
+	case c.spoolDir != "":
+		return ingest.ReadDeadLetterSpool(c.spoolDir, filter)
+	case c.kafkaAddress != "" && c.kafkaTopic != "":
+		return ingest.ReadDeadLetterTopic(ctx, c.kafkaAddress, c.kafkaTopic, func(rec ingest.DeadLetterRecord) error {
+			return filter("", rec)
+		})
+	default:
+		return errors.New("either --spool-dir or --kafka.address and --kafka.topic must be set")
+	}
+}
+
+func (c *DeadLetterCommand) getOutput() io.Writer {
+	if c.output == nil {
+		return os.Stdout
+	}
+	return c.output
+}
+
+func (c *DeadLetterCommand) list(_ *kingpin.ParseContext) error {
+	tw := tabwriter.NewWriter(c.getOutput(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TOPIC\tPARTITION\tOFFSET\tBATCH INDEX\tTENANT\tTIMESTAMP\tREASON\tSERIES\tSAMPLES\tERROR")
+
+	err := c.forEachRecord(context.Background(), func(_ string, rec ingest.DeadLetterRecord) error {
+		series, samples := "-", "-"
+		if req, err := parseDeadLetterRecord(rec); err == nil {
+			numSamples := 0
+			for _, ts := range req.Timeseries {
+				numSamples += len(ts.Samples) + len(ts.Histograms)
+			}
+			series, samples = fmt.Sprint(len(req.Timeseries)), fmt.Sprint(numSamples)
+		}
+
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%s\t%s\t%s\t%s\t%s\t%s\n", rec.Topic, rec.Partition, rec.Offset, rec.BatchIndex, rec.TenantID, rec.Timestamp.UTC().Format(time.RFC3339), rec.Reason, series, samples, rec.Error)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return tw.Flush()
+}
+
+func (c *DeadLetterCommand) reinject(_ *kingpin.ParseContext) error {
+	httpClient := &http.Client{Timeout: c.writeTimeout}
+	pushURL := strings.TrimSuffix(c.address, "/") + c.pushPath
+
+	var reinjected, skipped, failed int
+	err := c.forEachRecord(context.Background(), func(path string, rec ingest.DeadLetterRecord) error {
+		logger := log.WithFields(log.Fields{"topic": rec.Topic, "partition": rec.Partition, "offset": rec.Offset, "batch_index": rec.BatchIndex, "tenant": rec.TenantID})
+
+		// Records which can't be parsed would be rejected by Grafana Mimir too.
+		if _, err := parseDeadLetterRecord(rec); err != nil {
+			logger.WithError(err).Warn("skipping unparsable record")
+			skipped++
+			return nil
+		}
+
+		if err := c.push(httpClient, pushURL, rec); err != nil {
+			logger.WithError(err).Error("failed to re-inject record")
+			failed++
+			return nil
+		}
+		reinjected++
+
+		if c.delete && path != "" {
+			if err := os.Remove(path); err != nil {
+				return errors.Wrapf(err, "deleting re-injected record %s", path)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.WithFields(log.Fields{"reinjected": reinjected, "skipped": skipped, "failed": failed}).Info("re-injection completed")
+	if failed > 0 {
+		return fmt.Errorf("failed to re-inject %d records", failed)
+	}
+	return nil
+}
+
+func (c *DeadLetterCommand) push(httpClient *http.Client, pushURL string, rec ingest.DeadLetterRecord) error {
+	req, err := http.NewRequest(http.MethodPost, pushURL, bytes.NewReader(snappy.Encode(nil, rec.Content)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("User-Agent", client.UserAgent())
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	req.Header.Set("X-Scope-OrgID", rec.TenantID)
+	if c.apiKey != "" {
+		req.SetBasicAuth(rec.TenantID, c.apiKey)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("server returned HTTP status %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+func parseDeadLetterRecord(rec ingest.DeadLetterRecord) (*mimirpb.WriteRequest, error) {
+	req := &mimirpb.WriteRequest{}
+	if err := req.Unmarshal(rec.Content); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
@@ -0,0 +1,129 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/storage/ingest"
+)
+
+func writeDeadLetterSpool(t *testing.T, dir string, records ...ingest.DeadLetterRecord) {
+	for _, rec := range records {
+		data, err := json.Marshal(rec)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, rec.Topic+"-"+rec.TenantID+".json"), data, 0o640))
+	}
+}
+
+func marshalWriteRequest(t *testing.T, metricName string) []byte {
+	req := &mimirpb.WriteRequest{Timeseries: []mimirpb.PreallocTimeseries{{TimeSeries: &mimirpb.TimeSeries{
+		Labels:  []mimirpb.LabelAdapter{{Name: "__name__", Value: metricName}},
+		Samples: []mimirpb.Sample{{TimestampMs: 1, Value: 1}, {TimestampMs: 2, Value: 2}},
+	}}}}
+	data, err := req.Marshal()
+	require.NoError(t, err)
+	return data
+}
+
+func TestDeadLetterCommand_List(t *testing.T) {
+	dir := t.TempDir()
+	timestamp := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	writeDeadLetterSpool(t, dir,
+		ingest.DeadLetterRecord{TenantID: "user-1", Topic: "ingest", Partition: 1, Offset: 10, Timestamp: timestamp, Reason: ingest.DeadLetterReasonServerError, Error: "server error", Content: marshalWriteRequest(t, "up")},
+		ingest.DeadLetterRecord{TenantID: "user-2", Topic: "ingest", Partition: 1, Offset: 11, Timestamp: timestamp, Reason: ingest.DeadLetterReasonUnparsable, Error: "unparsable", Content: []byte{0}},
+	)
+
+	t.Run("all records", func(t *testing.T) {
+		output := &bytes.Buffer{}
+		cmd := &DeadLetterCommand{spoolDir: dir, output: output}
+		require.NoError(t, cmd.list(nil))
+
+		assert.Equal(t, ""+
+			"TOPIC   PARTITION  OFFSET  BATCH INDEX  TENANT  TIMESTAMP             REASON        SERIES  SAMPLES  ERROR\n"+
+			"ingest  1          10      0            user-1  2024-01-02T03:04:05Z  server_error  1       2        server error\n"+
+			"ingest  1          11      0            user-2  2024-01-02T03:04:05Z  unparsable    -       -        unparsable\n",
+			output.String())
+	})
+
+	t.Run("filtered by tenant", func(t *testing.T) {
+		output := &bytes.Buffer{}
+		cmd := &DeadLetterCommand{spoolDir: dir, tenantID: "user-2", output: output}
+		require.NoError(t, cmd.list(nil))
+
+		assert.NotContains(t, output.String(), "user-1")
+		assert.Contains(t, output.String(), "user-2")
+	})
+
+	t.Run("no source", func(t *testing.T) {
+		cmd := &DeadLetterCommand{output: io.Discard}
+		require.Error(t, cmd.list(nil))
+	})
+}
+
+func TestDeadLetterCommand_Reinject(t *testing.T) {
+	var (
+		mtx      sync.Mutex
+		received = map[string]*mimirpb.WriteRequest{}
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/push", r.URL.Path)
+		assert.Equal(t, "snappy", r.Header.Get("Content-Encoding"))
+
+		compressed, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		data, err := snappy.Decode(nil, compressed)
+		require.NoError(t, err)
+
+		req := &mimirpb.WriteRequest{}
+		require.NoError(t, req.Unmarshal(data))
+
+		tenantID := r.Header.Get("X-Scope-OrgID")
+		if tenantID == "user-failing" {
+			http.Error(w, "failure", http.StatusInternalServerError)
+			return
+		}
+
+		mtx.Lock()
+		received[tenantID] = req
+		mtx.Unlock()
+	}))
+	t.Cleanup(server.Close)
+
+	dir := t.TempDir()
+	writeDeadLetterSpool(t, dir,
+		ingest.DeadLetterRecord{TenantID: "user-1", Topic: "ingest", Offset: 10, Reason: ingest.DeadLetterReasonServerError, Content: marshalWriteRequest(t, "up")},
+		ingest.DeadLetterRecord{TenantID: "user-2", Topic: "ingest", Offset: 11, Reason: ingest.DeadLetterReasonUnparsable, Content: []byte{0}},
+		ingest.DeadLetterRecord{TenantID: "user-failing", Topic: "ingest", Offset: 12, Reason: ingest.DeadLetterReasonServerError, Content: marshalWriteRequest(t, "up")},
+	)
+
+	cmd := &DeadLetterCommand{spoolDir: dir, address: server.URL, pushPath: "/api/v1/push", writeTimeout: time.Minute, delete: true}
+	require.EqualError(t, cmd.reinject(nil), "failed to re-inject 1 records")
+
+	require.Len(t, received, 1)
+	require.Contains(t, received, "user-1")
+	assert.Equal(t, "up", received["user-1"].Timeseries[0].Labels[0].Value)
+
+	// Only the successfully re-injected record should have been deleted.
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.ElementsMatch(t, []string{"ingest-user-2.json", "ingest-user-failing.json"}, names)
+}
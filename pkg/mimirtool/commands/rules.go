@@ -8,21 +8,29 @@ package commands
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"reflect"
 	"regexp" //lint:ignore faillint Required by kingpin for regexp flags
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/alecthomas/kingpin/v2"
 	"github.com/grafana/dskit/concurrency"
+	"github.com/oklog/ulid"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/model/rulefmt"
+	"github.com/prometheus/prometheus/tsdb"
 	log "github.com/sirupsen/logrus"
 	yamlv3 "gopkg.in/yaml.v3"
 
+	"github.com/grafana/mimir/pkg/mimirtool/backfill"
 	"github.com/grafana/mimir/pkg/mimirtool/client"
 	"github.com/grafana/mimir/pkg/mimirtool/printer"
 	"github.com/grafana/mimir/pkg/mimirtool/rules"
@@ -58,6 +66,12 @@ type ruleCommandClient interface {
 
 	// DeleteNamespace delete all the rule groups in a namespace including the namespace itself.
 	DeleteNamespace(ctx context.Context, namespace string) error
+
+	// BackfillRuleGroup evaluates the recording rules of a rule group over a past time range.
+	BackfillRuleGroup(ctx context.Context, namespace, groupName string, start, end time.Time, sleepTime time.Duration) (model.Matrix, error)
+
+	// Backfill uploads TSDB blocks to the compactor.
+	Backfill(ctx context.Context, blocks []string, sleepTime time.Duration) error
 }
 
 // RuleCommand configures and executes rule related mimir operations
@@ -109,6 +123,15 @@ type RuleCommand struct {
 	// Diff Rules Config
 	Verbose bool
 
+	// Backfill Rules Config
+	BackfillFrom      string
+	BackfillTo        string
+	BackfillTSDBPath  string
+	BackfillSleepTime time.Duration
+
+	// Test Rules Config
+	TestFilesList []string
+
 	// Metrics.
 	ruleLoadTimestamp        prometheus.Gauge
 	ruleLoadSuccessTimestamp prometheus.Gauge
@@ -158,9 +181,15 @@ func (r *RuleCommand) Register(app *kingpin.Application, envVars EnvVarNames, re
 	deleteNamespaceCmd := rulesCmd.
 		Command("delete-namespace", "Delete a namespace from the ruler.").
 		Action(r.deleteNamespace)
+	backfillCmd := rulesCmd.
+		Command("backfill", "Backfill the recording rules of a rule group over a past time range, and upload the recorded series as TSDB blocks to the Grafana Mimir compactor.").
+		Action(r.backfillRules)
+	testCmd := rulesCmd.
+		Command("test", "Run unit tests for rules, evaluating the rules like the Grafana Mimir ruler does. The test files use the Prometheus promtool unit test format.").
+		Action(r.testRules)
 
 	// Require Mimir cluster address and tenant ID on all these commands
-	for _, c := range []*kingpin.CmdClause{listCmd, printRulesCmd, getRuleGroupCmd, deleteRuleGroupCmd, loadRulesCmd, diffRulesCmd, syncRulesCmd, deleteNamespaceCmd} {
+	for _, c := range []*kingpin.CmdClause{listCmd, printRulesCmd, getRuleGroupCmd, deleteRuleGroupCmd, loadRulesCmd, diffRulesCmd, syncRulesCmd, deleteNamespaceCmd, backfillCmd} {
 		c.Flag("address", "Address of the Grafana Mimir cluster; alternatively, set "+envVars.Address+".").
 			Envar(envVars.Address).
 			Required().
@@ -281,6 +310,17 @@ func (r *RuleCommand) Register(app *kingpin.Application, envVars EnvVarNames, re
 	// Delete Namespace Command
 	deleteNamespaceCmd.Arg("namespace", "Namespace to delete.").Required().StringVar(&r.Namespace)
 
+	// Backfill Command
+	backfillCmd.Arg("namespace", "Namespace of the rulegroup to backfill.").Required().StringVar(&r.Namespace)
+	backfillCmd.Arg("group", "Name of the rulegroup to backfill.").Required().StringVar(&r.RuleGroup)
+	backfillCmd.Flag("from", "Start of the time range to backfill, in RFC3339 format.").Required().StringVar(&r.BackfillFrom)
+	backfillCmd.Flag("to", "End of the time range to backfill, in RFC3339 format.").Required().StringVar(&r.BackfillTo)
+	backfillCmd.Flag("tsdb-path", "Path to the folder where to store the TSDB blocks, if not set a new directory in $TEMP is created and removed once the blocks are uploaded.").Default("").StringVar(&r.BackfillTSDBPath)
+	backfillCmd.Flag("sleep-time", "How long to sleep between checking state of block upload after uploading all files for the block, and before retrying the rule group evaluations rejected because too many backfills are in progress.").Default("20s").DurationVar(&r.BackfillSleepTime)
+
+	// Test Command
+	testCmd.Arg("test-files", "The unit test files to run.").Required().ExistingFilesVar(&r.TestFilesList)
+
 }
 
 func (r *RuleCommand) setup(_ *kingpin.ParseContext, reg prometheus.Registerer) error {
@@ -854,3 +894,166 @@ func (r *RuleCommand) deleteNamespace(_ *kingpin.ParseContext) error {
 	}
 	return nil
 }
+
+func (r *RuleCommand) backfillRules(_ *kingpin.ParseContext) error {
+	from, err := time.Parse(time.RFC3339, r.BackfillFrom)
+	if err != nil {
+		return fmt.Errorf("error parsing from: '%s' value: %w", r.BackfillFrom, err)
+	}
+	to, err := time.Parse(time.RFC3339, r.BackfillTo)
+	if err != nil {
+		return fmt.Errorf("error parsing to: '%s' value: %w", r.BackfillTo, err)
+	}
+	if !from.Before(to) {
+		return errors.New("from must be before to")
+	}
+
+	tsdbPath := r.BackfillTSDBPath
+	if tsdbPath == "" {
+		tsdbPath, err = os.MkdirTemp("", "mimirtool-rules-backfill")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tsdbPath)
+	} else if err := os.MkdirAll(tsdbPath, 0755); err != nil {
+		return err
+	}
+
+	blocks, err := r.backfillRuleGroupBlocks(context.Background(), from, to, tsdbPath)
+	if err != nil {
+		return errors.Wrap(err, "backfill operation unsuccessful")
+	}
+	if len(blocks) == 0 {
+		log.Infof("the rule group recorded no series in the time range, nothing to upload")
+		return nil
+	}
+
+	return r.cli.Backfill(context.Background(), blocks, r.BackfillSleepTime)
+}
+
+// backfillRuleGroupBlocks evaluates the rule group over the time range, one block range at a time, writes the
+// recorded series as TSDB blocks in tsdbPath, and returns the directories of the blocks written.
+func (r *RuleCommand) backfillRuleGroupBlocks(ctx context.Context, from, to time.Time, tsdbPath string) ([]string, error) {
+	series := map[model.Fingerprint]*model.SampleStream{}
+	blockRange := time.Duration(tsdb.DefaultBlockDuration) * time.Millisecond
+	for start := from; !start.After(to); start = start.Add(blockRange) {
+		end := start.Add(blockRange - time.Millisecond)
+		if end.After(to) {
+			end = to
+		}
+
+		log.WithFields(log.Fields{
+			"namespace": r.Namespace,
+			"group":     r.RuleGroup,
+			"from":      start.Format(time.RFC3339),
+			"to":        end.Format(time.RFC3339),
+		}).Infof("evaluating rule group")
+
+		matrix, err := r.cli.BackfillRuleGroup(ctx, r.Namespace, r.RuleGroup, start, end, r.BackfillSleepTime)
+		if err != nil {
+			return nil, err
+		}
+		for _, stream := range matrix {
+			fp := stream.Metric.Fingerprint()
+			if existing, ok := series[fp]; ok {
+				existing.Values = append(existing.Values, stream.Values...)
+				continue
+			}
+			series[fp] = stream
+		}
+	}
+	if len(series) == 0 {
+		return nil, nil
+	}
+
+	matrix := make(model.Matrix, 0, len(series))
+	for _, stream := range series {
+		matrix = append(matrix, stream)
+	}
+
+	existingBlocks, err := listBlocks(tsdbPath)
+	if err != nil {
+		return nil, err
+	}
+
+	iteratorCreator := func() backfill.Iterator {
+		return newMatrixIterator(matrix)
+	}
+	if err := backfill.CreateBlocks(iteratorCreator, from.UnixMilli(), to.UnixMilli(), 1000, tsdbPath, true, os.Stdout); err != nil {
+		return nil, err
+	}
+
+	allBlocks, err := listBlocks(tsdbPath)
+	if err != nil {
+		return nil, err
+	}
+	var blocks []string
+	for _, b := range allBlocks {
+		if !slices.Contains(existingBlocks, b) {
+			blocks = append(blocks, b)
+		}
+	}
+	return blocks, nil
+}
+
+// listBlocks returns the directories of the TSDB blocks in dir.
+func listBlocks(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var blocks []string
+	for _, entry := range entries {
+		if _, err := ulid.Parse(entry.Name()); err != nil || !entry.IsDir() {
+			continue
+		}
+		blocks = append(blocks, filepath.Join(dir, entry.Name()))
+	}
+	return blocks, nil
+}
+
+// matrixIterator iterates over the samples of a matrix, series by series.
+type matrixIterator struct {
+	matrix model.Matrix
+	series int
+	sample int
+	labels labels.Labels
+}
+
+func newMatrixIterator(matrix model.Matrix) *matrixIterator {
+	return &matrixIterator{matrix: matrix, sample: -1}
+}
+
+func (i *matrixIterator) Next() error {
+	for i.series < len(i.matrix) {
+		if i.sample+1 < len(i.matrix[i.series].Values) {
+			i.sample++
+			if i.sample == 0 {
+				i.labels = labelsFromMetric(i.matrix[i.series].Metric)
+			}
+			return nil
+		}
+		i.series++
+		i.sample = -1
+	}
+	return io.EOF
+}
+
+func (i *matrixIterator) Sample() (int64, float64) {
+	s := i.matrix[i.series].Values[i.sample]
+	return int64(s.Timestamp), float64(s.Value)
+}
+
+func (i *matrixIterator) Labels() labels.Labels {
+	return i.labels
+}
+
+func labelsFromMetric(metric model.Metric) labels.Labels {
+	b := labels.NewScratchBuilder(len(metric))
+	for name, value := range metric {
+		b.Add(string(name), string(value))
+	}
+	b.Sort()
+	return b.Labels()
+}
@@ -11,7 +11,9 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/rulefmt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -20,6 +22,7 @@ import (
 
 	"github.com/grafana/mimir/pkg/mimirtool/rules"
 	"github.com/grafana/mimir/pkg/mimirtool/rules/rwrulefmt"
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
 )
 
 func TestRuleCommand_executeChanges(t *testing.T) {
@@ -212,6 +215,52 @@ func TestRuleCommand_checkRules(t *testing.T) {
 	}
 }
 
+func TestRuleCommand_backfillRuleGroupBlocks(t *testing.T) {
+	from := time.Unix(0, 0).UTC()
+	to := from.Add(3 * time.Hour)
+	metric := model.Metric{"__name__": "job:up:sum", "job": "api"}
+
+	samples := func(start, end time.Duration) []model.SamplePair {
+		var values []model.SamplePair
+		for ts := start; ts <= end; ts += 30 * time.Minute {
+			values = append(values, model.SamplePair{Timestamp: model.Time(ts.Milliseconds()), Value: 1})
+		}
+		return values
+	}
+
+	cli := newRuleCommandClientMock()
+	// The time range is evaluated one block range at a time.
+	cli.On("BackfillRuleGroup", mock.Anything, "namespace-1", "group-1", from, from.Add(2*time.Hour-time.Millisecond), time.Second).
+		Return(model.Matrix{{Metric: metric, Values: samples(0, 90*time.Minute)}}, nil)
+	cli.On("BackfillRuleGroup", mock.Anything, "namespace-1", "group-1", from.Add(2*time.Hour), to, time.Second).
+		Return(model.Matrix{{Metric: metric, Values: samples(2*time.Hour, 3*time.Hour)}}, nil)
+
+	r := RuleCommand{cli: cli, Namespace: "namespace-1", RuleGroup: "group-1", BackfillSleepTime: time.Second}
+	tsdbPath := t.TempDir()
+	blocks, err := r.backfillRuleGroupBlocks(context.Background(), from, to, tsdbPath)
+	require.NoError(t, err)
+	cli.AssertNumberOfCalls(t, "BackfillRuleGroup", 2)
+
+	// One block is created for each hour.
+	require.Len(t, blocks, 4)
+	var numSamples uint64
+	for _, b := range blocks {
+		meta, err := block.ReadMetaFromDir(b)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), meta.Stats.NumSeries)
+		numSamples += meta.Stats.NumSamples
+	}
+	assert.Equal(t, uint64(7), numSamples)
+
+	// The blocks already present in the TSDB path aren't returned.
+	blocks, err = r.backfillRuleGroupBlocks(context.Background(), from, to, tsdbPath)
+	require.NoError(t, err)
+	require.Len(t, blocks, 4)
+	allBlocks, err := listBlocks(tsdbPath)
+	require.NoError(t, err)
+	require.Len(t, allBlocks, 8)
+}
+
 type ruleCommandClientMock struct {
 	mock.Mock
 }
@@ -244,3 +293,13 @@ func (m *ruleCommandClientMock) DeleteNamespace(ctx context.Context, namespace s
 	args := m.Called(ctx, namespace)
 	return args.Error(0)
 }
+
+func (m *ruleCommandClientMock) BackfillRuleGroup(ctx context.Context, namespace, groupName string, start, end time.Time, sleepTime time.Duration) (model.Matrix, error) {
+	args := m.Called(ctx, namespace, groupName, start, end, sleepTime)
+	return args.Get(0).(model.Matrix), args.Error(1)
+}
+
+func (m *ruleCommandClientMock) Backfill(ctx context.Context, blocks []string, sleepTime time.Duration) error {
+	args := m.Called(ctx, blocks, sleepTime)
+	return args.Error(0)
+}
@@ -0,0 +1,537 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+// Provenance-includes-location: https://github.com/prometheus/prometheus/blob/main/cmd/promtool/unittest.go
+// Provenance-includes-license: Apache-2.0
+// Provenance-includes-copyright: The Prometheus Authors.
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alecthomas/kingpin/v2"
+	gokitlog "github.com/go-kit/log"
+	"github.com/grafana/dskit/tenant"
+	"github.com/grafana/dskit/user"
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+	promRules "github.com/prometheus/prometheus/rules"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/util/annotations"
+	log "github.com/sirupsen/logrus"
+	yamlv3 "gopkg.in/yaml.v3"
+
+	"github.com/grafana/mimir/pkg/mimirtool/rules"
+	"github.com/grafana/mimir/pkg/querier/tenantfederation"
+	"github.com/grafana/mimir/pkg/ruler"
+)
+
+const (
+	// defaultRulesTestTenant is the tenant owning the rules when a test group doesn't set one.
+	defaultRulesTestTenant = "anonymous"
+
+	// rulesTestFederationConcurrency is the number of tenants queried concurrently by federated rule groups.
+	rulesTestFederationConcurrency = 16
+)
+
+// rulesUnitTestFile is a rules unit test file. It's the promtool unit test file format,
+// with the Grafana Mimir specific evaluation_delay and tenant fields.
+type rulesUnitTestFile struct {
+	RuleFiles          []string         `yaml:"rule_files"`
+	EvaluationInterval model.Duration   `yaml:"evaluation_interval,omitempty"`
+	EvaluationDelay    model.Duration   `yaml:"evaluation_delay,omitempty"`
+	GroupEvalOrder     []string         `yaml:"group_eval_order"`
+	Tests              []rulesTestGroup `yaml:"tests"`
+}
+
+// rulesTestGroup is a group of tests sharing the same input series.
+type rulesTestGroup struct {
+	Name            string                `yaml:"name,omitempty"`
+	Tenant          string                `yaml:"tenant,omitempty"`
+	Interval        model.Duration        `yaml:"interval"`
+	InputSeries     []rulesTestSeries     `yaml:"input_series"`
+	AlertRuleTests  []rulesTestAlertCase  `yaml:"alert_rule_test,omitempty"`
+	PromQLExprTests []rulesTestPromQLCase `yaml:"promql_expr_test,omitempty"`
+	ExternalLabels  map[string]string     `yaml:"external_labels,omitempty"`
+	ExternalURL     string                `yaml:"external_url,omitempty"`
+}
+
+type rulesTestSeries struct {
+	Series string `yaml:"series"`
+	Values string `yaml:"values"`
+	// Tenant is the tenant the series belongs to, defaulting to the tenant of the test group.
+	Tenant string `yaml:"tenant,omitempty"`
+}
+
+type rulesTestAlertCase struct {
+	EvalTime  model.Duration      `yaml:"eval_time"`
+	Alertname string              `yaml:"alertname"`
+	ExpAlerts []rulesTestExpAlert `yaml:"exp_alerts"`
+}
+
+type rulesTestExpAlert struct {
+	ExpLabels      map[string]string `yaml:"exp_labels"`
+	ExpAnnotations map[string]string `yaml:"exp_annotations"`
+}
+
+type rulesTestPromQLCase struct {
+	Expr       string               `yaml:"expr"`
+	EvalTime   model.Duration       `yaml:"eval_time"`
+	ExpSamples []rulesTestExpSample `yaml:"exp_samples"`
+}
+
+type rulesTestExpSample struct {
+	Labels string  `yaml:"labels"`
+	Value  float64 `yaml:"value"`
+}
+
+func (r *RuleCommand) testRules(_ *kingpin.ParseContext) error {
+	failed := 0
+	for _, file := range r.TestFilesList {
+		errs := runRulesUnitTestFile(file)
+		if len(errs) == 0 {
+			log.WithField("file", file).Infoln("SUCCESS")
+			continue
+		}
+
+		failed++
+		for _, err := range errs {
+			log.WithField("file", file).Errorln(err)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d rules unit test files failed", failed, len(r.TestFilesList))
+	}
+	return nil
+}
+
+// runRulesUnitTestFile runs the tests of a rules unit test file, and returns the failures.
+func runRulesUnitTestFile(filename string) []error {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return []error{err}
+	}
+
+	var testFile rulesUnitTestFile
+	decoder := yamlv3.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&testFile); err != nil {
+		return []error{errors.Wrap(err, "unable to parse the test file")}
+	}
+
+	evalInterval := time.Minute
+	if testFile.EvaluationInterval > 0 {
+		evalInterval = time.Duration(testFile.EvaluationInterval)
+	}
+
+	ruleFiles, err := resolveRuleFiles(filepath.Dir(filename), testFile.RuleFiles)
+	if err != nil {
+		return []error{err}
+	}
+	namespaces, err := rules.ParseFiles(rules.MimirBackend, ruleFiles)
+	if err != nil {
+		return []error{errors.Wrap(err, "unable to parse the rule files")}
+	}
+
+	groupOrder := make(map[string]int, len(testFile.GroupEvalOrder))
+	for i, name := range testFile.GroupEvalOrder {
+		if _, ok := groupOrder[name]; ok {
+			return []error{fmt.Errorf("group name repeated in group_eval_order: %s", name)}
+		}
+		groupOrder[name] = i
+	}
+
+	var errs []error
+	for _, tg := range testFile.Tests {
+		for _, err := range tg.test(namespaces, evalInterval, time.Duration(testFile.EvaluationDelay), groupOrder) {
+			if tg.Name != "" {
+				err = errors.Wrapf(err, "test group %s", tg.Name)
+			}
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// resolveRuleFiles resolves the rule files relative to the directory of the test file, expanding the globs.
+func resolveRuleFiles(dir string, patterns []string) ([]string, error) {
+	var files []string
+	for _, pattern := range patterns {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(dir, pattern)
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no rule file matches %s", pattern)
+		}
+		files = append(files, matches...)
+	}
+	return files, nil
+}
+
+// test runs the tests of the test group, evaluating the rule groups as the Grafana Mimir ruler does
+// for the tenant of the test group.
+func (tg *rulesTestGroup) test(namespaces map[string]rules.RuleNamespace, evalInterval, evalDelay time.Duration, groupOrder map[string]int) []error {
+	ruleTenant := tg.Tenant
+	if ruleTenant == "" {
+		ruleTenant = defaultRulesTestTenant
+	}
+	if tg.Interval == 0 {
+		tg.Interval = model.Duration(evalInterval)
+	}
+
+	// Each tenant has its own storage, loaded with the input series of the tenant.
+	inputSeries := map[string]*strings.Builder{ruleTenant: {}}
+	for _, s := range tg.InputSeries {
+		tenantID := s.Tenant
+		if tenantID == "" {
+			tenantID = ruleTenant
+		}
+		if inputSeries[tenantID] == nil {
+			inputSeries[tenantID] = &strings.Builder{}
+		}
+		fmt.Fprintf(inputSeries[tenantID], "%s %s\n", s.Series, s.Values)
+	}
+
+	loaders := make(map[string]*promql.LazyLoader, len(inputSeries))
+	queryable := make(tenantQueryable, len(inputSeries))
+	defer func() {
+		for _, loader := range loaders {
+			loader.Close()
+		}
+	}()
+	for tenantID, series := range inputSeries {
+		loader, err := promql.NewLazyLoader(rulesTestT{}, fmt.Sprintf("load %s\n%s", tg.Interval, series), promql.LazyLoaderOpts{
+			EnableAtModifier:     true,
+			EnableNegativeOffset: true,
+		})
+		// The storage is created even if the input series are invalid.
+		loaders[tenantID] = loader
+		if err != nil {
+			return []error{errors.Wrapf(err, "unable to parse the input series of tenant %s", tenantID)}
+		}
+		queryable[tenantID] = loader.Storage()
+	}
+
+	engine := promql.NewEngine(promql.EngineOpts{
+		MaxSamples:               50000000,
+		Timeout:                  2 * time.Minute,
+		EnableAtModifier:         true,
+		EnableNegativeOffset:     true,
+		NoStepSubqueryIntervalFn: func(int64) int64 { return evalInterval.Milliseconds() },
+	})
+
+	// The rule groups with source tenants query the tenants through tenant federation, like the ruler does.
+	federatedQueryable := tenantfederation.NewQueryable(queryable, false, rulesTestFederationConcurrency, nil, gokitlog.NewNopLogger())
+	queryFunc := ruler.TenantFederationQueryFunc(promRules.EngineQueryFunc(engine, queryable), promRules.EngineQueryFunc(engine, federatedQueryable))
+
+	ctx := user.InjectOrgID(context.Background(), ruleTenant)
+	manager := promRules.NewManager(&promRules.ManagerOptions{
+		QueryFunc:   queryFunc,
+		NotifyFunc:  func(context.Context, string, ...*promRules.Alert) {},
+		Context:     ctx,
+		Appendable:  loaders[ruleTenant].Storage(),
+		Queryable:   queryable,
+		Logger:      gokitlog.NewNopLogger(),
+		GroupLoader: rulesTestGroupLoader(namespaces),
+		DefaultEvaluationDelay: func() time.Duration {
+			return evalDelay
+		},
+	})
+
+	namespaceNames := make([]string, 0, len(namespaces))
+	for name := range namespaces {
+		namespaceNames = append(namespaceNames, name)
+	}
+	slices.Sort(namespaceNames)
+
+	groupsMap, errs := manager.LoadGroups(evalInterval, labels.FromMap(tg.ExternalLabels), tg.ExternalURL, nil, namespaceNames...)
+	if len(errs) > 0 {
+		return errs
+	}
+	groups := orderedRuleGroups(groupsMap, groupOrder)
+
+	// The alert tests are checked after the evaluation preceding their evaluation time.
+	var maxEvalTime time.Duration
+	alertTests := map[time.Duration][]rulesTestAlertCase{}
+	var alertEvalTimes []time.Duration
+	for _, tc := range tg.AlertRuleTests {
+		evalTime := time.Duration(tc.EvalTime)
+		if _, ok := alertTests[evalTime]; !ok {
+			alertEvalTimes = append(alertEvalTimes, evalTime)
+		}
+		alertTests[evalTime] = append(alertTests[evalTime], tc)
+		maxEvalTime = max(maxEvalTime, evalTime)
+	}
+	slices.Sort(alertEvalTimes)
+	for _, tc := range tg.PromQLExprTests {
+		maxEvalTime = max(maxEvalTime, time.Duration(tc.EvalTime))
+	}
+
+	mint := time.Unix(0, 0).UTC()
+	maxt := mint.Add(maxEvalTime)
+
+	var failures []error
+	next := 0
+	for ts := mint; !ts.After(maxt); ts = ts.Add(evalInterval) {
+		for tenantID, loader := range loaders {
+			loader.WithSamplesTill(ts, func(err error) {
+				if err != nil {
+					errs = append(errs, errors.Wrapf(err, "unable to load the input series of tenant %s", tenantID))
+				}
+			})
+		}
+		if len(errs) > 0 {
+			return errs
+		}
+
+		for _, g := range groups {
+			g.Eval(ruler.FederatedGroupContextFunc(ctx, g), ts)
+			for _, r := range g.Rules() {
+				if r.LastError() != nil {
+					errs = append(errs, fmt.Errorf("rule: %s, time: %s, err: %v", r.Name(), ts.Sub(mint), r.LastError()))
+				}
+			}
+		}
+		if len(errs) > 0 {
+			return errs
+		}
+
+		// Check the alert tests whose evaluation time is between this evaluation and the next one.
+		for ; next < len(alertEvalTimes) && alertEvalTimes[next] < ts.Add(evalInterval).Sub(mint); next++ {
+			evalTime := alertEvalTimes[next]
+			if evalTime < ts.Sub(mint) {
+				continue
+			}
+			for _, tc := range alertTests[evalTime] {
+				if err := tc.check(groups, evalTime); err != nil {
+					failures = append(failures, err)
+				}
+			}
+		}
+	}
+
+	for _, tc := range tg.PromQLExprTests {
+		if err := tc.check(ctx, queryFunc, mint); err != nil {
+			failures = append(failures, err)
+		}
+	}
+	return failures
+}
+
+// check compares the firing alerts of the alerting rules named after the alertname of the test case with the expected alerts.
+func (tc *rulesTestAlertCase) check(groups []*promRules.Group, evalTime time.Duration) error {
+	var got []string
+	for _, g := range groups {
+		for _, r := range g.Rules() {
+			ar, ok := r.(*promRules.AlertingRule)
+			if !ok || ar.Name() != tc.Alertname {
+				continue
+			}
+			for _, a := range ar.ActiveAlerts() {
+				if a.State == promRules.StateFiring {
+					got = append(got, formatRulesTestAlert(a.Labels, a.Annotations))
+				}
+			}
+		}
+	}
+
+	exp := make([]string, 0, len(tc.ExpAlerts))
+	for _, a := range tc.ExpAlerts {
+		lbls := labels.NewBuilder(labels.FromMap(a.ExpLabels)).Set(labels.AlertName, tc.Alertname).Labels()
+		exp = append(exp, formatRulesTestAlert(lbls, labels.FromMap(a.ExpAnnotations)))
+	}
+
+	sort.Strings(got)
+	sort.Strings(exp)
+	if !slices.Equal(exp, got) {
+		return fmt.Errorf("alertname: %s, time: %s, exp: %v, got: %v", tc.Alertname, evalTime, exp, got)
+	}
+	return nil
+}
+
+func formatRulesTestAlert(lbls, annotations labels.Labels) string {
+	return fmt.Sprintf("Labels:%s Annotations:%s", lbls, annotations)
+}
+
+// check runs the expression of the test case at its evaluation time, as the tenant of the test group, and compares the result with the expected samples.
+func (tc *rulesTestPromQLCase) check(ctx context.Context, queryFunc promRules.QueryFunc, mint time.Time) error {
+	evalTime := time.Duration(tc.EvalTime)
+	vector, err := queryFunc(ctx, tc.Expr, mint.Add(evalTime))
+	if err != nil {
+		return fmt.Errorf("expr: %q, time: %s, err: %v", tc.Expr, evalTime, err)
+	}
+
+	got := make([]string, 0, len(vector))
+	for _, s := range vector {
+		if s.H != nil {
+			got = append(got, fmt.Sprintf("%s %s", s.Metric, s.H))
+			continue
+		}
+		got = append(got, fmt.Sprintf("%s %g", s.Metric, s.F))
+	}
+
+	exp := make([]string, 0, len(tc.ExpSamples))
+	for _, s := range tc.ExpSamples {
+		lbls, err := parser.ParseMetric(s.Labels)
+		if err != nil {
+			return fmt.Errorf("expr: %q, time: %s, err: unable to parse the labels %q: %v", tc.Expr, evalTime, s.Labels, err)
+		}
+		exp = append(exp, fmt.Sprintf("%s %g", lbls, s.Value))
+	}
+
+	sort.Strings(got)
+	sort.Strings(exp)
+	if !slices.Equal(exp, got) {
+		return fmt.Errorf("expr: %q, time: %s, exp: %v, got: %v", tc.Expr, evalTime, exp, got)
+	}
+	return nil
+}
+
+// orderedRuleGroups returns the rule groups in the group_eval_order first, and then the other rule groups
+// sorted by namespace and name.
+func orderedRuleGroups(groupsMap map[string]*promRules.Group, groupOrder map[string]int) []*promRules.Group {
+	groups := make([]*promRules.Group, 0, len(groupsMap))
+	for _, g := range groupsMap {
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		iOrder, iOrdered := groupOrder[groups[i].Name()]
+		jOrder, jOrdered := groupOrder[groups[j].Name()]
+		switch {
+		case iOrdered && jOrdered:
+			return iOrder < jOrder
+		case iOrdered != jOrdered:
+			return iOrdered
+		case groups[i].File() != groups[j].File():
+			return groups[i].File() < groups[j].File()
+		default:
+			return groups[i].Name() < groups[j].Name()
+		}
+	})
+	return groups
+}
+
+// rulesTestGroupLoader is a rules.GroupLoader loading the rule groups of the namespaces, identified by their name.
+type rulesTestGroupLoader map[string]rules.RuleNamespace
+
+func (l rulesTestGroupLoader) Load(identifier string) (*rulefmt.RuleGroups, []error) {
+	ns, ok := l[identifier]
+	if !ok {
+		return nil, []error{fmt.Errorf("namespace %s not found", identifier)}
+	}
+
+	groups := &rulefmt.RuleGroups{Groups: make([]rulefmt.RuleGroup, 0, len(ns.Groups))}
+	for _, g := range ns.Groups {
+		groups.Groups = append(groups.Groups, g.RuleGroup)
+	}
+	return groups, nil
+}
+
+func (rulesTestGroupLoader) Parse(query string) (parser.Expr, error) {
+	return parser.ParseExpr(query)
+}
+
+// rulesTestT is the testutil.T of the lazy loaders, which only use it to report storage failures.
+type rulesTestT struct{}
+
+func (rulesTestT) Errorf(format string, args ...interface{}) {
+	log.Errorf(format, args...)
+}
+
+func (rulesTestT) FailNow() {
+	log.Fatalln("unable to run the rules unit tests")
+}
+
+// tenantQueryable is a storage.Queryable querying the queryable of the tenant in the context.
+type tenantQueryable map[string]storage.Queryable
+
+func (q tenantQueryable) Querier(mint, maxt int64) (storage.Querier, error) {
+	return &tenantQuerier{queryables: q, mint: mint, maxt: maxt, queriers: map[string]storage.Querier{}}, nil
+}
+
+type tenantQuerier struct {
+	queryables tenantQueryable
+	mint, maxt int64
+
+	mtx      sync.Mutex
+	queriers map[string]storage.Querier
+}
+
+// querier returns the querier of the tenant in the context. Tenants without input series have no data.
+func (q *tenantQuerier) querier(ctx context.Context) (storage.Querier, error) {
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	if querier, ok := q.queriers[tenantID]; ok {
+		return querier, nil
+	}
+	queryable, ok := q.queryables[tenantID]
+	if !ok {
+		return storage.NoopQuerier(), nil
+	}
+	querier, err := queryable.Querier(q.mint, q.maxt)
+	if err != nil {
+		return nil, err
+	}
+	q.queriers[tenantID] = querier
+	return querier, nil
+}
+
+func (q *tenantQuerier) Select(ctx context.Context, sortSeries bool, hints *storage.SelectHints, matchers ...*labels.Matcher) storage.SeriesSet {
+	querier, err := q.querier(ctx)
+	if err != nil {
+		return storage.ErrSeriesSet(err)
+	}
+	return querier.Select(ctx, sortSeries, hints, matchers...)
+}
+
+func (q *tenantQuerier) LabelValues(ctx context.Context, name string, matchers ...*labels.Matcher) ([]string, annotations.Annotations, error) {
+	querier, err := q.querier(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return querier.LabelValues(ctx, name, matchers...)
+}
+
+func (q *tenantQuerier) LabelNames(ctx context.Context, matchers ...*labels.Matcher) ([]string, annotations.Annotations, error) {
+	querier, err := q.querier(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return querier.LabelNames(ctx, matchers...)
+}
+
+func (q *tenantQuerier) Close() error {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	var lastErr error
+	for _, querier := range q.queriers {
+		if err := querier.Close(); err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
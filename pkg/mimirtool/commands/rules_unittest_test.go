@@ -0,0 +1,35 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunRulesUnitTestFile(t *testing.T) {
+	t.Run("passing tests", func(t *testing.T) {
+		assert.Empty(t, runRulesUnitTestFile("testdata/rules_unittest/pass.yaml"))
+	})
+
+	t.Run("default evaluation delay", func(t *testing.T) {
+		assert.Empty(t, runRulesUnitTestFile("testdata/rules_unittest/evaluation_delay.yaml"))
+	})
+
+	t.Run("failing tests", func(t *testing.T) {
+		errs := runRulesUnitTestFile("testdata/rules_unittest/fail.yaml")
+		require.Len(t, errs, 2)
+		assert.Contains(t, errs[0].Error(), "test group failing")
+		assert.Contains(t, errs[0].Error(), "alertname: InstanceDown, time: 1m0s")
+		assert.Contains(t, errs[1].Error(), `expr: "job:up:sum", time: 5m0s`)
+		assert.Contains(t, errs[1].Error(), `got: [{__name__="job:up:sum", job="api"} 0]`)
+	})
+
+	t.Run("invalid test file", func(t *testing.T) {
+		errs := runRulesUnitTestFile("testdata/rules_unittest/rules.yaml")
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "unable to parse the test file")
+	})
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/thanos-io/objstore"
 
 	"github.com/grafana/mimir/pkg/storage/bucket"
+	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
 	"github.com/grafana/mimir/pkg/storage/tsdb/bucketindex"
 	"github.com/grafana/mimir/pkg/util/globalerror"
 )
@@ -115,6 +116,33 @@ func (f *BucketIndexBlocksFinder) GetBlocks(ctx context.Context, userID string,
 	return blocks, matchingDeletionMarks, nil
 }
 
+// GetSeriesDeletionRequests implements SeriesDeletionRequestsFinder. The requests processed by the compactor are
+// returned until the blocks it marked for deletion while purging the deleted samples are no longer queried.
+func (f *BucketIndexBlocksFinder) GetSeriesDeletionRequests(ctx context.Context, userID string) ([]*mimir_tsdb.SeriesDeletionRequest, error) {
+	if f.State() != services.Running {
+		return nil, errBucketIndexBlocksFinderNotRunning
+	}
+
+	idx, err := f.loader.GetIndex(ctx, userID)
+	if errors.Is(err, bucketindex.ErrIndexNotFound) {
+		// The bucket index hasn't been created yet, so there can't be any series deletion request in it.
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	requests := make([]*mimir_tsdb.SeriesDeletionRequest, 0, len(idx.SeriesDeletionRequests))
+	for _, req := range idx.SeriesDeletionRequests {
+		if req.Processed() && time.Since(req.ProcessedAt.Time()) > f.cfg.IgnoreDeletionMarksDelay {
+			continue
+		}
+		requests = append(requests, req)
+	}
+
+	return requests, nil
+}
+
 func newBucketIndexTooOldError(updatedAt time.Time, maxStalePeriod time.Duration) error {
 	return errors.New(globalerror.BucketIndexTooOld.Message(fmt.Sprintf("the bucket index is too old. It was last updated at %s, which exceeds the maximum allowed staleness period of %v", updatedAt.UTC().Format(time.RFC3339Nano), maxStalePeriod)))
 }
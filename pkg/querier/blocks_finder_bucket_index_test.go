@@ -19,8 +19,10 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/thanos-io/objstore"
 
+	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
 	"github.com/grafana/mimir/pkg/storage/tsdb/bucketindex"
 	mimir_testutil "github.com/grafana/mimir/pkg/storage/tsdb/testutil"
+	"github.com/grafana/mimir/pkg/util"
 )
 
 func TestBucketIndexBlocksFinder_GetBlocks(t *testing.T) {
@@ -204,6 +206,36 @@ func TestBucketIndexBlocksFinder_GetBlocks_BucketIndexIsTooOld(t *testing.T) {
 	require.EqualError(t, err, newBucketIndexTooOldError(idx.GetUpdatedAt(), finder.cfg.MaxStalePeriod).Error())
 }
 
+func TestBucketIndexBlocksFinder_GetSeriesDeletionRequests(t *testing.T) {
+	const userID = "user-1"
+
+	ctx := context.Background()
+	bkt, _ := mimir_testutil.PrepareFilesystemBucket(t)
+	finder := prepareBucketIndexBlocksFinder(t, bkt)
+
+	t.Run("no bucket index", func(t *testing.T) {
+		requests, err := finder.GetSeriesDeletionRequests(ctx, "user-without-index")
+		require.NoError(t, err)
+		assert.Empty(t, requests)
+	})
+
+	t.Run("processed requests are returned until the replaced blocks are no longer queried", func(t *testing.T) {
+		pending := &mimir_tsdb.SeriesDeletionRequest{RequestID: "pending", Selectors: []string{`{job="a"}`}, StartTime: 10, EndTime: 20}
+		recentlyProcessed := &mimir_tsdb.SeriesDeletionRequest{RequestID: "recently-processed", Selectors: []string{`{job="b"}`}, StartTime: 10, EndTime: 20, ProcessedAt: util.UnixSecondsFromTime(time.Now().Add(-time.Minute))}
+		processed := &mimir_tsdb.SeriesDeletionRequest{RequestID: "processed", Selectors: []string{`{job="c"}`}, StartTime: 10, EndTime: 20, ProcessedAt: util.UnixSecondsFromTime(time.Now().Add(-2 * time.Hour))}
+
+		require.NoError(t, bucketindex.WriteIndex(ctx, bkt, userID, nil, &bucketindex.Index{
+			Version:                bucketindex.IndexVersion1,
+			SeriesDeletionRequests: []*mimir_tsdb.SeriesDeletionRequest{pending, recentlyProcessed, processed},
+			UpdatedAt:              time.Now().Unix(),
+		}))
+
+		requests, err := finder.GetSeriesDeletionRequests(ctx, userID)
+		require.NoError(t, err)
+		assert.Equal(t, []*mimir_tsdb.SeriesDeletionRequest{pending, recentlyProcessed}, requests)
+	})
+}
+
 func prepareBucketIndexBlocksFinder(t testing.TB, bkt objstore.Bucket) *BucketIndexBlocksFinder {
 	ctx := context.Background()
 	cfg := BucketIndexBlocksFinderConfig{
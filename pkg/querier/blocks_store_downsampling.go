@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querier
+
+import (
+	"sort"
+
+	"github.com/oklog/ulid"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+
+	"github.com/grafana/mimir/pkg/storage/tsdb/bucketindex"
+	"github.com/grafana/mimir/pkg/storage/tsdb/downsample"
+)
+
+// downsamplingMinSamplesPerWindow is the minimum number of downsampled samples required in the query step
+// and in the range of the function to query the blocks of a downsampling resolution.
+const downsamplingMinSamplesPerWindow = 5
+
+// downsamplingAggrByFunc are the functions whose result can be computed from the samples of a downsampled
+// aggregate, instead of the raw samples.
+var downsamplingAggrByFunc = map[string]downsample.AggrType{
+	"rate":          downsample.AggrCounter,
+	"increase":      downsample.AggrCounter,
+	"resets":        downsample.AggrCounter,
+	"min_over_time": downsample.AggrMin,
+	"max_over_time": downsample.AggrMax,
+	"sum_over_time": downsample.AggrSum,
+}
+
+// downsamplingForHints returns the coarsest downsampling resolution satisfying the step and the range of the query,
+// and the aggregate to query from the downsampled blocks. The resolution is downsample.ResLevel0 if the query
+// requires the raw samples.
+func downsamplingForHints(sp *storage.SelectHints) (int64, downsample.AggrType) {
+	if sp == nil {
+		return downsample.ResLevel0, 0
+	}
+	aggr, ok := downsamplingAggrByFunc[sp.Func]
+	if !ok {
+		return downsample.ResLevel0, 0
+	}
+
+	for _, resolution := range []int64{downsample.ResLevel2, downsample.ResLevel1} {
+		if sp.Step >= downsamplingMinSamplesPerWindow*resolution && sp.Range >= downsamplingMinSamplesPerWindow*resolution {
+			return resolution, aggr
+		}
+	}
+	return downsample.ResLevel0, 0
+}
+
+// selectBlocksByResolution returns the blocks covering the time range with the coarsest resolution up to
+// maxResolution, falling back to the finer resolutions where the coarser ones have gaps. The blocks are
+// selected separately for each compactor shard, because the blocks of different shards contain different series.
+func selectBlocksByResolution(blocks bucketindex.Blocks, minT, maxT, maxResolution int64) bucketindex.Blocks {
+	// The raw blocks are returned as is if there are no downsampled blocks to query.
+	raw := make(bucketindex.Blocks, 0, len(blocks))
+	for _, b := range blocks {
+		if b.Resolution == downsample.ResLevel0 {
+			raw = append(raw, b)
+		}
+	}
+	if maxResolution == downsample.ResLevel0 || len(raw) == len(blocks) {
+		return raw
+	}
+
+	byShard := map[string]map[int64]bucketindex.Blocks{}
+	for _, b := range blocks {
+		if b.Resolution > maxResolution {
+			continue
+		}
+		if byShard[b.CompactorShardID] == nil {
+			byShard[b.CompactorShardID] = map[int64]bucketindex.Blocks{}
+		}
+		byShard[b.CompactorShardID][b.Resolution] = append(byShard[b.CompactorShardID][b.Resolution], b)
+	}
+
+	var result bucketindex.Blocks
+	for _, byResolution := range byShard {
+		resolutions := make([]int64, 0, len(byResolution))
+		for resolution, blocks := range byResolution {
+			resolutions = append(resolutions, resolution)
+			sort.Slice(blocks, func(i, j int) bool {
+				return blocks[i].MinTime < blocks[j].MinTime
+			})
+		}
+		sort.Slice(resolutions, func(i, j int) bool {
+			return resolutions[i] > resolutions[j]
+		})
+
+		result = append(result, blocksForResolutions(byResolution, resolutions, minT, maxT)...)
+	}
+	return result
+}
+
+// blocksForResolutions returns the blocks of the first resolution within the time range, and fills the gaps
+// with the blocks of the next resolutions.
+func blocksForResolutions(byResolution map[int64]bucketindex.Blocks, resolutions []int64, minT, maxT int64) bucketindex.Blocks {
+	if minT > maxT || len(resolutions) == 0 {
+		return nil
+	}
+
+	var result bucketindex.Blocks
+	start := minT
+	for _, b := range byResolution[resolutions[0]] {
+		if b.MaxTime <= minT {
+			continue
+		}
+		if b.MinTime > maxT {
+			break
+		}
+		result = append(result, blocksForResolutions(byResolution, resolutions[1:], start, b.MinTime-1)...)
+		result = append(result, b)
+		start = max(start, b.MaxTime)
+	}
+	return append(result, blocksForResolutions(byResolution, resolutions[1:], start, maxT)...)
+}
+
+// splitClientsByDownsampledBlocks splits the blocks to query from each client between the raw blocks and the
+// downsampled blocks, because they need to be queried with different matchers.
+func splitClientsByDownsampledBlocks(clients map[BlocksStoreClient][]ulid.ULID, downsampledBlocks map[ulid.ULID]struct{}) (raw, downsampled map[BlocksStoreClient][]ulid.ULID) {
+	if len(downsampledBlocks) == 0 {
+		return clients, nil
+	}
+
+	raw = map[BlocksStoreClient][]ulid.ULID{}
+	downsampled = map[BlocksStoreClient][]ulid.ULID{}
+	for c, blockIDs := range clients {
+		for _, id := range blockIDs {
+			if _, ok := downsampledBlocks[id]; ok {
+				downsampled[c] = append(downsampled[c], id)
+			} else {
+				raw[c] = append(raw[c], id)
+			}
+		}
+	}
+	return raw, downsampled
+}
+
+// withoutAggrLabelSeriesSet wraps a storage.SeriesSet of downsampled series and removes the aggregate label
+// from the series, so that they're merged with the raw series. The series are still sorted, because all the
+// series of the set have the same aggregate.
+type withoutAggrLabelSeriesSet struct {
+	storage.SeriesSet
+}
+
+func (s withoutAggrLabelSeriesSet) At() storage.Series {
+	return withoutAggrLabelSeries{Series: s.SeriesSet.At()}
+}
+
+type withoutAggrLabelSeries struct {
+	storage.Series
+}
+
+func (s withoutAggrLabelSeries) Labels() labels.Labels {
+	return downsample.WithoutAggrLabel(s.Series.Labels())
+}
@@ -0,0 +1,158 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/oklog/ulid"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/grafana/mimir/pkg/storage/tsdb/bucketindex"
+	"github.com/grafana/mimir/pkg/storage/tsdb/downsample"
+)
+
+func TestDownsamplingForHints(t *testing.T) {
+	tests := map[string]struct {
+		hints              *storage.SelectHints
+		expectedResolution int64
+		expectedAggr       downsample.AggrType
+	}{
+		"no hints": {
+			hints:              nil,
+			expectedResolution: downsample.ResLevel0,
+		},
+		"plain selector": {
+			hints:              &storage.SelectHints{Step: time.Hour.Milliseconds()},
+			expectedResolution: downsample.ResLevel0,
+		},
+		"unsupported function": {
+			hints:              &storage.SelectHints{Func: "avg_over_time", Step: 24 * time.Hour.Milliseconds(), Range: 24 * time.Hour.Milliseconds()},
+			expectedResolution: downsample.ResLevel0,
+		},
+		"rate with a short step": {
+			hints:              &storage.SelectHints{Func: "rate", Step: time.Minute.Milliseconds(), Range: time.Hour.Milliseconds()},
+			expectedResolution: downsample.ResLevel0,
+		},
+		"rate with a short range": {
+			hints:              &storage.SelectHints{Func: "rate", Step: time.Hour.Milliseconds(), Range: 5 * time.Minute.Milliseconds()},
+			expectedResolution: downsample.ResLevel0,
+		},
+		"rate with a step and range of 5 windows of 5m": {
+			hints:              &storage.SelectHints{Func: "rate", Step: 25 * time.Minute.Milliseconds(), Range: 25 * time.Minute.Milliseconds()},
+			expectedResolution: downsample.ResLevel1,
+			expectedAggr:       downsample.AggrCounter,
+		},
+		"rate with a step and range of 5 windows of 1h": {
+			hints:              &storage.SelectHints{Func: "rate", Step: 5 * time.Hour.Milliseconds(), Range: 6 * time.Hour.Milliseconds()},
+			expectedResolution: downsample.ResLevel2,
+			expectedAggr:       downsample.AggrCounter,
+		},
+		"max_over_time": {
+			hints:              &storage.SelectHints{Func: "max_over_time", Step: time.Hour.Milliseconds(), Range: time.Hour.Milliseconds()},
+			expectedResolution: downsample.ResLevel1,
+			expectedAggr:       downsample.AggrMax,
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			resolution, aggr := downsamplingForHints(testData.hints)
+			assert.Equal(t, testData.expectedResolution, resolution)
+			assert.Equal(t, testData.expectedAggr, aggr)
+		})
+	}
+}
+
+func TestSelectBlocksByResolution(t *testing.T) {
+	const hour = int64(time.Hour / time.Millisecond)
+
+	raw1 := &bucketindex.Block{ID: ulid.MustNew(1, nil), MinTime: 0, MaxTime: 2 * hour}
+	raw2 := &bucketindex.Block{ID: ulid.MustNew(2, nil), MinTime: 2 * hour, MaxTime: 4 * hour}
+	raw3 := &bucketindex.Block{ID: ulid.MustNew(3, nil), MinTime: 4 * hour, MaxTime: 6 * hour}
+	level1Block1 := &bucketindex.Block{ID: ulid.MustNew(4, nil), MinTime: 0, MaxTime: 2 * hour, Resolution: downsample.ResLevel1}
+	level1Block3 := &bucketindex.Block{ID: ulid.MustNew(5, nil), MinTime: 4 * hour, MaxTime: 6 * hour, Resolution: downsample.ResLevel1}
+	level2Block1 := &bucketindex.Block{ID: ulid.MustNew(6, nil), MinTime: 0, MaxTime: 2 * hour, Resolution: downsample.ResLevel2}
+
+	shard1Raw := &bucketindex.Block{ID: ulid.MustNew(7, nil), MinTime: 0, MaxTime: 2 * hour, CompactorShardID: "1_of_2"}
+	shard2Raw := &bucketindex.Block{ID: ulid.MustNew(8, nil), MinTime: 0, MaxTime: 2 * hour, CompactorShardID: "2_of_2"}
+	shard2Level1 := &bucketindex.Block{ID: ulid.MustNew(9, nil), MinTime: 0, MaxTime: 2 * hour, CompactorShardID: "2_of_2", Resolution: downsample.ResLevel1}
+
+	tests := map[string]struct {
+		blocks        bucketindex.Blocks
+		minT, maxT    int64
+		maxResolution int64
+		expected      bucketindex.Blocks
+	}{
+		"raw blocks only": {
+			blocks:        bucketindex.Blocks{raw1, raw2, raw3},
+			minT:          0,
+			maxT:          6 * hour,
+			maxResolution: downsample.ResLevel2,
+			expected:      bucketindex.Blocks{raw1, raw2, raw3},
+		},
+		"raw resolution requested": {
+			blocks:        bucketindex.Blocks{raw1, raw2, raw3, level1Block1, level1Block3, level2Block1},
+			minT:          0,
+			maxT:          6 * hour,
+			maxResolution: downsample.ResLevel0,
+			expected:      bucketindex.Blocks{raw1, raw2, raw3},
+		},
+		"downsampled blocks replace the raw blocks, and the gaps are filled with the raw blocks": {
+			blocks:        bucketindex.Blocks{raw3, raw2, raw1, level1Block3, level1Block1},
+			minT:          0,
+			maxT:          6 * hour,
+			maxResolution: downsample.ResLevel1,
+			expected:      bucketindex.Blocks{level1Block1, raw2, level1Block3},
+		},
+		"the coarsest resolution is preferred": {
+			blocks:        bucketindex.Blocks{raw1, raw2, raw3, level1Block1, level1Block3, level2Block1},
+			minT:          0,
+			maxT:          6 * hour,
+			maxResolution: downsample.ResLevel2,
+			expected:      bucketindex.Blocks{level2Block1, raw2, level1Block3},
+		},
+		"the resolution is limited to the max resolution": {
+			blocks:        bucketindex.Blocks{raw1, level1Block1, level2Block1},
+			minT:          0,
+			maxT:          2 * hour,
+			maxResolution: downsample.ResLevel1,
+			expected:      bucketindex.Blocks{level1Block1},
+		},
+		"the blocks are selected for each compactor shard": {
+			blocks:        bucketindex.Blocks{shard1Raw, shard2Raw, shard2Level1},
+			minT:          0,
+			maxT:          2 * hour,
+			maxResolution: downsample.ResLevel1,
+			expected:      bucketindex.Blocks{shard1Raw, shard2Level1},
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			assert.ElementsMatch(t, testData.expected, selectBlocksByResolution(testData.blocks, testData.minT, testData.maxT, testData.maxResolution))
+		})
+	}
+}
+
+func TestSplitClientsByDownsampledBlocks(t *testing.T) {
+	block1 := ulid.MustNew(1, nil)
+	block2 := ulid.MustNew(2, nil)
+	block3 := ulid.MustNew(3, nil)
+	client1 := &storeGatewayClientMock{remoteAddr: "1.1.1.1"}
+	client2 := &storeGatewayClientMock{remoteAddr: "2.2.2.2"}
+	clients := map[BlocksStoreClient][]ulid.ULID{
+		client1: {block1, block2},
+		client2: {block3},
+	}
+
+	raw, downsampled := splitClientsByDownsampledBlocks(clients, nil)
+	assert.Equal(t, clients, raw)
+	assert.Empty(t, downsampled)
+
+	raw, downsampled = splitClientsByDownsampledBlocks(clients, map[ulid.ULID]struct{}{block2: {}, block3: {}})
+	assert.Equal(t, map[BlocksStoreClient][]ulid.ULID{client1: {block1}}, raw)
+	assert.Equal(t, map[BlocksStoreClient][]ulid.ULID{client1: {block2}, client2: {block3}}, downsampled)
+}
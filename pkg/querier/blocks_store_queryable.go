@@ -28,6 +28,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/model/exemplar"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/storage"
 	"github.com/prometheus/prometheus/util/annotations"
@@ -36,6 +37,7 @@ import (
 	"golang.org/x/sync/errgroup"
 	grpc_metadata "google.golang.org/grpc/metadata"
 
+	"github.com/grafana/mimir/pkg/mimirpb"
 	"github.com/grafana/mimir/pkg/querier/stats"
 	"github.com/grafana/mimir/pkg/storage/bucket"
 	"github.com/grafana/mimir/pkg/storage/series"
@@ -43,6 +45,7 @@ import (
 	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
 	"github.com/grafana/mimir/pkg/storage/tsdb/block"
 	"github.com/grafana/mimir/pkg/storage/tsdb/bucketindex"
+	"github.com/grafana/mimir/pkg/storage/tsdb/downsample"
 	"github.com/grafana/mimir/pkg/storegateway"
 	"github.com/grafana/mimir/pkg/storegateway/hintspb"
 	"github.com/grafana/mimir/pkg/storegateway/storegatewaypb"
@@ -196,6 +199,15 @@ func NewBlocksStoreQueryable(
 	return q, nil
 }
 
+// GetSeriesDeletionRequests implements SeriesDeletionRequestsFinder.
+func (q *BlocksStoreQueryable) GetSeriesDeletionRequests(ctx context.Context, userID string) ([]*mimir_tsdb.SeriesDeletionRequest, error) {
+	finder, ok := q.finder.(SeriesDeletionRequestsFinder)
+	if !ok {
+		return nil, nil
+	}
+	return finder.GetSeriesDeletionRequests(ctx, userID)
+}
+
 func NewBlocksStoreQueryableFromConfig(querierCfg Config, gatewayCfg storegateway.Config, storageCfg mimir_tsdb.BlocksStorageConfig, limits BlocksStoreLimits, logger log.Logger, reg prometheus.Registerer) (*BlocksStoreQueryable, error) {
 	var (
 		stores       BlocksStoreSet
@@ -298,6 +310,33 @@ func (q *BlocksStoreQueryable) Querier(mint, maxt int64) (storage.Querier, error
 		return nil, errors.Errorf("BlocksStoreQueryable is not running: %v", s)
 	}
 
+	return q.newQuerier(mint, maxt), nil
+}
+
+// ExemplarQuerier implements storage.ExemplarQueryable.
+func (q *BlocksStoreQueryable) ExemplarQuerier(ctx context.Context) (storage.ExemplarQuerier, error) {
+	if s := q.State(); s != services.Running {
+		return nil, errors.Errorf("BlocksStoreQueryable is not running: %v", s)
+	}
+
+	return &blocksStoreExemplarQuerier{
+		ctx:       ctx,
+		queryable: q,
+	}, nil
+}
+
+// MetricsMetadata returns the metric metadata shipped along with the blocks within the time range. The metadata of
+// all the metrics is returned if metric is empty. At most limit metric families and limitPerMetric metadata for
+// each metric family are returned, unless they're 0.
+func (q *BlocksStoreQueryable) MetricsMetadata(ctx context.Context, minT, maxT int64, metric string, limit, limitPerMetric int) ([]mimirpb.MetricMetadata, error) {
+	if s := q.State(); s != services.Running {
+		return nil, errors.Errorf("BlocksStoreQueryable is not running: %v", s)
+	}
+
+	return q.newQuerier(minT, maxT).selectMetricsMetadata(ctx, metric, limit, limitPerMetric)
+}
+
+func (q *BlocksStoreQueryable) newQuerier(mint, maxt int64) *blocksStoreQuerier {
 	return &blocksStoreQuerier{
 		minT:                     mint,
 		maxT:                     maxt,
@@ -309,7 +348,18 @@ func (q *BlocksStoreQueryable) Querier(mint, maxt int64) (storage.Querier, error
 		consistency:              q.consistency,
 		logger:                   q.logger,
 		queryStoreAfter:          q.queryStoreAfter,
-	}, nil
+	}
+}
+
+type blocksStoreExemplarQuerier struct {
+	ctx       context.Context
+	queryable *BlocksStoreQueryable
+}
+
+// Select implements storage.ExemplarQuerier. The exemplars are returned for the series matching any of the
+// sets of matchers.
+func (q *blocksStoreExemplarQuerier) Select(start, end int64, matchers ...[]*labels.Matcher) ([]exemplar.QueryResult, error) {
+	return q.queryable.newQuerier(start, end).selectExemplars(q.ctx, matchers)
 }
 
 type blocksStoreQuerier struct {
@@ -364,7 +414,7 @@ func (q *blocksStoreQuerier) LabelNames(ctx context.Context, matchers ...*labels
 		convertedMatchers = convertMatchersToLabelMatcher(matchers)
 	)
 
-	queryF := func(clients map[BlocksStoreClient][]ulid.ULID, minT, maxT int64) ([]ulid.ULID, error) {
+	queryF := func(clients map[BlocksStoreClient][]ulid.ULID, _ map[ulid.ULID]struct{}, minT, maxT int64) ([]ulid.ULID, error) {
 		nameSets, warnings, queriedBlocks, err := q.fetchLabelNamesFromStore(ctx, clients, minT, maxT, tenantID, convertedMatchers)
 		if err != nil {
 			return nil, err
@@ -376,7 +426,7 @@ func (q *blocksStoreQuerier) LabelNames(ctx context.Context, matchers ...*labels
 		return queriedBlocks, nil
 	}
 
-	if err := q.queryWithConsistencyCheck(ctx, spanLog, minT, maxT, tenantID, nil, queryF); err != nil {
+	if err := q.queryWithConsistencyCheck(ctx, spanLog, minT, maxT, tenantID, nil, downsample.ResLevel0, queryF); err != nil {
 		return nil, nil, err
 	}
 
@@ -408,7 +458,7 @@ func (q *blocksStoreQuerier) LabelValues(ctx context.Context, name string, match
 		resWarnings  annotations.Annotations
 	)
 
-	queryF := func(clients map[BlocksStoreClient][]ulid.ULID, minT, maxT int64) ([]ulid.ULID, error) {
+	queryF := func(clients map[BlocksStoreClient][]ulid.ULID, _ map[ulid.ULID]struct{}, minT, maxT int64) ([]ulid.ULID, error) {
 		valueSets, warnings, queriedBlocks, err := q.fetchLabelValuesFromStore(ctx, name, clients, minT, maxT, tenantID, matchers...)
 		if err != nil {
 			return nil, err
@@ -420,13 +470,86 @@ func (q *blocksStoreQuerier) LabelValues(ctx context.Context, name string, match
 		return queriedBlocks, nil
 	}
 
-	if err := q.queryWithConsistencyCheck(ctx, spanLog, minT, maxT, tenantID, nil, queryF); err != nil {
+	if err := q.queryWithConsistencyCheck(ctx, spanLog, minT, maxT, tenantID, nil, downsample.ResLevel0, queryF); err != nil {
 		return nil, nil, err
 	}
 
 	return util.MergeSlices(resValueSets...), resWarnings, nil
 }
 
+func (q *blocksStoreQuerier) selectExemplars(ctx context.Context, matchers [][]*labels.Matcher) ([]exemplar.QueryResult, error) {
+	spanLog, ctx := spanlogger.NewWithLogger(ctx, q.logger, "blocksStoreQuerier.selectExemplars")
+	defer spanLog.Span.Finish()
+
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	minT, maxT := q.minT, q.maxT
+
+	spanLog.DebugLog("start", util.TimeFromMillis(minT).UTC().String(), "end",
+		util.TimeFromMillis(maxT).UTC().String(), "matchers", util.MultiMatchersStringer(matchers))
+
+	convertedMatchers := make([]storepb.LabelMatchers, 0, len(matchers))
+	for _, m := range matchers {
+		convertedMatchers = append(convertedMatchers, storepb.LabelMatchers{Matchers: convertMatchersToLabelMatcher(m)})
+	}
+
+	var resSets [][]exemplar.QueryResult
+
+	queryF := func(clients map[BlocksStoreClient][]ulid.ULID, _ map[ulid.ULID]struct{}, minT, maxT int64) ([]ulid.ULID, error) {
+		sets, queriedBlocks, err := q.fetchExemplarsFromStore(ctx, clients, minT, maxT, tenantID, convertedMatchers)
+		if err != nil {
+			return nil, err
+		}
+
+		resSets = append(resSets, sets...)
+
+		return queriedBlocks, nil
+	}
+
+	if err := q.queryWithConsistencyCheck(ctx, spanLog, minT, maxT, tenantID, nil, downsample.ResLevel0, queryF); err != nil {
+		return nil, err
+	}
+
+	return block.MergeExemplars(resSets...), nil
+}
+
+func (q *blocksStoreQuerier) selectMetricsMetadata(ctx context.Context, metric string, limit, limitPerMetric int) ([]mimirpb.MetricMetadata, error) {
+	spanLog, ctx := spanlogger.NewWithLogger(ctx, q.logger, "blocksStoreQuerier.selectMetricsMetadata")
+	defer spanLog.Span.Finish()
+
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	minT, maxT := q.minT, q.maxT
+
+	spanLog.DebugLog("start", util.TimeFromMillis(minT).UTC().String(), "end",
+		util.TimeFromMillis(maxT).UTC().String(), "metric", metric)
+
+	var resSets [][]mimirpb.MetricMetadata
+
+	queryF := func(clients map[BlocksStoreClient][]ulid.ULID, _ map[ulid.ULID]struct{}, minT, maxT int64) ([]ulid.ULID, error) {
+		sets, queriedBlocks, err := q.fetchMetricsMetadataFromStore(ctx, clients, minT, maxT, tenantID, metric, limit, limitPerMetric)
+		if err != nil {
+			return nil, err
+		}
+
+		resSets = append(resSets, sets...)
+
+		return queriedBlocks, nil
+	}
+
+	if err := q.queryWithConsistencyCheck(ctx, spanLog, minT, maxT, tenantID, nil, downsample.ResLevel0, queryF); err != nil {
+		return nil, err
+	}
+
+	return block.LimitMetricMetadata(block.MergeMetricMetadata(resSets...), limit, limitPerMetric), nil
+}
+
 func (q *blocksStoreQuerier) Close() error {
 	return nil
 }
@@ -451,12 +574,18 @@ func (q *blocksStoreQuerier) selectSorted(ctx context.Context, sp *storage.Selec
 		return storage.ErrSeriesSet(err)
 	}
 
-	queryF := func(clients map[BlocksStoreClient][]ulid.ULID, minT, maxT int64) ([]ulid.ULID, error) {
-		seriesSets, queriedBlocks, warnings, startStreamingChunks, chunkEstimator, err := q.fetchSeriesFromStores(ctx, sp, clients, minT, maxT, tenantID, convertedMatchers)
+	fetchF := func(clients map[BlocksStoreClient][]ulid.ULID, minT, maxT int64, matchers []storepb.LabelMatcher, downsampled bool) ([]ulid.ULID, error) {
+		seriesSets, queriedBlocks, warnings, startStreamingChunks, chunkEstimator, err := q.fetchSeriesFromStores(ctx, sp, clients, minT, maxT, tenantID, matchers)
 		if err != nil {
 			return nil, err
 		}
 
+		if downsampled {
+			for i, set := range seriesSets {
+				seriesSets[i] = withoutAggrLabelSeriesSet{SeriesSet: set}
+			}
+		}
+
 		resSeriesSets = append(resSeriesSets, seriesSets...)
 		resWarnings.Merge(warnings)
 		streamStarters = append(streamStarters, startStreamingChunks)
@@ -465,7 +594,29 @@ func (q *blocksStoreQuerier) selectSorted(ctx context.Context, sp *storage.Selec
 		return queriedBlocks, nil
 	}
 
-	err = q.queryWithConsistencyCheck(ctx, spanLog, minT, maxT, tenantID, shard, queryF)
+	// The downsampled blocks are queried for the aggregate required by the query, separately from the raw blocks.
+	maxResolution, aggr := downsamplingForHints(sp)
+	queryF := func(clients map[BlocksStoreClient][]ulid.ULID, downsampledBlocks map[ulid.ULID]struct{}, minT, maxT int64) ([]ulid.ULID, error) {
+		rawClients, downsampledClients := splitClientsByDownsampledBlocks(clients, downsampledBlocks)
+
+		queriedBlocks, err := fetchF(rawClients, minT, maxT, convertedMatchers, false)
+		if err != nil || len(downsampledClients) == 0 {
+			return queriedBlocks, err
+		}
+
+		aggrMatchers := append(append([]storepb.LabelMatcher(nil), convertedMatchers...), storepb.LabelMatcher{
+			Type:  storepb.LabelMatcher_EQ,
+			Name:  downsample.AggrLabel,
+			Value: aggr.String(),
+		})
+		queriedDownsampledBlocks, err := fetchF(downsampledClients, minT, maxT, aggrMatchers, true)
+		if err != nil {
+			return nil, err
+		}
+		return append(queriedBlocks, queriedDownsampledBlocks...), nil
+	}
+
+	err = q.queryWithConsistencyCheck(ctx, spanLog, minT, maxT, tenantID, shard, maxResolution, queryF)
 	if err != nil {
 		return storage.ErrSeriesSet(err)
 	}
@@ -497,10 +648,14 @@ func (q *blocksStoreQuerier) selectSorted(ctx context.Context, sp *storage.Selec
 		resWarnings)
 }
 
-type queryFunc func(clients map[BlocksStoreClient][]ulid.ULID, minT, maxT int64) ([]ulid.ULID, error)
+// queryFunc queries the blocks from the clients. The downsampledBlocks are the queried blocks with a downsampling
+// resolution greater than 0.
+type queryFunc func(clients map[BlocksStoreClient][]ulid.ULID, downsampledBlocks map[ulid.ULID]struct{}, minT, maxT int64) ([]ulid.ULID, error)
 
+// queryWithConsistencyCheck queries the blocks containing samples within the time range. The downsampled blocks
+// with a resolution up to maxResolution are queried instead of the raw blocks, where they cover the time range.
 func (q *blocksStoreQuerier) queryWithConsistencyCheck(
-	ctx context.Context, spanLog *spanlogger.SpanLogger, minT, maxT int64, tenantID string, shard *sharding.ShardSelector, queryF queryFunc,
+	ctx context.Context, spanLog *spanlogger.SpanLogger, minT, maxT int64, tenantID string, shard *sharding.ShardSelector, maxResolution int64, queryF queryFunc,
 ) error {
 	now := time.Now()
 
@@ -526,6 +681,21 @@ func (q *blocksStoreQuerier) queryWithConsistencyCheck(
 
 	q.metrics.blocksFound.Add(float64(len(knownBlocks)))
 
+	knownBlocks = selectBlocksByResolution(knownBlocks, minT, maxT, maxResolution)
+
+	var downsampledBlocks map[ulid.ULID]struct{}
+	for _, b := range knownBlocks {
+		if b.Resolution > downsample.ResLevel0 {
+			if downsampledBlocks == nil {
+				downsampledBlocks = map[ulid.ULID]struct{}{}
+			}
+			downsampledBlocks[b.ID] = struct{}{}
+		}
+	}
+	if len(downsampledBlocks) > 0 {
+		spanLog.DebugLog("msg", "querying downsampled blocks", "max resolution", downsample.ResolutionString(maxResolution), "downsampled blocks", len(downsampledBlocks))
+	}
+
 	if shard != nil && shard.ShardCount > 0 {
 		spanLog.DebugLog("msg", "filtering blocks due to sharding", "blocksBeforeFiltering", knownBlocks.String(), "shardID", shard.LabelValue())
 
@@ -568,7 +738,7 @@ func (q *blocksStoreQuerier) queryWithConsistencyCheck(
 
 		// Fetch series from stores. If an error occur we do not retry because retries
 		// are only meant to cover missing blocks.
-		queriedBlocks, err := queryF(clients, minT, maxT)
+		queriedBlocks, err := queryF(clients, downsampledBlocks, minT, maxT)
 		if err != nil {
 			return err
 		}
@@ -1018,6 +1188,178 @@ func (q *blocksStoreQuerier) fetchLabelNamesFromStore(
 	return nameSets, warnings, queriedBlocks, nil
 }
 
+func (q *blocksStoreQuerier) fetchExemplarsFromStore(
+	ctx context.Context,
+	clients map[BlocksStoreClient][]ulid.ULID,
+	minT int64,
+	maxT int64,
+	tenantID string,
+	matchers []storepb.LabelMatchers,
+) ([][]exemplar.QueryResult, []ulid.ULID, error) {
+	var (
+		reqCtx        = grpc_metadata.AppendToOutgoingContext(ctx, storegateway.GrpcContextMetadataTenantID, tenantID)
+		g, gCtx       = errgroup.WithContext(reqCtx)
+		mtx           = sync.Mutex{}
+		sets          = [][]exemplar.QueryResult{}
+		queriedBlocks = []ulid.ULID(nil)
+		spanLog       = spanlogger.FromContext(ctx, q.logger)
+	)
+
+	// Concurrently fetch exemplars from all clients.
+	for c, blockIDs := range clients {
+		// Change variables scope since it will be used in a goroutine.
+		c := c
+		blockIDs := blockIDs
+
+		g.Go(func() error {
+			req, err := createExemplarsRequest(minT, maxT, blockIDs, matchers)
+			if err != nil {
+				return errors.Wrapf(err, "failed to create exemplars request")
+			}
+
+			exemplarsResp, err := c.Exemplars(gCtx, req)
+			if err != nil {
+				if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+					return err
+				}
+
+				level.Warn(spanLog).Log("msg", "failed to fetch exemplars", "remote", c.RemoteAddress(), "err", err)
+				return nil
+			}
+
+			myQueriedBlocks := []ulid.ULID(nil)
+			if exemplarsResp.Hints != nil {
+				hints := hintspb.ExemplarsResponseHints{}
+				if err := types.UnmarshalAny(exemplarsResp.Hints, &hints); err != nil {
+					return errors.Wrapf(err, "failed to unmarshal exemplars hints from %s", c.RemoteAddress())
+				}
+
+				ids, err := convertBlockHintsToULIDs(hints.QueriedBlocks)
+				if err != nil {
+					return errors.Wrapf(err, "failed to parse queried block IDs from received hints")
+				}
+
+				myQueriedBlocks = ids
+			}
+
+			result := make([]exemplar.QueryResult, 0, len(exemplarsResp.Series))
+			for _, s := range exemplarsResp.Series {
+				result = append(result, exemplar.QueryResult{
+					SeriesLabels: mimirpb.FromLabelAdaptersToLabels(s.Labels),
+					Exemplars:    mimirpb.FromExemplarProtosToExemplars(s.Exemplars),
+				})
+			}
+			for _, w := range exemplarsResp.Warnings {
+				level.Warn(spanLog).Log("msg", "received warning while fetching exemplars", "remote", c.RemoteAddress(), "warning", w)
+			}
+
+			spanLog.DebugLog("msg", "received exemplars from store-gateway",
+				"instance", c,
+				"num series", len(result),
+				"requested blocks", strings.Join(convertULIDsToString(blockIDs), " "),
+				"queried blocks", strings.Join(convertULIDsToString(myQueriedBlocks), " "))
+
+			// Store the result.
+			mtx.Lock()
+			sets = append(sets, result)
+			queriedBlocks = append(queriedBlocks, myQueriedBlocks...)
+			mtx.Unlock()
+
+			return nil
+		})
+	}
+
+	// Wait until all client requests complete.
+	if err := g.Wait(); err != nil {
+		return nil, nil, err
+	}
+
+	return sets, queriedBlocks, nil
+}
+
+func (q *blocksStoreQuerier) fetchMetricsMetadataFromStore(
+	ctx context.Context,
+	clients map[BlocksStoreClient][]ulid.ULID,
+	minT int64,
+	maxT int64,
+	tenantID string,
+	metric string,
+	limit, limitPerMetric int,
+) ([][]mimirpb.MetricMetadata, []ulid.ULID, error) {
+	var (
+		reqCtx        = grpc_metadata.AppendToOutgoingContext(ctx, storegateway.GrpcContextMetadataTenantID, tenantID)
+		g, gCtx       = errgroup.WithContext(reqCtx)
+		mtx           = sync.Mutex{}
+		sets          = [][]mimirpb.MetricMetadata{}
+		queriedBlocks = []ulid.ULID(nil)
+		spanLog       = spanlogger.FromContext(ctx, q.logger)
+	)
+
+	// Concurrently fetch metric metadata from all clients.
+	for c, blockIDs := range clients {
+		// Change variables scope since it will be used in a goroutine.
+		c := c
+		blockIDs := blockIDs
+
+		g.Go(func() error {
+			req, err := createMetricsMetadataRequest(minT, maxT, metric, limit, limitPerMetric, blockIDs)
+			if err != nil {
+				return errors.Wrapf(err, "failed to create metrics metadata request")
+			}
+
+			metadataResp, err := c.MetricsMetadata(gCtx, req)
+			if err != nil {
+				if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+					return err
+				}
+
+				level.Warn(spanLog).Log("msg", "failed to fetch metrics metadata", "remote", c.RemoteAddress(), "err", err)
+				return nil
+			}
+
+			myQueriedBlocks := []ulid.ULID(nil)
+			if metadataResp.Hints != nil {
+				hints := hintspb.MetricsMetadataResponseHints{}
+				if err := types.UnmarshalAny(metadataResp.Hints, &hints); err != nil {
+					return errors.Wrapf(err, "failed to unmarshal metrics metadata hints from %s", c.RemoteAddress())
+				}
+
+				ids, err := convertBlockHintsToULIDs(hints.QueriedBlocks)
+				if err != nil {
+					return errors.Wrapf(err, "failed to parse queried block IDs from received hints")
+				}
+
+				myQueriedBlocks = ids
+			}
+
+			for _, w := range metadataResp.Warnings {
+				level.Warn(spanLog).Log("msg", "received warning while fetching metrics metadata", "remote", c.RemoteAddress(), "warning", w)
+			}
+
+			spanLog.DebugLog("msg", "received metrics metadata from store-gateway",
+				"instance", c,
+				"num metadata", len(metadataResp.Metadata),
+				"requested blocks", strings.Join(convertULIDsToString(blockIDs), " "),
+				"queried blocks", strings.Join(convertULIDsToString(myQueriedBlocks), " "))
+
+			// Store the result.
+			mtx.Lock()
+			sets = append(sets, metadataResp.Metadata)
+			queriedBlocks = append(queriedBlocks, myQueriedBlocks...)
+			mtx.Unlock()
+
+			return nil
+		})
+	}
+
+	// Wait until all client requests complete.
+	if err := g.Wait(); err != nil {
+		return nil, nil, err
+	}
+
+	return sets, queriedBlocks, nil
+}
+
 func (q *blocksStoreQuerier) fetchLabelValuesFromStore(
 	ctx context.Context,
 	name string,
@@ -1162,6 +1504,64 @@ func createLabelNamesRequest(minT, maxT int64, blockIDs []ulid.ULID, matchers []
 	return req, nil
 }
 
+func createExemplarsRequest(minT, maxT int64, blockIDs []ulid.ULID, matchers []storepb.LabelMatchers) (*storepb.ExemplarsRequest, error) {
+	req := &storepb.ExemplarsRequest{
+		Start:    minT,
+		End:      maxT,
+		Matchers: matchers,
+	}
+
+	// Selectively query only specific blocks.
+	hints := &hintspb.ExemplarsRequestHints{
+		BlockMatchers: []storepb.LabelMatcher{
+			{
+				Type:  storepb.LabelMatcher_RE,
+				Name:  block.BlockIDLabel,
+				Value: strings.Join(convertULIDsToString(blockIDs), "|"),
+			},
+		},
+	}
+
+	anyHints, err := types.MarshalAny(hints)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal exemplars request hints")
+	}
+
+	req.Hints = anyHints
+
+	return req, nil
+}
+
+func createMetricsMetadataRequest(minT, maxT int64, metric string, limit, limitPerMetric int, blockIDs []ulid.ULID) (*storepb.MetricsMetadataRequest, error) {
+	req := &storepb.MetricsMetadataRequest{
+		Start:          minT,
+		End:            maxT,
+		Metric:         metric,
+		Limit:          int32(limit),
+		LimitPerMetric: int32(limitPerMetric),
+	}
+
+	// Selectively query only specific blocks.
+	hints := &hintspb.MetricsMetadataRequestHints{
+		BlockMatchers: []storepb.LabelMatcher{
+			{
+				Type:  storepb.LabelMatcher_RE,
+				Name:  block.BlockIDLabel,
+				Value: strings.Join(convertULIDsToString(blockIDs), "|"),
+			},
+		},
+	}
+
+	anyHints, err := types.MarshalAny(hints)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal metrics metadata request hints")
+	}
+
+	req.Hints = anyHints
+
+	return req, nil
+}
+
 func createLabelValuesRequest(minT, maxT int64, label string, blockIDs []ulid.ULID, matchers ...*labels.Matcher) (*storepb.LabelValuesRequest, error) {
 	req := &storepb.LabelValuesRequest{
 		Start:    minT,
@@ -31,6 +31,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/prometheus/model/exemplar"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/promql"
 	"github.com/prometheus/prometheus/storage"
@@ -1810,6 +1811,272 @@ func TestBlocksStoreQuerier_Labels(t *testing.T) {
 	})
 }
 
+func TestBlocksStoreQuerier_SelectExemplars(t *testing.T) {
+	const (
+		minT = int64(10)
+		maxT = int64(20)
+	)
+
+	var (
+		block1  = ulid.MustNew(1, nil)
+		block2  = ulid.MustNew(2, nil)
+		series1 = labels.FromStrings(labels.MetricName, "test_metric", "series", "1")
+		series2 = labels.FromStrings(labels.MetricName, "test_metric", "series", "2")
+		matcher = labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, "test_metric")
+	)
+
+	exemplarSeries := func(lset labels.Labels, ts ...int64) storepb.ExemplarSeries {
+		s := storepb.ExemplarSeries{Labels: mimirpb.FromLabelsToLabelAdapters(lset)}
+		for _, t := range ts {
+			s.Exemplars = append(s.Exemplars, mimirpb.Exemplar{Labels: []mimirpb.LabelAdapter{{Name: "trace_id", Value: "1"}}, Value: 1, TimestampMs: t})
+		}
+		return s
+	}
+	queryResult := func(lset labels.Labels, ts ...int64) exemplar.QueryResult {
+		r := exemplar.QueryResult{SeriesLabels: lset}
+		for _, t := range ts {
+			r.Exemplars = append(r.Exemplars, exemplar.Exemplar{Labels: labels.FromStrings("trace_id", "1"), Value: 1, Ts: t})
+		}
+		return r
+	}
+
+	tests := map[string]struct {
+		finderResult      bucketindex.Blocks
+		storeSetResponses []interface{}
+		expected          []exemplar.QueryResult
+		expectedErr       string
+	}{
+		"no block in the storage matching the query time range": {
+			finderResult: nil,
+		},
+		"multiple store-gateway instances hold the required blocks with overlapping series": {
+			finderResult: bucketindex.Blocks{
+				{ID: block1},
+				{ID: block2},
+			},
+			storeSetResponses: []interface{}{
+				map[BlocksStoreClient][]ulid.ULID{
+					&storeGatewayClientMock{
+						remoteAddr: "1.1.1.1",
+						mockedExemplarsResponse: &storepb.ExemplarsResponse{
+							Series: []storepb.ExemplarSeries{exemplarSeries(series1, 10), exemplarSeries(series2, 12)},
+							Hints:  mockExemplarsHints(block1),
+						},
+					}: {block1},
+					&storeGatewayClientMock{
+						remoteAddr: "2.2.2.2",
+						mockedExemplarsResponse: &storepb.ExemplarsResponse{
+							Series: []storepb.ExemplarSeries{exemplarSeries(series1, 15)},
+							Hints:  mockExemplarsHints(block2),
+						},
+					}: {block2},
+				},
+			},
+			expected: []exemplar.QueryResult{queryResult(series1, 10, 15), queryResult(series2, 12)},
+		},
+		"a block is queried from another store-gateway if missing": {
+			finderResult: bucketindex.Blocks{
+				{ID: block1},
+				{ID: block2},
+			},
+			storeSetResponses: []interface{}{
+				map[BlocksStoreClient][]ulid.ULID{
+					&storeGatewayClientMock{
+						remoteAddr: "1.1.1.1",
+						mockedExemplarsResponse: &storepb.ExemplarsResponse{
+							Series: []storepb.ExemplarSeries{exemplarSeries(series1, 10)},
+							Hints:  mockExemplarsHints(block1),
+						},
+					}: {block1, block2},
+				},
+				map[BlocksStoreClient][]ulid.ULID{
+					&storeGatewayClientMock{
+						remoteAddr: "2.2.2.2",
+						mockedExemplarsResponse: &storepb.ExemplarsResponse{
+							Series: []storepb.ExemplarSeries{exemplarSeries(series2, 12)},
+							Hints:  mockExemplarsHints(block2),
+						},
+					}: {block2},
+				},
+			},
+			expected: []exemplar.QueryResult{queryResult(series1, 10), queryResult(series2, 12)},
+		},
+		"a block is missing from all the store-gateways": {
+			finderResult: bucketindex.Blocks{
+				{ID: block1},
+				{ID: block2},
+			},
+			storeSetResponses: []interface{}{
+				map[BlocksStoreClient][]ulid.ULID{
+					&storeGatewayClientMock{
+						remoteAddr: "1.1.1.1",
+						mockedExemplarsResponse: &storepb.ExemplarsResponse{
+							Hints: mockExemplarsHints(block1),
+						},
+					}: {block1, block2},
+				},
+				errors.New("no store-gateway remaining after exclude"),
+			},
+			expectedErr: newStoreConsistencyCheckFailedError([]ulid.ULID{block2}).Error(),
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx := user.InjectOrgID(context.Background(), "user-1")
+
+			stores := &blocksStoreSetMock{mockedResponses: testData.storeSetResponses}
+			finder := &blocksFinderMock{}
+			finder.On("GetBlocks", mock.Anything, "user-1", minT, maxT).Return(testData.finderResult, map[ulid.ULID]*bucketindex.BlockDeletionMark(nil), nil)
+
+			q := &blocksStoreQuerier{
+				minT:        minT,
+				maxT:        maxT,
+				finder:      finder,
+				stores:      stores,
+				consistency: NewBlocksConsistency(0, 0, log.NewNopLogger(), nil),
+				logger:      log.NewNopLogger(),
+				metrics:     newBlocksStoreQueryableMetrics(prometheus.NewPedanticRegistry()),
+				limits:      &blocksStoreLimitsMock{},
+			}
+
+			actual, err := q.selectExemplars(ctx, [][]*labels.Matcher{{matcher}})
+			if testData.expectedErr != "" {
+				require.EqualError(t, err, testData.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, testData.expected, actual)
+		})
+	}
+}
+
+func TestBlocksStoreQuerier_SelectMetricsMetadata(t *testing.T) {
+	const (
+		minT = int64(10)
+		maxT = int64(20)
+	)
+
+	var (
+		block1    = ulid.MustNew(1, nil)
+		block2    = ulid.MustNew(2, nil)
+		metadata1 = mimirpb.MetricMetadata{Type: mimirpb.COUNTER, MetricFamilyName: "test_metric_1", Help: "foo"}
+		metadata2 = mimirpb.MetricMetadata{Type: mimirpb.GAUGE, MetricFamilyName: "test_metric_2", Help: "bar"}
+	)
+
+	tests := map[string]struct {
+		finderResult      bucketindex.Blocks
+		storeSetResponses []interface{}
+		expected          []mimirpb.MetricMetadata
+		expectedErr       string
+	}{
+		"no block in the storage matching the query time range": {
+			finderResult: nil,
+		},
+		"multiple store-gateway instances hold the required blocks with overlapping metadata": {
+			finderResult: bucketindex.Blocks{
+				{ID: block1},
+				{ID: block2},
+			},
+			storeSetResponses: []interface{}{
+				map[BlocksStoreClient][]ulid.ULID{
+					&storeGatewayClientMock{
+						remoteAddr: "1.1.1.1",
+						mockedMetadataResponse: &storepb.MetricsMetadataResponse{
+							Metadata: []mimirpb.MetricMetadata{metadata1, metadata2},
+							Hints:    mockMetricsMetadataHints(block1),
+						},
+					}: {block1},
+					&storeGatewayClientMock{
+						remoteAddr: "2.2.2.2",
+						mockedMetadataResponse: &storepb.MetricsMetadataResponse{
+							Metadata: []mimirpb.MetricMetadata{metadata1},
+							Hints:    mockMetricsMetadataHints(block2),
+						},
+					}: {block2},
+				},
+			},
+			expected: []mimirpb.MetricMetadata{metadata1, metadata2},
+		},
+		"a block is queried from another store-gateway if missing": {
+			finderResult: bucketindex.Blocks{
+				{ID: block1},
+				{ID: block2},
+			},
+			storeSetResponses: []interface{}{
+				map[BlocksStoreClient][]ulid.ULID{
+					&storeGatewayClientMock{
+						remoteAddr: "1.1.1.1",
+						mockedMetadataResponse: &storepb.MetricsMetadataResponse{
+							Metadata: []mimirpb.MetricMetadata{metadata2},
+							Hints:    mockMetricsMetadataHints(block1),
+						},
+					}: {block1, block2},
+				},
+				map[BlocksStoreClient][]ulid.ULID{
+					&storeGatewayClientMock{
+						remoteAddr: "2.2.2.2",
+						mockedMetadataResponse: &storepb.MetricsMetadataResponse{
+							Metadata: []mimirpb.MetricMetadata{metadata1},
+							Hints:    mockMetricsMetadataHints(block2),
+						},
+					}: {block2},
+				},
+			},
+			expected: []mimirpb.MetricMetadata{metadata1, metadata2},
+		},
+		"a block is missing from all the store-gateways": {
+			finderResult: bucketindex.Blocks{
+				{ID: block1},
+				{ID: block2},
+			},
+			storeSetResponses: []interface{}{
+				map[BlocksStoreClient][]ulid.ULID{
+					&storeGatewayClientMock{
+						remoteAddr: "1.1.1.1",
+						mockedMetadataResponse: &storepb.MetricsMetadataResponse{
+							Hints: mockMetricsMetadataHints(block1),
+						},
+					}: {block1, block2},
+				},
+				errors.New("no store-gateway remaining after exclude"),
+			},
+			expectedErr: newStoreConsistencyCheckFailedError([]ulid.ULID{block2}).Error(),
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			ctx := user.InjectOrgID(context.Background(), "user-1")
+
+			stores := &blocksStoreSetMock{mockedResponses: testData.storeSetResponses}
+			finder := &blocksFinderMock{}
+			finder.On("GetBlocks", mock.Anything, "user-1", minT, maxT).Return(testData.finderResult, map[ulid.ULID]*bucketindex.BlockDeletionMark(nil), nil)
+
+			q := &blocksStoreQuerier{
+				minT:        minT,
+				maxT:        maxT,
+				finder:      finder,
+				stores:      stores,
+				consistency: NewBlocksConsistency(0, 0, log.NewNopLogger(), nil),
+				logger:      log.NewNopLogger(),
+				metrics:     newBlocksStoreQueryableMetrics(prometheus.NewPedanticRegistry()),
+				limits:      &blocksStoreLimitsMock{},
+			}
+
+			actual, err := q.selectMetricsMetadata(ctx, "", 0, 0)
+			if testData.expectedErr != "" {
+				require.EqualError(t, err, testData.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, testData.expected, actual)
+		})
+	}
+}
+
 func TestBlocksStoreQuerier_SelectSortedShouldHonorQueryStoreAfter(t *testing.T) {
 	now := time.Now()
 	ctx := context.Background()
@@ -2238,6 +2505,10 @@ type storeGatewayClientMock struct {
 	mockedLabelNamesErr       error
 	mockedLabelValuesResponse *storepb.LabelValuesResponse
 	mockedLabelValuesErr      error
+	mockedExemplarsResponse   *storepb.ExemplarsResponse
+	mockedExemplarsErr        error
+	mockedMetadataResponse    *storepb.MetricsMetadataResponse
+	mockedMetadataErr         error
 }
 
 func (m *storeGatewayClientMock) Series(ctx context.Context, _ *storepb.SeriesRequest, _ ...grpc.CallOption) (storegatewaypb.StoreGateway_SeriesClient, error) {
@@ -2257,6 +2528,14 @@ func (m *storeGatewayClientMock) LabelValues(context.Context, *storepb.LabelValu
 	return m.mockedLabelValuesResponse, m.mockedLabelValuesErr
 }
 
+func (m *storeGatewayClientMock) Exemplars(context.Context, *storepb.ExemplarsRequest, ...grpc.CallOption) (*storepb.ExemplarsResponse, error) {
+	return m.mockedExemplarsResponse, m.mockedExemplarsErr
+}
+
+func (m *storeGatewayClientMock) MetricsMetadata(context.Context, *storepb.MetricsMetadataRequest, ...grpc.CallOption) (*storepb.MetricsMetadataResponse, error) {
+	return m.mockedMetadataResponse, m.mockedMetadataErr
+}
+
 func (m *storeGatewayClientMock) RemoteAddress() string {
 	return m.remoteAddr
 }
@@ -2333,6 +2612,16 @@ func (m *cancelerStoreGatewayClientMock) LabelValues(ctx context.Context, _ *sto
 	return nil, ctx.Err()
 }
 
+func (m *cancelerStoreGatewayClientMock) Exemplars(ctx context.Context, _ *storepb.ExemplarsRequest, _ ...grpc.CallOption) (*storepb.ExemplarsResponse, error) {
+	m.cancel()
+	return nil, ctx.Err()
+}
+
+func (m *cancelerStoreGatewayClientMock) MetricsMetadata(ctx context.Context, _ *storepb.MetricsMetadataRequest, _ ...grpc.CallOption) (*storepb.MetricsMetadataResponse, error) {
+	m.cancel()
+	return nil, ctx.Err()
+}
+
 func (m *cancelerStoreGatewayClientMock) RemoteAddress() string {
 	return m.remoteAddr
 }
@@ -2468,6 +2757,34 @@ func mockValuesHints(ids ...ulid.ULID) *types.Any {
 	return marshalled
 }
 
+func mockExemplarsHints(ids ...ulid.ULID) *types.Any {
+	hints := &hintspb.ExemplarsResponseHints{}
+	for _, id := range ids {
+		hints.AddQueriedBlock(id)
+	}
+
+	marshalled, err := types.MarshalAny(hints)
+	if err != nil {
+		panic(err)
+	}
+
+	return marshalled
+}
+
+func mockMetricsMetadataHints(ids ...ulid.ULID) *types.Any {
+	hints := &hintspb.MetricsMetadataResponseHints{}
+	for _, id := range ids {
+		hints.AddQueriedBlock(id)
+	}
+
+	marshalled, err := types.MarshalAny(hints)
+	if err != nil {
+		panic(err)
+	}
+
+	return marshalled
+}
+
 func namesFromSeries(series ...labels.Labels) []string {
 	namesMap := map[string]struct{}{}
 	for _, s := range series {
@@ -3,14 +3,18 @@
 package querier
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"sort"
+	"time"
 
 	"github.com/grafana/dskit/httpgrpc"
 	"github.com/grafana/dskit/tenant"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
 
 	"github.com/grafana/mimir/pkg/cardinality"
 	"github.com/grafana/mimir/pkg/distributor"
@@ -22,7 +26,8 @@ import (
 )
 
 // LabelNamesCardinalityHandler creates handler for label names cardinality endpoint.
-func LabelNamesCardinalityHandler(d Distributor, limits *validation.Overrides) http.Handler {
+// The blocks queryable is used to serve requests with the blocks count method.
+func LabelNamesCardinalityHandler(d Distributor, blocks storage.Queryable, limits *validation.Overrides) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 		tenantID, err := tenant.TenantID(ctx)
@@ -40,7 +45,14 @@ func LabelNamesCardinalityHandler(d Distributor, limits *validation.Overrides) h
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		response, err := d.LabelNamesAndValues(ctx, cardinalityRequest.Matchers, cardinalityRequest.CountMethod)
+
+		var response *ingester_client.LabelNamesAndValuesResponse
+		if cardinalityRequest.CountMethod == cardinality.BlocksMethod {
+			clampBlocksTimeRange(&cardinalityRequest.Start, cardinalityRequest.End, limits.MaxLabelsQueryLength(tenantID))
+			response, err = blocksLabelNamesAndValues(ctx, blocks, cardinalityRequest)
+		} else {
+			response, err = d.LabelNamesAndValues(ctx, cardinalityRequest.Matchers, cardinalityRequest.CountMethod)
+		}
 		if err != nil {
 			respondFromError(err, w)
 			return
@@ -51,7 +63,8 @@ func LabelNamesCardinalityHandler(d Distributor, limits *validation.Overrides) h
 }
 
 // LabelValuesCardinalityHandler creates handler for label values cardinality endpoint.
-func LabelValuesCardinalityHandler(distributor Distributor, limits *validation.Overrides) http.Handler {
+// The blocks queryable is used to serve requests with the blocks count method.
+func LabelValuesCardinalityHandler(distributor Distributor, blocks storage.Queryable, limits *validation.Overrides) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 		// Guarantee request's context is for a single tenant id
@@ -71,7 +84,16 @@ func LabelValuesCardinalityHandler(distributor Distributor, limits *validation.O
 			return
 		}
 
-		seriesCountTotal, cardinalityResponse, err := distributor.LabelValuesCardinality(ctx, cardinalityRequest.LabelNames, cardinalityRequest.Matchers, cardinalityRequest.CountMethod)
+		var (
+			seriesCountTotal    uint64
+			cardinalityResponse *ingester_client.LabelValuesCardinalityResponse
+		)
+		if cardinalityRequest.CountMethod == cardinality.BlocksMethod {
+			clampBlocksTimeRange(&cardinalityRequest.Start, cardinalityRequest.End, limits.MaxLabelsQueryLength(tenantID))
+			seriesCountTotal, cardinalityResponse, err = blocksLabelValuesCardinality(ctx, blocks, cardinalityRequest)
+		} else {
+			seriesCountTotal, cardinalityResponse, err = distributor.LabelValuesCardinality(ctx, cardinalityRequest.LabelNames, cardinalityRequest.Matchers, cardinalityRequest.CountMethod)
+		}
 		if err != nil {
 			respondFromError(err, w)
 			return
@@ -227,3 +249,99 @@ func limitLabelValuesCardinality(labelValuesCardinality []api.LabelValuesCardina
 	}
 	return labelValuesCardinality[:limit]
 }
+
+// clampBlocksTimeRange manipulates the start time so that the queried time range is not longer
+// than maxLength, consistently with how the limit is enforced for label names and values queries.
+func clampBlocksTimeRange(start *int64, end int64, maxLength time.Duration) {
+	if maxLength <= 0 {
+		return
+	}
+	if minStart := end - maxLength.Milliseconds(); *start < minStart {
+		*start = minStart
+	}
+}
+
+// blocksLabelNamesAndValues returns the label names and values of the series matching the request's
+// matchers, looking up the index of the blocks overlapping the request's time range.
+func blocksLabelNamesAndValues(ctx context.Context, blocks storage.Queryable, req *cardinality.LabelNamesRequest) (*ingester_client.LabelNamesAndValuesResponse, error) {
+	q, err := blocks.Querier(req.Start, req.End)
+	if err != nil {
+		return nil, err
+	}
+	defer q.Close()
+
+	names, _, err := q.LabelNames(ctx, req.Matchers...)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*ingester_client.LabelValues, 0, len(names))
+	for _, name := range names {
+		values, _, err := q.LabelValues(ctx, name, req.Matchers...)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, &ingester_client.LabelValues{LabelName: name, Values: values})
+	}
+
+	return &ingester_client.LabelNamesAndValuesResponse{Items: items}, nil
+}
+
+// blocksLabelValuesCardinality returns the number of series matching the request's matchers and, for each
+// requested label name, the number of series per label value. The series are looked up in the index of
+// the blocks overlapping the request's time range, without fetching any chunk.
+func blocksLabelValuesCardinality(ctx context.Context, blocks storage.Queryable, req *cardinality.LabelValuesRequest) (uint64, *ingester_client.LabelValuesCardinalityResponse, error) {
+	q, err := blocks.Querier(req.Start, req.End)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer q.Close()
+
+	hints := &storage.SelectHints{Start: req.Start, End: req.End, Func: "series"}
+
+	// Count all series matching the selector. When no selector is provided we count all series of the tenant.
+	matchers := req.Matchers
+	if len(matchers) == 0 {
+		matchers = []*labels.Matcher{labels.MustNewMatcher(labels.MatchRegexp, labels.MetricName, ".+")}
+	}
+
+	seriesCountTotal, err := countSeries(ctx, q, hints, matchers, func(labels.Labels) {})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	items := make([]*ingester_client.LabelValueSeriesCount, 0, len(req.LabelNames))
+	for _, labelName := range req.LabelNames {
+		name := string(labelName)
+		item := &ingester_client.LabelValueSeriesCount{
+			LabelName:        name,
+			LabelValueSeries: map[string]uint64{},
+		}
+
+		// Only select series which have the label, otherwise they would be counted for the empty value.
+		nameMatchers := append([]*labels.Matcher{labels.MustNewMatcher(labels.MatchRegexp, name, ".+")}, req.Matchers...)
+		_, err := countSeries(ctx, q, hints, nameMatchers, func(lbls labels.Labels) {
+			item.LabelValueSeries[lbls.Get(name)]++
+		})
+		if err != nil {
+			return 0, nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	return seriesCountTotal, &ingester_client.LabelValuesCardinalityResponse{Items: items}, nil
+}
+
+// countSeries returns the number of series matching the input matchers, calling fn for each of them.
+func countSeries(ctx context.Context, q storage.Querier, hints *storage.SelectHints, matchers []*labels.Matcher, fn func(labels.Labels)) (uint64, error) {
+	set := q.Select(ctx, false, hints, matchers...)
+
+	var count uint64
+	for set.Next() {
+		fn(set.At().Labels())
+		count++
+	}
+
+	return count, set.Err()
+}
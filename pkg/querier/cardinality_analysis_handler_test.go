@@ -18,6 +18,8 @@ import (
 	"github.com/grafana/dskit/user"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -37,7 +39,7 @@ func TestLabelNamesCardinalityHandler(t *testing.T) {
 		{LabelName: "label-z", Values: []string{"0z", "1z", "2z"}},
 	}
 	distributor := mockDistributorLabelNamesAndValues(items, nil)
-	handler := createEnabledHandler(t, LabelNamesCardinalityHandler, distributor)
+	handler := createEnabledHandler(t, withBlocksQueryable(LabelNamesCardinalityHandler, nil), distributor)
 	ctx := user.InjectOrgID(context.Background(), "team-a")
 	request, err := http.NewRequestWithContext(ctx, "GET", "/ignored-url?limit=4", http.NoBody)
 	require.NoError(t, err)
@@ -102,7 +104,7 @@ func TestLabelNamesCardinalityHandler_MatchersTest(t *testing.T) {
 	for _, data := range td {
 		t.Run(data.name, func(t *testing.T) {
 			distributor := mockDistributorLabelNamesAndValues([]*client.LabelValues{}, nil)
-			handler := createEnabledHandler(t, LabelNamesCardinalityHandler, distributor)
+			handler := createEnabledHandler(t, withBlocksQueryable(LabelNamesCardinalityHandler, nil), distributor)
 			ctx := user.InjectOrgID(context.Background(), "team-a")
 			recorder := httptest.NewRecorder()
 			path := "/ignored-url"
@@ -154,7 +156,7 @@ func TestLabelNamesCardinalityHandler_LimitTest(t *testing.T) {
 			labelCountTotal := 30
 			items, valuesCountTotal := generateLabelValues(labelCountTotal)
 			distributor := mockDistributorLabelNamesAndValues(items, nil)
-			handler := createEnabledHandler(t, LabelNamesCardinalityHandler, distributor)
+			handler := createEnabledHandler(t, withBlocksQueryable(LabelNamesCardinalityHandler, nil), distributor)
 
 			ctx := user.InjectOrgID(context.Background(), "team-a")
 			path := "/ignored-url"
@@ -213,7 +215,7 @@ func TestLabelNamesCardinalityHandler_DistributorError(t *testing.T) {
 			limits.CardinalityAnalysisEnabled = true
 			overrides, err := validation.NewOverrides(limits, nil)
 			require.NoError(t, err)
-			handler := LabelNamesCardinalityHandler(distributor, overrides)
+			handler := LabelNamesCardinalityHandler(distributor, nil, overrides)
 			ctx := user.InjectOrgID(context.Background(), "test")
 
 			request, err := http.NewRequestWithContext(ctx, "GET", labelNamesURL, http.NoBody)
@@ -278,7 +280,7 @@ func TestLabelNamesCardinalityHandler_NegativeTests(t *testing.T) {
 			}
 			overrides, err := validation.NewOverrides(limits, nil)
 			require.NoError(t, err)
-			handler := LabelNamesCardinalityHandler(mockDistributorLabelNamesAndValues([]*client.LabelValues{}, nil), overrides)
+			handler := LabelNamesCardinalityHandler(mockDistributorLabelNamesAndValues([]*client.LabelValues{}, nil), nil, overrides)
 
 			recorder := httptest.NewRecorder()
 
@@ -566,7 +568,7 @@ func TestLabelValuesCardinalityHandler_Success(t *testing.T) {
 			seriesCountTotal,
 			testData.labelValuesCardinality,
 			nil)
-		handler := createEnabledHandler(t, LabelValuesCardinalityHandler, distributor)
+		handler := createEnabledHandler(t, withBlocksQueryable(LabelValuesCardinalityHandler, nil), distributor)
 		ctx := user.InjectOrgID(context.Background(), "test")
 
 		t.Run("GET request "+testName, func(t *testing.T) {
@@ -646,7 +648,7 @@ func TestLabelValuesCardinalityHandler_FeatureFlag(t *testing.T) {
 			limits := validation.Limits{CardinalityAnalysisEnabled: testData.cardinalityAnalysisEnabled}
 			overrides, err := validation.NewOverrides(limits, nil)
 			require.NoError(t, err)
-			handler := LabelValuesCardinalityHandler(distributor, overrides)
+			handler := LabelValuesCardinalityHandler(distributor, nil, overrides)
 
 			recorder := httptest.NewRecorder()
 			handler.ServeHTTP(recorder, testData.request)
@@ -673,7 +675,7 @@ func TestLabelValuesCardinalityHandler_ParseError(t *testing.T) {
 		uint64(0),
 		&client.LabelValuesCardinalityResponse{Items: []*client.LabelValueSeriesCount{}},
 		nil)
-	handler := createEnabledHandler(t, LabelValuesCardinalityHandler, distributor)
+	handler := createEnabledHandler(t, withBlocksQueryable(LabelValuesCardinalityHandler, nil), distributor)
 	ctx := user.InjectOrgID(context.Background(), "test")
 
 	t.Run("should return bad request if no tenant id is provided", func(t *testing.T) {
@@ -767,7 +769,7 @@ func TestLabelValuesCardinalityHandler_DistributorError(t *testing.T) {
 				uint64(0),
 				&client.LabelValuesCardinalityResponse{Items: []*client.LabelValueSeriesCount{}},
 				testData.distributorError)
-			handler := createEnabledHandler(t, LabelValuesCardinalityHandler, distributor)
+			handler := createEnabledHandler(t, withBlocksQueryable(LabelValuesCardinalityHandler, nil), distributor)
 			ctx := user.InjectOrgID(context.Background(), "test")
 
 			request, err := http.NewRequestWithContext(ctx, "GET", labelValuesURL, http.NoBody)
@@ -789,6 +791,127 @@ func TestLabelValuesCardinalityHandler_DistributorError(t *testing.T) {
 	}
 }
 
+func TestCardinalityHandlers_BlocksCountMethod(t *testing.T) {
+	blocks := mockBlocksQueryableForCardinality(t, []labels.Labels{
+		labels.FromStrings(labels.MetricName, "metric_1", "job", "a", "pod", "1"),
+		labels.FromStrings(labels.MetricName, "metric_1", "job", "a", "pod", "2"),
+		labels.FromStrings(labels.MetricName, "metric_1", "job", "b", "pod", "3"),
+		labels.FromStrings(labels.MetricName, "metric_2", "job", "b"),
+	})
+
+	// The distributor must not be called when counting series in blocks.
+	distributor := &mockDistributor{}
+	ctx := user.InjectOrgID(context.Background(), "test")
+
+	t.Run("label names", func(t *testing.T) {
+		handler := createEnabledHandler(t, withBlocksQueryable(LabelNamesCardinalityHandler, blocks), distributor)
+		request, err := http.NewRequestWithContext(ctx, "GET", "/label_names?count_method=blocks&start=0&end=10&selector={__name__=\"metric_1\"}", http.NoBody)
+		require.NoError(t, err)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Result().StatusCode)
+
+		responseBody := api.LabelNamesCardinalityResponse{}
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &responseBody))
+		require.Equal(t, api.LabelNamesCardinalityResponse{
+			LabelValuesCountTotal: 6,
+			LabelNamesCount:       3,
+			Cardinality: []*api.LabelNamesCardinalityItem{
+				{LabelName: "pod", LabelValuesCount: 3},
+				{LabelName: "job", LabelValuesCount: 2},
+				{LabelName: labels.MetricName, LabelValuesCount: 1},
+			},
+		}, responseBody)
+	})
+
+	t.Run("label values", func(t *testing.T) {
+		handler := createEnabledHandler(t, withBlocksQueryable(LabelValuesCardinalityHandler, blocks), distributor)
+		request, err := http.NewRequestWithContext(ctx, "GET", "/label_values?count_method=blocks&start=0&end=10&label_names[]=job&label_names[]=pod", http.NoBody)
+		require.NoError(t, err)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Result().StatusCode)
+
+		responseBody := api.LabelValuesCardinalityResponse{}
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &responseBody))
+		require.Equal(t, api.LabelValuesCardinalityResponse{
+			SeriesCountTotal: 4,
+			Labels: []api.LabelNamesCardinality{
+				{
+					LabelName:        "job",
+					LabelValuesCount: 2,
+					SeriesCount:      4,
+					Cardinality: []api.LabelValuesCardinality{
+						{LabelValue: "a", SeriesCount: 2},
+						{LabelValue: "b", SeriesCount: 2},
+					},
+				},
+				{
+					LabelName:        "pod",
+					LabelValuesCount: 3,
+					SeriesCount:      3,
+					Cardinality: []api.LabelValuesCardinality{
+						{LabelValue: "1", SeriesCount: 1},
+						{LabelValue: "2", SeriesCount: 1},
+						{LabelValue: "3", SeriesCount: 1},
+					},
+				},
+			},
+		}, responseBody)
+	})
+
+	t.Run("time range outside of the blocks", func(t *testing.T) {
+		handler := createEnabledHandler(t, withBlocksQueryable(LabelValuesCardinalityHandler, blocks), distributor)
+		request, err := http.NewRequestWithContext(ctx, "GET", "/label_values?count_method=blocks&start=100&end=200&label_names[]=job", http.NoBody)
+		require.NoError(t, err)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Result().StatusCode)
+
+		responseBody := api.LabelValuesCardinalityResponse{}
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &responseBody))
+		require.Equal(t, uint64(0), responseBody.SeriesCountTotal)
+		require.Len(t, responseBody.Labels, 1)
+		require.Empty(t, responseBody.Labels[0].Cardinality)
+	})
+
+	t.Run("missing time range", func(t *testing.T) {
+		handler := createEnabledHandler(t, withBlocksQueryable(LabelNamesCardinalityHandler, blocks), distributor)
+		request, err := http.NewRequestWithContext(ctx, "GET", "/label_names?count_method=blocks&start=0", http.NoBody)
+		require.NoError(t, err)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusBadRequest, recorder.Result().StatusCode)
+		require.Contains(t, recorder.Body.String(), "'end' param is required")
+	})
+
+	distributor.AssertNotCalled(t, "LabelNamesAndValues", mock.Anything, mock.Anything, mock.Anything)
+	distributor.AssertNotCalled(t, "LabelValuesCardinality", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// mockBlocksQueryableForCardinality returns a queryable backed by a TSDB head containing the input series,
+// each of them with a sample at timestamp 1.
+func mockBlocksQueryableForCardinality(t *testing.T, series []labels.Labels) storage.Queryable {
+	opts := tsdb.DefaultHeadOptions()
+	opts.ChunkDirRoot = t.TempDir()
+	head, err := tsdb.NewHead(nil, nil, nil, nil, opts, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = head.Close()
+	})
+
+	app := head.Appender(context.Background())
+	for _, lbls := range series {
+		_, err := app.Append(0, lbls, 1, 1)
+		require.NoError(t, err)
+	}
+	require.NoError(t, app.Commit())
+
+	return storage.QueryableFunc(func(mint, maxt int64) (storage.Querier, error) {
+		return tsdb.NewBlockQuerier(tsdb.NewRangeHead(head, mint, maxt), mint, maxt)
+	})
+}
+
 func TestActiveSeriesCardinalityHandler(t *testing.T) {
 	tests := []struct {
 		name                 string
@@ -912,6 +1035,13 @@ func createEnabledHandler(t testing.TB, cardinalityHandler func(Distributor, *va
 	return handler
 }
 
+// withBlocksQueryable adapts a cardinality handler requiring a blocks queryable to the signature expected by createEnabledHandler.
+func withBlocksQueryable(cardinalityHandler func(Distributor, storage.Queryable, *validation.Overrides) http.Handler, blocks storage.Queryable) func(Distributor, *validation.Overrides) http.Handler {
+	return func(d Distributor, limits *validation.Overrides) http.Handler {
+		return cardinalityHandler(d, blocks, limits)
+	}
+}
+
 func createRequest(path string, tenantID string) *http.Request {
 	ctx := context.Background()
 	if len(tenantID) > 0 {
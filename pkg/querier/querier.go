@@ -10,6 +10,10 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -18,17 +22,23 @@ import (
 	"github.com/grafana/dskit/tenant"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/exemplar"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/promql"
 	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/prometheus/prometheus/scrape"
 	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb/tombstones"
 	"github.com/prometheus/prometheus/util/annotations"
 	"golang.org/x/sync/errgroup"
 
+	"github.com/grafana/mimir/pkg/ingester/client"
+	"github.com/grafana/mimir/pkg/mimirpb"
 	"github.com/grafana/mimir/pkg/querier/engine"
 	"github.com/grafana/mimir/pkg/querier/stats"
 	"github.com/grafana/mimir/pkg/storage/chunk"
 	"github.com/grafana/mimir/pkg/storage/lazyquery"
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
 	"github.com/grafana/mimir/pkg/util"
 	"github.com/grafana/mimir/pkg/util/activitytracker"
 	"github.com/grafana/mimir/pkg/util/limiter"
@@ -54,6 +64,8 @@ type Config struct {
 	StreamingChunksPerStoreGatewaySeriesBufferSize uint64        `yaml:"streaming_chunks_per_store_gateway_series_buffer_size" category:"experimental"`
 	MinimizeIngesterRequests                       bool          `yaml:"minimize_ingester_requests" category:"experimental"` // Enabled by default as of Mimir 2.11, remove altogether in 2.12.
 	MinimiseIngesterRequestsHedgingDelay           time.Duration `yaml:"minimize_ingester_requests_hedging_delay" category:"advanced"`
+	QueryStoreForExemplarsEnabled                  bool          `yaml:"query_store_for_exemplars_enabled" category:"experimental"`
+	QueryStoreForMetadataLookback                  time.Duration `yaml:"query_store_for_metadata_lookback" category:"experimental"`
 
 	// PromQL engine config.
 	EngineConfig engine.Config `yaml:",inline"`
@@ -82,6 +94,8 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	// Based on our testing, 256 series / ingester was a good balance between memory consumption and the CPU overhead of managing a batch of series.
 	f.Uint64Var(&cfg.StreamingChunksPerIngesterSeriesBufferSize, "querier.streaming-chunks-per-ingester-buffer-size", 256, "Number of series to buffer per ingester when streaming chunks from ingesters.")
 	f.Uint64Var(&cfg.StreamingChunksPerStoreGatewaySeriesBufferSize, "querier.streaming-chunks-per-store-gateway-buffer-size", 256, "Number of series to buffer per store-gateway when streaming chunks from store-gateways.")
+	f.BoolVar(&cfg.QueryStoreForExemplarsEnabled, "querier.query-store-for-exemplars-enabled", false, "If enabled, exemplars are also queried from the store-gateways, for the time range older than -"+queryStoreAfterFlag+". The exemplars are only available in the storage if they're shipped by the ingesters.")
+	f.DurationVar(&cfg.QueryStoreForMetadataLookback, "querier.query-store-for-metadata-lookback", 0, "If greater than 0, the metric metadata is also queried from the store-gateways, for the blocks within this period of time and older than -"+queryStoreAfterFlag+", so that it's available for the metrics no longer written. The metric metadata is only available in the storage if it's shipped by the ingesters. 0 to disable.")
 
 	cfg.EngineConfig.RegisterFlags(f)
 }
@@ -129,8 +143,14 @@ func New(cfg Config, limits *validation.Overrides, distributor Distributor, stor
 
 	distributorQueryable := newDistributorQueryable(distributor, limits, queryMetrics, logger)
 
-	queryable := newQueryable(distributorQueryable, storeQueryable, cfg, limits, queryMetrics, logger)
+	// The store queryable also provides the series deletion requests, when backed by the bucket index.
+	deletionRequestsFinder, _ := storeQueryable.(SeriesDeletionRequestsFinder)
+
+	queryable := newQueryable(distributorQueryable, storeQueryable, deletionRequestsFinder, cfg, limits, queryMetrics, logger)
 	exemplarQueryable := newDistributorExemplarQueryable(distributor, logger)
+	if storeExemplarQueryable, ok := storeQueryable.(storage.ExemplarQueryable); ok && cfg.QueryStoreForExemplarsEnabled {
+		exemplarQueryable = newMultiExemplarQueryable(exemplarQueryable, storeExemplarQueryable, cfg, limits, logger)
+	}
 
 	lazyQueryable := storage.QueryableFunc(func(minT int64, maxT int64) (storage.Querier, error) {
 		querier, err := queryable.Querier(minT, maxT)
@@ -149,6 +169,155 @@ func New(cfg Config, limits *validation.Overrides, distributor Distributor, stor
 	return NewSampleAndChunkQueryable(lazyQueryable), exemplarQueryable, engine
 }
 
+// multiExemplarQueryable queries the exemplars from the ingesters and the store-gateways, depending on the time
+// range of the query, and merges them.
+type multiExemplarQueryable struct {
+	distributor storage.ExemplarQueryable
+	blockStore  storage.ExemplarQueryable
+	cfg         Config
+	limits      *validation.Overrides
+	logger      log.Logger
+}
+
+func newMultiExemplarQueryable(distributor, blockStore storage.ExemplarQueryable, cfg Config, limits *validation.Overrides, logger log.Logger) storage.ExemplarQueryable {
+	return &multiExemplarQueryable{
+		distributor: distributor,
+		blockStore:  blockStore,
+		cfg:         cfg,
+		limits:      limits,
+		logger:      logger,
+	}
+}
+
+// ExemplarQuerier implements storage.ExemplarQueryable.
+func (q *multiExemplarQueryable) ExemplarQuerier(ctx context.Context) (storage.ExemplarQuerier, error) {
+	return &multiExemplarQuerier{ctx: ctx, queryable: q}, nil
+}
+
+type multiExemplarQuerier struct {
+	ctx       context.Context
+	queryable *multiExemplarQueryable
+}
+
+// Select implements storage.ExemplarQuerier.
+func (q *multiExemplarQuerier) Select(start, end int64, matchers ...[]*labels.Matcher) ([]exemplar.QueryResult, error) {
+	spanLog, ctx := spanlogger.NewWithLogger(q.ctx, q.queryable.logger, "querier.SelectExemplars")
+	defer spanLog.Span.Finish()
+
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var queryables []storage.ExemplarQueryable
+	now := time.Now()
+	if ShouldQueryIngesters(q.queryable.limits.QueryIngestersWithin(tenantID), now, end) {
+		queryables = append(queryables, q.queryable.distributor)
+	}
+	if ShouldQueryBlockStore(q.queryable.cfg.QueryStoreAfter, now, start) {
+		queryables = append(queryables, q.queryable.blockStore)
+	}
+
+	results := make([][]exemplar.QueryResult, len(queryables))
+	g, gCtx := errgroup.WithContext(ctx)
+	for i, queryable := range queryables {
+		i, queryable := i, queryable
+		g.Go(func() error {
+			querier, err := queryable.ExemplarQuerier(gCtx)
+			if err != nil {
+				return err
+			}
+			results[i], err = querier.Select(start, end, matchers...)
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return block.MergeExemplars(results...), nil
+}
+
+// blocksStoreMetadataSupplier returns the metric metadata shipped along with the blocks.
+type blocksStoreMetadataSupplier interface {
+	MetricsMetadata(ctx context.Context, minT, maxT int64, metric string, limit, limitPerMetric int) ([]mimirpb.MetricMetadata, error)
+}
+
+// NewMetadataSupplier returns a MetadataSupplier returning the metric metadata from the distributor, merged with
+// the metric metadata from the store-gateways if -querier.query-store-for-metadata-lookback is enabled.
+func NewMetadataSupplier(cfg Config, distributor MetadataSupplier, storeQueryable storage.Queryable, logger log.Logger) MetadataSupplier {
+	blockStore, ok := storeQueryable.(blocksStoreMetadataSupplier)
+	if !ok || cfg.QueryStoreForMetadataLookback <= 0 {
+		return distributor
+	}
+
+	return &multiMetadataSupplier{
+		distributor: distributor,
+		blockStore:  blockStore,
+		lookback:    cfg.QueryStoreForMetadataLookback,
+		logger:      logger,
+	}
+}
+
+// multiMetadataSupplier queries the metric metadata from the ingesters and the store-gateways, and merges it.
+type multiMetadataSupplier struct {
+	distributor MetadataSupplier
+	blockStore  blocksStoreMetadataSupplier
+	lookback    time.Duration
+	logger      log.Logger
+}
+
+// MetricsMetadata implements MetadataSupplier. The limits of the request bound the metadata from the store-gateways,
+// and they're applied again by the metadata handler once the results are merged.
+func (m *multiMetadataSupplier) MetricsMetadata(ctx context.Context, req *client.MetricsMetadataRequest) ([]scrape.MetricMetadata, error) {
+	spanLog, ctx := spanlogger.NewWithLogger(ctx, m.logger, "querier.MetricsMetadata")
+	defer spanLog.Span.Finish()
+
+	var (
+		fromDistributor []scrape.MetricMetadata
+		fromBlockStore  []mimirpb.MetricMetadata
+	)
+
+	now := time.Now()
+	g, gCtx := errgroup.WithContext(ctx)
+	g.Go(func() (err error) {
+		fromDistributor, err = m.distributor.MetricsMetadata(gCtx, req)
+		return err
+	})
+	g.Go(func() (err error) {
+		// The negative limits of the request disable them, while the store-gateways don't apply a limit of 0.
+		fromBlockStore, err = m.blockStore.MetricsMetadata(gCtx, util.TimeToMillis(now.Add(-m.lookback)), util.TimeToMillis(now), req.Metric, max(int(req.Limit), 0), max(int(req.LimitPerMetric), 0))
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	// The metadata from the ingesters comes first, because it's the most recent one.
+	result := make([]scrape.MetricMetadata, 0, len(fromDistributor)+len(fromBlockStore))
+	unique := make(map[scrape.MetricMetadata]struct{}, len(fromDistributor)+len(fromBlockStore))
+	for _, md := range fromDistributor {
+		if _, exists := unique[md]; !exists {
+			result = append(result, md)
+			unique[md] = struct{}{}
+		}
+	}
+	for _, pb := range fromBlockStore {
+		md := scrape.MetricMetadata{
+			Metric: pb.MetricFamilyName,
+			Help:   pb.Help,
+			Unit:   pb.Unit,
+			Type:   mimirpb.MetricMetadataMetricTypeToMetricType(pb.GetType()),
+		}
+		if _, exists := unique[md]; !exists {
+			result = append(result, md)
+			unique[md] = struct{}{}
+		}
+	}
+
+	return result, nil
+}
+
 // NewSampleAndChunkQueryable creates a SampleAndChunkQueryable from a Queryable.
 func NewSampleAndChunkQueryable(q storage.Queryable) storage.SampleAndChunkQueryable {
 	return &sampleAndChunkQueryable{q}
@@ -178,6 +347,7 @@ func (q *chunkQuerier) Select(ctx context.Context, sortSeries bool, hints *stora
 func newQueryable(
 	distributor storage.Queryable,
 	blockStore storage.Queryable,
+	deletionRequestsFinder SeriesDeletionRequestsFinder,
 	cfg Config,
 	limits *validation.Overrides,
 	queryMetrics *stats.QueryMetrics,
@@ -187,6 +357,7 @@ func newQueryable(
 		return multiQuerier{
 			distributor:        distributor,
 			blockStore:         blockStore,
+			deletionRequests:   deletionRequestsFinder,
 			queryMetrics:       queryMetrics,
 			cfg:                cfg,
 			minT:               minT,
@@ -207,6 +378,9 @@ type multiQuerier struct {
 	cfg          Config
 	minT, maxT   int64
 
+	// deletionRequests is optional, and when set the series deletion requests are honored at query time.
+	deletionRequests SeriesDeletionRequestsFinder
+
 	maxQueryIntoFuture time.Duration
 	limits             *validation.Overrides
 
@@ -323,6 +497,21 @@ func (mq multiQuerier) Select(ctx context.Context, _ bool, sp *storage.SelectHin
 		return storage.ErrSeriesSet(NewMaxQueryLengthError(endTime.Sub(startTime), maxQueryLength))
 	}
 
+	deletions, err := mq.getSeriesDeletions(ctx, userID, startMs, endMs)
+	if err != nil {
+		return storage.ErrSeriesSet(err)
+	}
+	if len(deletions) > 0 && sp.Func == "series" {
+		// The samples are needed to find out whether the series partially deleted within the time range
+		// have some samples left. The hints have been copied above, so the caller's ones are left untouched.
+		sp.Func = ""
+	}
+
+	return mq.filterDeletedSeries(mq.selectFromQueriers(ctx, queriers, sp, matchers...), deletions, startMs, endMs)
+}
+
+// selectFromQueriers selects the series from all the queriers, and merges them.
+func (mq multiQuerier) selectFromQueriers(ctx context.Context, queriers []storage.Querier, sp *storage.SelectHints, matchers ...*labels.Matcher) storage.SeriesSet {
 	if len(queriers) == 1 {
 		return queriers[0].Select(ctx, true, sp, matchers...)
 	}
@@ -350,6 +539,37 @@ func (mq multiQuerier) Select(ctx context.Context, _ bool, sp *storage.SelectHin
 	return mq.mergeSeriesSets(result)
 }
 
+// getSeriesDeletions returns the series deletions of the user overlapping with the input time range.
+func (mq multiQuerier) getSeriesDeletions(ctx context.Context, userID string, minT, maxT int64) ([]seriesDeletion, error) {
+	if mq.deletionRequests == nil {
+		return nil, nil
+	}
+
+	requests, err := mq.deletionRequests.GetSeriesDeletionRequests(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSeriesDeletions(requests, minT, maxT)
+}
+
+// getQuerierSeriesDeletions returns the series deletions of the user overlapping with the querier time range.
+func (mq multiQuerier) getQuerierSeriesDeletions(ctx context.Context) ([]seriesDeletion, error) {
+	userID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return mq.getSeriesDeletions(ctx, userID, mq.minT, mq.maxT)
+}
+
+func (mq multiQuerier) filterDeletedSeries(set storage.SeriesSet, deletions []seriesDeletion, minT, maxT int64) storage.SeriesSet {
+	if len(deletions) == 0 {
+		return set
+	}
+	return newSeriesDeletionSeriesSet(set, deletions, minT, maxT)
+}
+
 // LabelValues implements storage.Querier.
 func (mq multiQuerier) LabelValues(ctx context.Context, name string, matchers ...*labels.Matcher) ([]string, annotations.Annotations, error) {
 	ctx, queriers, err := mq.getQueriers(ctx)
@@ -360,6 +580,19 @@ func (mq multiQuerier) LabelValues(ctx context.Context, name string, matchers ..
 		return nil, nil, err
 	}
 
+	deletions, err := mq.getQuerierSeriesDeletions(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	values, warnings, err := mq.labelValues(ctx, queriers, name, matchers...)
+	if err != nil || len(deletions) == 0 || len(values) == 0 {
+		return values, warnings, err
+	}
+	return mq.removeDeletedLabelValues(ctx, queriers, deletions, name, values, warnings, matchers...)
+}
+
+func (mq multiQuerier) labelValues(ctx context.Context, queriers []storage.Querier, name string, matchers ...*labels.Matcher) ([]string, annotations.Annotations, error) {
 	if len(queriers) == 1 {
 		return queriers[0].LabelValues(ctx, name, matchers...)
 	}
@@ -407,6 +640,19 @@ func (mq multiQuerier) LabelNames(ctx context.Context, matchers ...*labels.Match
 		return nil, nil, err
 	}
 
+	deletions, err := mq.getQuerierSeriesDeletions(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	names, warnings, err := mq.labelNames(ctx, queriers, matchers...)
+	if err != nil || len(deletions) == 0 || len(names) == 0 {
+		return names, warnings, err
+	}
+	return mq.removeDeletedLabelNames(ctx, queriers, deletions, names, warnings, matchers...)
+}
+
+func (mq multiQuerier) labelNames(ctx context.Context, queriers []storage.Querier, matchers ...*labels.Matcher) ([]string, annotations.Annotations, error) {
 	if len(queriers) == 1 {
 		return queriers[0].LabelNames(ctx, matchers...)
 	}
@@ -445,6 +691,147 @@ func (mq multiQuerier) LabelNames(ctx context.Context, matchers ...*labels.Match
 	return util.MergeSlices(sets...), warnings, nil
 }
 
+// removeDeletedLabelValues removes the values of the label name held only by series without samples left within
+// the querier time range because of the series deletions. The values of the series matching the deletions are found
+// from the index, so the samples are only fetched for the series partially deleted within the time range, whose
+// values aren't held by any other series.
+func (mq multiQuerier) removeDeletedLabelValues(ctx context.Context, queriers []storage.Querier, deletions []seriesDeletion, name string, values []string, warnings annotations.Annotations, matchers ...*labels.Matcher) ([]string, annotations.Annotations, error) {
+	affected := map[string]struct{}{}
+	for _, deletionMatchers := range seriesDeletionMatchers(deletions) {
+		deletedValues, _, err := mq.labelValues(ctx, queriers, name, append(slices.Clone(matchers), deletionMatchers...)...)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, value := range deletedValues {
+			affected[value] = struct{}{}
+		}
+	}
+	if len(affected) == 0 {
+		return values, warnings, nil
+	}
+
+	affectedValues := make([]string, 0, len(affected))
+	for value := range affected {
+		affectedValues = append(affectedValues, regexp.QuoteMeta(value))
+	}
+	sort.Strings(affectedValues)
+	affectedMatchers := append(slices.Clone(matchers), labels.MustNewMatcher(labels.MatchRegexp, name, strings.Join(affectedValues, "|")))
+
+	// Find out which of the affected values are still held by some series.
+	kept := make(map[string]struct{}, len(affected))
+	err := mq.forEachNonDeletedSeries(ctx, queriers, deletions, &warnings, affectedMatchers,
+		func(lbls labels.Labels) bool {
+			_, ok := kept[lbls.Get(name)]
+			return !ok
+		},
+		func(lbls labels.Labels) bool {
+			kept[lbls.Get(name)] = struct{}{}
+			return len(kept) < len(affected)
+		})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return slices.DeleteFunc(values, func(value string) bool {
+		_, isAffected := affected[value]
+		_, isKept := kept[value]
+		return isAffected && !isKept
+	}), warnings, nil
+}
+
+// removeDeletedLabelNames removes the label names held only by series without samples left within the querier time
+// range because of the series deletions. The names of the series matching the deletions are found from the index,
+// and each of them is kept as soon as a series holding it is found with samples left.
+func (mq multiQuerier) removeDeletedLabelNames(ctx context.Context, queriers []storage.Querier, deletions []seriesDeletion, names []string, warnings annotations.Annotations, matchers ...*labels.Matcher) ([]string, annotations.Annotations, error) {
+	affected := map[string]struct{}{}
+	for _, deletionMatchers := range seriesDeletionMatchers(deletions) {
+		deletedNames, _, err := mq.labelNames(ctx, queriers, append(slices.Clone(matchers), deletionMatchers...)...)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, name := range deletedNames {
+			affected[name] = struct{}{}
+		}
+	}
+	if len(affected) == 0 {
+		return names, warnings, nil
+	}
+
+	removed := map[string]struct{}{}
+	for name := range affected {
+		found := false
+		nameMatchers := append(slices.Clone(matchers), labels.MustNewMatcher(labels.MatchNotEqual, name, ""))
+		err := mq.forEachNonDeletedSeries(ctx, queriers, deletions, &warnings, nameMatchers,
+			func(labels.Labels) bool { return true },
+			func(labels.Labels) bool {
+				found = true
+				return false
+			})
+		if err != nil {
+			return nil, nil, err
+		}
+		if !found {
+			removed[name] = struct{}{}
+		}
+	}
+
+	return slices.DeleteFunc(names, func(name string) bool {
+		_, isRemoved := removed[name]
+		return isRemoved
+	}), warnings, nil
+}
+
+// forEachNonDeletedSeries calls fn with the labels of the series matching the matchers and having samples left within
+// the querier time range, until fn returns false. The series are selected without samples, and the samples are
+// fetched only for the series partially deleted within the time range, when check returns true for them.
+func (mq multiQuerier) forEachNonDeletedSeries(ctx context.Context, queriers []storage.Querier, deletions []seriesDeletion, warnings *annotations.Annotations, matchers []*labels.Matcher, check, fn func(labels.Labels) bool) error {
+	set := mq.selectFromQueriers(ctx, queriers, &storage.SelectHints{Start: mq.minT, End: mq.maxT, Func: "series"}, matchers...)
+	queryRange := tombstones.Interval{Mint: mq.minT, Maxt: mq.maxT}
+
+	for set.Next() {
+		lbls := set.At().Labels()
+
+		if intervals := deletedIntervals(deletions, lbls); len(intervals) > 0 {
+			if queryRange.IsSubrange(intervals) || !check(lbls) {
+				continue
+			}
+			hasSamples, err := mq.seriesHasSamples(ctx, queriers, deletions, warnings, lbls)
+			if err != nil {
+				return err
+			}
+			if !hasSamples {
+				continue
+			}
+		}
+
+		if !fn(lbls) {
+			break
+		}
+	}
+
+	warnings.Merge(set.Warnings())
+	return set.Err()
+}
+
+// seriesHasSamples returns whether the series has samples left within the querier time range, once the series
+// deletions are applied.
+func (mq multiQuerier) seriesHasSamples(ctx context.Context, queriers []storage.Querier, deletions []seriesDeletion, warnings *annotations.Annotations, lbls labels.Labels) (bool, error) {
+	matchers := make([]*labels.Matcher, 0, lbls.Len())
+	lbls.Range(func(l labels.Label) {
+		matchers = append(matchers, labels.MustNewMatcher(labels.MatchEqual, l.Name, l.Value))
+	})
+
+	set := mq.filterDeletedSeries(mq.selectFromQueriers(ctx, queriers, &storage.SelectHints{Start: mq.minT, End: mq.maxT}, matchers...), deletions, mq.minT, mq.maxT)
+	found := false
+	for !found && set.Next() {
+		// The matchers select the series having additional labels too.
+		found = labels.Equal(set.At().Labels(), lbls)
+	}
+
+	warnings.Merge(set.Warnings())
+	return found, set.Err()
+}
+
 func (multiQuerier) Close() error {
 	return nil
 }
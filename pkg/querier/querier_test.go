@@ -16,6 +16,7 @@ import (
 	"github.com/grafana/dskit/user"
 	"github.com/pkg/errors"
 	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/exemplar"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/promql"
 	"github.com/prometheus/prometheus/scrape"
@@ -26,6 +27,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
 
 	"github.com/grafana/mimir/pkg/cardinality"
 	"github.com/grafana/mimir/pkg/ingester/client"
@@ -1184,6 +1186,162 @@ func TestQuerier_QueryStoreAfterConfig(t *testing.T) {
 	}
 }
 
+func TestMultiExemplarQueryable(t *testing.T) {
+	now := time.Now()
+	series1 := labels.FromStrings(labels.MetricName, "metric", "series", "1")
+	series2 := labels.FromStrings(labels.MetricName, "metric", "series", "2")
+	ingesterExemplar := exemplar.Exemplar{Labels: labels.FromStrings("trace_id", "1"), Value: 1, Ts: now.UnixMilli()}
+	storeExemplar := exemplar.Exemplar{Labels: labels.FromStrings("trace_id", "2"), Value: 2, Ts: now.Add(-3 * time.Hour).UnixMilli()}
+
+	testCases := map[string]struct {
+		mint, maxt          time.Time
+		expectedHitIngester bool
+		expectedHitStorage  bool
+		expected            []exemplar.QueryResult
+	}{
+		"hit only ingester": {
+			mint:                now.Add(-5 * time.Minute),
+			maxt:                now,
+			expectedHitIngester: true,
+			expected: []exemplar.QueryResult{
+				{SeriesLabels: series1, Exemplars: []exemplar.Exemplar{ingesterExemplar}},
+			},
+		},
+		"hit both": {
+			mint:                now.Add(-5 * time.Hour),
+			maxt:                now,
+			expectedHitIngester: true,
+			expectedHitStorage:  true,
+			expected: []exemplar.QueryResult{
+				{SeriesLabels: series1, Exemplars: []exemplar.Exemplar{storeExemplar, ingesterExemplar}},
+				{SeriesLabels: series2, Exemplars: []exemplar.Exemplar{storeExemplar}},
+			},
+		},
+		"hit only storage": {
+			mint:               now.Add(-5 * time.Hour),
+			maxt:               now.Add(-2 * time.Hour),
+			expectedHitStorage: true,
+			expected: []exemplar.QueryResult{
+				{SeriesLabels: series1, Exemplars: []exemplar.Exemplar{storeExemplar}},
+				{SeriesLabels: series2, Exemplars: []exemplar.Exemplar{storeExemplar}},
+			},
+		},
+	}
+
+	cfg := Config{}
+	flagext.DefaultValues(&cfg)
+	cfg.QueryStoreAfter = time.Hour
+
+	limits := defaultLimitsConfig()
+	limits.QueryIngestersWithin = model.Duration(time.Hour)
+	overrides, err := validation.NewOverrides(limits, nil)
+	require.NoError(t, err)
+
+	for name, c := range testCases {
+		t.Run(name, func(t *testing.T) {
+			distributor := &mockExemplarQueryable{results: []exemplar.QueryResult{
+				{SeriesLabels: series1, Exemplars: []exemplar.Exemplar{ingesterExemplar}},
+			}}
+			store := &mockExemplarQueryable{results: []exemplar.QueryResult{
+				{SeriesLabels: series2, Exemplars: []exemplar.Exemplar{storeExemplar}},
+				{SeriesLabels: series1, Exemplars: []exemplar.Exemplar{storeExemplar}},
+			}}
+
+			queryable := newMultiExemplarQueryable(distributor, store, cfg, overrides, log.NewNopLogger())
+			querier, err := queryable.ExemplarQuerier(user.InjectOrgID(context.Background(), "0"))
+			require.NoError(t, err)
+
+			matchers := []*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, "metric")}
+			actual, err := querier.Select(c.mint.UnixMilli(), c.maxt.UnixMilli(), matchers)
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, actual)
+			assert.Equal(t, c.expectedHitIngester, distributor.called.Load())
+			assert.Equal(t, c.expectedHitStorage, store.called.Load())
+		})
+	}
+}
+
+type mockExemplarQueryable struct {
+	results []exemplar.QueryResult
+	called  atomic.Bool
+}
+
+func (m *mockExemplarQueryable) ExemplarQuerier(context.Context) (storage.ExemplarQuerier, error) {
+	return m, nil
+}
+
+func (m *mockExemplarQueryable) Select(int64, int64, ...[]*labels.Matcher) ([]exemplar.QueryResult, error) {
+	m.called.Store(true)
+	return m.results, nil
+}
+
+func TestMultiMetadataSupplier(t *testing.T) {
+	ingesterMetadata := scrape.MetricMetadata{Metric: "metric_1", Type: model.MetricTypeCounter, Help: "foo"}
+	storeMetadata := mimirpb.MetricMetadata{Type: mimirpb.GAUGE, MetricFamilyName: "metric_2", Help: "bar", Unit: "seconds"}
+	duplicatedMetadata := mimirpb.MetricMetadata{Type: mimirpb.COUNTER, MetricFamilyName: "metric_1", Help: "foo"}
+
+	req := &client.MetricsMetadataRequest{Limit: -1, LimitPerMetric: -1, Metric: "metric"}
+	distributor := &mockDistributor{}
+	distributor.On("MetricsMetadata", mock.Anything, req).Return([]scrape.MetricMetadata{ingesterMetadata}, nil)
+	store := &mockBlocksStoreMetadataSupplier{metadata: []mimirpb.MetricMetadata{duplicatedMetadata, storeMetadata}}
+
+	cfg := Config{}
+	flagext.DefaultValues(&cfg)
+
+	t.Run("the store-gateways aren't queried if disabled", func(t *testing.T) {
+		assert.Same(t, distributor, NewMetadataSupplier(cfg, distributor, store, log.NewNopLogger()))
+	})
+
+	t.Run("the metadata from the store-gateways is merged with the metadata from the ingesters", func(t *testing.T) {
+		cfg := cfg
+		cfg.QueryStoreForMetadataLookback = 24 * time.Hour
+		supplier := NewMetadataSupplier(cfg, distributor, store, log.NewNopLogger())
+
+		now := time.Now()
+		actual, err := supplier.MetricsMetadata(user.InjectOrgID(context.Background(), "0"), req)
+		require.NoError(t, err)
+		assert.Equal(t, []scrape.MetricMetadata{
+			ingesterMetadata,
+			{Metric: "metric_2", Type: model.MetricTypeGauge, Help: "bar", Unit: "seconds"},
+		}, actual)
+
+		assert.Equal(t, "metric", store.metric)
+		assert.Equal(t, 24*time.Hour, time.Duration(store.maxT-store.minT)*time.Millisecond)
+		assert.InDelta(t, now.UnixMilli(), store.maxT, float64(time.Minute.Milliseconds()))
+		assert.Equal(t, 0, store.limit)
+		assert.Equal(t, 0, store.limitPerMetric)
+	})
+
+	t.Run("the limits of the request are passed to the store-gateways", func(t *testing.T) {
+		cfg := cfg
+		cfg.QueryStoreForMetadataLookback = 24 * time.Hour
+		supplier := NewMetadataSupplier(cfg, distributor, store, log.NewNopLogger())
+
+		limitedReq := &client.MetricsMetadataRequest{Limit: 10, LimitPerMetric: 2, Metric: "metric"}
+		distributor.On("MetricsMetadata", mock.Anything, limitedReq).Return([]scrape.MetricMetadata{ingesterMetadata}, nil)
+
+		_, err := supplier.MetricsMetadata(user.InjectOrgID(context.Background(), "0"), limitedReq)
+		require.NoError(t, err)
+		assert.Equal(t, 10, store.limit)
+		assert.Equal(t, 2, store.limitPerMetric)
+	})
+}
+
+type mockBlocksStoreMetadataSupplier struct {
+	storage.Queryable
+
+	metadata              []mimirpb.MetricMetadata
+	minT, maxT            int64
+	metric                string
+	limit, limitPerMetric int
+}
+
+func (m *mockBlocksStoreMetadataSupplier) MetricsMetadata(_ context.Context, minT, maxT int64, metric string, limit, limitPerMetric int) ([]mimirpb.MetricMetadata, error) {
+	m.minT, m.maxT, m.metric = minT, maxT, metric
+	m.limit, m.limitPerMetric = limit, limitPerMetric
+	return m.metadata, nil
+}
+
 func TestConfig_ValidateLimits(t *testing.T) {
 	tests := map[string]struct {
 		setup    func(cfg *Config, limits *validation.Limits)
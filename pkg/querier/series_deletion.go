@@ -0,0 +1,178 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querier
+
+import (
+	"context"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/prometheus/prometheus/tsdb/chunkenc"
+	"github.com/prometheus/prometheus/tsdb/tombstones"
+
+	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
+)
+
+// SeriesDeletionRequestsFinder is the interface used to find the series deletion requests of a user.
+type SeriesDeletionRequestsFinder interface {
+	// GetSeriesDeletionRequests returns the series deletion requests of userID.
+	GetSeriesDeletionRequests(ctx context.Context, userID string) ([]*mimir_tsdb.SeriesDeletionRequest, error)
+}
+
+// seriesDeletion holds the parsed series deletion requests overlapping with a query time range.
+type seriesDeletion struct {
+	matchers [][]*labels.Matcher
+	interval tombstones.Interval
+}
+
+// newSeriesDeletions returns the series deletions for the requests overlapping with the input time range.
+func newSeriesDeletions(requests []*mimir_tsdb.SeriesDeletionRequest, minT, maxT int64) ([]seriesDeletion, error) {
+	var out []seriesDeletion
+
+	for _, req := range requests {
+		if !req.Overlaps(minT, maxT) {
+			continue
+		}
+
+		matchers, err := req.Matchers()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, seriesDeletion{
+			matchers: matchers,
+			interval: tombstones.Interval{Mint: req.StartTime, Maxt: req.EndTime},
+		})
+	}
+
+	return out, nil
+}
+
+// seriesDeletionMatchers returns the matchers sets of all the series deletions.
+func seriesDeletionMatchers(deletions []seriesDeletion) [][]*labels.Matcher {
+	var out [][]*labels.Matcher
+	for _, deletion := range deletions {
+		out = append(out, deletion.matchers...)
+	}
+	return out
+}
+
+// deletedIntervals returns the sorted and non-overlapping deleted intervals for the series with the input labels.
+func deletedIntervals(deletions []seriesDeletion, lbls labels.Labels) tombstones.Intervals {
+	var out tombstones.Intervals
+
+	for _, deletion := range deletions {
+		for _, matchers := range deletion.matchers {
+			if matchesAll(matchers, lbls) {
+				out = out.Add(deletion.interval)
+				break
+			}
+		}
+	}
+
+	return out
+}
+
+func matchesAll(matchers []*labels.Matcher, lbls labels.Labels) bool {
+	for _, m := range matchers {
+		if !m.Matches(lbls.Get(m.Name)) {
+			return false
+		}
+	}
+	return true
+}
+
+// seriesDeletionSeriesSet wraps a storage.SeriesSet and hides the samples of series
+// matching the series deletions. Series without samples left within the queried time
+// range are removed from the set.
+type seriesDeletionSeriesSet struct {
+	storage.SeriesSet
+
+	deletions  []seriesDeletion
+	minT, maxT int64
+	curr       storage.Series
+	err        error
+}
+
+func newSeriesDeletionSeriesSet(set storage.SeriesSet, deletions []seriesDeletion, minT, maxT int64) storage.SeriesSet {
+	return &seriesDeletionSeriesSet{
+		SeriesSet: set,
+		deletions: deletions,
+		minT:      minT,
+		maxT:      maxT,
+	}
+}
+
+func (s *seriesDeletionSeriesSet) Next() bool {
+	for s.SeriesSet.Next() {
+		series := s.SeriesSet.At()
+
+		intervals := deletedIntervals(s.deletions, series.Labels())
+		if len(intervals) == 0 {
+			s.curr = series
+			return true
+		}
+		if (tombstones.Interval{Mint: s.minT, Maxt: s.maxT}).IsSubrange(intervals) {
+			continue
+		}
+
+		deleted := &seriesDeletionSeries{Series: series, intervals: intervals}
+		hasSamples, err := s.hasSamples(deleted)
+		if err != nil {
+			s.err = err
+			return false
+		}
+		if !hasSamples {
+			continue
+		}
+
+		s.curr = deleted
+		return true
+	}
+
+	return false
+}
+
+func (s *seriesDeletionSeriesSet) At() storage.Series {
+	return s.curr
+}
+
+// hasSamples returns whether the series has samples left within the time range. The series without any sample
+// left are removed from the set, even when they're only partially deleted within the time range.
+func (s *seriesDeletionSeriesSet) hasSamples(series storage.Series) (bool, error) {
+	it := series.Iterator(nil)
+	valueType := it.Next()
+	if valueType != chunkenc.ValNone && it.AtT() < s.minT {
+		valueType = it.Seek(s.minT)
+	}
+	if valueType == chunkenc.ValNone {
+		return false, it.Err()
+	}
+	return it.AtT() <= s.maxT, nil
+}
+
+func (s *seriesDeletionSeriesSet) Err() error {
+	if s.err != nil {
+		return s.err
+	}
+	return s.SeriesSet.Err()
+}
+
+type seriesDeletionSeries struct {
+	storage.Series
+
+	intervals tombstones.Intervals
+}
+
+func (s *seriesDeletionSeries) Iterator(it chunkenc.Iterator) chunkenc.Iterator {
+	// Reuse the wrapped iterator, if possible.
+	if deletedIt, ok := it.(*tsdb.DeletedIterator); ok {
+		it = deletedIt.Iter
+	}
+
+	// The intervals are copied, because the iterator consumes them.
+	return &tsdb.DeletedIterator{
+		Iter:      s.Series.Iterator(it),
+		Intervals: append(tombstones.Intervals(nil), s.intervals...),
+	}
+}
@@ -0,0 +1,246 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querier
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/flagext"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb/chunkenc"
+	"github.com/prometheus/prometheus/util/annotations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/querier/stats"
+	"github.com/grafana/mimir/pkg/storage/series"
+	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
+	"github.com/grafana/mimir/pkg/util/validation"
+)
+
+func TestSeriesDeletionSeriesSet(t *testing.T) {
+	newSeries := func(job string, timestamps ...model.Time) storage.Series {
+		if len(timestamps) == 0 {
+			timestamps = []model.Time{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+		}
+		samples := make([]model.SamplePair, 0, len(timestamps))
+		for _, ts := range timestamps {
+			samples = append(samples, model.SamplePair{Timestamp: ts, Value: model.SampleValue(ts)})
+		}
+		return series.NewConcreteSeries(labels.FromStrings("job", job), samples, nil)
+	}
+
+	requests := []*mimir_tsdb.SeriesDeletionRequest{
+		// Deletes a portion of the samples of the series with job="partial".
+		{Selectors: []string{`{job="partial"}`}, StartTime: 2, EndTime: 4},
+		// Deletes another portion of the samples of the series with job="partial" or job="other".
+		{Selectors: []string{`{job="other"}`, `{job="partial"}`}, StartTime: 7, EndTime: 7},
+		// Deletes all the samples of the series with job="full".
+		{Selectors: []string{`{job="full"}`}, StartTime: 0, EndTime: 100},
+		// Deletes all the samples of the series with job="sparse" within the queried time range.
+		{Selectors: []string{`{job="sparse"}`}, StartTime: 2, EndTime: 6},
+		// Doesn't overlap with the queried time range.
+		{Selectors: []string{`{job="unaffected"}`}, StartTime: 50, EndTime: 100},
+	}
+
+	deletions, err := newSeriesDeletions(requests, 0, 9)
+	require.NoError(t, err)
+	require.Len(t, deletions, 4)
+
+	set := newSeriesDeletionSeriesSet(&sliceSeriesSet{
+		series: []storage.Series{
+			newSeries("full"),
+			newSeries("other"),
+			newSeries("partial"),
+			newSeries("sparse", 3, 5, 12),
+			newSeries("unaffected"),
+		},
+		ix: -1,
+	}, deletions, 0, 9)
+
+	actual := map[string][]int64{}
+	var it chunkenc.Iterator
+	for set.Next() {
+		s := set.At()
+		it = s.Iterator(it)
+
+		var timestamps []int64
+		for it.Next() != chunkenc.ValNone {
+			timestamps = append(timestamps, it.AtT())
+		}
+		require.NoError(t, it.Err())
+		actual[s.Labels().Get("job")] = timestamps
+	}
+	require.NoError(t, set.Err())
+
+	assert.Equal(t, map[string][]int64{
+		"other":      {0, 1, 2, 3, 4, 5, 6, 8, 9},
+		"partial":    {0, 1, 5, 6, 8, 9},
+		"unaffected": {0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+	}, actual)
+}
+
+func TestNewSeriesDeletions_InvalidSelector(t *testing.T) {
+	_, err := newSeriesDeletions([]*mimir_tsdb.SeriesDeletionRequest{{Selectors: []string{`{job=}`}, StartTime: 0, EndTime: 10}}, 0, 10)
+	require.Error(t, err)
+}
+
+func TestMultiQuerier_SeriesDeletions(t *testing.T) {
+	store := &seriesDeletionMockQuerier{series: []labels.Labels{
+		labels.FromStrings(labels.MetricName, "deleted", "job", "a", "pii", "secret"),
+		labels.FromStrings(labels.MetricName, "kept", "job", "b"),
+	}}
+
+	var cfg Config
+	flagext.DefaultValues(&cfg)
+	overrides, err := validation.NewOverrides(defaultLimitsConfig(), nil)
+	require.NoError(t, err)
+
+	finder := &seriesDeletionMockFinder{}
+	queryable := newQueryable(nil, storage.QueryableFunc(func(int64, int64) (storage.Querier, error) {
+		return store, nil
+	}), finder, cfg, overrides, stats.NewQueryMetrics(nil), log.NewNopLogger())
+
+	ctx := user.InjectOrgID(context.Background(), "user-1")
+	querier, err := queryable.Querier(0, 30)
+	require.NoError(t, err)
+
+	query := func() (names, values, seriesNames []string) {
+		store.samplesSelects = 0
+		names, _, err := querier.LabelNames(ctx)
+		require.NoError(t, err)
+		values, _, err = querier.LabelValues(ctx, "job")
+		require.NoError(t, err)
+
+		set := querier.Select(ctx, true, &storage.SelectHints{Start: 0, End: 30, Func: "series"}, labels.MustNewMatcher(labels.MatchRegexp, labels.MetricName, ".+"))
+		for set.Next() {
+			seriesNames = append(seriesNames, set.At().Labels().Get(labels.MetricName))
+		}
+		require.NoError(t, set.Err())
+		return names, values, seriesNames
+	}
+
+	t.Run("without series deletion requests", func(t *testing.T) {
+		names, values, seriesNames := query()
+		assert.Equal(t, []string{labels.MetricName, "job", "pii"}, names)
+		assert.Equal(t, []string{"a", "b"}, values)
+		assert.Equal(t, []string{"deleted", "kept"}, seriesNames)
+	})
+
+	t.Run("with a series deletion request covering part of the time range and all the samples of the series", func(t *testing.T) {
+		finder.requests = []*mimir_tsdb.SeriesDeletionRequest{{Selectors: []string{`{pii="secret"}`}, StartTime: 5, EndTime: 25}}
+
+		names, values, seriesNames := query()
+		assert.Equal(t, []string{labels.MetricName, "job"}, names)
+		assert.Equal(t, []string{"b"}, values)
+		assert.Equal(t, []string{"kept"}, seriesNames)
+	})
+
+	t.Run("with a series deletion request covering some samples of the series", func(t *testing.T) {
+		finder.requests = []*mimir_tsdb.SeriesDeletionRequest{{Selectors: []string{`{pii="secret"}`}, StartTime: 5, EndTime: 15}}
+
+		names, values, seriesNames := query()
+		assert.Equal(t, []string{labels.MetricName, "job", "pii"}, names)
+		assert.Equal(t, []string{"a", "b"}, values)
+		assert.Equal(t, []string{"deleted", "kept"}, seriesNames)
+	})
+
+	t.Run("with a series deletion request not matching any series", func(t *testing.T) {
+		finder.requests = []*mimir_tsdb.SeriesDeletionRequest{{Selectors: []string{`{job="unknown"}`}, StartTime: 5, EndTime: 15}}
+
+		names, values, _ := query()
+		assert.Equal(t, []string{labels.MetricName, "job", "pii"}, names)
+		assert.Equal(t, []string{"a", "b"}, values)
+
+		// Only the series select fetches the samples, because the deletion doesn't match any series.
+		assert.Equal(t, 1, store.samplesSelects)
+	})
+
+	t.Run("with a series deletion request not matching the label values matchers", func(t *testing.T) {
+		finder.requests = []*mimir_tsdb.SeriesDeletionRequest{{Selectors: []string{`{pii="secret"}`}, StartTime: 5, EndTime: 15}}
+		store.samplesSelects = 0
+
+		values, _, err := querier.LabelValues(ctx, "job", labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, "kept"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"b"}, values)
+		assert.Equal(t, 0, store.samplesSelects)
+	})
+}
+
+type seriesDeletionMockFinder struct {
+	requests []*mimir_tsdb.SeriesDeletionRequest
+}
+
+func (f *seriesDeletionMockFinder) GetSeriesDeletionRequests(context.Context, string) ([]*mimir_tsdb.SeriesDeletionRequest, error) {
+	return f.requests, nil
+}
+
+// seriesDeletionMockQuerier returns the series matching the matchers, without samples for the series-only selects
+// like the store-gateways do.
+type seriesDeletionMockQuerier struct {
+	series []labels.Labels
+
+	// Number of selects fetching the samples.
+	samplesSelects int
+}
+
+func (q *seriesDeletionMockQuerier) Select(_ context.Context, _ bool, hints *storage.SelectHints, matchers ...*labels.Matcher) storage.SeriesSet {
+	if hints.Func != "series" {
+		q.samplesSelects++
+	}
+
+	set := &sliceSeriesSet{ix: -1}
+	for _, lbls := range q.series {
+		if !matchesAll(matchers, lbls) {
+			continue
+		}
+		if hints.Func == "series" {
+			set.series = append(set.series, series.NewConcreteSeries(lbls, nil, nil))
+		} else {
+			set.series = append(set.series, series.NewConcreteSeries(lbls, []model.SamplePair{{Timestamp: 10, Value: 1}, {Timestamp: 20, Value: 2}}, nil))
+		}
+	}
+	return set
+}
+
+func (q *seriesDeletionMockQuerier) LabelValues(_ context.Context, name string, matchers ...*labels.Matcher) ([]string, annotations.Annotations, error) {
+	unique := map[string]struct{}{}
+	for _, lbls := range q.series {
+		if value := lbls.Get(name); value != "" && matchesAll(matchers, lbls) {
+			unique[value] = struct{}{}
+		}
+	}
+	values := make([]string, 0, len(unique))
+	for value := range unique {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	return values, nil, nil
+}
+
+func (q *seriesDeletionMockQuerier) LabelNames(_ context.Context, matchers ...*labels.Matcher) ([]string, annotations.Annotations, error) {
+	unique := map[string]struct{}{}
+	for _, lbls := range q.series {
+		if matchesAll(matchers, lbls) {
+			lbls.Range(func(l labels.Label) {
+				unique[l.Name] = struct{}{}
+			})
+		}
+	}
+	names := make([]string, 0, len(unique))
+	for name := range unique {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil, nil
+}
+
+func (q *seriesDeletionMockQuerier) Close() error {
+	return nil
+}
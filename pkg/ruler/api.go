@@ -25,6 +25,8 @@ import (
 	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/model/rulefmt"
+	"github.com/prometheus/prometheus/rules"
+	"go.uber.org/atomic"
 	"gopkg.in/yaml.v3"
 
 	"github.com/grafana/mimir/pkg/mimirpb"
@@ -141,15 +143,21 @@ type API struct {
 	ruler *Ruler
 	store rulestore.RuleStore
 
+	// queryFunc is used to evaluate the rules when backfilling rule groups.
+	queryFunc rules.QueryFunc
+	// backfills is the number of rule group backfills in progress.
+	backfills atomic.Int64
+
 	logger log.Logger
 }
 
-// NewAPI returns a new API struct with the provided ruler and rule store
-func NewAPI(r *Ruler, s rulestore.RuleStore, logger log.Logger) *API {
+// NewAPI returns a new API struct with the provided ruler, rule store and query function
+func NewAPI(r *Ruler, s rulestore.RuleStore, queryFunc rules.QueryFunc, logger log.Logger) *API {
 	return &API{
-		ruler:  r,
-		store:  s,
-		logger: logger,
+		ruler:     r,
+		store:     s,
+		queryFunc: queryFunc,
+		logger:    logger,
 	}
 }
 
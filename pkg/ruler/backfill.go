@@ -0,0 +1,177 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package ruler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/pkg/errors"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/prometheus/prometheus/rules"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/ruler/rulespb"
+	"github.com/grafana/mimir/pkg/ruler/rulestore"
+	"github.com/grafana/mimir/pkg/util"
+	"github.com/grafana/mimir/pkg/util/spanlogger"
+)
+
+// maxBackfillEvaluations is the maximum number of evaluations of a rule group in a backfill request.
+const maxBackfillEvaluations = 11000
+
+var errBackfillNativeHistograms = errors.New("backfilling recording rules returning native histograms is not supported")
+
+// BackfillResult is the result of the backfill of a rule group: the series recorded by its recording rules.
+type BackfillResult struct {
+	ResultType string       `json:"resultType"`
+	Result     model.Matrix `json:"result"`
+}
+
+// BackfillRuleGroup evaluates the recording rules of a rule group over a past time range, at the rule group
+// evaluation interval, and returns the recorded series. The alerting rules of the rule group are ignored.
+// The recording rules are evaluated independently, so a recording rule depending on the series recorded by
+// another recording rule only gets the series which have already been recorded.
+func (a *API) BackfillRuleGroup(w http.ResponseWriter, req *http.Request) {
+	logger, ctx := spanlogger.NewWithLogger(req.Context(), a.logger, "API.BackfillRuleGroup")
+	defer logger.Finish()
+
+	userID, namespace, groupName, err := parseRequest(req, true, true)
+	if err != nil {
+		respondServerError(logger, w, err.Error())
+		return
+	}
+
+	start, err := util.ParseTimeParam(req, "start", 0)
+	if err != nil {
+		respondInvalidRequest(logger, w, err.Error())
+		return
+	}
+	end, err := util.ParseTimeParam(req, "end", 0)
+	if err != nil {
+		respondInvalidRequest(logger, w, err.Error())
+		return
+	}
+	if start == 0 || end == 0 {
+		respondInvalidRequest(logger, w, "start and end parameters are required")
+		return
+	}
+	if end < start {
+		respondInvalidRequest(logger, w, "end timestamp must not be before start time")
+		return
+	}
+
+	rg, err := a.store.GetRuleGroup(ctx, userID, namespace, groupName)
+	if err != nil {
+		if errors.Is(err, rulestore.ErrGroupNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		respondServerError(logger, w, err.Error())
+		return
+	}
+
+	interval := a.ruler.cfg.EvaluationInterval
+	if rg.Interval > 0 {
+		interval = rg.Interval
+	}
+	if evaluations := (end-start)/interval.Milliseconds() + 1; evaluations > maxBackfillEvaluations {
+		respondInvalidRequest(logger, w, fmt.Sprintf("the time range would require %d evaluations of the rule group, which exceeds the maximum of %d evaluations: reduce the time range", evaluations, maxBackfillEvaluations))
+		return
+	}
+
+	maxConcurrency := int64(a.ruler.cfg.MaxConcurrentBackfills)
+	currentBackfills := a.backfills.Inc()
+	defer a.backfills.Dec()
+	if maxConcurrency > 0 && currentBackfills > maxConcurrency {
+		respondError(logger, w, http.StatusTooManyRequests, v1.ErrClient, fmt.Sprintf("too many rule group backfills in progress, limit is %d", maxConcurrency))
+		return
+	}
+
+	evalDelay := a.ruler.limits.EvaluationDelay(userID)
+	if rg.EvaluationDelay > 0 {
+		evalDelay = rg.EvaluationDelay
+	}
+
+	level.Info(logger).Log("msg", "backfilling rule group", "user", userID, "namespace", namespace, "group", groupName, "start", util.FormatTimeMillis(start), "end", util.FormatTimeMillis(end))
+
+	result, err := backfillRuleGroup(ctx, rg, a.queryFunc, util.TimeFromMillis(start), util.TimeFromMillis(end), interval, evalDelay)
+	if err != nil {
+		if errors.Is(err, errBackfillNativeHistograms) {
+			respondInvalidRequest(logger, w, err.Error())
+			return
+		}
+		respondServerError(logger, w, err.Error())
+		return
+	}
+
+	util.WriteJSONResponse(w, &response{
+		Status: "success",
+		Data:   result,
+	})
+}
+
+// backfillRuleGroup evaluates the recording rules of the rule group at each multiple of the interval between start and end.
+func backfillRuleGroup(ctx context.Context, rg *rulespb.RuleGroupDesc, queryFunc rules.QueryFunc, start, end time.Time, interval, evalDelay time.Duration) (*BackfillResult, error) {
+	if len(rg.SourceTenants) > 0 {
+		ctx = context.WithValue(ctx, federatedGroupSourceTenants, rg.SourceTenants)
+	}
+
+	var recordingRules []*rules.RecordingRule
+	for _, r := range rg.Rules {
+		if r.Record == "" {
+			continue
+		}
+		expr, err := parser.ParseExpr(r.Expr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse expression of recording rule %s", r.Record)
+		}
+		recordingRules = append(recordingRules, rules.NewRecordingRule(r.Record, expr, mimirpb.FromLabelAdaptersToLabels(r.Labels)))
+	}
+
+	// The evaluations are aligned on the interval, so that consecutive time ranges can be backfilled
+	// without overlapping evaluations.
+	first := (start.UnixMilli() + interval.Milliseconds() - 1) / interval.Milliseconds() * interval.Milliseconds()
+
+	series := map[string]*model.SampleStream{}
+	for ts := time.UnixMilli(first); !ts.After(end); ts = ts.Add(interval) {
+		for _, rule := range recordingRules {
+			vector, err := rule.Eval(ctx, evalDelay, ts, queryFunc, nil, 0)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to evaluate recording rule %s at %s", rule.Name(), ts.UTC().Format(time.RFC3339))
+			}
+
+			for _, sample := range vector {
+				if sample.H != nil {
+					return nil, errBackfillNativeHistograms
+				}
+
+				key := sample.Metric.String()
+				stream := series[key]
+				if stream == nil {
+					stream = &model.SampleStream{Metric: util.LabelsToMetric(sample.Metric)}
+					series[key] = stream
+				}
+				// The sample timestamp is the evaluation time minus the evaluation delay, like the samples
+				// recorded by the rule group evaluations.
+				stream.Values = append(stream.Values, model.SamplePair{
+					Timestamp: model.Time(sample.T),
+					Value:     model.SampleValue(sample.F),
+				})
+			}
+		}
+	}
+
+	result := &BackfillResult{ResultType: model.ValMatrix.String(), Result: make(model.Matrix, 0, len(series))}
+	for _, stream := range series {
+		result.Result = append(result.Result, stream)
+	}
+	sort.Sort(result.Result)
+	return result, nil
+}
@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package ruler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/gorilla/mux"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+	"github.com/grafana/mimir/pkg/ruler/rulespb"
+)
+
+func TestAPI_BackfillRuleGroup(t *testing.T) {
+	const userID = "user-1"
+
+	recordingRule := createRecordingRule("job:up:sum", "sum by (job) (up)")
+	recordingRule.Labels = []mimirpb.LabelAdapter{{Name: "env", Value: "prod"}}
+	histogramRule := createRecordingRule("job:latency:sum", "sum by (job) (latency)")
+	delayedGroup := createRuleGroup("delayed", userID, createRecordingRule("job:up:count", "count by (job) (up)"))
+	delayedGroup.EvaluationDelay = 30 * time.Second
+
+	mockRulesNamespaces := map[string]rulespb.RuleGroupList{
+		userID: {
+			createRuleGroup("group-1", userID, recordingRule, createAlertingRule("UP_ALERT", "up < 1")),
+			createRuleGroup("histograms", userID, histogramRule),
+			delayedGroup,
+		},
+	}
+
+	var queries []string
+	queryFunc := func(_ context.Context, qs string, ts time.Time) (promql.Vector, error) {
+		queries = append(queries, qs)
+		if qs == histogramRule.Expr {
+			return promql.Vector{{Metric: labels.FromStrings("job", "api"), T: ts.UnixMilli(), H: &histogram.FloatHistogram{}}}, nil
+		}
+		return promql.Vector{
+			{Metric: labels.FromStrings("job", "api"), T: ts.UnixMilli(), F: float64(ts.Unix())},
+			{Metric: labels.FromStrings("job", "db"), T: ts.UnixMilli(), F: 1},
+		}, nil
+	}
+
+	r := prepareRuler(t, defaultRulerConfig(t), newMockRuleStore(mockRulesNamespaces))
+	a := NewAPI(r, r.directStore, queryFunc, log.NewNopLogger())
+
+	router := mux.NewRouter()
+	router.Path("/prometheus/config/v1/rules/{namespace}/{groupName}/backfill").Methods(http.MethodPost).HandlerFunc(a.BackfillRuleGroup)
+
+	serve := func(url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, requestFor(t, http.MethodPost, url, nil, userID))
+		return w
+	}
+
+	w := serve("https://localhost:8080/prometheus/config/v1/rules/test/group-1/backfill?start=600&end=720")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp struct {
+		Status string         `json:"status"`
+		Data   BackfillResult `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "success", resp.Status)
+	assert.Equal(t, "matrix", resp.Data.ResultType)
+	assert.Equal(t, model.Matrix{
+		{
+			Metric: model.Metric{"__name__": "job:up:sum", "env": "prod", "job": "api"},
+			Values: []model.SamplePair{{Timestamp: 600000, Value: 600}, {Timestamp: 660000, Value: 660}, {Timestamp: 720000, Value: 720}},
+		},
+		{
+			Metric: model.Metric{"__name__": "job:up:sum", "env": "prod", "job": "db"},
+			Values: []model.SamplePair{{Timestamp: 600000, Value: 1}, {Timestamp: 660000, Value: 1}, {Timestamp: 720000, Value: 1}},
+		},
+	}, resp.Data.Result)
+	// The alerting rule isn't evaluated.
+	assert.Equal(t, []string{recordingRule.Expr, recordingRule.Expr, recordingRule.Expr}, queries)
+
+	// The recorded samples are timestamped at the evaluation time minus the evaluation delay.
+	w = serve("https://localhost:8080/prometheus/config/v1/rules/test/delayed/backfill?start=600&end=660")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Data.Result, 2)
+	assert.Equal(t, []model.SamplePair{{Timestamp: 570000, Value: 570}, {Timestamp: 630000, Value: 630}}, resp.Data.Result[0].Values)
+
+	// The backfills exceeding the max concurrency are rejected.
+	a.backfills.Store(int64(r.cfg.MaxConcurrentBackfills))
+	w = serve("https://localhost:8080/prometheus/config/v1/rules/test/group-1/backfill?start=600&end=720")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code, w.Body.String())
+	a.backfills.Store(0)
+
+	for url, expectedCode := range map[string]int{
+		"https://localhost:8080/prometheus/config/v1/rules/test/group-1/backfill":                       http.StatusBadRequest,
+		"https://localhost:8080/prometheus/config/v1/rules/test/group-1/backfill?start=720&end=600":     http.StatusBadRequest,
+		"https://localhost:8080/prometheus/config/v1/rules/test/group-1/backfill?start=600&end=1000000": http.StatusBadRequest,
+		"https://localhost:8080/prometheus/config/v1/rules/test/unknown/backfill?start=600&end=720":     http.StatusNotFound,
+		"https://localhost:8080/prometheus/config/v1/rules/test/histograms/backfill?start=600&end=720":  http.StatusBadRequest,
+	} {
+		w := serve(url)
+		assert.Equal(t, expectedCode, w.Code, url)
+	}
+}
@@ -309,6 +309,9 @@ func DefaultTenantManagerFactory(
 		wrappedQueryFunc := WrapQueryFuncWithReadConsistency(queryFunc, logger)
 		wrappedQueryFunc = MetricsQueryFunc(wrappedQueryFunc, totalQueries, failedQueries)
 		wrappedQueryFunc = RecordAndReportRuleQueryMetrics(wrappedQueryFunc, queryTime, zeroFetchedSeriesCount, logger)
+		if cfg.EvaluationHistory.Enabled {
+			wrappedQueryFunc = EvaluationHistoryQueryFunc(wrappedQueryFunc)
+		}
 
 		// Wrap the queryable with our custom logic.
 		wrappedQueryable := WrapQueryableWithReadConsistency(queryable, logger)
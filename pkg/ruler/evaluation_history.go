@@ -0,0 +1,737 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package ruler
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/concurrency"
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/tenant"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	promRules "github.com/prometheus/prometheus/rules"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/mimir/pkg/ruler/rulestore"
+	"github.com/grafana/mimir/pkg/storage/bucket"
+	"github.com/grafana/mimir/pkg/util"
+	"github.com/grafana/mimir/pkg/util/spanlogger"
+)
+
+const (
+	// EvaluationHistoryPrefix is the ruler storage prefix under which the rule evaluation history of the tenants is persisted.
+	EvaluationHistoryPrefix = "rule-evaluation-history"
+
+	evaluationSamplesCtxKey contextKey = 2
+
+	// evaluationHistoryConcurrency is the max number of rule group histories concurrently read from or checked in
+	// the ruler storage.
+	evaluationHistoryConcurrency = 16
+
+	// evaluationHistoryCleanupInterval is how frequently the persisted history of the rule groups which haven't been
+	// evaluated for longer than the retention is deleted from the ruler storage.
+	evaluationHistoryCleanupInterval = time.Hour
+)
+
+var (
+	errInvalidEvaluationHistoryMaxEntries      = errors.New("the rule evaluation history max entries per rule group must be greater than 0")
+	errInvalidEvaluationHistoryRetention       = errors.New("the rule evaluation history retention must be greater than 0")
+	errInvalidEvaluationHistoryPersistInterval = errors.New("the rule evaluation history persist interval must be greater than 0")
+	errEvaluationHistoryLocalStorage           = errors.New("the rule evaluation history requires an object storage backend for the ruler storage")
+)
+
+// EvaluationHistoryConfig configures the history of the rule evaluations.
+type EvaluationHistoryConfig struct {
+	Enabled                bool          `yaml:"enabled" category:"experimental"`
+	MaxEntriesPerRuleGroup int           `yaml:"max_entries_per_rule_group" category:"experimental"`
+	Retention              time.Duration `yaml:"retention" category:"experimental"`
+	PersistInterval        time.Duration `yaml:"persist_interval" category:"experimental"`
+}
+
+func (cfg *EvaluationHistoryConfig) RegisterFlags(f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, "ruler.evaluation-history.enabled", false, "True to keep the history of the rule evaluations, persisted to the ruler storage and exposed by the rule evaluation history API. The history of an evaluation contains its duration, number of samples, error and alert state transitions.")
+	f.IntVar(&cfg.MaxEntriesPerRuleGroup, "ruler.evaluation-history.max-entries-per-rule-group", 10000, "Maximum number of rule evaluations kept in the history of each rule group.")
+	f.DurationVar(&cfg.Retention, "ruler.evaluation-history.retention", 24*time.Hour, "How long the rule evaluations are kept in the history.")
+	f.DurationVar(&cfg.PersistInterval, "ruler.evaluation-history.persist-interval", time.Minute, "How frequently the history of the rule groups evaluated by the ruler is persisted to the ruler storage.")
+}
+
+func (cfg *EvaluationHistoryConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.MaxEntriesPerRuleGroup <= 0 {
+		return errInvalidEvaluationHistoryMaxEntries
+	}
+	if cfg.Retention <= 0 {
+		return errInvalidEvaluationHistoryRetention
+	}
+	if cfg.PersistInterval <= 0 {
+		return errInvalidEvaluationHistoryPersistInterval
+	}
+	return nil
+}
+
+// RuleEvaluation is the outcome of an evaluation of a rule.
+type RuleEvaluation struct {
+	Namespace        string            `json:"namespace"`
+	Group            string            `json:"group"`
+	Rule             string            `json:"rule"`
+	Type             string            `json:"type"`
+	Timestamp        time.Time         `json:"timestamp"`
+	EvaluationTime   float64           `json:"evaluationTime"`
+	Samples          int               `json:"samples"`
+	Health           string            `json:"health"`
+	Error            string            `json:"error,omitempty"`
+	AlertTransitions []AlertTransition `json:"alertTransitions,omitempty"`
+}
+
+// AlertTransition is a change of the state of an alert during a rule evaluation.
+type AlertTransition struct {
+	Labels labels.Labels `json:"labels"`
+	From   string        `json:"from"`
+	To     string        `json:"to"`
+}
+
+// EvaluationHistoryResult is the result of the rule evaluation history API.
+type EvaluationHistoryResult struct {
+	Evaluations []RuleEvaluation `json:"evaluations"`
+}
+
+// evaluationHistoryObject is the content of the object persisting the history of a rule group.
+type evaluationHistoryObject struct {
+	Evaluations []RuleEvaluation `json:"evaluations"`
+}
+
+// EvaluationHistory keeps the history of the evaluations of the rule groups evaluated by the ruler, and periodically
+// persists it to the ruler storage, with an object per rule group. The history of a rule group is loaded from the
+// ruler storage in the background when the ruler starts evaluating it, so that the history continues when the rule
+// group moves between rulers. The persisted history of the rule groups which haven't been evaluated by any ruler for
+// longer than the retention, including the removed rule groups and tenants, is periodically deleted.
+type EvaluationHistory struct {
+	services.Service
+
+	cfg         EvaluationHistoryConfig
+	bucket      objstore.Bucket
+	cfgProvider bucket.TenantConfigProvider
+	logger      log.Logger
+
+	mtx    sync.Mutex
+	groups map[string]map[string]*groupEvaluationHistory // First key = user, second key = namespace and group name.
+
+	// load is notified when the history of a rule group has to be loaded.
+	load        chan struct{}
+	lastCleanup time.Time
+
+	persistFailures prometheus.Counter
+}
+
+// groupEvaluationHistory is the history of a rule group.
+type groupEvaluationHistory struct {
+	userID, namespace, name string
+
+	mtx sync.Mutex
+	// loaded is true once the history persisted to the ruler storage has been loaded, and loading while
+	// it's being loaded, without holding the lock.
+	loaded      bool
+	loading     bool
+	evaluations []RuleEvaluation
+	dirty       bool
+	// active is false once the rule group isn't evaluated by this ruler anymore, so that its history is
+	// removed from memory after being persisted.
+	active bool
+	// alerts holds the pending and firing alerts of the alerting rules, by rule name, after the last evaluation.
+	alerts map[string]map[uint64]trackedAlert
+}
+
+type trackedAlert struct {
+	labels labels.Labels
+	state  promRules.AlertState
+}
+
+// NewEvaluationHistory returns the rule evaluation history, persisted to the ruler storage.
+func NewEvaluationHistory(ctx context.Context, cfg EvaluationHistoryConfig, storageCfg rulestore.Config, cfgProvider bucket.TenantConfigProvider, logger log.Logger, reg prometheus.Registerer) (*EvaluationHistory, error) {
+	if storageCfg.Backend == rulestore.BackendLocal {
+		return nil, errEvaluationHistoryLocalStorage
+	}
+
+	bkt, err := bucket.NewClient(ctx, storageCfg.Config, "ruler-evaluation-history", logger, reg)
+	if err != nil {
+		return nil, err
+	}
+	return newEvaluationHistory(cfg, bkt, cfgProvider, logger, reg), nil
+}
+
+func newEvaluationHistory(cfg EvaluationHistoryConfig, bkt objstore.Bucket, cfgProvider bucket.TenantConfigProvider, logger log.Logger, reg prometheus.Registerer) *EvaluationHistory {
+	h := &EvaluationHistory{
+		cfg:         cfg,
+		bucket:      bucket.NewPrefixedBucketClient(bkt, EvaluationHistoryPrefix),
+		cfgProvider: cfgProvider,
+		logger:      logger,
+		groups:      map[string]map[string]*groupEvaluationHistory{},
+		load:        make(chan struct{}, 1),
+		persistFailures: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "cortex_ruler_evaluation_history_persist_failures_total",
+			Help: "The total number of failures persisting the history of a rule group to the ruler storage.",
+		}),
+	}
+	h.Service = services.NewBasicService(nil, h.running, h.stopping).WithName("ruler evaluation history")
+	return h
+}
+
+func (h *EvaluationHistory) running(ctx context.Context) error {
+	persistTicker := time.NewTicker(h.cfg.PersistInterval)
+	defer persistTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-h.load:
+			h.loadGroups(ctx)
+		case <-persistTicker.C:
+			h.persist(ctx)
+			if time.Since(h.lastCleanup) >= evaluationHistoryCleanupInterval {
+				h.cleanup(ctx, time.Now())
+				h.lastCleanup = time.Now()
+			}
+		}
+	}
+}
+
+func (h *EvaluationHistory) stopping(_ error) error {
+	// Persist the history of the last evaluations before shutting down.
+	h.persist(context.Background())
+	return nil
+}
+
+// evalIterationFunc returns the function evaluating the rule groups of the tenant, which records the outcome
+// of each evaluation in the history. The rule group files are in the rulesDir directory.
+func (h *EvaluationHistory) evalIterationFunc(userID, rulesDir string) promRules.GroupEvalIterationFunc {
+	return func(ctx context.Context, g *promRules.Group, evalTimestamp time.Time) {
+		samples := &evaluationSamples{counts: map[string]int{}}
+		promRules.DefaultEvalIterationFunc(context.WithValue(ctx, evaluationSamplesCtxKey, samples), g, evalTimestamp)
+
+		namespace, err := decodeNamespace(rulesDir, g.File())
+		if err != nil {
+			level.Warn(h.logger).Log("msg", "unable to record the rule group evaluation in the history", "user", userID, "file", g.File(), "group", g.Name(), "err", err)
+			return
+		}
+		h.record(userID, namespace, g, evalTimestamp, samples)
+	}
+}
+
+// record adds the outcome of the evaluation of the rules of the group to its history.
+func (h *EvaluationHistory) record(userID, namespace string, g *promRules.Group, evalTimestamp time.Time, samples *evaluationSamples) {
+	gh := h.group(userID, namespace, g.Name())
+
+	gh.mtx.Lock()
+	defer gh.mtx.Unlock()
+
+	alerts := map[string]map[uint64]trackedAlert{}
+	for _, ar := range g.AlertingRules() {
+		ruleAlerts := alerts[ar.Name()]
+		if ruleAlerts == nil {
+			ruleAlerts = map[uint64]trackedAlert{}
+			alerts[ar.Name()] = ruleAlerts
+		}
+		for _, a := range ar.ActiveAlerts() {
+			ruleAlerts[a.Labels.Hash()] = trackedAlert{labels: a.Labels, state: a.State}
+		}
+	}
+
+	transitionsRecorded := map[string]bool{}
+	for _, r := range g.Rules() {
+		evaluation := RuleEvaluation{
+			Namespace:      namespace,
+			Group:          g.Name(),
+			Rule:           r.Name(),
+			Type:           "recording",
+			Timestamp:      evalTimestamp,
+			EvaluationTime: r.GetEvaluationDuration().Seconds(),
+			Samples:        samples.get(r.Query().String()),
+			Health:         string(r.Health()),
+		}
+		if err := r.LastError(); err != nil {
+			evaluation.Error = err.Error()
+		}
+		if _, ok := r.(*promRules.AlertingRule); ok {
+			evaluation.Type = "alerting"
+			// The transitions of the alerting rules with the same name are recorded once.
+			if !transitionsRecorded[r.Name()] {
+				evaluation.AlertTransitions = alertTransitions(gh.alerts[r.Name()], alerts[r.Name()])
+				transitionsRecorded[r.Name()] = true
+			}
+		}
+		gh.evaluations = append(gh.evaluations, evaluation)
+	}
+	gh.alerts = alerts
+	gh.dirty = true
+
+	gh.trim(evalTimestamp.Add(-h.cfg.Retention), h.cfg.MaxEntriesPerRuleGroup)
+}
+
+// group returns the history of the rule group, creating it if it doesn't exist. The history persisted by the
+// previous rulers is loaded in the background.
+func (h *EvaluationHistory) group(userID, namespace, name string) *groupEvaluationHistory {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	return h.groupLocked(userID, namespace, name)
+}
+
+// groupLocked is like group, but must be called with the lock held.
+func (h *EvaluationHistory) groupLocked(userID, namespace, name string) *groupEvaluationHistory {
+	userGroups := h.groups[userID]
+	if userGroups == nil {
+		userGroups = map[string]*groupEvaluationHistory{}
+		h.groups[userID] = userGroups
+	}
+
+	key := evaluationHistoryGroupKey(namespace, name)
+	gh := userGroups[key]
+	if gh == nil {
+		gh = &groupEvaluationHistory{userID: userID, namespace: namespace, name: name}
+		userGroups[key] = gh
+
+		select {
+		case h.load <- struct{}{}:
+		default:
+		}
+	}
+	gh.mtx.Lock()
+	gh.active = true
+	gh.mtx.Unlock()
+	return gh
+}
+
+// loadGroups loads the persisted history of the rule groups which haven't been loaded yet.
+func (h *EvaluationHistory) loadGroups(ctx context.Context) {
+	h.mtx.Lock()
+	var groups []*groupEvaluationHistory
+	for _, userGroups := range h.groups {
+		for _, gh := range userGroups {
+			groups = append(groups, gh)
+		}
+	}
+	h.mtx.Unlock()
+
+	_ = concurrency.ForEachJob(ctx, len(groups), evaluationHistoryConcurrency, func(ctx context.Context, idx int) error {
+		h.loadGroup(ctx, groups[idx])
+		return nil
+	})
+}
+
+// loadGroup loads the persisted history of the rule group, which precedes the evaluations recorded in memory.
+// The lock of the group history isn't held while reading the ruler storage, so that the evaluations are recorded
+// in the meanwhile.
+func (h *EvaluationHistory) loadGroup(ctx context.Context, gh *groupEvaluationHistory) {
+	gh.mtx.Lock()
+	if gh.loaded || gh.loading {
+		gh.mtx.Unlock()
+		return
+	}
+	gh.loading = true
+	gh.mtx.Unlock()
+
+	persisted, err := h.read(ctx, gh.userID, gh.namespace, gh.name)
+
+	gh.mtx.Lock()
+	defer gh.mtx.Unlock()
+
+	gh.loading = false
+	if err != nil {
+		// The history is loaded again before being persisted, and not persisted in the meanwhile.
+		level.Warn(h.logger).Log("msg", "unable to load the rule group evaluation history", "user", gh.userID, "namespace", gh.namespace, "group", gh.name, "err", err)
+		return
+	}
+
+	gh.evaluations = append(persisted, gh.evaluations...)
+	gh.loaded = true
+	gh.trim(time.Now().Add(-h.cfg.Retention), h.cfg.MaxEntriesPerRuleGroup)
+}
+
+// read reads the persisted history of the rule group. A rule group without persisted history has no evaluations.
+func (h *EvaluationHistory) read(ctx context.Context, userID, namespace, name string) ([]RuleEvaluation, error) {
+	userBucket := bucket.NewUserBucketClient(userID, h.bucket, h.cfgProvider)
+
+	reader, err := userBucket.Get(ctx, evaluationHistoryObjectKey(namespace, name))
+	if userBucket.IsObjNotFoundErr(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = reader.Close() }()
+
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	var obj evaluationHistoryObject
+	if err := json.Unmarshal(content, &obj); err != nil {
+		return nil, errors.Wrap(err, "unable to decode the rule group evaluation history")
+	}
+	return obj.Evaluations, nil
+}
+
+// trim removes the evaluations older than minTime, and the oldest evaluations exceeding maxEntries.
+// Must be called with the group history lock held.
+func (gh *groupEvaluationHistory) trim(minTime time.Time, maxEntries int) {
+	first := sort.Search(len(gh.evaluations), func(i int) bool {
+		return !gh.evaluations[i].Timestamp.Before(minTime)
+	})
+	first = max(first, len(gh.evaluations)-maxEntries)
+	if first > 0 {
+		gh.evaluations = append(gh.evaluations[:0], gh.evaluations[first:]...)
+	}
+}
+
+// alertTransitions returns the changes of state of the alerts between two evaluations.
+func alertTransitions(prev, curr map[uint64]trackedAlert) []AlertTransition {
+	var transitions []AlertTransition
+	for hash, a := range curr {
+		from := promRules.StateInactive
+		if p, ok := prev[hash]; ok {
+			from = p.state
+		}
+		if from != a.state {
+			transitions = append(transitions, AlertTransition{Labels: a.labels, From: from.String(), To: a.state.String()})
+		}
+	}
+	for hash, p := range prev {
+		if _, ok := curr[hash]; !ok {
+			transitions = append(transitions, AlertTransition{Labels: p.labels, From: p.state.String(), To: promRules.StateInactive.String()})
+		}
+	}
+
+	sort.Slice(transitions, func(i, j int) bool {
+		return labels.Compare(transitions[i].Labels, transitions[j].Labels) < 0
+	})
+	return transitions
+}
+
+// retainGroups creates the history of the rule groups of the tenant evaluated by this ruler, and marks the history of
+// the rule groups which aren't evaluated by this ruler anymore as inactive, so that they're removed from memory once
+// persisted. The rule group files are in the rulesDir directory.
+func (h *EvaluationHistory) retainGroups(userID, rulesDir string, groups []*promRules.Group) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	retained := make(map[string]struct{}, len(groups))
+	for _, g := range groups {
+		namespace, err := decodeNamespace(rulesDir, g.File())
+		if err != nil {
+			continue
+		}
+		retained[evaluationHistoryGroupKey(namespace, g.Name())] = struct{}{}
+
+		// The history of the rule groups is loaded before their first evaluation, if possible.
+		h.groupLocked(userID, namespace, g.Name())
+	}
+
+	for key, gh := range h.groups[userID] {
+		if _, ok := retained[key]; !ok {
+			gh.mtx.Lock()
+			gh.active = false
+			gh.mtx.Unlock()
+		}
+	}
+}
+
+// removeUser marks the history of all the rule groups of the tenant as inactive.
+func (h *EvaluationHistory) removeUser(userID string) {
+	h.retainGroups(userID, "", nil)
+}
+
+// persist uploads the history of the rule groups which changed since the last time it has been persisted,
+// and removes the inactive rule groups from memory. The history of the rule groups which couldn't be loaded
+// is loaded again first.
+func (h *EvaluationHistory) persist(ctx context.Context) {
+	h.loadGroups(ctx)
+
+	h.mtx.Lock()
+	var groups []*groupEvaluationHistory
+	for _, userGroups := range h.groups {
+		for _, gh := range userGroups {
+			groups = append(groups, gh)
+		}
+	}
+	h.mtx.Unlock()
+
+	for _, gh := range groups {
+		gh.mtx.Lock()
+		if !gh.dirty || !gh.loaded {
+			gh.mtx.Unlock()
+			continue
+		}
+		content, err := json.Marshal(evaluationHistoryObject{Evaluations: gh.evaluations})
+		gh.dirty = false
+		gh.mtx.Unlock()
+
+		if err == nil {
+			userBucket := bucket.NewUserBucketClient(gh.userID, h.bucket, h.cfgProvider)
+			err = userBucket.Upload(ctx, evaluationHistoryObjectKey(gh.namespace, gh.name), bytes.NewReader(content))
+		}
+		if err != nil {
+			h.persistFailures.Inc()
+			level.Warn(h.logger).Log("msg", "unable to persist the rule group evaluation history", "user", gh.userID, "namespace", gh.namespace, "group", gh.name, "err", err)
+
+			gh.mtx.Lock()
+			gh.dirty = true
+			gh.mtx.Unlock()
+		}
+	}
+
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	for userID, userGroups := range h.groups {
+		for key, gh := range userGroups {
+			gh.mtx.Lock()
+			// The history of a rule group which couldn't be loaded is lost, because it can't be persisted.
+			if !gh.active && (!gh.dirty || !gh.loaded) {
+				delete(userGroups, key)
+			}
+			gh.mtx.Unlock()
+		}
+		if len(userGroups) == 0 {
+			delete(h.groups, userID)
+		}
+	}
+}
+
+// evaluations returns the evaluations of the rule groups of the tenant between start and end, matching the filters.
+// The history of the rule groups evaluated by this ruler is read from memory, and the history of the other rule groups,
+// or of the rule groups whose persisted history hasn't been loaded yet, is read from the ruler storage. The evaluations
+// older than the retention are ignored, even if they haven't been removed from the ruler storage yet.
+func (h *EvaluationHistory) evaluations(ctx context.Context, userID string, start, end time.Time, namespaces, groups, rules StringFilterSet) ([]RuleEvaluation, error) {
+	if minTime := time.Now().Add(-h.cfg.Retention); start.Before(minTime) {
+		start = minTime
+	}
+
+	var (
+		resultMtx sync.Mutex
+		result    []RuleEvaluation
+	)
+	add := func(evaluations []RuleEvaluation) {
+		resultMtx.Lock()
+		defer resultMtx.Unlock()
+
+		for _, e := range evaluations {
+			if !e.Timestamp.Before(start) && !e.Timestamp.After(end) && !rules.IsFiltered(e.Rule) {
+				result = append(result, e)
+			}
+		}
+	}
+
+	// Rule groups whose history is entirely read from memory.
+	inMemory := map[string]struct{}{}
+
+	h.mtx.Lock()
+	userGroups := make([]*groupEvaluationHistory, 0, len(h.groups[userID]))
+	for _, gh := range h.groups[userID] {
+		userGroups = append(userGroups, gh)
+	}
+	h.mtx.Unlock()
+
+	for _, gh := range userGroups {
+		gh.mtx.Lock()
+		if !namespaces.IsFiltered(gh.namespace) && !groups.IsFiltered(gh.name) {
+			add(gh.evaluations)
+			if gh.loaded {
+				inMemory[evaluationHistoryGroupKey(gh.namespace, gh.name)] = struct{}{}
+			}
+		}
+		gh.mtx.Unlock()
+	}
+
+	var keys []string
+	userBucket := bucket.NewUserBucketClient(userID, h.bucket, h.cfgProvider)
+	err := userBucket.Iter(ctx, "", func(key string) error {
+		namespace, name, err := parseEvaluationHistoryObjectKey(key)
+		if err != nil {
+			level.Warn(h.logger).Log("msg", "invalid rule group evaluation history object key", "user", userID, "key", key, "err", err)
+			return nil
+		}
+		if namespaces.IsFiltered(namespace) || groups.IsFiltered(name) {
+			return nil
+		}
+		if _, ok := inMemory[evaluationHistoryGroupKey(namespace, name)]; ok {
+			return nil
+		}
+		keys = append(keys, key)
+		return nil
+	}, objstore.WithRecursiveIter)
+	if err != nil {
+		return nil, err
+	}
+
+	err = concurrency.ForEachJob(ctx, len(keys), evaluationHistoryConcurrency, func(ctx context.Context, idx int) error {
+		// The key has already been parsed successfully.
+		namespace, name, _ := parseEvaluationHistoryObjectKey(keys[idx])
+		evaluations, err := h.read(ctx, userID, namespace, name)
+		if err != nil {
+			return errors.Wrapf(err, "unable to read the evaluation history of the rule group %s in namespace %s", name, namespace)
+		}
+		add(evaluations)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Timestamp.Before(result[j].Timestamp)
+	})
+	return result, nil
+}
+
+// cleanup deletes the persisted history of the rule groups which hasn't been updated for longer than the retention.
+// The persisted history of a rule group is updated whenever it's evaluated, so only the history of the rule groups
+// not evaluated by any ruler anymore, because they or their tenant have been removed, is deleted.
+func (h *EvaluationHistory) cleanup(ctx context.Context, now time.Time) {
+	var keys []string
+	err := h.bucket.Iter(ctx, "", func(key string) error {
+		keys = append(keys, key)
+		return nil
+	}, objstore.WithRecursiveIter)
+	if err != nil {
+		level.Warn(h.logger).Log("msg", "unable to list the rule group evaluation histories", "err", err)
+		return
+	}
+
+	minTime := now.Add(-h.cfg.Retention)
+	_ = concurrency.ForEachJob(ctx, len(keys), evaluationHistoryConcurrency, func(ctx context.Context, idx int) error {
+		attrs, err := h.bucket.Attributes(ctx, keys[idx])
+		if h.bucket.IsObjNotFoundErr(err) {
+			return nil
+		}
+		if err == nil && attrs.LastModified.Before(minTime) {
+			err = h.bucket.Delete(ctx, keys[idx])
+		}
+		if err != nil && !h.bucket.IsObjNotFoundErr(err) {
+			level.Warn(h.logger).Log("msg", "unable to clean up the rule group evaluation history", "key", keys[idx], "err", err)
+		}
+		return nil
+	})
+}
+
+// EvaluationHistoryHandler returns the evaluations of the rules of the tenant kept in the history.
+func (h *EvaluationHistory) EvaluationHistoryHandler(w http.ResponseWriter, req *http.Request) {
+	logger, ctx := spanlogger.NewWithLogger(req.Context(), h.logger, "EvaluationHistory.EvaluationHistoryHandler")
+	defer logger.Finish()
+
+	userID, err := tenant.TenantID(ctx)
+	if err != nil || userID == "" {
+		level.Error(logger).Log("msg", "error extracting org id from context", "err", err)
+		respondServerError(logger, w, "no valid org id found")
+		return
+	}
+
+	start, err := util.ParseTimeParam(req, "start", math.MinInt64)
+	if err != nil {
+		respondInvalidRequest(logger, w, err.Error())
+		return
+	}
+	end, err := util.ParseTimeParam(req, "end", math.MaxInt64)
+	if err != nil {
+		respondInvalidRequest(logger, w, err.Error())
+		return
+	}
+	if end < start {
+		respondInvalidRequest(logger, w, "end timestamp must not be before start time")
+		return
+	}
+
+	evaluations, err := h.evaluations(ctx, userID, util.TimeFromMillis(start), util.TimeFromMillis(end),
+		makeStringFilterSet(req.URL.Query()["file"]),
+		makeStringFilterSet(req.URL.Query()["rule_group"]),
+		makeStringFilterSet(req.URL.Query()["rule_name"]))
+	if err != nil {
+		respondServerError(logger, w, err.Error())
+		return
+	}
+
+	util.WriteJSONResponse(w, &response{
+		Status: "success",
+		Data:   &EvaluationHistoryResult{Evaluations: evaluations},
+	})
+}
+
+// evaluationSamples counts the samples returned by the queries of the rules during a rule group evaluation.
+type evaluationSamples struct {
+	mtx    sync.Mutex
+	counts map[string]int // Key = query.
+}
+
+func (s *evaluationSamples) add(query string, count int) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.counts[query] = count
+}
+
+func (s *evaluationSamples) get(query string) int {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.counts[query]
+}
+
+// EvaluationHistoryQueryFunc counts the samples returned by the rule queries, for the rule evaluation history.
+func EvaluationHistoryQueryFunc(qf promRules.QueryFunc) promRules.QueryFunc {
+	return func(ctx context.Context, qs string, t time.Time) (promql.Vector, error) {
+		result, err := qf(ctx, qs, t)
+		if samples, ok := ctx.Value(evaluationSamplesCtxKey).(*evaluationSamples); ok && err == nil {
+			samples.add(qs, len(result))
+		}
+		return result, err
+	}
+}
+
+// decodeNamespace returns the namespace of a rule group file mapped to disk.
+func decodeNamespace(rulesDir, file string) (string, error) {
+	return url.PathUnescape(strings.TrimPrefix(file, rulesDir))
+}
+
+func evaluationHistoryGroupKey(namespace, name string) string {
+	return namespace + "\xff" + name
+}
+
+func evaluationHistoryObjectKey(namespace, name string) string {
+	return base64.URLEncoding.EncodeToString([]byte(namespace)) + objstore.DirDelim + base64.URLEncoding.EncodeToString([]byte(name))
+}
+
+func parseEvaluationHistoryObjectKey(key string) (namespace, name string, _ error) {
+	parts := strings.Split(key, objstore.DirDelim)
+	if len(parts) != 2 {
+		return "", "", errors.New("invalid rule group evaluation history object key")
+	}
+
+	decodedNamespace, err := base64.URLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", "", err
+	}
+	decodedName, err := base64.URLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", "", err
+	}
+	return string(decodedNamespace), string(decodedName), nil
+}
@@ -0,0 +1,342 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package ruler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+	promRules "github.com/prometheus/prometheus/rules"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+)
+
+func TestEvaluationHistory(t *testing.T) {
+	const (
+		userID    = "user-1"
+		rulesDir  = "/rules/user-1/"
+		namespace = "namespace/1"
+	)
+
+	cfg := EvaluationHistoryConfig{Enabled: true, MaxEntriesPerRuleGroup: 100, Retention: time.Hour, PersistInterval: time.Minute}
+	bkt := objstore.NewInMemBucket()
+	h := newEvaluationHistory(cfg, bkt, nil, log.NewNopLogger(), prometheus.NewPedanticRegistry())
+
+	// The alert is firing at the first evaluation, and resolved at the second one.
+	alertFiring := true
+	queryFunc := func(_ context.Context, qs string, ts time.Time) (promql.Vector, error) {
+		switch qs {
+		case "sum by (job) (up)":
+			return promql.Vector{
+				{Metric: labels.FromStrings("job", "api"), T: ts.UnixMilli(), F: 1},
+				{Metric: labels.FromStrings("job", "db"), T: ts.UnixMilli(), F: 1},
+			}, nil
+		case `up{job="api"} == 0`:
+			if !alertFiring {
+				return nil, nil
+			}
+			return promql.Vector{{Metric: labels.FromStrings("job", "api"), T: ts.UnixMilli(), F: 0}}, nil
+		}
+		return nil, nil
+	}
+
+	g := newEvaluationHistoryTestGroup(t, rulesDir+url.PathEscape(namespace), "group-1", queryFunc,
+		promRules.NewRecordingRule("job:up:sum", mustParseExpr(t, "sum by (job) (up)"), labels.EmptyLabels()),
+		promRules.NewAlertingRule("InstanceDown", mustParseExpr(t, `up{job="api"} == 0`), 0, 0, labels.EmptyLabels(), labels.EmptyLabels(), labels.EmptyLabels(), "", true, log.NewNopLogger()),
+	)
+
+	evalIterationFunc := h.evalIterationFunc(userID, rulesDir)
+	t0 := time.Now().Truncate(time.Minute)
+	evalIterationFunc(context.Background(), g, t0)
+	alertFiring = false
+	evalIterationFunc(context.Background(), g, t0.Add(time.Minute))
+
+	expectedLabels := labels.FromStrings("alertname", "InstanceDown", "job", "api")
+	assertEvaluations := func(t *testing.T, evaluations []RuleEvaluation) {
+		require.Len(t, evaluations, 4)
+
+		for i, e := range evaluations {
+			assert.Equal(t, namespace, e.Namespace)
+			assert.Equal(t, "group-1", e.Group)
+			assert.Equal(t, string(promRules.HealthGood), e.Health)
+			assert.Empty(t, e.Error)
+			assert.True(t, e.Timestamp.Equal(t0.Add(time.Duration(i/2)*time.Minute)))
+		}
+
+		assert.Equal(t, "job:up:sum", evaluations[0].Rule)
+		assert.Equal(t, "recording", evaluations[0].Type)
+		assert.Equal(t, 2, evaluations[0].Samples)
+		assert.Empty(t, evaluations[0].AlertTransitions)
+
+		assert.Equal(t, "InstanceDown", evaluations[1].Rule)
+		assert.Equal(t, "alerting", evaluations[1].Type)
+		assert.Equal(t, 1, evaluations[1].Samples)
+		require.Len(t, evaluations[1].AlertTransitions, 1)
+		assert.Equal(t, expectedLabels, evaluations[1].AlertTransitions[0].Labels)
+		assert.Equal(t, "inactive", evaluations[1].AlertTransitions[0].From)
+		assert.Equal(t, "firing", evaluations[1].AlertTransitions[0].To)
+
+		assert.Equal(t, "InstanceDown", evaluations[3].Rule)
+		assert.Equal(t, 0, evaluations[3].Samples)
+		require.Len(t, evaluations[3].AlertTransitions, 1)
+		assert.Equal(t, expectedLabels, evaluations[3].AlertTransitions[0].Labels)
+		assert.Equal(t, "firing", evaluations[3].AlertTransitions[0].From)
+		assert.Equal(t, "inactive", evaluations[3].AlertTransitions[0].To)
+	}
+
+	t.Run("history is read from memory", func(t *testing.T) {
+		evaluations, err := h.evaluations(context.Background(), userID, t0, t0.Add(time.Hour), nil, nil, nil)
+		require.NoError(t, err)
+		assertEvaluations(t, evaluations)
+	})
+
+	t.Run("history is persisted to the ruler storage", func(t *testing.T) {
+		h.persist(context.Background())
+		assert.Len(t, bkt.Objects(), 1)
+		assert.Equal(t, 0.0, testutil.ToFloat64(h.persistFailures))
+
+		// The history of the rule group is removed from memory once it isn't evaluated anymore.
+		h.retainGroups(userID, rulesDir, nil)
+		h.persist(context.Background())
+		assert.Empty(t, h.groups)
+
+		evaluations, err := h.evaluations(context.Background(), userID, t0, t0.Add(time.Hour), nil, nil, nil)
+		require.NoError(t, err)
+		assertEvaluations(t, evaluations)
+	})
+
+	t.Run("persisted history is continued by another ruler", func(t *testing.T) {
+		other := newEvaluationHistory(cfg, bkt, nil, log.NewNopLogger(), prometheus.NewPedanticRegistry())
+		other.evalIterationFunc(userID, rulesDir)(context.Background(), g, t0.Add(2*time.Minute))
+
+		evaluations, err := other.evaluations(context.Background(), userID, t0, t0.Add(time.Hour), nil, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, evaluations, 6)
+		assertEvaluations(t, evaluations[:4])
+		assert.True(t, evaluations[5].Timestamp.Equal(t0.Add(2*time.Minute)))
+	})
+
+	t.Run("history is filtered", func(t *testing.T) {
+		evaluations, err := h.evaluations(context.Background(), userID, t0.Add(time.Minute), t0.Add(time.Hour), nil, nil, makeStringFilterSet([]string{"InstanceDown"}))
+		require.NoError(t, err)
+		require.Len(t, evaluations, 1)
+		assert.Equal(t, "InstanceDown", evaluations[0].Rule)
+
+		evaluations, err = h.evaluations(context.Background(), userID, t0, t0.Add(time.Hour), makeStringFilterSet([]string{"other"}), nil, nil)
+		require.NoError(t, err)
+		assert.Empty(t, evaluations)
+
+		evaluations, err = h.evaluations(context.Background(), "user-2", t0, t0.Add(time.Hour), nil, nil, nil)
+		require.NoError(t, err)
+		assert.Empty(t, evaluations)
+	})
+}
+
+func TestEvaluationHistory_Trim(t *testing.T) {
+	const rulesDir = "/rules/user-1/"
+
+	queryFunc := func(context.Context, string, time.Time) (promql.Vector, error) { return nil, nil }
+	g := newEvaluationHistoryTestGroup(t, rulesDir+"namespace", "group-1", queryFunc,
+		promRules.NewRecordingRule("job:up:sum", mustParseExpr(t, "sum by (job) (up)"), labels.EmptyLabels()),
+	)
+
+	t0 := time.Now().Truncate(time.Minute)
+	for name, tc := range map[string]struct {
+		maxEntries int
+		retention  time.Duration
+		expected   []time.Time
+	}{
+		"max entries": {
+			maxEntries: 3,
+			retention:  time.Hour,
+			expected:   []time.Time{t0.Add(2 * time.Minute), t0.Add(3 * time.Minute), t0.Add(4 * time.Minute)},
+		},
+		"retention": {
+			maxEntries: 100,
+			retention:  time.Minute,
+			expected:   []time.Time{t0.Add(3 * time.Minute), t0.Add(4 * time.Minute)},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg := EvaluationHistoryConfig{Enabled: true, MaxEntriesPerRuleGroup: tc.maxEntries, Retention: tc.retention, PersistInterval: time.Minute}
+			h := newEvaluationHistory(cfg, objstore.NewInMemBucket(), nil, log.NewNopLogger(), nil)
+
+			for i := 0; i < 5; i++ {
+				h.evalIterationFunc("user-1", rulesDir)(context.Background(), g, t0.Add(time.Duration(i)*time.Minute))
+			}
+
+			evaluations, err := h.evaluations(context.Background(), "user-1", time.UnixMilli(math.MinInt64), time.UnixMilli(math.MaxInt64), nil, nil, nil)
+			require.NoError(t, err)
+
+			timestamps := make([]time.Time, 0, len(evaluations))
+			for _, e := range evaluations {
+				timestamps = append(timestamps, e.Timestamp)
+			}
+			assert.Equal(t, tc.expected, timestamps)
+		})
+	}
+}
+
+func TestEvaluationHistory_LoadAndRetention(t *testing.T) {
+	const (
+		userID   = "user-1"
+		rulesDir = "/rules/user-1/"
+	)
+
+	ctx := context.Background()
+	cfg := EvaluationHistoryConfig{Enabled: true, MaxEntriesPerRuleGroup: 100, Retention: time.Hour, PersistInterval: time.Minute}
+	bkt := objstore.NewInMemBucket()
+	h := newEvaluationHistory(cfg, bkt, nil, log.NewNopLogger(), nil)
+
+	now := time.Now().Truncate(time.Minute)
+	persisted, err := json.Marshal(evaluationHistoryObject{Evaluations: []RuleEvaluation{
+		{Namespace: "namespace", Group: "group-1", Rule: "job:up:sum", Timestamp: now.Add(-2 * time.Hour)},
+		{Namespace: "namespace", Group: "group-1", Rule: "job:up:sum", Timestamp: now.Add(-time.Minute)},
+	}})
+	require.NoError(t, err)
+	require.NoError(t, bkt.Upload(ctx, path.Join(EvaluationHistoryPrefix, userID, evaluationHistoryObjectKey("namespace", "group-1")), bytes.NewReader(persisted)))
+
+	// The evaluations older than the retention aren't returned, even if they're still persisted.
+	evaluations, err := h.evaluations(ctx, userID, time.UnixMilli(math.MinInt64), time.UnixMilli(math.MaxInt64), nil, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, evaluations, 1)
+	assert.True(t, evaluations[0].Timestamp.Equal(now.Add(-time.Minute)))
+
+	// The history is loaded in the background once the ruler starts evaluating the rule group.
+	queryFunc := func(context.Context, string, time.Time) (promql.Vector, error) { return nil, nil }
+	g := newEvaluationHistoryTestGroup(t, rulesDir+"namespace", "group-1", queryFunc,
+		promRules.NewRecordingRule("job:up:sum", mustParseExpr(t, "sum by (job) (up)"), labels.EmptyLabels()),
+	)
+	h.retainGroups(userID, rulesDir, []*promRules.Group{g})
+	require.Len(t, h.load, 1)
+	<-h.load
+	h.loadGroups(ctx)
+
+	gh := h.group(userID, "namespace", "group-1")
+	assert.True(t, gh.loaded)
+	require.Len(t, gh.evaluations, 1)
+	assert.True(t, gh.evaluations[0].Timestamp.Equal(now.Add(-time.Minute)))
+}
+
+func TestEvaluationHistory_Cleanup(t *testing.T) {
+	ctx := context.Background()
+	cfg := EvaluationHistoryConfig{Enabled: true, MaxEntriesPerRuleGroup: 100, Retention: time.Hour, PersistInterval: time.Minute}
+	bkt := objstore.NewInMemBucket()
+	h := newEvaluationHistory(cfg, bkt, nil, log.NewNopLogger(), nil)
+
+	for _, userID := range []string{"user-1", "user-2"} {
+		require.NoError(t, bkt.Upload(ctx, path.Join(EvaluationHistoryPrefix, userID, evaluationHistoryObjectKey("namespace", "group-1")), strings.NewReader(`{"evaluations":[]}`)))
+	}
+
+	// The history updated within the retention is kept.
+	h.cleanup(ctx, time.Now().Add(30*time.Minute))
+	assert.Len(t, bkt.Objects(), 2)
+
+	// The history of the rule groups which haven't been evaluated for longer than the retention is deleted.
+	h.cleanup(ctx, time.Now().Add(2*time.Hour))
+	assert.Empty(t, bkt.Objects())
+}
+
+func TestEvaluationHistory_EvaluationHistoryHandler(t *testing.T) {
+	const rulesDir = "/rules/user-1/"
+
+	cfg := EvaluationHistoryConfig{Enabled: true, MaxEntriesPerRuleGroup: 100, Retention: time.Hour, PersistInterval: time.Minute}
+	h := newEvaluationHistory(cfg, objstore.NewInMemBucket(), nil, log.NewNopLogger(), nil)
+
+	queryFunc := func(context.Context, string, time.Time) (promql.Vector, error) { return nil, nil }
+	g := newEvaluationHistoryTestGroup(t, rulesDir+"namespace", "group-1", queryFunc,
+		promRules.NewRecordingRule("job:up:sum", mustParseExpr(t, "sum by (job) (up)"), labels.EmptyLabels()),
+	)
+
+	t0 := time.Now().Truncate(time.Minute).Add(-10 * time.Minute)
+	for i := 0; i < 3; i++ {
+		h.evalIterationFunc("user-1", rulesDir)(context.Background(), g, t0.Add(time.Duration(i)*time.Minute))
+	}
+
+	for name, tc := range map[string]struct {
+		url                 string
+		expectedStatus      int
+		expectedEvaluations int
+	}{
+		"all evaluations": {
+			url:                 "/prometheus/api/v1/rules/history",
+			expectedStatus:      http.StatusOK,
+			expectedEvaluations: 3,
+		},
+		"time range": {
+			url:                 fmt.Sprintf("/prometheus/api/v1/rules/history?start=%d&end=%d", t0.Unix()+60, t0.Unix()+60),
+			expectedStatus:      http.StatusOK,
+			expectedEvaluations: 1,
+		},
+		"rule group filter": {
+			url:                 "/prometheus/api/v1/rules/history?file=namespace&rule_group=other",
+			expectedStatus:      http.StatusOK,
+			expectedEvaluations: 0,
+		},
+		"invalid time range": {
+			url:            fmt.Sprintf("/prometheus/api/v1/rules/history?start=%d&end=%d", t0.Unix()+60, t0.Unix()),
+			expectedStatus: http.StatusBadRequest,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.EvaluationHistoryHandler(w, requestFor(t, http.MethodGet, tc.url, nil, "user-1"))
+			require.Equal(t, tc.expectedStatus, w.Code, w.Body.String())
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var resp struct {
+				Status string                  `json:"status"`
+				Data   EvaluationHistoryResult `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, "success", resp.Status)
+			assert.Len(t, resp.Data.Evaluations, tc.expectedEvaluations)
+		})
+	}
+}
+
+func newEvaluationHistoryTestGroup(t *testing.T, file, name string, queryFunc promRules.QueryFunc, rules ...promRules.Rule) *promRules.Group {
+	pusher := newPusherMock()
+	pusher.MockPush(&mimirpb.WriteResponse{}, nil)
+
+	return promRules.NewGroup(promRules.GroupOptions{
+		Name:     name,
+		File:     file,
+		Interval: time.Minute,
+		Rules:    rules,
+		Opts: &promRules.ManagerOptions{
+			Appendable: NewPusherAppendable(pusher, "user-1", prometheus.NewCounter(prometheus.CounterOpts{}), prometheus.NewCounter(prometheus.CounterOpts{})),
+			QueryFunc:  EvaluationHistoryQueryFunc(queryFunc),
+			Context:    context.Background(),
+			Logger:     log.NewNopLogger(),
+			NotifyFunc: func(context.Context, string, ...*promRules.Alert) {},
+		},
+	})
+}
+
+func mustParseExpr(t *testing.T, expr string) parser.Expr {
+	parsed, err := parser.ParseExpr(expr)
+	require.NoError(t, err)
+	return parsed
+}
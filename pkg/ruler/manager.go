@@ -9,12 +9,14 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"path/filepath"
 	"sync"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/grafana/dskit/cache"
 	"github.com/grafana/dskit/concurrency"
+	"github.com/grafana/dskit/services"
 	"github.com/grafana/dskit/user"
 	ot "github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
@@ -39,6 +41,9 @@ type DefaultMultiTenantManager struct {
 
 	mapper *mapper
 
+	// History of the rule evaluations, nil if disabled.
+	evaluationHistory *EvaluationHistory
+
 	// Struct for holding per-user Prometheus rules Managers.
 	userManagerMtx sync.RWMutex
 	userManagers   map[string]RulesManager
@@ -60,7 +65,7 @@ type DefaultMultiTenantManager struct {
 	rulerIsRunning atomic.Bool
 }
 
-func NewDefaultMultiTenantManager(cfg Config, managerFactory ManagerFactory, reg prometheus.Registerer, logger log.Logger, dnsResolver cache.AddressProvider) (*DefaultMultiTenantManager, error) {
+func NewDefaultMultiTenantManager(cfg Config, managerFactory ManagerFactory, evaluationHistory *EvaluationHistory, reg prometheus.Registerer, logger log.Logger, dnsResolver cache.AddressProvider) (*DefaultMultiTenantManager, error) {
 	refreshMetrics := discovery.NewRefreshMetrics(reg)
 	ncfg, err := buildNotifierConfig(&cfg, dnsResolver, refreshMetrics)
 	if err != nil {
@@ -78,6 +83,7 @@ func NewDefaultMultiTenantManager(cfg Config, managerFactory ManagerFactory, reg
 		managerFactory:     managerFactory,
 		notifiers:          map[string]*rulerNotifier{},
 		mapper:             newMapper(cfg.RulePath, logger),
+		evaluationHistory:  evaluationHistory,
 		userManagers:       map[string]RulesManager{},
 		userManagerMetrics: userManagerMetrics,
 		managersTotal: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
@@ -155,6 +161,12 @@ func (r *DefaultMultiTenantManager) Start() {
 		return
 	}
 
+	if r.evaluationHistory != nil {
+		if err := services.StartAndAwaitRunning(context.Background(), r.evaluationHistory); err != nil {
+			level.Error(r.logger).Log("msg", "unable to start the rule evaluation history", "err", err)
+		}
+	}
+
 	for _, mngr := range r.userManagers {
 		go mngr.Run()
 	}
@@ -216,13 +228,23 @@ func (r *DefaultMultiTenantManager) syncRulesToManager(user string, groups rules
 	level.Debug(r.logger).Log("msg", "updating rules", "user", user)
 	r.configUpdatesTotal.WithLabelValues(user).Inc()
 
-	err = manager.Update(r.cfg.EvaluationInterval, files, labels.EmptyLabels(), r.cfg.ExternalURL.String(), nil)
+	var evalIterationFunc promRules.GroupEvalIterationFunc
+	rulesDir := filepath.Join(r.cfg.RulePath, user) + "/"
+	if r.evaluationHistory != nil {
+		evalIterationFunc = r.evaluationHistory.evalIterationFunc(user, rulesDir)
+	}
+
+	err = manager.Update(r.cfg.EvaluationInterval, files, labels.EmptyLabels(), r.cfg.ExternalURL.String(), evalIterationFunc)
 	if err != nil {
 		r.lastReloadSuccessful.WithLabelValues(user).Set(0)
 		level.Error(r.logger).Log("msg", "unable to update rule manager", "user", user, "err", err)
 		return
 	}
 
+	if r.evaluationHistory != nil {
+		r.evaluationHistory.retainGroups(user, rulesDir, manager.RuleGroups())
+	}
+
 	r.lastReloadSuccessful.WithLabelValues(user).Set(1)
 	r.lastReloadSuccessfulTimestamp.WithLabelValues(user).SetToCurrentTime()
 }
@@ -350,6 +372,9 @@ func (r *DefaultMultiTenantManager) removeUsersIf(shouldRemove func(userID strin
 		r.lastReloadSuccessfulTimestamp.DeleteLabelValues(userID)
 		r.configUpdatesTotal.DeleteLabelValues(userID)
 		r.userManagerMetrics.RemoveUserRegistry(userID)
+		if r.evaluationHistory != nil {
+			r.evaluationHistory.removeUser(userID)
+		}
 		level.Info(r.logger).Log("msg", "deleted rule manager and local rule files", "user", userID)
 	}
 
@@ -391,6 +416,14 @@ func (r *DefaultMultiTenantManager) Stop() {
 	r.userManagerMtx.Unlock()
 	level.Info(r.logger).Log("msg", "all user managers stopped")
 
+	// Stop the rule evaluation history once the user managers are stopped, so that it persists
+	// the last evaluations.
+	if r.evaluationHistory != nil {
+		if err := services.StopAndAwaitTerminated(context.Background(), r.evaluationHistory); err != nil {
+			level.Warn(r.logger).Log("msg", "unable to stop the rule evaluation history", "err", err)
+		}
+	}
+
 	// cleanup user rules directories
 	r.mapper.cleanup()
 }
@@ -127,10 +127,15 @@ type Config struct {
 
 	EnableQueryStats bool `yaml:"query_stats_enabled" category:"advanced"`
 
+	// Max number of rule group backfills handled concurrently.
+	MaxConcurrentBackfills int `yaml:"max_concurrent_backfills" category:"experimental"`
+
 	QueryFrontend QueryFrontendConfig `yaml:"query_frontend"`
 
 	TenantFederation TenantFederationConfig `yaml:"tenant_federation"`
 
+	EvaluationHistory EvaluationHistoryConfig `yaml:"evaluation_history"`
+
 	// Allow to override timers for testing purposes.
 	RingCheckPeriod             time.Duration `yaml:"-"`
 	rulerSyncQueuePollFrequency time.Duration `yaml:"-"`
@@ -150,6 +155,10 @@ func (cfg *Config) Validate(limits validation.Limits) error {
 		return errors.Wrap(err, "invalid ruler query-frontend config")
 	}
 
+	if err := cfg.EvaluationHistory.Validate(); err != nil {
+		return errors.Wrap(err, "invalid ruler evaluation history config")
+	}
+
 	return nil
 }
 
@@ -159,6 +168,7 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet, logger log.Logger) {
 	cfg.Ring.RegisterFlags(f, logger)
 	cfg.Notifier.RegisterFlags(f)
 	cfg.TenantFederation.RegisterFlags(f)
+	cfg.EvaluationHistory.RegisterFlags(f)
 	cfg.QueryFrontend.RegisterFlags(f)
 
 	cfg.ExternalURL.URL, _ = url.Parse("") // Must be non-nil
@@ -185,6 +195,7 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet, logger log.Logger) {
 	f.Var(&cfg.DisabledTenants, "ruler.disabled-tenants", "Comma separated list of tenants whose rules this ruler cannot evaluate. If specified, a ruler that would normally pick the specified tenant(s) for processing will ignore them instead. Subject to sharding.")
 
 	f.BoolVar(&cfg.EnableQueryStats, "ruler.query-stats-enabled", false, "Report the wall time for ruler queries to complete as a per-tenant metric and as an info level log message.")
+	f.IntVar(&cfg.MaxConcurrentBackfills, "ruler.max-concurrent-backfills", 1, "Max number of rule group backfill requests handled concurrently by each ruler. The requests exceeding the limit are rejected with the 429 status code. 0 = no limit.")
 
 	cfg.RingCheckPeriod = 5 * time.Second
 }
@@ -41,6 +41,10 @@ type SchedulerRequest struct {
 	StatsEnabled              bool
 	AdditionalQueueDimensions []string
 
+	// EstimatedCost is the cost of executing the request as estimated by the query-frontend.
+	// A value of 0 means the cost is unknown.
+	EstimatedCost float64
+
 	EnqueueTime time.Time
 
 	Ctx        context.Context
@@ -51,6 +55,14 @@ type SchedulerRequest struct {
 	ParentSpanContext opentracing.SpanContext
 }
 
+// Cost returns the estimated cost of the request, or 1 if the cost is unknown.
+func (sr *SchedulerRequest) Cost() float64 {
+	if sr.EstimatedCost > 0 {
+		return sr.EstimatedCost
+	}
+	return 1
+}
+
 // UserIndex is opaque type that allows to resume iteration over users between successive calls
 // of RequestQueue.GetNextRequestForQuerier method.
 type UserIndex struct {
@@ -82,6 +94,7 @@ type RequestQueue struct {
 
 	maxOutstandingPerTenant          int
 	additionalQueueDimensionsEnabled bool
+	costBasedFairnessEnabled         bool
 	forgetDelay                      time.Duration
 
 	connectedQuerierWorkers *atomic.Int32
@@ -124,6 +137,7 @@ func NewRequestQueue(
 	log log.Logger,
 	maxOutstandingPerTenant int,
 	additionalQueueDimensionsEnabled bool,
+	costBasedFairnessEnabled bool,
 	forgetDelay time.Duration,
 	queueLength *prometheus.GaugeVec,
 	discardedRequests *prometheus.CounterVec,
@@ -133,6 +147,7 @@ func NewRequestQueue(
 		log:                              log,
 		maxOutstandingPerTenant:          maxOutstandingPerTenant,
 		additionalQueueDimensionsEnabled: additionalQueueDimensionsEnabled,
+		costBasedFairnessEnabled:         costBasedFairnessEnabled,
 		forgetDelay:                      forgetDelay,
 
 		connectedQuerierWorkers: atomic.NewInt32(0),
@@ -163,7 +178,7 @@ func (q *RequestQueue) starting(_ context.Context) error {
 
 func (q *RequestQueue) dispatcherLoop() {
 	stopping := false
-	queueBroker := newQueueBroker(q.maxOutstandingPerTenant, q.additionalQueueDimensionsEnabled, q.forgetDelay)
+	queueBroker := newQueueBroker(q.maxOutstandingPerTenant, q.additionalQueueDimensionsEnabled, q.costBasedFairnessEnabled, q.forgetDelay)
 	waitingGetNextRequestForQuerierCalls := list.New()
 
 	for {
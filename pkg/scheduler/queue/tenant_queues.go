@@ -79,6 +79,10 @@ type tenantQuerierAssignments struct {
 	tenantIDOrder []TenantID
 	tenantsByID   map[TenantID]*queueTenant
 
+	// Cost consumed by the tenants removed from the queue while other tenants were still queued,
+	// so that a tenant draining its queue and coming back doesn't start again from the lowest cost.
+	removedTenantsConsumedCost map[TenantID]float64
+
 	// Tenant assigned querier ID set as determined by shuffle sharding.
 	// If tenant querier ID set is not nil, only those queriers can handle the tenant's requests,
 	// Tenant querier ID is set to nil if sharding is off or available queriers <= tenant's maxQueriers.
@@ -95,6 +99,10 @@ type queueTenant struct {
 
 	// points up to tenant order to enable efficient removal
 	orderIndex int
+
+	// sum of the estimated cost of the requests dequeued for this tenant since it was added to the queue;
+	// used to pick the next tenant to dequeue from when cost-based fairness is enabled
+	consumedCost float64
 }
 
 // queueBroker encapsulates access to tenant queues for pending requests
@@ -106,9 +114,10 @@ type queueBroker struct {
 
 	maxTenantQueueSize               int
 	additionalQueueDimensionsEnabled bool
+	costBasedFairnessEnabled         bool
 }
 
-func newQueueBroker(maxTenantQueueSize int, additionalQueueDimensionsEnabled bool, forgetDelay time.Duration) *queueBroker {
+func newQueueBroker(maxTenantQueueSize int, additionalQueueDimensionsEnabled bool, costBasedFairnessEnabled bool, forgetDelay time.Duration) *queueBroker {
 	return &queueBroker{
 		tenantQueuesTree: NewTreeQueue("root"),
 		tenantQuerierAssignments: tenantQuerierAssignments{
@@ -118,9 +127,12 @@ func newQueueBroker(maxTenantQueueSize int, additionalQueueDimensionsEnabled boo
 			tenantIDOrder:      nil,
 			tenantsByID:        map[TenantID]*queueTenant{},
 			tenantQuerierIDs:   map[TenantID]map[QuerierID]struct{}{},
+
+			removedTenantsConsumedCost: map[TenantID]float64{},
 		},
 		maxTenantQueueSize:               maxTenantQueueSize,
 		additionalQueueDimensionsEnabled: additionalQueueDimensionsEnabled,
+		costBasedFairnessEnabled:         costBasedFairnessEnabled,
 	}
 }
 
@@ -166,6 +178,13 @@ func (qb *queueBroker) enqueueRequestFront(request *tenantRequest, tenantMaxQuer
 	if err != nil {
 		return err
 	}
+
+	// The request has not been processed, so its cost is given back to the tenant. The consumed cost is clamped
+	// at zero, because the tenant may have been removed and re-added with a lower cost since the dequeue.
+	if tenant := qb.tenantQuerierAssignments.tenantsByID[request.tenantID]; tenant != nil {
+		tenant.consumedCost = max(0, tenant.consumedCost-requestCost(request.req))
+	}
+
 	return qb.tenantQueuesTree.EnqueueFrontByPath(queuePath, request)
 }
 
@@ -181,7 +200,16 @@ func (qb *queueBroker) makeQueuePath(request *tenantRequest) (QueuePath, error)
 }
 
 func (qb *queueBroker) dequeueRequestForQuerier(lastTenantIndex int, querierID QuerierID) (*tenantRequest, *queueTenant, int, error) {
-	tenant, tenantIndex, err := qb.tenantQuerierAssignments.getNextTenantForQuerier(lastTenantIndex, querierID)
+	var (
+		tenant      *queueTenant
+		tenantIndex int
+		err         error
+	)
+	if qb.costBasedFairnessEnabled {
+		tenant, tenantIndex, err = qb.tenantQuerierAssignments.getNextTenantForQuerierByCost(lastTenantIndex, querierID)
+	} else {
+		tenant, tenantIndex, err = qb.tenantQuerierAssignments.getNextTenantForQuerier(lastTenantIndex, querierID)
+	}
 	if tenant == nil || err != nil {
 		return nil, tenant, tenantIndex, err
 	}
@@ -189,21 +217,30 @@ func (qb *queueBroker) dequeueRequestForQuerier(lastTenantIndex int, querierID Q
 	queuePath := QueuePath{string(tenant.tenantID)}
 	queueElement := qb.tenantQueuesTree.DequeueByPath(queuePath)
 
-	queueNodeAfterDequeue := qb.tenantQueuesTree.getNode(queuePath)
-	if queueNodeAfterDequeue == nil {
-		// queue node was deleted due to being empty after dequeue
-		qb.tenantQuerierAssignments.removeTenant(tenant.tenantID)
-	}
-
 	var request *tenantRequest
 	if queueElement != nil {
 		// re-casting to same type it was enqueued as; panic would indicate a bug
 		request = queueElement.(*tenantRequest)
+		tenant.consumedCost += requestCost(request.req)
+	}
+
+	queueNodeAfterDequeue := qb.tenantQueuesTree.getNode(queuePath)
+	if queueNodeAfterDequeue == nil {
+		// queue node was deleted due to being empty after dequeue
+		qb.tenantQuerierAssignments.removeTenant(tenant.tenantID)
 	}
 
 	return request, tenant, tenantIndex, nil
 }
 
+// requestCost returns the estimated cost of a request. Requests with an unknown cost are assumed to cost 1.
+func requestCost(req Request) float64 {
+	if schedulerRequest, ok := req.(*SchedulerRequest); ok {
+		return schedulerRequest.Cost()
+	}
+	return 1
+}
+
 func (qb *queueBroker) addQuerierConnection(querierID QuerierID) {
 	qb.tenantQuerierAssignments.addQuerierConnection(querierID)
 }
@@ -262,6 +299,60 @@ func (tqa *tenantQuerierAssignments) getNextTenantForQuerier(lastTenantIndex int
 	return nil, lastTenantIndex, nil
 }
 
+// getNextTenantForQuerierByCost gets the tenant assigned to a given querier which has consumed the lowest cost.
+//
+// Tenants are visited in the same order as getNextTenantForQuerier, so that ties between tenants
+// which consumed the same cost are broken by rotating through the global tenant order.
+func (tqa *tenantQuerierAssignments) getNextTenantForQuerierByCost(lastTenantIndex int, querierID QuerierID) (*queueTenant, int, error) {
+	// check if querier is registered and is not shutting down
+	if q := tqa.queriersByID[querierID]; q == nil || q.shuttingDown {
+		return nil, lastTenantIndex, ErrQuerierShuttingDown
+	}
+
+	var selectedTenant *queueTenant
+	selectedTenantIndex := lastTenantIndex
+
+	tenantOrderIndex := lastTenantIndex
+	for iters := 0; iters < len(tqa.tenantIDOrder); iters++ {
+		tenantOrderIndex++
+		if tenantOrderIndex >= len(tqa.tenantIDOrder) {
+			// Do not use modulo to wrap this index; see getNextTenantForQuerier.
+			tenantOrderIndex = 0
+		}
+
+		tenantID := tqa.tenantIDOrder[tenantOrderIndex]
+		if tenantID == emptyTenantID {
+			continue
+		}
+
+		if tenantQuerierSet := tqa.tenantQuerierIDs[tenantID]; tenantQuerierSet != nil {
+			if _, ok := tenantQuerierSet[querierID]; !ok {
+				// tenant is not assigned this querier
+				continue
+			}
+		}
+
+		tenant := tqa.tenantsByID[tenantID]
+		if selectedTenant == nil || tenant.consumedCost < selectedTenant.consumedCost {
+			selectedTenant = tenant
+			selectedTenantIndex = tenantOrderIndex
+		}
+	}
+
+	return selectedTenant, selectedTenantIndex, nil
+}
+
+// minConsumedCost returns the lowest cost consumed by any tenant, or 0 if there are no tenants.
+func (tqa *tenantQuerierAssignments) minConsumedCost() float64 {
+	minCost, first := 0.0, true
+	for _, tenant := range tqa.tenantsByID {
+		if first || tenant.consumedCost < minCost {
+			minCost, first = tenant.consumedCost, false
+		}
+	}
+	return minCost
+}
+
 func (tqa *tenantQuerierAssignments) getTenant(tenantID TenantID) (*queueTenant, error) {
 	if tenantID == emptyTenantID {
 		return nil, ErrInvalidTenantID
@@ -295,6 +386,14 @@ func (tqa *tenantQuerierAssignments) createOrUpdateTenant(tenantID TenantID, max
 			shuffleShardSeed: util.ShuffleShardSeed(string(tenantID), ""),
 			// orderIndex set to sentinel value to indicate it is not inserted yet
 			orderIndex: -1,
+			// new tenants start from the lowest cost consumed by the other tenants, otherwise
+			// they would be prioritized until catching up with the tenants already in the queue
+			consumedCost: tqa.minConsumedCost(),
+		}
+		// tenants re-added while the queue was still busy keep the cost consumed before being removed
+		if removedCost, ok := tqa.removedTenantsConsumedCost[tenantID]; ok {
+			tenant.consumedCost = max(tenant.consumedCost, removedCost)
+			delete(tqa.removedTenantsConsumedCost, tenantID)
 		}
 		for i, id := range tqa.tenantIDOrder {
 			if id == emptyTenantID {
@@ -352,6 +451,7 @@ func (tqa *tenantQuerierAssignments) removeTenant(tenantID TenantID) {
 	}
 	delete(tqa.tenantsByID, tenantID)
 	tqa.tenantIDOrder[tenant.orderIndex] = emptyTenantID
+	tqa.recordRemovedTenantConsumedCost(tenant)
 
 	// Shrink tenant list if possible by removing empty tenant IDs.
 	// We remove only from the end; removing from the middle would re-index all tenant IDs
@@ -363,6 +463,29 @@ func (tqa *tenantQuerierAssignments) removeTenant(tenantID TenantID) {
 	}
 }
 
+// recordRemovedTenantConsumedCost keeps the cost consumed by a removed tenant until the tenant is
+// re-added. The recorded costs are forgotten once the queue is empty, and the costs not above the lowest
+// cost consumed by the remaining tenants are dropped, because a re-added tenant starts from it anyway.
+func (tqa *tenantQuerierAssignments) recordRemovedTenantConsumedCost(tenant *queueTenant) {
+	if tqa.removedTenantsConsumedCost == nil {
+		tqa.removedTenantsConsumedCost = map[TenantID]float64{}
+	}
+	if len(tqa.tenantsByID) == 0 {
+		clear(tqa.removedTenantsConsumedCost)
+		return
+	}
+
+	minCost := tqa.minConsumedCost()
+	for tenantID, cost := range tqa.removedTenantsConsumedCost {
+		if cost <= minCost {
+			delete(tqa.removedTenantsConsumedCost, tenantID)
+		}
+	}
+	if tenant.consumedCost > minCost {
+		tqa.removedTenantsConsumedCost[tenant.tenantID] = tenant.consumedCost
+	}
+}
+
 func (tqa *tenantQuerierAssignments) removeQuerierConnection(querierID QuerierID, now time.Time) {
 	querier := tqa.queriersByID[querierID]
 	if querier == nil || querier.connections <= 0 {
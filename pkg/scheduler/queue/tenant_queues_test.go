@@ -21,7 +21,7 @@ import (
 )
 
 func TestQueues(t *testing.T) {
-	qb := newQueueBroker(0, true, 0)
+	qb := newQueueBroker(0, true, false, 0)
 	assert.NotNil(t, qb)
 	assert.NoError(t, isConsistent(qb))
 
@@ -81,7 +81,7 @@ func TestQueues(t *testing.T) {
 
 func TestQueuesRespectMaxTenantQueueSizeWithSubQueues(t *testing.T) {
 	maxTenantQueueSize := 100
-	qb := newQueueBroker(maxTenantQueueSize, true, 0)
+	qb := newQueueBroker(maxTenantQueueSize, true, false, 0)
 	additionalQueueDimensions := map[int][]string{
 		0: nil,
 		1: {"ingester"},
@@ -142,7 +142,7 @@ func TestQueuesRespectMaxTenantQueueSizeWithSubQueues(t *testing.T) {
 }
 
 func TestQueuesOnTerminatingQuerier(t *testing.T) {
-	qb := newQueueBroker(0, true, 0)
+	qb := newQueueBroker(0, true, false, 0)
 	assert.NotNil(t, qb)
 	assert.NoError(t, isConsistent(qb))
 
@@ -171,8 +171,118 @@ func TestQueuesOnTerminatingQuerier(t *testing.T) {
 	assert.Equal(t, ErrQuerierShuttingDown, err)
 }
 
+func TestQueues_CostBasedFairness(t *testing.T) {
+	qb := newQueueBroker(100, false, true, 0)
+	qb.addQuerierConnection("querier-1")
+
+	enqueue := func(tenantID TenantID, cost float64) {
+		req := &tenantRequest{
+			tenantID: tenantID,
+			req:      &SchedulerRequest{UserID: string(tenantID), EstimatedCost: cost},
+		}
+		require.NoError(t, qb.enqueueRequestBack(req, 0))
+	}
+	dequeue := func() *tenantRequest {
+		req, _, _, err := qb.dequeueRequestForQuerier(-1, "querier-1")
+		require.NoError(t, err)
+		require.NotNil(t, req)
+		return req
+	}
+
+	for i := 0; i < 3; i++ {
+		enqueue("expensive", 10)
+		enqueue("cheap", 1)
+	}
+
+	// Both tenants start with no consumed cost, so the first one in the tenant order is picked. Then the
+	// cheap tenant is picked until its consumed cost catches up with the expensive one.
+	var actualOrder []TenantID
+	for i := 0; i < 6; i++ {
+		actualOrder = append(actualOrder, dequeue().tenantID)
+	}
+	assert.Equal(t, []TenantID{"expensive", "cheap", "cheap", "cheap", "expensive", "expensive"}, actualOrder)
+	assert.True(t, qb.isEmpty())
+
+	t.Run("new tenants start from the lowest consumed cost", func(t *testing.T) {
+		enqueue("expensive", 10)
+		enqueue("expensive", 10)
+		require.Equal(t, TenantID("expensive"), dequeue().tenantID)
+
+		enqueue("cheap", 1)
+		tenant, err := qb.tenantQuerierAssignments.getTenant("cheap")
+		require.NoError(t, err)
+		assert.Equal(t, 10.0, tenant.consumedCost)
+
+		// Ties are broken by following the tenant order, so the remaining requests are picked
+		// starting from the expensive tenant.
+		require.Equal(t, TenantID("expensive"), dequeue().tenantID)
+		require.Equal(t, TenantID("cheap"), dequeue().tenantID)
+		assert.True(t, qb.isEmpty())
+	})
+
+	t.Run("re-enqueued requests give back their cost", func(t *testing.T) {
+		enqueue("expensive", 10)
+		enqueue("expensive", 10)
+		req := dequeue()
+
+		tenant, err := qb.tenantQuerierAssignments.getTenant("expensive")
+		require.NoError(t, err)
+		assert.Equal(t, 10.0, tenant.consumedCost)
+
+		require.NoError(t, qb.enqueueRequestFront(req, 0))
+		assert.Equal(t, 0.0, tenant.consumedCost)
+	})
+
+	t.Run("the consumed cost doesn't go below zero", func(t *testing.T) {
+		for !qb.isEmpty() {
+			dequeue()
+		}
+
+		enqueue("single", 10)
+		req := dequeue()
+
+		// The tenant has been removed from the queue after the dequeue, so it's re-added with the lowest
+		// consumed cost before its request cost is given back.
+		require.NoError(t, qb.enqueueRequestFront(req, 0))
+		tenant, err := qb.tenantQuerierAssignments.getTenant("single")
+		require.NoError(t, err)
+		assert.Equal(t, 0.0, tenant.consumedCost)
+	})
+
+	t.Run("tenants re-added while other tenants are queued keep their consumed cost", func(t *testing.T) {
+		for !qb.isEmpty() {
+			dequeue()
+		}
+
+		enqueue("busy", 1)
+		enqueue("busy", 1)
+		enqueue("draining", 10)
+		require.Equal(t, TenantID("busy"), dequeue().tenantID)
+		require.Equal(t, TenantID("draining"), dequeue().tenantID)
+
+		// The draining tenant has been removed from the queue, but it's re-added with the cost consumed so far
+		// instead of the lowest cost, so the busy tenant is picked first.
+		enqueue("draining", 10)
+		tenant, err := qb.tenantQuerierAssignments.getTenant("draining")
+		require.NoError(t, err)
+		assert.Equal(t, 10.0, tenant.consumedCost)
+		require.Equal(t, TenantID("busy"), dequeue().tenantID)
+		require.Equal(t, TenantID("draining"), dequeue().tenantID)
+
+		// The recorded costs are forgotten once the queue is empty.
+		assert.True(t, qb.isEmpty())
+		assert.Empty(t, qb.tenantQuerierAssignments.removedTenantsConsumedCost)
+	})
+
+	t.Run("requests with unknown cost are assumed to cost 1", func(t *testing.T) {
+		assert.Equal(t, 1.0, requestCost(&SchedulerRequest{}))
+		assert.Equal(t, 1.0, requestCost("frontend v1 request"))
+		assert.Equal(t, 5.0, requestCost(&SchedulerRequest{EstimatedCost: 5}))
+	})
+}
+
 func TestQueuesWithQueriers(t *testing.T) {
-	qb := newQueueBroker(0, true, 0)
+	qb := newQueueBroker(0, true, false, 0)
 	assert.NotNil(t, qb)
 	assert.NoError(t, isConsistent(qb))
 
@@ -251,7 +361,7 @@ func TestQueuesConsistency(t *testing.T) {
 
 	for testName, testData := range tests {
 		t.Run(testName, func(t *testing.T) {
-			qb := newQueueBroker(0, true, testData.forgetDelay)
+			qb := newQueueBroker(0, true, false, testData.forgetDelay)
 			assert.NotNil(t, qb)
 			assert.NoError(t, isConsistent(qb))
 
@@ -302,7 +412,7 @@ func TestQueues_ForgetDelay(t *testing.T) {
 	)
 
 	now := time.Now()
-	qb := newQueueBroker(0, true, forgetDelay)
+	qb := newQueueBroker(0, true, false, forgetDelay)
 	assert.NotNil(t, qb)
 	assert.NoError(t, isConsistent(qb))
 
@@ -394,7 +504,7 @@ func TestQueues_ForgetDelay_ShouldCorrectlyHandleQuerierReconnectingBeforeForget
 	)
 
 	now := time.Now()
-	qb := newQueueBroker(0, true, forgetDelay)
+	qb := newQueueBroker(0, true, false, forgetDelay)
 	assert.NotNil(t, qb)
 	assert.NoError(t, isConsistent(qb))
 
@@ -5,6 +5,7 @@ package schedulerpb
 
 import (
 	context "context"
+	encoding_binary "encoding/binary"
 	fmt "fmt"
 	_ "github.com/gogo/protobuf/gogoproto"
 	proto "github.com/gogo/protobuf/proto"
@@ -229,6 +230,8 @@ type FrontendToScheduler struct {
 	HttpRequest               *httpgrpc.HTTPRequest `protobuf:"bytes,5,opt,name=httpRequest,proto3" json:"httpRequest,omitempty"`
 	StatsEnabled              bool                  `protobuf:"varint,6,opt,name=statsEnabled,proto3" json:"statsEnabled,omitempty"`
 	AdditionalQueueDimensions []string              `protobuf:"bytes,7,rep,name=additionalQueueDimensions,proto3" json:"additionalQueueDimensions,omitempty"`
+	// Estimated cost of executing the query, used by the scheduler to fairly dequeue requests across tenants.
+	EstimatedCost float64 `protobuf:"fixed64,8,opt,name=estimatedCost,proto3" json:"estimatedCost,omitempty"`
 }
 
 func (m *FrontendToScheduler) Reset()      { *m = FrontendToScheduler{} }
@@ -312,6 +315,13 @@ func (m *FrontendToScheduler) GetAdditionalQueueDimensions() []string {
 	return nil
 }
 
+func (m *FrontendToScheduler) GetEstimatedCost() float64 {
+	if m != nil {
+		return m.EstimatedCost
+	}
+	return 0
+}
+
 type SchedulerToFrontend struct {
 	Status SchedulerToFrontendStatus `protobuf:"varint,1,opt,name=status,proto3,enum=schedulerpb.SchedulerToFrontendStatus" json:"status,omitempty"`
 	Error  string                    `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
@@ -455,51 +465,52 @@ func init() {
 func init() { proto.RegisterFile("scheduler.proto", fileDescriptor_2b3fc28395a6d9c5) }
 
 var fileDescriptor_2b3fc28395a6d9c5 = []byte{
-	// 690 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x94, 0x4f, 0x53, 0xda, 0x40,
-	0x18, 0xc6, 0xb3, 0xfc, 0x53, 0x5e, 0xac, 0xd2, 0x55, 0x5b, 0x64, 0x6c, 0xcc, 0x30, 0x1d, 0x87,
-	0x7a, 0x00, 0x87, 0x1e, 0xda, 0x83, 0xd3, 0x19, 0xaa, 0xb1, 0x32, 0xb5, 0x41, 0x96, 0x30, 0xfd,
-	0x73, 0x61, 0x02, 0x59, 0x21, 0xa3, 0x66, 0x63, 0x36, 0x99, 0x0e, 0xb7, 0x7e, 0x84, 0x7e, 0x8c,
-	0x7e, 0x92, 0x4e, 0x8f, 0x1e, 0x3d, 0xf4, 0x50, 0xe3, 0xa5, 0x47, 0x2f, 0xbd, 0x77, 0x08, 0x81,
-	0x06, 0x0a, 0xea, 0x6d, 0xf7, 0xe5, 0x79, 0xd8, 0x7d, 0x7e, 0xef, 0x9b, 0x85, 0x25, 0xde, 0xee,
-	0x52, 0xdd, 0x3d, 0xa5, 0x76, 0xc1, 0xb2, 0x99, 0xc3, 0x70, 0x6a, 0x54, 0xb0, 0x5a, 0xd9, 0x95,
-	0x0e, 0xeb, 0x30, 0xbf, 0x5e, 0xec, 0xaf, 0x06, 0x92, 0xec, 0x76, 0xc7, 0x70, 0xba, 0x6e, 0xab,
-	0xd0, 0x66, 0x67, 0xc5, 0x8e, 0xad, 0x1d, 0x6b, 0xa6, 0x56, 0xd4, 0xf9, 0x89, 0xe1, 0x14, 0xbb,
-	0x8e, 0x63, 0x75, 0x6c, 0xab, 0x3d, 0x5a, 0x0c, 0x1c, 0xb9, 0x12, 0xe0, 0x9a, 0x4b, 0x6d, 0x83,
-	0xda, 0x2a, 0xab, 0x0f, 0xff, 0x1f, 0xaf, 0x43, 0xf2, 0x7c, 0x50, 0xad, 0xec, 0x65, 0x90, 0x84,
-	0xf2, 0x49, 0xf2, 0xaf, 0x90, 0xfb, 0x83, 0x00, 0x8f, 0xb4, 0x2a, 0x0b, 0xfc, 0x38, 0x03, 0x73,
-	0x7d, 0x4d, 0x2f, 0xb0, 0xc4, 0xc8, 0x70, 0x8b, 0x5f, 0x40, 0xaa, 0x7f, 0x2c, 0xa1, 0xe7, 0x2e,
-	0xe5, 0x4e, 0x26, 0x22, 0xa1, 0x7c, 0xaa, 0xb4, 0x5a, 0x18, 0x5d, 0xe5, 0x40, 0x55, 0x8f, 0x82,
-	0x1f, 0x49, 0x58, 0x89, 0xf3, 0xb0, 0x74, 0x6c, 0x33, 0xd3, 0xa1, 0xa6, 0x5e, 0xd6, 0x75, 0x9b,
-	0x72, 0x9e, 0x89, 0xfa, 0xb7, 0x99, 0x2c, 0xe3, 0x47, 0x90, 0x70, 0xb9, 0x7f, 0xdd, 0x98, 0x2f,
-	0x08, 0x76, 0x38, 0x07, 0x0b, 0xdc, 0xd1, 0x1c, 0x2e, 0x9b, 0x5a, 0xeb, 0x94, 0xea, 0x99, 0xb8,
-	0x84, 0xf2, 0xf3, 0x64, 0xac, 0x86, 0x37, 0x61, 0xf1, 0xdc, 0xa5, 0x2e, 0x55, 0x8d, 0x33, 0xaa,
-	0x68, 0x26, 0xe3, 0x99, 0x84, 0x84, 0xf2, 0x51, 0x32, 0x51, 0xcd, 0x7d, 0x8f, 0xc0, 0xf2, 0x7e,
-	0x70, 0x6e, 0x98, 0xd6, 0x4b, 0x88, 0x39, 0x3d, 0x8b, 0xfa, 0xa9, 0x17, 0x4b, 0x4f, 0x0b, 0xa1,
-	0x3e, 0x15, 0xa6, 0xe8, 0xd5, 0x9e, 0x45, 0x89, 0xef, 0x98, 0x96, 0x2f, 0x32, 0x3d, 0x5f, 0x08,
-	0x6e, 0x74, 0x1c, 0xee, 0xac, 0xe4, 0x13, 0xd0, 0xe3, 0xf7, 0x86, 0x3e, 0x89, 0x2c, 0x31, 0x05,
-	0xd9, 0x0e, 0xac, 0x69, 0xba, 0x6e, 0x38, 0x06, 0x33, 0xb5, 0xd3, 0x5a, 0x1f, 0xd3, 0x9e, 0x71,
-	0x46, 0x4d, 0x6e, 0x30, 0x93, 0x67, 0xe6, 0xa4, 0x68, 0x3e, 0x49, 0x66, 0x0b, 0x72, 0x27, 0xb0,
-	0x1c, 0x9a, 0x9f, 0x21, 0x22, 0xfc, 0x0a, 0x12, 0xfd, 0x43, 0x5c, 0x1e, 0x90, 0xdc, 0x1c, 0x23,
-	0x39, 0xc5, 0x51, 0xf7, 0xd5, 0x24, 0x70, 0xe1, 0x15, 0x88, 0x53, 0xdb, 0x66, 0x76, 0xc0, 0x70,
-	0xb0, 0xc9, 0xed, 0xc0, 0xba, 0xc2, 0x1c, 0xe3, 0xb8, 0x17, 0xcc, 0x69, 0xbd, 0xeb, 0x3a, 0x3a,
-	0xfb, 0x6c, 0x0e, 0xe3, 0xde, 0x3e, 0xeb, 0x1b, 0xf0, 0x64, 0x86, 0x9b, 0x5b, 0xcc, 0xe4, 0x74,
-	0x6b, 0x07, 0x1e, 0xcf, 0xe8, 0x31, 0x9e, 0x87, 0x58, 0x45, 0xa9, 0xa8, 0x69, 0x01, 0xa7, 0x60,
-	0x4e, 0x56, 0x6a, 0x0d, 0xb9, 0x21, 0xa7, 0x11, 0x06, 0x48, 0xec, 0x96, 0x95, 0x5d, 0xf9, 0x30,
-	0x1d, 0xd9, 0x6a, 0xc3, 0xda, 0xcc, 0x5c, 0x38, 0x01, 0x91, 0xea, 0xdb, 0xb4, 0x80, 0x25, 0x58,
-	0x57, 0xab, 0xd5, 0xe6, 0xbb, 0xb2, 0xf2, 0xb1, 0x49, 0xe4, 0x5a, 0x43, 0xae, 0xab, 0xf5, 0xe6,
-	0x91, 0x4c, 0x9a, 0xaa, 0xac, 0x94, 0x15, 0x35, 0x8d, 0x70, 0x12, 0xe2, 0x32, 0x21, 0x55, 0x92,
-	0x8e, 0xe0, 0x87, 0xf0, 0xa0, 0x7e, 0xd0, 0x50, 0xd5, 0x8a, 0xf2, 0xa6, 0xb9, 0x57, 0x7d, 0xaf,
-	0xa4, 0xa3, 0xa5, 0x9f, 0x28, 0xc4, 0x7b, 0x9f, 0xd9, 0xc3, 0x0f, 0xb6, 0x01, 0xa9, 0x60, 0x79,
-	0xc8, 0x98, 0x85, 0x37, 0xc6, 0x70, 0xff, 0xff, 0x2a, 0x64, 0x37, 0x66, 0xf5, 0x23, 0xd0, 0xe6,
-	0x84, 0x3c, 0xda, 0x46, 0xd8, 0x84, 0xd5, 0xa9, 0xc8, 0xf0, 0xb3, 0x31, 0xff, 0x6d, 0x4d, 0xc9,
-	0x6e, 0xdd, 0x47, 0x3a, 0xe8, 0x40, 0xc9, 0x82, 0x95, 0x70, 0xba, 0xd1, 0x38, 0x7d, 0x80, 0x85,
-	0xe1, 0xda, 0xcf, 0x27, 0xdd, 0xf5, 0x61, 0x66, 0xa5, 0xbb, 0x06, 0x6e, 0x90, 0xf0, 0x75, 0xf9,
-	0xe2, 0x4a, 0x14, 0x2e, 0xaf, 0x44, 0xe1, 0xe6, 0x4a, 0x44, 0x5f, 0x3c, 0x11, 0x7d, 0xf3, 0x44,
-	0xf4, 0xc3, 0x13, 0xd1, 0x85, 0x27, 0xa2, 0x5f, 0x9e, 0x88, 0x7e, 0x7b, 0xa2, 0x70, 0xe3, 0x89,
-	0xe8, 0xeb, 0xb5, 0x28, 0x5c, 0x5c, 0x8b, 0xc2, 0xe5, 0xb5, 0x28, 0x7c, 0x0a, 0xbf, 0xdf, 0xad,
-	0x84, 0xff, 0xfc, 0x3e, 0xff, 0x1b, 0x00, 0x00, 0xff, 0xff, 0x68, 0xe9, 0x45, 0x9c, 0xe6, 0x05,
-	0x00, 0x00,
+	// 712 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x54, 0x4d, 0x53, 0xe2, 0x58,
+	0x14, 0xcd, 0xe3, 0x4b, 0xb9, 0xf8, 0xc1, 0x3c, 0x75, 0x26, 0x52, 0x4e, 0x4c, 0x51, 0x96, 0x95,
+	0x71, 0x01, 0x16, 0xb3, 0x98, 0x59, 0x58, 0x53, 0xc5, 0x68, 0x1c, 0xa9, 0x71, 0x82, 0x84, 0x50,
+	0x33, 0xd3, 0x1b, 0x2a, 0x90, 0x27, 0xa4, 0x94, 0xbc, 0x98, 0xf7, 0x52, 0x5d, 0xec, 0xfa, 0x27,
+	0xf4, 0xcf, 0xe8, 0x9f, 0xd2, 0x4b, 0x97, 0x2e, 0x7a, 0xd1, 0xe2, 0xa6, 0x17, 0xbd, 0x70, 0xd3,
+	0xfb, 0x2e, 0x42, 0xa0, 0x03, 0x0d, 0xea, 0xee, 0xbe, 0xc3, 0x39, 0xe4, 0x9e, 0x73, 0xef, 0x7b,
+	0xb0, 0xce, 0xda, 0x5d, 0x62, 0xf9, 0xd7, 0xc4, 0x2b, 0xb8, 0x1e, 0xe5, 0x14, 0x67, 0x26, 0x80,
+	0xdb, 0xca, 0x6d, 0x76, 0x68, 0x87, 0x06, 0x78, 0x71, 0x58, 0x8d, 0x28, 0xb9, 0xc3, 0x8e, 0xcd,
+	0xbb, 0x7e, 0xab, 0xd0, 0xa6, 0xbd, 0x62, 0xc7, 0x33, 0x2f, 0x4d, 0xc7, 0x2c, 0x5a, 0xec, 0xca,
+	0xe6, 0xc5, 0x2e, 0xe7, 0x6e, 0xc7, 0x73, 0xdb, 0x93, 0x62, 0xa4, 0xc8, 0x97, 0x00, 0xd7, 0x7c,
+	0xe2, 0xd9, 0xc4, 0x33, 0x68, 0x7d, 0xfc, 0xff, 0x78, 0x07, 0xd2, 0x37, 0x23, 0xb4, 0x72, 0x22,
+	0x22, 0x19, 0x29, 0x69, 0xfd, 0x1b, 0x90, 0xff, 0x82, 0x00, 0x4f, 0xb8, 0x06, 0x0d, 0xf5, 0x58,
+	0x84, 0xa5, 0x21, 0xa7, 0x1f, 0x4a, 0x12, 0xfa, 0xf8, 0x88, 0x7f, 0x83, 0xcc, 0xf0, 0xb3, 0x3a,
+	0xb9, 0xf1, 0x09, 0xe3, 0x62, 0x4c, 0x46, 0x4a, 0xa6, 0xb4, 0x55, 0x98, 0xb4, 0x72, 0x66, 0x18,
+	0x17, 0xe1, 0x8f, 0x7a, 0x94, 0x89, 0x15, 0x58, 0xbf, 0xf4, 0xa8, 0xc3, 0x89, 0x63, 0x95, 0x2d,
+	0xcb, 0x23, 0x8c, 0x89, 0xf1, 0xa0, 0x9b, 0x59, 0x18, 0xff, 0x08, 0x29, 0x9f, 0x05, 0xed, 0x26,
+	0x02, 0x42, 0x78, 0xc2, 0x79, 0x58, 0x61, 0xdc, 0xe4, 0x4c, 0x75, 0xcc, 0xd6, 0x35, 0xb1, 0xc4,
+	0xa4, 0x8c, 0x94, 0x65, 0x7d, 0x0a, 0xc3, 0xfb, 0xb0, 0x76, 0xe3, 0x13, 0x9f, 0x18, 0x76, 0x8f,
+	0x68, 0xa6, 0x43, 0x99, 0x98, 0x92, 0x91, 0x12, 0xd7, 0x67, 0xd0, 0xfc, 0xe7, 0x18, 0x6c, 0x9c,
+	0x86, 0xdf, 0x8d, 0xa6, 0xf5, 0x3b, 0x24, 0x78, 0xdf, 0x25, 0x81, 0xeb, 0xb5, 0xd2, 0x5e, 0x21,
+	0x32, 0xa7, 0xc2, 0x1c, 0xbe, 0xd1, 0x77, 0x89, 0x1e, 0x28, 0xe6, 0xf9, 0x8b, 0xcd, 0xf7, 0x17,
+	0x09, 0x37, 0x3e, 0x1d, 0xee, 0x22, 0xe7, 0x33, 0xa1, 0x27, 0x5f, 0x1c, 0xfa, 0x6c, 0x64, 0xa9,
+	0x39, 0x91, 0x1d, 0xc1, 0xb6, 0x69, 0x59, 0x36, 0xb7, 0xa9, 0x63, 0x5e, 0xd7, 0x7c, 0xe2, 0x93,
+	0x13, 0xbb, 0x47, 0x1c, 0x66, 0x53, 0x87, 0x89, 0x4b, 0x72, 0x5c, 0x49, 0xeb, 0x8b, 0x09, 0x78,
+	0x0f, 0x56, 0x09, 0xe3, 0x76, 0xcf, 0xe4, 0xc4, 0x3a, 0xa6, 0x8c, 0x8b, 0xcb, 0x32, 0x52, 0x90,
+	0x3e, 0x0d, 0xe6, 0xaf, 0x60, 0x23, 0xb2, 0x65, 0xe3, 0x20, 0xf1, 0x1f, 0x90, 0x1a, 0xb6, 0xe2,
+	0xb3, 0x30, 0xef, 0xfd, 0xa9, 0xbc, 0xe7, 0x28, 0xea, 0x01, 0x5b, 0x0f, 0x55, 0x78, 0x13, 0x92,
+	0xc4, 0xf3, 0xa8, 0x17, 0x26, 0x3d, 0x3a, 0xe4, 0x8f, 0x60, 0x47, 0xa3, 0xdc, 0xbe, 0xec, 0x87,
+	0xdb, 0x5c, 0xef, 0xfa, 0xdc, 0xa2, 0xaf, 0x9d, 0x71, 0x28, 0x4f, 0xdf, 0x88, 0x5d, 0xf8, 0x79,
+	0x81, 0x9a, 0xb9, 0xd4, 0x61, 0xe4, 0xe0, 0x08, 0x7e, 0x5a, 0xb0, 0x09, 0x78, 0x19, 0x12, 0x15,
+	0xad, 0x62, 0x64, 0x05, 0x9c, 0x81, 0x25, 0x55, 0xab, 0x35, 0xd4, 0x86, 0x9a, 0x45, 0x18, 0x20,
+	0x75, 0x5c, 0xd6, 0x8e, 0xd5, 0xf3, 0x6c, 0xec, 0xa0, 0x0d, 0xdb, 0x0b, 0x7d, 0xe1, 0x14, 0xc4,
+	0xaa, 0x7f, 0x67, 0x05, 0x2c, 0xc3, 0x8e, 0x51, 0xad, 0x36, 0xff, 0x29, 0x6b, 0xff, 0x37, 0x75,
+	0xb5, 0xd6, 0x50, 0xeb, 0x46, 0xbd, 0x79, 0xa1, 0xea, 0x4d, 0x43, 0xd5, 0xca, 0x9a, 0x91, 0x45,
+	0x38, 0x0d, 0x49, 0x55, 0xd7, 0xab, 0x7a, 0x36, 0x86, 0x7f, 0x80, 0xd5, 0xfa, 0x59, 0xc3, 0x30,
+	0x2a, 0xda, 0x5f, 0xcd, 0x93, 0xea, 0xbf, 0x5a, 0x36, 0x5e, 0xfa, 0x80, 0x22, 0x79, 0x9f, 0x52,
+	0x6f, 0x7c, 0xad, 0x1b, 0x90, 0x09, 0xcb, 0x73, 0x4a, 0x5d, 0xbc, 0x3b, 0x15, 0xf7, 0xf7, 0x6f,
+	0x47, 0x6e, 0x77, 0xd1, 0x3c, 0x42, 0x6e, 0x5e, 0x50, 0xd0, 0x21, 0xc2, 0x0e, 0x6c, 0xcd, 0x8d,
+	0x0c, 0xff, 0x32, 0xa5, 0x7f, 0x6a, 0x28, 0xb9, 0x83, 0x97, 0x50, 0x47, 0x13, 0x28, 0xb9, 0xb0,
+	0x19, 0x75, 0x37, 0x59, 0xa7, 0xff, 0x60, 0x65, 0x5c, 0x07, 0xfe, 0xe4, 0xe7, 0xae, 0x6f, 0x4e,
+	0x7e, 0x6e, 0xe1, 0x46, 0x0e, 0xff, 0x2c, 0xdf, 0xde, 0x4b, 0xc2, 0xdd, 0xbd, 0x24, 0x3c, 0xde,
+	0x4b, 0xe8, 0xcd, 0x40, 0x42, 0xef, 0x06, 0x12, 0x7a, 0x3f, 0x90, 0xd0, 0xed, 0x40, 0x42, 0x1f,
+	0x07, 0x12, 0xfa, 0x34, 0x90, 0x84, 0xc7, 0x81, 0x84, 0xde, 0x3e, 0x48, 0xc2, 0xed, 0x83, 0x24,
+	0xdc, 0x3d, 0x48, 0xc2, 0xab, 0xe8, 0x2b, 0xdf, 0x4a, 0x05, 0x8f, 0xf4, 0xaf, 0x5f, 0x07, 0x00,
+	0x25, 0x98, 0x57, 0xf9, 0x0c, 0x06, 0x00, 0x00,
 }
 
 func (x FrontendToSchedulerType) String() string {
@@ -624,6 +635,9 @@ func (this *FrontendToScheduler) Equal(that interface{}) bool {
 			return false
 		}
 	}
+	if this.EstimatedCost != that1.EstimatedCost {
+		return false
+	}
 	return true
 }
 func (this *SchedulerToFrontend) Equal(that interface{}) bool {
@@ -729,7 +743,7 @@ func (this *FrontendToScheduler) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 11)
+	s := make([]string, 0, 12)
 	s = append(s, "&schedulerpb.FrontendToScheduler{")
 	s = append(s, "Type: "+fmt.Sprintf("%#v", this.Type)+",\n")
 	s = append(s, "FrontendAddress: "+fmt.Sprintf("%#v", this.FrontendAddress)+",\n")
@@ -740,6 +754,7 @@ func (this *FrontendToScheduler) GoString() string {
 	}
 	s = append(s, "StatsEnabled: "+fmt.Sprintf("%#v", this.StatsEnabled)+",\n")
 	s = append(s, "AdditionalQueueDimensions: "+fmt.Sprintf("%#v", this.AdditionalQueueDimensions)+",\n")
+	s = append(s, "EstimatedCost: "+fmt.Sprintf("%#v", this.EstimatedCost)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -1180,6 +1195,12 @@ func (m *FrontendToScheduler) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.EstimatedCost != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.EstimatedCost))))
+		i--
+		dAtA[i] = 0x41
+	}
 	if len(m.AdditionalQueueDimensions) > 0 {
 		for iNdEx := len(m.AdditionalQueueDimensions) - 1; iNdEx >= 0; iNdEx-- {
 			i -= len(m.AdditionalQueueDimensions[iNdEx])
@@ -1413,6 +1434,9 @@ func (m *FrontendToScheduler) Size() (n int) {
 			n += 1 + l + sovScheduler(uint64(l))
 		}
 	}
+	if m.EstimatedCost != 0 {
+		n += 9
+	}
 	return n
 }
 
@@ -1497,6 +1521,7 @@ func (this *FrontendToScheduler) String() string {
 		`HttpRequest:` + strings.Replace(fmt.Sprintf("%v", this.HttpRequest), "HTTPRequest", "httpgrpc.HTTPRequest", 1) + `,`,
 		`StatsEnabled:` + fmt.Sprintf("%v", this.StatsEnabled) + `,`,
 		`AdditionalQueueDimensions:` + fmt.Sprintf("%v", this.AdditionalQueueDimensions) + `,`,
+		`EstimatedCost:` + fmt.Sprintf("%v", this.EstimatedCost) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -2054,6 +2079,17 @@ func (m *FrontendToScheduler) Unmarshal(dAtA []byte) error {
 			}
 			m.AdditionalQueueDimensions = append(m.AdditionalQueueDimensions, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
+		case 8:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EstimatedCost", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.EstimatedCost = float64(math.Float64frombits(v))
 		default:
 			iNdEx = preIndex
 			skippy, err := skipScheduler(dAtA[iNdEx:])
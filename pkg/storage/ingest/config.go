@@ -5,23 +5,46 @@ package ingest
 import (
 	"errors"
 	"flag"
+	"fmt"
+	"strings"
 	"time"
 )
 
+const (
+	consumeFromConsumerGroup = "consumer-group"
+	consumeFromOffset        = "offset"
+	consumeFromTimestamp     = "timestamp"
+)
+
+var consumeFromPositionOptions = []string{consumeFromConsumerGroup, consumeFromOffset, consumeFromTimestamp}
+
 var (
 	ErrMissingKafkaAddress = errors.New("the Kafka address has not been configured")
 	ErrMissingKafkaTopic   = errors.New("the Kafka topic has not been configured")
+
+	ErrInvalidConsumeFromPosition     = fmt.Errorf("the consume from position at startup must be one of %s", strings.Join(consumeFromPositionOptions, ", "))
+	ErrInvalidConsumeFromOffset       = errors.New("the consume from offset at startup must be greater than or equal to 0")
+	ErrInvalidConsumeFromTimestamp    = errors.New("the consume from timestamp at startup must be greater than 0")
+	ErrInvalidReadinessMaxConsumerLag = errors.New("the readiness max consumer lag must be greater than or equal to 0")
+
+	ErrInvalidProducerBatchMaxBytes = fmt.Errorf("the producer batch max bytes must be greater than 0 and lower than or equal to %d", producerBatchMaxBytes)
+	ErrInvalidProducerBatchLinger   = errors.New("the producer batch linger must be greater than 0")
+
+	ErrDeadLetterTopicAndSpoolDir      = errors.New("the dead-letter topic and spool directory can't be configured at the same time")
+	ErrInvalidDeadLetterMaxPushRetries = errors.New("the dead-letter max push retries must be greater than 0")
 )
 
 type Config struct {
-	Enabled     bool        `yaml:"enabled"`
-	KafkaConfig KafkaConfig `yaml:"kafka"`
+	Enabled          bool             `yaml:"enabled"`
+	KafkaConfig      KafkaConfig      `yaml:"kafka"`
+	DeadLetterConfig DeadLetterConfig `yaml:"dead_letter"`
 }
 
 func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	f.BoolVar(&cfg.Enabled, "ingest-storage.enabled", false, "True to enable the ingestion via object storage.")
 
 	cfg.KafkaConfig.RegisterFlagsWithPrefix("ingest-storage.kafka", f)
+	cfg.DeadLetterConfig.RegisterFlagsWithPrefix("ingest-storage.dead-letter", f)
 }
 
 // Validate the config.
@@ -35,6 +58,10 @@ func (cfg *Config) Validate() error {
 		return err
 	}
 
+	if err := cfg.DeadLetterConfig.Validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -48,6 +75,15 @@ type KafkaConfig struct {
 
 	LastProducedOffsetPollInterval time.Duration `yaml:"last_produced_offset_poll_interval"`
 	LastProducedOffsetRetryTimeout time.Duration `yaml:"last_produced_offset_retry_timeout"`
+
+	ConsumeFromPositionAtStartup  string `yaml:"consume_from_position_at_startup"`
+	ConsumeFromOffsetAtStartup    int64  `yaml:"consume_from_offset_at_startup"`
+	ConsumeFromTimestampAtStartup int64  `yaml:"consume_from_timestamp_at_startup"`
+	ReadinessMaxConsumerLag       int64  `yaml:"readiness_max_consumer_lag"`
+
+	ProducerBatchingEnabled bool          `yaml:"producer_batching_enabled"`
+	ProducerBatchMaxBytes   int           `yaml:"producer_batch_max_bytes"`
+	ProducerBatchLinger     time.Duration `yaml:"producer_batch_linger"`
 }
 
 func (cfg *KafkaConfig) RegisterFlags(f *flag.FlagSet) {
@@ -63,6 +99,15 @@ func (cfg *KafkaConfig) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet)
 
 	f.DurationVar(&cfg.LastProducedOffsetPollInterval, prefix+".last-produced-offset-poll-interval", time.Second, "How frequently to poll the last produced offset, used to enforce strong read consistency.")
 	f.DurationVar(&cfg.LastProducedOffsetRetryTimeout, prefix+".last-produced-offset-retry-timeout", 10*time.Second, "How long to retry a failed request to get the last produced offset.")
+
+	f.StringVar(&cfg.ConsumeFromPositionAtStartup, prefix+".consume-from-position-at-startup", consumeFromConsumerGroup, fmt.Sprintf("From which position to start consuming the partition at startup. Supported options: %s. The %s option resumes from the last offset committed by the consumer group, while the other options are meant to replay the partition after a disaster and, since the consumer group offset is overwritten as records are consumed, should be reverted once the replay is done.", strings.Join(consumeFromPositionOptions, ", "), consumeFromConsumerGroup))
+	f.Int64Var(&cfg.ConsumeFromOffsetAtStartup, prefix+".consume-from-offset-at-startup", 0, fmt.Sprintf("The offset from which to start consuming the partition at startup, when the consume from position is %s.", consumeFromOffset))
+	f.Int64Var(&cfg.ConsumeFromTimestampAtStartup, prefix+".consume-from-timestamp-at-startup", 0, fmt.Sprintf("The timestamp, in milliseconds, from which to start consuming the partition at startup, when the consume from position is %s. The consumption starts from the first record produced at or after the timestamp.", consumeFromTimestamp))
+	f.Int64Var(&cfg.ReadinessMaxConsumerLag, prefix+".readiness-max-consumer-lag", 0, "The maximum consumer lag, in number of records, below which the ingester is considered ready after startup. The ingester is kept not ready until the consumer lag has been below the threshold once. 0 to disable.")
+
+	f.BoolVar(&cfg.ProducerBatchingEnabled, prefix+".producer-batching-enabled", false, "True to coalesce the write requests for the same partition, possibly of different tenants, into a single batched record. All consumers must be able to decode batched records before enabling it.")
+	f.IntVar(&cfg.ProducerBatchMaxBytes, prefix+".producer-batch-max-bytes", 1024*1024, "The maximum size of a batched record, in bytes. A write request larger than this is written in a batched record on its own.")
+	f.DurationVar(&cfg.ProducerBatchLinger, prefix+".producer-batch-linger", 20*time.Millisecond, "How long to wait for more write requests before writing a batched record which hasn't reached the maximum size.")
 }
 
 func (cfg *KafkaConfig) Validate() error {
@@ -72,6 +117,62 @@ func (cfg *KafkaConfig) Validate() error {
 	if cfg.Topic == "" {
 		return ErrMissingKafkaTopic
 	}
+	switch cfg.ConsumeFromPositionAtStartup {
+	case consumeFromConsumerGroup:
+	case consumeFromOffset:
+		if cfg.ConsumeFromOffsetAtStartup < 0 {
+			return ErrInvalidConsumeFromOffset
+		}
+	case consumeFromTimestamp:
+		if cfg.ConsumeFromTimestampAtStartup <= 0 {
+			return ErrInvalidConsumeFromTimestamp
+		}
+	default:
+		return ErrInvalidConsumeFromPosition
+	}
+	if cfg.ReadinessMaxConsumerLag < 0 {
+		return ErrInvalidReadinessMaxConsumerLag
+	}
+	if cfg.ProducerBatchingEnabled {
+		if cfg.ProducerBatchMaxBytes <= 0 || cfg.ProducerBatchMaxBytes > producerBatchMaxBytes {
+			return ErrInvalidProducerBatchMaxBytes
+		}
+		if cfg.ProducerBatchLinger <= 0 {
+			return ErrInvalidProducerBatchLinger
+		}
+	}
+
+	return nil
+}
+
+// DeadLetterConfig holds the config for the handling of records which can't be applied when consumed.
+type DeadLetterConfig struct {
+	Topic          string `yaml:"topic"`
+	SpoolDir       string `yaml:"spool_dir"`
+	MaxPushRetries int    `yaml:"max_push_retries"`
+}
+
+func (cfg *DeadLetterConfig) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.StringVar(&cfg.Topic, prefix+".topic", "", "The Kafka topic where records which can't be applied are copied to, together with their tenant and offset. The topic is on the same Kafka backend of the ingest storage.")
+	f.StringVar(&cfg.SpoolDir, prefix+".spool-dir", "", "The local directory where records which can't be applied are copied to, one file per record, together with their tenant and offset. If neither the dead-letter topic nor the spool directory are configured, unparsable records are dropped and records failing with a server error are retried indefinitely.")
+	f.IntVar(&cfg.MaxPushRetries, prefix+".max-push-retries", 10, "How many times pushing a record failing with a server error is retried before the record is copied to the dead-letter topic or spool directory. Used only when either of them is configured.")
+}
+
+// Enabled returns whether records which can't be applied are copied to a dead-letter topic or spool directory.
+func (cfg *DeadLetterConfig) Enabled() bool {
+	return cfg.Topic != "" || cfg.SpoolDir != ""
+}
+
+func (cfg *DeadLetterConfig) Validate() error {
+	if !cfg.Enabled() {
+		return nil
+	}
+	if cfg.Topic != "" && cfg.SpoolDir != "" {
+		return ErrDeadLetterTopicAndSpoolDir
+	}
+	if cfg.MaxPushRetries <= 0 {
+		return ErrInvalidDeadLetterMaxPushRetries
+	}
 
 	return nil
 }
@@ -38,6 +38,108 @@ func TestConfig_Validate(t *testing.T) {
 				cfg.KafkaConfig.Topic = "test"
 			},
 		},
+		"should pass if ingest storage is enabled and producer batching is configured": {
+			setup: func(cfg *Config) {
+				cfg.Enabled = true
+				cfg.KafkaConfig.Address = "localhost"
+				cfg.KafkaConfig.Topic = "test"
+				cfg.KafkaConfig.ProducerBatchingEnabled = true
+			},
+		},
+		"should fail if producer batching is enabled and the batch max bytes is too large": {
+			setup: func(cfg *Config) {
+				cfg.Enabled = true
+				cfg.KafkaConfig.Address = "localhost"
+				cfg.KafkaConfig.Topic = "test"
+				cfg.KafkaConfig.ProducerBatchingEnabled = true
+				cfg.KafkaConfig.ProducerBatchMaxBytes = producerBatchMaxBytes + 1
+			},
+			expectedErr: ErrInvalidProducerBatchMaxBytes,
+		},
+		"should fail if producer batching is enabled and the batch linger is not positive": {
+			setup: func(cfg *Config) {
+				cfg.Enabled = true
+				cfg.KafkaConfig.Address = "localhost"
+				cfg.KafkaConfig.Topic = "test"
+				cfg.KafkaConfig.ProducerBatchingEnabled = true
+				cfg.KafkaConfig.ProducerBatchLinger = 0
+			},
+			expectedErr: ErrInvalidProducerBatchLinger,
+		},
+		"should pass if ingest storage is enabled and the reader consumes from a timestamp at startup": {
+			setup: func(cfg *Config) {
+				cfg.Enabled = true
+				cfg.KafkaConfig.Address = "localhost"
+				cfg.KafkaConfig.Topic = "test"
+				cfg.KafkaConfig.ConsumeFromPositionAtStartup = consumeFromTimestamp
+				cfg.KafkaConfig.ConsumeFromTimestampAtStartup = 1700000000000
+			},
+		},
+		"should fail if the consume from position at startup is invalid": {
+			setup: func(cfg *Config) {
+				cfg.Enabled = true
+				cfg.KafkaConfig.Address = "localhost"
+				cfg.KafkaConfig.Topic = "test"
+				cfg.KafkaConfig.ConsumeFromPositionAtStartup = "unknown"
+			},
+			expectedErr: ErrInvalidConsumeFromPosition,
+		},
+		"should fail if the reader consumes from a negative offset at startup": {
+			setup: func(cfg *Config) {
+				cfg.Enabled = true
+				cfg.KafkaConfig.Address = "localhost"
+				cfg.KafkaConfig.Topic = "test"
+				cfg.KafkaConfig.ConsumeFromPositionAtStartup = consumeFromOffset
+				cfg.KafkaConfig.ConsumeFromOffsetAtStartup = -1
+			},
+			expectedErr: ErrInvalidConsumeFromOffset,
+		},
+		"should fail if the reader consumes from a timestamp at startup but the timestamp is not configured": {
+			setup: func(cfg *Config) {
+				cfg.Enabled = true
+				cfg.KafkaConfig.Address = "localhost"
+				cfg.KafkaConfig.Topic = "test"
+				cfg.KafkaConfig.ConsumeFromPositionAtStartup = consumeFromTimestamp
+			},
+			expectedErr: ErrInvalidConsumeFromTimestamp,
+		},
+		"should fail if the readiness max consumer lag is negative": {
+			setup: func(cfg *Config) {
+				cfg.Enabled = true
+				cfg.KafkaConfig.Address = "localhost"
+				cfg.KafkaConfig.Topic = "test"
+				cfg.KafkaConfig.ReadinessMaxConsumerLag = -1
+			},
+			expectedErr: ErrInvalidReadinessMaxConsumerLag,
+		},
+		"should pass if ingest storage is enabled and the dead-letter spool directory is configured": {
+			setup: func(cfg *Config) {
+				cfg.Enabled = true
+				cfg.KafkaConfig.Address = "localhost"
+				cfg.KafkaConfig.Topic = "test"
+				cfg.DeadLetterConfig.SpoolDir = "/tmp/dead-letter"
+			},
+		},
+		"should fail if both the dead-letter topic and spool directory are configured": {
+			setup: func(cfg *Config) {
+				cfg.Enabled = true
+				cfg.KafkaConfig.Address = "localhost"
+				cfg.KafkaConfig.Topic = "test"
+				cfg.DeadLetterConfig.Topic = "test-dead-letter"
+				cfg.DeadLetterConfig.SpoolDir = "/tmp/dead-letter"
+			},
+			expectedErr: ErrDeadLetterTopicAndSpoolDir,
+		},
+		"should fail if dead-letter is enabled and max push retries is not positive": {
+			setup: func(cfg *Config) {
+				cfg.Enabled = true
+				cfg.KafkaConfig.Address = "localhost"
+				cfg.KafkaConfig.Topic = "test"
+				cfg.DeadLetterConfig.Topic = "test-dead-letter"
+				cfg.DeadLetterConfig.MaxPushRetries = 0
+			},
+			expectedErr: ErrInvalidDeadLetterMaxPushRetries,
+		},
 	}
 
 	for testName, testData := range tests {
@@ -0,0 +1,336 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/plugin/kprom"
+)
+
+const (
+	// DeadLetterReasonUnparsable is the reason of a record which can't be unmarshalled into a write request.
+	DeadLetterReasonUnparsable = "unparsable"
+
+	// DeadLetterReasonServerError is the reason of a record which kept failing with a server error when pushed.
+	DeadLetterReasonServerError = "server_error"
+
+	deadLetterSpoolFileExt = ".json"
+)
+
+// DeadLetterRecord is a record consumed from the ingest storage which couldn't be applied,
+// and has been copied to the dead-letter topic or spool directory so that it can be inspected
+// and re-injected later.
+type DeadLetterRecord struct {
+	TenantID  string `json:"tenant_id"`
+	Topic     string `json:"topic"`
+	Partition int32  `json:"partition"`
+	Offset    int64  `json:"offset"`
+
+	// BatchIndex is the index of the write request within the batched record at Offset,
+	// 0 if the record is not batched.
+	BatchIndex int `json:"batch_index"`
+
+	// Timestamp is the time the record has been copied to the dead-letter queue.
+	Timestamp time.Time `json:"timestamp"`
+	Reason    string    `json:"reason"`
+	Error     string    `json:"error"`
+
+	// Content is the original content of the record, which is a serialized write request
+	// unless the record is unparsable.
+	Content []byte `json:"content"`
+}
+
+// DecodeDeadLetterRecord decodes a record copied to the dead-letter topic or spool directory.
+func DecodeDeadLetterRecord(data []byte) (DeadLetterRecord, error) {
+	var rec DeadLetterRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return DeadLetterRecord{}, errors.Wrap(err, "decoding dead-letter record")
+	}
+	return rec, nil
+}
+
+// deadLetterSink stores dead-letter records.
+type deadLetterSink interface {
+	write(ctx context.Context, rec DeadLetterRecord, data []byte) error
+	close()
+}
+
+// deadLetterQueue copies the records which can't be applied to the configured sink.
+type deadLetterQueue struct {
+	cfg         DeadLetterConfig
+	sink        deadLetterSink
+	topic       string
+	partitionID int32
+	logger      log.Logger
+
+	recordsTotal  *prometheus.CounterVec
+	failuresTotal prometheus.Counter
+}
+
+// newDeadLetterQueue returns the dead-letter queue for the records consumed from the input partition,
+// or nil if the dead-letter handling is disabled.
+func newDeadLetterQueue(cfg DeadLetterConfig, kafkaCfg KafkaConfig, partitionID int32, logger log.Logger, reg prometheus.Registerer) (*deadLetterQueue, error) {
+	var (
+		sink deadLetterSink
+		err  error
+	)
+
+	switch {
+	case cfg.Topic != "":
+		sink, err = newDeadLetterTopicSink(cfg.Topic, kafkaCfg, partitionID, logger, reg)
+	case cfg.SpoolDir != "":
+		sink, err = newDeadLetterSpoolSink(cfg.SpoolDir)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &deadLetterQueue{
+		cfg:         cfg,
+		sink:        sink,
+		topic:       kafkaCfg.Topic,
+		partitionID: partitionID,
+		logger:      logger,
+		recordsTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_ingest_storage_reader_records_dead_lettered_total",
+			Help: "Number of records which couldn't be applied and have been copied to the dead-letter queue.",
+		}, []string{"reason"}),
+		failuresTotal: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "cortex_ingest_storage_reader_dead_letter_failures_total",
+			Help: "Number of failures while copying a record to the dead-letter queue.",
+		}),
+	}, nil
+}
+
+// add copies the input record to the dead-letter queue.
+func (q *deadLetterQueue) add(ctx context.Context, r record, reason string, cause error) error {
+	rec := DeadLetterRecord{
+		TenantID:   r.tenantID,
+		Topic:      q.topic,
+		Partition:  q.partitionID,
+		Offset:     r.offset,
+		BatchIndex: r.batchIndex,
+		Timestamp:  time.Now().UTC(),
+		Reason:     reason,
+		Error:      cause.Error(),
+		Content:    r.content,
+	}
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		q.failuresTotal.Inc()
+		return errors.Wrap(err, "encoding dead-letter record")
+	}
+
+	if err := q.sink.write(ctx, rec, data); err != nil {
+		q.failuresTotal.Inc()
+		return err
+	}
+
+	q.recordsTotal.WithLabelValues(reason).Inc()
+	level.Warn(q.logger).Log("msg", "copied record which couldn't be applied to the dead-letter queue", "user", r.tenantID, "offset", r.offset, "batch_index", r.batchIndex, "reason", reason, "err", cause)
+	return nil
+}
+
+func (q *deadLetterQueue) close() {
+	q.sink.close()
+}
+
+// deadLetterTopicSink writes dead-letter records to a Kafka topic.
+type deadLetterTopicSink struct {
+	client *kgo.Client
+}
+
+func newDeadLetterTopicSink(topic string, kafkaCfg KafkaConfig, partitionID int32, logger log.Logger, reg prometheus.Registerer) (*deadLetterTopicSink, error) {
+	// Do not export the client ID, because we use it to specify options to the backend.
+	metrics := kprom.NewMetrics("cortex_ingest_storage_dead_letter_writer",
+		kprom.Registerer(prometheus.WrapRegistererWith(prometheus.Labels{"partition": strconv.Itoa(int(partitionID))}, reg)),
+		kprom.FetchAndProduceDetail(kprom.Batches, kprom.Records, kprom.CompressedBytes, kprom.UncompressedBytes))
+
+	opts := append(
+		commonKafkaClientOptions(kafkaCfg, metrics, logger),
+		kgo.RequiredAcks(kgo.AllISRAcks()),
+		kgo.DefaultProduceTopic(topic),
+		kgo.RecordDeliveryTimeout(kafkaCfg.WriteTimeout),
+		kgo.ProduceRequestTimeout(kafkaCfg.WriteTimeout),
+		kgo.RequestTimeoutOverhead(writerRequestTimeoutOverhead),
+	)
+	client, err := kgo.NewClient(opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating dead-letter kafka client")
+	}
+
+	return &deadLetterTopicSink{client: client}, nil
+}
+
+func (s *deadLetterTopicSink) write(ctx context.Context, rec DeadLetterRecord, data []byte) error {
+	err := s.client.ProduceSync(ctx, &kgo.Record{Key: []byte(rec.TenantID), Value: data}).FirstErr()
+	return errors.Wrap(err, "writing record to the dead-letter topic")
+}
+
+func (s *deadLetterTopicSink) close() {
+	s.client.Close()
+}
+
+// deadLetterSpoolSink writes dead-letter records to a local directory, one file per record.
+type deadLetterSpoolSink struct {
+	dir string
+}
+
+func newDeadLetterSpoolSink(dir string) (*deadLetterSpoolSink, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, errors.Wrap(err, "creating dead-letter spool directory")
+	}
+	return &deadLetterSpoolSink{dir: dir}, nil
+}
+
+func (s *deadLetterSpoolSink) write(_ context.Context, rec DeadLetterRecord, data []byte) error {
+	// The offset and batch index are zero padded so that the files are sorted by offset and batch index.
+	name := fmt.Sprintf("%s-%d-%020d-%06d%s", rec.Topic, rec.Partition, rec.Offset, rec.BatchIndex, deadLetterSpoolFileExt)
+
+	// Write to a temporary file first, so that a partially written record is never read.
+	tmp := filepath.Join(s.dir, name+".tmp")
+	if err := os.WriteFile(tmp, data, 0o640); err != nil {
+		return errors.Wrap(err, "writing record to the dead-letter spool directory")
+	}
+	if err := os.Rename(tmp, filepath.Join(s.dir, name)); err != nil {
+		return errors.Wrap(err, "writing record to the dead-letter spool directory")
+	}
+	return nil
+}
+
+func (s *deadLetterSpoolSink) close() {}
+
+// ReadDeadLetterSpool calls fn for each record in the dead-letter spool directory, sorted by file name.
+func ReadDeadLetterSpool(dir string, fn func(path string, rec DeadLetterRecord) error) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return errors.Wrap(err, "reading dead-letter spool directory")
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && strings.HasSuffix(entry.Name(), deadLetterSpoolFileExt) {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		rec, err := DecodeDeadLetterRecord(data)
+		if err != nil {
+			return errors.Wrapf(err, "file %s", path)
+		}
+
+		if err := fn(path, rec); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReadDeadLetterTopic calls fn for each record in the dead-letter topic, from the oldest record
+// to the last record produced when the function has been called.
+func ReadDeadLetterTopic(ctx context.Context, address, topic string, fn func(rec DeadLetterRecord) error) error {
+	adminClient, err := kgo.NewClient(kgo.SeedBrokers(address))
+	if err != nil {
+		return errors.Wrap(err, "creating kafka client")
+	}
+	adm := kadm.NewClient(adminClient)
+	defer adm.Close()
+
+	startOffsets, err := adm.ListStartOffsets(ctx, topic)
+	if err != nil {
+		return errors.Wrap(err, "listing dead-letter topic start offsets")
+	}
+	endOffsets, err := adm.ListEndOffsets(ctx, topic)
+	if err != nil {
+		return errors.Wrap(err, "listing dead-letter topic end offsets")
+	}
+
+	// Only consume the partitions which have some records, up until the current end offset.
+	consumeFrom := map[int32]kgo.Offset{}
+	consumeUntil := map[int32]int64{}
+	endOffsets.Each(func(end kadm.ListedOffset) {
+		start, ok := startOffsets.Lookup(topic, end.Partition)
+		if !ok || end.Err != nil || start.Err != nil || start.Offset >= end.Offset {
+			return
+		}
+		consumeFrom[end.Partition] = kgo.NewOffset().At(start.Offset)
+		consumeUntil[end.Partition] = end.Offset
+	})
+	if len(consumeFrom) == 0 {
+		return nil
+	}
+
+	client, err := kgo.NewClient(
+		kgo.SeedBrokers(address),
+		kgo.ConsumePartitions(map[string]map[int32]kgo.Offset{topic: consumeFrom}),
+	)
+	if err != nil {
+		return errors.Wrap(err, "creating kafka client")
+	}
+	defer client.Close()
+
+	for len(consumeUntil) > 0 {
+		fetches := client.PollFetches(ctx)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if errs := fetches.Errors(); len(errs) > 0 {
+			return errors.Wrapf(errs[0].Err, "fetching from dead-letter topic partition %d", errs[0].Partition)
+		}
+
+		var fnErr error
+		fetches.EachRecord(func(r *kgo.Record) {
+			until, ok := consumeUntil[r.Partition]
+			if fnErr != nil || !ok || r.Offset >= until {
+				return
+			}
+
+			var rec DeadLetterRecord
+			if rec, fnErr = DecodeDeadLetterRecord(r.Value); fnErr != nil {
+				fnErr = errors.Wrapf(fnErr, "partition %d offset %d", r.Partition, r.Offset)
+				return
+			}
+			if fnErr = fn(rec); fnErr != nil {
+				return
+			}
+
+			if r.Offset >= until-1 {
+				delete(consumeUntil, r.Partition)
+			}
+		})
+		if fnErr != nil {
+			return fnErr
+		}
+	}
+
+	return nil
+}
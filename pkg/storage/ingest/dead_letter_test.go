@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package ingest
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	promtest "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/util/testkafka"
+)
+
+func TestNewDeadLetterQueue_Disabled(t *testing.T) {
+	deadLetter, err := newDeadLetterQueue(DeadLetterConfig{}, KafkaConfig{Topic: "test"}, 1, log.NewNopLogger(), prometheus.NewPedanticRegistry())
+	require.NoError(t, err)
+	assert.Nil(t, deadLetter)
+}
+
+func TestDeadLetterQueue_Spool(t *testing.T) {
+	spoolDir := filepath.Join(t.TempDir(), "spool")
+	reg := prometheus.NewPedanticRegistry()
+
+	deadLetter, err := newDeadLetterQueue(DeadLetterConfig{SpoolDir: spoolDir, MaxPushRetries: 1}, KafkaConfig{Topic: "test"}, 2, log.NewNopLogger(), reg)
+	require.NoError(t, err)
+	t.Cleanup(deadLetter.close)
+
+	ctx := context.Background()
+	require.NoError(t, deadLetter.add(ctx, record{tenantID: "user-1", content: []byte("second"), offset: 200}, DeadLetterReasonServerError, errors.New("server error")))
+	require.NoError(t, deadLetter.add(ctx, record{tenantID: "user-2", content: []byte("first"), offset: 30}, DeadLetterReasonUnparsable, errors.New("unparsable")))
+
+	// Files which are not dead-letter records should be ignored.
+	require.NoError(t, os.WriteFile(filepath.Join(spoolDir, "other.txt"), []byte("other"), 0o640))
+
+	var (
+		paths   []string
+		records []DeadLetterRecord
+	)
+	require.NoError(t, ReadDeadLetterSpool(spoolDir, func(path string, rec DeadLetterRecord) error {
+		paths = append(paths, path)
+		records = append(records, rec)
+		return nil
+	}))
+
+	// Records should be sorted by offset.
+	require.Len(t, records, 2)
+	assert.Equal(t, filepath.Join(spoolDir, "test-2-00000000000000000030-000000.json"), paths[0])
+	assert.Equal(t, DeadLetterRecord{
+		TenantID:  "user-2",
+		Topic:     "test",
+		Partition: 2,
+		Offset:    30,
+		Timestamp: records[0].Timestamp,
+		Reason:    DeadLetterReasonUnparsable,
+		Error:     "unparsable",
+		Content:   []byte("first"),
+	}, records[0])
+	assert.Equal(t, int64(200), records[1].Offset)
+	assert.Equal(t, "server error", records[1].Error)
+
+	assert.NoError(t, promtest.GatherAndCompare(reg, strings.NewReader(`
+		# HELP cortex_ingest_storage_reader_records_dead_lettered_total Number of records which couldn't be applied and have been copied to the dead-letter queue.
+		# TYPE cortex_ingest_storage_reader_records_dead_lettered_total counter
+		cortex_ingest_storage_reader_records_dead_lettered_total{reason="server_error"} 1
+		cortex_ingest_storage_reader_records_dead_lettered_total{reason="unparsable"} 1
+	`), "cortex_ingest_storage_reader_records_dead_lettered_total"))
+}
+
+func TestDeadLetterQueue_Topic(t *testing.T) {
+	const (
+		topicName           = "test"
+		deadLetterTopicName = "test-dead-letter"
+	)
+
+	ctx := context.Background()
+	_, clusterAddr := testkafka.CreateCluster(t, 2, deadLetterTopicName)
+
+	deadLetter, err := newDeadLetterQueue(DeadLetterConfig{Topic: deadLetterTopicName, MaxPushRetries: 1}, createTestKafkaConfig(clusterAddr, topicName), 1, log.NewNopLogger(), prometheus.NewPedanticRegistry())
+	require.NoError(t, err)
+	t.Cleanup(deadLetter.close)
+
+	require.NoError(t, deadLetter.add(ctx, record{tenantID: "user-1", content: []byte("first"), offset: 10}, DeadLetterReasonServerError, errors.New("server error")))
+	require.NoError(t, deadLetter.add(ctx, record{tenantID: "user-2", content: []byte("second"), offset: 11}, DeadLetterReasonUnparsable, errors.New("unparsable")))
+
+	var records []DeadLetterRecord
+	require.NoError(t, ReadDeadLetterTopic(ctx, clusterAddr, deadLetterTopicName, func(rec DeadLetterRecord) error {
+		records = append(records, rec)
+		return nil
+	}))
+
+	require.Len(t, records, 2)
+	contents := map[string]DeadLetterRecord{}
+	for _, rec := range records {
+		assert.Equal(t, topicName, rec.Topic)
+		assert.Equal(t, int32(1), rec.Partition)
+		contents[string(rec.Content)] = rec
+	}
+	assert.Equal(t, "user-1", contents["first"].TenantID)
+	assert.Equal(t, int64(10), contents["first"].Offset)
+	assert.Equal(t, DeadLetterReasonServerError, contents["first"].Reason)
+	assert.Equal(t, "user-2", contents["second"].TenantID)
+	assert.Equal(t, int64(11), contents["second"].Offset)
+	assert.Equal(t, DeadLetterReasonUnparsable, contents["second"].Reason)
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/backoff"
 	"github.com/grafana/dskit/cancellation"
 	"github.com/grafana/dskit/grpcutil"
 	"github.com/grafana/dskit/user"
@@ -26,6 +27,10 @@ type Pusher interface {
 type pusherConsumer struct {
 	p Pusher
 
+	// deadLetter is nil if the dead-letter handling is disabled.
+	deadLetter       *deadLetterQueue
+	pushRetryBackoff backoff.Config
+
 	processingTimeSeconds prometheus.Observer
 	clientErrRequests     prometheus.Counter
 	serverErrRequests     prometheus.Counter
@@ -37,17 +42,21 @@ type parsedRecord struct {
 	*mimirpb.WriteRequest
 	tenantID string
 	err      error
+
+	// source is the record the WriteRequest has been parsed from.
+	source record
 }
 
-func newPusherConsumer(p Pusher, reg prometheus.Registerer, l log.Logger) *pusherConsumer {
+func newPusherConsumer(p Pusher, deadLetter *deadLetterQueue, reg prometheus.Registerer, l log.Logger) *pusherConsumer {
 	errRequestsCounter := promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
 		Name: "cortex_ingest_storage_reader_records_failed_total",
 		Help: "Number of records (write requests) which caused errors while processing. Client errors are errors such as tenant limits and samples out of bounds. Server errors indicate internal recoverable errors.",
 	}, []string{"cause"})
 
-	return &pusherConsumer{
-		p: p,
-		l: l,
+	c := &pusherConsumer{
+		p:          p,
+		l:          l,
+		deadLetter: deadLetter,
 		processingTimeSeconds: promauto.With(reg).NewSummary(prometheus.SummaryOpts{
 			Name:       "cortex_ingest_storage_reader_processing_time_seconds",
 			Help:       "Time taken to process a single record (write request).",
@@ -62,6 +71,17 @@ func newPusherConsumer(p Pusher, reg prometheus.Registerer, l log.Logger) *pushe
 			Help: "Number of attempted records (write requests).",
 		}),
 	}
+
+	if deadLetter != nil {
+		c.pushRetryBackoff = backoff.Config{
+			MinBackoff: 250 * time.Millisecond,
+			MaxBackoff: 2 * time.Second,
+			// The first attempt is counted as a retry too.
+			MaxRetries: deadLetter.cfg.MaxPushRetries + 1,
+		}
+	}
+
+	return c
 }
 
 func (c pusherConsumer) consume(ctx context.Context, records []record) error {
@@ -83,20 +103,28 @@ func (c pusherConsumer) pushRequests(ctx context.Context, reqC <-chan parsedReco
 	for wr := range reqC {
 		recordIdx++
 		if wr.err != nil {
-			level.Error(c.l).Log("msg", "failed to parse write request; skipping", "err", wr.err)
+			if c.deadLetter == nil {
+				level.Error(c.l).Log("msg", "failed to parse write request; skipping", "err", wr.err)
+				continue
+			}
+			if err := c.deadLetter.add(ctx, wr.source, DeadLetterReasonUnparsable, wr.err); err != nil {
+				return fmt.Errorf("copying unparsable record at index %d for tenant %s to the dead-letter queue: %w", recordIdx, wr.tenantID, err)
+			}
 			continue
 		}
-		processingStart := time.Now()
 
-		ctx := user.InjectOrgID(ctx, wr.tenantID)
-		_, err := c.p.Push(ctx, wr.WriteRequest)
-
-		c.processingTimeSeconds.Observe(time.Since(processingStart).Seconds())
-		c.totalRequests.Inc()
+		err := c.pushRequest(user.InjectOrgID(ctx, wr.tenantID), wr)
 		if err != nil {
 			if !isClientIngesterError(err) {
 				c.serverErrRequests.Inc()
-				return fmt.Errorf("consuming record at index %d for tenant %s: %w", recordIdx, wr.tenantID, err)
+				// Do not copy the record to the dead-letter queue if pushing has been interrupted.
+				if c.deadLetter == nil || ctx.Err() != nil {
+					return fmt.Errorf("consuming record at index %d for tenant %s: %w", recordIdx, wr.tenantID, err)
+				}
+				if err := c.deadLetter.add(ctx, wr.source, DeadLetterReasonServerError, err); err != nil {
+					return fmt.Errorf("copying record at index %d for tenant %s to the dead-letter queue: %w", recordIdx, wr.tenantID, err)
+				}
+				continue
 			}
 			c.clientErrRequests.Inc()
 			level.Warn(c.l).Log("msg", "detected a client error while ingesting write request (the request may have been partially ingested)", "err", err, "user", wr.tenantID)
@@ -105,6 +133,50 @@ func (c pusherConsumer) pushRequests(ctx context.Context, reqC <-chan parsedReco
 	return nil
 }
 
+// pushRequest pushes the parsed record. When the dead-letter handling is enabled, pushing a record failing
+// with a server error is retried up to the configured number of times.
+func (c pusherConsumer) pushRequest(ctx context.Context, wr parsedRecord) error {
+	if c.deadLetter == nil {
+		return c.push(ctx, wr.WriteRequest)
+	}
+
+	var (
+		req  = wr.WriteRequest
+		err  error
+		boff = backoff.New(ctx, c.pushRetryBackoff)
+	)
+
+	for boff.Ongoing() {
+		// The Pusher reuses the WriteRequest slices once done, so the record is parsed again before retrying.
+		if req == nil {
+			req = &mimirpb.WriteRequest{}
+			if err := req.Unmarshal(wr.source.content); err != nil {
+				return errors.Wrap(err, "parsing ingest consumer write request")
+			}
+		}
+
+		err = c.push(ctx, req)
+		if err == nil || isClientIngesterError(err) {
+			return err
+		}
+		req = nil
+
+		level.Warn(c.l).Log("msg", "detected a server error while ingesting write request; will retry", "err", err, "user", wr.tenantID, "num_retries", boff.NumRetries())
+		boff.Wait()
+	}
+
+	return err
+}
+
+func (c pusherConsumer) push(ctx context.Context, req *mimirpb.WriteRequest) error {
+	processingStart := time.Now()
+	_, err := c.p.Push(ctx, req)
+
+	c.processingTimeSeconds.Observe(time.Since(processingStart).Seconds())
+	c.totalRequests.Inc()
+	return err
+}
+
 func isClientIngesterError(err error) bool {
 	stat, ok := grpcutil.ErrorToStatus(err)
 	if !ok {
@@ -127,20 +199,68 @@ func (c pusherConsumer) unmarshalRequests(ctx context.Context, records []record,
 	done := ctx.Done()
 
 	for _, record := range records {
-		pRecord := parsedRecord{
-			tenantID:     record.tenantID,
-			WriteRequest: &mimirpb.WriteRequest{},
-		}
-		// We don't free the WriteRequest slices because they are being freed by the Pusher.
-		err := pRecord.WriteRequest.Unmarshal(record.content)
-		if err != nil {
-			err = errors.Wrap(err, "parsing ingest consumer write request")
-			pRecord.err = err
+		var pRecords []parsedRecord
+		switch record.version {
+		case recordVersionSingle:
+			pRecords = []parsedRecord{parseWriteRequest(record)}
+		case recordVersionBatch:
+			pRecords = parseBatchedWriteRequests(record)
+		default:
+			pRecords = []parsedRecord{{
+				tenantID: record.tenantID,
+				err:      fmt.Errorf("parsing ingest consumer write request: unsupported record version %d", record.version),
+				source:   record,
+			}}
 		}
-		select {
-		case <-done:
-			return
-		case recC <- pRecord:
+
+		for _, pRecord := range pRecords {
+			select {
+			case <-done:
+				return
+			case recC <- pRecord:
+			}
 		}
 	}
 }
+
+func parseWriteRequest(record record) parsedRecord {
+	pRecord := parsedRecord{
+		tenantID:     record.tenantID,
+		WriteRequest: &mimirpb.WriteRequest{},
+		source:       record,
+	}
+	// We don't free the WriteRequest slices because they are being freed by the Pusher.
+	err := pRecord.WriteRequest.Unmarshal(record.content)
+	if err != nil {
+		err = errors.Wrap(err, "parsing ingest consumer write request")
+		pRecord.err = err
+	}
+	return pRecord
+}
+
+// parseBatchedWriteRequests parses each write request of a batched record. The source of each
+// parsed record is the single write request, so that it can be handled on its own.
+func parseBatchedWriteRequests(batch record) []parsedRecord {
+	var pRecords []parsedRecord
+
+	err := decodeRecordBatch(batch.content, func(tenantID string, data []byte) {
+		pRecords = append(pRecords, parseWriteRequest(record{
+			tenantID:   tenantID,
+			content:    data,
+			offset:     batch.offset,
+			version:    recordVersionSingle,
+			batchIndex: len(pRecords),
+		}))
+	})
+	if err != nil {
+		// The requests decoded before the error are dropped as well, so that the whole
+		// batched record is handled as unparsable.
+		pRecords = []parsedRecord{{
+			tenantID: batch.tenantID,
+			err:      errors.Wrap(err, "parsing ingest consumer batched record"),
+			source:   batch,
+		}}
+	}
+
+	return pRecords
+}
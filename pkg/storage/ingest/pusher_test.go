@@ -5,6 +5,7 @@ package ingest
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/go-kit/log"
 	"github.com/gogo/status"
@@ -177,7 +178,7 @@ func TestPusherConsumer(t *testing.T) {
 
 				return tc.responses[receivedReqs].WriteResponse, tc.responses[receivedReqs].err
 			})
-			c := newPusherConsumer(pusher, prometheus.NewPedanticRegistry(), log.NewNopLogger())
+			c := newPusherConsumer(pusher, nil, prometheus.NewPedanticRegistry(), log.NewNopLogger())
 			err := c.consume(context.Background(), tc.records)
 			if tc.expErr == "" {
 				assert.NoError(t, err)
@@ -197,3 +198,171 @@ func ingesterError(cause mimirpb.ErrorCause, statusCode codes.Code, message stri
 	}
 	return statWithDetails.Err()
 }
+
+func TestPusherConsumer_BatchedRecords(t *testing.T) {
+	writeReqs := []*mimirpb.WriteRequest{
+		{Timeseries: []mimirpb.PreallocTimeseries{mockPreallocTimeseries("series_1")}},
+		{Timeseries: []mimirpb.PreallocTimeseries{mockPreallocTimeseries("series_2")}},
+		{Timeseries: []mimirpb.PreallocTimeseries{mockPreallocTimeseries("series_3")}},
+	}
+	wrBytes := make([][]byte, len(writeReqs))
+	for i, wr := range writeReqs {
+		var err error
+		wrBytes[i], err = wr.Marshal()
+		require.NoError(t, err)
+	}
+
+	batch := recordBatchBuilder{}
+	batch.add("user-1", wrBytes[0])
+	batch.add("user-2", wrBytes[1])
+
+	records := []record{
+		{content: batch.buf, offset: 1, version: recordVersionBatch},
+		{content: wrBytes[2], tenantID: "user-3", offset: 2},
+		{content: batch.buf[:len(batch.buf)-1], offset: 3, version: recordVersionBatch},
+		{content: wrBytes[2], tenantID: "user-3", offset: 4, version: 2},
+	}
+
+	var pushed []string
+	pusher := pusherFunc(func(ctx context.Context, request *mimirpb.WriteRequest) (*mimirpb.WriteResponse, error) {
+		tenantID, err := tenant.TenantID(ctx)
+		require.NoError(t, err)
+		pushed = append(pushed, tenantID+"/"+request.Timeseries[0].Labels[0].Value)
+		return &mimirpb.WriteResponse{}, nil
+	})
+
+	spoolDir := t.TempDir()
+	reg := prometheus.NewPedanticRegistry()
+	deadLetter, err := newDeadLetterQueue(DeadLetterConfig{SpoolDir: spoolDir, MaxPushRetries: 1}, KafkaConfig{Topic: "test"}, 1, log.NewNopLogger(), reg)
+	require.NoError(t, err)
+
+	c := newPusherConsumer(pusher, deadLetter, reg, log.NewNopLogger())
+	require.NoError(t, c.consume(context.Background(), records))
+	assert.Equal(t, []string{"user-1/series_1", "user-2/series_2", "user-3/series_3"}, pushed)
+
+	// The truncated batch and the record with an unsupported version should be handled as unparsable.
+	var deadLettered []string
+	require.NoError(t, ReadDeadLetterSpool(spoolDir, func(_ string, rec DeadLetterRecord) error {
+		assert.Equal(t, DeadLetterReasonUnparsable, rec.Reason)
+		deadLettered = append(deadLettered, rec.Error)
+		return nil
+	}))
+	require.Len(t, deadLettered, 2)
+	assert.Contains(t, deadLettered[0], "parsing ingest consumer batched record")
+	assert.Contains(t, deadLettered[1], "unsupported record version 2")
+}
+
+func TestPusherConsumer_DeadLetter(t *testing.T) {
+	const tenantID = "t1"
+
+	writeReqs := []*mimirpb.WriteRequest{
+		{Timeseries: []mimirpb.PreallocTimeseries{mockPreallocTimeseries("series_1")}},
+		{Timeseries: []mimirpb.PreallocTimeseries{mockPreallocTimeseries("series_2")}},
+	}
+	wrBytes := make([][]byte, len(writeReqs))
+	for i, wr := range writeReqs {
+		var err error
+		wrBytes[i], err = wr.Marshal()
+		require.NoError(t, err)
+	}
+
+	batch := recordBatchBuilder{}
+	batch.add(tenantID, wrBytes[0])
+	batch.add(tenantID, wrBytes[1])
+
+	serverErr := ingesterError(mimirpb.TSDB_UNAVAILABLE, codes.Unavailable, "ingester internal error")
+	clientErr := ingesterError(mimirpb.BAD_DATA, codes.InvalidArgument, "ingester test error")
+
+	testCases := map[string]struct {
+		records            []record
+		responses          []error
+		expectedPushes     []string
+		expectedDeadLetter []DeadLetterRecord
+	}{
+		"unparsable record": {
+			records: []record{
+				{content: wrBytes[0], tenantID: tenantID, offset: 10},
+				{content: []byte{0}, tenantID: tenantID, offset: 11},
+				{content: wrBytes[1], tenantID: tenantID, offset: 12},
+			},
+			responses:      []error{nil, nil},
+			expectedPushes: []string{"series_1", "series_2"},
+			expectedDeadLetter: []DeadLetterRecord{
+				{TenantID: tenantID, Topic: "test", Partition: 1, Offset: 11, Reason: DeadLetterReasonUnparsable, Content: []byte{0}},
+			},
+		},
+		"server error recovered by retrying": {
+			records: []record{
+				{content: wrBytes[0], tenantID: tenantID, offset: 10},
+				{content: wrBytes[1], tenantID: tenantID, offset: 11},
+			},
+			responses:      []error{serverErr, serverErr, nil, nil},
+			expectedPushes: []string{"series_1", "series_1", "series_1", "series_2"},
+		},
+		"persistent server error": {
+			records: []record{
+				{content: wrBytes[0], tenantID: tenantID, offset: 10},
+				{content: wrBytes[1], tenantID: tenantID, offset: 11},
+			},
+			responses:      []error{serverErr, serverErr, serverErr, nil},
+			expectedPushes: []string{"series_1", "series_1", "series_1", "series_2"},
+			expectedDeadLetter: []DeadLetterRecord{
+				{TenantID: tenantID, Topic: "test", Partition: 1, Offset: 10, Reason: DeadLetterReasonServerError, Content: wrBytes[0]},
+			},
+		},
+		"persistent server error on a batched record": {
+			records: []record{
+				{content: batch.buf, offset: 10, version: recordVersionBatch},
+			},
+			responses:      []error{serverErr, serverErr, serverErr, serverErr, serverErr, serverErr},
+			expectedPushes: []string{"series_1", "series_1", "series_1", "series_2", "series_2", "series_2"},
+			expectedDeadLetter: []DeadLetterRecord{
+				{TenantID: tenantID, Topic: "test", Partition: 1, Offset: 10, BatchIndex: 0, Reason: DeadLetterReasonServerError, Content: wrBytes[0]},
+				{TenantID: tenantID, Topic: "test", Partition: 1, Offset: 10, BatchIndex: 1, Reason: DeadLetterReasonServerError, Content: wrBytes[1]},
+			},
+		},
+		"client error": {
+			records: []record{
+				{content: wrBytes[0], tenantID: tenantID, offset: 10},
+				{content: wrBytes[1], tenantID: tenantID, offset: 11},
+			},
+			responses:      []error{clientErr, nil},
+			expectedPushes: []string{"series_1", "series_2"},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			var pushes []string
+			pusher := pusherFunc(func(_ context.Context, request *mimirpb.WriteRequest) (*mimirpb.WriteResponse, error) {
+				require.Less(t, len(pushes), len(tc.responses), "received more requests than expected")
+				pushes = append(pushes, request.Timeseries[0].Labels[0].Value)
+				return &mimirpb.WriteResponse{}, tc.responses[len(pushes)-1]
+			})
+
+			spoolDir := t.TempDir()
+			reg := prometheus.NewPedanticRegistry()
+			deadLetter, err := newDeadLetterQueue(DeadLetterConfig{SpoolDir: spoolDir, MaxPushRetries: 2}, KafkaConfig{Topic: "test"}, 1, log.NewNopLogger(), reg)
+			require.NoError(t, err)
+
+			c := newPusherConsumer(pusher, deadLetter, reg, log.NewNopLogger())
+			c.pushRetryBackoff.MinBackoff = time.Millisecond
+			c.pushRetryBackoff.MaxBackoff = time.Millisecond
+
+			require.NoError(t, c.consume(context.Background(), tc.records))
+			assert.Equal(t, tc.expectedPushes, pushes)
+
+			var actualDeadLetter []DeadLetterRecord
+			require.NoError(t, ReadDeadLetterSpool(spoolDir, func(_ string, rec DeadLetterRecord) error {
+				assert.NotEmpty(t, rec.Error)
+				assert.False(t, rec.Timestamp.IsZero())
+				rec.Error = ""
+				rec.Timestamp = time.Time{}
+				actualDeadLetter = append(actualDeadLetter, rec)
+				return nil
+			}))
+			assert.Equal(t, tc.expectedDeadLetter, actualDeadLetter)
+		})
+	}
+}
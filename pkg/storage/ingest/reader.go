@@ -27,6 +27,11 @@ import (
 type record struct {
 	tenantID string
 	content  []byte
+	offset   int64
+	version  int
+
+	// batchIndex is the index of the write request within the batched record it has been decoded from.
+	batchIndex int
 }
 
 type recordConsumer interface {
@@ -47,6 +52,9 @@ type PartitionReader struct {
 	consumer recordConsumer
 	metrics  readerMetrics
 
+	// deadLetter is nil if the dead-letter handling is disabled.
+	deadLetter *deadLetterQueue
+
 	committer      *partitionCommitter
 	commitInterval time.Duration
 
@@ -55,13 +63,26 @@ type PartitionReader struct {
 	consumedOffsetWatcher *partitionOffsetWatcher
 	offsetReader          *partitionOffsetReader
 
+	// caughtUp is set once the consumer lag has been below the readiness threshold.
+	caughtUp *atomic.Bool
+
 	logger log.Logger
 	reg    prometheus.Registerer
 }
 
-func NewPartitionReaderForPusher(kafkaCfg KafkaConfig, partitionID int32, consumerGroup string, pusher Pusher, logger log.Logger, reg prometheus.Registerer) (*PartitionReader, error) {
-	consumer := newPusherConsumer(pusher, reg, logger)
-	return newPartitionReader(kafkaCfg, partitionID, consumerGroup, consumer, logger, reg)
+func NewPartitionReaderForPusher(kafkaCfg KafkaConfig, deadLetterCfg DeadLetterConfig, partitionID int32, consumerGroup string, pusher Pusher, logger log.Logger, reg prometheus.Registerer) (*PartitionReader, error) {
+	deadLetter, err := newDeadLetterQueue(deadLetterCfg, kafkaCfg, partitionID, logger, reg)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating dead-letter queue")
+	}
+
+	consumer := newPusherConsumer(pusher, deadLetter, reg, logger)
+	r, err := newPartitionReader(kafkaCfg, partitionID, consumerGroup, consumer, logger, reg)
+	if err != nil {
+		return nil, err
+	}
+	r.deadLetter = deadLetter
+	return r, nil
 }
 
 func newPartitionReader(kafkaCfg KafkaConfig, partitionID int32, consumerGroup string, consumer recordConsumer, logger log.Logger, reg prometheus.Registerer) (*PartitionReader, error) {
@@ -73,6 +94,7 @@ func newPartitionReader(kafkaCfg KafkaConfig, partitionID int32, consumerGroup s
 		metrics:               newReaderMetrics(partitionID, reg),
 		commitInterval:        time.Second,
 		consumedOffsetWatcher: newPartitionOffsetWatcher(),
+		caughtUp:              atomic.NewBool(false),
 		logger:                log.With(logger, "partition", partitionID),
 		reg:                   reg,
 	}
@@ -82,12 +104,12 @@ func newPartitionReader(kafkaCfg KafkaConfig, partitionID int32, consumerGroup s
 }
 
 func (r *PartitionReader) start(ctx context.Context) error {
-	startFromOffset, err := r.fetchLastCommittedOffsetWithRetries(ctx)
+	startFromOffset, err := r.fetchStartOffset(ctx)
 	if err != nil {
 		return err
 	}
 	r.consumedOffsetWatcher.Notify(startFromOffset - 1)
-	level.Info(r.logger).Log("msg", "resuming consumption from offset", "offset", startFromOffset)
+	level.Info(r.logger).Log("msg", "resuming consumption from offset", "offset", startFromOffset, "position", r.kafkaCfg.ConsumeFromPositionAtStartup)
 
 	r.client, err = r.newKafkaReader(kgo.NewOffset().At(startFromOffset))
 	if err != nil {
@@ -116,6 +138,9 @@ func (r *PartitionReader) stop(error) error {
 		return errors.Wrap(err, "stopping service manager")
 	}
 	r.client.Close()
+	if r.deadLetter != nil {
+		r.deadLetter.close()
+	}
 	return nil
 }
 
@@ -204,6 +229,8 @@ func (r *PartitionReader) consumeFetches(ctx context.Context, fetches kgo.Fetche
 		records = append(records, record{
 			content:  r.Value,
 			tenantID: string(r.Key),
+			offset:   r.Offset,
+			version:  parseRecordVersion(r),
 		})
 	})
 
@@ -289,7 +316,19 @@ func (r *PartitionReader) newKafkaReader(at kgo.Offset) (*kgo.Client, error) {
 	return client, nil
 }
 
-func (r *PartitionReader) fetchLastCommittedOffsetWithRetries(ctx context.Context) (offset int64, err error) {
+// fetchStartOffset returns the offset from which the consumption should start, based on the configured position.
+func (r *PartitionReader) fetchStartOffset(ctx context.Context) (int64, error) {
+	switch r.kafkaCfg.ConsumeFromPositionAtStartup {
+	case consumeFromOffset:
+		return r.kafkaCfg.ConsumeFromOffsetAtStartup, nil
+	case consumeFromTimestamp:
+		return r.fetchOffsetWithRetries(ctx, "offset after timestamp", r.fetchOffsetAfterTimestamp)
+	default:
+		return r.fetchOffsetWithRetries(ctx, "last committed offset", r.fetchLastCommittedOffset)
+	}
+}
+
+func (r *PartitionReader) fetchOffsetWithRetries(ctx context.Context, name string, fetch func(context.Context) (int64, error)) (offset int64, err error) {
 	var (
 		retry = backoff.New(ctx, backoff.Config{
 			MinBackoff: 100 * time.Millisecond,
@@ -299,12 +338,12 @@ func (r *PartitionReader) fetchLastCommittedOffsetWithRetries(ctx context.Contex
 	)
 
 	for retry.Ongoing() {
-		offset, err = r.fetchLastCommittedOffset(ctx)
+		offset, err = fetch(ctx)
 		if err == nil {
 			return offset, nil
 		}
 
-		level.Warn(r.logger).Log("msg", "failed to fetch last committed offset", "err", err)
+		level.Warn(r.logger).Log("msg", "failed to fetch "+name, "err", err)
 		retry.Wait()
 	}
 
@@ -341,6 +380,76 @@ func (r *PartitionReader) fetchLastCommittedOffset(ctx context.Context) (int64,
 	return offset.At, nil
 }
 
+// fetchOffsetAfterTimestamp returns the offset of the first record produced at or after the configured
+// timestamp, or the offset of the next record to be produced if there's no such record.
+func (r *PartitionReader) fetchOffsetAfterTimestamp(ctx context.Context) (int64, error) {
+	// We use an ephemeral client for the same reason explained in fetchLastCommittedOffset().
+	cl, err := kgo.NewClient(kgo.SeedBrokers(r.kafkaCfg.Address))
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to create admin client")
+	}
+	adm := kadm.NewClient(cl)
+	defer adm.Close()
+
+	offsets, err := adm.ListOffsetsAfterMilli(ctx, r.kafkaCfg.ConsumeFromTimestampAtStartup, r.kafkaCfg.Topic)
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to list offsets after timestamp")
+	}
+
+	offset, ok := offsets.Lookup(r.kafkaCfg.Topic, r.partitionID)
+	if !ok {
+		return 0, fmt.Errorf("partition %d not found in the listed offsets", r.partitionID)
+	}
+	if offset.Err != nil {
+		return 0, errors.Wrap(offset.Err, "unable to list offsets after timestamp")
+	}
+	if offset.Offset >= 0 {
+		return offset.Offset, nil
+	}
+
+	// There's no record produced at or after the timestamp, so we start from the end of the partition.
+	endOffsets, err := adm.ListEndOffsets(ctx, r.kafkaCfg.Topic)
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to list end offsets")
+	}
+	endOffset, ok := endOffsets.Lookup(r.kafkaCfg.Topic, r.partitionID)
+	if !ok {
+		return 0, fmt.Errorf("partition %d not found in the listed end offsets", r.partitionID)
+	}
+	if endOffset.Err != nil {
+		return 0, errors.Wrap(endOffset.Err, "unable to list end offsets")
+	}
+	return endOffset.Offset, nil
+}
+
+// CheckReady returns an error if the reader hasn't caught up with the partition yet, which means the
+// consumer lag hasn't been below the configured threshold since the reader started.
+func (r *PartitionReader) CheckReady(ctx context.Context) error {
+	if r.kafkaCfg.ReadinessMaxConsumerLag <= 0 || r.caughtUp.Load() {
+		return nil
+	}
+
+	// Ensure the service is running. Some subservices used below are created when starting
+	// so they're not available before that.
+	if state := r.Service.State(); state != services.Running {
+		return fmt.Errorf("partition reader service is not running (state: %s)", state.String())
+	}
+
+	lastProducedOffset, err := r.offsetReader.FetchLastProducedOffset(ctx)
+	if err != nil {
+		return errors.Wrap(err, "fetching last produced offset")
+	}
+
+	lag := lastProducedOffset - r.consumedOffsetWatcher.LastConsumedOffset()
+	if lag > r.kafkaCfg.ReadinessMaxConsumerLag {
+		return fmt.Errorf("partition reader is catching up (consumer lag: %d records, max allowed: %d)", lag, r.kafkaCfg.ReadinessMaxConsumerLag)
+	}
+
+	level.Info(r.logger).Log("msg", "partition reader has caught up", "consumer_lag", lag)
+	r.caughtUp.Store(true)
+	return nil
+}
+
 // WaitReadConsistency waits until all data produced up until now has been consumed by the reader.
 func (r *PartitionReader) WaitReadConsistency(ctx context.Context) (returnErr error) {
 	startTime := time.Now()
@@ -16,7 +16,9 @@ import (
 	promtest "github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kfake"
 	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
 	"go.uber.org/atomic"
 
 	"github.com/grafana/mimir/pkg/util/testkafka"
@@ -285,6 +287,12 @@ func withLastProducedOffsetPollInterval(i time.Duration) func(cfg *readerTestCfg
 	}
 }
 
+func withKafkaConfig(fn func(cfg *KafkaConfig)) func(cfg *readerTestCfg) {
+	return func(cfg *readerTestCfg) {
+		fn(&cfg.kafka)
+	}
+}
+
 func withRegistry(reg prometheus.Registerer) func(cfg *readerTestCfg) {
 	return func(cfg *readerTestCfg) {
 		cfg.registry = reg
@@ -422,6 +430,160 @@ func TestPartitionReader_Commit(t *testing.T) {
 	})
 }
 
+func TestPartitionReader_ConsumeFromPositionAtStartup(t *testing.T) {
+	const (
+		topicName   = "test"
+		partitionID = 1
+	)
+
+	t.Run("should consume from the configured offset", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancelCause(context.Background())
+		t.Cleanup(func() { cancel(errors.New("test done")) })
+
+		_, clusterAddr := testkafka.CreateCluster(t, partitionID+1, topicName)
+		writeClient := newKafkaProduceClient(t, clusterAddr)
+
+		produceRecord(ctx, t, writeClient, topicName, partitionID, []byte("1"))
+		produceRecord(ctx, t, writeClient, topicName, partitionID, []byte("2"))
+		produceRecord(ctx, t, writeClient, topicName, partitionID, []byte("3"))
+
+		consumer := newTestConsumer(3)
+		startReader(ctx, t, clusterAddr, topicName, partitionID, consumer, withKafkaConfig(func(cfg *KafkaConfig) {
+			cfg.ConsumeFromPositionAtStartup = consumeFromOffset
+			cfg.ConsumeFromOffsetAtStartup = 1
+		}))
+
+		records, err := consumer.waitRecords(2, 5*time.Second, time.Second)
+		assert.NoError(t, err)
+		assert.Equal(t, [][]byte{[]byte("2"), []byte("3")}, records)
+	})
+
+	// The fake Kafka cluster doesn't correctly look up offsets by timestamp, so we mock the response
+	// to the ListOffsets requests for the configured timestamp, and return the given offset.
+	mockListOffsetsAfterTimestamp := func(cluster *kfake.Cluster, timestamp, offset int64) {
+		cluster.ControlKey(int16(kmsg.ListOffsets), func(kreq kmsg.Request) (kmsg.Response, error, bool) {
+			cluster.KeepControl()
+
+			req := kreq.(*kmsg.ListOffsetsRequest)
+			if len(req.Topics) != 1 || len(req.Topics[0].Partitions) == 0 || req.Topics[0].Partitions[0].Timestamp != timestamp {
+				return nil, nil, false
+			}
+
+			res := req.ResponseKind().(*kmsg.ListOffsetsResponse)
+			res.Topics = []kmsg.ListOffsetsResponseTopic{{Topic: req.Topics[0].Topic}}
+			for _, partition := range req.Topics[0].Partitions {
+				res.Topics[0].Partitions = append(res.Topics[0].Partitions, kmsg.ListOffsetsResponseTopicPartition{
+					Partition: partition.Partition,
+					Offset:    offset,
+					Timestamp: timestamp,
+				})
+			}
+			return res, nil, true
+		})
+	}
+
+	t.Run("should consume from the first record produced after the configured timestamp", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancelCause(context.Background())
+		t.Cleanup(func() { cancel(errors.New("test done")) })
+
+		cluster, clusterAddr := testkafka.CreateCluster(t, partitionID+1, topicName)
+		writeClient := newKafkaProduceClient(t, clusterAddr)
+
+		produceRecord(ctx, t, writeClient, topicName, partitionID, []byte("1"))
+		produceRecord(ctx, t, writeClient, topicName, partitionID, []byte("2"))
+		produceRecord(ctx, t, writeClient, topicName, partitionID, []byte("3"))
+
+		startTimestamp := time.Now().UnixMilli()
+		mockListOffsetsAfterTimestamp(cluster, startTimestamp, 2)
+
+		consumer := newTestConsumer(3)
+		startReader(ctx, t, clusterAddr, topicName, partitionID, consumer, withKafkaConfig(func(cfg *KafkaConfig) {
+			cfg.ConsumeFromPositionAtStartup = consumeFromTimestamp
+			cfg.ConsumeFromTimestampAtStartup = startTimestamp
+		}))
+
+		records, err := consumer.waitRecords(1, 5*time.Second, time.Second)
+		assert.NoError(t, err)
+		assert.Equal(t, [][]byte{[]byte("3")}, records)
+	})
+
+	t.Run("should consume from the end of the partition if no record has been produced after the configured timestamp", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancelCause(context.Background())
+		t.Cleanup(func() { cancel(errors.New("test done")) })
+
+		cluster, clusterAddr := testkafka.CreateCluster(t, partitionID+1, topicName)
+		writeClient := newKafkaProduceClient(t, clusterAddr)
+
+		produceRecord(ctx, t, writeClient, topicName, partitionID, []byte("1"))
+		produceRecord(ctx, t, writeClient, topicName, partitionID, []byte("2"))
+
+		startTimestamp := time.Now().Add(time.Hour).UnixMilli()
+		mockListOffsetsAfterTimestamp(cluster, startTimestamp, -1)
+
+		consumer := newTestConsumer(3)
+		startReader(ctx, t, clusterAddr, topicName, partitionID, consumer, withKafkaConfig(func(cfg *KafkaConfig) {
+			cfg.ConsumeFromPositionAtStartup = consumeFromTimestamp
+			cfg.ConsumeFromTimestampAtStartup = startTimestamp
+		}))
+
+		produceRecord(ctx, t, writeClient, topicName, partitionID, []byte("3"))
+
+		records, err := consumer.waitRecords(1, 5*time.Second, time.Second)
+		assert.NoError(t, err)
+		assert.Equal(t, [][]byte{[]byte("3")}, records)
+	})
+}
+
+func TestPartitionReader_CheckReady(t *testing.T) {
+	const (
+		topicName   = "test"
+		partitionID = 0
+	)
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	t.Cleanup(func() { cancel(errors.New("test done")) })
+
+	_, clusterAddr := testkafka.CreateCluster(t, 1, topicName)
+	writeClient := newKafkaProduceClient(t, clusterAddr)
+
+	for i := 0; i < 5; i++ {
+		produceRecord(ctx, t, writeClient, topicName, partitionID, []byte(fmt.Sprintf("record-%d", i)))
+	}
+
+	// Create a consumer with no buffer capacity, so that records are not consumed until we read them.
+	consumer := newTestConsumer(0)
+	reader := startReader(ctx, t, clusterAddr, topicName, partitionID, consumer, withKafkaConfig(func(cfg *KafkaConfig) {
+		cfg.LastProducedOffsetPollInterval = 100 * time.Millisecond
+		cfg.ReadinessMaxConsumerLag = 2
+	}))
+
+	err := reader.CheckReady(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "partition reader is catching up")
+
+	_, err = consumer.waitRecords(5, 5*time.Second, 0)
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		return reader.CheckReady(ctx) == nil
+	}, 5*time.Second, 100*time.Millisecond)
+
+	// Once caught up, the reader should stay ready even if the lag grows again.
+	for i := 5; i < 10; i++ {
+		produceRecord(ctx, t, writeClient, topicName, partitionID, []byte(fmt.Sprintf("record-%d", i)))
+	}
+	assert.NoError(t, reader.CheckReady(ctx))
+
+	_, err = consumer.waitRecords(5, 5*time.Second, 0)
+	require.NoError(t, err)
+}
+
 type testConsumer struct {
 	records chan []byte
 }
@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package ingest
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+const (
+	// recordVersionHeaderKey is the key of the Kafka record header holding the version of the record format.
+	// Records without this header hold a single serialized WriteRequest, and the tenant ID in the record key.
+	recordVersionHeaderKey = "Version"
+
+	// recordVersionSingle is the version of records holding a single serialized WriteRequest.
+	recordVersionSingle = 0
+
+	// recordVersionBatch is the version of records holding a batch of serialized WriteRequests,
+	// possibly of different tenants. The batch is encoded as a sequence of entries, each of which
+	// is made of the uvarint-prefixed tenant ID followed by the uvarint-prefixed WriteRequest.
+	recordVersionBatch = 1
+)
+
+// parseRecordVersion returns the version of the format of the input record,
+// or -1 if the version header can't be parsed.
+func parseRecordVersion(rec *kgo.Record) int {
+	for _, header := range rec.Headers {
+		if header.Key != recordVersionHeaderKey {
+			continue
+		}
+
+		version, err := strconv.Atoi(string(header.Value))
+		if err != nil {
+			return -1
+		}
+		return version
+	}
+
+	return recordVersionSingle
+}
+
+// recordBatchBuilder encodes a batch of serialized WriteRequests into the content of a single record.
+type recordBatchBuilder struct {
+	buf []byte
+	len int
+}
+
+// add the serialized WriteRequest of the input tenant to the batch.
+func (b *recordBatchBuilder) add(tenantID string, data []byte) {
+	b.buf = binary.AppendUvarint(b.buf, uint64(len(tenantID)))
+	b.buf = append(b.buf, tenantID...)
+	b.buf = binary.AppendUvarint(b.buf, uint64(len(data)))
+	b.buf = append(b.buf, data...)
+	b.len++
+}
+
+// recordBatchEntrySize returns how many bytes the input entry takes once added to a batch.
+func recordBatchEntrySize(tenantID string, data []byte) int {
+	return uvarintSize(uint64(len(tenantID))) + len(tenantID) + uvarintSize(uint64(len(data))) + len(data)
+}
+
+func (b *recordBatchBuilder) size() int {
+	return len(b.buf)
+}
+
+// record returns the Kafka record holding the batch.
+func (b *recordBatchBuilder) record() *kgo.Record {
+	return &kgo.Record{
+		Value:   b.buf,
+		Headers: []kgo.RecordHeader{{Key: recordVersionHeaderKey, Value: []byte(strconv.Itoa(recordVersionBatch))}},
+	}
+}
+
+// decodeRecordBatch calls fn for each serialized WriteRequest in the content of a batched record.
+// The input data is not copied, so fn must not retain it once the content is released.
+func decodeRecordBatch(content []byte, fn func(tenantID string, data []byte)) error {
+	for len(content) > 0 {
+		tenantID, rest, err := readUvarintPrefixed(content)
+		if err != nil {
+			return errors.Wrap(err, "decoding batched record tenant ID")
+		}
+
+		data, rest, err := readUvarintPrefixed(rest)
+		if err != nil {
+			return errors.Wrap(err, "decoding batched record write request")
+		}
+
+		fn(string(tenantID), data)
+		content = rest
+	}
+
+	return nil
+}
+
+func readUvarintPrefixed(buf []byte) (value, rest []byte, err error) {
+	length, n := binary.Uvarint(buf)
+	if n <= 0 {
+		return nil, nil, errors.New("invalid length")
+	}
+	buf = buf[n:]
+
+	if uint64(len(buf)) < length {
+		return nil, nil, fmt.Errorf("expected %d bytes but only %d are left", length, len(buf))
+	}
+	return buf[:length], buf[length:], nil
+}
+
+func uvarintSize(v uint64) int {
+	size := 1
+	for ; v >= 0x80; v >>= 7 {
+		size++
+	}
+	return size
+}
@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package ingest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestRecordBatch(t *testing.T) {
+	type entry struct {
+		tenantID string
+		data     string
+	}
+
+	entries := []entry{
+		{tenantID: "user-1", data: "first"},
+		{tenantID: "user-2", data: ""},
+		{tenantID: "user-1", data: strings.Repeat("x", 300)},
+	}
+
+	builder := recordBatchBuilder{}
+	expectedSize := 0
+	for _, e := range entries {
+		builder.add(e.tenantID, []byte(e.data))
+		expectedSize += recordBatchEntrySize(e.tenantID, []byte(e.data))
+	}
+	assert.Equal(t, expectedSize, builder.size())
+	assert.Equal(t, len(entries), builder.len)
+
+	rec := builder.record()
+	assert.Nil(t, rec.Key)
+	assert.Equal(t, recordVersionBatch, parseRecordVersion(rec))
+
+	var actual []entry
+	require.NoError(t, decodeRecordBatch(rec.Value, func(tenantID string, data []byte) {
+		actual = append(actual, entry{tenantID: tenantID, data: string(data)})
+	}))
+	assert.Equal(t, entries, actual)
+
+	t.Run("truncated content", func(t *testing.T) {
+		err := decodeRecordBatch(rec.Value[:len(rec.Value)-1], func(string, []byte) {})
+		require.ErrorContains(t, err, "decoding batched record write request")
+	})
+
+	t.Run("empty content", func(t *testing.T) {
+		require.NoError(t, decodeRecordBatch(nil, func(string, []byte) {
+			require.Fail(t, "no entry expected")
+		}))
+	})
+}
+
+func TestParseRecordVersion(t *testing.T) {
+	tests := map[string]struct {
+		headers  []kgo.RecordHeader
+		expected int
+	}{
+		"no headers": {
+			expected: recordVersionSingle,
+		},
+		"other headers": {
+			headers:  []kgo.RecordHeader{{Key: "other", Value: []byte("1")}},
+			expected: recordVersionSingle,
+		},
+		"batch version": {
+			headers:  []kgo.RecordHeader{{Key: recordVersionHeaderKey, Value: []byte("1")}},
+			expected: recordVersionBatch,
+		},
+		"invalid version": {
+			headers:  []kgo.RecordHeader{{Key: recordVersionHeaderKey, Value: []byte("x")}},
+			expected: -1,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, parseRecordVersion(&kgo.Record{Headers: tc.headers}))
+		})
+	}
+}
@@ -26,6 +26,9 @@ const (
 	// before being sent on the wire and the actual time it takes to send it over the network and
 	// start being processed by Kafka.
 	writerRequestTimeoutOverhead = 2 * time.Second
+
+	// producerBatchMaxBytes is the upper bound of the size of a record batch sent to Kafka.
+	producerBatchMaxBytes = 16_000_000
 )
 
 // Writer is responsible to write incoming data to the ingest storage.
@@ -40,9 +43,15 @@ type Writer struct {
 	writersMx sync.RWMutex
 	writers   map[int32]*kgo.Client
 
+	// The batchers are used only when the producer batching is enabled. Each batcher writes
+	// to the partition through the writer of the same partition.
+	batchersMx sync.RWMutex
+	batchers   map[int32]*partitionBatcher
+
 	// Metrics.
 	writeLatency    prometheus.Summary
 	writeBytesTotal prometheus.Counter
+	batchRequests   prometheus.Histogram
 
 	// The following settings can only be overridden in tests.
 	maxInflightProduceRequests int
@@ -54,6 +63,7 @@ func NewWriter(kafkaCfg KafkaConfig, logger log.Logger, reg prometheus.Registere
 		logger:                     logger,
 		registerer:                 reg,
 		writers:                    map[int32]*kgo.Client{},
+		batchers:                   map[int32]*partitionBatcher{},
 		maxInflightProduceRequests: 20,
 
 		// Metrics.
@@ -68,6 +78,11 @@ func NewWriter(kafkaCfg KafkaConfig, logger log.Logger, reg prometheus.Registere
 			Name: "cortex_ingest_storage_writer_sent_bytes_total",
 			Help: "Total number of bytes sent to the ingest storage.",
 		}),
+		batchRequests: promauto.With(reg).NewHistogram(prometheus.HistogramOpts{
+			Name:    "cortex_ingest_storage_writer_batch_requests",
+			Help:    "Number of write requests coalesced in a single batched record.",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+		}),
 	}
 
 	w.Service = services.NewIdleService(nil, w.stopping)
@@ -76,6 +91,14 @@ func NewWriter(kafkaCfg KafkaConfig, logger log.Logger, reg prometheus.Registere
 }
 
 func (w *Writer) stopping(_ error) error {
+	// Write the pending batches before closing the clients.
+	w.batchersMx.Lock()
+	for partitionID, batcher := range w.batchers {
+		batcher.flushPending()
+		delete(w.batchers, partitionID)
+	}
+	w.batchersMx.Unlock()
+
 	w.writersMx.Lock()
 	defer w.writersMx.Unlock()
 
@@ -102,21 +125,33 @@ func (w *Writer) WriteSync(ctx context.Context, partitionID int32, userID string
 		return errors.Wrap(err, "failed to serialise data")
 	}
 
-	// Prepare the record to write.
-	record := &kgo.Record{
-		Key:   []byte(userID), // We don't partition based on the key, so the value here doesn't make any difference.
-		Value: data,
-	}
-
-	// Write to backend.
-	writer, err := w.getKafkaWriterForPartition(partitionID)
-	if err != nil {
-		return err
-	}
-
-	err = w.produceSync(ctx, writer, record)
-	if err != nil {
-		return err
+	if w.kafkaCfg.ProducerBatchingEnabled {
+		batcher, err := w.getBatcherForPartition(partitionID)
+		if err != nil {
+			return err
+		}
+
+		err = batcher.write(ctx, userID, data)
+		if err != nil {
+			return err
+		}
+	} else {
+		// Prepare the record to write.
+		record := &kgo.Record{
+			Key:   []byte(userID), // We don't partition based on the key, so the value here doesn't make any difference.
+			Value: data,
+		}
+
+		// Write to backend.
+		writer, err := w.getKafkaWriterForPartition(partitionID)
+		if err != nil {
+			return err
+		}
+
+		err = w.produceSync(ctx, writer, record)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Track latency and payload size only for successful requests.
@@ -172,6 +207,34 @@ func (w *Writer) getKafkaWriterForPartition(partitionID int32) (*kgo.Client, err
 	return newWriter, nil
 }
 
+func (w *Writer) getBatcherForPartition(partitionID int32) (*partitionBatcher, error) {
+	// Check if the batcher has already been created.
+	w.batchersMx.RLock()
+	batcher := w.batchers[partitionID]
+	w.batchersMx.RUnlock()
+
+	if batcher != nil {
+		return batcher, nil
+	}
+
+	writer, err := w.getKafkaWriterForPartition(partitionID)
+	if err != nil {
+		return nil, err
+	}
+
+	w.batchersMx.Lock()
+	defer w.batchersMx.Unlock()
+
+	// Ensure a new batcher wasn't created in the meanwhile. If so, use it.
+	batcher = w.batchers[partitionID]
+	if batcher != nil {
+		return batcher, nil
+	}
+	batcher = newPartitionBatcher(writer, w.kafkaCfg.ProducerBatchMaxBytes, w.kafkaCfg.ProducerBatchLinger, w.batchRequests)
+	w.batchers[partitionID] = batcher
+	return batcher, nil
+}
+
 // newKafkaWriter creates a new Kafka client used to write to a specific partition.
 func (w *Writer) newKafkaWriter(partitionID int32) (*kgo.Client, error) {
 	logger := log.With(w.logger, "partition", partitionID)
@@ -190,7 +253,7 @@ func (w *Writer) newKafkaWriter(partitionID int32) (*kgo.Client, error) {
 		kgo.RecordPartitioner(newKafkaStaticPartitioner(int(partitionID))),
 
 		// Set the upper bounds the size of a record batch.
-		kgo.ProducerBatchMaxBytes(16_000_000),
+		kgo.ProducerBatchMaxBytes(producerBatchMaxBytes),
 
 		// By default, the Kafka client allows 1 Produce in-flight request per broker. Disabling write idempotency
 		// (which we don't need), we can increase the max number of in-flight Produce requests per broker. A higher
@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package ingest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// partitionBatcher coalesces the write requests for a partition into batched records. A batch is
+// written once it reaches the max size, or once the linger time since the first request has elapsed.
+type partitionBatcher struct {
+	client   *kgo.Client
+	maxBytes int
+	linger   time.Duration
+
+	batchRequests prometheus.Observer
+
+	pendingMx sync.Mutex
+	pending   *pendingBatch
+}
+
+// pendingBatch is a batch which hasn't been written yet.
+type pendingBatch struct {
+	builder recordBatchBuilder
+	timer   *time.Timer
+
+	// done is closed once the batch has been written. Once closed, it's safe to read err.
+	done chan struct{}
+	err  error
+}
+
+func newPartitionBatcher(client *kgo.Client, maxBytes int, linger time.Duration, batchRequests prometheus.Observer) *partitionBatcher {
+	return &partitionBatcher{
+		client:        client,
+		maxBytes:      maxBytes,
+		linger:        linger,
+		batchRequests: batchRequests,
+	}
+}
+
+// write adds the serialized WriteRequest to the pending batch, and blocks until the batch has been
+// written or the context is done.
+func (b *partitionBatcher) write(ctx context.Context, tenantID string, data []byte) error {
+	var toFlush []*pendingBatch
+
+	b.pendingMx.Lock()
+
+	// Write the pending batch first if adding this request would exceed the max size.
+	if b.pending != nil && b.pending.builder.size()+recordBatchEntrySize(tenantID, data) > b.maxBytes {
+		toFlush = append(toFlush, b.detachPendingLocked())
+	}
+
+	if b.pending == nil {
+		batch := &pendingBatch{done: make(chan struct{})}
+		batch.timer = time.AfterFunc(b.linger, func() { b.flushIfPending(batch) })
+		b.pending = batch
+	}
+
+	batch := b.pending
+	batch.builder.add(tenantID, data)
+
+	// Write the batch right away if it has reached the max size.
+	if batch.builder.size() >= b.maxBytes {
+		toFlush = append(toFlush, b.detachPendingLocked())
+	}
+
+	b.pendingMx.Unlock()
+
+	// Produce outside the lock, because it may block if the client buffer is full.
+	for _, flushBatch := range toFlush {
+		b.flush(flushBatch)
+	}
+
+	select {
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	case <-batch.done:
+		return batch.err
+	}
+}
+
+// flushIfPending writes the input batch, unless it has already been detached to be written.
+func (b *partitionBatcher) flushIfPending(batch *pendingBatch) {
+	b.pendingMx.Lock()
+	if b.pending != batch {
+		b.pendingMx.Unlock()
+		return
+	}
+	b.detachPendingLocked()
+	b.pendingMx.Unlock()
+
+	b.flush(batch)
+}
+
+// flushPending writes the pending batch, if any.
+func (b *partitionBatcher) flushPending() {
+	b.pendingMx.Lock()
+	batch := b.detachPendingLocked()
+	b.pendingMx.Unlock()
+
+	if batch != nil {
+		b.flush(batch)
+	}
+}
+
+// detachPendingLocked removes the pending batch, so that new requests are added to a new batch.
+// This function must be called with the pendingMx lock held.
+func (b *partitionBatcher) detachPendingLocked() *pendingBatch {
+	batch := b.pending
+	b.pending = nil
+
+	if batch != nil {
+		batch.timer.Stop()
+	}
+	return batch
+}
+
+func (b *partitionBatcher) flush(batch *pendingBatch) {
+	b.batchRequests.Observe(float64(batch.builder.len))
+
+	// We use a new context to avoid that other Produce() may be cancelled when a request context is canceled.
+	// See Writer.produceSync() for more details.
+	b.client.Produce(context.Background(), batch.builder.record(), func(_ *kgo.Record, err error) {
+		batch.err = err
+		close(batch.done)
+	})
+}
@@ -405,3 +405,112 @@ func createTestKafkaClient(t *testing.T, cfg KafkaConfig) *kgo.Client {
 
 	return client
 }
+
+func TestWriter_WriteSync_ProducerBatching(t *testing.T) {
+	const (
+		topicName     = "test"
+		numPartitions = 1
+		partitionID   = 0
+	)
+
+	ctx := context.Background()
+
+	readAllRecords := func(t *testing.T, clusterAddr string, expected int) []*kgo.Record {
+		consumer, err := kgo.NewClient(kgo.SeedBrokers(clusterAddr), kgo.ConsumePartitions(map[string]map[int32]kgo.Offset{topicName: {int32(partitionID): kgo.NewOffset().AtStart()}}))
+		require.NoError(t, err)
+		t.Cleanup(consumer.Close)
+
+		fetchCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		t.Cleanup(cancel)
+
+		var records []*kgo.Record
+		for len(records) < expected {
+			fetches := consumer.PollFetches(fetchCtx)
+			require.NoError(t, fetches.Err())
+			records = append(records, fetches.Records()...)
+		}
+		return records
+	}
+
+	t.Run("should coalesce concurrent requests of different tenants into a single record", func(t *testing.T) {
+		t.Parallel()
+
+		_, clusterAddr := testkafka.CreateCluster(t, numPartitions, topicName)
+
+		cfg := createTestKafkaConfig(clusterAddr, topicName)
+		cfg.ProducerBatchingEnabled = true
+		cfg.ProducerBatchLinger = time.Second
+		writer, reg := createTestWriter(t, cfg)
+
+		tenants := []string{"user-1", "user-2", "user-3"}
+
+		wg := sync.WaitGroup{}
+		for _, tenantID := range tenants {
+			tenantID := tenantID
+			runAsync(&wg, func() {
+				req := &mimirpb.WriteRequest{Timeseries: []mimirpb.PreallocTimeseries{mockPreallocTimeseries("series_" + tenantID)}, Source: mimirpb.API}
+				require.NoError(t, writer.WriteSync(ctx, partitionID, tenantID, req))
+			})
+		}
+		wg.Wait()
+
+		records := readAllRecords(t, clusterAddr, 1)
+		require.Len(t, records, 1)
+		assert.Equal(t, recordVersionBatch, parseRecordVersion(records[0]))
+
+		received := map[string]string{}
+		require.NoError(t, decodeRecordBatch(records[0].Value, func(tenantID string, data []byte) {
+			req := mimirpb.WriteRequest{}
+			require.NoError(t, req.Unmarshal(data))
+			require.Len(t, req.Timeseries, 1)
+			received[tenantID] = req.Timeseries[0].Labels[0].Value
+		}))
+		assert.Equal(t, map[string]string{"user-1": "series_user-1", "user-2": "series_user-2", "user-3": "series_user-3"}, received)
+
+		assert.NoError(t, promtest.GatherAndCompare(reg, strings.NewReader(`
+			# HELP cortex_ingest_storage_writer_batch_requests Number of write requests coalesced in a single batched record.
+			# TYPE cortex_ingest_storage_writer_batch_requests histogram
+			cortex_ingest_storage_writer_batch_requests_bucket{le="1"} 0
+			cortex_ingest_storage_writer_batch_requests_bucket{le="2"} 0
+			cortex_ingest_storage_writer_batch_requests_bucket{le="4"} 1
+			cortex_ingest_storage_writer_batch_requests_bucket{le="8"} 1
+			cortex_ingest_storage_writer_batch_requests_bucket{le="16"} 1
+			cortex_ingest_storage_writer_batch_requests_bucket{le="32"} 1
+			cortex_ingest_storage_writer_batch_requests_bucket{le="64"} 1
+			cortex_ingest_storage_writer_batch_requests_bucket{le="128"} 1
+			cortex_ingest_storage_writer_batch_requests_bucket{le="256"} 1
+			cortex_ingest_storage_writer_batch_requests_bucket{le="512"} 1
+			cortex_ingest_storage_writer_batch_requests_bucket{le="1024"} 1
+			cortex_ingest_storage_writer_batch_requests_bucket{le="2048"} 1
+			cortex_ingest_storage_writer_batch_requests_bucket{le="+Inf"} 1
+			cortex_ingest_storage_writer_batch_requests_sum 3
+			cortex_ingest_storage_writer_batch_requests_count 1
+		`), "cortex_ingest_storage_writer_batch_requests"))
+	})
+
+	t.Run("should write the batch without waiting for the linger once it reaches the max size", func(t *testing.T) {
+		t.Parallel()
+
+		_, clusterAddr := testkafka.CreateCluster(t, numPartitions, topicName)
+
+		cfg := createTestKafkaConfig(clusterAddr, topicName)
+		cfg.ProducerBatchingEnabled = true
+		cfg.ProducerBatchMaxBytes = 1
+		cfg.ProducerBatchLinger = time.Hour
+		writer, _ := createTestWriter(t, cfg)
+
+		writeCtx := createTestContextWithTimeout(t, 5*time.Second)
+		require.NoError(t, writer.WriteSync(writeCtx, partitionID, "user-1", &mimirpb.WriteRequest{Timeseries: []mimirpb.PreallocTimeseries{mockPreallocTimeseries("series_1")}, Source: mimirpb.API}))
+		require.NoError(t, writer.WriteSync(writeCtx, partitionID, "user-2", &mimirpb.WriteRequest{Timeseries: []mimirpb.PreallocTimeseries{mockPreallocTimeseries("series_2")}, Source: mimirpb.API}))
+
+		records := readAllRecords(t, clusterAddr, 2)
+		require.Len(t, records, 2)
+		for i, tenantID := range []string{"user-1", "user-2"} {
+			var tenants []string
+			require.NoError(t, decodeRecordBatch(records[i].Value, func(tenantID string, _ []byte) {
+				tenants = append(tenants, tenantID)
+			}))
+			assert.Equal(t, []string{tenantID}, tenants)
+		}
+	})
+}
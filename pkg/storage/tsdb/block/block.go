@@ -37,6 +37,10 @@ const (
 	SparseIndexHeaderFilename = "sparse-index-header"
 	// ChunksDirname is the known dir name for chunks with compressed samples.
 	ChunksDirname = "chunks"
+	// ExemplarsFilename is the optional file storing the exemplars of the block series.
+	ExemplarsFilename = "exemplars"
+	// MetricMetadataFilename is the optional file storing the metadata of the block metrics.
+	MetricMetadataFilename = "metric_metadata.json"
 
 	// DebugMetas is a directory for debug meta files that happen in the past. Useful for debugging.
 	DebugMetas = "debug/metas"
@@ -121,6 +125,18 @@ func Upload(ctx context.Context, logger log.Logger, bkt objstore.Bucket, blockDi
 		return cleanUp(logger, bkt, id, errors.Wrap(err, "upload index"))
 	}
 
+	if HasExemplars(meta) {
+		if err := objstore.UploadFile(ctx, logger, bkt, filepath.Join(blockDir, ExemplarsFilename), path.Join(id.String(), ExemplarsFilename)); err != nil {
+			return cleanUp(logger, bkt, id, errors.Wrap(err, "upload exemplars"))
+		}
+	}
+
+	if HasMetricMetadata(meta) {
+		if err := objstore.UploadFile(ctx, logger, bkt, filepath.Join(blockDir, MetricMetadataFilename), path.Join(id.String(), MetricMetadataFilename)); err != nil {
+			return cleanUp(logger, bkt, id, errors.Wrap(err, "upload metric metadata"))
+		}
+	}
+
 	// Meta.json always need to be uploaded as a last item. This will allow to assume block directories without meta file to be pending uploads.
 	if err := bkt.Upload(ctx, path.Join(id.String(), MetaFilename), strings.NewReader(metaEncoded.String())); err != nil {
 		// Don't call cleanUp here. Despite getting error, meta.json may have been uploaded in certain cases,
@@ -313,6 +329,20 @@ func GatherFileStats(blockDir string) (res []File, _ error) {
 	}
 	res = append(res, mf)
 
+	// The exemplars and metric metadata files are optional.
+	for _, name := range []string{ExemplarsFilename, MetricMetadataFilename} {
+		optionalFile, err := os.Stat(filepath.Join(blockDir, name))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, errors.Wrapf(err, "stat %v", filepath.Join(blockDir, name))
+		}
+		if err == nil {
+			res = append(res, File{
+				RelPath:   optionalFile.Name(),
+				SizeBytes: optionalFile.Size(),
+			})
+		}
+	}
+
 	metaFile, err := os.Stat(filepath.Join(blockDir, MetaFilename))
 	if err != nil {
 		return nil, errors.Wrapf(err, "stat %v", filepath.Join(blockDir, MetaFilename))
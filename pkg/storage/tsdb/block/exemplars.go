@@ -0,0 +1,248 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package block
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/runutil"
+	"github.com/oklog/ulid"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+)
+
+const (
+	// ExemplarsFormatV1 is the only supported format of the exemplars file: the format version byte, followed by
+	// a length-delimited mimirpb.TimeSeries for each series, holding the series labels and exemplars, sorted by labels.
+	ExemplarsFormatV1 = 1
+
+	// maxExemplarsSeriesSize is the max size of the exemplars of a single series in the exemplars file.
+	maxExemplarsSeriesSize = 64 * 1024 * 1024
+)
+
+// HasExemplars returns whether the files of the block include the exemplars file.
+func HasExemplars(meta *Meta) bool {
+	for _, f := range meta.Thanos.Files {
+		if f.RelPath == ExemplarsFilename {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteExemplarsFile writes the exemplars to the exemplars file of the block directory. The file isn't written if there
+// are no exemplars.
+func WriteExemplarsFile(logger log.Logger, dir string, series []exemplar.QueryResult) error {
+	if len(series) == 0 {
+		return nil
+	}
+
+	// Make any changes to the file appear atomic.
+	path := filepath.Join(dir, ExemplarsFilename)
+	tmp := path + ".tmp"
+
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+
+	if err := WriteExemplars(f, series); err != nil {
+		runutil.CloseWithLogOnErr(logger, f, "close exemplars")
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return renameFile(logger, tmp, path)
+}
+
+// WriteExemplars writes the exemplars to the writer, in the exemplars file format. The series are sorted by labels.
+func WriteExemplars(w io.Writer, series []exemplar.QueryResult) error {
+	sort.Slice(series, func(i, j int) bool {
+		return labels.Compare(series[i].SeriesLabels, series[j].SeriesLabels) < 0
+	})
+
+	bw := bufio.NewWriter(w)
+	if err := bw.WriteByte(ExemplarsFormatV1); err != nil {
+		return err
+	}
+
+	var (
+		buf    []byte
+		lenBuf [binary.MaxVarintLen64]byte
+	)
+	for _, s := range series {
+		if len(s.Exemplars) == 0 {
+			continue
+		}
+
+		ts := mimirpb.TimeSeries{
+			Labels:    mimirpb.FromLabelsToLabelAdapters(s.SeriesLabels),
+			Exemplars: mimirpb.FromExemplarsToExemplarProtos(s.Exemplars),
+		}
+		size := ts.Size()
+		if cap(buf) < size {
+			buf = make([]byte, size)
+		}
+		n, err := ts.MarshalToSizedBuffer(buf[:size])
+		if err != nil {
+			return errors.Wrap(err, "marshal exemplars")
+		}
+
+		if _, err := bw.Write(lenBuf[:binary.PutUvarint(lenBuf[:], uint64(n))]); err != nil {
+			return err
+		}
+		if _, err := bw.Write(buf[:n]); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// ReadExemplarsFromDir reads the exemplars file of the block directory. Returns no exemplars if the block has no
+// exemplars file.
+func ReadExemplarsFromDir(dir string) ([]exemplar.QueryResult, error) {
+	f, err := os.Open(filepath.Join(dir, ExemplarsFilename))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ReadExemplars(f)
+}
+
+// DownloadExemplars reads the exemplars file of the block from the bucket. Returns no exemplars if the block has no
+// exemplars file.
+func DownloadExemplars(ctx context.Context, logger log.Logger, bkt objstore.BucketReader, id ulid.ULID) ([]exemplar.QueryResult, error) {
+	rc, err := bkt.Get(ctx, path.Join(id.String(), ExemplarsFilename))
+	if bkt.IsObjNotFoundErr(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "exemplars bkt get for %s", id.String())
+	}
+	defer runutil.CloseWithLogOnErr(logger, rc, "download exemplars bucket client")
+
+	return ReadExemplars(rc)
+}
+
+// ReadExemplars reads the exemplars from the reader, in the exemplars file format.
+func ReadExemplars(r io.Reader) ([]exemplar.QueryResult, error) {
+	br := bufio.NewReader(r)
+	version, err := br.ReadByte()
+	if err != nil {
+		return nil, errors.Wrap(err, "read exemplars format version")
+	}
+	if version != ExemplarsFormatV1 {
+		return nil, errors.Errorf("unexpected exemplars format version %d", version)
+	}
+
+	var series []exemplar.QueryResult
+	for {
+		size, err := binary.ReadUvarint(br)
+		if errors.Is(err, io.EOF) {
+			return series, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "read exemplars series size")
+		}
+		if size > maxExemplarsSeriesSize {
+			return nil, errors.Errorf("exemplars series size %d exceeds the max size %d", size, maxExemplarsSeriesSize)
+		}
+
+		// The unmarshalled labels reference the buffer, so it can't be reused.
+		buf := make([]byte, size)
+		if _, err := io.ReadFull(br, buf); err != nil {
+			return nil, errors.Wrap(err, "read exemplars series")
+		}
+		var ts mimirpb.TimeSeries
+		if err := ts.Unmarshal(buf); err != nil {
+			return nil, errors.Wrap(err, "unmarshal exemplars series")
+		}
+
+		series = append(series, exemplar.QueryResult{
+			SeriesLabels: mimirpb.FromLabelAdaptersToLabels(ts.Labels),
+			Exemplars:    mimirpb.FromExemplarProtosToExemplars(ts.Exemplars),
+		})
+	}
+}
+
+// MergeExemplars merges the exemplars of the same series, removing the duplicated exemplars. The merged series are
+// sorted by labels, and the exemplars of each series by timestamp.
+func MergeExemplars(sets ...[]exemplar.QueryResult) []exemplar.QueryResult {
+	var (
+		result   []exemplar.QueryResult
+		bySeries = map[string]int{}
+	)
+	for _, set := range sets {
+		for _, s := range set {
+			key := s.SeriesLabels.String()
+			ix, ok := bySeries[key]
+			if !ok {
+				ix = len(result)
+				bySeries[key] = ix
+				result = append(result, exemplar.QueryResult{SeriesLabels: s.SeriesLabels})
+			}
+			result[ix].Exemplars = append(result[ix].Exemplars, s.Exemplars...)
+		}
+	}
+
+	for i := range result {
+		exemplars := result[i].Exemplars
+		sort.Slice(exemplars, func(a, b int) bool {
+			if exemplars[a].Ts != exemplars[b].Ts {
+				return exemplars[a].Ts < exemplars[b].Ts
+			}
+			if c := labels.Compare(exemplars[a].Labels, exemplars[b].Labels); c != 0 {
+				return c < 0
+			}
+			return exemplars[a].Value < exemplars[b].Value
+		})
+
+		deduped := exemplars[:0]
+		for _, e := range exemplars {
+			if n := len(deduped); n > 0 && deduped[n-1].Ts == e.Ts && deduped[n-1].Value == e.Value && labels.Equal(deduped[n-1].Labels, e.Labels) {
+				continue
+			}
+			deduped = append(deduped, e)
+		}
+		result[i].Exemplars = deduped
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return labels.Compare(result[i].SeriesLabels, result[j].SeriesLabels) < 0
+	})
+	return result
+}
+
+// MatchesAnyMatchersSet returns whether the labels match all the matchers of any of the sets of matchers.
+func MatchesAnyMatchersSet(lbls labels.Labels, matchersSets [][]*labels.Matcher) bool {
+	for _, matchers := range matchersSets {
+		matches := true
+		for _, m := range matchers {
+			if !m.Matches(lbls.Get(m.Name)) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package block
+
+import (
+	"bytes"
+	"context"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+)
+
+func TestWriteAndReadExemplars(t *testing.T) {
+	series := []exemplar.QueryResult{
+		{
+			SeriesLabels: labels.FromStrings("__name__", "metric", "job", "b"),
+			Exemplars: []exemplar.Exemplar{
+				{Labels: labels.FromStrings("trace_id", "3"), Value: 3, Ts: 300},
+			},
+		},
+		{
+			SeriesLabels: labels.FromStrings("__name__", "metric", "job", "a"),
+			Exemplars: []exemplar.Exemplar{
+				{Labels: labels.FromStrings("trace_id", "1"), Value: 1, Ts: 100},
+				{Labels: labels.FromStrings("trace_id", "2"), Value: 2, Ts: 200},
+			},
+		},
+		{
+			// Series without exemplars are skipped.
+			SeriesLabels: labels.FromStrings("__name__", "metric", "job", "c"),
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteExemplars(&buf, series))
+
+	actual, err := ReadExemplars(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, []exemplar.QueryResult{series[0], series[1]}, actual)
+	assert.Equal(t, labels.FromStrings("__name__", "metric", "job", "a"), actual[0].SeriesLabels)
+
+	t.Run("unexpected format version", func(t *testing.T) {
+		_, err := ReadExemplars(bytes.NewReader([]byte{2}))
+		require.EqualError(t, err, "unexpected exemplars format version 2")
+	})
+}
+
+func TestExemplarsFile(t *testing.T) {
+	ctx := context.Background()
+	logger := log.NewNopLogger()
+	dir := t.TempDir()
+	bkt := objstore.NewInMemBucket()
+
+	id, err := CreateBlock(ctx, dir, []labels.Labels{
+		labels.FromStrings("a", "1"),
+		labels.FromStrings("a", "2"),
+		labels.FromStrings("a", "3"),
+	}, 100, 0, 1000, labels.FromStrings("ext1", "val1"))
+	require.NoError(t, err)
+	bdir := filepath.Join(dir, id.String())
+
+	// A block without exemplars.
+	series, err := ReadExemplarsFromDir(bdir)
+	require.NoError(t, err)
+	assert.Empty(t, series)
+
+	require.NoError(t, Upload(ctx, logger, bkt, bdir, nil))
+	meta, err := DownloadMeta(ctx, logger, bkt, id)
+	require.NoError(t, err)
+	assert.False(t, HasExemplars(&meta))
+
+	series, err = DownloadExemplars(ctx, logger, bkt, id)
+	require.NoError(t, err)
+	assert.Empty(t, series)
+
+	// A block with exemplars.
+	expected := []exemplar.QueryResult{{
+		SeriesLabels: labels.FromStrings("a", "1"),
+		Exemplars:    []exemplar.Exemplar{{Labels: labels.FromStrings("trace_id", "1"), Value: 1, Ts: 100}},
+	}}
+	require.NoError(t, WriteExemplarsFile(logger, bdir, expected))
+
+	series, err = ReadExemplarsFromDir(bdir)
+	require.NoError(t, err)
+	assert.Equal(t, expected, series)
+
+	require.NoError(t, Delete(ctx, logger, bkt, id))
+	require.NoError(t, Upload(ctx, logger, bkt, bdir, nil))
+	meta, err = DownloadMeta(ctx, logger, bkt, id)
+	require.NoError(t, err)
+	assert.True(t, HasExemplars(&meta))
+
+	exists, err := bkt.Exists(ctx, path.Join(id.String(), ExemplarsFilename))
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	series, err = DownloadExemplars(ctx, logger, bkt, id)
+	require.NoError(t, err)
+	assert.Equal(t, expected, series)
+}
+
+func TestMergeExemplars(t *testing.T) {
+	seriesA := labels.FromStrings("__name__", "metric", "job", "a")
+	seriesB := labels.FromStrings("__name__", "metric", "job", "b")
+	e := func(traceID string, ts int64) exemplar.Exemplar {
+		return exemplar.Exemplar{Labels: labels.FromStrings("trace_id", traceID), Value: float64(ts), Ts: ts}
+	}
+
+	actual := MergeExemplars(
+		[]exemplar.QueryResult{
+			{SeriesLabels: seriesB, Exemplars: []exemplar.Exemplar{e("1", 100)}},
+			{SeriesLabels: seriesA, Exemplars: []exemplar.Exemplar{e("2", 200), e("1", 100)}},
+		},
+		[]exemplar.QueryResult{
+			{SeriesLabels: seriesA, Exemplars: []exemplar.Exemplar{e("3", 200), e("2", 200), e("4", 50)}},
+		},
+		nil,
+	)
+
+	assert.Equal(t, []exemplar.QueryResult{
+		{SeriesLabels: seriesA, Exemplars: []exemplar.Exemplar{e("4", 50), e("1", 100), e("2", 200), e("3", 200)}},
+		{SeriesLabels: seriesB, Exemplars: []exemplar.Exemplar{e("1", 100)}},
+	}, actual)
+
+	assert.Empty(t, MergeExemplars())
+}
@@ -76,6 +76,10 @@ const (
 	OutOfOrderChunksNoCompactReason = "block-index-out-of-order-chunk"
 	// CriticalNoCompactReason is a reason of to no compact block that has some critical issue (e.g. corrupted index).
 	CriticalNoCompactReason = "critical"
+	// RetentionRulesNoCompactReason is a reason to not compact a block while it's rewritten by the retention rules.
+	RetentionRulesNoCompactReason = "retention-rules"
+	// SeriesDeletionNoCompactReason is a reason to not compact a block while it's rewritten by the series deletion requests.
+	SeriesDeletionNoCompactReason = "series-deletion"
 )
 
 // NoCompactMark marker stores reason of block being excluded from compaction if needed.
@@ -73,6 +73,14 @@ type ThanosMeta struct {
 	// Useful to avoid API call to get size of each file, as well as for debugging purposes.
 	// Optional, added in v0.17.0.
 	Files []File `json:"files,omitempty"`
+
+	// RetentionRules is the sorted list of the matchers of the retention rules already applied to the block,
+	// which dropped the series matching them. Optional.
+	RetentionRules []string `json:"retention_rules,omitempty"`
+
+	// SeriesDeletionRequests is the sorted list of the IDs of the series deletion requests already applied to
+	// the block, which purged the samples deleted by them. Optional.
+	SeriesDeletionRequests []string `json:"series_deletion_requests,omitempty"`
 }
 
 type Matchers []*labels.Matcher
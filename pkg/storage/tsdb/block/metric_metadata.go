@@ -0,0 +1,183 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package block
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/runutil"
+	"github.com/oklog/ulid"
+	"github.com/pkg/errors"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+)
+
+// MetricMetadataFormatV1 is the only supported format of the metric metadata file: a JSON object holding the format
+// version and the metadata of the block metrics, sorted by metric family name.
+const MetricMetadataFormatV1 = 1
+
+type metricMetadataFile struct {
+	Version  int                      `json:"version"`
+	Metadata []mimirpb.MetricMetadata `json:"metadata"`
+}
+
+// HasMetricMetadata returns whether the files of the block include the metric metadata file.
+func HasMetricMetadata(meta *Meta) bool {
+	for _, f := range meta.Thanos.Files {
+		if f.RelPath == MetricMetadataFilename {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteMetricMetadataFile writes the metric metadata to the metric metadata file of the block directory. The file
+// isn't written if there is no metadata.
+func WriteMetricMetadataFile(logger log.Logger, dir string, metadata []mimirpb.MetricMetadata) error {
+	if len(metadata) == 0 {
+		return nil
+	}
+
+	// Make any changes to the file appear atomic.
+	path := filepath.Join(dir, MetricMetadataFilename)
+	tmp := path + ".tmp"
+
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+
+	if err := WriteMetricMetadata(f, metadata); err != nil {
+		runutil.CloseWithLogOnErr(logger, f, "close metric metadata")
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return renameFile(logger, tmp, path)
+}
+
+// WriteMetricMetadata writes the metric metadata to the writer, in the metric metadata file format. The duplicated
+// metadata is removed.
+func WriteMetricMetadata(w io.Writer, metadata []mimirpb.MetricMetadata) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(&metricMetadataFile{
+		Version:  MetricMetadataFormatV1,
+		Metadata: MergeMetricMetadata(metadata),
+	})
+}
+
+// ReadMetricMetadataFromDir reads the metric metadata file of the block directory. Returns no metadata if the block
+// has no metric metadata file.
+func ReadMetricMetadataFromDir(dir string) ([]mimirpb.MetricMetadata, error) {
+	f, err := os.Open(filepath.Join(dir, MetricMetadataFilename))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ReadMetricMetadata(f)
+}
+
+// DownloadMetricMetadata reads the metric metadata file of the block from the bucket. Returns no metadata if the
+// block has no metric metadata file.
+func DownloadMetricMetadata(ctx context.Context, logger log.Logger, bkt objstore.BucketReader, id ulid.ULID) ([]mimirpb.MetricMetadata, error) {
+	rc, err := bkt.Get(ctx, path.Join(id.String(), MetricMetadataFilename))
+	if bkt.IsObjNotFoundErr(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "metric metadata bkt get for %s", id.String())
+	}
+	defer runutil.CloseWithLogOnErr(logger, rc, "download metric metadata bucket client")
+
+	return ReadMetricMetadata(rc)
+}
+
+// ReadMetricMetadata reads the metric metadata from the reader, in the metric metadata file format.
+func ReadMetricMetadata(r io.Reader) ([]mimirpb.MetricMetadata, error) {
+	var file metricMetadataFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, errors.Wrap(err, "decode metric metadata")
+	}
+	if file.Version != MetricMetadataFormatV1 {
+		return nil, errors.Errorf("unexpected metric metadata format version %d", file.Version)
+	}
+	return file.Metadata, nil
+}
+
+// MergeMetricMetadata merges the sets of metric metadata, removing the duplicated metadata. The merged metadata is
+// sorted by metric family name.
+func MergeMetricMetadata(sets ...[]mimirpb.MetricMetadata) []mimirpb.MetricMetadata {
+	var (
+		result []mimirpb.MetricMetadata
+		unique = map[mimirpb.MetricMetadata]struct{}{}
+	)
+	for _, set := range sets {
+		for _, m := range set {
+			if _, ok := unique[m]; ok {
+				continue
+			}
+			unique[m] = struct{}{}
+			result = append(result, m)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.MetricFamilyName != b.MetricFamilyName {
+			return a.MetricFamilyName < b.MetricFamilyName
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Help != b.Help {
+			return a.Help < b.Help
+		}
+		return a.Unit < b.Unit
+	})
+	return result
+}
+
+// LimitMetricMetadata returns at most limit metric families of the metadata, sorted by metric family name, and at
+// most limitPerMetric metadata for each metric family. No limit is applied if limit or limitPerMetric is 0.
+func LimitMetricMetadata(metadata []mimirpb.MetricMetadata, limit, limitPerMetric int) []mimirpb.MetricMetadata {
+	if limit <= 0 && limitPerMetric <= 0 {
+		return metadata
+	}
+
+	var (
+		result         []mimirpb.MetricMetadata
+		families       int
+		familyMetadata int
+		lastFamilyName string
+	)
+	for _, m := range metadata {
+		if families == 0 || m.MetricFamilyName != lastFamilyName {
+			if limit > 0 && families >= limit {
+				break
+			}
+			families++
+			familyMetadata = 0
+			lastFamilyName = m.MetricFamilyName
+		}
+		if limitPerMetric > 0 && familyMetadata >= limitPerMetric {
+			continue
+		}
+		familyMetadata++
+		result = append(result, m)
+	}
+	return result
+}
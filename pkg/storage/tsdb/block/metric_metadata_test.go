@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package block
+
+import (
+	"bytes"
+	"context"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/mimir/pkg/mimirpb"
+)
+
+func TestWriteAndReadMetricMetadata(t *testing.T) {
+	metadata := []mimirpb.MetricMetadata{
+		{Type: mimirpb.GAUGE, MetricFamilyName: "metric_b", Help: "Help of b."},
+		{Type: mimirpb.COUNTER, MetricFamilyName: "metric_a", Help: "Help of a.", Unit: "seconds"},
+		{Type: mimirpb.GAUGE, MetricFamilyName: "metric_b", Help: "Help of b."},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteMetricMetadata(&buf, metadata))
+
+	actual, err := ReadMetricMetadata(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, []mimirpb.MetricMetadata{metadata[1], metadata[0]}, actual)
+
+	t.Run("unexpected format version", func(t *testing.T) {
+		_, err := ReadMetricMetadata(bytes.NewReader([]byte(`{"version":2}`)))
+		require.EqualError(t, err, "unexpected metric metadata format version 2")
+	})
+}
+
+func TestMetricMetadataFile(t *testing.T) {
+	ctx := context.Background()
+	logger := log.NewNopLogger()
+	dir := t.TempDir()
+	bkt := objstore.NewInMemBucket()
+
+	id, err := CreateBlock(ctx, dir, []labels.Labels{
+		labels.FromStrings("__name__", "metric_a"),
+		labels.FromStrings("__name__", "metric_b"),
+		labels.FromStrings("__name__", "metric_c"),
+	}, 100, 0, 1000, labels.FromStrings("ext1", "val1"))
+	require.NoError(t, err)
+	bdir := filepath.Join(dir, id.String())
+
+	// A block without metric metadata.
+	metadata, err := ReadMetricMetadataFromDir(bdir)
+	require.NoError(t, err)
+	assert.Empty(t, metadata)
+
+	require.NoError(t, Upload(ctx, logger, bkt, bdir, nil))
+	meta, err := DownloadMeta(ctx, logger, bkt, id)
+	require.NoError(t, err)
+	assert.False(t, HasMetricMetadata(&meta))
+
+	metadata, err = DownloadMetricMetadata(ctx, logger, bkt, id)
+	require.NoError(t, err)
+	assert.Empty(t, metadata)
+
+	// A block with metric metadata.
+	expected := []mimirpb.MetricMetadata{{Type: mimirpb.COUNTER, MetricFamilyName: "metric_a", Help: "Help of a."}}
+	require.NoError(t, WriteMetricMetadataFile(logger, bdir, expected))
+
+	metadata, err = ReadMetricMetadataFromDir(bdir)
+	require.NoError(t, err)
+	assert.Equal(t, expected, metadata)
+
+	require.NoError(t, Delete(ctx, logger, bkt, id))
+	require.NoError(t, Upload(ctx, logger, bkt, bdir, nil))
+	meta, err = DownloadMeta(ctx, logger, bkt, id)
+	require.NoError(t, err)
+	assert.True(t, HasMetricMetadata(&meta))
+
+	exists, err := bkt.Exists(ctx, path.Join(id.String(), MetricMetadataFilename))
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	metadata, err = DownloadMetricMetadata(ctx, logger, bkt, id)
+	require.NoError(t, err)
+	assert.Equal(t, expected, metadata)
+}
+
+func TestMergeMetricMetadata(t *testing.T) {
+	a := mimirpb.MetricMetadata{Type: mimirpb.COUNTER, MetricFamilyName: "metric_a", Help: "Help of a."}
+	a2 := mimirpb.MetricMetadata{Type: mimirpb.COUNTER, MetricFamilyName: "metric_a", Help: "Another help of a."}
+	b := mimirpb.MetricMetadata{Type: mimirpb.GAUGE, MetricFamilyName: "metric_b", Help: "Help of b."}
+
+	assert.Equal(t, []mimirpb.MetricMetadata{a2, a, b}, MergeMetricMetadata([]mimirpb.MetricMetadata{b, a}, nil, []mimirpb.MetricMetadata{a, a2}))
+	assert.Empty(t, MergeMetricMetadata())
+}
+
+func TestLimitMetricMetadata(t *testing.T) {
+	a := mimirpb.MetricMetadata{Type: mimirpb.COUNTER, MetricFamilyName: "metric_a", Help: "Help of a."}
+	a2 := mimirpb.MetricMetadata{Type: mimirpb.COUNTER, MetricFamilyName: "metric_a", Help: "Another help of a."}
+	b := mimirpb.MetricMetadata{Type: mimirpb.GAUGE, MetricFamilyName: "metric_b", Help: "Help of b."}
+	c := mimirpb.MetricMetadata{Type: mimirpb.GAUGE, MetricFamilyName: "metric_c", Help: "Help of c."}
+	metadata := MergeMetricMetadata([]mimirpb.MetricMetadata{a, a2, b, c})
+
+	assert.Equal(t, []mimirpb.MetricMetadata{a2, a, b, c}, LimitMetricMetadata(metadata, 0, 0))
+	assert.Equal(t, []mimirpb.MetricMetadata{a2, a, b}, LimitMetricMetadata(metadata, 2, 0))
+	assert.Equal(t, []mimirpb.MetricMetadata{a2, b, c}, LimitMetricMetadata(metadata, 0, 1))
+	assert.Equal(t, []mimirpb.MetricMetadata{a2}, LimitMetricMetadata(metadata, 1, 1))
+	assert.Empty(t, LimitMetricMetadata(nil, 1, 1))
+}
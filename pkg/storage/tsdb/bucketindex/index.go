@@ -42,6 +42,9 @@ type Index struct {
 	// List of block deletion marks.
 	BlockDeletionMarks BlockDeletionMarks `json:"block_deletion_marks"`
 
+	// List of series deletion requests.
+	SeriesDeletionRequests []*mimir_tsdb.SeriesDeletionRequest `json:"series_deletion_requests,omitempty"`
+
 	// UpdatedAt is a unix timestamp (seconds precision) of when the index has been updated
 	// (written in the storage) the last time.
 	UpdatedAt int64 `json:"updated_at"`
@@ -97,6 +100,15 @@ type Block struct {
 
 	// Whether the block was from out of order samples
 	OutOfOrder bool `json:"out_of_order,omitempty"`
+
+	// Downsampling resolution of the block (millis precision), 0 for raw blocks.
+	Resolution int64 `json:"resolution,omitempty"`
+
+	// Sorted matchers of the retention rules already applied to the block.
+	RetentionRules []string `json:"retention_rules,omitempty"`
+
+	// Sorted IDs of the series deletion requests already applied to the block.
+	SeriesDeletionRequests []string `json:"series_deletion_requests,omitempty"`
 }
 
 // Within returns whether the block contains samples within the provided range.
@@ -130,9 +142,12 @@ func (m *Block) ThanosMeta() *block.Meta {
 			},
 		},
 		Thanos: block.ThanosMeta{
-			Version:      block.ThanosVersion1,
-			SegmentFiles: m.thanosMetaSegmentFiles(),
-			Source:       block.SourceType(m.Source),
+			Version:                block.ThanosVersion1,
+			SegmentFiles:           m.thanosMetaSegmentFiles(),
+			Source:                 block.SourceType(m.Source),
+			Downsample:             block.ThanosDownsample{Resolution: m.Resolution},
+			RetentionRules:         m.RetentionRules,
+			SeriesDeletionRequests: m.SeriesDeletionRequests,
 		},
 	}
 }
@@ -172,6 +187,10 @@ func BlockFromThanosMeta(meta block.Meta) *Block {
 		Source:           string(meta.Thanos.Source),
 		CompactionLevel:  meta.Compaction.Level,
 		OutOfOrder:       meta.Compaction.FromOutOfOrder(),
+		Resolution:       meta.Thanos.Downsample.Resolution,
+		RetentionRules:   meta.Thanos.RetentionRules,
+		// The series deletion requests applied to the block are recorded in its meta when it's rewritten.
+		SeriesDeletionRequests: meta.Thanos.SeriesDeletionRequests,
 	}
 }
 
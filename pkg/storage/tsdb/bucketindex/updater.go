@@ -20,6 +20,7 @@ import (
 	"github.com/thanos-io/objstore"
 
 	"github.com/grafana/mimir/pkg/storage/bucket"
+	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
 	"github.com/grafana/mimir/pkg/storage/tsdb/block"
 )
 
@@ -65,11 +66,18 @@ func (w *Updater) UpdateIndex(ctx context.Context, old *Index) (*Index, map[ulid
 		return nil, nil, err
 	}
 
+	// Series deletion requests are few and small, so we always read all of them.
+	seriesDeletionRequests, err := mimir_tsdb.ListSeriesDeletionRequests(ctx, w.bkt)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	return &Index{
-		Version:            IndexVersion2,
-		Blocks:             blocks,
-		BlockDeletionMarks: blockDeletionMarks,
-		UpdatedAt:          time.Now().Unix(),
+		Version:                IndexVersion2,
+		Blocks:                 blocks,
+		BlockDeletionMarks:     blockDeletionMarks,
+		SeriesDeletionRequests: seriesDeletionRequests,
+		UpdatedAt:              time.Now().Unix(),
 	}, partials, nil
 }
 
@@ -175,6 +175,33 @@ func TestUpdater_UpdateIndex_NoTenantInTheBucket(t *testing.T) {
 	}
 }
 
+func TestUpdater_UpdateIndex_ShouldIncludeSeriesDeletionRequests(t *testing.T) {
+	const userID = "user-1"
+
+	ctx := context.Background()
+	bkt, _ := testutil.PrepareFilesystemBucket(t)
+	userBkt := bucket.NewUserBucketClient(userID, bkt, nil)
+
+	block1 := block.MockStorageBlockWithExtLabels(t, bkt, userID, 10, 20, nil)
+	req, err := mimir_tsdb.NewSeriesDeletionRequest([]string{`{job="test"}`}, 10, 15, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, mimir_tsdb.WriteSeriesDeletionRequest(ctx, userBkt, req))
+
+	w := NewUpdater(bkt, userID, nil, log.NewNopLogger())
+	idx, _, err := w.UpdateIndex(ctx, nil)
+	require.NoError(t, err)
+	assertBucketIndexEqual(t, idx, bkt, userID, []block.Meta{block1}, []*block.DeletionMark{})
+	assert.Equal(t, []*mimir_tsdb.SeriesDeletionRequest{req}, idx.SeriesDeletionRequests)
+
+	// Cancel the request and update the index.
+	_, err = mimir_tsdb.DeleteSeriesDeletionRequest(ctx, userBkt, req.RequestID)
+	require.NoError(t, err)
+
+	idx, _, err = w.UpdateIndex(ctx, idx)
+	require.NoError(t, err)
+	assert.Empty(t, idx.SeriesDeletionRequests)
+}
+
 func TestUpdater_UpdateIndexFromVersion1ToVersion2(t *testing.T) {
 	const userID = "user-1"
 
@@ -199,6 +199,8 @@ type TSDBConfig struct {
 	Retention                 time.Duration `yaml:"retention_period"`
 	ShipInterval              time.Duration `yaml:"ship_interval" category:"advanced"`
 	ShipConcurrency           int           `yaml:"ship_concurrency" category:"advanced"`
+	ShipExemplarsEnabled      bool          `yaml:"ship_exemplars_enabled" category:"experimental"`
+	ShipMetricMetadataEnabled bool          `yaml:"ship_metric_metadata_enabled" category:"experimental"`
 	HeadCompactionInterval    time.Duration `yaml:"head_compaction_interval" category:"advanced"`
 	HeadCompactionConcurrency int           `yaml:"head_compaction_concurrency" category:"advanced"`
 	HeadCompactionIdleTimeout time.Duration `yaml:"head_compaction_idle_timeout" category:"advanced"`
@@ -281,6 +283,8 @@ func (cfg *TSDBConfig) RegisterFlags(f *flag.FlagSet) {
 	f.DurationVar(&cfg.Retention, "blocks-storage.tsdb.retention-period", 13*time.Hour, "TSDB blocks retention in the ingester before a block is removed. If shipping is enabled, the retention will be relative to the time when the block was uploaded to storage. If shipping is disabled then its relative to the creation time of the block. This should be larger than the -blocks-storage.tsdb.block-ranges-period, -querier.query-store-after and large enough to give store-gateways and queriers enough time to discover newly uploaded blocks.")
 	f.DurationVar(&cfg.ShipInterval, "blocks-storage.tsdb.ship-interval", 1*time.Minute, "How frequently the TSDB blocks are scanned and new ones are shipped to the storage. 0 means shipping is disabled.")
 	f.IntVar(&cfg.ShipConcurrency, "blocks-storage.tsdb.ship-concurrency", 10, "Maximum number of tenants concurrently shipping blocks to the storage.")
+	f.BoolVar(&cfg.ShipExemplarsEnabled, "blocks-storage.tsdb.ship-exemplars-enabled", false, "If enabled, the exemplars within the time range of each block are shipped to the storage along with the block, so that they can be queried from the store-gateways once evicted from the ingesters.")
+	f.BoolVar(&cfg.ShipMetricMetadataEnabled, "blocks-storage.tsdb.ship-metric-metadata-enabled", false, "If enabled, the metric metadata of the metrics within each block is shipped to the storage along with the block, so that it can be queried from the store-gateways once purged from the ingesters.")
 
 	// This cache is only used when querying compacted blocks. The default cache size is enough to store the hashes for
 	// all series in all queryable blocks, assuming 2M series per ingester (and default retention):
@@ -0,0 +1,374 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+// Package downsample implements the downsampling of TSDB blocks. For each series of the original block,
+// a downsampled block contains one series per aggregate, identified by the AggrLabel label.
+package downsample
+
+import (
+	"context"
+	"math"
+	"path/filepath"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/oklog/ulid"
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/prometheus/prometheus/tsdb/chunkenc"
+
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
+)
+
+const (
+	// ResLevel0 is the resolution of raw blocks.
+	ResLevel0 = int64(0)
+	// ResLevel1 is the resolution of the blocks downsampled from raw blocks.
+	ResLevel1 = int64(5 * time.Minute / time.Millisecond)
+	// ResLevel2 is the resolution of the blocks downsampled from ResLevel1 blocks.
+	ResLevel2 = int64(time.Hour / time.Millisecond)
+
+	// AggrLabel is the name of the label holding the aggregate of a series of a downsampled block.
+	AggrLabel = "__aggr__"
+)
+
+// AggrType is an aggregate of the samples of a series over a window of the downsampling resolution.
+type AggrType uint8
+
+const (
+	// AggrCount is the number of float samples.
+	AggrCount AggrType = iota
+	// AggrSum is the sum of the float samples, or of the native histogram samples.
+	AggrSum
+	// AggrMin is the minimum of the float samples.
+	AggrMin
+	// AggrMax is the maximum of the float samples.
+	AggrMax
+	// AggrCounter is the last sample, plus the last sample before each counter reset.
+	AggrCounter
+)
+
+// AggrTypes are all the aggregates of a downsampled block.
+var AggrTypes = []AggrType{AggrCount, AggrSum, AggrMin, AggrMax, AggrCounter}
+
+func (a AggrType) String() string {
+	switch a {
+	case AggrCount:
+		return "count"
+	case AggrSum:
+		return "sum"
+	case AggrMin:
+		return "min"
+	case AggrMax:
+		return "max"
+	case AggrCounter:
+		return "counter"
+	}
+	return "unknown"
+}
+
+// ParseAggrType returns the aggregate with the given name.
+func ParseAggrType(s string) (AggrType, bool) {
+	for _, a := range AggrTypes {
+		if a.String() == s {
+			return a, true
+		}
+	}
+	return 0, false
+}
+
+// WithoutAggrLabel returns the series labels without the AggrLabel label.
+func WithoutAggrLabel(lset labels.Labels) labels.Labels {
+	if !lset.Has(AggrLabel) {
+		return lset
+	}
+	return labels.NewBuilder(lset).Del(AggrLabel).Labels()
+}
+
+// ResolutionString returns the human-readable form of a resolution.
+func ResolutionString(resolution int64) string {
+	if resolution == ResLevel0 {
+		return "raw"
+	}
+	return model.Duration(time.Duration(resolution) * time.Millisecond).String()
+}
+
+// Block writes into dir a new block with the samples of the block b, described by meta, aggregated over windows
+// of the given resolution. The samples of a raw block are aggregated into all the AggrTypes, while the samples of
+// a downsampled block are aggregated into the aggregate of their series. The new block keeps the time range, the
+// external labels and the compaction sources of the original block.
+// Returns an empty ID if the new block would have no samples.
+func Block(ctx context.Context, logger log.Logger, meta *block.Meta, b tsdb.BlockReader, dir string, resolution int64) (ulid.ULID, error) {
+	origResolution := meta.Thanos.Downsample.Resolution
+	if resolution <= origResolution {
+		return ulid.ULID{}, errors.Errorf("cannot downsample block %s with resolution %d to resolution %d", meta.ULID, origResolution, resolution)
+	}
+
+	q, err := tsdb.NewBlockQuerier(b, meta.MinTime, meta.MaxTime)
+	if err != nil {
+		return ulid.ULID{}, errors.Wrap(err, "create block querier")
+	}
+	defer q.Close()
+
+	// The head of the block writer rejects the samples older than half of its chunk range from the most
+	// recent sample, so the range needs to be large enough to append the series one after the other.
+	w, err := tsdb.NewBlockWriter(logger, dir, 2*(meta.MaxTime-meta.MinTime)+resolution)
+	if err != nil {
+		return ulid.ULID{}, errors.Wrap(err, "create block writer")
+	}
+	defer w.Close()
+
+	var (
+		it       chunkenc.Iterator
+		samples  []sample
+		appended int
+	)
+	set := q.Select(ctx, false, nil, labels.MustNewMatcher(labels.MatchRegexp, labels.MetricName, ".*"))
+	for set.Next() {
+		if err := ctx.Err(); err != nil {
+			return ulid.ULID{}, err
+		}
+
+		s := set.At()
+		it = s.Iterator(it)
+		if samples, err = appendSamples(samples[:0], it); err != nil {
+			return ulid.ULID{}, errors.Wrapf(err, "read samples of series %s", s.Labels())
+		}
+
+		if origResolution == ResLevel0 {
+			for _, aggr := range AggrTypes {
+				lset := labels.NewBuilder(s.Labels()).Set(AggrLabel, aggr.String()).Labels()
+				n, err := appendSeries(ctx, w, lset, aggregate(samples, resolution, aggr, true))
+				if err != nil {
+					return ulid.ULID{}, err
+				}
+				appended += n
+			}
+			continue
+		}
+
+		aggr, ok := ParseAggrType(s.Labels().Get(AggrLabel))
+		if !ok {
+			return ulid.ULID{}, errors.Errorf("series %s of a downsampled block has no valid %s label", s.Labels(), AggrLabel)
+		}
+		n, err := appendSeries(ctx, w, s.Labels(), aggregate(samples, resolution, aggr, false))
+		if err != nil {
+			return ulid.ULID{}, err
+		}
+		appended += n
+	}
+	if err := set.Err(); err != nil {
+		return ulid.ULID{}, errors.Wrap(err, "read series")
+	}
+	if appended == 0 {
+		return ulid.ULID{}, nil
+	}
+
+	id, err := w.Flush(ctx)
+	if err != nil {
+		return ulid.ULID{}, errors.Wrap(err, "write downsampled block")
+	}
+	if id == (ulid.ULID{}) {
+		return id, nil
+	}
+
+	bdir := filepath.Join(dir, id.String())
+	newMeta, err := block.InjectThanosMeta(logger, bdir, block.ThanosMeta{
+		Labels:       meta.Thanos.Labels,
+		Downsample:   block.ThanosDownsample{Resolution: resolution},
+		Source:       block.CompactorSource,
+		SegmentFiles: block.GetSegmentFiles(bdir),
+	}, &meta.BlockMeta)
+	if err != nil {
+		return ulid.ULID{}, errors.Wrapf(err, "failed to finalize the block %s", bdir)
+	}
+
+	// The downsampled samples don't necessarily start and end at the same timestamps as the original ones.
+	newMeta.MinTime = meta.MinTime
+	newMeta.MaxTime = meta.MaxTime
+	if err := newMeta.WriteToDir(logger, bdir); err != nil {
+		return ulid.ULID{}, errors.Wrap(err, "write meta")
+	}
+	return id, nil
+}
+
+// sample is either a float sample or a native histogram sample.
+type sample struct {
+	t  int64
+	f  float64
+	fh *histogram.FloatHistogram
+}
+
+// appendSamples appends the samples of the iterator to dst, skipping the stale markers.
+func appendSamples(dst []sample, it chunkenc.Iterator) ([]sample, error) {
+	for vt := it.Next(); vt != chunkenc.ValNone; vt = it.Next() {
+		switch vt {
+		case chunkenc.ValFloat:
+			t, f := it.At()
+			if value.IsStaleNaN(f) {
+				continue
+			}
+			dst = append(dst, sample{t: t, f: f})
+		case chunkenc.ValHistogram, chunkenc.ValFloatHistogram:
+			t, fh := it.AtFloatHistogram(nil)
+			if value.IsStaleNaN(fh.Sum) {
+				continue
+			}
+			dst = append(dst, sample{t: t, fh: fh})
+		}
+	}
+	return dst, it.Err()
+}
+
+// appendSeries appends the samples of a series to the block writer, and returns the number of appended samples.
+func appendSeries(ctx context.Context, w *tsdb.BlockWriter, lset labels.Labels, samples []sample) (int, error) {
+	if len(samples) == 0 {
+		return 0, nil
+	}
+
+	app := w.Appender(ctx)
+	var (
+		ref storage.SeriesRef
+		err error
+	)
+	for _, s := range samples {
+		if s.fh != nil {
+			ref, err = app.AppendHistogram(ref, lset, s.t, nil, s.fh)
+		} else {
+			ref, err = app.Append(ref, lset, s.t, s.f)
+		}
+		if err != nil {
+			_ = app.Rollback()
+			return 0, errors.Wrapf(err, "append sample of series %s", lset)
+		}
+	}
+	if err := app.Commit(); err != nil {
+		return 0, errors.Wrapf(err, "commit series %s", lset)
+	}
+	return len(samples), nil
+}
+
+// aggregate returns the samples of the aggregate over windows of the given resolution. The input samples are raw
+// samples if raw is true, otherwise they're the samples of the same aggregate at a lower resolution.
+func aggregate(samples []sample, resolution int64, aggr AggrType, raw bool) []sample {
+	if aggr == AggrCounter {
+		return counterSamples(samples, resolution)
+	}
+
+	var out []sample
+	for start := 0; start < len(samples); {
+		end := start + 1
+		for end < len(samples) && window(samples[end].t, resolution) == window(samples[start].t, resolution) {
+			end++
+		}
+		out = appendWindowAggregate(out, samples[start:end], aggr, raw)
+		start = end
+	}
+	return out
+}
+
+// appendWindowAggregate appends the aggregate of the samples of one window, at the timestamp of the last aggregated
+// sample. The float samples and the native histogram samples of the window are aggregated separately.
+func appendWindowAggregate(out []sample, samples []sample, aggr AggrType, raw bool) []sample {
+	var (
+		f, fh       sample
+		hasF, hasFH bool
+	)
+	for _, s := range samples {
+		if s.fh != nil {
+			// Only the sum is meaningful for native histograms.
+			if aggr != AggrSum {
+				continue
+			}
+			if !hasFH {
+				fh.fh = s.fh.Copy()
+			} else {
+				fh.fh = fh.fh.Add(s.fh)
+			}
+			fh.t, hasFH = s.t, true
+			continue
+		}
+
+		v := s.f
+		if aggr == AggrCount && raw {
+			v = 1
+		}
+		switch {
+		case !hasF:
+			f.f = v
+		case aggr == AggrCount || aggr == AggrSum:
+			f.f += v
+		case aggr == AggrMin:
+			f.f = math.Min(f.f, v)
+		case aggr == AggrMax:
+			f.f = math.Max(f.f, v)
+		}
+		f.t, hasF = s.t, true
+	}
+
+	if hasFH {
+		resetCounterResetHint(fh.fh)
+	}
+	switch {
+	case hasF && hasFH && fh.t < f.t:
+		return append(out, fh, f)
+	case hasF:
+		return append(out, f)
+	case hasFH:
+		return append(out, fh)
+	}
+	return out
+}
+
+// counterSamples returns the last sample of each window, and the last sample before each counter reset, so that the
+// increase computed from the returned samples is the same as the increase computed from the input samples.
+func counterSamples(samples []sample, resolution int64) []sample {
+	var out []sample
+	for i, s := range samples {
+		if i > 0 && isCounterReset(samples[i-1], s) && (len(out) == 0 || out[len(out)-1].t != samples[i-1].t) {
+			out = appendCounterSample(out, samples[i-1])
+		}
+		if i == len(samples)-1 || window(samples[i+1].t, resolution) != window(s.t, resolution) {
+			out = appendCounterSample(out, s)
+		}
+	}
+	return out
+}
+
+func appendCounterSample(out []sample, s sample) []sample {
+	if s.fh != nil {
+		// The counter reset hint of the sample may not apply to the previous sample anymore.
+		s.fh = s.fh.Copy()
+		resetCounterResetHint(s.fh)
+	}
+	return append(out, s)
+}
+
+func isCounterReset(prev, cur sample) bool {
+	switch {
+	case prev.fh == nil && cur.fh == nil:
+		return cur.f < prev.f
+	case prev.fh != nil && cur.fh != nil:
+		return cur.fh.DetectReset(prev.fh)
+	}
+	return false
+}
+
+func resetCounterResetHint(fh *histogram.FloatHistogram) {
+	if fh.CounterResetHint != histogram.GaugeType {
+		fh.CounterResetHint = histogram.UnknownCounterReset
+	}
+}
+
+// window returns the index of the window of the given resolution containing the timestamp.
+func window(t, resolution int64) int64 {
+	w := t / resolution
+	if t < 0 && t%resolution != 0 {
+		w--
+	}
+	return w
+}
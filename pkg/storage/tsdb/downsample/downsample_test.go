@@ -0,0 +1,258 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package downsample
+
+import (
+	"context"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/prometheus/prometheus/tsdb/chunkenc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/mimir/pkg/storage/tsdb/block"
+)
+
+const minute = int64(time.Minute / time.Millisecond)
+
+func TestAggregate(t *testing.T) {
+	samples := []sample{
+		{t: 0, f: 4},
+		{t: 1 * minute, f: 2},
+		{t: 4 * minute, f: 6},
+		{t: 5 * minute, f: 1},
+		{t: 9 * minute, f: 3},
+		{t: 20 * minute, f: 5},
+	}
+
+	tests := map[AggrType][]sample{
+		AggrCount: {{t: 4 * minute, f: 3}, {t: 9 * minute, f: 2}, {t: 20 * minute, f: 1}},
+		AggrSum:   {{t: 4 * minute, f: 12}, {t: 9 * minute, f: 4}, {t: 20 * minute, f: 5}},
+		AggrMin:   {{t: 4 * minute, f: 2}, {t: 9 * minute, f: 1}, {t: 20 * minute, f: 5}},
+		AggrMax:   {{t: 4 * minute, f: 6}, {t: 9 * minute, f: 3}, {t: 20 * minute, f: 5}},
+	}
+
+	for aggr, expected := range tests {
+		t.Run(aggr.String(), func(t *testing.T) {
+			assert.Equal(t, expected, aggregate(samples, ResLevel1, aggr, true))
+		})
+	}
+
+	t.Run("count of a downsampled series", func(t *testing.T) {
+		counts := []sample{{t: 4 * minute, f: 3}, {t: 9 * minute, f: 2}, {t: 20 * minute, f: 1}}
+		assert.Equal(t, []sample{{t: 20 * minute, f: 6}}, aggregate(counts, ResLevel2, AggrCount, false))
+	})
+}
+
+func TestAggregate_Counter(t *testing.T) {
+	samples := []sample{
+		{t: 0, f: 1},
+		{t: 2 * minute, f: 5},
+		{t: 4 * minute, f: 8},
+		// Counter reset within the window.
+		{t: 6 * minute, f: 10},
+		{t: 7 * minute, f: 2},
+		{t: 9 * minute, f: 4},
+		// Counter reset at the first sample of the window.
+		{t: 11 * minute, f: 1},
+		{t: 14 * minute, f: 3},
+	}
+
+	expected := []sample{
+		{t: 4 * minute, f: 8},
+		{t: 6 * minute, f: 10},
+		{t: 9 * minute, f: 4},
+		{t: 14 * minute, f: 3},
+	}
+	assert.Equal(t, expected, aggregate(samples, ResLevel1, AggrCounter, true))
+
+	// The increase is the same as the one of the raw samples, since the first downsampled sample.
+	assert.Equal(t, increase(samples[2:]), increase(expected))
+}
+
+func TestAggregate_NativeHistograms(t *testing.T) {
+	h := func(count float64) *histogram.FloatHistogram {
+		return &histogram.FloatHistogram{
+			Count:           count,
+			Sum:             count,
+			Schema:          0,
+			PositiveSpans:   []histogram.Span{{Offset: 0, Length: 1}},
+			PositiveBuckets: []float64{count},
+		}
+	}
+	samples := []sample{
+		{t: 0, fh: h(1)},
+		{t: 2 * minute, fh: h(3)},
+		{t: 6 * minute, fh: h(4)},
+		{t: 7 * minute, fh: h(1)},
+		{t: 8 * minute, fh: h(2)},
+	}
+
+	assert.Empty(t, aggregate(samples, ResLevel1, AggrMax, true))
+
+	sums := aggregate(samples, ResLevel1, AggrSum, true)
+	require.Len(t, sums, 2)
+	assert.Equal(t, 2*minute, sums[0].t)
+	assert.Equal(t, 4.0, sums[0].fh.Count)
+	assert.Equal(t, 8*minute, sums[1].t)
+	assert.Equal(t, 7.0, sums[1].fh.Count)
+
+	counters := aggregate(samples, ResLevel1, AggrCounter, true)
+	require.Len(t, counters, 3)
+	for i, expected := range []sample{{t: 2 * minute, fh: h(3)}, {t: 6 * minute, fh: h(4)}, {t: 8 * minute, fh: h(2)}} {
+		assert.Equal(t, expected.t, counters[i].t)
+		assert.Equal(t, expected.fh.Count, counters[i].fh.Count)
+	}
+}
+
+func TestBlock(t *testing.T) {
+	ctx := context.Background()
+	logger := log.NewNopLogger()
+	dir := t.TempDir()
+
+	// Create a raw block with 2 series, with one sample per minute during 2 hours.
+	const mint, maxt = int64(0), 120 * minute
+	series := []labels.Labels{
+		labels.FromStrings("__name__", "gauge", "job", "a"),
+		labels.FromStrings("__name__", "counter", "job", "a"),
+	}
+	w, err := tsdb.NewBlockWriter(logger, dir, 2*maxt)
+	require.NoError(t, err)
+	app := w.Appender(ctx)
+	for ts := mint; ts < maxt; ts += minute {
+		_, err := app.Append(0, series[0], ts, float64(ts/minute%10))
+		require.NoError(t, err)
+		_, err = app.Append(0, series[1], ts, float64(ts/minute))
+		require.NoError(t, err)
+	}
+	// A stale marker is ignored.
+	_, err = app.Append(0, series[0], maxt-1, math.Float64frombits(value.StaleNaN))
+	require.NoError(t, err)
+	require.NoError(t, app.Commit())
+	rawID, err := w.Flush(ctx)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	rawDir := filepath.Join(dir, rawID.String())
+	rawMeta, err := block.InjectThanosMeta(logger, rawDir, block.ThanosMeta{
+		Labels: map[string]string{"__compactor_shard_id__": "1_of_2"},
+		Source: block.CompactorSource,
+	}, nil)
+	require.NoError(t, err)
+
+	// Downsample the raw block to 5m, and the 5m block to 1h.
+	level1Meta, level1Dir := downsampleBlock(t, rawMeta, rawDir, ResLevel1)
+	assert.Equal(t, ResLevel1, level1Meta.Thanos.Downsample.Resolution)
+	assert.Equal(t, rawMeta.MinTime, level1Meta.MinTime)
+	assert.Equal(t, rawMeta.MaxTime, level1Meta.MaxTime)
+	assert.Equal(t, rawMeta.Thanos.Labels, level1Meta.Thanos.Labels)
+	assert.Equal(t, rawMeta.Compaction.Sources, level1Meta.Compaction.Sources)
+	assert.Equal(t, uint64(10), level1Meta.Stats.NumSeries)
+
+	assert.Equal(t, map[string][]sample{
+		`{__aggr__="count", __name__="gauge", job="a"}`: repeatSample(5, minute*4, 24),
+		`{__aggr__="max", __name__="gauge", job="a"}`:   alternateSamples(4, 9, 24),
+	}, readSeries(t, level1Dir, `{__name__="gauge", __aggr__=~"count|max"}`))
+	assert.Equal(t, map[string][]sample{
+		`{__aggr__="counter", __name__="counter", job="a"}`: counterSamplesAt(24),
+	}, readSeries(t, level1Dir, `{__name__="counter", __aggr__="counter"}`))
+
+	_, err = Block(ctx, logger, level1Meta, openBlock(t, level1Dir), t.TempDir(), ResLevel1)
+	require.Error(t, err)
+
+	level2Meta, level2Dir := downsampleBlock(t, level1Meta, level1Dir, ResLevel2)
+	assert.Equal(t, ResLevel2, level2Meta.Thanos.Downsample.Resolution)
+	assert.Equal(t, uint64(10), level2Meta.Stats.NumSeries)
+	assert.Equal(t, map[string][]sample{
+		`{__aggr__="count", __name__="gauge", job="a"}`: {{t: 59 * minute, f: 60}, {t: 119 * minute, f: 60}},
+	}, readSeries(t, level2Dir, `{__name__="gauge", __aggr__="count"}`))
+}
+
+func downsampleBlock(t *testing.T, meta *block.Meta, bdir string, resolution int64) (*block.Meta, string) {
+	dir := t.TempDir()
+	id, err := Block(context.Background(), log.NewNopLogger(), meta, openBlock(t, bdir), dir, resolution)
+	require.NoError(t, err)
+
+	newMeta, err := block.ReadMetaFromDir(filepath.Join(dir, id.String()))
+	require.NoError(t, err)
+	require.NoError(t, block.VerifyBlock(context.Background(), log.NewNopLogger(), filepath.Join(dir, id.String()), newMeta.MinTime, newMeta.MaxTime, false))
+	return newMeta, filepath.Join(dir, id.String())
+}
+
+func openBlock(t *testing.T, bdir string) *tsdb.Block {
+	b, err := tsdb.OpenBlock(log.NewNopLogger(), bdir, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, b.Close()) })
+	return b
+}
+
+func readSeries(t *testing.T, bdir, selector string) map[string][]sample {
+	b := openBlock(t, bdir)
+	q, err := tsdb.NewBlockQuerier(b, b.MinTime(), b.MaxTime())
+	require.NoError(t, err)
+	defer q.Close()
+
+	matchers, err := parser.ParseMetricSelector(selector)
+	require.NoError(t, err)
+
+	result := map[string][]sample{}
+	var it chunkenc.Iterator
+	set := q.Select(context.Background(), true, nil, matchers...)
+	for set.Next() {
+		it = set.At().Iterator(it)
+		samples, err := appendSamples(nil, it)
+		require.NoError(t, err)
+		result[set.At().Labels().String()] = samples
+	}
+	require.NoError(t, set.Err())
+	return result
+}
+
+// repeatSample returns n samples with the value v, at the end of each 5m window offset by the given offset.
+func repeatSample(v float64, offset int64, n int) []sample {
+	samples := make([]sample, 0, n)
+	for i := 0; i < n; i++ {
+		samples = append(samples, sample{t: int64(i)*ResLevel1 + offset, f: v})
+	}
+	return samples
+}
+
+// alternateSamples returns n samples at the end of each 5m window, alternating between the values a and b.
+func alternateSamples(a, b float64, n int) []sample {
+	samples := repeatSample(a, 4*minute, n)
+	for i := 1; i < n; i += 2 {
+		samples[i].f = b
+	}
+	return samples
+}
+
+// counterSamplesAt returns the last sample of each 5m window of a counter increasing by 1 every minute.
+func counterSamplesAt(n int) []sample {
+	samples := repeatSample(0, 4*minute, n)
+	for i := range samples {
+		samples[i].f = float64(samples[i].t / minute)
+	}
+	return samples
+}
+
+// increase returns the increase of a float counter, taking into account the counter resets.
+func increase(samples []sample) float64 {
+	var result float64
+	for i := 1; i < len(samples); i++ {
+		if samples[i].f < samples[i-1].f {
+			result += samples[i].f
+		} else {
+			result += samples[i].f - samples[i-1].f
+		}
+	}
+	return result
+}
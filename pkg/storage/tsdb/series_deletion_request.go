@@ -0,0 +1,175 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package tsdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/mimir/pkg/util"
+	util_log "github.com/grafana/mimir/pkg/util/log"
+)
+
+// SeriesDeletionRequestsPath is the location of series deletion requests, relative to user-specific prefix.
+const SeriesDeletionRequestsPath = "markers/series-deletion-requests"
+
+// SeriesDeletionRequest is a request to delete the samples of the series matching any of the selectors,
+// within the time range.
+type SeriesDeletionRequest struct {
+	// Unique ID of the request, computed from the selectors and the time range.
+	RequestID string `json:"request_id"`
+
+	// Series selectors, in the PromQL format. A series is deleted if it matches any of them.
+	Selectors []string `json:"selectors"`
+
+	// StartTime and EndTime specify the time range of the deleted samples (millis precision, inclusive).
+	StartTime int64 `json:"start_time"`
+	EndTime   int64 `json:"end_time"`
+
+	// Unix timestamp when the request was created.
+	CreatedAt util.UnixSeconds `json:"created_at"`
+
+	// Unix timestamp when the deleted samples were purged from all the blocks, or 0 if the request is pending.
+	// The queriers stop honoring the processed requests once the blocks replaced while purging the samples are
+	// no longer queried.
+	ProcessedAt util.UnixSeconds `json:"processed_at,omitempty"`
+}
+
+// NewSeriesDeletionRequest returns a new series deletion request, after having validated the selectors and the time range.
+func NewSeriesDeletionRequest(selectors []string, startTime, endTime int64, createdAt time.Time) (*SeriesDeletionRequest, error) {
+	if len(selectors) == 0 {
+		return nil, errors.New("no series selector provided")
+	}
+	if endTime < startTime {
+		return nil, errors.New("the end time must be greater than or equal to the start time")
+	}
+
+	req := &SeriesDeletionRequest{
+		Selectors: selectors,
+		StartTime: startTime,
+		EndTime:   endTime,
+		CreatedAt: util.UnixSecondsFromTime(createdAt),
+	}
+	if _, err := req.Matchers(); err != nil {
+		return nil, err
+	}
+
+	// The ID is a function of the request content, so that submitting the same request twice doesn't create duplicates.
+	h := fnv.New64a()
+	_, _ = fmt.Fprintf(h, "%s\x00%d\x00%d", strings.Join(selectors, "\x00"), startTime, endTime)
+	req.RequestID = fmt.Sprintf("%016x", h.Sum64())
+
+	return req, nil
+}
+
+// Matchers returns the parsed selectors of the request.
+func (r *SeriesDeletionRequest) Matchers() ([][]*labels.Matcher, error) {
+	out := make([][]*labels.Matcher, 0, len(r.Selectors))
+	for _, selector := range r.Selectors {
+		matchers, err := parser.ParseMetricSelector(selector)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid series selector %q", selector)
+		}
+		out = append(out, matchers)
+	}
+	return out, nil
+}
+
+// Processed returns whether the deleted samples were purged from all the blocks.
+func (r *SeriesDeletionRequest) Processed() bool {
+	return r.ProcessedAt != 0
+}
+
+// Overlaps returns true if the request time range overlaps with the input time range (both inclusive).
+func (r *SeriesDeletionRequest) Overlaps(minT, maxT int64) bool {
+	return r.StartTime <= maxT && minT <= r.EndTime
+}
+
+// SeriesDeletionRequestFilepath returns the path, relative to user-specific prefix, of the series deletion request.
+func SeriesDeletionRequestFilepath(requestID string) string {
+	return path.Join(SeriesDeletionRequestsPath, requestID+".json")
+}
+
+// WriteSeriesDeletionRequest uploads the series deletion request to the input user bucket.
+func WriteSeriesDeletionRequest(ctx context.Context, userBkt objstore.Bucket, req *SeriesDeletionRequest) error {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return errors.Wrap(err, "serialize series deletion request")
+	}
+
+	return errors.Wrap(userBkt.Upload(ctx, SeriesDeletionRequestFilepath(req.RequestID), bytes.NewReader(data)), "upload series deletion request")
+}
+
+// DeleteSeriesDeletionRequest removes the series deletion request from the input user bucket.
+// Returns false and no error if the request doesn't exist.
+func DeleteSeriesDeletionRequest(ctx context.Context, userBkt objstore.Bucket, requestID string) (bool, error) {
+	err := userBkt.Delete(ctx, SeriesDeletionRequestFilepath(requestID))
+	if userBkt.IsObjNotFoundErr(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrap(err, "delete series deletion request")
+	}
+	return true, nil
+}
+
+// ListSeriesDeletionRequests returns all series deletion requests stored in the input user bucket.
+func ListSeriesDeletionRequests(ctx context.Context, userBkt objstore.BucketReader) ([]*SeriesDeletionRequest, error) {
+	var out []*SeriesDeletionRequest
+
+	err := userBkt.Iter(ctx, SeriesDeletionRequestsPath+"/", func(name string) error {
+		if !strings.HasSuffix(name, ".json") {
+			return nil
+		}
+
+		req, err := readSeriesDeletionRequest(ctx, userBkt, name)
+		if err != nil {
+			return err
+		}
+		if req != nil {
+			out = append(out, req)
+		}
+		return nil
+	})
+
+	return out, errors.Wrap(err, "list series deletion requests")
+}
+
+// readSeriesDeletionRequest reads the series deletion request at the input path. If the request doesn't exist
+// (e.g. it was deleted in the meanwhile), returns nil request and no error.
+func readSeriesDeletionRequest(ctx context.Context, userBkt objstore.BucketReader, name string) (*SeriesDeletionRequest, error) {
+	r, err := userBkt.Get(ctx, name)
+	if err != nil {
+		if userBkt.IsObjNotFoundErr(err) {
+			return nil, nil
+		}
+
+		return nil, errors.Wrapf(err, "failed to read series deletion request object: %s", name)
+	}
+
+	req := &SeriesDeletionRequest{}
+	err = json.NewDecoder(r).Decode(req)
+
+	// Close reader before dealing with decode error.
+	if closeErr := r.Close(); closeErr != nil {
+		level.Warn(util_log.Logger).Log("msg", "failed to close bucket reader", "err", closeErr)
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode series deletion request object: %s", name)
+	}
+
+	return req, nil
+}
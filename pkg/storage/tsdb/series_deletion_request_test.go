@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package tsdb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+)
+
+func TestNewSeriesDeletionRequest(t *testing.T) {
+	now := time.Unix(1000, 0)
+
+	for name, tc := range map[string]struct {
+		selectors   []string
+		start, end  int64
+		expectedErr string
+	}{
+		"valid request": {
+			selectors: []string{`{__name__="up", job="test"}`, `http_requests_total`},
+			start:     10,
+			end:       20,
+		},
+		"no selectors": {
+			start:       10,
+			end:         20,
+			expectedErr: "no series selector provided",
+		},
+		"invalid selector": {
+			selectors:   []string{`{job=}`},
+			start:       10,
+			end:         20,
+			expectedErr: "invalid series selector",
+		},
+		"end before start": {
+			selectors:   []string{`up`},
+			start:       20,
+			end:         10,
+			expectedErr: "the end time must be greater than or equal to the start time",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			req, err := NewSeriesDeletionRequest(tc.selectors, tc.start, tc.end, now)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotEmpty(t, req.RequestID)
+			assert.Equal(t, tc.selectors, req.Selectors)
+			assert.Equal(t, tc.start, req.StartTime)
+			assert.Equal(t, tc.end, req.EndTime)
+
+			// The same request must have the same ID.
+			other, err := NewSeriesDeletionRequest(tc.selectors, tc.start, tc.end, now.Add(time.Hour))
+			require.NoError(t, err)
+			assert.Equal(t, req.RequestID, other.RequestID)
+
+			// A different time range must have a different ID.
+			other, err = NewSeriesDeletionRequest(tc.selectors, tc.start, tc.end+1, now)
+			require.NoError(t, err)
+			assert.NotEqual(t, req.RequestID, other.RequestID)
+		})
+	}
+}
+
+func TestSeriesDeletionRequest_Overlaps(t *testing.T) {
+	req := &SeriesDeletionRequest{StartTime: 10, EndTime: 20}
+
+	assert.True(t, req.Overlaps(0, 10))
+	assert.True(t, req.Overlaps(15, 16))
+	assert.True(t, req.Overlaps(20, 30))
+	assert.True(t, req.Overlaps(0, 30))
+	assert.False(t, req.Overlaps(0, 9))
+	assert.False(t, req.Overlaps(21, 30))
+}
+
+func TestSeriesDeletionRequests_WriteListDelete(t *testing.T) {
+	ctx := context.Background()
+	bkt := objstore.NewInMemBucket()
+
+	// Listing requests from an empty bucket should succeed.
+	requests, err := ListSeriesDeletionRequests(ctx, bkt)
+	require.NoError(t, err)
+	assert.Empty(t, requests)
+
+	first, err := NewSeriesDeletionRequest([]string{`up`}, 10, 20, time.Unix(1000, 0))
+	require.NoError(t, err)
+	second, err := NewSeriesDeletionRequest([]string{`{job="test"}`}, 30, 40, time.Unix(2000, 0))
+	require.NoError(t, err)
+
+	require.NoError(t, WriteSeriesDeletionRequest(ctx, bkt, first))
+	require.NoError(t, WriteSeriesDeletionRequest(ctx, bkt, second))
+
+	requests, err = ListSeriesDeletionRequests(ctx, bkt)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []*SeriesDeletionRequest{first, second}, requests)
+
+	found, err := DeleteSeriesDeletionRequest(ctx, bkt, first.RequestID)
+	require.NoError(t, err)
+	assert.True(t, found)
+
+	found, err = DeleteSeriesDeletionRequest(ctx, bkt, first.RequestID)
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	requests, err = ListSeriesDeletionRequests(ctx, bkt)
+	require.NoError(t, err)
+	assert.Equal(t, []*SeriesDeletionRequest{second}, requests)
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/oklog/ulid"
 	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/exemplar"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/storage"
 	"github.com/prometheus/prometheus/tsdb/chunks"
@@ -162,6 +163,16 @@ func (noopCache) FetchLabelValues(_ context.Context, _ string, _ ulid.ULID, _ st
 	return nil, false
 }
 
+func (noopCache) StoreExemplars(_ string, _ ulid.ULID, _ []byte) {}
+func (noopCache) FetchExemplars(_ context.Context, _ string, _ ulid.ULID) ([]byte, bool) {
+	return nil, false
+}
+
+func (noopCache) StoreMetricMetadata(_ string, _ ulid.ULID, _ []byte) {}
+func (noopCache) FetchMetricMetadata(_ context.Context, _ string, _ ulid.ULID) ([]byte, bool) {
+	return nil, false
+}
+
 // BucketStoreOption are functions that configure BucketStore.
 type BucketStoreOption func(s *BucketStore)
 
@@ -1494,6 +1505,219 @@ func storeCachedLabelNames(ctx context.Context, indexCache indexcache.IndexCache
 	indexCache.StoreLabelNames(userID, blockID, entry.MatchersKey, data)
 }
 
+// Exemplars implements the storegatewaypb.StoreGatewayServer interface.
+func (s *BucketStore) Exemplars(ctx context.Context, req *storepb.ExemplarsRequest) (*storepb.ExemplarsResponse, error) {
+	reqMatchersSets := make([][]*labels.Matcher, 0, len(req.Matchers))
+	for _, m := range req.Matchers {
+		matchers, err := storepb.MatchersToPromMatchers(m.Matchers...)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, errors.Wrap(err, "translate request labels matchers").Error())
+		}
+		reqMatchersSets = append(reqMatchersSets, matchers)
+	}
+
+	resHints := &hintspb.ExemplarsResponseHints{}
+
+	var reqBlockMatchers []*labels.Matcher
+	if req.Hints != nil {
+		reqHints := &hintspb.ExemplarsRequestHints{}
+		err := types.UnmarshalAny(req.Hints, reqHints)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, errors.Wrap(err, "unmarshal exemplars request hints").Error())
+		}
+
+		reqBlockMatchers, err = storepb.MatchersToPromMatchers(reqHints.BlockMatchers...)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, errors.Wrap(err, "translate request hints labels matchers").Error())
+		}
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+
+	s.blocksMx.RLock()
+
+	var mtx sync.Mutex
+	var sets [][]exemplar.QueryResult
+
+	for _, b := range s.blocks {
+		b := b
+		if !b.overlapsClosedInterval(req.Start, req.End) {
+			continue
+		}
+		if len(reqBlockMatchers) > 0 && !b.matchLabels(reqBlockMatchers) {
+			continue
+		}
+
+		// The block is queried even if it has no exemplars, so that the querier doesn't look for them elsewhere.
+		resHints.AddQueriedBlock(b.meta.ULID)
+		if !block.HasExemplars(b.meta) {
+			continue
+		}
+
+		g.Go(func() error {
+			result, err := b.loadExemplars(gctx)
+			if err != nil {
+				return errors.Wrapf(err, "block %s", b.meta.ULID)
+			}
+
+			result = filterExemplars(result, reqMatchersSets, req.Start, req.End)
+			if len(result) > 0 {
+				mtx.Lock()
+				sets = append(sets, result)
+				mtx.Unlock()
+			}
+
+			return nil
+		})
+	}
+
+	s.blocksMx.RUnlock()
+
+	if err := g.Wait(); err != nil {
+		if errors.Is(err, context.Canceled) {
+			return nil, status.Error(codes.Canceled, err.Error())
+		}
+
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	anyHints, err := types.MarshalAny(resHints)
+	if err != nil {
+		return nil, status.Error(codes.Unknown, errors.Wrap(err, "marshal exemplars response hints").Error())
+	}
+
+	merged := block.MergeExemplars(sets...)
+	series := make([]storepb.ExemplarSeries, 0, len(merged))
+	for _, r := range merged {
+		exemplars := make([]mimirpb.Exemplar, 0, len(r.Exemplars))
+		for _, e := range r.Exemplars {
+			exemplars = append(exemplars, mimirpb.Exemplar{
+				Labels:      mimirpb.FromLabelsToLabelAdapters(e.Labels),
+				Value:       e.Value,
+				TimestampMs: e.Ts,
+			})
+		}
+		series = append(series, storepb.ExemplarSeries{
+			Labels:    mimirpb.FromLabelsToLabelAdapters(r.SeriesLabels),
+			Exemplars: exemplars,
+		})
+	}
+
+	return &storepb.ExemplarsResponse{
+		Series: series,
+		Hints:  anyHints,
+	}, nil
+}
+
+// filterExemplars returns the exemplars within the time range (inclusive) of the series matching any of the sets
+// of matchers.
+func filterExemplars(series []exemplar.QueryResult, matchersSets [][]*labels.Matcher, minT, maxT int64) []exemplar.QueryResult {
+	var result []exemplar.QueryResult
+	for _, s := range series {
+		if !block.MatchesAnyMatchersSet(s.SeriesLabels, matchersSets) {
+			continue
+		}
+
+		var exemplars []exemplar.Exemplar
+		for _, e := range s.Exemplars {
+			if e.Ts >= minT && e.Ts <= maxT {
+				exemplars = append(exemplars, e)
+			}
+		}
+		if len(exemplars) > 0 {
+			result = append(result, exemplar.QueryResult{SeriesLabels: s.SeriesLabels, Exemplars: exemplars})
+		}
+	}
+	return result
+}
+
+// MetricsMetadata implements the storegatewaypb.StoreGatewayServer interface.
+func (s *BucketStore) MetricsMetadata(ctx context.Context, req *storepb.MetricsMetadataRequest) (*storepb.MetricsMetadataResponse, error) {
+	resHints := &hintspb.MetricsMetadataResponseHints{}
+
+	var reqBlockMatchers []*labels.Matcher
+	if req.Hints != nil {
+		reqHints := &hintspb.MetricsMetadataRequestHints{}
+		err := types.UnmarshalAny(req.Hints, reqHints)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, errors.Wrap(err, "unmarshal metrics metadata request hints").Error())
+		}
+
+		reqBlockMatchers, err = storepb.MatchersToPromMatchers(reqHints.BlockMatchers...)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, errors.Wrap(err, "translate request hints labels matchers").Error())
+		}
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+
+	s.blocksMx.RLock()
+
+	var mtx sync.Mutex
+	var sets [][]mimirpb.MetricMetadata
+
+	for _, b := range s.blocks {
+		b := b
+		if !b.overlapsClosedInterval(req.Start, req.End) {
+			continue
+		}
+		if len(reqBlockMatchers) > 0 && !b.matchLabels(reqBlockMatchers) {
+			continue
+		}
+
+		// The block is queried even if it has no metric metadata, so that the querier doesn't look for it elsewhere.
+		resHints.AddQueriedBlock(b.meta.ULID)
+		if !block.HasMetricMetadata(b.meta) {
+			continue
+		}
+
+		g.Go(func() error {
+			result, err := b.loadMetricMetadata(gctx)
+			if err != nil {
+				return errors.Wrapf(err, "block %s", b.meta.ULID)
+			}
+
+			if req.Metric != "" {
+				// The metadata of the block is cached, so it's filtered into a new slice.
+				var filtered []mimirpb.MetricMetadata
+				for _, m := range result {
+					if m.MetricFamilyName == req.Metric {
+						filtered = append(filtered, m)
+					}
+				}
+				result = filtered
+			}
+			if len(result) > 0 {
+				mtx.Lock()
+				sets = append(sets, result)
+				mtx.Unlock()
+			}
+
+			return nil
+		})
+	}
+
+	s.blocksMx.RUnlock()
+
+	if err := g.Wait(); err != nil {
+		if errors.Is(err, context.Canceled) {
+			return nil, status.Error(codes.Canceled, err.Error())
+		}
+
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	anyHints, err := types.MarshalAny(resHints)
+	if err != nil {
+		return nil, status.Error(codes.Unknown, errors.Wrap(err, "marshal metrics metadata response hints").Error())
+	}
+
+	return &storepb.MetricsMetadataResponse{
+		Metadata: block.LimitMetricMetadata(block.MergeMetricMetadata(sets...), int(req.Limit), int(req.LimitPerMetric)),
+		Hints:    anyHints,
+	}, nil
+}
+
 // LabelValues implements the storegatewaypb.StoreGatewayServer interface.
 func (s *BucketStore) LabelValues(ctx context.Context, req *storepb.LabelValuesRequest) (*storepb.LabelValuesResponse, error) {
 	reqSeriesMatchers, err := storepb.MatchersToPromMatchers(req.Matchers...)
@@ -1932,6 +2156,47 @@ func (b *bucketBlock) chunkRangeReader(ctx context.Context, seq int, off, length
 	return b.bkt.GetRange(ctx, b.chunkObjs[seq], off, length)
 }
 
+// loadExemplars returns the exemplars of the block. The exemplars file is stored in the index cache, so that the
+// memory used by the exemplars of the queried blocks is bounded by the index cache size.
+func (b *bucketBlock) loadExemplars(ctx context.Context) ([]exemplar.QueryResult, error) {
+	data, ok := b.indexCache.FetchExemplars(ctx, b.userID, b.meta.ULID)
+	if !ok {
+		var err error
+		if data, err = b.readFile(ctx, block.ExemplarsFilename); err != nil {
+			return nil, errors.Wrap(err, "read exemplars file")
+		}
+		b.indexCache.StoreExemplars(b.userID, b.meta.ULID, data)
+	}
+
+	return block.ReadExemplars(bytes.NewReader(data))
+}
+
+// readFile reads the whole content of a file of the block.
+func (b *bucketBlock) readFile(ctx context.Context, name string) ([]byte, error) {
+	r, err := b.bkt.Get(ctx, path.Join(b.meta.ULID.String(), name))
+	if err != nil {
+		return nil, err
+	}
+	defer runutil.CloseWithLogOnErr(b.logger, r, "readFile close reader")
+
+	return io.ReadAll(r)
+}
+
+// loadMetricMetadata returns the metric metadata of the block. The metric metadata file is stored in the index
+// cache, like the exemplars file.
+func (b *bucketBlock) loadMetricMetadata(ctx context.Context) ([]mimirpb.MetricMetadata, error) {
+	data, ok := b.indexCache.FetchMetricMetadata(ctx, b.userID, b.meta.ULID)
+	if !ok {
+		var err error
+		if data, err = b.readFile(ctx, block.MetricMetadataFilename); err != nil {
+			return nil, errors.Wrap(err, "read metric metadata file")
+		}
+		b.indexCache.StoreMetricMetadata(b.userID, b.meta.ULID, data)
+	}
+
+	return block.ReadMetricMetadata(bytes.NewReader(data))
+}
+
 func (b *bucketBlock) loadedIndexReader(ctx context.Context, postingsStrategy postingsSelectionStrategy, stats *safeQueryStats) *bucketIndexReader {
 	span, _ := opentracing.StartSpanFromContext(ctx, "bucketBlock.loadedIndexReader")
 	defer span.Finish()
@@ -15,10 +15,12 @@ import (
 	"time"
 
 	"github.com/go-kit/log"
+	"github.com/gogo/protobuf/types"
 	"github.com/grafana/dskit/grpcutil"
 	dskit_metrics "github.com/grafana/dskit/metrics"
 	"github.com/prometheus/client_golang/prometheus"
 	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/prometheus/model/exemplar"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/model/timestamp"
 	"github.com/prometheus/prometheus/tsdb/hashcache"
@@ -31,6 +33,7 @@ import (
 	"github.com/grafana/mimir/pkg/mimirpb"
 	mimir_tsdb "github.com/grafana/mimir/pkg/storage/tsdb"
 	"github.com/grafana/mimir/pkg/storage/tsdb/block"
+	"github.com/grafana/mimir/pkg/storegateway/hintspb"
 	"github.com/grafana/mimir/pkg/storegateway/indexcache"
 	"github.com/grafana/mimir/pkg/storegateway/indexheader"
 	"github.com/grafana/mimir/pkg/storegateway/storepb"
@@ -62,7 +65,7 @@ type storeSuite struct {
 // When nonOverlappingBlocks is true, it shifts the 2nd block ahead by 2hrs for every block range.
 // This way the first and the last blocks created have no overlapping blocks.
 func prepareTestBlocks(t testing.TB, now time.Time, count int, dir string, bkt objstore.Bucket,
-	series []labels.Labels, extLset labels.Labels, nonOverlappingBlocks bool) (minTime, maxTime int64) {
+	series []labels.Labels, extLset labels.Labels, nonOverlappingBlocks, withExemplars, withMetricMetadata bool) (minTime, maxTime int64) {
 	ctx := context.Background()
 	logger := log.NewNopLogger()
 
@@ -90,6 +93,35 @@ func prepareTestBlocks(t testing.TB, now time.Time, count int, dir string, bkt o
 
 		dir1, dir2 := filepath.Join(dir, id1.String()), filepath.Join(dir, id2.String())
 
+		// Add an exemplar per series at the beginning of each block.
+		if withExemplars {
+			for _, b := range []struct {
+				dir    string
+				series []labels.Labels
+			}{{dir1, series[:4]}, {dir2, series[4:]}} {
+				var exemplars []exemplar.QueryResult
+				for _, lset := range b.series {
+					exemplars = append(exemplars, exemplar.QueryResult{
+						SeriesLabels: lset,
+						Exemplars:    []exemplar.Exemplar{{Labels: labels.FromStrings("trace_id", filepath.Base(b.dir)), Value: 1, Ts: mint}},
+					})
+				}
+				assert.NoError(t, block.WriteExemplarsFile(logger, b.dir, exemplars))
+			}
+		}
+
+		// Add a metric metadata to each block, named after the position of the block in the time slot,
+		// with a different help for each block.
+		if withMetricMetadata {
+			for _, b := range []struct {
+				dir    string
+				metric string
+			}{{dir1, "first"}, {dir2, "second"}} {
+				md := []mimirpb.MetricMetadata{{Type: mimirpb.COUNTER, MetricFamilyName: b.metric, Help: "block " + filepath.Base(b.dir)}}
+				assert.NoError(t, block.WriteMetricMetadataFile(logger, b.dir, md))
+			}
+		}
+
 		// Replace labels to the meta of the second block.
 		meta, err := block.ReadMetaFromDir(dir2)
 		assert.NoError(t, err)
@@ -120,6 +152,10 @@ type prepareStoreConfig struct {
 	// When nonOverlappingBlocks is true, it shifts the 2nd block ahead by 2hrs for every block range.
 	// This way the first and the last blocks created have no overlapping blocks.
 	nonOverlappingBlocks bool
+	// When withExemplars is true, an exemplar is written for each series of each block.
+	withExemplars bool
+	// When withMetricMetadata is true, a metric metadata is written for each block.
+	withMetricMetadata bool
 }
 
 func (c *prepareStoreConfig) apply(opts ...prepareStoreConfigOption) *prepareStoreConfig {
@@ -157,6 +193,18 @@ func defaultPrepareStoreConfig(t testing.TB) *prepareStoreConfig {
 
 type prepareStoreConfigOption func(config *prepareStoreConfig)
 
+func withExemplars() prepareStoreConfigOption {
+	return func(config *prepareStoreConfig) {
+		config.withExemplars = true
+	}
+}
+
+func withMetricMetadata() prepareStoreConfigOption {
+	return func(config *prepareStoreConfig) {
+		config.withMetricMetadata = true
+	}
+}
+
 func withManyParts() prepareStoreConfigOption {
 	return func(config *prepareStoreConfig) {
 		config.manyParts = true
@@ -166,7 +214,7 @@ func withManyParts() prepareStoreConfigOption {
 func prepareStoreWithTestBlocks(t testing.TB, bkt objstore.Bucket, cfg *prepareStoreConfig) *storeSuite {
 	extLset := labels.FromStrings("ext1", "value1")
 
-	minTime, maxTime := prepareTestBlocks(t, time.Now(), 3, cfg.tempDir, bkt, cfg.series, extLset, cfg.nonOverlappingBlocks)
+	minTime, maxTime := prepareTestBlocks(t, time.Now(), 3, cfg.tempDir, bkt, cfg.series, extLset, cfg.nonOverlappingBlocks, cfg.withExemplars, cfg.withMetricMetadata)
 
 	s := &storeSuite{
 		logger:          log.NewNopLogger(),
@@ -890,6 +938,189 @@ func TestBucketStore_LabelValues_e2e(t *testing.T) {
 	})
 }
 
+func TestBucketStore_Exemplars_e2e(t *testing.T) {
+	foreachStore(t, func(t *testing.T, newSuite suiteFactory) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		s := newSuite(withExemplars())
+		s.cache.SwapIndexCacheWith(newInMemoryIndexCache(t))
+
+		for name, tc := range map[string]struct {
+			req                   *storepb.ExemplarsRequest
+			expectedSeries        []labels.Labels
+			expectedExemplars     int
+			expectedQueriedBlocks int
+		}{
+			"single set of matchers": {
+				req: &storepb.ExemplarsRequest{
+					Start: s.minTime,
+					End:   s.maxTime,
+					Matchers: []storepb.LabelMatchers{
+						{Matchers: []storepb.LabelMatcher{{Type: storepb.LabelMatcher_EQ, Name: "a", Value: "1"}}},
+					},
+				},
+				expectedSeries: []labels.Labels{
+					labels.FromStrings("a", "1", "b", "1"),
+					labels.FromStrings("a", "1", "b", "2"),
+					labels.FromStrings("a", "1", "c", "1"),
+					labels.FromStrings("a", "1", "c", "2"),
+				},
+				expectedExemplars:     3,
+				expectedQueriedBlocks: 6,
+			},
+			"multiple sets of matchers": {
+				req: &storepb.ExemplarsRequest{
+					Start: s.minTime,
+					End:   s.maxTime,
+					Matchers: []storepb.LabelMatchers{
+						{Matchers: []storepb.LabelMatcher{{Type: storepb.LabelMatcher_EQ, Name: "b", Value: "1"}}},
+						{Matchers: []storepb.LabelMatcher{{Type: storepb.LabelMatcher_EQ, Name: "a", Value: "2"}, {Type: storepb.LabelMatcher_EQ, Name: "c", Value: "2"}}},
+					},
+				},
+				expectedSeries: []labels.Labels{
+					labels.FromStrings("a", "1", "b", "1"),
+					labels.FromStrings("a", "2", "b", "1"),
+					labels.FromStrings("a", "2", "c", "2"),
+				},
+				expectedExemplars:     3,
+				expectedQueriedBlocks: 6,
+			},
+			"time range of the first blocks": {
+				req: &storepb.ExemplarsRequest{
+					Start: s.minTime,
+					End:   s.minTime,
+					Matchers: []storepb.LabelMatchers{
+						{Matchers: []storepb.LabelMatcher{{Type: storepb.LabelMatcher_EQ, Name: "b", Value: "2"}}},
+					},
+				},
+				expectedSeries: []labels.Labels{
+					labels.FromStrings("a", "1", "b", "2"),
+					labels.FromStrings("a", "2", "b", "2"),
+				},
+				expectedExemplars:     1,
+				expectedQueriedBlocks: 2,
+			},
+			"outside the time range": {
+				req: &storepb.ExemplarsRequest{
+					Start: timestamp.FromTime(time.Now().Add(-24 * time.Hour)),
+					End:   timestamp.FromTime(time.Now().Add(-23 * time.Hour)),
+					Matchers: []storepb.LabelMatchers{
+						{Matchers: []storepb.LabelMatcher{{Type: storepb.LabelMatcher_EQ, Name: "a", Value: "1"}}},
+					},
+				},
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				resp, err := s.store.Exemplars(ctx, tc.req)
+				require.NoError(t, err)
+
+				actualSeries := make([]labels.Labels, 0, len(resp.Series))
+				for _, series := range resp.Series {
+					actualSeries = append(actualSeries, mimirpb.FromLabelAdaptersToLabels(series.Labels))
+					assert.Len(t, series.Exemplars, tc.expectedExemplars)
+				}
+				assert.Equal(t, tc.expectedSeries, emptyToNilLabels(actualSeries))
+
+				var hints hintspb.ExemplarsResponseHints
+				require.NoError(t, types.UnmarshalAny(resp.Hints, &hints))
+				assert.Len(t, hints.QueriedBlocks, tc.expectedQueriedBlocks)
+			})
+		}
+
+		// The exemplars of the queried blocks are stored in the index cache, and the next queries don't download them again.
+		s.store.blocksMx.RLock()
+		for _, b := range s.store.blocks {
+			_, ok := s.cache.FetchExemplars(ctx, b.userID, b.meta.ULID)
+			assert.True(t, ok, b.meta.ULID.String())
+		}
+		s.store.blocksMx.RUnlock()
+	})
+}
+
+func TestBucketStore_MetricsMetadata_e2e(t *testing.T) {
+	foreachStore(t, func(t *testing.T, newSuite suiteFactory) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		s := newSuite(withMetricMetadata())
+		s.cache.SwapIndexCacheWith(newInMemoryIndexCache(t))
+
+		for name, tc := range map[string]struct {
+			req                   *storepb.MetricsMetadataRequest
+			expectedMetadata      int
+			expectedQueriedBlocks int
+		}{
+			"all metrics": {
+				req:                   &storepb.MetricsMetadataRequest{Start: s.minTime, End: s.maxTime},
+				expectedMetadata:      6,
+				expectedQueriedBlocks: 6,
+			},
+			"single metric": {
+				req:                   &storepb.MetricsMetadataRequest{Start: s.minTime, End: s.maxTime, Metric: "first"},
+				expectedMetadata:      3,
+				expectedQueriedBlocks: 6,
+			},
+			"unknown metric": {
+				req:                   &storepb.MetricsMetadataRequest{Start: s.minTime, End: s.maxTime, Metric: "unknown"},
+				expectedQueriedBlocks: 6,
+			},
+			"time range of the first blocks": {
+				req:                   &storepb.MetricsMetadataRequest{Start: s.minTime, End: s.minTime},
+				expectedMetadata:      2,
+				expectedQueriedBlocks: 2,
+			},
+			"outside the time range": {
+				req: &storepb.MetricsMetadataRequest{
+					Start: timestamp.FromTime(time.Now().Add(-24 * time.Hour)),
+					End:   timestamp.FromTime(time.Now().Add(-23 * time.Hour)),
+				},
+			},
+			"limit": {
+				req:                   &storepb.MetricsMetadataRequest{Start: s.minTime, End: s.maxTime, Limit: 1},
+				expectedMetadata:      3,
+				expectedQueriedBlocks: 6,
+			},
+			"limit per metric": {
+				req:                   &storepb.MetricsMetadataRequest{Start: s.minTime, End: s.maxTime, LimitPerMetric: 1},
+				expectedMetadata:      2,
+				expectedQueriedBlocks: 6,
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				resp, err := s.store.MetricsMetadata(ctx, tc.req)
+				require.NoError(t, err)
+
+				assert.Len(t, resp.Metadata, tc.expectedMetadata)
+				for _, m := range resp.Metadata {
+					if tc.req.Metric != "" {
+						assert.Equal(t, tc.req.Metric, m.MetricFamilyName)
+					}
+				}
+
+				var hints hintspb.MetricsMetadataResponseHints
+				require.NoError(t, types.UnmarshalAny(resp.Hints, &hints))
+				assert.Len(t, hints.QueriedBlocks, tc.expectedQueriedBlocks)
+			})
+		}
+
+		// The metric metadata of the queried blocks is stored in the index cache, and the next queries don't download it again.
+		s.store.blocksMx.RLock()
+		for _, b := range s.store.blocks {
+			_, ok := s.cache.FetchMetricMetadata(ctx, b.userID, b.meta.ULID)
+			assert.True(t, ok, b.meta.ULID.String())
+		}
+		s.store.blocksMx.RUnlock()
+	})
+}
+
+func emptyToNilLabels(series []labels.Labels) []labels.Labels {
+	if len(series) == 0 {
+		return nil
+	}
+	return series
+}
+
 func TestBucketStore_ValueTypes_e2e(t *testing.T) {
 	for _, streamingBatchSize := range []int{0, 1, 5} {
 		t.Run(fmt.Sprintf("streamingBatchSize=%d", streamingBatchSize), func(t *testing.T) {
@@ -334,6 +334,26 @@ func (g *StoreGateway) LabelNames(ctx context.Context, req *storepb.LabelNamesRe
 	return g.stores.LabelNames(ctx, req)
 }
 
+// Exemplars implements the storegatewaypb.StoreGatewayServer interface.
+func (g *StoreGateway) Exemplars(ctx context.Context, req *storepb.ExemplarsRequest) (*storepb.ExemplarsResponse, error) {
+	ix := g.tracker.Insert(func() string {
+		return requestActivity(ctx, "StoreGateway/Exemplars", req)
+	})
+	defer g.tracker.Delete(ix)
+
+	return g.stores.Exemplars(ctx, req)
+}
+
+// MetricsMetadata implements the storegatewaypb.StoreGatewayServer interface.
+func (g *StoreGateway) MetricsMetadata(ctx context.Context, req *storepb.MetricsMetadataRequest) (*storepb.MetricsMetadataResponse, error) {
+	ix := g.tracker.Insert(func() string {
+		return requestActivity(ctx, "StoreGateway/MetricsMetadata", req)
+	})
+	defer g.tracker.Delete(ix)
+
+	return g.stores.MetricsMetadata(ctx, req)
+}
+
 // LabelValues implements the storegatewaypb.StoreGatewayServer interface.
 func (g *StoreGateway) LabelValues(ctx context.Context, req *storepb.LabelValuesRequest) (*storepb.LabelValuesResponse, error) {
 	ix := g.tracker.Insert(func() string {
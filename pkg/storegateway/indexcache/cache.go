@@ -29,6 +29,8 @@ const (
 	cacheTypeSeriesForPostings = "SeriesForPostings"
 	cacheTypeLabelNames        = "LabelNames"
 	cacheTypeLabelValues       = "LabelValues"
+	cacheTypeExemplars         = "Exemplars"
+	cacheTypeMetricMetadata    = "MetricMetadata"
 )
 
 var (
@@ -39,6 +41,8 @@ var (
 		cacheTypeSeriesForPostings,
 		cacheTypeLabelNames,
 		cacheTypeLabelValues,
+		cacheTypeExemplars,
+		cacheTypeMetricMetadata,
 	}
 )
 
@@ -114,6 +118,16 @@ type IndexCache interface {
 	StoreLabelValues(userID string, blockID ulid.ULID, labelName string, matchersKey LabelMatchersKey, v []byte)
 	// FetchLabelValues fetches the result of a LabelValues() call.
 	FetchLabelValues(ctx context.Context, userID string, blockID ulid.ULID, labelName string, matchersKey LabelMatchersKey) ([]byte, bool)
+
+	// StoreExemplars stores the exemplars file of a block.
+	StoreExemplars(userID string, blockID ulid.ULID, v []byte)
+	// FetchExemplars fetches the exemplars file of a block.
+	FetchExemplars(ctx context.Context, userID string, blockID ulid.ULID) ([]byte, bool)
+
+	// StoreMetricMetadata stores the metric metadata file of a block.
+	StoreMetricMetadata(userID string, blockID ulid.ULID, v []byte)
+	// FetchMetricMetadata fetches the metric metadata file of a block.
+	FetchMetricMetadata(ctx context.Context, userID string, blockID ulid.ULID) ([]byte, bool)
 }
 
 // PostingsKey represents a canonical key for a []storage.SeriesRef slice
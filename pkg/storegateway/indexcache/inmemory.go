@@ -374,6 +374,26 @@ func (c *InMemoryIndexCache) FetchLabelValues(_ context.Context, userID string,
 	return c.get(cacheKeyLabelValues{userID, blockID, labelName, matchersKey})
 }
 
+// StoreExemplars stores the exemplars file of a block.
+func (c *InMemoryIndexCache) StoreExemplars(userID string, blockID ulid.ULID, v []byte) {
+	c.set(cacheKeyExemplars{userID, blockID}, v)
+}
+
+// FetchExemplars fetches the exemplars file of a block.
+func (c *InMemoryIndexCache) FetchExemplars(_ context.Context, userID string, blockID ulid.ULID) ([]byte, bool) {
+	return c.get(cacheKeyExemplars{userID, blockID})
+}
+
+// StoreMetricMetadata stores the metric metadata file of a block.
+func (c *InMemoryIndexCache) StoreMetricMetadata(userID string, blockID ulid.ULID, v []byte) {
+	c.set(cacheKeyMetricMetadata{userID, blockID}, v)
+}
+
+// FetchMetricMetadata fetches the metric metadata file of a block.
+func (c *InMemoryIndexCache) FetchMetricMetadata(_ context.Context, userID string, blockID ulid.ULID) ([]byte, bool) {
+	return c.get(cacheKeyMetricMetadata{userID, blockID})
+}
+
 // cacheKey is used by in-memory representation to store cached data.
 // The implementations of cacheKey should be hashable, as they will be used as keys for *lru.LRU cache
 type cacheKey interface {
@@ -467,6 +487,32 @@ func (c cacheKeyLabelValues) size() uint64 {
 	return stringSize(c.userID) + ulidSize + stringSize(c.labelName) + stringSize(string(c.matchersKey))
 }
 
+type cacheKeyExemplars struct {
+	userID string
+	block  ulid.ULID
+}
+
+func (c cacheKeyExemplars) typ() string {
+	return cacheTypeExemplars
+}
+
+func (c cacheKeyExemplars) size() uint64 {
+	return stringSize(c.userID) + ulidSize
+}
+
+type cacheKeyMetricMetadata struct {
+	userID string
+	block  ulid.ULID
+}
+
+func (c cacheKeyMetricMetadata) typ() string {
+	return cacheTypeMetricMetadata
+}
+
+func (c cacheKeyMetricMetadata) size() uint64 {
+	return stringSize(c.userID) + ulidSize
+}
+
 func stringSize(s string) uint64 {
 	return stringHeaderSize + uint64(len(s))
 }
@@ -194,6 +194,24 @@ func TestInMemoryIndexCache_UpdateItem(t *testing.T) {
 				return cache.FetchLabelValues(ctx, user, uid(id), fmt.Sprintf("lbl_%d", id), CanonicalLabelMatchersKey(matchers))
 			},
 		},
+		{
+			typ: cacheTypeExemplars,
+			set: func(id uint64, b []byte) {
+				cache.StoreExemplars(user, uid(id), b)
+			},
+			get: func(id uint64) ([]byte, bool) {
+				return cache.FetchExemplars(ctx, user, uid(id))
+			},
+		},
+		{
+			typ: cacheTypeMetricMetadata,
+			set: func(id uint64, b []byte) {
+				cache.StoreMetricMetadata(user, uid(id), b)
+			},
+			get: func(id uint64) ([]byte, bool) {
+				return cache.FetchMetricMetadata(ctx, user, uid(id))
+			},
+		},
 	} {
 		t.Run(tt.typ, func(t *testing.T) {
 			defer func() { errorLogs = nil }()
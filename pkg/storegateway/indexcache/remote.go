@@ -325,3 +325,32 @@ func labelValuesCacheKey(userID string, blockID ulid.ULID, labelName string, mat
 	hash := blake2b.Sum256([]byte(matchersKey))
 	return "LV2:" + userID + ":" + blockID.String() + ":" + labelName + ":" + base64.RawURLEncoding.EncodeToString(hash[0:])
 }
+
+// StoreExemplars stores the exemplars file of a block.
+func (c *RemoteIndexCache) StoreExemplars(userID string, blockID ulid.ULID, v []byte) {
+	c.set(exemplarsCacheKey(userID, blockID), v)
+}
+
+// FetchExemplars fetches the exemplars file of a block.
+func (c *RemoteIndexCache) FetchExemplars(ctx context.Context, userID string, blockID ulid.ULID) ([]byte, bool) {
+	return c.get(ctx, cacheTypeExemplars, exemplarsCacheKey(userID, blockID))
+}
+
+func exemplarsCacheKey(userID string, blockID ulid.ULID) string {
+	// We use EX: as E2: is already used for ExpandedPostings.
+	return "EX:" + userID + ":" + blockID.String()
+}
+
+// StoreMetricMetadata stores the metric metadata file of a block.
+func (c *RemoteIndexCache) StoreMetricMetadata(userID string, blockID ulid.ULID, v []byte) {
+	c.set(metricMetadataCacheKey(userID, blockID), v)
+}
+
+// FetchMetricMetadata fetches the metric metadata file of a block.
+func (c *RemoteIndexCache) FetchMetricMetadata(ctx context.Context, userID string, blockID ulid.ULID) ([]byte, bool) {
+	return c.get(ctx, cacheTypeMetricMetadata, metricMetadataCacheKey(userID, blockID))
+}
+
+func metricMetadataCacheKey(userID string, blockID ulid.ULID) string {
+	return "MM:" + userID + ":" + blockID.String()
+}
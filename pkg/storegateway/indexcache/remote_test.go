@@ -688,6 +688,172 @@ func TestRemoteIndexCache_FetchLabelValues(t *testing.T) {
 	}
 }
 
+func TestRemoteIndexCache_FetchExemplars(t *testing.T) {
+	t.Parallel()
+
+	// Init some data to conveniently define test cases later one.
+	user1 := "tenant1"
+	user2 := "tenant2"
+	block1 := ulid.MustNew(1, nil)
+	block2 := ulid.MustNew(2, nil)
+	value1 := []byte{1}
+	value2 := []byte{2}
+	value3 := []byte{3}
+
+	tests := map[string]struct {
+		setup        []mockedExemplars
+		mockedErr    error
+		fetchUserID  string
+		fetchBlockID ulid.ULID
+		expectedData []byte
+		expectedOk   bool
+	}{
+		"should return no hit on empty cache": {
+			setup:        []mockedExemplars{},
+			fetchUserID:  user1,
+			fetchBlockID: block1,
+			expectedData: nil,
+			expectedOk:   false,
+		},
+		"should return no miss on hit": {
+			setup: []mockedExemplars{
+				{userID: user1, block: block1, value: value1},
+				{userID: user2, block: block1, value: value2},
+				{userID: user1, block: block2, value: value3},
+			},
+			fetchUserID:  user1,
+			fetchBlockID: block1,
+			expectedData: value1,
+			expectedOk:   true,
+		},
+		"should return no hit on remote cache error": {
+			setup: []mockedExemplars{
+				{userID: user1, block: block1, value: value1},
+				{userID: user1, block: block2, value: value3},
+			},
+			mockedErr:    context.DeadlineExceeded,
+			fetchUserID:  user1,
+			fetchBlockID: block1,
+			expectedData: nil,
+			expectedOk:   false,
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			client := newMockedRemoteCacheClient(testData.mockedErr)
+			c, err := NewRemoteIndexCache(log.NewNopLogger(), client, nil)
+			assert.NoError(t, err)
+
+			// Store the exemplars expected before running the test.
+			ctx := context.Background()
+			for _, p := range testData.setup {
+				c.StoreExemplars(p.userID, p.block, p.value)
+			}
+
+			// Fetch exemplars from cached and assert on it.
+			data, ok := c.FetchExemplars(ctx, testData.fetchUserID, testData.fetchBlockID)
+			assert.Equal(t, testData.expectedData, data)
+			assert.Equal(t, testData.expectedOk, ok)
+
+			// Assert on metrics.
+			expectedHits := 0.0
+			if testData.expectedOk {
+				expectedHits = 1.0
+			}
+			assert.Equal(t, float64(1), prom_testutil.ToFloat64(c.requests.WithLabelValues(cacheTypeExemplars)))
+			assert.Equal(t, expectedHits, prom_testutil.ToFloat64(c.hits.WithLabelValues(cacheTypeExemplars)))
+			for _, typ := range remove(allCacheTypes, cacheTypeExemplars) {
+				assert.Equal(t, 0.0, prom_testutil.ToFloat64(c.requests.WithLabelValues(typ)))
+				assert.Equal(t, 0.0, prom_testutil.ToFloat64(c.hits.WithLabelValues(typ)))
+			}
+		})
+	}
+}
+
+func TestRemoteIndexCache_FetchMetricMetadata(t *testing.T) {
+	t.Parallel()
+
+	// Init some data to conveniently define test cases later one.
+	user1 := "tenant1"
+	user2 := "tenant2"
+	block1 := ulid.MustNew(1, nil)
+	block2 := ulid.MustNew(2, nil)
+	value1 := []byte{1}
+	value2 := []byte{2}
+	value3 := []byte{3}
+
+	tests := map[string]struct {
+		setup        []mockedMetricMetadata
+		mockedErr    error
+		fetchUserID  string
+		fetchBlockID ulid.ULID
+		expectedData []byte
+		expectedOk   bool
+	}{
+		"should return no hit on empty cache": {
+			setup:        []mockedMetricMetadata{},
+			fetchUserID:  user1,
+			fetchBlockID: block1,
+			expectedData: nil,
+			expectedOk:   false,
+		},
+		"should return no miss on hit": {
+			setup: []mockedMetricMetadata{
+				{userID: user1, block: block1, value: value1},
+				{userID: user2, block: block1, value: value2},
+				{userID: user1, block: block2, value: value3},
+			},
+			fetchUserID:  user1,
+			fetchBlockID: block1,
+			expectedData: value1,
+			expectedOk:   true,
+		},
+		"should return no hit on remote cache error": {
+			setup: []mockedMetricMetadata{
+				{userID: user1, block: block1, value: value1},
+				{userID: user1, block: block2, value: value3},
+			},
+			mockedErr:    context.DeadlineExceeded,
+			fetchUserID:  user1,
+			fetchBlockID: block1,
+			expectedData: nil,
+			expectedOk:   false,
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			client := newMockedRemoteCacheClient(testData.mockedErr)
+			c, err := NewRemoteIndexCache(log.NewNopLogger(), client, nil)
+			assert.NoError(t, err)
+
+			// Store the metric metadata expected before running the test.
+			ctx := context.Background()
+			for _, p := range testData.setup {
+				c.StoreMetricMetadata(p.userID, p.block, p.value)
+			}
+
+			// Fetch metric metadata from cached and assert on it.
+			data, ok := c.FetchMetricMetadata(ctx, testData.fetchUserID, testData.fetchBlockID)
+			assert.Equal(t, testData.expectedData, data)
+			assert.Equal(t, testData.expectedOk, ok)
+
+			// Assert on metrics.
+			expectedHits := 0.0
+			if testData.expectedOk {
+				expectedHits = 1.0
+			}
+			assert.Equal(t, float64(1), prom_testutil.ToFloat64(c.requests.WithLabelValues(cacheTypeMetricMetadata)))
+			assert.Equal(t, expectedHits, prom_testutil.ToFloat64(c.hits.WithLabelValues(cacheTypeMetricMetadata)))
+			for _, typ := range remove(allCacheTypes, cacheTypeMetricMetadata) {
+				assert.Equal(t, 0.0, prom_testutil.ToFloat64(c.requests.WithLabelValues(typ)))
+				assert.Equal(t, 0.0, prom_testutil.ToFloat64(c.hits.WithLabelValues(typ)))
+			}
+		})
+	}
+}
+
 func TestStringCacheKeys_Values(t *testing.T) {
 	t.Parallel()
 
@@ -718,6 +884,14 @@ func TestStringCacheKeys_Values(t *testing.T) {
 			key:      seriesForRefCacheKey(user, uid, 12345),
 			expected: fmt.Sprintf("S:%s:%s:12345", user, uid.String()),
 		},
+		"should stringify exemplars cache key": {
+			key:      exemplarsCacheKey(user, uid),
+			expected: fmt.Sprintf("EX:%s:%s", user, uid.String()),
+		},
+		"should stringify metric metadata cache key": {
+			key:      metricMetadataCacheKey(user, uid),
+			expected: fmt.Sprintf("MM:%s:%s", user, uid.String()),
+		},
 	}
 
 	for testName, testData := range tests {
@@ -906,6 +1080,18 @@ type mockedLabelValues struct {
 	value     []byte
 }
 
+type mockedExemplars struct {
+	userID string
+	block  ulid.ULID
+	value  []byte
+}
+
+type mockedMetricMetadata struct {
+	userID string
+	block  ulid.ULID
+	value  []byte
+}
+
 type mockedRemoteCacheClient struct {
 	cache             map[string][]byte
 	mockedGetMultiErr error
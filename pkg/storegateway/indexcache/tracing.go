@@ -162,6 +162,48 @@ func (t *TracingIndexCache) FetchLabelValues(ctx context.Context, userID string,
 	return data, found
 }
 
+func (t *TracingIndexCache) StoreExemplars(userID string, blockID ulid.ULID, v []byte) {
+	t.c.StoreExemplars(userID, blockID, v)
+}
+
+func (t *TracingIndexCache) FetchExemplars(ctx context.Context, userID string, blockID ulid.ULID) ([]byte, bool) {
+	t0 := time.Now()
+	data, found := t.c.FetchExemplars(ctx, userID, blockID)
+
+	spanLogger := spanlogger.FromContext(ctx, t.logger)
+	spanLogger.DebugLog(
+		"msg", "IndexCache.FetchExemplars",
+		"block", blockID,
+		"found", found,
+		"time elapsed", time.Since(t0),
+		"returned bytes", len(data),
+		"user_id", userID,
+	)
+
+	return data, found
+}
+
+func (t *TracingIndexCache) StoreMetricMetadata(userID string, blockID ulid.ULID, v []byte) {
+	t.c.StoreMetricMetadata(userID, blockID, v)
+}
+
+func (t *TracingIndexCache) FetchMetricMetadata(ctx context.Context, userID string, blockID ulid.ULID) ([]byte, bool) {
+	t0 := time.Now()
+	data, found := t.c.FetchMetricMetadata(ctx, userID, blockID)
+
+	spanLogger := spanlogger.FromContext(ctx, t.logger)
+	spanLogger.DebugLog(
+		"msg", "IndexCache.FetchMetricMetadata",
+		"block", blockID,
+		"found", found,
+		"time elapsed", time.Since(t0),
+		"returned bytes", len(data),
+		"user_id", userID,
+	)
+
+	return data, found
+}
+
 func sumBytes[T comparable](res map[T][]byte) int {
 	sum := 0
 	for _, v := range res {
@@ -25,6 +25,7 @@ import (
 	"github.com/grafana/mimir/pkg/storage/sharding"
 	"github.com/grafana/mimir/pkg/storage/tsdb"
 	"github.com/grafana/mimir/pkg/storage/tsdb/block"
+	"github.com/grafana/mimir/pkg/storage/tsdb/downsample"
 	"github.com/grafana/mimir/pkg/storegateway/indexcache"
 	"github.com/grafana/mimir/pkg/storegateway/storepb"
 	"github.com/grafana/mimir/pkg/util/pool"
@@ -1357,7 +1358,8 @@ func (b cachedSeriesHasher) CachedHash(seriesID storage.SeriesRef, stats *queryS
 func (b cachedSeriesHasher) Hash(id storage.SeriesRef, lset labels.Labels, stats *queryStats) uint64 {
 	hash, ok := b.CachedHash(id, stats)
 	if !ok {
-		hash = labels.StableHash(lset)
+		// The series of a downsampled block belong to the same shard as the raw series they're downsampled from.
+		hash = labels.StableHash(downsample.WithoutAggrLabel(lset))
 		b.cache.Store(id, hash)
 	}
 	return hash
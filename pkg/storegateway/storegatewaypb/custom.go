@@ -45,6 +45,18 @@ func (c *customStoreGatewayClient) LabelValues(ctx context.Context, in *storepb.
 	return res, util.WrapGrpcContextError(err)
 }
 
+// Exemplars implements StoreGatewayClient.
+func (c *customStoreGatewayClient) Exemplars(ctx context.Context, in *storepb.ExemplarsRequest, opts ...grpc.CallOption) (*storepb.ExemplarsResponse, error) {
+	res, err := c.wrapped.Exemplars(ctx, in, opts...)
+	return res, util.WrapGrpcContextError(err)
+}
+
+// MetricsMetadata implements StoreGatewayClient.
+func (c *customStoreGatewayClient) MetricsMetadata(ctx context.Context, in *storepb.MetricsMetadataRequest, opts ...grpc.CallOption) (*storepb.MetricsMetadataResponse, error) {
+	res, err := c.wrapped.MetricsMetadata(ctx, in, opts...)
+	return res, util.WrapGrpcContextError(err)
+}
+
 // customStoreGatewayClient is a custom StoreGateway_SeriesClient which wraps well known gRPC errors into standard golang errors.
 type customSeriesClient struct {
 	*customClientStream
@@ -7,8 +7,8 @@ import (
 	fmt "fmt"
 	_ "github.com/gogo/protobuf/gogoproto"
 	proto "github.com/gogo/protobuf/proto"
-	_ "github.com/grafana/mimir/pkg/mimirpb"
 	github_com_grafana_mimir_pkg_mimirpb "github.com/grafana/mimir/pkg/mimirpb"
+	mimirpb "github.com/grafana/mimir/pkg/mimirpb"
 	io "io"
 	math "math"
 	math_bits "math/bits"
@@ -412,6 +412,43 @@ func (m *LabelMatcher) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_LabelMatcher proto.InternalMessageInfo
 
+type ExemplarSeries struct {
+	Labels    []github_com_grafana_mimir_pkg_mimirpb.LabelAdapter `protobuf:"bytes,1,rep,name=labels,proto3,customtype=github.com/grafana/mimir/pkg/mimirpb.LabelAdapter" json:"labels"`
+	Exemplars []mimirpb.Exemplar                                  `protobuf:"bytes,2,rep,name=exemplars,proto3" json:"exemplars"`
+}
+
+func (m *ExemplarSeries) Reset()      { *m = ExemplarSeries{} }
+func (*ExemplarSeries) ProtoMessage() {}
+func (*ExemplarSeries) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d938547f84707355, []int{9}
+}
+func (m *ExemplarSeries) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ExemplarSeries) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ExemplarSeries.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ExemplarSeries) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExemplarSeries.Merge(m, src)
+}
+func (m *ExemplarSeries) XXX_Size() int {
+	return m.Size()
+}
+func (m *ExemplarSeries) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExemplarSeries.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExemplarSeries proto.InternalMessageInfo
+
 func init() {
 	proto.RegisterEnum("thanos.Chunk_Encoding", Chunk_Encoding_name, Chunk_Encoding_value)
 	proto.RegisterEnum("thanos.LabelMatcher_Type", LabelMatcher_Type_name, LabelMatcher_Type_value)
@@ -424,57 +461,60 @@ func init() {
 	proto.RegisterType((*StreamingChunksEstimate)(nil), "thanos.StreamingChunksEstimate")
 	proto.RegisterType((*AggrChunk)(nil), "thanos.AggrChunk")
 	proto.RegisterType((*LabelMatcher)(nil), "thanos.LabelMatcher")
+	proto.RegisterType((*ExemplarSeries)(nil), "thanos.ExemplarSeries")
 }
 
 func init() { proto.RegisterFile("types.proto", fileDescriptor_d938547f84707355) }
 
 var fileDescriptor_d938547f84707355 = []byte{
-	// 715 bytes of a gzipped FileDescriptorProto
+	// 745 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x54, 0x4d, 0x6f, 0xd3, 0x4a,
-	0x14, 0xf5, 0x24, 0x4e, 0xe2, 0x4c, 0xda, 0x57, 0xbf, 0x49, 0xde, 0x6b, 0xda, 0x85, 0x1b, 0x2c,
-	0x21, 0x45, 0x48, 0x75, 0x20, 0x74, 0x83, 0xc4, 0xa6, 0xa9, 0x02, 0x25, 0xa2, 0xb4, 0x75, 0x8b,
-	0x84, 0x10, 0x92, 0x35, 0x49, 0x26, 0xce, 0xa8, 0xf1, 0x87, 0x3c, 0x13, 0x48, 0x16, 0x48, 0xac,
-	0x58, 0xf3, 0x17, 0xd8, 0xf1, 0x47, 0x90, 0xba, 0xa3, 0xcb, 0x8a, 0x45, 0x45, 0xd2, 0x0d, 0xcb,
-	0xfe, 0x04, 0xe4, 0x19, 0xa7, 0xa4, 0xed, 0xa6, 0x6c, 0x58, 0x79, 0xee, 0x3d, 0xe7, 0xde, 0x73,
-	0xee, 0xd5, 0x8c, 0x61, 0x81, 0x8f, 0x43, 0xc2, 0xac, 0x30, 0x0a, 0x78, 0x80, 0xb2, 0xbc, 0x8f,
-	0xfd, 0x80, 0xad, 0xae, 0xbb, 0x94, 0xf7, 0x87, 0x6d, 0xab, 0x13, 0x78, 0x35, 0x37, 0x70, 0x83,
-	0x9a, 0x80, 0xdb, 0xc3, 0x9e, 0x88, 0x44, 0x20, 0x4e, 0xb2, 0x6c, 0xf5, 0xfe, 0x3c, 0x3d, 0xc2,
-	0x3d, 0xec, 0xe3, 0x9a, 0x47, 0x3d, 0x1a, 0xd5, 0xc2, 0x23, 0x57, 0x9e, 0xc2, 0xb6, 0xfc, 0xca,
-	0x0a, 0xf3, 0x1b, 0x80, 0x99, 0xad, 0xfe, 0xd0, 0x3f, 0x42, 0xf7, 0xa0, 0x1a, 0x3b, 0x28, 0x83,
-	0x0a, 0xa8, 0xfe, 0x53, 0xff, 0xdf, 0x92, 0x0e, 0x2c, 0x01, 0x5a, 0x4d, 0xbf, 0x13, 0x74, 0xa9,
-	0xef, 0xda, 0x82, 0x83, 0xf6, 0xa0, 0xda, 0xc5, 0x1c, 0x97, 0x53, 0x15, 0x50, 0x5d, 0x68, 0x3c,
-	0x3e, 0x3e, 0x5b, 0x53, 0xbe, 0x9f, 0xad, 0x6d, 0xdc, 0x46, 0xdd, 0x7a, 0xe9, 0x33, 0xdc, 0x23,
-	0x8d, 0x31, 0x27, 0x07, 0x03, 0xda, 0x21, 0xb6, 0xe8, 0x64, 0x6e, 0x43, 0x6d, 0xa6, 0x81, 0x16,
-	0x61, 0x5e, 0xa8, 0x3a, 0xaf, 0x76, 0x6d, 0x5d, 0x41, 0x45, 0xb8, 0x24, 0xc3, 0x6d, 0xca, 0x78,
-	0xe0, 0x46, 0xd8, 0xd3, 0x01, 0x2a, 0xc3, 0x92, 0x4c, 0x3e, 0x19, 0x04, 0x98, 0xff, 0x46, 0x52,
-	0xe6, 0x67, 0x00, 0xb3, 0x07, 0x24, 0xa2, 0x84, 0xa1, 0x1e, 0xcc, 0x0e, 0x70, 0x9b, 0x0c, 0x58,
-	0x19, 0x54, 0xd2, 0xd5, 0x42, 0xbd, 0x68, 0x75, 0x82, 0x88, 0x93, 0x51, 0xd8, 0xb6, 0x9e, 0xc7,
-	0xf9, 0x3d, 0x4c, 0xa3, 0xc6, 0xa3, 0xc4, 0xfd, 0x83, 0x5b, 0xb9, 0x17, 0x75, 0x9b, 0x5d, 0x1c,
-	0x72, 0x12, 0xd9, 0x49, 0x77, 0x54, 0x83, 0xd9, 0x4e, 0x6c, 0x86, 0x95, 0x53, 0x42, 0xe7, 0xdf,
-	0xd9, 0xf2, 0x36, 0x5d, 0x37, 0x12, 0x36, 0x1b, 0x6a, 0xac, 0x62, 0x27, 0x34, 0x73, 0x0c, 0x97,
-	0x0e, 0x78, 0x44, 0xb0, 0x47, 0x7d, 0xf7, 0xef, 0x7a, 0x35, 0xdf, 0xc3, 0xd2, 0x35, 0xe9, 0x06,
-	0xe6, 0x9d, 0x7e, 0x3c, 0x03, 0x13, 0x61, 0xa2, 0xbf, 0x3c, 0x9b, 0xe1, 0x1a, 0xdb, 0x4e, 0x68,
-	0x68, 0x03, 0x2e, 0x53, 0xe6, 0x10, 0xbf, 0xeb, 0x04, 0x3d, 0x47, 0xe6, 0x1c, 0x26, 0xb8, 0xe2,
-	0x5a, 0x68, 0x76, 0x91, 0xb2, 0xa6, 0xdf, 0xdd, 0xed, 0xc9, 0x3a, 0xd9, 0xc6, 0x24, 0x73, 0x93,
-	0x8b, 0xcd, 0x30, 0x74, 0x07, 0x2e, 0x24, 0xe5, 0xd4, 0xef, 0x92, 0x91, 0xb8, 0x80, 0xaa, 0x5d,
-	0x90, 0xb9, 0x67, 0x71, 0xea, 0xcf, 0x17, 0xfc, 0x74, 0x6e, 0x4a, 0x29, 0x73, 0xdb, 0x29, 0x25,
-	0x7b, 0x36, 0xa5, 0xb9, 0x03, 0x97, 0xaf, 0x41, 0x4d, 0xc6, 0xa9, 0x87, 0x39, 0x41, 0x75, 0xf8,
-	0x1f, 0x49, 0xce, 0x5d, 0x47, 0xe8, 0x3a, 0x9d, 0x60, 0xe8, 0xf3, 0x64, 0x80, 0xe2, 0x25, 0x28,
-	0xea, 0xb6, 0x62, 0xc8, 0xfc, 0x08, 0x60, 0xfe, 0xd2, 0x33, 0x5a, 0x81, 0x9a, 0x47, 0x7d, 0x87,
-	0x53, 0x4f, 0x3e, 0xbb, 0xb4, 0x9d, 0xf3, 0xa8, 0x7f, 0x48, 0x3d, 0x22, 0x20, 0x3c, 0x92, 0x50,
-	0x2a, 0x81, 0xf0, 0x48, 0x40, 0x77, 0x61, 0x3a, 0xc2, 0xef, 0xca, 0xe9, 0x0a, 0xa8, 0x16, 0xea,
-	0x8b, 0x57, 0xde, 0x69, 0xb2, 0x85, 0x18, 0x6f, 0xa9, 0x9a, 0xaa, 0x67, 0x5a, 0xaa, 0x96, 0xd1,
-	0xb3, 0x2d, 0x55, 0xcb, 0xea, 0xb9, 0x96, 0xaa, 0xe5, 0x74, 0xad, 0xa5, 0x6a, 0x9a, 0x9e, 0x37,
-	0xbf, 0x02, 0xb8, 0x20, 0xee, 0xc7, 0x4e, 0xbc, 0x17, 0x12, 0xa1, 0xf5, 0x2b, 0xcf, 0x7f, 0x65,
-	0xd6, 0x76, 0x9e, 0x63, 0x1d, 0x8e, 0x43, 0x92, 0xfc, 0x01, 0x10, 0x54, 0x7d, 0x9c, 0x78, 0xcb,
-	0xdb, 0xe2, 0x8c, 0x4a, 0x30, 0xf3, 0x16, 0x0f, 0x86, 0x44, 0x58, 0xcb, 0xdb, 0x32, 0x30, 0xdf,
-	0x40, 0x35, 0xae, 0x8b, 0x9f, 0xf1, 0x7c, 0x33, 0xa7, 0xb9, 0xaf, 0x2b, 0xa8, 0x04, 0xf5, 0x2b,
-	0xc9, 0x17, 0xcd, 0x7d, 0x1d, 0xdc, 0xa0, 0xda, 0x4d, 0x3d, 0x75, 0x93, 0x6a, 0x37, 0xf5, 0x74,
-	0x63, 0xf3, 0x78, 0x62, 0x28, 0x27, 0x13, 0x43, 0x39, 0x9d, 0x18, 0xca, 0xc5, 0xc4, 0x00, 0x1f,
-	0xa6, 0x06, 0xf8, 0x32, 0x35, 0xc0, 0xf1, 0xd4, 0x00, 0x27, 0x53, 0x03, 0xfc, 0x98, 0x1a, 0xe0,
-	0xe7, 0xd4, 0x50, 0x2e, 0xa6, 0x06, 0xf8, 0x74, 0x6e, 0x28, 0x27, 0xe7, 0x86, 0x72, 0x7a, 0x6e,
-	0x28, 0xaf, 0x73, 0x8c, 0x07, 0x11, 0x09, 0xdb, 0xed, 0xac, 0xf8, 0x13, 0x3e, 0xfc, 0x15, 0x00,
-	0x00, 0xff, 0xff, 0x58, 0xdc, 0x9c, 0x04, 0x81, 0x05, 0x00, 0x00,
+	0x14, 0xf5, 0x24, 0x4e, 0xe2, 0x4c, 0xfa, 0xe1, 0x37, 0xc9, 0x7b, 0x4d, 0xbb, 0x70, 0xf3, 0x2c,
+	0x3d, 0x29, 0x7a, 0x52, 0x1d, 0x08, 0x15, 0x12, 0x12, 0x9b, 0xa6, 0x32, 0x94, 0x88, 0xd2, 0xd6,
+	0x2d, 0x12, 0x42, 0x48, 0xd6, 0x24, 0x99, 0x38, 0xa3, 0xc6, 0x1f, 0xb2, 0x27, 0x90, 0x2c, 0x90,
+	0x58, 0xb1, 0xe6, 0x2f, 0xb0, 0x63, 0xc9, 0x9f, 0x40, 0xea, 0x8e, 0x2e, 0x2b, 0x16, 0x15, 0x49,
+	0x37, 0x2c, 0xfb, 0x13, 0x90, 0x67, 0x9c, 0x36, 0x6d, 0x37, 0x65, 0xd3, 0x55, 0x66, 0xee, 0x39,
+	0xf7, 0x9e, 0x73, 0x6f, 0xe6, 0x1a, 0x16, 0xd8, 0x28, 0x20, 0x91, 0x11, 0x84, 0x3e, 0xf3, 0x51,
+	0x96, 0xf5, 0xb0, 0xe7, 0x47, 0x2b, 0x6b, 0x0e, 0x65, 0xbd, 0x41, 0xcb, 0x68, 0xfb, 0x6e, 0xcd,
+	0xf1, 0x1d, 0xbf, 0xc6, 0xe1, 0xd6, 0xa0, 0xcb, 0x6f, 0xfc, 0xc2, 0x4f, 0x22, 0x6d, 0xe5, 0xde,
+	0x2c, 0x3d, 0xc4, 0x5d, 0xec, 0xe1, 0x9a, 0x4b, 0x5d, 0x1a, 0xd6, 0x82, 0x43, 0x47, 0x9c, 0x82,
+	0x96, 0xf8, 0x15, 0x19, 0xfa, 0x77, 0x00, 0x33, 0x9b, 0xbd, 0x81, 0x77, 0x88, 0xfe, 0x87, 0x72,
+	0xec, 0xa0, 0x0c, 0x2a, 0xa0, 0xba, 0x50, 0xff, 0xc7, 0x10, 0x0e, 0x0c, 0x0e, 0x1a, 0xa6, 0xd7,
+	0xf6, 0x3b, 0xd4, 0x73, 0x2c, 0xce, 0x41, 0xbb, 0x50, 0xee, 0x60, 0x86, 0xcb, 0xa9, 0x0a, 0xa8,
+	0xce, 0x35, 0x1e, 0x1f, 0x9d, 0xae, 0x4a, 0x3f, 0x4e, 0x57, 0xd7, 0x6f, 0xa3, 0x6e, 0xbc, 0xf4,
+	0x22, 0xdc, 0x25, 0x8d, 0x11, 0x23, 0xfb, 0x7d, 0xda, 0x26, 0x16, 0xaf, 0xa4, 0x6f, 0x41, 0x65,
+	0xaa, 0x81, 0xe6, 0x61, 0x9e, 0xab, 0xda, 0xaf, 0x76, 0x2c, 0x55, 0x42, 0x45, 0xb8, 0x28, 0xae,
+	0x5b, 0x34, 0x62, 0xbe, 0x13, 0x62, 0x57, 0x05, 0xa8, 0x0c, 0x4b, 0x22, 0xf8, 0xa4, 0xef, 0x63,
+	0x76, 0x89, 0xa4, 0xf4, 0xcf, 0x00, 0x66, 0xf7, 0x49, 0x48, 0x49, 0x84, 0xba, 0x30, 0xdb, 0xc7,
+	0x2d, 0xd2, 0x8f, 0xca, 0xa0, 0x92, 0xae, 0x16, 0xea, 0x45, 0xa3, 0xed, 0x87, 0x8c, 0x0c, 0x83,
+	0x96, 0xf1, 0x3c, 0x8e, 0xef, 0x62, 0x1a, 0x36, 0x1e, 0x25, 0xee, 0xef, 0xdf, 0xca, 0x3d, 0xcf,
+	0xdb, 0xe8, 0xe0, 0x80, 0x91, 0xd0, 0x4a, 0xaa, 0xa3, 0x1a, 0xcc, 0xb6, 0x63, 0x33, 0x51, 0x39,
+	0xc5, 0x75, 0xfe, 0x9a, 0x0e, 0x6f, 0xc3, 0x71, 0x42, 0x6e, 0xb3, 0x21, 0xc7, 0x2a, 0x56, 0x42,
+	0xd3, 0x47, 0x70, 0x71, 0x9f, 0x85, 0x04, 0xbb, 0xd4, 0x73, 0xee, 0xd6, 0xab, 0xfe, 0x1e, 0x96,
+	0xae, 0x49, 0x37, 0x30, 0x6b, 0xf7, 0xe2, 0x1e, 0x22, 0x7e, 0x4d, 0xf4, 0x97, 0xa6, 0x3d, 0x5c,
+	0x63, 0x5b, 0x09, 0x0d, 0xad, 0xc3, 0x25, 0x1a, 0xd9, 0xc4, 0xeb, 0xd8, 0x7e, 0xd7, 0x16, 0x31,
+	0x3b, 0xe2, 0x5c, 0xfe, 0x2c, 0x14, 0xab, 0x48, 0x23, 0xd3, 0xeb, 0xec, 0x74, 0x45, 0x9e, 0x28,
+	0xa3, 0x93, 0x99, 0xce, 0xf9, 0x64, 0x22, 0xf4, 0x2f, 0x9c, 0x4b, 0xd2, 0xa9, 0xd7, 0x21, 0x43,
+	0xfe, 0x00, 0x65, 0xab, 0x20, 0x62, 0xcf, 0xe2, 0xd0, 0x9f, 0x0f, 0xf8, 0xe9, 0x4c, 0x97, 0x42,
+	0xe6, 0xb6, 0x5d, 0x0a, 0xf6, 0xb4, 0x4b, 0x7d, 0x1b, 0x2e, 0x5d, 0x83, 0xcc, 0x88, 0x51, 0x17,
+	0x33, 0x82, 0xea, 0xf0, 0x6f, 0x92, 0x9c, 0x3b, 0x36, 0xd7, 0xb5, 0xdb, 0xfe, 0xc0, 0x63, 0x49,
+	0x03, 0xc5, 0x0b, 0x90, 0xe7, 0x6d, 0xc6, 0x90, 0xfe, 0x11, 0xc0, 0xfc, 0x85, 0x67, 0xb4, 0x0c,
+	0x15, 0x97, 0x7a, 0x36, 0xa3, 0xae, 0x58, 0xbb, 0xb4, 0x95, 0x73, 0xa9, 0x77, 0x40, 0x5d, 0xc2,
+	0x21, 0x3c, 0x14, 0x50, 0x2a, 0x81, 0xf0, 0x90, 0x43, 0xff, 0xc1, 0x74, 0x88, 0xdf, 0x95, 0xd3,
+	0x15, 0x50, 0x2d, 0xd4, 0xe7, 0xaf, 0xec, 0x69, 0x32, 0x85, 0x18, 0x6f, 0xca, 0x8a, 0xac, 0x66,
+	0x9a, 0xb2, 0x92, 0x51, 0xb3, 0x4d, 0x59, 0xc9, 0xaa, 0xb9, 0xa6, 0xac, 0xe4, 0x54, 0xa5, 0x29,
+	0x2b, 0x8a, 0x9a, 0xd7, 0xbf, 0x01, 0x38, 0xc7, 0xdf, 0xc7, 0x76, 0x3c, 0x17, 0x12, 0xa2, 0xb5,
+	0x2b, 0xeb, 0xbf, 0x3c, 0x2d, 0x3b, 0xcb, 0x31, 0x0e, 0x46, 0x01, 0x49, 0xbe, 0x00, 0x08, 0xca,
+	0x1e, 0x4e, 0xbc, 0xe5, 0x2d, 0x7e, 0x46, 0x25, 0x98, 0x79, 0x8b, 0xfb, 0x03, 0xc2, 0xad, 0xe5,
+	0x2d, 0x71, 0xd1, 0xdf, 0x40, 0x39, 0xce, 0x8b, 0xd7, 0x78, 0xb6, 0x98, 0x6d, 0xee, 0xa9, 0x12,
+	0x2a, 0x41, 0xf5, 0x4a, 0xf0, 0x85, 0xb9, 0xa7, 0x82, 0x1b, 0x54, 0xcb, 0x54, 0x53, 0x37, 0xa9,
+	0x96, 0xa9, 0xa6, 0xf5, 0xaf, 0x00, 0x2e, 0x98, 0x43, 0xe2, 0x06, 0x7d, 0x1c, 0xde, 0xf1, 0xd6,
+	0x3f, 0x84, 0x79, 0x92, 0x28, 0x4f, 0xdf, 0x25, 0xba, 0x94, 0x9a, 0x9a, 0x4a, 0xfe, 0x92, 0x4b,
+	0x6a, 0x63, 0xe3, 0x68, 0xac, 0x49, 0xc7, 0x63, 0x4d, 0x3a, 0x19, 0x6b, 0xd2, 0xf9, 0x58, 0x03,
+	0x1f, 0x26, 0x1a, 0xf8, 0x32, 0xd1, 0xc0, 0xd1, 0x44, 0x03, 0xc7, 0x13, 0x0d, 0xfc, 0x9c, 0x68,
+	0xe0, 0xd7, 0x44, 0x93, 0xce, 0x27, 0x1a, 0xf8, 0x74, 0xa6, 0x49, 0xc7, 0x67, 0x9a, 0x74, 0x72,
+	0xa6, 0x49, 0xaf, 0x73, 0x11, 0xf3, 0x43, 0x12, 0xb4, 0x5a, 0x59, 0xfe, 0xf1, 0x7e, 0xf0, 0x7b,
+	0x00, 0xf8, 0x8c, 0x4c, 0x5f, 0x34, 0x06, 0x00, 0x00,
 }
 
 func (x Chunk_Encoding) String() string {
@@ -761,6 +801,43 @@ func (this *LabelMatcher) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *ExemplarSeries) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*ExemplarSeries)
+	if !ok {
+		that2, ok := that.(ExemplarSeries)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.Labels) != len(that1.Labels) {
+		return false
+	}
+	for i := range this.Labels {
+		if !this.Labels[i].Equal(that1.Labels[i]) {
+			return false
+		}
+	}
+	if len(this.Exemplars) != len(that1.Exemplars) {
+		return false
+	}
+	for i := range this.Exemplars {
+		if !this.Exemplars[i].Equal(&that1.Exemplars[i]) {
+			return false
+		}
+	}
+	return true
+}
 func (this *Chunk) GoString() string {
 	if this == nil {
 		return "nil"
@@ -875,6 +952,23 @@ func (this *LabelMatcher) GoString() string {
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
+func (this *ExemplarSeries) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&storepb.ExemplarSeries{")
+	s = append(s, "Labels: "+fmt.Sprintf("%#v", this.Labels)+",\n")
+	if this.Exemplars != nil {
+		vs := make([]mimirpb.Exemplar, len(this.Exemplars))
+		for i := range vs {
+			vs[i] = this.Exemplars[i]
+		}
+		s = append(s, "Exemplars: "+fmt.Sprintf("%#v", vs)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
 func valueToGoStringTypes(v interface{}, typ string) string {
 	rv := reflect.ValueOf(v)
 	if rv.IsNil() {
@@ -1248,6 +1342,57 @@ func (m *LabelMatcher) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *ExemplarSeries) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ExemplarSeries) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ExemplarSeries) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Exemplars) > 0 {
+		for iNdEx := len(m.Exemplars) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Exemplars[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTypes(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Labels) > 0 {
+		for iNdEx := len(m.Labels) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size := m.Labels[iNdEx].Size()
+				i -= size
+				if _, err := m.Labels[iNdEx].MarshalTo(dAtA[i:]); err != nil {
+					return 0, err
+				}
+				i = encodeVarintTypes(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintTypes(dAtA []byte, offset int, v uint64) int {
 	offset -= sovTypes(v)
 	base := offset
@@ -1409,6 +1554,27 @@ func (m *LabelMatcher) Size() (n int) {
 	return n
 }
 
+func (m *ExemplarSeries) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Labels) > 0 {
+		for _, e := range m.Labels {
+			l = e.Size()
+			n += 1 + l + sovTypes(uint64(l))
+		}
+	}
+	if len(m.Exemplars) > 0 {
+		for _, e := range m.Exemplars {
+			l = e.Size()
+			n += 1 + l + sovTypes(uint64(l))
+		}
+	}
+	return n
+}
+
 func sovTypes(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -1533,6 +1699,22 @@ func (this *LabelMatcher) String() string {
 	}, "")
 	return s
 }
+func (this *ExemplarSeries) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForExemplars := "[]Exemplar{"
+	for _, f := range this.Exemplars {
+		repeatedStringForExemplars += fmt.Sprintf("%v", f) + ","
+	}
+	repeatedStringForExemplars += "}"
+	s := strings.Join([]string{`&ExemplarSeries{`,
+		`Labels:` + fmt.Sprintf("%v", this.Labels) + `,`,
+		`Exemplars:` + repeatedStringForExemplars + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func valueToStringTypes(v interface{}) string {
 	rv := reflect.ValueOf(v)
 	if rv.IsNil() {
@@ -2486,6 +2668,124 @@ func (m *LabelMatcher) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *ExemplarSeries) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ExemplarSeries: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ExemplarSeries: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Labels", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Labels = append(m.Labels, github_com_grafana_mimir_pkg_mimirpb.LabelAdapter{})
+			if err := m.Labels[len(m.Labels)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Exemplars", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Exemplars = append(m.Exemplars, mimirpb.Exemplar{})
+			if err := m.Exemplars[len(m.Exemplars)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipTypes(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
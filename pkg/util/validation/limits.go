@@ -65,6 +65,7 @@ const (
 var (
 	errInvalidIngestStorageReadConsistency         = fmt.Errorf("invalid ingest storage read consistency (supported values: %s)", strings.Join(api.ReadConsistencies, ", "))
 	errInvalidMaxEstimatedChunksPerQueryMultiplier = errors.New("invalid value for -" + MaxEstimatedChunksPerQueryMultiplierFlag + ": must be 0 or greater than or equal to 1")
+	errInvalidCompactorDownsampling1hAfter         = errors.New("invalid value for -compactor.downsampling-1h-after: must be 0 or greater than or equal to -compactor.downsampling-5m-after, which must be enabled")
 )
 
 // LimitError is a marker interface for the errors that do not comply with the specified limits.
@@ -118,6 +119,12 @@ type Limits struct {
 	MetricRelabelConfigs                        []*relabel.Config   `yaml:"metric_relabel_configs,omitempty" json:"metric_relabel_configs,omitempty" doc:"nocli|description=List of metric relabel configurations. Note that in most situations, it is more effective to use metrics relabeling directly in the Prometheus server, e.g. remote_write.write_relabel_configs. Labels available during the relabeling phase and cleaned afterwards: __meta_tenant_id" category:"experimental"`
 	MetricRelabelingEnabled                     bool                `yaml:"metric_relabeling_enabled" json:"metric_relabeling_enabled" category:"experimental"`
 	ServiceOverloadStatusCodeOnRateLimitEnabled bool                `yaml:"service_overload_status_code_on_rate_limit_enabled" json:"service_overload_status_code_on_rate_limit_enabled" category:"experimental"`
+
+	// Stream aggregation
+	StreamAggregationRules           []*StreamAggregationRule `yaml:"stream_aggregation_rules,omitempty" json:"stream_aggregation_rules,omitempty" doc:"nocli|description=List of stream aggregation rules. The samples of the series matching the match selector of a rule, received by each distributor over the rule interval, are aggregated by or without the configured labels into one series per output. The supported outputs are: total, sum, count, min and max. The aggregated series are named after the input metric, the interval, the grouping labels and the output, for example <metric>:1m_by_job_total, and have an aggregator label set to the distributor instance ID. Only the samples of the series passing the validation are aggregated. The input series are dropped if drop_input is true. The aggregated series are pushed through the distributor instance limits, the validation and the ingestion rate limit, but not the request rate limit." category:"experimental"`
+	StreamAggregationMaxInputSeries  int                      `yaml:"stream_aggregation_max_input_series" json:"stream_aggregation_max_input_series" category:"experimental"`
+	StreamAggregationMaxOutputSeries int                      `yaml:"stream_aggregation_max_output_series" json:"stream_aggregation_max_output_series" category:"experimental"`
+
 	// Ingester enforced limits.
 	// Series
 	MaxGlobalSeriesPerUser   int `yaml:"max_global_series_per_user" json:"max_global_series_per_user"`
@@ -131,6 +138,9 @@ type Limits struct {
 	NativeHistogramsIngestionEnabled bool `yaml:"native_histograms_ingestion_enabled" json:"native_histograms_ingestion_enabled" category:"experimental"`
 	// Active series custom trackers
 	ActiveSeriesCustomTrackersConfig activeseries.CustomTrackersConfig `yaml:"active_series_custom_trackers" json:"active_series_custom_trackers" doc:"description=Additional custom trackers for active metrics. If there are active series matching a provided matcher (map value), the count will be exposed in the custom trackers metric labeled using the tracker name (map key). Zero valued counts are not exposed (and removed when they go back to zero)." category:"advanced"`
+	// Active series cost attribution
+	CostAttributionLabel      string `yaml:"cost_attribution_label" json:"cost_attribution_label" category:"experimental"`
+	MaxCostAttributionPerUser int    `yaml:"max_cost_attribution_per_user" json:"max_cost_attribution_per_user" category:"experimental"`
 	// Max allowed time window for out-of-order samples.
 	OutOfOrderTimeWindow                 model.Duration `yaml:"out_of_order_time_window" json:"out_of_order_time_window" category:"experimental"`
 	OutOfOrderBlocksExternalLabelEnabled bool           `yaml:"out_of_order_blocks_external_label_enabled" json:"out_of_order_blocks_external_label_enabled" category:"experimental"`
@@ -195,6 +205,11 @@ type Limits struct {
 	CompactorBlockUploadVerifyChunks      bool           `yaml:"compactor_block_upload_verify_chunks" json:"compactor_block_upload_verify_chunks"`
 	CompactorBlockUploadMaxBlockSizeBytes int64          `yaml:"compactor_block_upload_max_block_size_bytes" json:"compactor_block_upload_max_block_size_bytes" category:"advanced"`
 
+	CompactorDownsampling5mAfter model.Duration `yaml:"compactor_downsampling_5m_after" json:"compactor_downsampling_5m_after" category:"experimental"`
+	CompactorDownsampling1hAfter model.Duration `yaml:"compactor_downsampling_1h_after" json:"compactor_downsampling_1h_after" category:"experimental"`
+
+	CompactorBlocksRetentionRules []*RetentionRule `yaml:"compactor_blocks_retention_rules,omitempty" json:"compactor_blocks_retention_rules,omitempty" doc:"nocli|description=List of retention rules, each with a match selector and a retention period. The compactor rewrites the blocks whose time range is entirely older than the period of a rule, dropping the series matching the rule selector. The compactor_blocks_retention_period still applies to all the series." category:"experimental"`
+
 	// This config doesn't have a CLI flag registered here because they're registered in
 	// their own original config struct.
 	S3SSEType                 string `yaml:"s3_sse_type" json:"s3_sse_type" doc:"nocli|description=S3 server-side encryption type. Required to enable server-side encryption overrides for a specific tenant. If not set, the default S3 client settings are used."`
@@ -216,7 +231,14 @@ type Limits struct {
 	AlertmanagerMaxAlertsSizeBytes             int `yaml:"alertmanager_max_alerts_size_bytes" json:"alertmanager_max_alerts_size_bytes"`
 
 	// OpenTelemetry
-	OTelMetricSuffixesEnabled bool `yaml:"otel_metric_suffixes_enabled" json:"otel_metric_suffixes_enabled" category:"advanced"`
+	OTelMetricSuffixesEnabled       bool                   `yaml:"otel_metric_suffixes_enabled" json:"otel_metric_suffixes_enabled" category:"advanced"`
+	OTelDeltaToCumulativeEnabled    bool                   `yaml:"otel_delta_to_cumulative_enabled" json:"otel_delta_to_cumulative_enabled" category:"experimental"`
+	OTelDeltaToCumulativeMaxStreams int                    `yaml:"otel_delta_to_cumulative_max_streams" json:"otel_delta_to_cumulative_max_streams" category:"experimental"`
+	OTelDeltaToCumulativeMaxStale   model.Duration         `yaml:"otel_delta_to_cumulative_max_stale" json:"otel_delta_to_cumulative_max_stale" category:"experimental"`
+	PromoteOTelResourceAttributes   flagext.StringSliceCSV `yaml:"promote_otel_resource_attributes" json:"promote_otel_resource_attributes" category:"experimental"`
+
+	// Remote write 2.0
+	CreatedTimestampZeroIngestionEnabled bool `yaml:"created_timestamp_zero_ingestion_enabled" json:"created_timestamp_zero_ingestion_enabled" category:"experimental"`
 
 	// Ingest storage.
 	IngestStorageReadConsistency       string `yaml:"ingest_storage_read_consistency" json:"ingest_storage_read_consistency" category:"experimental" doc:"hidden"`
@@ -250,6 +272,14 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 	f.BoolVar(&l.MetricRelabelingEnabled, "distributor.metric-relabeling-enabled", true, "Enable metric relabeling for the tenant. This configuration option can be used to forcefully disable metric relabeling on a per-tenant basis.")
 	f.BoolVar(&l.ServiceOverloadStatusCodeOnRateLimitEnabled, "distributor.service-overload-status-code-on-rate-limit-enabled", false, "If enabled, rate limit errors will be reported to the client with HTTP status code 529 (Service is overloaded). If disabled, status code 429 (Too Many Requests) is used. Enabling -distributor.retry-after-header.enabled before utilizing this option is strongly recommended as it helps prevent premature request retries by the client.")
 	f.BoolVar(&l.OTelMetricSuffixesEnabled, "distributor.otel-metric-suffixes-enabled", false, "Whether to enable automatic suffixes to names of metrics ingested through OTLP.")
+	f.BoolVar(&l.OTelDeltaToCumulativeEnabled, "distributor.otel-delta-to-cumulative-enabled", false, "Whether to accumulate the data points of OTLP sums, histograms and exponential histograms with delta temporality into cumulative series. The accumulation state is kept in memory by each distributor, so all the data points of a series must be sent to the same distributor.")
+	f.IntVar(&l.OTelDeltaToCumulativeMaxStreams, "distributor.otel-delta-to-cumulative-max-streams", 10000, "The maximum number of OTLP delta streams accumulated per tenant by each distributor. Data points of new streams are discarded once the limit is reached. 0 to disable.")
+	_ = l.OTelDeltaToCumulativeMaxStale.Set("5m")
+	f.Var(&l.OTelDeltaToCumulativeMaxStale, "distributor.otel-delta-to-cumulative-max-stale", "How long an OTLP delta stream is kept in memory after its last data point has been received.")
+	f.Var(&l.PromoteOTelResourceAttributes, "distributor.promote-otel-resource-attributes", "Comma-separated list of OTLP resource attributes to promote to labels of the series ingested through OTLP.")
+	f.IntVar(&l.StreamAggregationMaxInputSeries, "distributor.stream-aggregation-max-input-series", 100000, "The maximum number of input series aggregated by the stream aggregation rules per tenant by each distributor. New input series aren't aggregated once the limit is reached, and aren't dropped even if drop_input is true. 0 to disable.")
+	f.IntVar(&l.StreamAggregationMaxOutputSeries, "distributor.stream-aggregation-max-output-series", 10000, "The maximum number of output series, before applying the outputs, aggregated by the stream aggregation rules per tenant by each distributor. The input series of new output series aren't aggregated once the limit is reached, and aren't dropped even if drop_input is true. 0 to disable.")
+	f.BoolVar(&l.CreatedTimestampZeroIngestionEnabled, "distributor.created-timestamp-zero-ingestion-enabled", false, "Whether to ingest a zero sample at the created timestamp of the series received through remote write 2.0, when the created timestamp precedes the first sample of the series in the request.")
 
 	f.IntVar(&l.MaxGlobalSeriesPerUser, MaxSeriesPerUserFlag, 150000, "The maximum number of in-memory series per tenant, across the cluster before replication. 0 to disable.")
 	f.IntVar(&l.MaxGlobalSeriesPerMetric, MaxSeriesPerMetricFlag, 0, "The maximum number of in-memory series per metric name, across the cluster before replication. 0 to disable.")
@@ -258,6 +288,8 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 	f.IntVar(&l.MaxGlobalMetadataPerMetric, MaxMetadataPerMetricFlag, 0, "The maximum number of metadata per metric, across the cluster. 0 to disable.")
 	f.IntVar(&l.MaxGlobalExemplarsPerUser, "ingester.max-global-exemplars-per-user", 0, "The maximum number of exemplars in memory, across the cluster. 0 to disable exemplars ingestion.")
 	f.Var(&l.ActiveSeriesCustomTrackersConfig, "ingester.active-series-custom-trackers", "Additional active series metrics, matching the provided matchers. Matchers should be in form <name>:<matcher>, like 'foobar:{foo=\"bar\"}'. Multiple matchers can be provided either providing the flag multiple times or providing multiple semicolon-separated values to a single flag.")
+	f.StringVar(&l.CostAttributionLabel, "ingester.cost-attribution-label", "", "Label whose values the active series and the received samples of the tenant are grouped by, and exposed in the attributed metrics. Empty to disable the cost attribution.")
+	f.IntVar(&l.MaxCostAttributionPerUser, "ingester.max-cost-attribution-per-user", 100, "The maximum number of distinct values of the cost attribution label per tenant, in each ingester. The series with additional values are attributed to the __overflow__ value.")
 	f.Var(&l.OutOfOrderTimeWindow, "ingester.out-of-order-time-window", fmt.Sprintf("Non-zero value enables out-of-order support for most recent samples that are within the time window in relation to the TSDB's maximum time, i.e., within [db.maxTime-timeWindow, db.maxTime]). The ingester will need more memory as a factor of rate of out-of-order samples being ingested and the number of series that are getting out-of-order samples. If query falls into this window, cached results will use value from -%s option to specify TTL for resulting cache entry.", resultsCacheTTLForOutOfOrderWindowFlag))
 	f.BoolVar(&l.NativeHistogramsIngestionEnabled, "ingester.native-histograms-ingestion-enabled", false, "Enable ingestion of native histogram samples. If false, native histogram samples are ignored without an error. To query native histograms with query-sharding enabled make sure to set -query-frontend.query-result-response-format to 'protobuf'.")
 	f.BoolVar(&l.OutOfOrderBlocksExternalLabelEnabled, "ingester.out-of-order-blocks-external-label-enabled", false, "Whether the shipper should label out-of-order blocks with an external label before uploading them. Setting this label will compact out-of-order blocks separately from non-out-of-order blocks")
@@ -306,6 +338,8 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 	f.BoolVar(&l.CompactorBlockUploadValidationEnabled, "compactor.block-upload-validation-enabled", true, "Enable block upload validation for the tenant.")
 	f.BoolVar(&l.CompactorBlockUploadVerifyChunks, "compactor.block-upload-verify-chunks", true, "Verify chunks when uploading blocks via the upload API for the tenant.")
 	f.Int64Var(&l.CompactorBlockUploadMaxBlockSizeBytes, "compactor.block-upload-max-block-size-bytes", 0, "Maximum size in bytes of a block that is allowed to be uploaded or validated. 0 = no limit.")
+	f.Var(&l.CompactorDownsampling5mAfter, "compactor.downsampling-5m-after", "Downsample the blocks whose samples are all older than the specified age to a 5m resolution. The raw blocks are kept. 0 to disable.")
+	f.Var(&l.CompactorDownsampling1hAfter, "compactor.downsampling-1h-after", "Downsample the 5m resolution blocks whose samples are all older than the specified age to a 1h resolution. Must be greater than or equal to -compactor.downsampling-5m-after, which must be enabled. 0 to disable.")
 
 	// Query-frontend.
 	f.Var(&l.MaxTotalQueryLength, MaxTotalQueryLengthFlag, "Limit the total query time range (end - start time). This limit is enforced in the query-frontend on the received query.")
@@ -405,10 +439,32 @@ func (l *Limits) validate() error {
 		}
 	}
 
+	for _, rule := range l.StreamAggregationRules {
+		if rule == nil {
+			return errors.New("invalid stream_aggregation_rules")
+		}
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, rule := range l.CompactorBlocksRetentionRules {
+		if rule == nil {
+			return errors.New("invalid compactor_blocks_retention_rules")
+		}
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+	}
+
 	if l.MaxEstimatedChunksPerQueryMultiplier < 1 && l.MaxEstimatedChunksPerQueryMultiplier != 0 {
 		return errInvalidMaxEstimatedChunksPerQueryMultiplier
 	}
 
+	if l.CompactorDownsampling1hAfter != 0 && (l.CompactorDownsampling5mAfter == 0 || l.CompactorDownsampling1hAfter < l.CompactorDownsampling5mAfter) {
+		return errInvalidCompactorDownsampling1hAfter
+	}
+
 	if !util.StringsContain(api.ReadConsistencies, l.IngestStorageReadConsistency) {
 		return errInvalidIngestStorageReadConsistency
 	}
@@ -704,6 +760,16 @@ func (o *Overrides) ActiveSeriesCustomTrackersConfig(userID string) activeseries
 	return o.getOverridesForUser(userID).ActiveSeriesCustomTrackersConfig
 }
 
+// CostAttributionLabel returns the label the active series and received samples of the user are grouped by.
+func (o *Overrides) CostAttributionLabel(userID string) string {
+	return o.getOverridesForUser(userID).CostAttributionLabel
+}
+
+// MaxCostAttributionPerUser returns the maximum number of distinct values of the cost attribution label.
+func (o *Overrides) MaxCostAttributionPerUser(userID string) int {
+	return o.getOverridesForUser(userID).MaxCostAttributionPerUser
+}
+
 // OutOfOrderTimeWindow returns the out-of-order time window for the user.
 func (o *Overrides) OutOfOrderTimeWindow(userID string) time.Duration {
 	return time.Duration(o.getOverridesForUser(userID).OutOfOrderTimeWindow)
@@ -739,6 +805,21 @@ func (o *Overrides) CompactorBlocksRetentionPeriod(userID string) time.Duration
 	return time.Duration(o.getOverridesForUser(userID).CompactorBlocksRetentionPeriod)
 }
 
+// CompactorBlocksRetentionRules returns the retention rules for a given user.
+func (o *Overrides) CompactorBlocksRetentionRules(userID string) []*RetentionRule {
+	return o.getOverridesForUser(userID).CompactorBlocksRetentionRules
+}
+
+// CompactorDownsampling5mAfter returns the age after which the blocks of a given user are downsampled to a 5m resolution.
+func (o *Overrides) CompactorDownsampling5mAfter(userID string) time.Duration {
+	return time.Duration(o.getOverridesForUser(userID).CompactorDownsampling5mAfter)
+}
+
+// CompactorDownsampling1hAfter returns the age after which the blocks of a given user are downsampled to a 1h resolution.
+func (o *Overrides) CompactorDownsampling1hAfter(userID string) time.Duration {
+	return time.Duration(o.getOverridesForUser(userID).CompactorDownsampling1hAfter)
+}
+
 // CompactorSplitAndMergeShards returns the number of shards to use when splitting blocks.
 func (o *Overrides) CompactorSplitAndMergeShards(userID string) int {
 	return o.getOverridesForUser(userID).CompactorSplitAndMergeShards
@@ -788,6 +869,23 @@ func (o *Overrides) MetricRelabelConfigs(userID string) []*relabel.Config {
 	return o.getOverridesForUser(userID).MetricRelabelConfigs
 }
 
+// StreamAggregationRules returns the stream aggregation rules for a given user.
+func (o *Overrides) StreamAggregationRules(userID string) []*StreamAggregationRule {
+	return o.getOverridesForUser(userID).StreamAggregationRules
+}
+
+// StreamAggregationMaxInputSeries returns the maximum number of input series aggregated by the stream aggregation
+// rules for a given user.
+func (o *Overrides) StreamAggregationMaxInputSeries(userID string) int {
+	return o.getOverridesForUser(userID).StreamAggregationMaxInputSeries
+}
+
+// StreamAggregationMaxOutputSeries returns the maximum number of output series aggregated by the stream aggregation
+// rules for a given user.
+func (o *Overrides) StreamAggregationMaxOutputSeries(userID string) int {
+	return o.getOverridesForUser(userID).StreamAggregationMaxOutputSeries
+}
+
 func (o *Overrides) MetricRelabelingEnabled(userID string) bool {
 	return o.getOverridesForUser(userID).MetricRelabelingEnabled
 }
@@ -960,6 +1058,26 @@ func (o *Overrides) OTelMetricSuffixesEnabled(tenantID string) bool {
 	return o.getOverridesForUser(tenantID).OTelMetricSuffixesEnabled
 }
 
+func (o *Overrides) OTelDeltaToCumulativeEnabled(tenantID string) bool {
+	return o.getOverridesForUser(tenantID).OTelDeltaToCumulativeEnabled
+}
+
+func (o *Overrides) OTelDeltaToCumulativeMaxStreams(tenantID string) int {
+	return o.getOverridesForUser(tenantID).OTelDeltaToCumulativeMaxStreams
+}
+
+func (o *Overrides) OTelDeltaToCumulativeMaxStale(tenantID string) time.Duration {
+	return time.Duration(o.getOverridesForUser(tenantID).OTelDeltaToCumulativeMaxStale)
+}
+
+func (o *Overrides) PromoteOTelResourceAttributes(tenantID string) []string {
+	return o.getOverridesForUser(tenantID).PromoteOTelResourceAttributes
+}
+
+func (o *Overrides) CreatedTimestampZeroIngestionEnabled(tenantID string) bool {
+	return o.getOverridesForUser(tenantID).CreatedTimestampZeroIngestionEnabled
+}
+
 func (o *Overrides) AlignQueriesWithStep(userID string) bool {
 	return o.getOverridesForUser(userID).AlignQueriesWithStep
 }
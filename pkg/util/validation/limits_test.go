@@ -670,6 +670,82 @@ metric_relabel_configs:
 			cfg:         `ingest_storage_read_consistency: xyz`,
 			expectedErr: errInvalidIngestStorageReadConsistency.Error(),
 		},
+		"should fail on empty stream_aggregation_rules entry": {
+			cfg: `
+stream_aggregation_rules:
+  -
+`,
+			expectedErr: "invalid stream_aggregation_rules",
+		},
+		"should fail on invalid stream_aggregation_rules": {
+			cfg: `
+stream_aggregation_rules:
+  - match: '{__name__="http_requests_total"}'
+    interval: 1m
+    outputs: [avg]
+`,
+			expectedErr: "unsupported stream aggregation rule output",
+		},
+		"should pass on valid stream_aggregation_rules": {
+			cfg: `
+stream_aggregation_rules:
+  - match: '{__name__="http_requests_total"}'
+    interval: 1m
+    by: [job]
+    outputs: [total, count]
+`,
+			expectedErr: "",
+		},
+		"should fail on empty compactor_blocks_retention_rules entry": {
+			cfg: `
+compactor_blocks_retention_rules:
+  -
+`,
+			expectedErr: "invalid compactor_blocks_retention_rules",
+		},
+		"should fail on invalid compactor_blocks_retention_rules selector": {
+			cfg: `
+compactor_blocks_retention_rules:
+  - match: '{job='
+    period: 1d
+`,
+			expectedErr: "invalid retention rule match selector",
+		},
+		"should fail on compactor_blocks_retention_rules with no period": {
+			cfg: `
+compactor_blocks_retention_rules:
+  - match: '{job="debug"}'
+`,
+			expectedErr: "retention rule period must be greater than 0",
+		},
+		"should fail on compactor_downsampling_1h_after without compactor_downsampling_5m_after": {
+			cfg:         `compactor_downsampling_1h_after: 30d`,
+			expectedErr: errInvalidCompactorDownsampling1hAfter.Error(),
+		},
+		"should fail on compactor_downsampling_1h_after lower than compactor_downsampling_5m_after": {
+			cfg: `
+compactor_downsampling_5m_after: 30d
+compactor_downsampling_1h_after: 7d
+`,
+			expectedErr: errInvalidCompactorDownsampling1hAfter.Error(),
+		},
+		"should pass on valid compactor downsampling ages": {
+			cfg: `
+compactor_downsampling_5m_after: 7d
+compactor_downsampling_1h_after: 30d
+`,
+			expectedErr: "",
+		},
+		"should pass on valid compactor_blocks_retention_rules": {
+			cfg: `
+compactor_blocks_retention_rules:
+  - match: '{job="debug"}'
+    period: 1d
+  - match: '{__name__=~"slo_.+"}'
+    period: 1y
+`,
+			expectedErr: "",
+		},
 	}
 
 	for testName, testData := range tests {
@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package validation
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// RetentionRule configures the retention period of the series matching a selector, enforced by the compactor.
+type RetentionRule struct {
+	Match  string         `yaml:"match" json:"match"`
+	Period model.Duration `yaml:"period" json:"period"`
+
+	matchers []*labels.Matcher
+}
+
+// Validate validates the rule and parses its selector.
+func (r *RetentionRule) Validate() error {
+	if r.Match == "" {
+		return errors.New("retention rule has no match selector")
+	}
+	matchers, err := parser.ParseMetricSelector(r.Match)
+	if err != nil {
+		return errors.Wrapf(err, "invalid retention rule match selector %q", r.Match)
+	}
+	if r.Period <= 0 {
+		return fmt.Errorf("retention rule period must be greater than 0, got %s", r.Period)
+	}
+
+	r.matchers = matchers
+	return nil
+}
+
+// Matchers returns the matchers of the rule selector. The rule must have been validated.
+func (r *RetentionRule) Matchers() []*labels.Matcher {
+	return r.matchers
+}
@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package validation
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// Supported outputs of the stream aggregation rules.
+const (
+	StreamAggregationOutputTotal = "total"
+	StreamAggregationOutputSum   = "sum"
+	StreamAggregationOutputCount = "count"
+	StreamAggregationOutputMin   = "min"
+	StreamAggregationOutputMax   = "max"
+)
+
+var streamAggregationOutputs = []string{
+	StreamAggregationOutputTotal,
+	StreamAggregationOutputSum,
+	StreamAggregationOutputCount,
+	StreamAggregationOutputMin,
+	StreamAggregationOutputMax,
+}
+
+// StreamAggregationRule configures the aggregation of the samples of the series matching a selector,
+// received by the distributor over an interval, into aggregated series.
+type StreamAggregationRule struct {
+	Match     string         `yaml:"match" json:"match"`
+	Interval  model.Duration `yaml:"interval" json:"interval"`
+	By        []string       `yaml:"by,omitempty" json:"by,omitempty"`
+	Without   []string       `yaml:"without,omitempty" json:"without,omitempty"`
+	Outputs   []string       `yaml:"outputs" json:"outputs"`
+	DropInput bool           `yaml:"drop_input,omitempty" json:"drop_input,omitempty"`
+
+	matchers []*labels.Matcher
+}
+
+// Validate validates the rule and parses its selector.
+func (r *StreamAggregationRule) Validate() error {
+	if r.Match == "" {
+		return errors.New("stream aggregation rule has no match selector")
+	}
+	matchers, err := parser.ParseMetricSelector(r.Match)
+	if err != nil {
+		return errors.Wrapf(err, "invalid stream aggregation rule match selector %q", r.Match)
+	}
+	if time.Duration(r.Interval) < time.Second {
+		return fmt.Errorf("stream aggregation rule interval must be at least 1s, got %s", r.Interval)
+	}
+	if len(r.By) > 0 && len(r.Without) > 0 {
+		return errors.New("stream aggregation rule can't have both by and without labels")
+	}
+	if slices.Contains(r.By, labels.MetricName) || slices.Contains(r.Without, labels.MetricName) {
+		return fmt.Errorf("stream aggregation rule can't aggregate by or without the %s label", labels.MetricName)
+	}
+	if len(r.Outputs) == 0 {
+		return errors.New("stream aggregation rule has no outputs")
+	}
+	for _, output := range r.Outputs {
+		if !slices.Contains(streamAggregationOutputs, output) {
+			return fmt.Errorf("unsupported stream aggregation rule output %q, supported outputs are: %s", output, strings.Join(streamAggregationOutputs, ", "))
+		}
+	}
+
+	r.matchers = matchers
+	return nil
+}
+
+// Matchers returns the matchers of the rule selector. The rule must have been validated.
+func (r *StreamAggregationRule) Matchers() []*labels.Matcher {
+	return r.matchers
+}
+
+// Key returns a string identifying the aggregation of the rule.
+func (r *StreamAggregationRule) Key() string {
+	return strings.Join([]string{r.Match, r.Interval.String(), strings.Join(r.By, ","), strings.Join(r.Without, ","), strings.Join(r.Outputs, ",")}, "\xff")
+}
+
+// OutputMetricName returns the name of the aggregated series of the metric for the output, following
+// the <metric>:<interval>[_by_<labels>|_without_<labels>]_<output> convention.
+func (r *StreamAggregationRule) OutputMetricName(metric, output string) string {
+	name := metric + ":" + r.Interval.String()
+	if len(r.By) > 0 {
+		name += "_by_" + strings.Join(r.By, "_")
+	}
+	if len(r.Without) > 0 {
+		name += "_without_" + strings.Join(r.Without, "_")
+	}
+	return name + "_" + output
+}
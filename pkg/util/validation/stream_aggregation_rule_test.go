@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package validation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamAggregationRule_Validate(t *testing.T) {
+	valid := func() StreamAggregationRule {
+		return StreamAggregationRule{
+			Match:    `{__name__="http_requests_total"}`,
+			Interval: model.Duration(time.Minute),
+			Without:  []string{"pod"},
+			Outputs:  []string{StreamAggregationOutputTotal},
+		}
+	}
+
+	rule := valid()
+	require.NoError(t, rule.Validate())
+	require.Len(t, rule.Matchers(), 1)
+	assert.Equal(t, "http_requests_total:1m_without_pod_total", rule.OutputMetricName("http_requests_total", StreamAggregationOutputTotal))
+
+	for name, tc := range map[string]struct {
+		modify      func(*StreamAggregationRule)
+		expectedErr string
+	}{
+		"missing match":       {modify: func(r *StreamAggregationRule) { r.Match = "" }, expectedErr: "no match selector"},
+		"invalid match":       {modify: func(r *StreamAggregationRule) { r.Match = "{" }, expectedErr: "invalid stream aggregation rule match selector"},
+		"too short interval":  {modify: func(r *StreamAggregationRule) { r.Interval = 0 }, expectedErr: "interval must be at least 1s"},
+		"both by and without": {modify: func(r *StreamAggregationRule) { r.By = []string{"job"} }, expectedErr: "both by and without"},
+		"without metric name": {modify: func(r *StreamAggregationRule) { r.Without = []string{"__name__"} }, expectedErr: "__name__"},
+		"no outputs":          {modify: func(r *StreamAggregationRule) { r.Outputs = nil }, expectedErr: "no outputs"},
+		"unsupported output":  {modify: func(r *StreamAggregationRule) { r.Outputs = []string{"avg"} }, expectedErr: `unsupported stream aggregation rule output "avg"`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			rule := valid()
+			tc.modify(&rule)
+			require.ErrorContains(t, rule.Validate(), tc.expectedErr)
+		})
+	}
+}
@@ -348,6 +348,10 @@ func getFieldCustomType(t reflect.Type) (string, bool) {
 		return "relabel_config...", true
 	case reflect.TypeOf([]*validation.BlockedQuery{}).String():
 		return "blocked_queries_config...", true
+	case reflect.TypeOf([]*validation.StreamAggregationRule{}).String():
+		return "stream_aggregation_rule_config...", true
+	case reflect.TypeOf([]*validation.RetentionRule{}).String():
+		return "retention_rule_config...", true
 	case reflect.TypeOf(activeseries.CustomTrackersConfig{}).String():
 		return "map of tracker name (string) to matcher (string)", true
 	default:
@@ -430,6 +434,10 @@ func getCustomFieldType(t reflect.Type) (string, bool) {
 		return "relabel_config...", true
 	case reflect.TypeOf([]*validation.BlockedQuery{}).String():
 		return "blocked_queries_config...", true
+	case reflect.TypeOf([]*validation.StreamAggregationRule{}).String():
+		return "stream_aggregation_rule_config...", true
+	case reflect.TypeOf([]*validation.RetentionRule{}).String():
+		return "retention_rule_config...", true
 	case reflect.TypeOf(activeseries.CustomTrackersConfig{}).String():
 		return "map of tracker name (string) to matcher (string)", true
 	default:
@@ -463,6 +471,10 @@ func ReflectType(typ string) reflect.Type {
 		return reflect.TypeOf([]*relabel.Config{})
 	case "blocked_queries_config...":
 		return reflect.TypeOf([]*validation.BlockedQuery{})
+	case "stream_aggregation_rule_config...":
+		return reflect.TypeOf([]*validation.StreamAggregationRule{})
+	case "retention_rule_config...":
+		return reflect.TypeOf([]*validation.RetentionRule{})
 	case "map of string to float64":
 		return reflect.TypeOf(map[string]float64{})
 	case "list of durations":
@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querytee
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// CardinalityComparator compares responses of the cardinality API routes. Objects are compared
+// field by field, while arrays are compared as sets, regardless of the order of their items.
+type CardinalityComparator struct{}
+
+func NewCardinalityComparator() *CardinalityComparator {
+	return &CardinalityComparator{}
+}
+
+func (c *CardinalityComparator) Compare(expectedResponse, actualResponse []byte) (ComparisonResult, error) {
+	var expected, actual interface{}
+
+	if err := json.Unmarshal(expectedResponse, &expected); err != nil {
+		return ComparisonFailed, errors.Wrap(err, "unable to unmarshal expected response")
+	}
+
+	if err := json.Unmarshal(actualResponse, &actual); err != nil {
+		return ComparisonFailed, errors.Wrap(err, "unable to unmarshal actual response")
+	}
+
+	if err := compareUnorderedJSON("$", expected, actual); err != nil {
+		return ComparisonFailed, err
+	}
+
+	return ComparisonSuccess, nil
+}
+
+// compareUnorderedJSON compares two values decoded from JSON, ignoring the order of array items.
+// The path of the first difference found is included in the returned error.
+func compareUnorderedJSON(path string, expected, actual interface{}) error {
+	switch expectedValue := expected.(type) {
+	case map[string]interface{}:
+		actualValue, ok := actual.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("expected object at %s but got %s", path, jsonString(actual))
+		}
+
+		if len(expectedValue) != len(actualValue) {
+			return fmt.Errorf("expected %d fields at %s but got %d", len(expectedValue), path, len(actualValue))
+		}
+
+		// Iterate in a deterministic order, so that the reported difference is stable.
+		keys := make([]string, 0, len(expectedValue))
+		for key := range expectedValue {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			fieldPath := path + "." + key
+
+			actualField, ok := actualValue[key]
+			if !ok {
+				return fmt.Errorf("expected field %s missing from actual response", fieldPath)
+			}
+
+			if err := compareUnorderedJSON(fieldPath, expectedValue[key], actualField); err != nil {
+				return err
+			}
+		}
+
+		return nil
+
+	case []interface{}:
+		actualValue, ok := actual.([]interface{})
+		if !ok {
+			return fmt.Errorf("expected array at %s but got %s", path, jsonString(actual))
+		}
+
+		if len(expectedValue) != len(actualValue) {
+			return fmt.Errorf("expected %d items at %s but got %d", len(expectedValue), path, len(actualValue))
+		}
+
+		// The items are compared by their canonical JSON encoding (object keys are sorted by the encoder
+		// and nested arrays are sorted by canonicalJSON), counting duplicates.
+		actualItems := make(map[string]int, len(actualValue))
+		for _, item := range actualValue {
+			actualItems[canonicalJSON(item)]++
+		}
+
+		for _, item := range expectedValue {
+			key := canonicalJSON(item)
+			if actualItems[key] == 0 {
+				return fmt.Errorf("expected item %s at %s missing from actual response", key, path)
+			}
+			actualItems[key]--
+		}
+
+		return nil
+
+	default:
+		if expected != actual {
+			return fmt.Errorf("expected %s at %s but got %s", jsonString(expected), path, jsonString(actual))
+		}
+
+		return nil
+	}
+}
+
+// canonicalJSON returns the JSON encoding of a value decoded from JSON, with arrays sorted recursively
+// so that values which differ only in the order of array items have the same encoding.
+func canonicalJSON(v interface{}) string {
+	return jsonString(canonicalize(v))
+}
+
+func canonicalize(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(value))
+		for key, field := range value {
+			out[key] = canonicalize(field)
+		}
+		return out
+
+	case []interface{}:
+		encoded := make([]string, 0, len(value))
+		for _, item := range value {
+			encoded = append(encoded, canonicalJSON(item))
+		}
+		sort.Strings(encoded)
+
+		out := make([]interface{}, 0, len(encoded))
+		for _, item := range encoded {
+			out = append(out, json.RawMessage(item))
+		}
+		return out
+
+	default:
+		return v
+	}
+}
+
+func jsonString(v interface{}) string {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(encoded)
+}
@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querytee
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCardinalityComparator(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		expected string
+		actual   string
+		err      string
+	}{
+		{
+			name:     "label names with items in a different order",
+			expected: `{"label_values_count_total":3,"label_names_count":2,"cardinality":[{"label_name":"job","label_values_count":2},{"label_name":"__name__","label_values_count":1}]}`,
+			actual:   `{"label_names_count":2,"label_values_count_total":3,"cardinality":[{"label_name":"__name__","label_values_count":1},{"label_name":"job","label_values_count":2}]}`,
+		},
+		{
+			name:     "label values with nested items in a different order",
+			expected: `{"series_count_total":3,"labels":[{"label_name":"job","label_values_count":2,"series_count":3,"cardinality":[{"label_value":"a","series_count":2},{"label_value":"b","series_count":1}]}]}`,
+			actual:   `{"series_count_total":3,"labels":[{"label_name":"job","label_values_count":2,"series_count":3,"cardinality":[{"label_value":"b","series_count":1},{"label_value":"a","series_count":2}]}]}`,
+		},
+		{
+			name:     "active series in a different order",
+			expected: `{"data":[{"__name__":"up","job":"a"},{"__name__":"up","job":"b"}]}`,
+			actual:   `{"data":[{"__name__":"up","job":"b"},{"__name__":"up","job":"a"}]}`,
+		},
+		{
+			name:     "different scalar field",
+			expected: `{"label_values_count_total":3,"label_names_count":2,"cardinality":[]}`,
+			actual:   `{"label_values_count_total":4,"label_names_count":2,"cardinality":[]}`,
+			err:      "expected 3 at $.label_values_count_total but got 4",
+		},
+		{
+			name:     "missing field",
+			expected: `{"label_values_count_total":3,"label_names_count":2}`,
+			actual:   `{"label_values_count_total":3,"cardinality":[]}`,
+			err:      "expected field $.label_names_count missing from actual response",
+		},
+		{
+			name:     "different number of items",
+			expected: `{"data":[{"job":"a"},{"job":"b"}]}`,
+			actual:   `{"data":[{"job":"a"}]}`,
+			err:      "expected 2 items at $.data but got 1",
+		},
+		{
+			name:     "different items",
+			expected: `{"data":[{"job":"a"},{"job":"a"}]}`,
+			actual:   `{"data":[{"job":"a"},{"job":"b"}]}`,
+			err:      `expected item {"job":"a"} at $.data missing from actual response`,
+		},
+		{
+			name:     "different types",
+			expected: `{"data":[]}`,
+			actual:   `{"data":{}}`,
+			err:      "expected array at $.data but got {}",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := NewCardinalityComparator().Compare([]byte(tc.expected), []byte(tc.actual))
+			if tc.err == "" {
+				require.NoError(t, err)
+				require.Equal(t, ComparisonSuccess, result)
+				return
+			}
+			require.EqualError(t, err, tc.err)
+			require.Equal(t, ComparisonFailed, result)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querytee
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/model"
+)
+
+type responseStatus struct {
+	Status    string `json:"status"`
+	ErrorType string `json:"errorType"`
+	Error     string `json:"error"`
+}
+
+func compareResponseStatus(expected, actual responseStatus) error {
+	if expected.Status != actual.Status {
+		return fmt.Errorf("expected status %s but got %s", expected.Status, actual.Status)
+	}
+
+	if expected.ErrorType != actual.ErrorType {
+		return fmt.Errorf("expected error type '%s' but got '%s'", expected.ErrorType, actual.ErrorType)
+	}
+
+	if expected.Error != actual.Error {
+		return fmt.Errorf("expected error '%s' but got '%s'", expected.Error, actual.Error)
+	}
+
+	return nil
+}
+
+type labelsResponse struct {
+	responseStatus
+	Data []string `json:"data"`
+}
+
+// LabelsComparator compares responses of the /api/v1/labels and /api/v1/label/<name>/values routes.
+// The returned names or values are compared as sets, regardless of their order. Responses with duplicated
+// names or values are reported as failed comparisons, since the APIs return each name or value only once.
+type LabelsComparator struct{}
+
+func NewLabelsComparator() *LabelsComparator {
+	return &LabelsComparator{}
+}
+
+func (c *LabelsComparator) Compare(expectedResponse, actualResponse []byte) (ComparisonResult, error) {
+	var expected, actual labelsResponse
+
+	if err := json.Unmarshal(expectedResponse, &expected); err != nil {
+		return ComparisonFailed, errors.Wrap(err, "unable to unmarshal expected response")
+	}
+
+	if err := json.Unmarshal(actualResponse, &actual); err != nil {
+		return ComparisonFailed, errors.Wrap(err, "unable to unmarshal actual response")
+	}
+
+	if err := compareResponseStatus(expected.responseStatus, actual.responseStatus); err != nil {
+		return ComparisonFailed, err
+	}
+
+	if len(expected.Data) != len(actual.Data) {
+		return ComparisonFailed, fmt.Errorf("expected %d values but got %d", len(expected.Data), len(actual.Data))
+	}
+
+	if _, err := uniqueLabelValues(expected.Data); err != nil {
+		return ComparisonFailed, errors.Wrap(err, "expected response")
+	}
+
+	actualValues, err := uniqueLabelValues(actual.Data)
+	if err != nil {
+		return ComparisonFailed, errors.Wrap(err, "actual response")
+	}
+
+	for _, value := range expected.Data {
+		if _, ok := actualValues[value]; !ok {
+			return ComparisonFailed, fmt.Errorf("expected value '%s' missing from actual response", value)
+		}
+	}
+
+	return ComparisonSuccess, nil
+}
+
+// uniqueLabelValues returns the set of values, or an error if a value is duplicated.
+func uniqueLabelValues(values []string) (map[string]struct{}, error) {
+	unique := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		if _, ok := unique[value]; ok {
+			return nil, fmt.Errorf("duplicated value '%s'", value)
+		}
+		unique[value] = struct{}{}
+	}
+	return unique, nil
+}
+
+type seriesResponse struct {
+	responseStatus
+	Data []model.Metric `json:"data"`
+}
+
+// SeriesComparator compares responses of the /api/v1/series route.
+// The returned series are compared as sets, regardless of their order. Responses with duplicated series
+// are reported as failed comparisons, since the API returns each series only once.
+type SeriesComparator struct{}
+
+func NewSeriesComparator() *SeriesComparator {
+	return &SeriesComparator{}
+}
+
+func (c *SeriesComparator) Compare(expectedResponse, actualResponse []byte) (ComparisonResult, error) {
+	var expected, actual seriesResponse
+
+	if err := json.Unmarshal(expectedResponse, &expected); err != nil {
+		return ComparisonFailed, errors.Wrap(err, "unable to unmarshal expected response")
+	}
+
+	if err := json.Unmarshal(actualResponse, &actual); err != nil {
+		return ComparisonFailed, errors.Wrap(err, "unable to unmarshal actual response")
+	}
+
+	if err := compareResponseStatus(expected.responseStatus, actual.responseStatus); err != nil {
+		return ComparisonFailed, err
+	}
+
+	if len(expected.Data) != len(actual.Data) {
+		return ComparisonFailed, fmt.Errorf("expected %d series but got %d", len(expected.Data), len(actual.Data))
+	}
+
+	if _, err := uniqueSeries(expected.Data); err != nil {
+		return ComparisonFailed, errors.Wrap(err, "expected response")
+	}
+
+	actualSeries, err := uniqueSeries(actual.Data)
+	if err != nil {
+		return ComparisonFailed, errors.Wrap(err, "actual response")
+	}
+
+	for _, series := range expected.Data {
+		if _, ok := actualSeries[series.String()]; !ok {
+			return ComparisonFailed, fmt.Errorf("expected series %s missing from actual response", series)
+		}
+	}
+
+	return ComparisonSuccess, nil
+}
+
+// uniqueSeries returns the set of series, keyed by their string representation, or an error if a series
+// is duplicated.
+func uniqueSeries(series []model.Metric) (map[string]struct{}, error) {
+	unique := make(map[string]struct{}, len(series))
+	for _, s := range series {
+		key := s.String()
+		if _, ok := unique[key]; ok {
+			return nil, fmt.Errorf("duplicated series %s", key)
+		}
+		unique[key] = struct{}{}
+	}
+	return unique, nil
+}
@@ -0,0 +1,143 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querytee
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabelsComparator(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		expected string
+		actual   string
+		err      string
+	}{
+		{
+			name:     "same values in the same order",
+			expected: `{"status":"success","data":["bar","foo"]}`,
+			actual:   `{"status":"success","data":["bar","foo"]}`,
+		},
+		{
+			name:     "same values in a different order",
+			expected: `{"status":"success","data":["bar","foo"]}`,
+			actual:   `{"status":"success","data":["foo","bar"]}`,
+		},
+		{
+			name:     "no values",
+			expected: `{"status":"success","data":[]}`,
+			actual:   `{"status":"success","data":[]}`,
+		},
+		{
+			name:     "different number of values",
+			expected: `{"status":"success","data":["bar","foo"]}`,
+			actual:   `{"status":"success","data":["bar"]}`,
+			err:      "expected 2 values but got 1",
+		},
+		{
+			name:     "different values",
+			expected: `{"status":"success","data":["bar","foo"]}`,
+			actual:   `{"status":"success","data":["bar","baz"]}`,
+			err:      "expected value 'foo' missing from actual response",
+		},
+		{
+			name:     "duplicated values in the expected response",
+			expected: `{"status":"success","data":["bar","bar"]}`,
+			actual:   `{"status":"success","data":["bar","foo"]}`,
+			err:      "expected response: duplicated value 'bar'",
+		},
+		{
+			name:     "duplicated values in the actual response",
+			expected: `{"status":"success","data":["bar","foo"]}`,
+			actual:   `{"status":"success","data":["foo","foo"]}`,
+			err:      "actual response: duplicated value 'foo'",
+		},
+		{
+			name:     "different status",
+			expected: `{"status":"success","data":["bar"]}`,
+			actual:   `{"status":"error","errorType":"internal","error":"something went wrong"}`,
+			err:      "expected status success but got error",
+		},
+		{
+			name:     "different errors",
+			expected: `{"status":"error","errorType":"bad_data","error":"invalid parameter"}`,
+			actual:   `{"status":"error","errorType":"bad_data","error":"invalid matcher"}`,
+			err:      "expected error 'invalid parameter' but got 'invalid matcher'",
+		},
+		{
+			name:     "invalid actual response",
+			expected: `{"status":"success","data":["bar"]}`,
+			actual:   `not json`,
+			err:      "unable to unmarshal actual response: invalid character 'o' in literal null (expecting 'u')",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := NewLabelsComparator().Compare([]byte(tc.expected), []byte(tc.actual))
+			if tc.err == "" {
+				require.NoError(t, err)
+				require.Equal(t, ComparisonSuccess, result)
+				return
+			}
+			require.EqualError(t, err, tc.err)
+			require.Equal(t, ComparisonFailed, result)
+		})
+	}
+}
+
+func TestSeriesComparator(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		expected string
+		actual   string
+		err      string
+	}{
+		{
+			name:     "same series in a different order",
+			expected: `{"status":"success","data":[{"__name__":"up","job":"a"},{"__name__":"up","job":"b"}]}`,
+			actual:   `{"status":"success","data":[{"__name__":"up","job":"b"},{"__name__":"up","job":"a"}]}`,
+		},
+		{
+			name:     "different number of series",
+			expected: `{"status":"success","data":[{"__name__":"up","job":"a"},{"__name__":"up","job":"b"}]}`,
+			actual:   `{"status":"success","data":[{"__name__":"up","job":"a"}]}`,
+			err:      "expected 2 series but got 1",
+		},
+		{
+			name:     "different series",
+			expected: `{"status":"success","data":[{"__name__":"up","job":"a"}]}`,
+			actual:   `{"status":"success","data":[{"__name__":"up","job":"b"}]}`,
+			err:      `expected series up{job="a"} missing from actual response`,
+		},
+		{
+			name:     "duplicated series in the expected response",
+			expected: `{"status":"success","data":[{"__name__":"up","job":"a"},{"job":"a","__name__":"up"}]}`,
+			actual:   `{"status":"success","data":[{"__name__":"up","job":"a"},{"__name__":"up","job":"b"}]}`,
+			err:      `expected response: duplicated series up{job="a"}`,
+		},
+		{
+			name:     "duplicated series in the actual response",
+			expected: `{"status":"success","data":[{"__name__":"up","job":"a"},{"__name__":"up","job":"b"}]}`,
+			actual:   `{"status":"success","data":[{"__name__":"up","job":"a"},{"__name__":"up","job":"a"}]}`,
+			err:      `actual response: duplicated series up{job="a"}`,
+		},
+		{
+			name:     "different status",
+			expected: `{"status":"success","data":[]}`,
+			actual:   `{"status":"error","errorType":"internal","error":"something went wrong"}`,
+			err:      "expected status success but got error",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := NewSeriesComparator().Compare([]byte(tc.expected), []byte(tc.actual))
+			if tc.err == "" {
+				require.NoError(t, err)
+				require.Equal(t, ComparisonSuccess, result)
+				return
+			}
+			require.EqualError(t, err, tc.err)
+			require.Equal(t, ComparisonFailed, result)
+		})
+	}
+}
@@ -0,0 +1,255 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querytee
+
+import (
+	"crypto/rand"
+	_ "embed" // Used to embed html template
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/oklog/ulid"
+	"github.com/pkg/errors"
+
+	"github.com/grafana/mimir/pkg/util"
+)
+
+const mismatchReportFileExtension = ".json"
+
+//go:embed mismatch_reports.gohtml
+var mismatchReportsPageHTML string
+var mismatchReportsPageTemplate = template.Must(template.New("mismatch-reports").Parse(mismatchReportsPageHTML))
+
+// MismatchReport holds the details of a request whose responses didn't match between the preferred
+// and the secondary backend.
+type MismatchReport struct {
+	ID        string           `json:"id"`
+	Timestamp time.Time        `json:"timestamp"`
+	RouteName string           `json:"route"`
+	Method    string           `json:"method"`
+	Path      string           `json:"path"`
+	Query     string           `json:"query"`
+	User      string           `json:"user,omitempty"`
+	Start     string           `json:"start,omitempty"`
+	End       string           `json:"end,omitempty"`
+	Result    ComparisonResult `json:"result"`
+	Diff      string           `json:"diff"`
+
+	Expected MismatchReportResponse `json:"expected"`
+	Actual   MismatchReportResponse `json:"actual"`
+}
+
+// MismatchReportResponse holds the response received from a backend.
+type MismatchReportResponse struct {
+	Backend     string `json:"backend"`
+	Status      int    `json:"status"`
+	ContentType string `json:"contentType,omitempty"`
+	Body        string `json:"body,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+func newMismatchReportResponse(res *backendResponse) MismatchReportResponse {
+	r := MismatchReportResponse{
+		Backend:     res.backend.name,
+		Status:      res.status,
+		ContentType: res.contentType,
+		Body:        string(res.body),
+	}
+	if res.err != nil {
+		r.Error = res.err.Error()
+	}
+	return r
+}
+
+// MismatchReportStore persists mismatch reports to a local directory, one JSON file per report.
+// Only the most recent reports are kept, up to the configured max number of reports.
+type MismatchReportStore struct {
+	dir        string
+	maxReports int
+
+	// Protects the directory content.
+	mtx sync.Mutex
+}
+
+func NewMismatchReportStore(dir string, maxReports int) (*MismatchReportStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, errors.Wrap(err, "create mismatch reports directory")
+	}
+
+	return &MismatchReportStore{
+		dir:        dir,
+		maxReports: maxReports,
+	}, nil
+}
+
+// Add stores a new report, assigning it an ID and deleting the oldest reports in excess.
+func (s *MismatchReportStore) Add(report MismatchReport) error {
+	if report.Timestamp.IsZero() {
+		report.Timestamp = time.Now()
+	}
+
+	// ULIDs are lexicographically sortable by time, so the reports can be listed in order by file name.
+	report.ID = ulid.MustNew(ulid.Timestamp(report.Timestamp), rand.Reader).String()
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		return errors.Wrap(err, "marshal mismatch report")
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	// Write to a temporary file first, so that a partially written report is never listed.
+	reportPath := s.reportPath(report.ID)
+	if err := os.WriteFile(reportPath+".tmp", data, 0o644); err != nil {
+		return errors.Wrap(err, "write mismatch report")
+	}
+	if err := os.Rename(reportPath+".tmp", reportPath); err != nil {
+		return errors.Wrap(err, "write mismatch report")
+	}
+
+	return s.enforceMaxReports()
+}
+
+func (s *MismatchReportStore) enforceMaxReports() error {
+	if s.maxReports <= 0 {
+		return nil
+	}
+
+	ids, err := s.listIDs()
+	if err != nil {
+		return err
+	}
+
+	for len(ids) > s.maxReports {
+		if err := os.Remove(s.reportPath(ids[0])); err != nil && !os.IsNotExist(err) {
+			return errors.Wrap(err, "delete mismatch report")
+		}
+		ids = ids[1:]
+	}
+
+	return nil
+}
+
+// List returns all the stored reports, most recent first.
+func (s *MismatchReportStore) List() ([]MismatchReport, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	ids, err := s.listIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]MismatchReport, 0, len(ids))
+	for i := len(ids) - 1; i >= 0; i-- {
+		report, err := s.read(ids[i])
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+// Get returns the report with the input ID, or os.ErrNotExist if it doesn't exist.
+func (s *MismatchReportStore) Get(id string) (MismatchReport, error) {
+	if _, err := ulid.ParseStrict(id); err != nil {
+		return MismatchReport{}, os.ErrNotExist
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	return s.read(id)
+}
+
+func (s *MismatchReportStore) read(id string) (MismatchReport, error) {
+	var report MismatchReport
+
+	data, err := os.ReadFile(s.reportPath(id))
+	if err != nil {
+		return report, err
+	}
+
+	if err := json.Unmarshal(data, &report); err != nil {
+		return report, errors.Wrapf(err, "unmarshal mismatch report %s", id)
+	}
+
+	return report, nil
+}
+
+// listIDs returns the IDs of the stored reports, sorted from the oldest to the most recent.
+func (s *MismatchReportStore) listIDs() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "list mismatch reports")
+	}
+
+	ids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, mismatchReportFileExtension) {
+			continue
+		}
+
+		id := strings.TrimSuffix(name, mismatchReportFileExtension)
+		if _, err := ulid.ParseStrict(id); err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func (s *MismatchReportStore) reportPath(id string) string {
+	return filepath.Join(s.dir, id+mismatchReportFileExtension)
+}
+
+type mismatchReportsPageContents struct {
+	Reports []MismatchReport `json:"reports"`
+	Now     time.Time        `json:"now"`
+}
+
+// ListHandler renders the list of stored reports.
+func (s *MismatchReportStore) ListHandler(w http.ResponseWriter, req *http.Request) {
+	reports, err := s.List()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	util.RenderHTTPResponse(w, mismatchReportsPageContents{
+		Reports: reports,
+		Now:     time.Now(),
+	}, mismatchReportsPageTemplate, req)
+}
+
+// ReportHandler returns a single report, including both backend responses, as JSON.
+func (s *MismatchReportStore) ReportHandler(w http.ResponseWriter, req *http.Request) {
+	id := mux.Vars(req)["id"]
+
+	report, err := s.Get(id)
+	if os.IsNotExist(err) {
+		http.Error(w, fmt.Sprintf("mismatch report %s not found", id), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSONResponse(w, report)
+}
@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querytee
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMismatchReportStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "reports")
+	store, err := NewMismatchReportStore(dir, 2)
+	require.NoError(t, err)
+
+	now := time.Now()
+	for i, query := range []string{"first", "second", "third"} {
+		require.NoError(t, store.Add(MismatchReport{
+			Timestamp: now.Add(time.Duration(i) * time.Second),
+			RouteName: "api_v1_query",
+			Query:     query,
+			Result:    ComparisonFailed,
+		}))
+	}
+
+	// Only the most recent reports should be kept, and listed from the most recent one.
+	reports, err := store.List()
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+	assert.Equal(t, "third", reports[0].Query)
+	assert.Equal(t, "second", reports[1].Query)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	report, err := store.Get(reports[1].ID)
+	require.NoError(t, err)
+	assert.Equal(t, reports[1], report)
+
+	_, err = store.Get("unknown")
+	assert.True(t, os.IsNotExist(err))
+
+	// Files which are not reports should be ignored.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "not-a-report.json"), []byte("{}"), 0o644))
+	reports, err = store.List()
+	require.NoError(t, err)
+	assert.Len(t, reports, 2)
+}
+
+func TestMismatchReportStore_Handlers(t *testing.T) {
+	store, err := NewMismatchReportStore(t.TempDir(), 0)
+	require.NoError(t, err)
+
+	require.NoError(t, store.Add(MismatchReport{
+		RouteName: "api_v1_query_range",
+		Query:     "query=up&start=1&end=2",
+		Start:     "1",
+		End:       "2",
+		Result:    ComparisonFailed,
+		Diff:      "expected 1 metrics but got 2",
+		Expected:  MismatchReportResponse{Backend: "preferred", Status: 200, Body: "expected body"},
+		Actual:    MismatchReportResponse{Backend: "secondary", Status: 200, Body: "actual body"},
+	}))
+
+	router := mux.NewRouter()
+	router.Path("/mismatches").HandlerFunc(store.ListHandler)
+	router.Path("/mismatches/{id}").HandlerFunc(store.ReportHandler)
+
+	t.Run("list as HTML", func(t *testing.T) {
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, httptest.NewRequest("GET", "/mismatches", nil))
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), "expected 1 metrics but got 2")
+		assert.Contains(t, resp.Body.String(), "preferred vs secondary")
+	})
+
+	t.Run("list as JSON", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/mismatches", nil)
+		req.Header.Set("Accept", "application/json")
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var contents mismatchReportsPageContents
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &contents))
+		require.Len(t, contents.Reports, 1)
+		assert.Equal(t, "api_v1_query_range", contents.Reports[0].RouteName)
+	})
+
+	t.Run("get a single report", func(t *testing.T) {
+		reports, err := store.List()
+		require.NoError(t, err)
+
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, httptest.NewRequest("GET", "/mismatches/"+reports[0].ID, nil))
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var report MismatchReport
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &report))
+		assert.Equal(t, reports[0], report)
+	})
+
+	t.Run("get a non-existing report", func(t *testing.T) {
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, httptest.NewRequest("GET", "/mismatches/01HQ4C8R8PYD9AXJ3TTB1RWVXF", nil))
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+}
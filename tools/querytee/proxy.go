@@ -8,6 +8,7 @@ package querytee
 import (
 	"flag"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
@@ -18,6 +19,7 @@ import (
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
+	"github.com/gorilla/mux"
 	"github.com/grafana/dskit/server"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
@@ -39,6 +41,11 @@ type ProxyConfig struct {
 	PassThroughNonRegisteredRoutes bool
 	SkipRecentSamples              time.Duration
 	BackendSkipTLSVerify           bool
+	MismatchReportsDir             string
+	MismatchReportsMax             int
+	RequestLogFile                 string
+	RequestLogMaxSizeBytes         int64
+	ReplayRequestLogFile           string
 }
 
 func (cfg *ProxyConfig) RegisterFlags(f *flag.FlagSet) {
@@ -55,6 +62,11 @@ func (cfg *ProxyConfig) RegisterFlags(f *flag.FlagSet) {
 	f.BoolVar(&cfg.UseRelativeError, "proxy.compare-use-relative-error", false, "Use relative error tolerance when comparing floating point values.")
 	f.DurationVar(&cfg.SkipRecentSamples, "proxy.compare-skip-recent-samples", 2*time.Minute, "The window from now to skip comparing samples. 0 to disable.")
 	f.BoolVar(&cfg.PassThroughNonRegisteredRoutes, "proxy.passthrough-non-registered-routes", false, "Passthrough requests for non-registered routes to preferred backend.")
+	f.StringVar(&cfg.MismatchReportsDir, "proxy.mismatch-reports-dir", "", "Local directory where the details of the responses which didn't match between the preferred and secondary backends are stored. The stored mismatches are listed at /mismatches. Requires -proxy.compare-responses=true. Empty to disable.")
+	f.IntVar(&cfg.MismatchReportsMax, "proxy.mismatch-reports-max", 1000, "Maximum number of mismatch reports to keep in -proxy.mismatch-reports-dir. The oldest reports are deleted first. 0 to disable the limit.")
+	f.StringVar(&cfg.RequestLogFile, "proxy.request-log-file", "", "Local file where the requests received for the registered routes are appended, so that they can be replayed later via -proxy.replay-request-log-file. The file holds the tenant of the requests, but not their credentials: replayed requests are authenticated with the credentials configured in the backend endpoints. Empty to disable.")
+	f.Int64Var(&cfg.RequestLogMaxSizeBytes, "proxy.request-log-max-size-bytes", 1<<30, "Maximum size, in bytes, of -proxy.request-log-file. Once the file reaches this size, the following requests are not captured. 0 to disable the limit.")
+	f.StringVar(&cfg.ReplayRequestLogFile, "proxy.replay-request-log-file", "", "If set, the query-tee doesn't listen for requests. Instead, it replays the requests captured in this request log file against the backends, compares the responses and exits. Requires -proxy.compare-responses=true.")
 }
 
 type Route struct {
@@ -72,6 +84,10 @@ type Proxy struct {
 	metrics    *ProxyMetrics
 	routes     []Route
 
+	// Optional store of the responses which didn't match, and log of the received requests.
+	reports    *MismatchReportStore
+	requestLog *RequestLog
+
 	// The HTTP and gRPC servers used to run the proxy service.
 	server *server.Server
 
@@ -88,6 +104,14 @@ func NewProxy(cfg ProxyConfig, logger log.Logger, routes []Route, registerer pro
 		return nil, fmt.Errorf("when enabling passthrough for non-registered routes -backend.preferred flag must be set to hostname of backend where those requests needs to be passed")
 	}
 
+	if cfg.MismatchReportsDir != "" && !cfg.CompareResponses {
+		return nil, fmt.Errorf("when enabling mismatch reports -proxy.compare-responses flag must be set to true")
+	}
+
+	if cfg.ReplayRequestLogFile != "" && !cfg.CompareResponses {
+		return nil, fmt.Errorf("when replaying a request log -proxy.compare-responses flag must be set to true")
+	}
+
 	p := &Proxy{
 		cfg:        cfg,
 		logger:     logger,
@@ -154,6 +178,13 @@ func NewProxy(cfg ProxyConfig, logger log.Logger, routes []Route, registerer pro
 		level.Warn(p.logger).Log("msg", "The proxy is running with only 1 backend. At least 2 backends are required to fulfil the purpose of the proxy and compare results.")
 	}
 
+	if cfg.MismatchReportsDir != "" {
+		var err error
+		if p.reports, err = NewMismatchReportStore(cfg.MismatchReportsDir, cfg.MismatchReportsMax); err != nil {
+			return nil, err
+		}
+	}
+
 	return p, nil
 }
 
@@ -198,15 +229,20 @@ func (p *Proxy) Start() error {
 		w.WriteHeader(http.StatusOK)
 	}))
 
-	// register routes
-	for _, route := range p.routes {
-		var comparator ResponsesComparator
-		if p.cfg.CompareResponses {
-			comparator = route.ResponseComparator
+	// Mismatch reports endpoints.
+	if p.reports != nil {
+		router.Path("/mismatches").Methods("GET").HandlerFunc(p.reports.ListHandler)
+		router.Path("/mismatches/{id}").Methods("GET").HandlerFunc(p.reports.ReportHandler)
+	}
+
+	if p.cfg.RequestLogFile != "" {
+		if p.requestLog, err = NewRequestLog(p.cfg.RequestLogFile, p.cfg.RequestLogMaxSizeBytes); err != nil {
+			return err
 		}
-		router.Path(route.Path).Methods(route.Methods...).Handler(NewProxyEndpoint(p.backends, route.RouteName, p.metrics, p.logger, comparator))
 	}
 
+	p.registerRoutes(router, p.requestLog)
+
 	if p.cfg.PassThroughNonRegisteredRoutes {
 		for _, backend := range p.backends {
 			if backend.preferred {
@@ -232,15 +268,84 @@ func (p *Proxy) Start() error {
 	return nil
 }
 
+// registerRoutes registers an endpoint for each route on the input router.
+func (p *Proxy) registerRoutes(router *mux.Router, requestLog *RequestLog) {
+	for _, route := range p.routes {
+		var comparator ResponsesComparator
+		if p.cfg.CompareResponses {
+			comparator = route.ResponseComparator
+		}
+		router.Path(route.Path).Methods(route.Methods...).Handler(NewProxyEndpoint(p.backends, route.RouteName, p.metrics, p.logger, comparator, p.reports, requestLog))
+	}
+}
+
 func (p *Proxy) Stop() error {
 	if p.server == nil {
 		return nil
 	}
 
 	p.server.Shutdown()
+
+	if p.requestLog != nil {
+		return p.requestLog.Close()
+	}
 	return nil
 }
 
+// ReplaySummary holds the outcome of replaying a request log.
+type ReplaySummary struct {
+	// Number of requests read from the request log.
+	Requests int
+
+	// Number of requests which didn't match any registered route and have not been replayed.
+	Unmatched int
+
+	// Number of replayed requests by comparison result.
+	Results map[ComparisonResult]int
+}
+
+// Replay reads the requests captured in a request log and sends them to the backends, one at a time,
+// comparing the responses as if the requests were received by the proxy. Responses which don't match
+// are stored as mismatch reports, if enabled.
+func (p *Proxy) Replay(r io.Reader) (ReplaySummary, error) {
+	router := mux.NewRouter()
+	p.registerRoutes(router, nil)
+
+	summary := ReplaySummary{Results: map[ComparisonResult]int{}}
+
+	err := ReadRequestLog(r, func(loggedReq LoggedRequest) error {
+		summary.Requests++
+
+		req, err := loggedReq.toHTTPRequest()
+		if err != nil {
+			return errors.Wrapf(err, "build request for %s", loggedReq.Path)
+		}
+
+		var match mux.RouteMatch
+		if !router.Match(req, &match) {
+			level.Warn(p.logger).Log("msg", "Skipped replaying request not matching any registered route", "method", req.Method, "path", req.URL.Path)
+			summary.Unmatched++
+			return nil
+		}
+
+		endpoint, ok := match.Handler.(*ProxyEndpoint)
+		if !ok {
+			summary.Unmatched++
+			return nil
+		}
+
+		// The responses are not sent to any client, so the channel is only drained.
+		resCh := make(chan *backendResponse, len(p.backends))
+		if result := endpoint.executeBackendRequests(req, resCh); result != "" {
+			summary.Results[result]++
+		}
+
+		return nil
+	})
+
+	return summary, err
+}
+
 func (p *Proxy) Await() {
 	// Wait until terminated.
 	p.done.Wait()
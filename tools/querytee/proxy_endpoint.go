@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strconv"
 	"sync"
 	"time"
@@ -28,6 +29,12 @@ type ProxyEndpoint struct {
 	logger     log.Logger
 	comparator ResponsesComparator
 
+	// Optional store where the details of mismatching responses are persisted.
+	reports *MismatchReportStore
+
+	// Optional log where the received requests are captured, so that they can be replayed later.
+	requestLog *RequestLog
+
 	// Whether for this endpoint there's a preferred backend configured.
 	hasPreferredBackend bool
 
@@ -35,7 +42,7 @@ type ProxyEndpoint struct {
 	routeName string
 }
 
-func NewProxyEndpoint(backends []*ProxyBackend, routeName string, metrics *ProxyMetrics, logger log.Logger, comparator ResponsesComparator) *ProxyEndpoint {
+func NewProxyEndpoint(backends []*ProxyBackend, routeName string, metrics *ProxyMetrics, logger log.Logger, comparator ResponsesComparator, reports *MismatchReportStore, requestLog *RequestLog) *ProxyEndpoint {
 	hasPreferredBackend := false
 	for _, backend := range backends {
 		if backend.preferred {
@@ -50,6 +57,8 @@ func NewProxyEndpoint(backends []*ProxyBackend, routeName string, metrics *Proxy
 		metrics:             metrics,
 		logger:              logger,
 		comparator:          comparator,
+		reports:             reports,
+		requestLog:          requestLog,
 		hasPreferredBackend: hasPreferredBackend,
 	}
 }
@@ -75,7 +84,10 @@ func (p *ProxyEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	p.metrics.responsesTotal.WithLabelValues(downstreamRes.backend.name, r.Method, p.routeName).Inc()
 }
 
-func (p *ProxyEndpoint) executeBackendRequests(req *http.Request, resCh chan *backendResponse) {
+// executeBackendRequests sends the request to all backends and, if a comparator is configured, compares
+// the responses once all backends have responded. The result of the comparison is returned, or an empty
+// result if responses have not been compared.
+func (p *ProxyEndpoint) executeBackendRequests(req *http.Request, resCh chan *backendResponse) ComparisonResult {
 	var (
 		wg           = sync.WaitGroup{}
 		err          error
@@ -89,7 +101,7 @@ func (p *ProxyEndpoint) executeBackendRequests(req *http.Request, resCh chan *ba
 		body, err = io.ReadAll(req.Body)
 		if err != nil {
 			level.Warn(p.logger).Log("msg", "Unable to read request body", "err", err)
-			return
+			return ""
 		}
 		if err := req.Body.Close(); err != nil {
 			level.Warn(p.logger).Log("msg", "Unable to close request body", "err", err)
@@ -104,6 +116,19 @@ func (p *ProxyEndpoint) executeBackendRequests(req *http.Request, resCh chan *ba
 
 	level.Debug(p.logger).Log("msg", "Received request", "path", req.URL.Path, "query", query)
 
+	if p.requestLog != nil {
+		loggedReq := LoggedRequest{
+			Timestamp: time.Now(),
+			Method:    req.Method,
+			Path:      req.URL.Path,
+			Query:     query,
+			User:      req.Header.Get("X-Scope-OrgID"),
+		}
+		if err := p.requestLog.Append(loggedReq); err != nil {
+			level.Warn(p.logger).Log("msg", "Unable to append request to the request log", "err", err)
+		}
+	}
+
 	wg.Add(len(p.backends))
 	for _, b := range p.backends {
 		b := b
@@ -185,8 +210,18 @@ func (p *ProxyEndpoint) executeBackendRequests(req *http.Request, resCh chan *ba
 			)
 		}
 
+		if result == ComparisonFailed && p.reports != nil {
+			report := newMismatchReport(p.routeName, req, query, expectedResponse, actualResponse, result, err)
+			if err := p.reports.Add(report); err != nil {
+				level.Warn(p.logger).Log("msg", "Unable to store mismatch report", "route-name", p.routeName, "err", err)
+			}
+		}
+
 		p.metrics.responsesComparedTotal.WithLabelValues(p.routeName, string(result)).Inc()
+		return result
 	}
+
+	return ""
 }
 
 func (p *ProxyEndpoint) waitBackendResponseForDownstream(resCh chan *backendResponse) *backendResponse {
@@ -248,6 +283,35 @@ func (p *ProxyEndpoint) compareResponses(expectedResponse, actualResponse *backe
 	return p.comparator.Compare(expectedResponse.body, actualResponse.body)
 }
 
+func newMismatchReport(routeName string, req *http.Request, query string, expectedResponse, actualResponse *backendResponse, result ComparisonResult, comparisonErr error) MismatchReport {
+	report := MismatchReport{
+		Timestamp: time.Now(),
+		RouteName: routeName,
+		Method:    req.Method,
+		Path:      req.URL.Path,
+		Query:     query,
+		User:      req.Header.Get("X-Scope-OrgID"),
+		Result:    result,
+		Expected:  newMismatchReportResponse(expectedResponse),
+		Actual:    newMismatchReportResponse(actualResponse),
+	}
+
+	if comparisonErr != nil {
+		report.Diff = comparisonErr.Error()
+	}
+
+	// Instant queries are evaluated at a single point in time, while other requests have a time range.
+	if params, err := url.ParseQuery(query); err == nil {
+		if t := params.Get("time"); t != "" {
+			report.Start, report.End = t, t
+		} else {
+			report.Start, report.End = params.Get("start"), params.Get("end")
+		}
+	}
+
+	return report
+}
+
 type backendResponse struct {
 	backend     *ProxyBackend
 	status      int
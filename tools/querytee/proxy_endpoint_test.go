@@ -12,6 +12,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
 	"strings"
 	"sync"
 	"testing"
@@ -104,7 +105,7 @@ func Test_ProxyEndpoint_waitBackendResponseForDownstream(t *testing.T) {
 		testData := testData
 
 		t.Run(testName, func(t *testing.T) {
-			endpoint := NewProxyEndpoint(testData.backends, "test", NewProxyMetrics(nil), log.NewNopLogger(), nil)
+			endpoint := NewProxyEndpoint(testData.backends, "test", NewProxyMetrics(nil), log.NewNopLogger(), nil, nil, nil)
 
 			// Send the responses from a dedicated goroutine.
 			resCh := make(chan *backendResponse)
@@ -148,7 +149,7 @@ func Test_ProxyEndpoint_Requests(t *testing.T) {
 		NewProxyBackend("backend-1", backendURL1, time.Second, true, false),
 		NewProxyBackend("backend-2", backendURL2, time.Second, false, false),
 	}
-	endpoint := NewProxyEndpoint(backends, "test", NewProxyMetrics(nil), log.NewNopLogger(), nil)
+	endpoint := NewProxyEndpoint(backends, "test", NewProxyMetrics(nil), log.NewNopLogger(), nil, nil, nil)
 
 	for _, tc := range []struct {
 		name    string
@@ -322,7 +323,7 @@ func Test_ProxyEndpoint_Comparison(t *testing.T) {
 				comparisonError:  scenario.comparatorError,
 			}
 
-			endpoint := NewProxyEndpoint(backends, "test", NewProxyMetrics(reg), logger, comparator)
+			endpoint := NewProxyEndpoint(backends, "test", NewProxyMetrics(reg), logger, comparator, nil, nil)
 
 			resp := httptest.NewRecorder()
 			req, err := http.NewRequest("GET", "http://test/api/v1/test", nil)
@@ -516,3 +517,104 @@ func (m *mockLogger) Log(keyvals ...interface{}) error {
 
 	return nil
 }
+
+func Test_ProxyEndpoint_MismatchReportsAndRequestLog(t *testing.T) {
+	backend := func(body string) *url.URL {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(body))
+		}))
+		t.Cleanup(server.Close)
+
+		u, err := url.Parse(server.URL)
+		require.NoError(t, err)
+		return u
+	}
+
+	backends := []*ProxyBackend{
+		NewProxyBackend("preferred-backend", backend("preferred response"), time.Second, true, false),
+		NewProxyBackend("secondary-backend", backend("secondary response"), time.Second, false, false),
+	}
+
+	reports, err := NewMismatchReportStore(t.TempDir(), 0)
+	require.NoError(t, err)
+
+	requestLogPath := t.TempDir() + "/requests.log"
+	requestLog, err := NewRequestLog(requestLogPath, 0)
+	require.NoError(t, err)
+
+	reg := prometheus.NewPedanticRegistry()
+	comparator := &mockComparator{comparisonResult: ComparisonFailed, comparisonError: errors.New("the responses don't match")}
+	endpoint := NewProxyEndpoint(backends, "test", NewProxyMetrics(reg), log.NewNopLogger(), comparator, reports, requestLog)
+
+	req := httptest.NewRequest("GET", "http://test/api/v1/test?query=up&time=10", nil)
+	req.Header.Set("X-Scope-OrgID", "user-1")
+	req.SetBasicAuth("user-1", "pass")
+	endpoint.ServeHTTP(httptest.NewRecorder(), req)
+
+	waitForResponseComparisonMetric(t, reg, ComparisonFailed)
+	require.NoError(t, requestLog.Close())
+
+	// The mismatch should have been reported.
+	stored, err := reports.List()
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	assert.Equal(t, "test", stored[0].RouteName)
+	assert.Equal(t, "query=up&time=10", stored[0].Query)
+	assert.Equal(t, "10", stored[0].Start)
+	assert.Equal(t, "10", stored[0].End)
+	assert.Equal(t, "the responses don't match", stored[0].Diff)
+	assert.Equal(t, MismatchReportResponse{Backend: "preferred-backend", Status: 200, ContentType: "application/json", Body: "preferred response"}, stored[0].Expected)
+	assert.Equal(t, MismatchReportResponse{Backend: "secondary-backend", Status: 200, ContentType: "application/json", Body: "secondary response"}, stored[0].Actual)
+
+	// The request should have been captured in the request log.
+	logContent, err := os.ReadFile(requestLogPath)
+	require.NoError(t, err)
+
+	var logged []LoggedRequest
+	require.NoError(t, ReadRequestLog(bytes.NewReader(logContent), func(r LoggedRequest) error {
+		logged = append(logged, r)
+		return nil
+	}))
+	require.Len(t, logged, 1)
+	assert.Equal(t, "GET", logged[0].Method)
+	assert.Equal(t, "/api/v1/test", logged[0].Path)
+	assert.Equal(t, "query=up&time=10", logged[0].Query)
+	assert.Equal(t, "user-1", logged[0].User)
+
+	// The client credentials should not be captured.
+	assert.NotContains(t, string(logContent), strings.TrimPrefix(req.Header.Get("Authorization"), "Basic "))
+}
+
+func Test_ProxyEndpoint_MismatchReportsSkipComparisonsNotFailed(t *testing.T) {
+	backendURL := func() *url.URL {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte("response"))
+		}))
+		t.Cleanup(server.Close)
+
+		u, err := url.Parse(server.URL)
+		require.NoError(t, err)
+		return u
+	}
+
+	backends := []*ProxyBackend{
+		NewProxyBackend("preferred-backend", backendURL(), time.Second, true, false),
+		NewProxyBackend("secondary-backend", backendURL(), time.Second, false, false),
+	}
+
+	reports, err := NewMismatchReportStore(t.TempDir(), 0)
+	require.NoError(t, err)
+
+	reg := prometheus.NewPedanticRegistry()
+	comparator := &mockComparator{comparisonResult: ComparisonSkipped, comparisonError: errors.New("the comparison was skipped")}
+	endpoint := NewProxyEndpoint(backends, "test", NewProxyMetrics(reg), log.NewNopLogger(), comparator, reports, nil)
+
+	endpoint.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "http://test/api/v1/test?query=up&time=10", nil))
+	waitForResponseComparisonMetric(t, reg, ComparisonSkipped)
+
+	stored, err := reports.List()
+	require.NoError(t, err)
+	assert.Empty(t, stored)
+}
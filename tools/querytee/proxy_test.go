@@ -522,3 +522,82 @@ func getServerAddress(proto string, server *server.Server) string {
 
 	return ""
 }
+
+func Test_Proxy_Replay(t *testing.T) {
+	backend := func(body string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(body + " " + r.URL.RawQuery))
+		}))
+	}
+
+	preferredBackend := backend("preferred")
+	t.Cleanup(preferredBackend.Close)
+	secondaryBackend := backend("secondary")
+	t.Cleanup(secondaryBackend.Close)
+
+	reportsDir := t.TempDir()
+	cfg := ProxyConfig{
+		BackendEndpoints:   preferredBackend.URL + "," + secondaryBackend.URL,
+		PreferredBackend:   strconv.Itoa(0),
+		BackendReadTimeout: time.Second,
+		CompareResponses:   true,
+		MismatchReportsDir: reportsDir,
+	}
+
+	routes := []Route{
+		{Path: "/api/v1/query", RouteName: "api_v1_query", Methods: []string{"GET", "POST"}, ResponseComparator: &testComparator{}},
+		{Path: "/api/v1/query_range", RouteName: "api_v1_query_range", Methods: []string{"GET", "POST"}, ResponseComparator: &mockComparator{
+			comparisonResult: ComparisonFailed,
+			comparisonError:  fmt.Errorf("expected 1 metrics but got 2"),
+		}},
+	}
+
+	p, err := NewProxy(cfg, log.NewNopLogger(), routes, prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	requestLog := strings.Join([]string{
+		`{"method":"GET","path":"/api/v1/query","query":"query=up&time=10","user":"user-1"}`,
+		`{"method":"POST","path":"/api/v1/query_range","query":"end=20&query=up&start=10&step=1","user":"user-1"}`,
+		`{"method":"GET","path":"/api/v1/unknown","query":""}`,
+	}, "\n")
+
+	summary, err := p.Replay(strings.NewReader(requestLog))
+	require.NoError(t, err)
+	assert.Equal(t, ReplaySummary{
+		Requests:  3,
+		Unmatched: 1,
+		Results:   map[ComparisonResult]int{ComparisonSuccess: 1, ComparisonFailed: 1},
+	}, summary)
+
+	reports, err := p.reports.List()
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+
+	report := reports[0]
+	assert.Equal(t, "api_v1_query_range", report.RouteName)
+	assert.Equal(t, "POST", report.Method)
+	assert.Equal(t, "user-1", report.User)
+	assert.Equal(t, "10", report.Start)
+	assert.Equal(t, "20", report.End)
+	assert.Equal(t, ComparisonFailed, report.Result)
+	assert.Equal(t, "expected 1 metrics but got 2", report.Diff)
+	assert.Equal(t, "preferred ", report.Expected.Body)
+	assert.Equal(t, "secondary ", report.Actual.Body)
+}
+
+func Test_NewProxy_MismatchReportsRequireComparison(t *testing.T) {
+	cfg := ProxyConfig{
+		BackendEndpoints:   "http://backend-1,http://backend-2",
+		PreferredBackend:   "backend-1",
+		MismatchReportsDir: t.TempDir(),
+	}
+
+	_, err := NewProxy(cfg, log.NewNopLogger(), testRoutes, nil)
+	require.EqualError(t, err, "when enabling mismatch reports -proxy.compare-responses flag must be set to true")
+
+	cfg.MismatchReportsDir = ""
+	cfg.ReplayRequestLogFile = "requests.log"
+	_, err = NewProxy(cfg, log.NewNopLogger(), testRoutes, nil)
+	require.EqualError(t, err, "when replaying a request log -proxy.compare-responses flag must be set to true")
+}
@@ -0,0 +1,148 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querytee
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// LoggedRequest is a request received by the query-tee, as captured in the request log.
+type LoggedRequest struct {
+	Timestamp time.Time `json:"timestamp"`
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+
+	// Query holds the URL-encoded query parameters. For POST requests, the form parameters are included too.
+	Query string `json:"query"`
+
+	// User holds the tenant of the request. The client credentials are not captured: when replaying, the
+	// requests are authenticated with the credentials configured in the backend endpoints, if any.
+	User string `json:"user,omitempty"`
+}
+
+// toHTTPRequest builds the HTTP request to send to the backends when replaying a logged request.
+func (r LoggedRequest) toHTTPRequest() (*http.Request, error) {
+	u := &url.URL{Path: r.Path}
+
+	var (
+		req *http.Request
+		err error
+	)
+	if r.Method == http.MethodPost {
+		req, err = http.NewRequest(r.Method, u.String(), strings.NewReader(r.Query))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	} else {
+		u.RawQuery = r.Query
+		req, err = http.NewRequest(r.Method, u.String(), nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if r.User != "" {
+		req.Header.Set("X-Scope-OrgID", r.User)
+	}
+
+	return req, nil
+}
+
+var errRequestLogFull = errors.New("the request log reached the max size, the following requests are not captured")
+
+// RequestLog appends the requests received by the query-tee to a file, one JSON object per line.
+// The file is readable by the owner only, because it holds the tenants and the queries.
+type RequestLog struct {
+	maxBytes int64
+
+	mtx  sync.Mutex
+	file *os.File
+	size int64
+	full bool
+}
+
+// NewRequestLog opens the request log file, appending to it if it already exists. Once the file reaches
+// maxBytes, the following requests are not captured. 0 disables the limit.
+func NewRequestLog(path string, maxBytes int64) (*RequestLog, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, errors.Wrap(err, "open request log")
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, errors.Wrap(err, "stat request log")
+	}
+
+	return &RequestLog{
+		maxBytes: maxBytes,
+		file:     file,
+		size:     info.Size(),
+	}, nil
+}
+
+// Append captures the request in the log. Once the log is full, it returns errRequestLogFull for the first
+// request which is not captured, and no error for the following ones.
+func (l *RequestLog) Append(req LoggedRequest) error {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if l.maxBytes > 0 && l.size+int64(len(data)) > l.maxBytes {
+		if l.full {
+			return nil
+		}
+		l.full = true
+		return errRequestLogFull
+	}
+
+	n, err := l.file.Write(data)
+	l.size += int64(n)
+	return err
+}
+
+func (l *RequestLog) Close() error {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	return l.file.Close()
+}
+
+// ReadRequestLog reads a request log written by RequestLog and calls fn for each request, in order.
+func ReadRequestLog(r io.Reader, fn func(LoggedRequest) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+
+		var req LoggedRequest
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			return errors.Wrapf(err, "parse request log line %d", line)
+		}
+
+		if err := fn(req); err != nil {
+			return err
+		}
+	}
+
+	return errors.Wrap(scanner.Err(), "read request log")
+}
@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package querytee
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "requests.log")
+
+	requests := []LoggedRequest{
+		{Timestamp: time.Unix(1, 0).UTC(), Method: "GET", Path: "/api/v1/query", Query: "query=up&time=1", User: "user-1"},
+		{Timestamp: time.Unix(2, 0).UTC(), Method: "POST", Path: "/api/v1/query_range", Query: "end=2&query=up&start=1&step=1"},
+	}
+
+	requestLog, err := NewRequestLog(path, 0)
+	require.NoError(t, err)
+	for _, req := range requests {
+		require.NoError(t, requestLog.Append(req))
+	}
+	require.NoError(t, requestLog.Close())
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	assert.Equal(t, requests, readRequestLogFile(t, path))
+}
+
+func TestRequestLog_MaxSize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "requests.log")
+	req := LoggedRequest{Timestamp: time.Unix(1, 0).UTC(), Method: "GET", Path: "/api/v1/query", Query: "query=up&time=1"}
+
+	data, err := json.Marshal(req)
+	require.NoError(t, err)
+	entrySize := int64(len(data) + 1)
+
+	// The limit fits two requests.
+	requestLog, err := NewRequestLog(path, 2*entrySize+1)
+	require.NoError(t, err)
+	require.NoError(t, requestLog.Append(req))
+	require.NoError(t, requestLog.Close())
+
+	// The size of the existing file is accounted when reopening it.
+	requestLog, err = NewRequestLog(path, 2*entrySize+1)
+	require.NoError(t, err)
+	require.NoError(t, requestLog.Append(req))
+	require.ErrorIs(t, requestLog.Append(req), errRequestLogFull)
+	require.NoError(t, requestLog.Append(req))
+	require.NoError(t, requestLog.Close())
+
+	assert.Equal(t, []LoggedRequest{req, req}, readRequestLogFile(t, path))
+}
+
+func readRequestLogFile(t *testing.T, path string) []LoggedRequest {
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = file.Close() })
+
+	var actual []LoggedRequest
+	require.NoError(t, ReadRequestLog(file, func(req LoggedRequest) error {
+		actual = append(actual, req)
+		return nil
+	}))
+	return actual
+}
+
+func TestReadRequestLog_InvalidLine(t *testing.T) {
+	err := ReadRequestLog(strings.NewReader("{\"method\":\"GET\"}\n\nnot json\n"), func(LoggedRequest) error { return nil })
+	require.ErrorContains(t, err, "parse request log line 3")
+}
+
+func TestLoggedRequest_toHTTPRequest(t *testing.T) {
+	t.Run("GET", func(t *testing.T) {
+		req, err := LoggedRequest{Method: "GET", Path: "/api/v1/query", Query: "query=up&time=1", User: "user-1"}.toHTTPRequest()
+		require.NoError(t, err)
+
+		assert.Equal(t, "GET", req.Method)
+		assert.Equal(t, "/api/v1/query", req.URL.Path)
+		assert.Equal(t, "query=up&time=1", req.URL.RawQuery)
+		assert.Equal(t, "user-1", req.Header.Get("X-Scope-OrgID"))
+		assert.Empty(t, req.Header.Get("Authorization"))
+		assert.Nil(t, req.Body)
+	})
+
+	t.Run("POST", func(t *testing.T) {
+		req, err := LoggedRequest{Method: "POST", Path: "/api/v1/query", Query: "query=up&time=1"}.toHTTPRequest()
+		require.NoError(t, err)
+
+		assert.Equal(t, "POST", req.Method)
+		assert.Empty(t, req.URL.RawQuery)
+		assert.Equal(t, "application/x-www-form-urlencoded", req.Header.Get("Content-Type"))
+		assert.Empty(t, req.Header.Get("X-Scope-OrgID"))
+
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "query=up&time=1", string(body))
+	})
+}
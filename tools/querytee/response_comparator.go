@@ -153,6 +153,22 @@ func compareMatrix(expectedRaw, actualRaw json.RawMessage, opts SampleComparison
 				return errors.Wrapf(err, "sample pair not matching for metric %s", expectedMetric.Metric)
 			}
 		}
+
+		expectedHistogramsLen := len(expectedMetric.Histograms)
+		actualHistogramsLen := len(actualMetric.Histograms)
+
+		if expectedHistogramsLen != actualHistogramsLen {
+			return fmt.Errorf("expected %d histogram samples for metric %s but got %d", expectedHistogramsLen,
+				expectedMetric.Metric, actualHistogramsLen)
+		}
+
+		for i, expectedHistogramPair := range expectedMetric.Histograms {
+			actualHistogramPair := actualMetric.Histograms[i]
+			err := compareSampleHistogramPair(expectedHistogramPair, actualHistogramPair, opts)
+			if err != nil {
+				return errors.Wrapf(err, "histogram sample pair not matching for metric %s", expectedMetric.Metric)
+			}
+		}
 	}
 
 	return nil
@@ -188,6 +204,29 @@ func compareVector(expectedRaw, actualRaw json.RawMessage, opts SampleComparison
 		}
 
 		actualMetric := actual[actualMetricIndex]
+
+		if expectedMetric.Histogram != nil || actualMetric.Histogram != nil {
+			if expectedMetric.Histogram == nil {
+				return fmt.Errorf("expected float value for metric %s but got histogram", expectedMetric.Metric)
+			}
+			if actualMetric.Histogram == nil {
+				return fmt.Errorf("expected histogram value for metric %s but got float", expectedMetric.Metric)
+			}
+
+			err := compareSampleHistogramPair(model.SampleHistogramPair{
+				Timestamp: expectedMetric.Timestamp,
+				Histogram: expectedMetric.Histogram,
+			}, model.SampleHistogramPair{
+				Timestamp: actualMetric.Timestamp,
+				Histogram: actualMetric.Histogram,
+			}, opts)
+			if err != nil {
+				return errors.Wrapf(err, "histogram sample pair not matching for metric %s", expectedMetric.Metric)
+			}
+
+			continue
+		}
+
 		err := compareSamplePair(model.SamplePair{
 			Timestamp: expectedMetric.Timestamp,
 			Value:     expectedMetric.Value,
@@ -238,6 +277,43 @@ func compareSamplePair(expected, actual model.SamplePair, opts SampleComparisonO
 	return nil
 }
 
+// compareSampleHistogramPair compares two native histogram samples. The count, sum and each bucket
+// count are compared applying the configured tolerance, while bucket boundaries must match exactly.
+func compareSampleHistogramPair(expected, actual model.SampleHistogramPair, opts SampleComparisonOptions) error {
+	if expected.Timestamp != actual.Timestamp {
+		return fmt.Errorf("expected timestamp %v but got %v", expected.Timestamp, actual.Timestamp)
+	}
+	if opts.SkipRecentSamples > 0 && time.Since(expected.Timestamp.Time()) < opts.SkipRecentSamples {
+		return nil
+	}
+
+	expectedHistogram, actualHistogram := expected.Histogram, actual.Histogram
+
+	if !compareSampleValue(model.SampleValue(expectedHistogram.Count), model.SampleValue(actualHistogram.Count), opts) {
+		return fmt.Errorf("expected count %s for timestamp %v but got %s", expectedHistogram.Count, expected.Timestamp, actualHistogram.Count)
+	}
+	if !compareSampleValue(model.SampleValue(expectedHistogram.Sum), model.SampleValue(actualHistogram.Sum), opts) {
+		return fmt.Errorf("expected sum %s for timestamp %v but got %s", expectedHistogram.Sum, expected.Timestamp, actualHistogram.Sum)
+	}
+
+	if len(expectedHistogram.Buckets) != len(actualHistogram.Buckets) {
+		return fmt.Errorf("expected %d buckets for timestamp %v but got %d", len(expectedHistogram.Buckets), expected.Timestamp, len(actualHistogram.Buckets))
+	}
+
+	for i, expectedBucket := range expectedHistogram.Buckets {
+		actualBucket := actualHistogram.Buckets[i]
+
+		if expectedBucket.Boundaries != actualBucket.Boundaries || expectedBucket.Lower != actualBucket.Lower || expectedBucket.Upper != actualBucket.Upper {
+			return fmt.Errorf("expected bucket %s for timestamp %v but got %s", expectedBucket, expected.Timestamp, actualBucket)
+		}
+		if !compareSampleValue(model.SampleValue(expectedBucket.Count), model.SampleValue(actualBucket.Count), opts) {
+			return fmt.Errorf("expected bucket %s for timestamp %v but got %s", expectedBucket, expected.Timestamp, actualBucket)
+		}
+	}
+
+	return nil
+}
+
 func compareSampleValue(first, second model.SampleValue, opts SampleComparisonOptions) bool {
 	f := float64(first)
 	s := float64(second)
@@ -87,6 +87,55 @@ func TestCompareMatrix(t *testing.T) {
 						]`),
 			err: errors.New("sample pair not matching for metric {foo=\"bar\"}: expected value 2 for timestamp 2 but got 3"),
 		},
+		{
+			name: "difference in number of histogram samples",
+			expected: json.RawMessage(`[
+							{"metric":{"foo":"bar"},"histograms":[[1,{"count":"2","sum":"3","buckets":[[1,"0","2","2"]]}],[2,{"count":"2","sum":"3","buckets":[[1,"0","2","2"]]}]]}
+						]`),
+			actual: json.RawMessage(`[
+							{"metric":{"foo":"bar"},"histograms":[[1,{"count":"2","sum":"3","buckets":[[1,"0","2","2"]]}]]}
+						]`),
+			err: errors.New("expected 2 histogram samples for metric {foo=\"bar\"} but got 1"),
+		},
+		{
+			name: "difference in histogram sample count",
+			expected: json.RawMessage(`[
+							{"metric":{"foo":"bar"},"histograms":[[1,{"count":"2","sum":"3","buckets":[[1,"0","2","2"]]}]]}
+						]`),
+			actual: json.RawMessage(`[
+							{"metric":{"foo":"bar"},"histograms":[[1,{"count":"3","sum":"3","buckets":[[1,"0","2","2"]]}]]}
+						]`),
+			err: errors.New("histogram sample pair not matching for metric {foo=\"bar\"}: expected count 2 for timestamp 1 but got 3"),
+		},
+		{
+			name: "difference in histogram bucket boundaries",
+			expected: json.RawMessage(`[
+							{"metric":{"foo":"bar"},"histograms":[[1,{"count":"2","sum":"3","buckets":[[1,"0","2","2"]]}]]}
+						]`),
+			actual: json.RawMessage(`[
+							{"metric":{"foo":"bar"},"histograms":[[1,{"count":"2","sum":"3","buckets":[[1,"0","4","2"]]}]]}
+						]`),
+			err: errors.New("histogram sample pair not matching for metric {foo=\"bar\"}: expected bucket [0,2):2 for timestamp 1 but got [0,4):2"),
+		},
+		{
+			name: "difference in histogram bucket count",
+			expected: json.RawMessage(`[
+							{"metric":{"foo":"bar"},"histograms":[[1,{"count":"2","sum":"3","buckets":[[1,"0","2","2"]]}]]}
+						]`),
+			actual: json.RawMessage(`[
+							{"metric":{"foo":"bar"},"histograms":[[1,{"count":"2","sum":"3","buckets":[[1,"0","2","1"]]}]]}
+						]`),
+			err: errors.New("histogram sample pair not matching for metric {foo=\"bar\"}: expected bucket [0,2):2 for timestamp 1 but got [0,2):1"),
+		},
+		{
+			name: "correct histogram samples",
+			expected: json.RawMessage(`[
+							{"metric":{"foo":"bar"},"values":[[1,"1"]],"histograms":[[2,{"count":"2","sum":"3","buckets":[[1,"0","2","2"]]}]]}
+						]`),
+			actual: json.RawMessage(`[
+							{"metric":{"foo":"bar"},"values":[[1,"1"]],"histograms":[[2,{"count":"2","sum":"3","buckets":[[1,"0","2","2"]]}]]}
+						]`),
+		},
 		{
 			name: "correct samples",
 			expected: json.RawMessage(`[
@@ -170,6 +219,35 @@ func TestCompareVector(t *testing.T) {
 						]`),
 			err: errors.New("sample pair not matching for metric {foo=\"bar\"}: expected value 1 for timestamp 1 but got 2"),
 		},
+		{
+			name: "float sample in expected response but histogram in actual response",
+			expected: json.RawMessage(`[
+							{"metric":{"foo":"bar"},"value":[1,"1"]}
+						]`),
+			actual: json.RawMessage(`[
+							{"metric":{"foo":"bar"},"histogram":[1,{"count":"2","sum":"3","buckets":[[1,"0","2","2"]]}]}
+						]`),
+			err: errors.New("expected float value for metric {foo=\"bar\"} but got histogram"),
+		},
+		{
+			name: "difference in histogram sample sum",
+			expected: json.RawMessage(`[
+							{"metric":{"foo":"bar"},"histogram":[1,{"count":"2","sum":"3","buckets":[[1,"0","2","2"]]}]}
+						]`),
+			actual: json.RawMessage(`[
+							{"metric":{"foo":"bar"},"histogram":[1,{"count":"2","sum":"4","buckets":[[1,"0","2","2"]]}]}
+						]`),
+			err: errors.New("histogram sample pair not matching for metric {foo=\"bar\"}: expected sum 3 for timestamp 1 but got 4"),
+		},
+		{
+			name: "correct histogram samples",
+			expected: json.RawMessage(`[
+							{"metric":{"foo":"bar"},"histogram":[1,{"count":"2","sum":"3","buckets":[[1,"0","2","2"]]}]}
+						]`),
+			actual: json.RawMessage(`[
+							{"metric":{"foo":"bar"},"histogram":[1,{"count":"2","sum":"3","buckets":[[1,"0","2","2"]]}]}
+						]`),
+		},
 		{
 			name: "correct samples",
 			expected: json.RawMessage(`[
@@ -192,6 +270,36 @@ func TestCompareVector(t *testing.T) {
 	}
 }
 
+func TestCompareSampleHistogramPair_Tolerance(t *testing.T) {
+	histogram := func(count, sum, bucketCount float64) model.SampleHistogramPair {
+		return model.SampleHistogramPair{
+			Timestamp: 1,
+			Histogram: &model.SampleHistogram{
+				Count: model.FloatString(count),
+				Sum:   model.FloatString(sum),
+				Buckets: model.HistogramBuckets{
+					{Boundaries: 1, Lower: 0, Upper: 2, Count: model.FloatString(bucketCount)},
+				},
+			},
+		}
+	}
+
+	opts := SampleComparisonOptions{Tolerance: 0.1}
+
+	require.NoError(t, compareSampleHistogramPair(histogram(2, 3, 2), histogram(2.05, 3.05, 2.05), opts))
+	require.EqualError(t, compareSampleHistogramPair(histogram(2, 3, 2), histogram(2, 3.5, 2), opts), "expected sum 3 for timestamp 0.001 but got 3.5")
+	require.EqualError(t, compareSampleHistogramPair(histogram(2, 3, 2), histogram(2, 3, 2.5), opts), "expected bucket [0,2):2 for timestamp 0.001 but got [0,2):2.5")
+
+	// Recent samples should be skipped.
+	now := model.Now()
+	recent := func(sum float64) model.SampleHistogramPair {
+		h := histogram(2, sum, 2)
+		h.Timestamp = now
+		return h
+	}
+	require.NoError(t, compareSampleHistogramPair(recent(3), recent(4), SampleComparisonOptions{SkipRecentSamples: time.Minute}))
+}
+
 func TestCompareScalar(t *testing.T) {
 	for _, tc := range []struct {
 		name     string